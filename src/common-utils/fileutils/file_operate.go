@@ -478,6 +478,16 @@ func CreateFile(filePath string, mode os.FileMode, checkerParam ...FileChecker)
 	return nil
 }
 
+// OpenFile opens a file with the flag, the file handle needs to be closed by the caller
+// it could optionally add FileChecker. On default, a link Checker will be used
+func OpenFile(path string, flag int, mode os.FileMode, checkerParam ...FileChecker) (*os.File, error) {
+	file, _, err := checkFile(path, flag, mode, checkerParam...)
+	if err != nil {
+		return nil, fmt.Errorf("check file failed: %s", err.Error())
+	}
+	return file, nil
+}
+
 // RenameFile renames a file
 // it could optionally add FileChecker. On default, a link Checker will be used
 func RenameFile(oldPath, newPath string, checkerParam ...FileChecker) error {
@@ -355,6 +355,30 @@ func TestCreateFile(t *testing.T) {
 	})
 }
 
+func TestOpenFile(t *testing.T) {
+	convey.Convey("test Open File: ", t, func() {
+		tmpDir, filePath, err := createTestFile("test_file.txt")
+		convey.So(err, convey.ShouldBeNil)
+		defer os.RemoveAll(tmpDir)
+
+		convey.Convey("open file success", func() {
+			file, err := OpenFile(filePath, os.O_RDWR, Mode600)
+			convey.So(err, convey.ShouldBeNil)
+			defer CloseFile(file)
+			_, err = file.Write([]byte("test"))
+			convey.So(err, convey.ShouldBeNil)
+		})
+
+		convey.Convey("open file failed: file is soft link", func() {
+			linkPath := filepath.Join(tmpDir, "test_link")
+			err = os.Symlink(filePath, linkPath)
+			convey.So(err, convey.ShouldBeNil)
+			_, err = OpenFile(linkPath, os.O_RDWR, Mode600)
+			convey.So(err, convey.ShouldResemble, errors.New("check file failed: can't support symlinks"))
+		})
+	})
+}
+
 func TestRenameFile(t *testing.T) {
 	convey.Convey("test Rename File: ", t, func() {
 		tmpDir, filePath, err := createTestFile("test_file.txt")
@@ -4,6 +4,7 @@ go 1.17
 
 require (
 	github.com/gin-gonic/gin v1.9.1
+	github.com/smartystreets/goconvey v1.7.2
 	huawei.com/mindx/common/checker v0.0.0
 	huawei.com/mindx/common/hwlog v0.10.5
 	huawei.com/mindx/common/limiter v0.0.0
@@ -18,6 +18,7 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"strconv"
 	"time"
 
 	"huawei.com/mindx/common/fileutils"
@@ -36,6 +37,28 @@ const (
 	defaultReadTimeOut = 2 * time.Minute
 )
 
+// ErrRangeNotSupported the server does not serve the requested range, the whole content has to be fetched again
+var ErrRangeNotSupported = errors.New("server does not support the requested range")
+
+// StatusError the server responds an unexpected status, RetryAfter is set if the server asks to retry later
+type StatusError struct {
+	StatusCode int
+	RetryAfter time.Duration
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("https return error status code: %d", e.StatusCode)
+}
+
+// newStatusError only the delay seconds form of the Retry-After header is supported
+func newStatusError(resp *http.Response) *StatusError {
+	statusErr := &StatusError{StatusCode: resp.StatusCode}
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+		statusErr.RetryAfter = time.Duration(seconds) * time.Second
+	}
+	return statusErr
+}
+
 // HttpsRequest [struct] for Https Request parameters
 type HttpsRequest struct {
 	url         string
@@ -43,6 +66,8 @@ type HttpsRequest struct {
 	client      *http.Client
 	reqHeader   map[string]interface{}
 	readTimeout time.Duration
+	proxy       ProxyFunc
+	respHeader  http.Header
 }
 
 // GetHttpsReq [method] for get https request
@@ -109,6 +134,12 @@ func (hr *HttpsRequest) SetReadTimeout(timeout time.Duration) *HttpsRequest {
 	return hr
 }
 
+// SetProxy method set the proxy for https request, nil means connecting directly
+func (hr *HttpsRequest) SetProxy(proxy ProxyFunc) *HttpsRequest {
+	hr.proxy = proxy
+	return hr
+}
+
 func (hr *HttpsRequest) initClient() error {
 	hr.tlsCert.SvrFlag = false
 	tlsCfg, err := certutils.GetTlsCfgWithPath(hr.tlsCert)
@@ -119,6 +150,7 @@ func (hr *HttpsRequest) initClient() error {
 		Transport: &http.Transport{
 			TLSClientConfig:     tlsCfg,
 			TLSHandshakeTimeout: timeOut,
+			Proxy:               hr.proxy,
 		},
 		Timeout: hr.readTimeout,
 	}
@@ -257,3 +289,51 @@ func (hr *HttpsRequest) handleRespToFile(resp *http.Response, writer io.Writer,
 
 	return nil
 }
+
+// GetRangeRespToFile [method] for http get resp from offset to file, the writer should be positioned at offset.
+// ErrRangeNotSupported is returned without writing anything if the server ignores the range, and *StatusError is
+// returned without writing anything if the server responds an error status
+func (hr *HttpsRequest) GetRangeRespToFile(writer io.Writer, offset, limit int64) error {
+	if hr.client == nil {
+		if err := hr.initClient(); err != nil {
+			return fmt.Errorf("init https client failed: %v", err)
+		}
+	}
+	req, err := http.NewRequest(http.MethodGet, hr.url, nil)
+	if err != nil {
+		return err
+	}
+	for k, v := range hr.reqHeader {
+		req.Header.Set(k, fmt.Sprintf("%v", v))
+	}
+	expectedStatus := http.StatusOK
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+		expectedStatus = http.StatusPartialContent
+	}
+	resp, err := hr.client.Do(req)
+	if err != nil {
+		return utils.TrimInfoFromError(err)
+	}
+	defer hr.client.CloseIdleConnections()
+	if resp == nil {
+		return fmt.Errorf("http response is nil")
+	}
+	hr.respHeader = resp.Header
+	if resp.StatusCode != expectedStatus {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			hwlog.RunLog.Warnf("close response body failed: %v", closeErr)
+		}
+		if offset > 0 && (resp.StatusCode == http.StatusOK ||
+			resp.StatusCode == http.StatusRequestedRangeNotSatisfiable) {
+			return ErrRangeNotSupported
+		}
+		return newStatusError(resp)
+	}
+	return hr.handleRespToFile(resp, writer, limit)
+}
+
+// GetRespHeader [method] for the header of the last response of GetRangeRespToFile, nil if there is no response
+func (hr *HttpsRequest) GetRespHeader() http.Header {
+	return hr.respHeader
+}
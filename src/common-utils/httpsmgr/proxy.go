@@ -0,0 +1,143 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package httpsmgr for http proxy
+package httpsmgr
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	proxyScheme     = "http"
+	noProxyWildcard = "*"
+	maxNoProxyItems = 64
+	maxProxyUserLen = 64
+)
+
+// ProxyFunc returns the proxy url for a request, nil url means connecting directly, same as http.Transport.Proxy
+type ProxyFunc func(req *http.Request) (*url.URL, error)
+
+// ProxyCfg [struct] http CONNECT proxy for outbound connections
+type ProxyCfg struct {
+	// Url address of proxy, such as http://192.168.1.10:3128, empty means connecting directly
+	Url string
+	// UserName and Password for proxy basic auth, not used if UserName is empty
+	UserName string
+	Password []byte
+	// NoProxy hosts connected directly, an item is an ip, a cidr, a domain name which matches its sub domains too,
+	// or * which matches all hosts
+	NoProxy []string
+}
+
+// Check [method] check whether proxy config is valid
+func (pc *ProxyCfg) Check() error {
+	if pc.Url == "" {
+		return nil
+	}
+	proxyUrl, err := url.Parse(pc.Url)
+	if err != nil {
+		return errors.New("proxy url is invalid")
+	}
+	if proxyUrl.Scheme != proxyScheme || proxyUrl.Hostname() == "" || proxyUrl.Port() == "" {
+		return fmt.Errorf("proxy url should be in format of %s://host:port", proxyScheme)
+	}
+	if proxyUrl.User != nil || (proxyUrl.Path != "" && proxyUrl.Path != "/") || proxyUrl.RawQuery != "" {
+		return errors.New("proxy url should not contain user info, path or query")
+	}
+	if len(pc.UserName) > maxProxyUserLen || strings.ContainsAny(pc.UserName, ":@/") {
+		return errors.New("proxy user name is invalid")
+	}
+	if len(pc.NoProxy) > maxNoProxyItems {
+		return fmt.Errorf("the count of no proxy items should not be more than %d", maxNoProxyItems)
+	}
+	for _, item := range pc.NoProxy {
+		if !isValidNoProxyItem(item) {
+			return fmt.Errorf("no proxy item [%s] is invalid", item)
+		}
+	}
+	return nil
+}
+
+func isValidNoProxyItem(item string) bool {
+	if item == noProxyWildcard || net.ParseIP(item) != nil {
+		return true
+	}
+	if strings.Contains(item, "/") {
+		_, _, err := net.ParseCIDR(item)
+		return err == nil
+	}
+	return item != "" && !strings.ContainsAny(item, " :@")
+}
+
+// GetProxyFunc [method] get the proxy function for http transport and websocket dialer, nil if proxy is not set
+func (pc *ProxyCfg) GetProxyFunc() (ProxyFunc, error) {
+	if pc == nil || pc.Url == "" {
+		return nil, nil
+	}
+	if err := pc.Check(); err != nil {
+		return nil, err
+	}
+	proxyUrl, err := url.Parse(pc.Url)
+	if err != nil {
+		return nil, errors.New("proxy url is invalid")
+	}
+	proxyUrl.Path = ""
+	if pc.UserName != "" {
+		proxyUrl.User = url.UserPassword(pc.UserName, string(pc.Password))
+	}
+	noProxy := append([]string{}, pc.NoProxy...)
+	return func(req *http.Request) (*url.URL, error) {
+		if req == nil || req.URL == nil || !UseProxy(req.URL.Hostname(), noProxy) {
+			return nil, nil
+		}
+		return proxyUrl, nil
+	}, nil
+}
+
+// UseProxy [method] whether the host should be connected through proxy, loopback address is always connected directly
+func UseProxy(host string, noProxy []string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if host == "" || host == "localhost" {
+		return false
+	}
+	ip := net.ParseIP(host)
+	if ip != nil && ip.IsLoopback() {
+		return false
+	}
+	for _, item := range noProxy {
+		item = strings.ToLower(strings.TrimSpace(item))
+		if item == noProxyWildcard {
+			return false
+		}
+		if _, ipNet, err := net.ParseCIDR(item); err == nil {
+			if ip != nil && ipNet.Contains(ip) {
+				return false
+			}
+			continue
+		}
+		if itemIp := net.ParseIP(item); itemIp != nil {
+			if ip != nil && itemIp.Equal(ip) {
+				return false
+			}
+			continue
+		}
+		domain := strings.TrimPrefix(item, ".")
+		if ip == nil && (host == domain || strings.HasSuffix(host, "."+domain)) {
+			return false
+		}
+	}
+	return true
+}
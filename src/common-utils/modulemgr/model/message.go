@@ -12,6 +12,7 @@
 package model
 
 import (
+	"context"
 	"crypto/rand"
 	"encoding/hex"
 	"encoding/json"
@@ -20,6 +21,8 @@ import (
 	"reflect"
 	"strconv"
 	"time"
+
+	"huawei.com/mindx/common/hwlog"
 )
 
 // Not good, just for compatible with both mef msg and kubeede msg
@@ -35,6 +38,10 @@ type header struct {
 	ResourceVersion string      `json:"resourceversion"` // kubeedge format
 	NodeId          string      `json:"nodeId"`
 	PeerInfo        MsgPeerInfo `json:"peerInfo"`
+	// TraceId correlates the messages derived from one request, it is carried across modules and websocket
+	TraceId string `json:"traceId,omitempty"`
+	// NeedAck asks the peer to ack the async message once it is handed to the handling module
+	NeedAck bool `json:"needAck,omitempty"`
 }
 
 type router struct {
@@ -214,6 +221,34 @@ func (msg *Message) SetIsSync(isSync bool) {
 	msg.Header.IsSync = isSync
 }
 
+// GetTraceId get message trace id
+func (msg *Message) GetTraceId() string {
+	return msg.Header.TraceId
+}
+
+// SetTraceId set message trace id
+func (msg *Message) SetTraceId(traceId string) {
+	msg.Header.TraceId = traceId
+}
+
+// GetNeedAck get message needs ack from peer or not
+func (msg *Message) GetNeedAck() bool {
+	return msg.Header.NeedAck
+}
+
+// SetNeedAck set message needs ack from peer or not
+func (msg *Message) SetNeedAck(needAck bool) {
+	msg.Header.NeedAck = needAck
+}
+
+// TraceContext get the context for logging by hwlog *WithCtx, the trace id is printed as the request id
+func (msg *Message) TraceContext() context.Context {
+	if msg.Header.TraceId == "" {
+		return context.Background()
+	}
+	return context.WithValue(context.Background(), hwlog.ReqID, msg.Header.TraceId)
+}
+
 // GetTimestamp get message timestamp
 func (msg *Message) GetTimestamp() int64 {
 	return msg.Header.Timestamp
@@ -365,6 +400,7 @@ func (msg *Message) NewResponse() (*Message, error) {
 	}
 	respMsg.Header.ParentId = msg.Header.Id
 	respMsg.Header.IsSync = msg.Header.IsSync
+	respMsg.Header.TraceId = msg.Header.TraceId
 
 	respMsg.Router.Source = msg.Router.Destination
 	respMsg.Router.Destination = msg.Router.Source
@@ -375,6 +411,12 @@ func (msg *Message) NewResponse() (*Message, error) {
 	return respMsg, nil
 }
 
+// NewTraceId create a trace id for correlating the messages derived from one request
+func NewTraceId() (string, error) {
+	generator := messageIdGenerator{}
+	return generator.String()
+}
+
 // NewMessage create new inner message
 func NewMessage() (*Message, error) {
 	var msgId string
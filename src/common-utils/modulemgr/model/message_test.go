@@ -17,6 +17,8 @@ import (
 	"testing"
 
 	"github.com/smartystreets/goconvey/convey"
+
+	"huawei.com/mindx/common/hwlog"
 )
 
 const msgIdRegex = "[0-9a-f]{8}(-[0-9a-f]{4}){3}-[0-9a-f]{12}"
@@ -119,6 +121,28 @@ func TestCreateResponeMsg(t *testing.T) {
 	}
 }
 
+func TestMsgTraceId(t *testing.T) {
+	convey.Convey("trace id should be carried by response and marshaled message", t, func() {
+		traceId, err := NewTraceId()
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(regexp.MustCompile(msgIdRegex).MatchString(traceId), convey.ShouldBeTrue)
+		msg, err := NewMessage()
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(msg.TraceContext().Value(hwlog.ReqID), convey.ShouldBeNil)
+		msg.SetTraceId(traceId)
+		convey.So(msg.TraceContext().Value(hwlog.ReqID), convey.ShouldEqual, traceId)
+
+		resp, err := msg.NewResponse()
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(resp.GetTraceId(), convey.ShouldEqual, traceId)
+		data, err := json.Marshal(resp)
+		convey.So(err, convey.ShouldBeNil)
+		var received Message
+		convey.So(json.Unmarshal(data, &received), convey.ShouldBeNil)
+		convey.So(received.GetTraceId(), convey.ShouldEqual, traceId)
+	})
+}
+
 func TestMsgMarshal(t *testing.T) {
 	convey.Convey("test msg marshaling", t, func() {
 		convey.Convey("test fill string content", testMarshalStringContent)
@@ -22,6 +22,9 @@ import (
 
 const (
 	handleSyncMsgTimeout = 30 * time.Second
+	maxAckedMsgIds       = 1024
+	// AckContent is the content of the ack to a message which needs ack
+	AckContent = "OK"
 )
 
 // RegisterModuleInfo register module info
@@ -66,6 +69,10 @@ type MsgHandler struct {
 	handLock           sync.Mutex
 	handlersMap        map[string]string
 	handlersLimiterMap map[string]limiter.IndependentLimiter
+	// ids of the latest acked messages, the message redelivered since its ack is lost is acked without handling
+	ackLock     sync.Mutex
+	ackedIds    map[string]struct{}
+	ackedIdList []string
 }
 
 // Register - register a handler module and related limiter (if set) for a message
@@ -97,6 +104,11 @@ func (wh *MsgHandler) HandleMsg(msgBytes []byte, pi model.MsgPeerInfo) []byte {
 		return nil
 	}
 	msg.SetPeerInfo(pi)
+	if msg.GetNeedAck() && msg.GetParentId() == "" && wh.isAcked(msg.GetId()) {
+		hwlog.RunLog.Warnf("msg [option: %s resource: %s] is redelivered, ack it again", msg.GetOption(),
+			msg.GetResource())
+		return wh.ack(&msg)
+	}
 	if msg.GetParentId() == "" {
 		// key rule [opt + ":" + res] must be sync with MessageHandlerIntf GetMessageKey() implementation
 		msgOpt := msg.GetOption()
@@ -110,10 +122,15 @@ func (wh *MsgHandler) HandleMsg(msgBytes []byte, pi model.MsgPeerInfo) []byte {
 		moduleName, ok := wh.handlersMap[key]
 		if !ok {
 			hwlog.RunLog.Errorf("no register msg handler [MsgOpt = %v, MsgRes = %v]", msgOpt, msgRes)
-			return nil
+			// redelivering the message does not help, so it is acked as well
+			return wh.ack(&msg)
 		}
 		msg.SetRouter("websocket", moduleName, msgOpt, msgRes)
 	}
+	if msg.GetTraceId() != "" {
+		hwlog.RunLog.InfofWithCtx(msg.TraceContext(), "receive msg [option: %s resource: %s] from websocket",
+			msg.GetOption(), msg.GetResource())
+	}
 	// sync request message
 	if msg.GetIsSync() && msg.GetParentId() == "" {
 		ret, err := SendSyncMessage(&msg, handleSyncMsgTimeout)
@@ -132,7 +149,57 @@ func (wh *MsgHandler) HandleMsg(msgBytes []byte, pi model.MsgPeerInfo) []byte {
 	// async message and sync resp message
 	if err := SendMessage(&msg); err != nil {
 		hwlog.RunLog.Errorf("send module message failed, error: %v", err)
+		return nil
+	}
+	if msg.GetParentId() == "" {
+		return wh.ack(&msg)
 	}
-
 	return nil
 }
+
+func (wh *MsgHandler) isAcked(id string) bool {
+	wh.ackLock.Lock()
+	defer wh.ackLock.Unlock()
+	_, ok := wh.ackedIds[id]
+	return ok
+}
+
+func (wh *MsgHandler) recordAcked(id string) {
+	wh.ackLock.Lock()
+	defer wh.ackLock.Unlock()
+	if wh.ackedIds == nil {
+		wh.ackedIds = make(map[string]struct{})
+	}
+	if _, ok := wh.ackedIds[id]; ok {
+		return
+	}
+	if len(wh.ackedIdList) >= maxAckedMsgIds {
+		delete(wh.ackedIds, wh.ackedIdList[0])
+		wh.ackedIdList = wh.ackedIdList[1:]
+	}
+	wh.ackedIds[id] = struct{}{}
+	wh.ackedIdList = append(wh.ackedIdList, id)
+}
+
+// ack replies the ack to the message which needs ack, so that the peer stops redelivering it
+func (wh *MsgHandler) ack(msg *model.Message) []byte {
+	if !msg.GetNeedAck() {
+		return nil
+	}
+	wh.recordAcked(msg.GetId())
+	resp, err := msg.NewResponse()
+	if err != nil {
+		hwlog.RunLog.Errorf("new ack of message failed: %v", err)
+		return nil
+	}
+	if err = resp.FillContent(AckContent); err != nil {
+		hwlog.RunLog.Errorf("fill ack content failed: %v", err)
+		return nil
+	}
+	data, err := json.Marshal(resp)
+	if err != nil {
+		hwlog.RunLog.Errorf("marshal ack failed: %v", err)
+		return nil
+	}
+	return data
+}
@@ -0,0 +1,78 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package modulemgr test for message handler
+package modulemgr
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/smartystreets/goconvey/convey"
+
+	"huawei.com/mindx/common/hwlog"
+	"huawei.com/mindx/common/modulemgr/model"
+)
+
+func TestMain(m *testing.M) {
+	if err := hwlog.InitRunLogger(&hwlog.LogConfig{OnlyToStdout: true}, context.Background()); err != nil {
+		panic(err)
+	}
+	fmt.Printf("modulemgr dt exit %d\n", m.Run())
+}
+
+func newAckMsgForUT(needAck bool) []byte {
+	msg, err := model.NewMessage()
+	convey.So(err, convey.ShouldBeNil)
+	msg.SetRouter("cloudhub", "unknown", "POST", "/unregistered")
+	msg.SetNeedAck(needAck)
+	data, err := json.Marshal(msg)
+	convey.So(err, convey.ShouldBeNil)
+	return data
+}
+
+func TestHandleMsgAck(t *testing.T) {
+	convey.Convey("message needs ack should be acked even if it is not handled", t, func() {
+		handler := &MsgHandler{}
+		data := newAckMsgForUT(true)
+		var req model.Message
+		convey.So(json.Unmarshal(data, &req), convey.ShouldBeNil)
+
+		ack := handler.HandleMsg(data, model.MsgPeerInfo{})
+		convey.So(ack, convey.ShouldNotBeNil)
+		var resp model.Message
+		convey.So(json.Unmarshal(ack, &resp), convey.ShouldBeNil)
+		convey.So(resp.GetParentId(), convey.ShouldEqual, req.GetId())
+		var content string
+		convey.So(resp.ParseContent(&content), convey.ShouldBeNil)
+		convey.So(content, convey.ShouldEqual, AckContent)
+		convey.So(handler.isAcked(req.GetId()), convey.ShouldBeTrue)
+
+		// the redelivered message is acked again
+		convey.So(handler.HandleMsg(data, model.MsgPeerInfo{}), convey.ShouldNotBeNil)
+	})
+
+	convey.Convey("message without ack should not be acked", t, func() {
+		handler := &MsgHandler{}
+		convey.So(handler.HandleMsg(newAckMsgForUT(false), model.MsgPeerInfo{}), convey.ShouldBeNil)
+	})
+
+	convey.Convey("the oldest acked ids should be evicted", t, func() {
+		handler := &MsgHandler{}
+		for i := 0; i <= maxAckedMsgIds; i++ {
+			handler.recordAcked(fmt.Sprintf("id-%d", i))
+		}
+		convey.So(handler.isAcked("id-0"), convey.ShouldBeFalse)
+		convey.So(handler.isAcked(fmt.Sprintf("id-%d", maxAckedMsgIds)), convey.ShouldBeTrue)
+		convey.So(len(handler.ackedIds), convey.ShouldEqual, maxAckedMsgIds)
+	})
+}
@@ -0,0 +1,93 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package terminal provide pseudo terminal for interactive sessions
+package terminal
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"strconv"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	ptmxPath   = "/dev/ptmx"
+	ptsDirPath = "/dev/pts/"
+	decimal    = 10
+)
+
+// winSize struct is the window size of a terminal, more info search linux TIOCSWINSZ
+type winSize struct {
+	rows   uint16
+	cols   uint16
+	xPixel uint16
+	yPixel uint16
+}
+
+// OpenPty open a pseudo terminal pair, the master is read and written by the caller, the slave is used as the
+// stdin, stdout and stderr of the command. The master is kept in non-blocking mode so that closing it interrupts
+// the pending read
+func OpenPty() (*os.File, *os.File, error) {
+	master, err := os.OpenFile(ptmxPath, os.O_RDWR|syscall.O_NOCTTY|syscall.O_CLOEXEC, 0)
+	if err != nil {
+		return nil, nil, fmt.Errorf("open %s failed: %v", ptmxPath, err)
+	}
+	var unlock int32
+	if err = fileIoctl(master, syscall.TIOCSPTLCK, uintptr(unsafe.Pointer(&unlock))); err != nil {
+		return nil, nil, closeOnErr(master, fmt.Errorf("unlock pty failed: %v", err))
+	}
+	var ptyNum uint32
+	if err = fileIoctl(master, syscall.TIOCGPTN, uintptr(unsafe.Pointer(&ptyNum))); err != nil {
+		return nil, nil, closeOnErr(master, fmt.Errorf("get pty number failed: %v", err))
+	}
+	slavePath := ptsDirPath + strconv.FormatUint(uint64(ptyNum), decimal)
+	slave, err := os.OpenFile(slavePath, os.O_RDWR|syscall.O_NOCTTY|syscall.O_CLOEXEC, 0)
+	if err != nil {
+		return nil, nil, closeOnErr(master, fmt.Errorf("open %s failed: %v", slavePath, err))
+	}
+	return master, slave, nil
+}
+
+// SetPtySize set the window size of a pseudo terminal, the foreground process receives SIGWINCH
+func SetPtySize(pty *os.File, rows, cols uint16) error {
+	if pty == nil {
+		return errors.New("pty is nil")
+	}
+	size := winSize{rows: rows, cols: cols}
+	err := fileIoctl(pty, syscall.TIOCSWINSZ, uintptr(unsafe.Pointer(&size)))
+	runtime.KeepAlive(&size)
+	return err
+}
+
+// fileIoctl do ioctl by the raw connection, unlike File.Fd it does not switch the file into blocking mode
+func fileIoctl(file *os.File, req uint, arg uintptr) error {
+	rawConn, err := file.SyscallConn()
+	if err != nil {
+		return err
+	}
+	var ioctlErr error
+	if err = rawConn.Control(func(fd uintptr) {
+		ioctlErr = syscallIOctl(int(fd), req, arg)
+	}); err != nil {
+		return err
+	}
+	return ioctlErr
+}
+
+func closeOnErr(file *os.File, err error) error {
+	if closeErr := file.Close(); closeErr != nil {
+		return fmt.Errorf("%v, close %s failed: %v", err, file.Name(), closeErr)
+	}
+	return err
+}
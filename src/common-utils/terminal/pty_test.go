@@ -0,0 +1,59 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package terminal test for pseudo terminal
+package terminal
+
+import (
+	"os"
+	"syscall"
+	"testing"
+	"unsafe"
+
+	"github.com/smartystreets/goconvey/convey"
+)
+
+const (
+	testRows = 40
+	testCols = 120
+)
+
+func TestOpenPty(t *testing.T) {
+	if _, err := os.Stat(ptmxPath); err != nil {
+		t.Skipf("%s is not supported", ptmxPath)
+	}
+	convey.Convey("test open pty and write through it", t, func() {
+		master, slave, err := OpenPty()
+		convey.So(err, convey.ShouldBeNil)
+		defer master.Close()
+		defer slave.Close()
+
+		_, err = slave.Write([]byte("ok"))
+		convey.So(err, convey.ShouldBeNil)
+		buf := make([]byte, len("ok"))
+		n, err := master.Read(buf)
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(string(buf[:n]), convey.ShouldEqual, "ok")
+	})
+
+	convey.Convey("test set pty size", t, func() {
+		master, slave, err := OpenPty()
+		convey.So(err, convey.ShouldBeNil)
+		defer master.Close()
+		defer slave.Close()
+
+		convey.So(SetPtySize(master, testRows, testCols), convey.ShouldBeNil)
+		var size winSize
+		convey.So(fileIoctl(slave, syscall.TIOCGWINSZ, uintptr(unsafe.Pointer(&size))), convey.ShouldBeNil)
+		convey.So(size.rows, convey.ShouldEqual, testRows)
+		convey.So(size.cols, convey.ShouldEqual, testCols)
+		convey.So(SetPtySize(nil, testRows, testCols), convey.ShouldNotBeNil)
+	})
+}
@@ -0,0 +1,110 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package websocketmgr
+
+import (
+	"context"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"huawei.com/mindx/common/hwlog"
+)
+
+const (
+	dnsLookupTimeout = 5 * time.Second
+	maxJitterPercent = 20
+	maxServerHosts   = 16
+)
+
+// serverEndpoint a server address to connect, host is used in url and tls verification,
+// dialAddr is the resolved address to dial, empty means dialing the host directly
+type serverEndpoint struct {
+	host     string
+	dialAddr string
+}
+
+// getServerEndpoints expands the configured hosts in order, a domain name with multiple records is expanded into
+// one endpoint for each record so that all of them are tried, except the hosts connected through proxy which
+// resolves the host itself
+func (wcp *WsClientProxy) getServerEndpoints() []serverEndpoint {
+	hosts := wcp.ProxyCfg.serverHosts
+	if len(hosts) == 0 {
+		hosts = []string{wcp.ProxyCfg.hosts}
+	}
+	var endpoints []serverEndpoint
+	for _, hostPort := range hosts {
+		host, port, err := net.SplitHostPort(hostPort)
+		if err != nil || net.ParseIP(host) != nil || wcp.isProxied(hostPort) {
+			endpoints = append(endpoints, serverEndpoint{host: hostPort})
+			continue
+		}
+		ctx, cancel := context.WithTimeout(wcp.ProxyCfg.ctx, dnsLookupTimeout)
+		addrs, err := net.DefaultResolver.LookupHost(ctx, host)
+		cancel()
+		if err != nil || len(addrs) == 0 {
+			hwlog.RunLog.Warnf("resolve server host [%s] failed: %v", host, err)
+			endpoints = append(endpoints, serverEndpoint{host: hostPort})
+			continue
+		}
+		for _, addr := range addrs {
+			endpoints = append(endpoints, serverEndpoint{host: hostPort, dialAddr: net.JoinHostPort(addr, port)})
+		}
+	}
+	return endpoints
+}
+
+func (wcp *WsClientProxy) isProxied(hostPort string) bool {
+	if wcp.ProxyCfg.httpProxy == nil {
+		return false
+	}
+	proxyUrl, err := wcp.ProxyCfg.httpProxy(&http.Request{URL: &url.URL{Scheme: "https", Host: hostPort}})
+	return err == nil && proxyUrl != nil
+}
+
+// newCountingDialer dials the given address instead of the address in url if it is not empty, and counts the bytes
+// on the network beneath tls for traffic stats and bandwidth limiter. The proxy address is dialed if proxy is used
+func newCountingDialer(dialAddr string) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if dialAddr != "" {
+			addr = dialAddr
+		}
+		conn, err := (&net.Dialer{Timeout: defaultHandshakeTimeout}).DialContext(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+		return &countingConn{Conn: conn}, nil
+	}
+}
+
+// withJitter spreads the retry of edge nodes disconnected at the same time, so that the server is not flooded
+func withJitter(interval time.Duration) time.Duration {
+	jitter := int64(interval) * maxJitterPercent / percentBase
+	if jitter <= 0 {
+		return interval
+	}
+	return interval + time.Duration(rand.Int63n(2*jitter+1)-jitter)
+}
+
+// GetActiveHost get the server host of current connection, empty if never connected
+func (wcp *WsClientProxy) GetActiveHost() string {
+	wcp.activeHostLock.RLock()
+	defer wcp.activeHostLock.RUnlock()
+	return wcp.activeHost
+}
+
+func (wcp *WsClientProxy) setActiveHost(host string) {
+	wcp.activeHostLock.Lock()
+	defer wcp.activeHostLock.Unlock()
+	wcp.activeHost = host
+}
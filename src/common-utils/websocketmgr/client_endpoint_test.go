@@ -0,0 +1,127 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package websocketmgr
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/gorilla/websocket"
+	"github.com/smartystreets/goconvey/convey"
+)
+
+const testServerPort = 10000
+
+func newClientProxyForUT(hosts ...string) *WsClientProxy {
+	cfg := &ProxyConfig{}
+	if err := cfg.SetServerHosts(hosts, testServerPort); err != nil {
+		panic(err)
+	}
+	cfg.ctx, cfg.cancel = context.WithCancel(context.Background())
+	return &WsClientProxy{ProxyCfg: cfg}
+}
+
+func TestSetServerHosts(t *testing.T) {
+	convey.Convey("set server hosts", t, func() {
+		cfg := &ProxyConfig{}
+		convey.So(cfg.SetServerHosts(nil, testServerPort), convey.ShouldNotBeNil)
+		convey.So(cfg.SetServerHosts([]string{"10.0.0.1", ""}, testServerPort), convey.ShouldNotBeNil)
+		convey.So(cfg.SetServerHosts([]string{"10.0.0.1"}, maxPort+1), convey.ShouldNotBeNil)
+		convey.So(cfg.SetServerHosts([]string{"10.0.0.1", "center.example.com"}, testServerPort), convey.ShouldBeNil)
+		convey.So(cfg.serverHosts, convey.ShouldResemble, []string{"10.0.0.1:10000", "center.example.com:10000"})
+		convey.So(cfg.hosts, convey.ShouldEqual, "10.0.0.1:10000")
+	})
+}
+
+func TestGetServerEndpoints(t *testing.T) {
+	convey.Convey("ip hosts are kept in order", t, func() {
+		wcp := newClientProxyForUT("10.0.0.2", "10.0.0.1")
+		convey.So(wcp.getServerEndpoints(), convey.ShouldResemble,
+			[]serverEndpoint{{host: "10.0.0.2:10000"}, {host: "10.0.0.1:10000"}})
+	})
+
+	convey.Convey("domain name is expanded into its records", t, func() {
+		wcp := newClientProxyForUT("center.example.com", "10.0.0.1")
+		patches := gomonkey.ApplyMethodReturn(net.DefaultResolver, "LookupHost",
+			[]string{"10.0.1.1", "10.0.1.2"}, nil)
+		defer patches.Reset()
+		convey.So(wcp.getServerEndpoints(), convey.ShouldResemble, []serverEndpoint{
+			{host: "center.example.com:10000", dialAddr: "10.0.1.1:10000"},
+			{host: "center.example.com:10000", dialAddr: "10.0.1.2:10000"},
+			{host: "10.0.0.1:10000"},
+		})
+	})
+
+	convey.Convey("domain name connected through proxy is resolved by proxy", t, func() {
+		wcp := newClientProxyForUT("center.example.com", "10.0.0.1")
+		proxyUrl := &url.URL{Scheme: "http", Host: "10.0.2.1:3128"}
+		wcp.ProxyCfg.SetHttpProxy(func(req *http.Request) (*url.URL, error) {
+			if req.URL.Hostname() == "10.0.0.1" {
+				return nil, nil
+			}
+			return proxyUrl, nil
+		})
+		patches := gomonkey.ApplyMethodReturn(net.DefaultResolver, "LookupHost", []string{"10.0.1.1"}, nil)
+		defer patches.Reset()
+		convey.So(wcp.isProxied("center.example.com:10000"), convey.ShouldBeTrue)
+		convey.So(wcp.isProxied("10.0.0.1:10000"), convey.ShouldBeFalse)
+		convey.So(wcp.getServerEndpoints(), convey.ShouldResemble,
+			[]serverEndpoint{{host: "center.example.com:10000"}, {host: "10.0.0.1:10000"}})
+	})
+
+	convey.Convey("domain name is dialed directly when resolve failed", t, func() {
+		wcp := newClientProxyForUT("center.example.com")
+		patches := gomonkey.ApplyMethodReturn(net.DefaultResolver, "LookupHost", nil, errors.New("no such host"))
+		defer patches.Reset()
+		convey.So(wcp.getServerEndpoints(), convey.ShouldResemble,
+			[]serverEndpoint{{host: "center.example.com:10000"}})
+	})
+}
+
+func TestWithJitter(t *testing.T) {
+	convey.Convey("jitter is within range", t, func() {
+		const interval = 10 * time.Second
+		for i := 0; i < 100; i++ {
+			result := withJitter(interval)
+			convey.So(result, convey.ShouldBeBetweenOrEqual,
+				interval*(percentBase-maxJitterPercent)/percentBase, interval*(percentBase+maxJitterPercent)/percentBase)
+		}
+		convey.So(withJitter(0), convey.ShouldEqual, 0)
+	})
+}
+
+func TestTryConnectFailover(t *testing.T) {
+	convey.Convey("client connects to the next host when the previous one failed", t, func() {
+		wcp := newClientProxyForUT("10.0.0.1", "10.0.0.2")
+		var tried []string
+		patches := gomonkey.ApplyMethod(&websocket.Dialer{}, "Dial",
+			func(_ *websocket.Dialer, url string, _ http.Header) (*websocket.Conn, *http.Response, error) {
+				tried = append(tried, strings.TrimPrefix(url, wssProtocol))
+				if strings.HasPrefix(url, wssProtocol+"10.0.0.1") {
+					return nil, nil, errors.New("connection refused")
+				}
+				return &websocket.Conn{}, &http.Response{Header: http.Header{}}, nil
+			})
+		defer patches.Reset()
+
+		_, _, err := wcp.tryConnect(&websocket.Dialer{})
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(tried, convey.ShouldResemble, []string{"10.0.0.1:10000", "10.0.0.2:10000"})
+		convey.So(wcp.GetActiveHost(), convey.ShouldEqual, "10.0.0.2:10000")
+	})
+}
@@ -14,8 +14,12 @@ package websocketmgr
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net"
+	"net/http"
+	"sync"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -33,6 +37,8 @@ type WsClientProxy struct {
 	reconnectCallbacks  []func()
 	disconnectCallbacks []func(WebsocketPeerInfo)
 	bandwidthLimiter    limiter.ServerBandwidthLimiterIntf
+	activeHost          string
+	activeHostLock      sync.RWMutex
 }
 
 // waiting a moment for server is ready
@@ -57,17 +63,19 @@ func (wcp *WsClientProxy) Start() error {
 
 func (wcp *WsClientProxy) start() error {
 	dialer := &websocket.Dialer{
-		TLSClientConfig:  wcp.ProxyCfg.tlsConfig,
-		HandshakeTimeout: defaultHandshakeTimeout,
-		ReadBufferSize:   wsReadBufferSize,
-		WriteBufferSize:  wsWriteBufferSize,
+		TLSClientConfig:   wcp.ProxyCfg.tlsConfig,
+		HandshakeTimeout:  defaultHandshakeTimeout,
+		ReadBufferSize:    wsReadBufferSize,
+		WriteBufferSize:   wsWriteBufferSize,
+		EnableCompression: wcp.ProxyCfg.compressCfg != nil,
+		Proxy:             wcp.ProxyCfg.httpProxy,
 	}
 	hwlog.RunLog.Info("websocket client begin try to connect the server")
-	conn, err := wcp.tryConnect(dialer)
+	conn, respHeader, err := wcp.tryConnect(dialer)
 	if err != nil {
 		return fmt.Errorf("connect the server failed, error: %v", err)
 	}
-	host, _, err := net.SplitHostPort(wcp.ProxyCfg.hosts)
+	host, _, err := net.SplitHostPort(wcp.GetActiveHost())
 	if err != nil {
 		return fmt.Errorf("get host ip failed: %v", err)
 	}
@@ -81,10 +89,19 @@ func (wcp *WsClientProxy) start() error {
 		wcp.bandwidthLimiter = bandwidthLimiter
 	}
 
+	peerInfo := WebsocketPeerInfo{Ip: host, Sn: wcp.ProxyCfg.name}
+	peerInfo.ProtocolVersion, peerInfo.Capabilities, err = parseProtocolHeader(respHeader)
+	if err != nil {
+		// the server is still usable, it is regarded as legacy
+		hwlog.RunLog.Warnf("protocol info of server is invalid: %v", err)
+	}
+	hwlog.RunLog.Infof("the protocol version of server is %d", peerInfo.ProtocolVersion)
+
 	wcp.connMgr = &wsConnectMgr{
-		conn:         conn,
-		peerInfo:     WebsocketPeerInfo{Ip: host, Sn: wcp.ProxyCfg.name},
-		currentProxy: wcp,
+		conn:               conn,
+		peerInfo:           peerInfo,
+		currentProxy:       wcp,
+		compressNegotiated: isDeflateNegotiated(respHeader),
 	}
 	wcp.connMgr.start()
 	return nil
@@ -139,6 +156,12 @@ func (wcp *WsClientProxy) Send(msg *model.Message, msgType ...int) error {
 	if !isValidWsMsgType(wsMsgType) {
 		return fmt.Errorf("websocket message type [%v] is not supported", wsMsgType)
 	}
+	// only requests wait while backing off, responses are still sent so that the peer is not blocked
+	if msg != nil && msg.GetParentId() == "" {
+		if err := wcp.connMgr.waitThrottled(); err != nil {
+			return err
+		}
+	}
 	data, err := json.Marshal(msg)
 	if err != nil {
 		return fmt.Errorf("marshal message failed: %v", err)
@@ -149,33 +172,56 @@ func (wcp *WsClientProxy) Send(msg *model.Message, msgType ...int) error {
 	return nil
 }
 
+// SendStream sends the content of src to server in chunks through binary frames, it blocks until the server confirms
+// the whole content is received. The transfer resumes from the offset the server already has
+func (wcp *WsClientProxy) SendStream(meta StreamMeta, src io.ReadSeeker) error {
+	if err := wcp.connMgr.sendStream(meta, src); err != nil {
+		return utils.TrimInfoFromError(err)
+	}
+	return nil
+}
+
+// GetCompressionStats get the traffic statistics of the connection to server
+func (wcp *WsClientProxy) GetCompressionStats() (CompressionStats, error) {
+	if wcp.connMgr == nil {
+		return CompressionStats{}, errors.New("websocket client is not connected")
+	}
+	return wcp.connMgr.compressionStats(), nil
+}
+
 // IsConnected judge the client is connected
 func (wcp *WsClientProxy) IsConnected() bool {
 	return wcp.connMgr.isConnected()
 }
 
-func (wcp *WsClientProxy) tryConnect(dialer *websocket.Dialer) (*websocket.Conn, error) {
+// tryConnect tries the server endpoints in order, it backs off with jitter after all of them failed
+func (wcp *WsClientProxy) tryConnect(dialer *websocket.Dialer) (*websocket.Conn, http.Header, error) {
 	tryConnInterval := 1 * time.Second
 	errCnt := 0
 	time.Sleep(delayStartTime)
 	for {
-		select {
-		case <-wcp.ProxyCfg.ctx.Done():
-			return nil, fmt.Errorf("connect has be canceled")
-		default:
-		}
-		hwlog.RunLog.Info("websocket client try to connect server")
-		connect, _, err := dialer.Dial(wssProtocol+wcp.ProxyCfg.hosts, wcp.ProxyCfg.headers)
-		if err == nil {
-			hwlog.RunLog.Info("websocket client connect the server success")
-			return connect, nil
-		}
-		// print error msg each 3-times, not every time
-		if errCnt += 1; errCnt%errorPrintFrequency == 0 {
-			hwlog.RunLog.Errorf("websocket client connect the server failed, error: %v",
-				utils.TrimInfoFromError(err))
+		for _, endpoint := range wcp.getServerEndpoints() {
+			select {
+			case <-wcp.ProxyCfg.ctx.Done():
+				return nil, nil, fmt.Errorf("connect has be canceled")
+			default:
+			}
+			hwlog.RunLog.Infof("websocket client try to connect server [%s]", endpoint.host)
+			endpointDialer := *dialer
+			endpointDialer.NetDialContext = newCountingDialer(endpoint.dialAddr)
+			connect, resp, err := endpointDialer.Dial(wssProtocol+endpoint.host, wcp.ProxyCfg.dialHeader())
+			if err == nil {
+				hwlog.RunLog.Infof("websocket client connect the server [%s] success", endpoint.host)
+				wcp.setActiveHost(endpoint.host)
+				return connect, resp.Header, nil
+			}
+			// print error msg each 3-times, not every time
+			if errCnt += 1; errCnt%errorPrintFrequency == 0 {
+				hwlog.RunLog.Errorf("websocket client connect the server [%s] failed, error: %v",
+					endpoint.host, utils.TrimInfoFromError(err))
+			}
 		}
-		time.Sleep(tryConnInterval)
+		time.Sleep(withJitter(tryConnInterval))
 		if tryConnInterval < maxTryConnInterval {
 			tryConnInterval = tryConnInterval * tryConnIntervalGrowRate
 		}
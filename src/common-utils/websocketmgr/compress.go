@@ -0,0 +1,174 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package websocketmgr
+
+import (
+	"net"
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"huawei.com/mindx/common/hwlog"
+)
+
+const (
+	extensionsHeader    = "Sec-WebSocket-Extensions"
+	permessageDeflate   = "permessage-deflate"
+	maxConnUnwrapDepth  = 4
+	percentBase         = 100
+	maxCompressionLevel = 9
+	minCompressionLevel = 1
+)
+
+type compressCfg struct {
+	threshold int
+	level     int
+}
+
+// CompressionStats traffic statistics of a connection. Raw bytes are the payload size of messages, wire bytes are
+// the bytes on the network, including websocket frame and tls overhead
+type CompressionStats struct {
+	Negotiated   bool  `json:"negotiated"`
+	RawSent      int64 `json:"rawSent"`
+	WireSent     int64 `json:"wireSent"`
+	RawReceived  int64 `json:"rawReceived"`
+	WireReceived int64 `json:"wireReceived"`
+}
+
+// SendRatio the percentage of wire bytes to raw bytes of sent messages, 0 if nothing is sent
+func (s CompressionStats) SendRatio() float64 {
+	return ratio(s.WireSent, s.RawSent)
+}
+
+// ReceiveRatio the percentage of wire bytes to raw bytes of received messages, 0 if nothing is received
+func (s CompressionStats) ReceiveRatio() float64 {
+	return ratio(s.WireReceived, s.RawReceived)
+}
+
+func ratio(wire, raw int64) float64 {
+	if raw == 0 {
+		return 0
+	}
+	return float64(wire) * percentBase / float64(raw)
+}
+
+// countingConn counts the bytes read from and written to the network
+type countingConn struct {
+	net.Conn
+	readBytes    int64
+	writtenBytes int64
+}
+
+// Read reads from the network and counts the bytes
+func (c *countingConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	atomic.AddInt64(&c.readBytes, int64(n))
+	return n, err
+}
+
+// Write writes to the network and counts the bytes
+func (c *countingConn) Write(b []byte) (int, error) {
+	n, err := c.Conn.Write(b)
+	atomic.AddInt64(&c.writtenBytes, int64(n))
+	return n, err
+}
+
+// countingListener wraps the accepted connections with countingConn
+type countingListener struct {
+	net.Listener
+}
+
+// Accept waits for the next connection and wraps it with countingConn
+func (l *countingListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	return &countingConn{Conn: conn}, nil
+}
+
+// findCountingConn unwraps the tls connection to find the counting connection beneath it
+func findCountingConn(conn net.Conn) *countingConn {
+	for i := 0; i < maxConnUnwrapDepth && conn != nil; i++ {
+		if counting, ok := conn.(*countingConn); ok {
+			return counting
+		}
+		wrapper, ok := conn.(interface{ NetConn() net.Conn })
+		if !ok {
+			return nil
+		}
+		conn = wrapper.NetConn()
+	}
+	return nil
+}
+
+func isDeflateNegotiated(header http.Header) bool {
+	for _, ext := range header.Values(extensionsHeader) {
+		if strings.Contains(ext, permessageDeflate) {
+			return true
+		}
+	}
+	return false
+}
+
+func (cm *wsConnectMgr) initCompression() {
+	if cm.conn.NetConn() != nil {
+		cm.wireConn = findCountingConn(cm.conn.NetConn())
+	}
+	if !cm.compressNegotiated {
+		return
+	}
+	cfg := cm.currentProxy.GetProxyConfig().compressCfg
+	if cfg == nil {
+		cm.compressNegotiated = false
+		return
+	}
+	if err := cm.conn.SetCompressionLevel(cfg.level); err != nil {
+		hwlog.RunLog.Errorf("[%s] set compression level failed: %v", cm.getPeerId(), err)
+	}
+	hwlog.RunLog.Infof("[%s] permessage-deflate is negotiated", cm.getPeerId())
+}
+
+// shouldCompress stream frames are not compressed since their contents are mostly compressed packages
+func (cm *wsConnectMgr) shouldCompress(msgType int, data []byte) bool {
+	if !cm.compressNegotiated || msgType != WsMsgTypeText && msgType != WsMsgTypeBinary {
+		return false
+	}
+	cfg := cm.currentProxy.GetProxyConfig().compressCfg
+	return cfg != nil && len(data) >= cfg.threshold && !isStreamFrame(data)
+}
+
+// wireReadLen the bytes read from network since last message, it is the message size if bytes are not counted
+func (cm *wsConnectMgr) wireReadLen(msgLen int) int {
+	atomic.AddInt64(&cm.rawReceived, int64(msgLen))
+	if cm.wireConn == nil {
+		return msgLen
+	}
+	read := atomic.LoadInt64(&cm.wireConn.readBytes)
+	wireLen := read - cm.lastWireRead
+	cm.lastWireRead = read
+	return int(wireLen)
+}
+
+func (cm *wsConnectMgr) compressionStats() CompressionStats {
+	stats := CompressionStats{
+		Negotiated:   cm.compressNegotiated,
+		RawSent:      atomic.LoadInt64(&cm.rawSent),
+		RawReceived:  atomic.LoadInt64(&cm.rawReceived),
+		WireSent:     atomic.LoadInt64(&cm.rawSent),
+		WireReceived: atomic.LoadInt64(&cm.rawReceived),
+	}
+	if cm.wireConn != nil {
+		stats.WireSent = atomic.LoadInt64(&cm.wireConn.writtenBytes)
+		stats.WireReceived = atomic.LoadInt64(&cm.wireConn.readBytes)
+	}
+	return stats
+}
@@ -0,0 +1,183 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package websocketmgr
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/smartystreets/goconvey/convey"
+)
+
+const (
+	testCompressThreshold = 128
+	testCompressWaitTime  = 5 * time.Second
+)
+
+func TestSetCompressionCfg(t *testing.T) {
+	convey.Convey("set compression config", t, func() {
+		cfg := &ProxyConfig{}
+		convey.So(cfg.SetCompressionCfg(-1, minCompressionLevel), convey.ShouldNotBeNil)
+		convey.So(cfg.SetCompressionCfg(testCompressThreshold, maxCompressionLevel+1), convey.ShouldNotBeNil)
+		convey.So(cfg.compressCfg, convey.ShouldBeNil)
+		convey.So(cfg.SetCompressionCfg(testCompressThreshold, minCompressionLevel), convey.ShouldBeNil)
+		convey.So(cfg.compressCfg, convey.ShouldResemble,
+			&compressCfg{threshold: testCompressThreshold, level: minCompressionLevel})
+	})
+}
+
+func TestFindCountingConn(t *testing.T) {
+	convey.Convey("counting connection is found beneath tls connection", t, func() {
+		local, remote := net.Pipe()
+		defer local.Close()
+		defer remote.Close()
+		counting := &countingConn{Conn: local}
+		convey.So(findCountingConn(counting), convey.ShouldEqual, counting)
+		convey.So(findCountingConn(tls.Client(counting, &tls.Config{})), convey.ShouldEqual, counting)
+		convey.So(findCountingConn(local), convey.ShouldBeNil)
+	})
+
+	convey.Convey("deflate negotiation is read from extensions header", t, func() {
+		header := http.Header{}
+		convey.So(isDeflateNegotiated(header), convey.ShouldBeFalse)
+		header.Add(extensionsHeader, "permessage-deflate; server_no_context_takeover; client_no_context_takeover")
+		convey.So(isDeflateNegotiated(header), convey.ShouldBeTrue)
+	})
+}
+
+// compressPeers a server connection manager and a client connection manager connected by a real websocket
+type compressPeers struct {
+	server   *httptest.Server
+	serverCm chan *wsConnectMgr
+	clientCm *wsConnectMgr
+}
+
+func newCompressConnMgr(conn *websocket.Conn, cfg *ProxyConfig, negotiated bool) *wsConnectMgr {
+	cm := &wsConnectMgr{conn: conn, peerInfo: WebsocketPeerInfo{Sn: "peer"}, connFlag: true,
+		currentProxy: &WsServerProxy{ProxyCfg: cfg}, compressNegotiated: negotiated}
+	cm.ctx, cm.cancel = context.WithCancel(context.Background())
+	cm.initCompression()
+	return cm
+}
+
+func newCompressPeers(serverCfg, clientCfg *ProxyConfig) *compressPeers {
+	peers := &compressPeers{serverCm: make(chan *wsConnectMgr, 1)}
+	upgrader := &websocket.Upgrader{EnableCompression: serverCfg.compressCfg != nil}
+	peers.server = httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		peers.serverCm <- newCompressConnMgr(conn, serverCfg, upgrader.EnableCompression && isDeflateNegotiated(r.Header))
+	}))
+	peers.server.Listener = &countingListener{Listener: peers.server.Listener}
+	peers.server.Start()
+
+	dialer := &websocket.Dialer{
+		EnableCompression: clientCfg.compressCfg != nil,
+		NetDialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			conn, err := (&net.Dialer{}).DialContext(ctx, network, addr)
+			if err != nil {
+				return nil, err
+			}
+			return &countingConn{Conn: conn}, nil
+		},
+	}
+	conn, resp, err := dialer.Dial("ws"+strings.TrimPrefix(peers.server.URL, "http"), nil)
+	if err != nil {
+		panic(err)
+	}
+	peers.clientCm = newCompressConnMgr(conn, clientCfg, isDeflateNegotiated(resp.Header))
+	return peers
+}
+
+func (p *compressPeers) close() {
+	if err := p.clientCm.conn.Close(); err != nil {
+		panic(err)
+	}
+	p.server.Close()
+}
+
+func (p *compressPeers) getServerCm() *wsConnectMgr {
+	select {
+	case cm := <-p.serverCm:
+		return cm
+	case <-time.After(testCompressWaitTime):
+		panic("server connection is not established")
+	}
+}
+
+func newCompressibleMsg() []byte {
+	return []byte(`{"header":{},"content":"` + strings.Repeat("pod status is running,", 1000) + `"}`)
+}
+
+func newCompressCfgForUT() *ProxyConfig {
+	cfg := &ProxyConfig{}
+	if err := cfg.SetCompressionCfg(testCompressThreshold, minCompressionLevel); err != nil {
+		panic(err)
+	}
+	return cfg
+}
+
+func TestCompression(t *testing.T) {
+	convey.Convey("message is compressed when both peers enable compression", t, func() {
+		peers := newCompressPeers(newCompressCfgForUT(), newCompressCfgForUT())
+		defer peers.close()
+		serverCm := peers.getServerCm()
+		convey.So(serverCm.compressNegotiated, convey.ShouldBeTrue)
+		convey.So(peers.clientCm.compressNegotiated, convey.ShouldBeTrue)
+
+		msg := newCompressibleMsg()
+		convey.So(peers.clientCm.send(websocket.TextMessage, msg), convey.ShouldBeNil)
+		_, received, err := serverCm.readMsg()
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(bytes.Equal(received, msg), convey.ShouldBeTrue)
+		wireLen := serverCm.wireReadLen(len(received))
+		convey.So(wireLen, convey.ShouldBeLessThan, len(msg)/2)
+
+		clientStats := peers.clientCm.compressionStats()
+		convey.So(clientStats.RawSent, convey.ShouldEqual, len(msg))
+		convey.So(clientStats.SendRatio(), convey.ShouldBeLessThan, percentBase/2)
+		serverStats := serverCm.compressionStats()
+		convey.So(serverStats.RawReceived, convey.ShouldEqual, len(msg))
+		convey.So(serverStats.ReceiveRatio(), convey.ShouldBeLessThan, percentBase/2)
+	})
+
+	convey.Convey("message is not compressed when peer does not enable compression", t, func() {
+		peers := newCompressPeers(&ProxyConfig{}, newCompressCfgForUT())
+		defer peers.close()
+		serverCm := peers.getServerCm()
+		convey.So(peers.clientCm.compressNegotiated, convey.ShouldBeFalse)
+
+		msg := newCompressibleMsg()
+		convey.So(peers.clientCm.send(websocket.TextMessage, msg), convey.ShouldBeNil)
+		_, received, err := serverCm.readMsg()
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(bytes.Equal(received, msg), convey.ShouldBeTrue)
+		convey.So(serverCm.wireReadLen(len(received)), convey.ShouldBeGreaterThanOrEqualTo, len(msg))
+	})
+
+	convey.Convey("small messages and stream frames are not compressed", t, func() {
+		cm := &wsConnectMgr{compressNegotiated: true, currentProxy: &WsServerProxy{ProxyCfg: newCompressCfgForUT()}}
+		convey.So(cm.shouldCompress(websocket.TextMessage, newCompressibleMsg()), convey.ShouldBeTrue)
+		convey.So(cm.shouldCompress(websocket.TextMessage, []byte(`{}`)), convey.ShouldBeFalse)
+		frame := encodeStreamFrame(streamFrame{frameType: frameData, payload: make([]byte, testCompressThreshold)})
+		convey.So(cm.shouldCompress(websocket.BinaryMessage, frame), convey.ShouldBeFalse)
+	})
+}
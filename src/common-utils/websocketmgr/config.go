@@ -29,6 +29,7 @@ const (
 	svcUrl                  = "/"
 	clientNameKey           = "clientName"
 	realIpKey               = "X-Real-IP"
+	clientCertSnKey         = "X-Client-Cert-Sn"
 	retryInterval           = 5 * time.Second
 	reconnectInterval       = 3 * time.Second
 	maxTryConnInterval      = 128 * time.Second
@@ -37,6 +38,13 @@ const (
 	defaultWriteTimeout     = 30 * time.Second
 	defaultHeaderSizeLimit  = 1024
 	defaultConnLimit        = 1024
+	defaultWorkerNum        = 16
+	defaultWorkerQueueSize  = 256
+	maxWorkerNum            = 256
+	maxWorkerQueueSize      = 4096
+	throttleBackoffTime     = 3 * time.Second
+	maxThrottleWaitTime     = 2 * throttleBackoffTime
+	maxPort                 = 65535
 	WsMsgTypeText           = websocket.TextMessage
 	WsMsgTypeBinary         = websocket.BinaryMessage
 	WsMsgTypeClose          = websocket.CloseMessage
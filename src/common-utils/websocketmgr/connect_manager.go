@@ -17,13 +17,13 @@ import (
 	"fmt"
 	"io"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/websocket"
 
 	"huawei.com/mindx/common/hwlog"
 	"huawei.com/mindx/common/limiter"
-	"huawei.com/mindx/common/modulemgr/model"
 )
 
 // ProxyInstanceIntf get related proxy instance(eg: server proxy / client proxy) then retrieve callbacks and limiter
@@ -39,6 +39,11 @@ type ProxyInstanceIntf interface {
 type WebsocketPeerInfo struct {
 	Sn string
 	Ip string
+	// CertSn the serial number in hex of the client cert, which is forwarded by the tls terminating proxy
+	CertSn string
+	// protocol version and capabilities sent by peer on connect, version is 0 if the peer is legacy
+	ProtocolVersion int
+	Capabilities    []string
 }
 
 type wsConnectMgr struct {
@@ -53,6 +58,17 @@ type wsConnectMgr struct {
 	cancel        context.CancelFunc
 	sendLock      sync.Mutex
 	rpsLimiter    limiter.IndependentLimiter // conn-level ws rps limiter, run before message-level limiter
+	inStreams     sync.Map                   // streams being received, key is stream id
+	outStreams    sync.Map                   // streams being sent, key is stream id
+	workers       *workerPool                // handles received messages with bounded queues
+	// unix nano time until which requests are not sent, since the peer throttled previous messages
+	throttledUntil int64
+	// traffic counters, wireConn is nil if the network connection is not wrapped for counting
+	compressNegotiated bool
+	wireConn           *countingConn
+	rawSent            int64
+	rawReceived        int64
+	lastWireRead       int64
 }
 
 const (
@@ -69,6 +85,7 @@ func (cm *wsConnectMgr) start() {
 	cm.conn.SetPingHandler(cm.pingHandle)
 	cm.conn.SetCloseHandler(cm.closeHandle)
 	cm.conn.SetReadLimit(defaultReadSizeLimit)
+	cm.initCompression()
 	// initialize and start a connection related message limiter for each websocket connection
 	if cfg := cm.currentProxy.GetProxyConfig().rpsLimiterCfg; cfg != nil {
 		cm.rpsLimiter = limiter.NewRpsLimiter(cfg.Rps, cfg.Burst)
@@ -93,6 +110,7 @@ func (cm *wsConnectMgr) isConnected() bool {
 }
 
 func (cm *wsConnectMgr) startLoop() {
+	cm.startWorkers()
 	go cm.heartbeat()
 	go cm.receive()
 }
@@ -109,7 +127,7 @@ func (cm *wsConnectMgr) receive() {
 			return
 		default:
 		}
-		msg, err := cm.readMsg()
+		msgType, msg, err := cm.readMsg()
 		if err != nil {
 			hwlog.RunLog.Errorf("read next websocket message from [%v] error: %v", cm.getPeerId(), err)
 			cm.waitHandleGrp.Done()
@@ -121,23 +139,22 @@ func (cm *wsConnectMgr) receive() {
 		if msg == nil {
 			continue
 		}
-		if err := cm.limiterCheck(len(msg)); err != nil {
-			hwlog.RunLog.Warnf("%v", err)
+		// the bandwidth limiter accounts for the bytes on the network, which are less than message when compressed
+		limitErr := cm.limiterCheck(cm.wireReadLen(len(msg)))
+		if msgType == websocket.BinaryMessage && isStreamFrame(msg) {
+			cm.handleStreamFrame(msg, limitErr)
 			continue
 		}
-		peerInfo := model.MsgPeerInfo{Ip: cm.peerInfo.Ip, Sn: cm.peerInfo.Sn}
-		handler := cm.currentProxy.GetProxyConfig().handlerMgr
-		if handler == nil {
-			hwlog.RunLog.Errorf("message handler is not initialized")
-			continue
-		}
-		go func() {
-			cm.sendMsg(handler.HandleMsg(msg, peerInfo))
-		}()
+		cm.dispatchMsg(msg, limitErr)
 	}
 }
 
 func (cm *wsConnectMgr) postProcess() {
+	cm.cleanStreams(true)
+	stats := cm.compressionStats()
+	hwlog.RunLog.Infof("[%s] traffic stats, sent: %d/%d bytes (%.1f%%), received: %d/%d bytes (%.1f%%)",
+		cm.getPeerId(), stats.WireSent, stats.RawSent, stats.SendRatio(),
+		stats.WireReceived, stats.RawReceived, stats.ReceiveRatio())
 	for _, f := range cm.currentProxy.GetDisconnectCallbacks() {
 		f(cm.peerInfo)
 	}
@@ -149,24 +166,26 @@ func (cm *wsConnectMgr) postProcess() {
 	}
 }
 
-func (cm *wsConnectMgr) readMsg() ([]byte, error) {
+// readMsg reads text message or binary message, binary message is either a stream frame or a message in json
+// format the same as text message
+func (cm *wsConnectMgr) readMsg() (int, []byte, error) {
 	localConn := cm.conn
 	if localConn == nil {
-		return nil, errors.New("connection is nil")
+		return 0, nil, errors.New("connection is nil")
 	}
 	messageType, reader, err := localConn.NextReader()
 	if err != nil {
-		return nil, fmt.Errorf("read message header error: %v", err)
+		return 0, nil, fmt.Errorf("read message header error: %v", err)
 	}
-	if messageType != websocket.TextMessage {
+	if messageType != websocket.TextMessage && messageType != websocket.BinaryMessage {
 		hwlog.RunLog.Errorf("[%s] received not support message type: %v", cm.getPeerId(), messageType)
-		return nil, nil
+		return messageType, nil, nil
 	}
 	msg, err := io.ReadAll(io.LimitReader(reader, defaultReadSizeLimit))
 	if err != nil {
-		return nil, fmt.Errorf("read message body error: %v", err)
+		return 0, nil, fmt.Errorf("read message body error: %v", err)
 	}
-	return msg, nil
+	return messageType, msg, nil
 }
 
 func (cm *wsConnectMgr) sendMsg(data []byte) {
@@ -202,6 +221,7 @@ func (cm *wsConnectMgr) heartbeat() {
 		default:
 		}
 		cm.sendPingMsg()
+		cm.cleanStreams(false)
 		time.Sleep(defaultHeartbeatInterval)
 		if time.Now().Sub(cm.lastAlive) > defaultHeartbeatTimeout {
 			hwlog.RunLog.Errorf("[%s] heartbeat timeout", cm.getPeerId())
@@ -230,6 +250,10 @@ func (cm *wsConnectMgr) send(msgType int, data []byte) error {
 	if localConn == nil {
 		return errors.New("websocket connection is nil")
 	}
+	if msgType == websocket.TextMessage || msgType == websocket.BinaryMessage {
+		localConn.EnableWriteCompression(cm.shouldCompress(msgType, data))
+		atomic.AddInt64(&cm.rawSent, int64(len(data)))
+	}
 	return localConn.WriteMessage(msgType, data)
 }
 
@@ -34,7 +34,7 @@ func TestReadMsg(t *testing.T) {
 				websocket.TextMessage, bytes.NewReader(make([]byte, msgSize)), nil)
 			defer patches.Reset()
 
-			msg, err := cm.readMsg()
+			_, msg, err := cm.readMsg()
 			convey.So(err != nil || len(msg) <= int(defaultReadSizeLimit), convey.ShouldBeTrue)
 		})
 
@@ -44,7 +44,7 @@ func TestReadMsg(t *testing.T) {
 				websocket.TextMessage, bytes.NewReader(make([]byte, msgReadLimit)), nil)
 			defer patches.Reset()
 
-			msg, err := cm.readMsg()
+			_, msg, err := cm.readMsg()
 			convey.So(err, convey.ShouldBeNil)
 			convey.So(len(msg), convey.ShouldEqual, msgReadLimit)
 		})
@@ -7,6 +7,7 @@ require (
 	github.com/gorilla/websocket v1.5.1
 	github.com/smartystreets/goconvey v1.7.2
 	huawei.com/mindx/common/checker v0.0.0
+	huawei.com/mindx/common/fileutils v0.0.4
 	huawei.com/mindx/common/hwlog v0.10.5
 	huawei.com/mindx/common/limiter v0.0.0
 	huawei.com/mindx/common/modulemgr v0.0.0
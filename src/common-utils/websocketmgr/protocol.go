@@ -0,0 +1,115 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package websocketmgr
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"huawei.com/mindx/common/hwlog"
+)
+
+const (
+	protocolVersionKey = "X-Protocol-Version"
+	capabilitiesKey    = "X-Capabilities"
+	capabilitySep      = ","
+	// LegacyProtocolVersion the protocol version of the peer which does not send its version on connect
+	LegacyProtocolVersion = 0
+	maxProtocolVersion    = 65535
+	maxCapabilityNum      = 64
+)
+
+var capabilityReg = regexp.MustCompile(`^[a-z0-9_]{1,64}$`)
+
+// protocolCfg protocol version and capabilities exchanged with the peer on connect, the capabilities may change
+// after the proxy starts, so they are got on each connect
+type protocolCfg struct {
+	version  int
+	capsFunc func() []string
+}
+
+// SetProtocolInfo set the protocol version and the function getting capabilities, they are sent by client in the
+// handshake request and by server in the handshake response
+func (pc *ProxyConfig) SetProtocolInfo(version int, capsFunc func() []string) error {
+	if version <= LegacyProtocolVersion || version > maxProtocolVersion {
+		return fmt.Errorf("protocol version should be in range (%d, %d]", LegacyProtocolVersion, maxProtocolVersion)
+	}
+	pc.protocolCfg = &protocolCfg{version: version, capsFunc: capsFunc}
+	return nil
+}
+
+// setProtocolHeader set protocol version and capabilities into header, invalid capabilities are ignored
+func (pc *ProxyConfig) setProtocolHeader(header http.Header) {
+	if pc.protocolCfg == nil {
+		return
+	}
+	header.Set(protocolVersionKey, strconv.Itoa(pc.protocolCfg.version))
+	if pc.protocolCfg.capsFunc == nil {
+		return
+	}
+	var caps []string
+	for _, capability := range pc.protocolCfg.capsFunc() {
+		if len(caps) >= maxCapabilityNum {
+			hwlog.RunLog.Warnf("capabilities exceed the limit %d, the rest are ignored", maxCapabilityNum)
+			break
+		}
+		if !capabilityReg.MatchString(capability) {
+			hwlog.RunLog.Warnf("capability [%s] is invalid, ignore it", capability)
+			continue
+		}
+		caps = append(caps, capability)
+	}
+	if len(caps) > 0 {
+		header.Set(capabilitiesKey, strings.Join(caps, capabilitySep))
+	}
+}
+
+// dialHeader the header of handshake request, it is built on each connect since the capabilities may change
+func (pc *ProxyConfig) dialHeader() http.Header {
+	header := pc.headers.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	pc.setProtocolHeader(header)
+	return header
+}
+
+// parseProtocolHeader parses the protocol version and capabilities sent by peer,
+// the peer without version is regarded as legacy
+func parseProtocolHeader(header http.Header) (int, []string, error) {
+	versionStr := header.Get(protocolVersionKey)
+	if versionStr == "" {
+		return LegacyProtocolVersion, nil, nil
+	}
+	version, err := strconv.Atoi(versionStr)
+	if err != nil || version <= LegacyProtocolVersion || version > maxProtocolVersion {
+		return LegacyProtocolVersion, nil, errors.New("protocol version is invalid")
+	}
+	capsStr := header.Get(capabilitiesKey)
+	if capsStr == "" {
+		return version, []string{}, nil
+	}
+	caps := strings.Split(capsStr, capabilitySep)
+	if len(caps) > maxCapabilityNum {
+		return LegacyProtocolVersion, nil, fmt.Errorf("the count of capabilities exceeds the limit %d",
+			maxCapabilityNum)
+	}
+	for _, capability := range caps {
+		if !capabilityReg.MatchString(capability) {
+			return LegacyProtocolVersion, nil, errors.New("capability is invalid")
+		}
+	}
+	return version, caps, nil
+}
@@ -0,0 +1,106 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package websocketmgr
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/smartystreets/goconvey/convey"
+)
+
+const testProtocolVersion = 2
+
+func TestSetProtocolInfo(t *testing.T) {
+	convey.Convey("set protocol info", t, func() {
+		cfg := &ProxyConfig{}
+		convey.So(cfg.SetProtocolInfo(LegacyProtocolVersion, nil), convey.ShouldNotBeNil)
+		convey.So(cfg.SetProtocolInfo(maxProtocolVersion+1, nil), convey.ShouldNotBeNil)
+		convey.So(cfg.protocolCfg, convey.ShouldBeNil)
+		convey.So(cfg.SetProtocolInfo(testProtocolVersion, nil), convey.ShouldBeNil)
+		convey.So(cfg.protocolCfg.version, convey.ShouldEqual, testProtocolVersion)
+	})
+}
+
+func TestDialHeader(t *testing.T) {
+	convey.Convey("dial header without protocol info only has client name", t, func() {
+		cfg := &ProxyConfig{headers: http.Header{}}
+		cfg.headers.Set(clientNameKey, endpointName)
+		header := cfg.dialHeader()
+		convey.So(header.Get(clientNameKey), convey.ShouldEqual, endpointName)
+		convey.So(header.Get(protocolVersionKey), convey.ShouldBeEmpty)
+	})
+
+	convey.Convey("capabilities are got on each dial and invalid ones are ignored", t, func() {
+		caps := []string{"npu_sharing"}
+		cfg := &ProxyConfig{headers: http.Header{}}
+		convey.So(cfg.SetProtocolInfo(testProtocolVersion, func() []string { return caps }), convey.ShouldBeNil)
+		header := cfg.dialHeader()
+		convey.So(header.Get(protocolVersionKey), convey.ShouldEqual, "2")
+		convey.So(header.Get(capabilitiesKey), convey.ShouldEqual, "npu_sharing")
+		caps = append(caps, "pod_restart", "Invalid,Cap")
+		header = cfg.dialHeader()
+		convey.So(header.Get(capabilitiesKey), convey.ShouldEqual, "npu_sharing,pod_restart")
+		convey.So(cfg.headers.Get(protocolVersionKey), convey.ShouldBeEmpty)
+	})
+}
+
+func TestParseProtocolHeader(t *testing.T) {
+	convey.Convey("peer without protocol version is legacy", t, func() {
+		version, caps, err := parseProtocolHeader(http.Header{})
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(version, convey.ShouldEqual, LegacyProtocolVersion)
+		convey.So(caps, convey.ShouldBeNil)
+	})
+
+	convey.Convey("protocol version and capabilities are parsed", t, func() {
+		header := http.Header{}
+		header.Set(protocolVersionKey, "2")
+		header.Set(capabilitiesKey, "npu_sharing,pod_restart")
+		version, caps, err := parseProtocolHeader(header)
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(version, convey.ShouldEqual, testProtocolVersion)
+		convey.So(caps, convey.ShouldResemble, []string{"npu_sharing", "pod_restart"})
+	})
+
+	convey.Convey("invalid protocol info is rejected", t, func() {
+		header := http.Header{}
+		header.Set(protocolVersionKey, "-1")
+		_, _, err := parseProtocolHeader(header)
+		convey.So(err, convey.ShouldNotBeNil)
+		header.Set(protocolVersionKey, "2")
+		header.Set(capabilitiesKey, "npu_sharing,../x")
+		_, _, err = parseProtocolHeader(header)
+		convey.So(err, convey.ShouldNotBeNil)
+		header.Set(capabilitiesKey, strings.Repeat("a,", maxCapabilityNum)+"a")
+		_, _, err = parseProtocolHeader(header)
+		convey.So(err, convey.ShouldNotBeNil)
+	})
+}
+
+func TestServeInvalidProtocolHeader(t *testing.T) {
+	convey.Convey("client with invalid protocol info is responded with bad request", t, func() {
+		cfg := &ProxyConfig{}
+		convey.So(cfg.SetProtocolInfo(testProtocolVersion, nil), convey.ShouldBeNil)
+		wsp := &WsServerProxy{ProxyCfg: cfg}
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Connection", "Upgrade")
+		req.Header.Set("Upgrade", "websocket")
+		req.Header.Set(clientNameKey, endpointName)
+		req.Header.Set(protocolVersionKey, "-1")
+		recorder := httptest.NewRecorder()
+		wsp.serveHTTP(recorder, req)
+		convey.So(recorder.Code, convey.ShouldEqual, http.StatusBadRequest)
+		convey.So(recorder.Header().Get(protocolVersionKey), convey.ShouldEqual, "2")
+	})
+}
@@ -13,9 +13,11 @@ package websocketmgr
 import (
 	"context"
 	"crypto/tls"
+	"errors"
 	"fmt"
 	"net"
 	"net/http"
+	"net/url"
 	"strconv"
 	"time"
 
@@ -32,6 +34,7 @@ type ProxyConfig struct {
 	tlsConfig           *tls.Config
 	isServer            bool
 	hosts               string
+	serverHosts         []string // ordered server addresses for client to connect, hosts is used if not set
 	headers             http.Header
 	handlerMgr          modulemgr.HandleMessageIntf
 	ctx                 context.Context
@@ -42,8 +45,17 @@ type ProxyConfig struct {
 	writeTimeout        time.Duration
 	rpsLimiterCfg       *limiter.RpsLimiterCfg          // rps limiter for each WebSocket connection
 	bandwidthLimiterCfg *limiter.BandwidthLimiterConfig // bandwidth limiter shared by all WebSocket connections
+	streamHandler       StreamHandler                   // creates sink for streams opened by peer
+	streamCfg           *streamCfg                      // chunk size and flow control window of sent streams
+	workerPoolCfg       *workerPoolCfg                  // workers and queue size handling messages of each connection
+	compressCfg         *compressCfg                    // permessage-deflate is negotiated only if it is set
+	httpProxy           proxyFunc                       // http CONNECT proxy of client, nil means direct
+	protocolCfg         *protocolCfg                    // protocol info is not exchanged if it is not set
 }
 
+// proxyFunc returns the proxy url for a request, nil url means connecting directly
+type proxyFunc = func(*http.Request) (*url.URL, error)
+
 // RegModInfos registers module info
 func (pc *ProxyConfig) RegModInfos(regHandlers []modulemgr.MessageHandlerIntf) {
 	if pc.handlerMgr == nil {
@@ -55,6 +67,42 @@ func (pc *ProxyConfig) RegModInfos(regHandlers []modulemgr.MessageHandlerIntf) {
 	}
 }
 
+// RegStreamHandler registers the handler for streams opened by peer, streams are refused if no handler registered
+func (pc *ProxyConfig) RegStreamHandler(handler StreamHandler) {
+	pc.streamHandler = handler
+}
+
+// SetStreamCfg set chunk size and flow control window in bytes for sent streams,
+// the window is the size of content sent but not acked by peer
+func (pc *ProxyConfig) SetStreamCfg(chunkSize int, windowSize int64) error {
+	if chunkSize <= 0 || chunkSize > maxStreamChunkSize || windowSize < int64(chunkSize) {
+		return fmt.Errorf("invalid stream config. chunkSize: %v, windowSize: %v", chunkSize, windowSize)
+	}
+	pc.streamCfg = &streamCfg{chunkSize: chunkSize, windowSize: windowSize}
+	return nil
+}
+
+// SetWorkerPoolCfg set worker number and queue size for handling received messages of each connection,
+// messages are replied throttled when the queue is full
+func (pc *ProxyConfig) SetWorkerPoolCfg(workerNum, queueSize int) error {
+	if workerNum <= 0 || workerNum > maxWorkerNum || queueSize <= 0 || queueSize > maxWorkerQueueSize {
+		return fmt.Errorf("invalid worker pool config. workerNum: %v, queueSize: %v", workerNum, queueSize)
+	}
+	pc.workerPoolCfg = &workerPoolCfg{workerNum: workerNum, queueSize: queueSize}
+	return nil
+}
+
+// SetCompressionCfg enable permessage-deflate negotiation, messages not smaller than threshold in bytes are
+// compressed with the level from 1 (best speed) to 9 (best compression) once the peer agrees
+func (pc *ProxyConfig) SetCompressionCfg(threshold, level int) error {
+	if threshold < 0 || int64(threshold) > defaultReadSizeLimit || level < minCompressionLevel ||
+		level > maxCompressionLevel {
+		return fmt.Errorf("invalid compression config. threshold: %v, level: %v", threshold, level)
+	}
+	pc.compressCfg = &compressCfg{threshold: threshold, level: level}
+	return nil
+}
+
 // UpdateTlsCa UpdateTls to update websocket tls config
 func (pc *ProxyConfig) UpdateTlsCa(caCertBytes []byte) error {
 	if len(caCertBytes) == 0 {
@@ -107,6 +155,29 @@ func (pc *ProxyConfig) SetBandwidthLimiterCfg(maxThroughput int, period time.Dur
 	return nil
 }
 
+// SetServerHosts set the ordered server addresses for client, the client tries them in order when connecting,
+// a host is either an ip or a domain name whose records are all tried. It overrides the host of InitProxyConfig
+func (pc *ProxyConfig) SetServerHosts(hosts []string, port int) error {
+	if len(hosts) == 0 || len(hosts) > maxServerHosts || port <= 0 || port > maxPort {
+		return fmt.Errorf("invalid server hosts config. host count: %v, port: %v", len(hosts), port)
+	}
+	serverHosts := make([]string, 0, len(hosts))
+	for _, host := range hosts {
+		if host == "" {
+			return errors.New("invalid server hosts config, host is empty")
+		}
+		serverHosts = append(serverHosts, net.JoinHostPort(host, strconv.Itoa(port)))
+	}
+	pc.serverHosts = serverHosts
+	pc.hosts = serverHosts[0]
+	return nil
+}
+
+// SetHttpProxy set the http CONNECT proxy for client, the proxy returns nil url for the hosts connected directly
+func (pc *ProxyConfig) SetHttpProxy(proxy proxyFunc) {
+	pc.httpProxy = proxy
+}
+
 // SetTimeout set 0 to use default timeout
 func (pc *ProxyConfig) SetTimeout(rTimeout, wTimeout, rHeaderTime time.Duration) {
 	pc.readTimeout = defaultReadTimeout
@@ -15,6 +15,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
 	"regexp"
@@ -35,6 +36,8 @@ import (
 const (
 	defaultReserveRate = 0.5
 	regexpSerialNumber = `^[a-zA-Z0-9]([-_a-zA-Z0-9]{0,62}[a-zA-Z0-9])?$`
+	// regexpCertSn the serial number of cert in hex, at most 20 bytes
+	regexpCertSn = `^[0-9a-fA-F]{1,40}$`
 )
 
 // WsServerProxy websocket server proxy
@@ -76,9 +79,10 @@ func (wsp *WsServerProxy) Start() error {
 	wsp.httpServer = httpServer
 	wsp.initHandlers()
 	wsp.upgrade = &websocket.Upgrader{
-		ReadBufferSize:   wsReadBufferSize,
-		WriteBufferSize:  wsWriteBufferSize,
-		HandshakeTimeout: defaultHandshakeTimeout,
+		ReadBufferSize:    wsReadBufferSize,
+		WriteBufferSize:   wsWriteBufferSize,
+		HandshakeTimeout:  defaultHandshakeTimeout,
+		EnableCompression: wsp.ProxyCfg.compressCfg != nil,
 	}
 
 	// initialize then start bandwidth limiter immediately when client proxy starts
@@ -129,6 +133,12 @@ func (wsp *WsServerProxy) Send(clientId string, msg *model.Message, msgType ...i
 	if !ok {
 		return fmt.Errorf("websocket sever send failed: the connect manager type [%T] unsupported", cltConnMgr)
 	}
+	// only requests wait while backing off, responses are still sent so that the peer is not blocked
+	if msg != nil && msg.GetParentId() == "" {
+		if err := connMgr.waitThrottled(); err != nil {
+			return err
+		}
+	}
 	data, err := json.Marshal(msg)
 	if err != nil {
 		return fmt.Errorf("json marshal message error: %v", err)
@@ -136,6 +146,33 @@ func (wsp *WsServerProxy) Send(clientId string, msg *model.Message, msgType ...i
 	return connMgr.send(wsMsgType, data)
 }
 
+// SendStream sends the content of src to client in chunks through binary frames, it blocks until the client confirms
+// the whole content is received. The transfer resumes from the offset the client already has
+func (wsp *WsServerProxy) SendStream(clientId string, meta StreamMeta, src io.ReadSeeker) error {
+	cltConnMgr, ok := wsp.clientMap.Load(clientId)
+	if !ok {
+		return fmt.Errorf("websocket sever send stream failed: the client [%v] not connect", clientId)
+	}
+	connMgr, ok := cltConnMgr.(*wsConnectMgr)
+	if !ok {
+		return fmt.Errorf("websocket sever send stream failed: the connect manager type [%T] unsupported", cltConnMgr)
+	}
+	return connMgr.sendStream(meta, src)
+}
+
+// GetCompressionStats get the traffic statistics of the connection to client
+func (wsp *WsServerProxy) GetCompressionStats(clientId string) (CompressionStats, error) {
+	cltConnMgr, ok := wsp.clientMap.Load(clientId)
+	if !ok {
+		return CompressionStats{}, fmt.Errorf("the client [%v] not connect", clientId)
+	}
+	connMgr, ok := cltConnMgr.(*wsConnectMgr)
+	if !ok {
+		return CompressionStats{}, fmt.Errorf("the connect manager type [%T] unsupported", cltConnMgr)
+	}
+	return connMgr.compressionStats(), nil
+}
+
 func (wsp *WsServerProxy) closeOneClient(name, conn interface{}) bool {
 	wsConn, ok := conn.(*wsConnectMgr)
 	if !ok {
@@ -177,15 +214,36 @@ func (wsp *WsServerProxy) serveHTTP(w http.ResponseWriter, r *http.Request) {
 		hwlog.RunLog.Error("ip is invalid")
 		return
 	}
-	conn, err := wsp.upgrade.Upgrade(w, r, nil)
+	certSn := r.Header.Get(clientCertSnKey)
+	if certSn != "" && !regexp.MustCompile(regexpCertSn).MatchString(certSn) {
+		hwlog.RunLog.Error("cert serial number is invalid")
+		return
+	}
+	version, caps, err := parseProtocolHeader(r.Header)
+	if err != nil {
+		hwlog.RunLog.Errorf("protocol info of client %s is invalid: %v", clientName, err)
+		// the protocol info of server is responded so that the client knows the version supported
+		wsp.ProxyCfg.setProtocolHeader(w.Header())
+		w.WriteHeader(http.StatusBadRequest)
+		if _, err = w.Write([]byte("protocol info is invalid")); err != nil {
+			hwlog.RunLog.Errorf("write response error: %v", utils.TrimInfoFromError(err))
+		}
+		return
+	}
+	respHeader := http.Header{}
+	wsp.ProxyCfg.setProtocolHeader(respHeader)
+	conn, err := wsp.upgrade.Upgrade(w, r, respHeader)
 	if err != nil {
 		hwlog.RunLog.Errorf("websocket start server http failed, error: %v", utils.TrimInfoFromError(err))
 		return
 	}
 	connMgr := &wsConnectMgr{
-		conn:         conn,
-		peerInfo:     WebsocketPeerInfo{Ip: ip, Sn: clientName},
+		conn: conn,
+		peerInfo: WebsocketPeerInfo{Ip: ip, Sn: clientName, CertSn: certSn, ProtocolVersion: version,
+			Capabilities: caps},
 		currentProxy: wsp,
+		// the upgrader agrees permessage-deflate whenever the client offers it and compression is enabled
+		compressNegotiated: wsp.upgrade.EnableCompression && isDeflateNegotiated(r.Header),
 	}
 	_, loaded := wsp.clientMap.LoadOrStore(clientName, connMgr)
 	if loaded {
@@ -196,7 +254,7 @@ func (wsp *WsServerProxy) serveHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	hwlog.RunLog.Infof("client [name=%v] [ip=%v] is connected", clientName, ip)
+	hwlog.RunLog.Infof("client [name=%v] [ip=%v] [protocol version=%d] is connected", clientName, ip, version)
 	hwlog.OpLog.Infof("build websocket connection with [name=%v] [ip=%v] success", clientName, ip)
 	connMgr.start()
 	<-connMgr.ctx.Done()
@@ -213,7 +271,7 @@ func (wsp *WsServerProxy) listen() error {
 			return nil
 		default:
 		}
-		if err := wsp.httpServer.ListenAndServeTLS("", ""); err != nil {
+		if err := wsp.listenAndServe(); err != nil {
 			hwlog.RunLog.Errorf("websocket listen and serve with tls failed, error: %v",
 				utils.TrimInfoFromError(err))
 		}
@@ -221,6 +279,15 @@ func (wsp *WsServerProxy) listen() error {
 	}
 }
 
+// listenAndServe serves on a listener which counts the bytes on the network beneath tls
+func (wsp *WsServerProxy) listenAndServe() error {
+	listener, err := net.Listen("tcp", wsp.httpServer.Addr)
+	if err != nil {
+		return err
+	}
+	return wsp.httpServer.ServeTLS(&countingListener{Listener: listener}, "", "")
+}
+
 func (wsp *WsServerProxy) checkArgs() error {
 	host, portStr, err := net.SplitHostPort(wsp.ProxyCfg.hosts)
 	if err != nil {
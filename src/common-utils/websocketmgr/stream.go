@@ -0,0 +1,524 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package websocketmgr
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"huawei.com/mindx/common/hwlog"
+)
+
+// stream frame layout in a websocket binary message:
+// magic(2) | version(1) | frame type(1) | stream id(16) | offset(8) | crc32 of payload(4) | payload
+const (
+	streamMagic0      = 0xEF
+	streamMagic1      = 0x53
+	streamVersion     = 1
+	streamIdLen       = 16
+	streamHeaderLen   = 32
+	streamTypeIndex   = 3
+	streamIdIndex     = 4
+	streamOffsetIndex = streamIdIndex + streamIdLen
+	streamCrcIndex    = streamOffsetIndex + 8
+)
+
+// stream frame types
+const (
+	frameOpen   byte = 1 // sender -> receiver, payload is the json of StreamMeta
+	frameAccept byte = 2 // receiver -> sender, offset is where the sender resumes from
+	frameData   byte = 3 // sender -> receiver, offset is the position of payload in the stream
+	frameAck    byte = 4 // receiver -> sender, offset is the next byte expected
+	frameRewind byte = 5 // receiver -> sender, a chunk is dropped, sender resends from offset
+	frameEnd    byte = 6 // sender -> receiver, all data is sent
+	frameResult byte = 7 // receiver -> sender, payload is the error message, empty means success
+)
+
+const (
+	defaultStreamChunkSize  = 256 * 1024
+	defaultStreamWindowSize = 8 * defaultStreamChunkSize
+	maxStreamChunkSize      = 1024 * 1024
+	streamCtrlBufferSize    = 64
+	streamAckTimeout        = 60 * time.Second
+	streamRewindInterval    = time.Second
+	streamIdleTimeout       = 5 * time.Minute
+	maxStreamNameLen        = 256
+	// maxInStreams the streams received from one peer at the same time, the content is written in the receiving loop
+	maxInStreams = 4
+)
+
+// StreamMeta describes the content transferred by a stream
+type StreamMeta struct {
+	Id     string `json:"id"`
+	Name   string `json:"name"`
+	Size   int64  `json:"size"`
+	Sha256 string `json:"sha256"`
+}
+
+// StreamSink receives the content of one stream on receiver side
+type StreamSink interface {
+	// Offset returns the size of content already received, the sender resumes from it
+	Offset() int64
+	// Write appends the next chunk of content
+	Write(p []byte) (int, error)
+	// Complete is called when all content is received, the integrity of whole content should be checked here
+	Complete() error
+	// Abort is called when the stream is broken, the received content may be kept for resuming
+	Abort(err error)
+}
+
+// StreamHandler creates the sink for a stream opened by the peer, return error to refuse the stream
+type StreamHandler func(peer WebsocketPeerInfo, meta StreamMeta) (StreamSink, error)
+
+type streamCfg struct {
+	chunkSize  int
+	windowSize int64
+}
+
+type streamFrame struct {
+	frameType byte
+	id        [streamIdLen]byte
+	offset    int64
+	payload   []byte
+}
+
+type outStream struct {
+	ctrl chan streamFrame
+}
+
+type inStream struct {
+	lock         sync.Mutex
+	closed       bool
+	meta         StreamMeta
+	sink         StreamSink
+	offset       int64
+	rewindOffset int64
+	lastActive   time.Time
+}
+
+func isStreamFrame(data []byte) bool {
+	return len(data) >= streamHeaderLen && data[0] == streamMagic0 && data[1] == streamMagic1
+}
+
+func encodeStreamFrame(frame streamFrame) []byte {
+	data := make([]byte, streamHeaderLen+len(frame.payload))
+	data[0], data[1], data[2], data[streamTypeIndex] = streamMagic0, streamMagic1, streamVersion, frame.frameType
+	copy(data[streamIdIndex:streamOffsetIndex], frame.id[:])
+	binary.BigEndian.PutUint64(data[streamOffsetIndex:streamCrcIndex], uint64(frame.offset))
+	binary.BigEndian.PutUint32(data[streamCrcIndex:streamHeaderLen], crc32.ChecksumIEEE(frame.payload))
+	copy(data[streamHeaderLen:], frame.payload)
+	return data
+}
+
+func decodeStreamFrame(data []byte) (streamFrame, error) {
+	var frame streamFrame
+	if !isStreamFrame(data) {
+		return frame, errors.New("not a stream frame")
+	}
+	if data[2] != streamVersion {
+		return frame, fmt.Errorf("stream frame version [%d] is not supported", data[2])
+	}
+	frame.frameType = data[streamTypeIndex]
+	copy(frame.id[:], data[streamIdIndex:streamOffsetIndex])
+	frame.offset = int64(binary.BigEndian.Uint64(data[streamOffsetIndex:streamCrcIndex]))
+	frame.payload = data[streamHeaderLen:]
+	if frame.offset < 0 {
+		return frame, errors.New("stream frame offset is invalid")
+	}
+	if crc32.ChecksumIEEE(frame.payload) != binary.BigEndian.Uint32(data[streamCrcIndex:streamHeaderLen]) {
+		return frame, errors.New("stream frame crc check failed")
+	}
+	return frame, nil
+}
+
+func newStreamId() ([streamIdLen]byte, error) {
+	var id [streamIdLen]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return id, fmt.Errorf("generate stream id failed: %v", err)
+	}
+	return id, nil
+}
+
+func checkStreamMeta(meta StreamMeta) error {
+	if meta.Size < 0 {
+		return errors.New("stream size is invalid")
+	}
+	if len(meta.Name) > maxStreamNameLen {
+		return errors.New("stream name is too long")
+	}
+	// the sha256 is required to check the integrity of whole content and to identify the content for resuming
+	if sum, err := hex.DecodeString(meta.Sha256); err != nil || len(sum) != sha256HexLen/2 {
+		return errors.New("stream sha256 is invalid")
+	}
+	return nil
+}
+
+func (cm *wsConnectMgr) getStreamCfg() streamCfg {
+	if cfg := cm.currentProxy.GetProxyConfig().streamCfg; cfg != nil {
+		return *cfg
+	}
+	return streamCfg{chunkSize: defaultStreamChunkSize, windowSize: defaultStreamWindowSize}
+}
+
+func (cm *wsConnectMgr) sendStreamFrame(frame streamFrame) error {
+	return cm.send(websocket.BinaryMessage, encodeStreamFrame(frame))
+}
+
+func (cm *wsConnectMgr) sendStreamResult(id [streamIdLen]byte, err error) {
+	var payload []byte
+	if err != nil {
+		payload = []byte(err.Error())
+	}
+	if sendErr := cm.sendStreamFrame(streamFrame{frameType: frameResult, id: id, payload: payload}); sendErr != nil {
+		hwlog.RunLog.Errorf("send stream result to [%s] failed: %v", cm.getPeerId(), sendErr)
+	}
+}
+
+// sendStream sends the content of src to peer, it blocks until the peer confirms the whole content is received
+func (cm *wsConnectMgr) sendStream(meta StreamMeta, src io.ReadSeeker) error {
+	if src == nil {
+		return errors.New("stream source is nil")
+	}
+	if err := checkStreamMeta(meta); err != nil {
+		return err
+	}
+	id, err := newStreamId()
+	if err != nil {
+		return err
+	}
+	meta.Id = hex.EncodeToString(id[:])
+	out := &outStream{ctrl: make(chan streamFrame, streamCtrlBufferSize)}
+	cm.outStreams.Store(id, out)
+	defer cm.outStreams.Delete(id)
+
+	metaData, err := json.Marshal(meta)
+	if err != nil {
+		return fmt.Errorf("marshal stream meta failed: %v", err)
+	}
+	if err = cm.sendStreamFrame(streamFrame{frameType: frameOpen, id: id, payload: metaData}); err != nil {
+		return fmt.Errorf("open stream failed: %v", err)
+	}
+	frame, err := cm.waitStreamFrame(out)
+	if err != nil {
+		return err
+	}
+	if frame.frameType != frameAccept {
+		return fmt.Errorf("stream is refused by peer: %s", string(frame.payload))
+	}
+	if frame.offset > meta.Size {
+		return fmt.Errorf("resume offset [%d] exceeds stream size", frame.offset)
+	}
+	hwlog.RunLog.Infof("stream [%s] to [%s] starts from offset %d", meta.Id, cm.getPeerId(), frame.offset)
+	if err = cm.sendStreamData(id, out, meta.Size, frame.offset, src); err != nil {
+		return err
+	}
+	if err = cm.sendStreamFrame(streamFrame{frameType: frameEnd, id: id, offset: meta.Size}); err != nil {
+		return fmt.Errorf("end stream failed: %v", err)
+	}
+	for {
+		if frame, err = cm.waitStreamFrame(out); err != nil {
+			return err
+		}
+		if frame.frameType == frameResult {
+			break
+		}
+	}
+	if len(frame.payload) != 0 {
+		return fmt.Errorf("peer failed to receive stream: %s", string(frame.payload))
+	}
+	hwlog.RunLog.Infof("stream [%s] to [%s] finished", meta.Id, cm.getPeerId())
+	return nil
+}
+
+func (cm *wsConnectMgr) sendStreamData(id [streamIdLen]byte, out *outStream, size, offset int64,
+	src io.ReadSeeker) error {
+	cfg := cm.getStreamCfg()
+	buf := make([]byte, cfg.chunkSize)
+	acked, next := offset, offset
+	for acked < size {
+		for next < size && next-acked < cfg.windowSize {
+			chunkLen := int64(cfg.chunkSize)
+			if size-next < chunkLen {
+				chunkLen = size - next
+			}
+			if _, err := src.Seek(next, io.SeekStart); err != nil {
+				return fmt.Errorf("seek stream source failed: %v", err)
+			}
+			if _, err := io.ReadFull(src, buf[:chunkLen]); err != nil {
+				return fmt.Errorf("read stream source failed: %v", err)
+			}
+			frame := streamFrame{frameType: frameData, id: id, offset: next, payload: buf[:chunkLen]}
+			if err := cm.sendStreamFrame(frame); err != nil {
+				return fmt.Errorf("send stream data failed: %v", err)
+			}
+			next += chunkLen
+		}
+		frame, err := cm.waitStreamFrame(out)
+		if err != nil {
+			return err
+		}
+		switch frame.frameType {
+		case frameAck:
+			if frame.offset > acked && frame.offset <= next {
+				acked = frame.offset
+			}
+		case frameRewind:
+			// the peer dropped a chunk, resend from the position it expects after a while
+			time.Sleep(streamRewindInterval)
+			acked, next = frame.offset, frame.offset
+		case frameResult:
+			return fmt.Errorf("stream is aborted by peer: %s", string(frame.payload))
+		default:
+		}
+	}
+	return nil
+}
+
+func (cm *wsConnectMgr) waitStreamFrame(out *outStream) (streamFrame, error) {
+	timer := time.NewTimer(streamAckTimeout)
+	defer timer.Stop()
+	select {
+	case frame := <-out.ctrl:
+		return frame, nil
+	case <-cm.ctx.Done():
+		return streamFrame{}, errors.New("websocket connection is closed")
+	case <-timer.C:
+		return streamFrame{}, errors.New("wait for stream response from peer timeout")
+	}
+}
+
+// handleStreamFrame handles stream frames in the receiving loop, so the chunks of one stream are handled in order
+func (cm *wsConnectMgr) handleStreamFrame(data []byte, limitErr error) {
+	frame, err := decodeStreamFrame(data)
+	if err != nil {
+		// the id and offset are still usable when only payload is broken, let sender resend it
+		hwlog.RunLog.Warnf("[%s] invalid stream frame: %v", cm.getPeerId(), err)
+		if frame.frameType == frameData {
+			cm.requestRewind(frame.id)
+		}
+		return
+	}
+	switch frame.frameType {
+	case frameOpen:
+		cm.openInStream(frame)
+	case frameData:
+		if limitErr != nil {
+			hwlog.RunLog.Warnf("stream data from [%s] is dropped: %v", cm.getPeerId(), limitErr)
+			cm.requestRewind(frame.id)
+			return
+		}
+		cm.writeInStream(frame)
+	case frameEnd:
+		cm.finishInStream(frame)
+	case frameAccept, frameAck, frameRewind, frameResult:
+		value, ok := cm.outStreams.Load(frame.id)
+		if !ok {
+			return
+		}
+		out, ok := value.(*outStream)
+		if !ok {
+			return
+		}
+		select {
+		case out.ctrl <- frame:
+		default:
+			hwlog.RunLog.Warnf("stream control frame from [%s] is dropped", cm.getPeerId())
+		}
+	default:
+		hwlog.RunLog.Warnf("[%s] unknown stream frame type: %d", cm.getPeerId(), frame.frameType)
+	}
+}
+
+func (cm *wsConnectMgr) openInStream(frame streamFrame) {
+	handler := cm.currentProxy.GetProxyConfig().streamHandler
+	if handler == nil {
+		cm.sendStreamResult(frame.id, errors.New("stream is not supported"))
+		return
+	}
+	var meta StreamMeta
+	if err := json.Unmarshal(frame.payload, &meta); err != nil {
+		cm.sendStreamResult(frame.id, errors.New("invalid stream meta"))
+		return
+	}
+	if err := checkStreamMeta(meta); err != nil {
+		cm.sendStreamResult(frame.id, err)
+		return
+	}
+	if _, ok := cm.inStreams.Load(frame.id); ok {
+		cm.sendStreamResult(frame.id, errors.New("stream is already opened"))
+		return
+	}
+	if cm.countInStreams() >= maxInStreams {
+		hwlog.RunLog.Errorf("stream [%s] from [%s] is refused, too many streams", meta.Id, cm.getPeerId())
+		cm.sendStreamResult(frame.id, errors.New("too many streams, try again later"))
+		return
+	}
+	sink, err := handler(cm.peerInfo, meta)
+	if err != nil {
+		hwlog.RunLog.Errorf("stream [%s] from [%s] is refused: %v", meta.Id, cm.getPeerId(), err)
+		cm.sendStreamResult(frame.id, err)
+		return
+	}
+	offset := sink.Offset()
+	if offset < 0 || offset > meta.Size {
+		sink.Abort(errors.New("received content exceeds stream size"))
+		cm.sendStreamResult(frame.id, errors.New("invalid resume offset"))
+		return
+	}
+	in := &inStream{meta: meta, sink: sink, offset: offset, rewindOffset: -1, lastActive: time.Now()}
+	cm.inStreams.Store(frame.id, in)
+	if err = cm.sendStreamFrame(streamFrame{frameType: frameAccept, id: frame.id, offset: offset}); err != nil {
+		in.lock.Lock()
+		defer in.lock.Unlock()
+		cm.abortInStreamLocked(frame.id, in, err)
+		return
+	}
+	hwlog.RunLog.Infof("stream [%s] from [%s] is opened at offset %d", meta.Id, cm.getPeerId(), offset)
+}
+
+func (cm *wsConnectMgr) countInStreams() int {
+	count := 0
+	cm.inStreams.Range(func(_, _ interface{}) bool {
+		count++
+		return true
+	})
+	return count
+}
+
+func (cm *wsConnectMgr) loadInStream(id [streamIdLen]byte) *inStream {
+	value, ok := cm.inStreams.Load(id)
+	if !ok {
+		return nil
+	}
+	in, ok := value.(*inStream)
+	if !ok {
+		return nil
+	}
+	return in
+}
+
+func (cm *wsConnectMgr) requestRewind(id [streamIdLen]byte) {
+	in := cm.loadInStream(id)
+	if in == nil {
+		return
+	}
+	in.lock.Lock()
+	defer in.lock.Unlock()
+	cm.requestRewindLocked(id, in)
+}
+
+// requestRewindLocked asks the sender to resend from the expected offset, only once for each offset since the
+// chunks in flight after the dropped one are all out of order
+func (cm *wsConnectMgr) requestRewindLocked(id [streamIdLen]byte, in *inStream) {
+	if in.closed || in.rewindOffset == in.offset {
+		return
+	}
+	in.rewindOffset = in.offset
+	if err := cm.sendStreamFrame(streamFrame{frameType: frameRewind, id: id, offset: in.offset}); err != nil {
+		hwlog.RunLog.Errorf("send stream rewind to [%s] failed: %v", cm.getPeerId(), err)
+	}
+}
+
+func (cm *wsConnectMgr) writeInStream(frame streamFrame) {
+	in := cm.loadInStream(frame.id)
+	if in == nil {
+		cm.sendStreamResult(frame.id, errors.New("stream is not opened"))
+		return
+	}
+	in.lock.Lock()
+	defer in.lock.Unlock()
+	if in.closed {
+		return
+	}
+	if frame.offset != in.offset {
+		cm.requestRewindLocked(frame.id, in)
+		return
+	}
+	if in.offset+int64(len(frame.payload)) > in.meta.Size {
+		cm.abortInStreamLocked(frame.id, in, errors.New("received content exceeds stream size"))
+		return
+	}
+	if _, err := in.sink.Write(frame.payload); err != nil {
+		cm.abortInStreamLocked(frame.id, in, fmt.Errorf("write stream content failed: %v", err))
+		return
+	}
+	in.offset += int64(len(frame.payload))
+	in.lastActive = time.Now()
+	if err := cm.sendStreamFrame(streamFrame{frameType: frameAck, id: frame.id, offset: in.offset}); err != nil {
+		hwlog.RunLog.Errorf("send stream ack to [%s] failed: %v", cm.getPeerId(), err)
+	}
+}
+
+func (cm *wsConnectMgr) finishInStream(frame streamFrame) {
+	in := cm.loadInStream(frame.id)
+	if in == nil {
+		cm.sendStreamResult(frame.id, errors.New("stream is not opened"))
+		return
+	}
+	in.lock.Lock()
+	defer in.lock.Unlock()
+	if in.closed {
+		return
+	}
+	if in.offset != in.meta.Size {
+		cm.abortInStreamLocked(frame.id, in, errors.New("stream is ended before all content is received"))
+		return
+	}
+	in.closed = true
+	cm.inStreams.Delete(frame.id)
+	err := in.sink.Complete()
+	if err != nil {
+		hwlog.RunLog.Errorf("complete stream [%s] from [%s] failed: %v", in.meta.Id, cm.getPeerId(), err)
+	} else {
+		hwlog.RunLog.Infof("stream [%s] from [%s] is received", in.meta.Id, cm.getPeerId())
+	}
+	cm.sendStreamResult(frame.id, err)
+}
+
+func (cm *wsConnectMgr) abortInStreamLocked(id [streamIdLen]byte, in *inStream, err error) {
+	in.closed = true
+	cm.inStreams.Delete(id)
+	hwlog.RunLog.Errorf("stream [%s] from [%s] is aborted: %v", in.meta.Id, cm.getPeerId(), err)
+	in.sink.Abort(err)
+	cm.sendStreamResult(id, err)
+}
+
+// cleanStreams aborts the streams being received when the connection is closed or the sender stays idle too long
+func (cm *wsConnectMgr) cleanStreams(all bool) {
+	cm.inStreams.Range(func(key, value interface{}) bool {
+		in, ok := value.(*inStream)
+		if !ok {
+			cm.inStreams.Delete(key)
+			return true
+		}
+		in.lock.Lock()
+		defer in.lock.Unlock()
+		if in.closed || (!all && time.Since(in.lastActive) < streamIdleTimeout) {
+			return true
+		}
+		in.closed = true
+		cm.inStreams.Delete(key)
+		hwlog.RunLog.Warnf("stream [%s] from [%s] is aborted since it is idle or connection is closed",
+			in.meta.Id, cm.getPeerId())
+		in.sink.Abort(errors.New("stream is idle or connection is closed"))
+		return true
+	})
+}
@@ -0,0 +1,190 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package websocketmgr
+
+import (
+	"crypto/sha256"
+	"encoding"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"strings"
+
+	"huawei.com/mindx/common/fileutils"
+	"huawei.com/mindx/common/hwlog"
+)
+
+const (
+	sha256HexLen     = 64
+	partFileSuffix   = ".part"
+	partStateSuffix  = ".part.state"
+	maxPartStateSize = 1024
+)
+
+// FileStreamSink saves the stream content into a file. The content is written into a ".part" file first which is
+// kept with the hash state of its content when the stream is broken, so that the next stream of the same content
+// resumes from its size without hashing the received content again
+type FileStreamSink struct {
+	path   string
+	meta   StreamMeta
+	file   *os.File
+	hash   hash.Hash
+	offset int64
+}
+
+// partState identifies the content which the part file belongs to, and the sha256 state of the part file
+type partState struct {
+	Sha256    string `json:"sha256"`
+	Size      int64  `json:"size"`
+	Offset    int64  `json:"offset"`
+	HashState []byte `json:"hashState"`
+}
+
+// NewFileStreamSink create a sink which saves the stream content to path, the sha256 of content is required
+func NewFileStreamSink(path string, meta StreamMeta) (*FileStreamSink, error) {
+	if meta.Sha256 == "" {
+		return nil, errors.New("sha256 of stream content is required")
+	}
+	partPath := path + partFileSuffix
+	if err := fileutils.CreateFile(partPath, fileutils.Mode600); err != nil {
+		return nil, fmt.Errorf("create stream file failed: %v", err)
+	}
+	file, err := fileutils.OpenFile(partPath, os.O_RDWR, fileutils.Mode600)
+	if err != nil {
+		return nil, fmt.Errorf("open stream file failed: %v", err)
+	}
+	sink := &FileStreamSink{path: path, meta: meta, file: file, hash: sha256.New()}
+	sink.restore()
+	if err = file.Truncate(sink.offset); err != nil {
+		closeStreamFile(file)
+		return nil, fmt.Errorf("truncate stream file failed: %v", err)
+	}
+	if _, err = file.Seek(sink.offset, io.SeekStart); err != nil {
+		closeStreamFile(file)
+		return nil, fmt.Errorf("seek stream file failed: %v", err)
+	}
+	return sink, nil
+}
+
+// restore resumes from the saved state if the part file belongs to the same content, the content written after the
+// state is saved is dropped. Otherwise, it starts over
+func (s *FileStreamSink) restore() {
+	data, err := fileutils.ReadLimitBytes(s.path+partStateSuffix, maxPartStateSize)
+	if err != nil {
+		return
+	}
+	var state partState
+	if err = json.Unmarshal(data, &state); err != nil {
+		hwlog.RunLog.Warnf("stream part state is invalid, start over: %v", err)
+		return
+	}
+	if !strings.EqualFold(state.Sha256, s.meta.Sha256) || state.Size != s.meta.Size {
+		hwlog.RunLog.Info("stream part file belongs to another content, start over")
+		return
+	}
+	info, err := s.file.Stat()
+	if err != nil || state.Offset < 0 || state.Offset > info.Size() || state.Offset > s.meta.Size {
+		hwlog.RunLog.Warn("stream part file is shorter than its state, start over")
+		return
+	}
+	unmarshaler, ok := s.hash.(encoding.BinaryUnmarshaler)
+	if !ok {
+		return
+	}
+	if err = unmarshaler.UnmarshalBinary(state.HashState); err != nil {
+		hwlog.RunLog.Warnf("restore hash state of stream part file failed, start over: %v", err)
+		s.hash.Reset()
+		return
+	}
+	s.offset = state.Offset
+}
+
+func (s *FileStreamSink) saveState() error {
+	marshaler, ok := s.hash.(encoding.BinaryMarshaler)
+	if !ok {
+		return errors.New("hash state is not supported")
+	}
+	hashState, err := marshaler.MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("marshal hash state failed: %v", err)
+	}
+	data, err := json.Marshal(partState{Sha256: s.meta.Sha256, Size: s.meta.Size, Offset: s.offset,
+		HashState: hashState})
+	if err != nil {
+		return fmt.Errorf("marshal stream part state failed: %v", err)
+	}
+	return fileutils.WriteData(s.path+partStateSuffix, data)
+}
+
+func (s *FileStreamSink) removePart() {
+	for _, path := range []string{s.path + partFileSuffix, s.path + partStateSuffix} {
+		if err := fileutils.DeleteFile(path); err != nil {
+			hwlog.RunLog.Errorf("remove stream part file failed: %v", err)
+		}
+	}
+}
+
+func closeStreamFile(file *os.File) {
+	if err := file.Close(); err != nil {
+		hwlog.RunLog.Errorf("close stream file failed: %v", err)
+	}
+}
+
+// Offset returns the size of received content
+func (s *FileStreamSink) Offset() int64 {
+	return s.offset
+}
+
+// Write appends the content to the part file, the sha256 is calculated along with writing
+func (s *FileStreamSink) Write(p []byte) (int, error) {
+	n, err := s.file.Write(p)
+	s.hash.Write(p[:n])
+	s.offset += int64(n)
+	return n, err
+}
+
+// Complete checks the sha256 of the whole content then moves the part file to the target path
+func (s *FileStreamSink) Complete() error {
+	if err := s.file.Sync(); err != nil {
+		closeStreamFile(s.file)
+		return fmt.Errorf("sync stream file failed: %v", err)
+	}
+	closeStreamFile(s.file)
+	if !strings.EqualFold(hex.EncodeToString(s.hash.Sum(nil)), s.meta.Sha256) {
+		// the part file is broken, remove it to avoid resuming from it
+		s.removePart()
+		return errors.New("sha256 of stream content is mismatched")
+	}
+	if err := fileutils.RenameFile(s.path+partFileSuffix, s.path); err != nil {
+		return fmt.Errorf("rename stream file failed: %v", err)
+	}
+	if err := fileutils.DeleteFile(s.path + partStateSuffix); err != nil {
+		hwlog.RunLog.Warnf("remove stream part state failed: %v", err)
+	}
+	return nil
+}
+
+// Abort closes the part file and keeps it with its hash state for resuming
+func (s *FileStreamSink) Abort(err error) {
+	hwlog.RunLog.Warnf("stream [%s] to file is aborted at offset %d: %v", s.meta.Id, s.offset, err)
+	defer closeStreamFile(s.file)
+	if syncErr := s.file.Sync(); syncErr != nil {
+		hwlog.RunLog.Errorf("sync stream file failed, it is not resumable: %v", syncErr)
+		return
+	}
+	if saveErr := s.saveState(); saveErr != nil {
+		hwlog.RunLog.Errorf("save stream part state failed, it is not resumable: %v", saveErr)
+	}
+}
@@ -0,0 +1,280 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package websocketmgr
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/smartystreets/goconvey/convey"
+
+	"huawei.com/mindx/common/fileutils"
+)
+
+const (
+	testStreamChunkSize = 1024
+	testStreamSize      = 10*testStreamChunkSize + 100
+)
+
+// loopbackPeers connects two connection managers in process, frames sent by one are handled by the other directly
+type loopbackPeers struct {
+	sender   *wsConnectMgr
+	receiver *wsConnectMgr
+	// dropData returns true if the data frame at offset should be dropped
+	dropData func(offset int64) bool
+}
+
+func newLoopbackPeers(handler StreamHandler) *loopbackPeers {
+	newConnMgr := func(name string, cfg *ProxyConfig) *wsConnectMgr {
+		cm := &wsConnectMgr{peerInfo: WebsocketPeerInfo{Sn: name}, currentProxy: &WsServerProxy{ProxyCfg: cfg}}
+		cm.ctx, cm.cancel = context.WithCancel(context.Background())
+		return cm
+	}
+	senderCfg := &ProxyConfig{}
+	if err := senderCfg.SetStreamCfg(testStreamChunkSize, 4*testStreamChunkSize); err != nil {
+		panic(err)
+	}
+	receiverCfg := &ProxyConfig{}
+	receiverCfg.RegStreamHandler(handler)
+	return &loopbackPeers{sender: newConnMgr("sender", senderCfg), receiver: newConnMgr("receiver", receiverCfg)}
+}
+
+func (lp *loopbackPeers) patch() *gomonkey.Patches {
+	return gomonkey.ApplyPrivateMethod(reflect.TypeOf(&wsConnectMgr{}), "send",
+		func(cm *wsConnectMgr, _ int, data []byte) error {
+			peer := lp.receiver
+			if cm == lp.receiver {
+				peer = lp.sender
+			}
+			frame, err := decodeStreamFrame(data)
+			if err != nil {
+				return err
+			}
+			if frame.frameType == frameData && lp.dropData != nil && lp.dropData(frame.offset) {
+				return nil
+			}
+			peer.handleStreamFrame(data, nil)
+			return nil
+		})
+}
+
+func prepareStreamContent() ([]byte, StreamMeta) {
+	content := make([]byte, testStreamSize)
+	if _, err := rand.Read(content); err != nil {
+		panic(err)
+	}
+	sum := sha256.Sum256(content)
+	return content, StreamMeta{Name: "test.bin", Size: int64(len(content)), Sha256: hex.EncodeToString(sum[:])}
+}
+
+func fileSinkHandler(path string) StreamHandler {
+	return func(_ WebsocketPeerInfo, meta StreamMeta) (StreamSink, error) {
+		return NewFileStreamSink(path, meta)
+	}
+}
+
+func TestStreamFrame(t *testing.T) {
+	convey.Convey("encode and decode stream frame", t, func() {
+		frame := streamFrame{frameType: frameData, offset: testStreamChunkSize, payload: []byte("chunk")}
+		data := encodeStreamFrame(frame)
+		convey.So(isStreamFrame(data), convey.ShouldBeTrue)
+		decoded, err := decodeStreamFrame(data)
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(decoded, convey.ShouldResemble, frame)
+
+		data[len(data)-1] ^= 0xFF
+		_, err = decodeStreamFrame(data)
+		convey.So(err, convey.ShouldNotBeNil)
+		convey.So(isStreamFrame([]byte(`{"header":{}}`)), convey.ShouldBeFalse)
+	})
+}
+
+func TestSendStream(t *testing.T) {
+	convey.Convey("stream is received completely", t, testSendStream)
+	convey.Convey("stream resumes from the received offset", t, testSendStreamResume)
+	convey.Convey("dropped chunk is resent after rewind", t, testSendStreamRewind)
+	convey.Convey("stream should be failed, sha256 mismatched", t, testSendStreamErrSha256)
+	convey.Convey("stream should be failed, refused by peer", t, testSendStreamErrRefused)
+	convey.Convey("stream should be failed, too many streams from peer", t, testSendStreamErrTooMany)
+}
+
+func testSendStream() {
+	dir := newStreamTempDir()
+	defer removeStreamTempDir(dir)
+	path := filepath.Join(dir, "stream.bin")
+	content, meta := prepareStreamContent()
+	lp := newLoopbackPeers(fileSinkHandler(path))
+	patches := lp.patch()
+	defer patches.Reset()
+
+	convey.So(lp.sender.sendStream(meta, bytes.NewReader(content)), convey.ShouldBeNil)
+	received, err := os.ReadFile(path)
+	convey.So(err, convey.ShouldBeNil)
+	convey.So(bytes.Equal(received, content), convey.ShouldBeTrue)
+}
+
+// abortAfter leaves the part file of the content received before offset as a broken stream does
+func abortAfter(path string, meta StreamMeta, content []byte, offset int) {
+	sink, err := NewFileStreamSink(path, meta)
+	convey.So(err, convey.ShouldBeNil)
+	_, err = sink.Write(content[:offset])
+	convey.So(err, convey.ShouldBeNil)
+	sink.Abort(errors.New("connection is closed"))
+}
+
+func testSendStreamResume() {
+	dir := newStreamTempDir()
+	defer removeStreamTempDir(dir)
+	path := filepath.Join(dir, "stream.bin")
+	content, meta := prepareStreamContent()
+	const received = 3*testStreamChunkSize + 10
+	abortAfter(path, meta, content, received)
+	lp := newLoopbackPeers(fileSinkHandler(path))
+	var firstOffset int64 = -1
+	lp.dropData = func(offset int64) bool {
+		if firstOffset < 0 {
+			firstOffset = offset
+		}
+		return false
+	}
+	patches := lp.patch()
+	defer patches.Reset()
+
+	convey.So(lp.sender.sendStream(meta, bytes.NewReader(content)), convey.ShouldBeNil)
+	convey.So(firstOffset, convey.ShouldEqual, received)
+	data, err := os.ReadFile(path)
+	convey.So(err, convey.ShouldBeNil)
+	convey.So(bytes.Equal(data, content), convey.ShouldBeTrue)
+	_, err = os.Stat(path + partStateSuffix)
+	convey.So(os.IsNotExist(err), convey.ShouldBeTrue)
+
+	convey.Convey("part file of another content is not resumed", func() {
+		otherContent, otherMeta := prepareStreamContent()
+		abortAfter(path, otherMeta, otherContent, received)
+		firstOffset = -1
+		convey.So(lp.sender.sendStream(meta, bytes.NewReader(content)), convey.ShouldBeNil)
+		convey.So(firstOffset, convey.ShouldEqual, 0)
+		data, err = os.ReadFile(path)
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(bytes.Equal(data, content), convey.ShouldBeTrue)
+	})
+
+	convey.Convey("part file without state is not resumed", func() {
+		convey.So(os.WriteFile(path+partFileSuffix, content[:received], fileutils.Mode600), convey.ShouldBeNil)
+		firstOffset = -1
+		convey.So(lp.sender.sendStream(meta, bytes.NewReader(content)), convey.ShouldBeNil)
+		convey.So(firstOffset, convey.ShouldEqual, 0)
+	})
+}
+
+func testSendStreamRewind() {
+	dir := newStreamTempDir()
+	defer removeStreamTempDir(dir)
+	path := filepath.Join(dir, "stream.bin")
+	content, meta := prepareStreamContent()
+	lp := newLoopbackPeers(fileSinkHandler(path))
+	dropped := false
+	lp.dropData = func(offset int64) bool {
+		if offset == 2*testStreamChunkSize && !dropped {
+			dropped = true
+			return true
+		}
+		return false
+	}
+	patches := lp.patch()
+	defer patches.Reset()
+
+	convey.So(lp.sender.sendStream(meta, bytes.NewReader(content)), convey.ShouldBeNil)
+	convey.So(dropped, convey.ShouldBeTrue)
+	data, err := os.ReadFile(path)
+	convey.So(err, convey.ShouldBeNil)
+	convey.So(bytes.Equal(data, content), convey.ShouldBeTrue)
+}
+
+func testSendStreamErrSha256() {
+	dir := newStreamTempDir()
+	defer removeStreamTempDir(dir)
+	path := filepath.Join(dir, "stream.bin")
+	content, meta := prepareStreamContent()
+	meta.Sha256 = hex.EncodeToString(make([]byte, sha256.Size))
+	lp := newLoopbackPeers(fileSinkHandler(path))
+	patches := lp.patch()
+	defer patches.Reset()
+
+	convey.So(lp.sender.sendStream(meta, bytes.NewReader(content)), convey.ShouldNotBeNil)
+	_, err := os.Stat(path)
+	convey.So(os.IsNotExist(err), convey.ShouldBeTrue)
+	_, err = os.Stat(path + partFileSuffix)
+	convey.So(os.IsNotExist(err), convey.ShouldBeTrue)
+}
+
+func testSendStreamErrRefused() {
+	content, meta := prepareStreamContent()
+	refusedHandler := func(WebsocketPeerInfo, StreamMeta) (StreamSink, error) {
+		return nil, errors.New("no space left")
+	}
+	for _, handler := range []StreamHandler{refusedHandler, nil} {
+		lp := newLoopbackPeers(handler)
+		patches := lp.patch()
+		err := lp.sender.sendStream(meta, bytes.NewReader(content))
+		patches.Reset()
+		convey.So(err, convey.ShouldNotBeNil)
+	}
+
+	convey.Convey("sha256 is required", func() {
+		meta.Sha256 = ""
+		lp := newLoopbackPeers(refusedHandler)
+		patches := lp.patch()
+		defer patches.Reset()
+		convey.So(lp.sender.sendStream(meta, bytes.NewReader(content)), convey.ShouldResemble,
+			errors.New("stream sha256 is invalid"))
+		_, err := NewFileStreamSink(filepath.Join(os.TempDir(), "stream.bin"), meta)
+		convey.So(err, convey.ShouldNotBeNil)
+	})
+}
+
+func testSendStreamErrTooMany() {
+	content, meta := prepareStreamContent()
+	lp := newLoopbackPeers(func(WebsocketPeerInfo, StreamMeta) (StreamSink, error) {
+		return nil, errors.New("stream should be refused before creating sink")
+	})
+	for i := 0; i < maxInStreams; i++ {
+		lp.receiver.inStreams.Store([streamIdLen]byte{byte(i)}, &inStream{})
+	}
+	patches := lp.patch()
+	defer patches.Reset()
+
+	err := lp.sender.sendStream(meta, bytes.NewReader(content))
+	convey.So(err, convey.ShouldResemble, errors.New("stream is refused by peer: too many streams, try again later"))
+}
+
+func newStreamTempDir() string {
+	dir, err := os.MkdirTemp("", "ws_stream")
+	if err != nil {
+		panic(err)
+	}
+	return dir
+}
+
+func removeStreamTempDir(dir string) {
+	if err := os.RemoveAll(dir); err != nil {
+		panic(err)
+	}
+}
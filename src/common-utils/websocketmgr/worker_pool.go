@@ -0,0 +1,188 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package websocketmgr
+
+import (
+	"encoding/json"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"huawei.com/mindx/common/hwlog"
+	"huawei.com/mindx/common/modulemgr/model"
+)
+
+// ThrottledContent is the content of the reply to a message which is rejected by limiter or full worker queue
+const ThrottledContent = "throttled"
+
+// ErrThrottled is returned by Send when the peer keeps throttling the requests longer than the sender can wait
+var ErrThrottled = errors.New("message is throttled by peer, please try again later")
+
+type workerPoolCfg struct {
+	workerNum int
+	queueSize int
+}
+
+// msgBrief the fields needed to schedule a message without parsing the whole message
+type msgBrief struct {
+	Header struct {
+		ParentId string `json:"parentId"`
+		IsSync   bool   `json:"isSync"`
+	} `json:"header"`
+	Content json.RawMessage `json:"content"`
+}
+
+// workerPool async requests are handled by several workers concurrently, sync requests are handled by one worker
+// in the order they are received. Responses are not queued in the workers, see dispatchMsg
+type workerPool struct {
+	asyncQueue   chan []byte
+	orderedQueue chan []byte
+}
+
+func (cm *wsConnectMgr) getWorkerPoolCfg() workerPoolCfg {
+	if cfg := cm.currentProxy.GetProxyConfig().workerPoolCfg; cfg != nil {
+		return *cfg
+	}
+	return workerPoolCfg{workerNum: defaultWorkerNum, queueSize: defaultWorkerQueueSize}
+}
+
+func (cm *wsConnectMgr) startWorkers() {
+	cfg := cm.getWorkerPoolCfg()
+	cm.workers = &workerPool{
+		asyncQueue:   make(chan []byte, cfg.queueSize),
+		orderedQueue: make(chan []byte, cfg.queueSize),
+	}
+	for i := 0; i < cfg.workerNum; i++ {
+		go cm.work(cm.workers.asyncQueue)
+	}
+	go cm.work(cm.workers.orderedQueue)
+}
+
+func (cm *wsConnectMgr) work(queue chan []byte) {
+	for {
+		select {
+		case <-cm.ctx.Done():
+			return
+		case msg := <-queue:
+			cm.handleMsg(msg)
+		}
+	}
+}
+
+func (cm *wsConnectMgr) handleMsg(msg []byte) {
+	handler := cm.currentProxy.GetProxyConfig().handlerMgr
+	if handler == nil {
+		hwlog.RunLog.Errorf("message handler is not initialized")
+		return
+	}
+	peerInfo := model.MsgPeerInfo{Ip: cm.peerInfo.Ip, Sn: cm.peerInfo.Sn}
+	cm.sendMsg(handler.HandleMsg(msg, peerInfo))
+}
+
+// dispatchMsg delivers the responses at once, so that the callers waiting for them are not delayed by the requests
+// queued in the workers. Requests are put into worker queue, and rejected if denied by limiter or queue is full
+func (cm *wsConnectMgr) dispatchMsg(msg []byte, limitErr error) {
+	var brief msgBrief
+	if err := json.Unmarshal(msg, &brief); err != nil {
+		hwlog.RunLog.Errorf("[%s] unmarshal message failed: %v", cm.getPeerId(), err)
+		return
+	}
+	if brief.Header.ParentId != "" {
+		if isThrottledContent(brief.Content) {
+			cm.onThrottled()
+			// only the caller of sync request is waiting for the reply
+			if !brief.Header.IsSync {
+				return
+			}
+		}
+		cm.handleMsg(msg)
+		return
+	}
+	if limitErr != nil {
+		cm.rejectMsg(msg, limitErr)
+		return
+	}
+	queue := cm.workers.asyncQueue
+	if brief.Header.IsSync {
+		queue = cm.workers.orderedQueue
+	}
+	select {
+	case queue <- msg:
+	default:
+		cm.rejectMsg(msg, errors.New("message is denied since worker queue is full"))
+	}
+}
+
+func isThrottledContent(content json.RawMessage) bool {
+	var str string
+	if err := json.Unmarshal(content, &str); err != nil {
+		return false
+	}
+	return str == ThrottledContent
+}
+
+// rejectMsg replies throttled to the request so that the peer can back off
+func (cm *wsConnectMgr) rejectMsg(msg []byte, reason error) {
+	hwlog.RunLog.Warnf("[%s] %v", cm.getPeerId(), reason)
+	var req model.Message
+	if err := json.Unmarshal(msg, &req); err != nil {
+		hwlog.RunLog.Errorf("[%s] unmarshal rejected message failed: %v", cm.getPeerId(), err)
+		return
+	}
+	resp, err := req.NewResponse()
+	if err != nil {
+		hwlog.RunLog.Errorf("create throttled reply failed: %v", err)
+		return
+	}
+	if err = resp.FillContent(ThrottledContent); err != nil {
+		hwlog.RunLog.Errorf("fill throttled reply failed: %v", err)
+		return
+	}
+	data, err := json.Marshal(resp)
+	if err != nil {
+		hwlog.RunLog.Errorf("marshal throttled reply failed: %v", err)
+		return
+	}
+	if err = cm.send(websocket.TextMessage, data); err != nil {
+		hwlog.RunLog.Errorf("send throttled reply to [%s] failed: %v", cm.getPeerId(), err)
+	}
+}
+
+// onThrottled makes the sender back off for a while
+func (cm *wsConnectMgr) onThrottled() {
+	hwlog.RunLog.Warnf("message is throttled by [%s], back off for %v", cm.getPeerId(), throttleBackoffTime)
+	atomic.StoreInt64(&cm.throttledUntil, time.Now().Add(throttleBackoffTime).UnixNano())
+}
+
+// waitThrottled blocks the request until the sender stops backing off. The wait is bounded, so ErrThrottled is
+// returned at once if the peer keeps throttling longer than maxThrottleWaitTime
+func (cm *wsConnectMgr) waitThrottled() error {
+	deadline := time.Now().Add(maxThrottleWaitTime)
+	for {
+		until := time.Unix(0, atomic.LoadInt64(&cm.throttledUntil))
+		wait := time.Until(until)
+		if wait <= 0 {
+			return nil
+		}
+		if until.After(deadline) {
+			return ErrThrottled
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-cm.ctx.Done():
+			timer.Stop()
+			return errors.New("connection is closed while waiting for the peer to stop throttling")
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,263 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package websocketmgr
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/smartystreets/goconvey/convey"
+
+	"huawei.com/mindx/common/modulemgr"
+	"huawei.com/mindx/common/modulemgr/model"
+)
+
+const testWorkerWaitTime = 5 * time.Second
+
+// recordHandler records the resources of handled messages in order
+type recordHandler struct {
+	lock      sync.Mutex
+	resources []string
+	done      chan struct{}
+	expected  int
+}
+
+func (h *recordHandler) Register(modulemgr.MessageHandlerIntf) {}
+
+func (h *recordHandler) HandleMsg(data []byte, _ model.MsgPeerInfo) []byte {
+	var msg model.Message
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return nil
+	}
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.resources = append(h.resources, msg.GetResource())
+	if len(h.resources) == h.expected {
+		close(h.done)
+	}
+	return nil
+}
+
+func newWorkerConnMgrForUT(cfg *ProxyConfig) *wsConnectMgr {
+	cm := &wsConnectMgr{peerInfo: WebsocketPeerInfo{Sn: "peer"}, currentProxy: &WsServerProxy{ProxyCfg: cfg}}
+	cm.ctx, cm.cancel = context.WithCancel(context.Background())
+	return cm
+}
+
+func newWsMsgForUT(resource string, isSync bool) []byte {
+	msg, err := model.NewMessage()
+	if err != nil {
+		panic(err)
+	}
+	msg.SetRouter("src", "dst", "POST", resource)
+	msg.SetIsSync(isSync)
+	if err = msg.FillContent("payload"); err != nil {
+		panic(err)
+	}
+	data, err := json.Marshal(msg)
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
+
+func newWsRespForUT(req []byte, content string) []byte {
+	var msg model.Message
+	if err := json.Unmarshal(req, &msg); err != nil {
+		panic(err)
+	}
+	resp, err := msg.NewResponse()
+	if err != nil {
+		panic(err)
+	}
+	if err = resp.FillContent(content); err != nil {
+		panic(err)
+	}
+	data, err := json.Marshal(resp)
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
+
+// patchSend records the messages sent through the connection
+func patchSend(sent *[][]byte) *gomonkey.Patches {
+	return gomonkey.ApplyPrivateMethod(reflect.TypeOf(&wsConnectMgr{}), "send",
+		func(_ *wsConnectMgr, _ int, data []byte) error {
+			*sent = append(*sent, data)
+			return nil
+		})
+}
+
+func TestSetWorkerPoolCfg(t *testing.T) {
+	convey.Convey("set worker pool config", t, func() {
+		cfg := &ProxyConfig{}
+		convey.So(cfg.SetWorkerPoolCfg(0, defaultWorkerQueueSize), convey.ShouldNotBeNil)
+		convey.So(cfg.SetWorkerPoolCfg(defaultWorkerNum, maxWorkerQueueSize+1), convey.ShouldNotBeNil)
+		convey.So(cfg.SetWorkerPoolCfg(1, 1), convey.ShouldBeNil)
+		convey.So(newWorkerConnMgrForUT(cfg).getWorkerPoolCfg(), convey.ShouldResemble,
+			workerPoolCfg{workerNum: 1, queueSize: 1})
+	})
+}
+
+func TestDispatchMsg(t *testing.T) {
+	convey.Convey("request is replied throttled when queue is full", t, testDispatchMsgQueueFull)
+	convey.Convey("request is replied throttled when denied by limiter", t, testDispatchMsgLimited)
+	convey.Convey("response is delivered at once when queue is full", t, testDispatchRespQueueFull)
+	convey.Convey("sync requests are put into the ordered queue", t, testDispatchSyncMsg)
+}
+
+func newFullWorkerPool() *workerPool {
+	return &workerPool{asyncQueue: make(chan []byte), orderedQueue: make(chan []byte)}
+}
+
+func checkThrottledReply(req, reply []byte) {
+	var reqMsg, replyMsg model.Message
+	convey.So(json.Unmarshal(req, &reqMsg), convey.ShouldBeNil)
+	convey.So(json.Unmarshal(reply, &replyMsg), convey.ShouldBeNil)
+	convey.So(replyMsg.GetParentId(), convey.ShouldEqual, reqMsg.GetId())
+	convey.So(replyMsg.GetIsSync(), convey.ShouldEqual, reqMsg.GetIsSync())
+	var content string
+	convey.So(replyMsg.ParseContent(&content), convey.ShouldBeNil)
+	convey.So(content, convey.ShouldEqual, ThrottledContent)
+}
+
+func testDispatchMsgQueueFull() {
+	var sent [][]byte
+	patches := patchSend(&sent)
+	defer patches.Reset()
+	cm := newWorkerConnMgrForUT(&ProxyConfig{})
+	cm.workers = newFullWorkerPool()
+
+	req := newWsMsgForUT("res", false)
+	cm.dispatchMsg(req, nil)
+	convey.So(len(sent), convey.ShouldEqual, 1)
+	checkThrottledReply(req, sent[0])
+}
+
+func testDispatchMsgLimited() {
+	var sent [][]byte
+	patches := patchSend(&sent)
+	defer patches.Reset()
+	cm := newWorkerConnMgrForUT(&ProxyConfig{})
+	cm.workers = &workerPool{asyncQueue: make(chan []byte, 1), orderedQueue: make(chan []byte, 1)}
+
+	req := newWsMsgForUT("res", true)
+	cm.dispatchMsg(req, errors.New("rps limited"))
+	convey.So(len(sent), convey.ShouldEqual, 1)
+	checkThrottledReply(req, sent[0])
+	convey.So(len(cm.workers.orderedQueue), convey.ShouldEqual, 0)
+}
+
+func testDispatchRespQueueFull() {
+	var sent [][]byte
+	patches := patchSend(&sent)
+	defer patches.Reset()
+	handler := &recordHandler{done: make(chan struct{}), expected: 1}
+	cm := newWorkerConnMgrForUT(&ProxyConfig{handlerMgr: handler})
+	cm.workers = newFullWorkerPool()
+
+	cm.dispatchMsg(newWsRespForUT(newWsMsgForUT("res", true), "OK"), errors.New("rps limited"))
+	convey.So(sent, convey.ShouldBeEmpty)
+	convey.So(handler.resources, convey.ShouldResemble, []string{"res"})
+}
+
+func testDispatchSyncMsg() {
+	handler := &recordHandler{done: make(chan struct{}), expected: 1}
+	cm := newWorkerConnMgrForUT(&ProxyConfig{handlerMgr: handler})
+	cm.workers = &workerPool{asyncQueue: make(chan []byte, 1), orderedQueue: make(chan []byte, 2)}
+	cm.dispatchMsg(newWsMsgForUT("sync", true), nil)
+	cm.dispatchMsg(newWsRespForUT(newWsMsgForUT("async", false), "OK"), nil)
+	convey.So(len(cm.workers.orderedQueue), convey.ShouldEqual, 1)
+	convey.So(len(cm.workers.asyncQueue), convey.ShouldEqual, 0)
+	convey.So(handler.resources, convey.ShouldResemble, []string{"async"})
+}
+
+func TestThrottledBackoff(t *testing.T) {
+	convey.Convey("throttled replies make the sender back off", t, testThrottledReply)
+	convey.Convey("request waits until the sender stops backing off", t, testSendWaitThrottled)
+}
+
+func testThrottledReply() {
+	handler := &recordHandler{done: make(chan struct{}), expected: 1}
+	cm := newWorkerConnMgrForUT(&ProxyConfig{handlerMgr: handler})
+	cm.workers = newFullWorkerPool()
+
+	// the throttled reply of async request is not passed to handler
+	cm.dispatchMsg(newWsRespForUT(newWsMsgForUT("async", false), ThrottledContent), nil)
+	convey.So(atomic.LoadInt64(&cm.throttledUntil), convey.ShouldBeGreaterThan, time.Now().UnixNano())
+	convey.So(handler.resources, convey.ShouldBeEmpty)
+	// the throttled reply of sync request is passed to the waiting caller at once
+	cm.dispatchMsg(newWsRespForUT(newWsMsgForUT("sync", true), ThrottledContent), nil)
+	convey.So(handler.resources, convey.ShouldResemble, []string{"sync"})
+}
+
+func testSendWaitThrottled() {
+	const backoff = 200 * time.Millisecond
+	cm := newWorkerConnMgrForUT(&ProxyConfig{})
+	defer cm.cancel()
+	proxy := &WsClientProxy{connMgr: cm}
+	var sent [][]byte
+	patches := patchSend(&sent)
+	defer patches.Reset()
+
+	req, err := model.NewMessage()
+	convey.So(err, convey.ShouldBeNil)
+	resp, err := req.NewResponse()
+	convey.So(err, convey.ShouldBeNil)
+	cm.throttledUntil = time.Now().Add(backoff).UnixNano()
+	start := time.Now()
+	convey.So(proxy.Send(resp), convey.ShouldBeNil)
+	convey.So(time.Since(start), convey.ShouldBeLessThan, backoff)
+	convey.So(proxy.Send(req), convey.ShouldBeNil)
+	convey.So(time.Since(start), convey.ShouldBeGreaterThanOrEqualTo, backoff)
+	convey.So(len(sent), convey.ShouldEqual, 2)
+
+	cm.throttledUntil = time.Now().Add(maxThrottleWaitTime + time.Second).UnixNano()
+	convey.So(proxy.Send(req), convey.ShouldEqual, ErrThrottled)
+	cm.throttledUntil = time.Now().Add(backoff).UnixNano()
+	cm.cancel()
+	convey.So(proxy.Send(req), convey.ShouldNotBeNil)
+	convey.So(len(sent), convey.ShouldEqual, 2)
+}
+
+func TestWorkerPool(t *testing.T) {
+	convey.Convey("sync messages are handled in the order they are received", t, func() {
+		const msgNum = 50
+		handler := &recordHandler{done: make(chan struct{}), expected: msgNum}
+		cfg := &ProxyConfig{handlerMgr: handler}
+		convey.So(cfg.SetWorkerPoolCfg(defaultWorkerNum, msgNum), convey.ShouldBeNil)
+		cm := newWorkerConnMgrForUT(cfg)
+		defer cm.cancel()
+		cm.startWorkers()
+
+		expected := make([]string, 0, msgNum)
+		for i := 0; i < msgNum; i++ {
+			resource := string(rune('a' + i%26))
+			expected = append(expected, resource)
+			cm.dispatchMsg(newWsMsgForUT(resource, true), nil)
+		}
+		select {
+		case <-handler.done:
+		case <-time.After(testWorkerWaitTime):
+		}
+		handler.lock.Lock()
+		defer handler.lock.Unlock()
+		convey.So(handler.resources, convey.ShouldResemble, expected)
+	})
+}
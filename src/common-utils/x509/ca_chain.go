@@ -36,6 +36,9 @@ type CaChainCheckOptions struct {
 	// AllowMiddleStrengthRsaPublicKey a 2048-bit rsa public key is considered as middle strength.
 	// Turn on this option only when you import third-party certificates.
 	AllowMiddleStrengthRsaPublicKey bool
+	// Policy is applied on top of the fixed signature algorithm and public key checks when it is set,
+	// all violations of the policy are reported together.
+	Policy *CertPolicy
 }
 
 // NewCaChainMgr is used to create and init a CaChainMgr
@@ -222,6 +225,10 @@ func (ccm *CaChainMgr) checkSingleCerts(cert *x509.Certificate, chainOpts CaChai
 		return err
 	}
 
+	if chainOpts.Policy != nil {
+		return chainOpts.Policy.CheckCert(cert).Err()
+	}
+
 	return nil
 }
 
@@ -0,0 +1,97 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package x509
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/pem"
+	"errors"
+	"fmt"
+)
+
+// bundle formats of ca certs used to interchange with other tools
+const (
+	BundleFormatPem    = "pem"
+	BundleFormatPkcs12 = "pkcs12"
+	BundleFormatJks    = "jks"
+
+	bundleAliasPrefix = "mef-ca-"
+	pemBlockCert      = "CERTIFICATE"
+)
+
+// BundleFormats the supported bundle formats
+var BundleFormats = []string{BundleFormatPem, BundleFormatPkcs12, BundleFormatJks}
+
+// DetectBundleFormat detects the bundle format by content, the content is taken as pkcs12 if it is not pem or jks
+func DetectBundleFormat(content []byte) string {
+	if bytes.Contains(content, []byte(certBegin)) {
+		return BundleFormatPem
+	}
+	if len(content) >= binary.Size(jksMagic) && binary.BigEndian.Uint32(content) == jksMagic {
+		return BundleFormatJks
+	}
+	return BundleFormatPkcs12
+}
+
+// NewCaChainMgrFromBundle is used to create and init a CaChainMgr from ca bundle,
+// password is used to verify the integrity of pkcs12 and jks, and is ignored by pem
+func NewCaChainMgrFromBundle(content []byte, format string, password []byte) (*CaChainMgr, error) {
+	var derCerts [][]byte
+	var err error
+	switch format {
+	case BundleFormatPem:
+		return NewCaChainMgr(content)
+	case BundleFormatPkcs12:
+		derCerts, err = decodePkcs12Certs(content, password)
+	case BundleFormatJks:
+		derCerts, err = decodeJksCerts(content, password)
+	default:
+		return nil, fmt.Errorf("bundle format [%s] is not supported", format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("decode %s bundle failed: %v", format, err)
+	}
+	if len(derCerts) == 0 {
+		return nil, fmt.Errorf("no cert is found in %s bundle", format)
+	}
+	return NewCaChainMgrFromDer(bytes.Join(derCerts, nil))
+}
+
+// ExportPem returns the certs in pem format
+func (ccm *CaChainMgr) ExportPem() []byte {
+	var buf bytes.Buffer
+	for _, cert := range ccm.certs {
+		buf.Write(pem.EncodeToMemory(&pem.Block{Type: pemBlockCert, Bytes: cert.Raw}))
+	}
+	return buf.Bytes()
+}
+
+// ExportBundle returns the certs in the bundle format, pkcs12 and jks are protected by password
+func (ccm *CaChainMgr) ExportBundle(format string, password []byte) ([]byte, error) {
+	if len(ccm.certs) == 0 {
+		return nil, errors.New("no cert to export")
+	}
+	aliases := make([]string, len(ccm.certs))
+	for idx := range ccm.certs {
+		aliases[idx] = fmt.Sprintf("%s%d", bundleAliasPrefix, idx)
+	}
+	switch format {
+	case BundleFormatPem:
+		return ccm.ExportPem(), nil
+	case BundleFormatPkcs12:
+		return encodePkcs12Certs(ccm.certs, aliases, password)
+	case BundleFormatJks:
+		return encodeJksCerts(ccm.certs, aliases, password)
+	default:
+		return nil, fmt.Errorf("bundle format [%s] is not supported", format)
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package x509
+
+import (
+	"os"
+	"testing"
+
+	"github.com/smartystreets/goconvey/convey"
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+// the pkcs12 files in testdata are exported by openssl with this password, they contain the client key and cert
+// followed by the ca chain
+const testBundlePassword = "Test@1234"
+
+func TestNewCaChainMgrFromBundle(t *testing.T) {
+	pemContent, err := os.ReadFile("./testdata/ca_chain.crt")
+	if err != nil {
+		panic(err)
+	}
+	pemMgr, err := NewCaChainMgr(pemContent)
+	if err != nil {
+		panic(err)
+	}
+
+	convey.Convey("test for NewCaChainMgrFromBundle", t, func() {
+		convey.Convey("import pkcs12 exported by openssl success", func() {
+			for _, path := range []string{"./testdata/ca_chain.p12", "./testdata/ca_chain_3des.p12"} {
+				content, err := os.ReadFile(path)
+				convey.So(err, convey.ShouldBeNil)
+				convey.So(DetectBundleFormat(content), convey.ShouldEqual, BundleFormatPkcs12)
+				mgr, err := NewCaChainMgrFromBundle(content, BundleFormatPkcs12, []byte(testBundlePassword))
+				convey.So(err, convey.ShouldBeNil)
+				convey.So(mgr.ExportPem(), convey.ShouldResemble, pemMgr.ExportPem())
+				convey.So(mgr.CheckCertChain(), convey.ShouldBeNil)
+			}
+		})
+
+		convey.Convey("import pkcs12 with wrong password failed", func() {
+			content, err := os.ReadFile("./testdata/ca_chain.p12")
+			convey.So(err, convey.ShouldBeNil)
+			_, err = NewCaChainMgrFromBundle(content, BundleFormatPkcs12, []byte("wrong"))
+			convey.So(err, convey.ShouldNotBeNil)
+		})
+
+		convey.Convey("import unsupported format failed", func() {
+			_, err := NewCaChainMgrFromBundle(pemContent, "der", nil)
+			convey.So(err, convey.ShouldNotBeNil)
+		})
+	})
+}
+
+func TestExportBundle(t *testing.T) {
+	pemContent, err := os.ReadFile("./testdata/ca_chain.crt")
+	if err != nil {
+		panic(err)
+	}
+	pemMgr, err := NewCaChainMgr(pemContent)
+	if err != nil {
+		panic(err)
+	}
+	password := []byte(testBundlePassword)
+
+	convey.Convey("test for ExportBundle", t, func() {
+		for _, format := range BundleFormats {
+			convey.Convey("export and import "+format+" bundle success", func() {
+				content, err := pemMgr.ExportBundle(format, password)
+				convey.So(err, convey.ShouldBeNil)
+				convey.So(DetectBundleFormat(content), convey.ShouldEqual, format)
+				mgr, err := NewCaChainMgrFromBundle(content, format, password)
+				convey.So(err, convey.ShouldBeNil)
+				convey.So(mgr.ExportPem(), convey.ShouldResemble, pemMgr.ExportPem())
+			})
+		}
+
+		convey.Convey("exported pkcs12 certs are trusted by java", func() {
+			content, err := pemMgr.ExportBundle(BundleFormatPkcs12, password)
+			convey.So(err, convey.ShouldBeNil)
+			certs, err := pkcs12.DecodeTrustStore(content, testBundlePassword)
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(len(certs), convey.ShouldEqual, len(pemMgr.certs))
+		})
+
+		convey.Convey("import jks with wrong password failed", func() {
+			content, err := pemMgr.ExportBundle(BundleFormatJks, password)
+			convey.So(err, convey.ShouldBeNil)
+			_, err = NewCaChainMgrFromBundle(content, BundleFormatJks, []byte("wrong"))
+			convey.So(err, convey.ShouldNotBeNil)
+		})
+	})
+}
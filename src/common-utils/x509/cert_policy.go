@@ -0,0 +1,333 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package x509
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"huawei.com/mindx/common/fileutils"
+)
+
+// key algorithms supported by cert policy
+const (
+	KeyAlgorithmRsa     = "RSA"
+	KeyAlgorithmEcdsa   = "ECDSA"
+	KeyAlgorithmEd25519 = "Ed25519"
+)
+
+// rules of cert policy, used to tell which rule a violation breaks
+const (
+	PolicyRuleKeyAlgorithm       = "keyAlgorithm"
+	PolicyRuleKeySize            = "keySize"
+	PolicyRuleSignatureAlgorithm = "signatureAlgorithm"
+	PolicyRuleValidity           = "maxValidity"
+	PolicyRuleKeyUsage           = "keyUsage"
+	PolicyRuleIssuer             = "issuer"
+)
+
+const (
+	maxPolicyKeySize      = 16384
+	maxPolicyValidityDays = 36500
+	maxPolicyListLen      = 100
+	maxPolicyIssuerLen    = 1024
+	maxPolicyFileSize     = 1024 * 1024
+	ed25519KeySize        = 256
+	rsaPssSuffix          = "RSAPSS"
+)
+
+var keyUsageNames = map[string]x509.KeyUsage{
+	"digitalSignature":  x509.KeyUsageDigitalSignature,
+	"contentCommitment": x509.KeyUsageContentCommitment,
+	"keyEncipherment":   x509.KeyUsageKeyEncipherment,
+	"dataEncipherment":  x509.KeyUsageDataEncipherment,
+	"keyAgreement":      x509.KeyUsageKeyAgreement,
+	"certSign":          x509.KeyUsageCertSign,
+	"crlSign":           x509.KeyUsageCRLSign,
+	"encipherOnly":      x509.KeyUsageEncipherOnly,
+	"decipherOnly":      x509.KeyUsageDecipherOnly,
+}
+
+// crl only carries the oid of signature algorithm, the names are the same as x509.SignatureAlgorithm.String()
+var crlSignatureAlgorithms = map[string]string{
+	"1.2.840.113549.1.1.4":  "MD5-RSA",
+	"1.2.840.113549.1.1.5":  "SHA1-RSA",
+	"1.2.840.113549.1.1.11": "SHA256-RSA",
+	"1.2.840.113549.1.1.12": "SHA384-RSA",
+	"1.2.840.113549.1.1.13": "SHA512-RSA",
+	"1.2.840.113549.1.1.10": rsaPssSuffix,
+	"1.2.840.10045.4.1":     "ECDSA-SHA1",
+	"1.2.840.10045.4.3.2":   "ECDSA-SHA256",
+	"1.2.840.10045.4.3.3":   "ECDSA-SHA384",
+	"1.2.840.10045.4.3.4":   "ECDSA-SHA512",
+	"1.3.101.112":           "Ed25519",
+}
+
+// KeyAlgorithmPolicy the allowed public key algorithm and its minimum size in bits
+type KeyAlgorithmPolicy struct {
+	Algorithm string `json:"algorithm"`
+	MinSize   int    `json:"minSize"`
+}
+
+// CertPolicy the configurable rules that imported third-party certs must meet,
+// empty SignatureAlgorithms or AllowedIssuers and zero MaxValidityDays mean no limitation
+type CertPolicy struct {
+	KeyAlgorithms       []KeyAlgorithmPolicy `json:"keyAlgorithms"`
+	SignatureAlgorithms []string             `json:"signatureAlgorithms"`
+	MaxValidityDays     int                  `json:"maxValidityDays"`
+	RequiredKeyUsages   []string             `json:"requiredKeyUsages"`
+	AllowedIssuers      []string             `json:"allowedIssuers"`
+}
+
+// PolicyViolation a rule of cert policy that the cert or crl does not meet
+type PolicyViolation struct {
+	Subject string `json:"subject"`
+	Rule    string `json:"rule"`
+	Detail  string `json:"detail"`
+}
+
+// PolicyViolations all the violations found by once evaluation
+type PolicyViolations []PolicyViolation
+
+// Err returns nil if there is no violation, otherwise an error contains all violations
+func (pv PolicyViolations) Err() error {
+	if len(pv) == 0 {
+		return nil
+	}
+	msgs := make([]string, 0, len(pv))
+	for _, violation := range pv {
+		msgs = append(msgs, fmt.Sprintf("[%s] %s: %s", violation.Subject, violation.Rule, violation.Detail))
+	}
+	return fmt.Errorf("cert policy is violated: %s", strings.Join(msgs, "; "))
+}
+
+// DefaultCertPolicy returns the policy which is the same as the fixed rules used to check imported ca certs
+func DefaultCertPolicy() *CertPolicy {
+	return &CertPolicy{
+		KeyAlgorithms: []KeyAlgorithmPolicy{
+			{Algorithm: KeyAlgorithmRsa, MinSize: 3072},
+			{Algorithm: KeyAlgorithmEcdsa, MinSize: 256},
+		},
+		SignatureAlgorithms: []string{
+			x509.SHA256WithRSA.String(), x509.SHA384WithRSA.String(), x509.SHA512WithRSA.String(),
+			x509.SHA256WithRSAPSS.String(), x509.SHA384WithRSAPSS.String(), x509.SHA512WithRSAPSS.String(),
+			x509.ECDSAWithSHA256.String(), x509.ECDSAWithSHA384.String(), x509.ECDSAWithSHA512.String(),
+		},
+		RequiredKeyUsages: []string{"certSign"},
+	}
+}
+
+// LoadCertPolicy loads the cert policy from json file, the default policy is used if the file does not exist
+func LoadCertPolicy(path string) (*CertPolicy, error) {
+	if !fileutils.IsExist(path) {
+		return DefaultCertPolicy(), nil
+	}
+	content, err := fileutils.LoadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("load cert policy file failed: %v", err)
+	}
+	if len(content) > maxPolicyFileSize {
+		return nil, errors.New("cert policy file is too large")
+	}
+	var policy CertPolicy
+	if err = json.Unmarshal(content, &policy); err != nil {
+		return nil, fmt.Errorf("unmarshal cert policy failed: %v", err)
+	}
+	if err = policy.Validate(); err != nil {
+		return nil, fmt.Errorf("cert policy is invalid: %v", err)
+	}
+	return &policy, nil
+}
+
+// Validate checks whether the rules of the policy are supported
+func (p *CertPolicy) Validate() error {
+	if len(p.KeyAlgorithms) == 0 {
+		return errors.New("at least one key algorithm should be allowed")
+	}
+	if len(p.KeyAlgorithms) > maxPolicyListLen || len(p.SignatureAlgorithms) > maxPolicyListLen ||
+		len(p.RequiredKeyUsages) > maxPolicyListLen || len(p.AllowedIssuers) > maxPolicyListLen {
+		return fmt.Errorf("the count of policy items exceeds %d", maxPolicyListLen)
+	}
+	for _, keyAlgo := range p.KeyAlgorithms {
+		switch keyAlgo.Algorithm {
+		case KeyAlgorithmRsa, KeyAlgorithmEcdsa, KeyAlgorithmEd25519:
+		default:
+			return fmt.Errorf("key algorithm [%s] is not supported", keyAlgo.Algorithm)
+		}
+		if keyAlgo.MinSize < 0 || keyAlgo.MinSize > maxPolicyKeySize {
+			return fmt.Errorf("min size of %s is out of range [0, %d]", keyAlgo.Algorithm, maxPolicyKeySize)
+		}
+	}
+	for _, signAlgo := range p.SignatureAlgorithms {
+		if !isKnownSignatureAlgorithm(signAlgo) {
+			return fmt.Errorf("signature algorithm [%s] is not supported", signAlgo)
+		}
+	}
+	if p.MaxValidityDays < 0 || p.MaxValidityDays > maxPolicyValidityDays {
+		return fmt.Errorf("max validity days is out of range [0, %d]", maxPolicyValidityDays)
+	}
+	for _, usage := range p.RequiredKeyUsages {
+		if _, ok := keyUsageNames[usage]; !ok {
+			return fmt.Errorf("key usage [%s] is not supported", usage)
+		}
+	}
+	for _, issuer := range p.AllowedIssuers {
+		if issuer == "" || len(issuer) > maxPolicyIssuerLen {
+			return fmt.Errorf("length of allowed issuer is out of range [1, %d]", maxPolicyIssuerLen)
+		}
+	}
+	return nil
+}
+
+func isKnownSignatureAlgorithm(name string) bool {
+	for _, known := range crlSignatureAlgorithms {
+		if known == name {
+			return true
+		}
+	}
+	for _, algo := range []x509.SignatureAlgorithm{x509.SHA256WithRSAPSS, x509.SHA384WithRSAPSS,
+		x509.SHA512WithRSAPSS} {
+		if algo.String() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckCerts evaluates the policy on every cert, and returns all violations
+func (p *CertPolicy) CheckCerts(certs []*x509.Certificate) PolicyViolations {
+	var violations PolicyViolations
+	for _, cert := range certs {
+		violations = append(violations, p.CheckCert(cert)...)
+	}
+	return violations
+}
+
+// CheckCert evaluates the policy on the cert, and returns all violations
+func (p *CertPolicy) CheckCert(cert *x509.Certificate) PolicyViolations {
+	subject := cert.Subject.String()
+	var violations PolicyViolations
+	addViolation := func(rule, detail string) {
+		violations = append(violations, PolicyViolation{Subject: subject, Rule: rule, Detail: detail})
+	}
+	if rule, detail := p.checkPublicKey(cert); rule != "" {
+		addViolation(rule, detail)
+	}
+	signAlgo := cert.SignatureAlgorithm.String()
+	if !p.isSignatureAllowed(signAlgo) {
+		addViolation(PolicyRuleSignatureAlgorithm, fmt.Sprintf("signature algorithm %s is not allowed", signAlgo))
+	}
+	if p.MaxValidityDays > 0 {
+		validity := cert.NotAfter.Sub(cert.NotBefore)
+		if validity > time.Duration(p.MaxValidityDays)*dayHours*time.Hour {
+			addViolation(PolicyRuleValidity, fmt.Sprintf("validity %d days exceeds %d days",
+				int64(validity.Hours()/dayHours), p.MaxValidityDays))
+		}
+	}
+	for _, usage := range p.RequiredKeyUsages {
+		if cert.KeyUsage&keyUsageNames[usage] == 0 {
+			addViolation(PolicyRuleKeyUsage, fmt.Sprintf("key usage %s is required", usage))
+		}
+	}
+	if issuer := cert.Issuer.String(); !p.isIssuerAllowed(issuer) {
+		addViolation(PolicyRuleIssuer, fmt.Sprintf("issuer %s is not allowed", issuer))
+	}
+	return violations
+}
+
+func (p *CertPolicy) checkPublicKey(cert *x509.Certificate) (string, string) {
+	var algo string
+	var size int
+	switch pub := cert.PublicKey.(type) {
+	case *rsa.PublicKey:
+		algo, size = KeyAlgorithmRsa, pub.N.BitLen()
+	case *ecdsa.PublicKey:
+		algo, size = KeyAlgorithmEcdsa, pub.Params().BitSize
+	case ed25519.PublicKey:
+		algo, size = KeyAlgorithmEd25519, ed25519KeySize
+	default:
+		return PolicyRuleKeyAlgorithm, fmt.Sprintf("public key algorithm %s is not supported",
+			cert.PublicKeyAlgorithm.String())
+	}
+	for _, allowed := range p.KeyAlgorithms {
+		if allowed.Algorithm != algo {
+			continue
+		}
+		if size < allowed.MinSize {
+			return PolicyRuleKeySize, fmt.Sprintf("%s key size %d is less than %d", algo, size, allowed.MinSize)
+		}
+		return "", ""
+	}
+	return PolicyRuleKeyAlgorithm, fmt.Sprintf("public key algorithm %s is not allowed", algo)
+}
+
+func (p *CertPolicy) isSignatureAllowed(signAlgo string) bool {
+	if len(p.SignatureAlgorithms) == 0 {
+		return true
+	}
+	for _, allowed := range p.SignatureAlgorithms {
+		// the hash of rsa-pss is not parsed from crl, it is allowed when any rsa-pss algorithm is allowed
+		if allowed == signAlgo || (signAlgo == rsaPssSuffix && strings.HasSuffix(allowed, rsaPssSuffix)) {
+			return true
+		}
+	}
+	return false
+}
+
+// isInsecureSignature the same hash algorithms as CheckSignatureAlgorithm are insecure
+func isInsecureSignature(signAlgo string) bool {
+	return strings.Contains(signAlgo, "MD2") || strings.Contains(signAlgo, "MD5") ||
+		strings.Contains(signAlgo, "SHA1")
+}
+
+func (p *CertPolicy) isIssuerAllowed(issuer string) bool {
+	if len(p.AllowedIssuers) == 0 {
+		return true
+	}
+	for _, allowed := range p.AllowedIssuers {
+		if allowed == issuer {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckCrls evaluates the signature algorithm and issuer rules of the policy on every crl
+func (p *CertPolicy) CheckCrls(crls []*pkix.CertificateList) PolicyViolations {
+	var violations PolicyViolations
+	for _, crl := range crls {
+		var issuerName pkix.Name
+		issuerName.FillFromRDNSequence(&crl.TBSCertList.Issuer)
+		issuer := issuerName.String()
+		// unknown and insecure algorithms are rejected even if the policy does not restrict signature algorithms
+		signAlgo, ok := crlSignatureAlgorithms[crl.SignatureAlgorithm.Algorithm.String()]
+		if !ok {
+			signAlgo = crl.SignatureAlgorithm.Algorithm.String()
+		}
+		if !ok || isInsecureSignature(signAlgo) || !p.isSignatureAllowed(signAlgo) {
+			violations = append(violations, PolicyViolation{Subject: issuer, Rule: PolicyRuleSignatureAlgorithm,
+				Detail: fmt.Sprintf("crl signature algorithm %s is not allowed", signAlgo)})
+		}
+		if !p.isIssuerAllowed(issuer) {
+			violations = append(violations, PolicyViolation{Subject: issuer, Rule: PolicyRuleIssuer,
+				Detail: fmt.Sprintf("crl issuer %s is not allowed", issuer)})
+		}
+	}
+	return violations
+}
@@ -0,0 +1,146 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package x509
+
+import (
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"os"
+	"testing"
+
+	"github.com/smartystreets/goconvey/convey"
+)
+
+func loadTestCaChainMgr(path string) *CaChainMgr {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		panic(err)
+	}
+	mgr, err := NewCaChainMgr(content)
+	if err != nil {
+		panic(err)
+	}
+	return mgr
+}
+
+func violatedRules(violations PolicyViolations) map[string]bool {
+	rules := map[string]bool{}
+	for _, violation := range violations {
+		rules[violation.Rule] = true
+	}
+	return rules
+}
+
+func TestCertPolicyCheckCerts(t *testing.T) {
+	chainMgr := loadTestCaChainMgr("./testdata/ca_chain.crt")
+	weakMgr := loadTestCaChainMgr("./testdata/ca.crt")
+
+	convey.Convey("test for CertPolicy.CheckCerts", t, func() {
+		convey.Convey("default policy is met by the cert chain", func() {
+			policy := DefaultCertPolicy()
+			convey.So(policy.Validate(), convey.ShouldBeNil)
+			convey.So(policy.CheckCerts(chainMgr.GetCerts()), convey.ShouldBeEmpty)
+			convey.So(chainMgr.CheckCertChain(CaChainCheckOptions{Policy: policy}), convey.ShouldBeNil)
+		})
+
+		convey.Convey("default policy reports weak key", func() {
+			violations := DefaultCertPolicy().CheckCerts(weakMgr.GetCerts())
+			convey.So(violatedRules(violations), convey.ShouldResemble, map[string]bool{PolicyRuleKeySize: true})
+			convey.So(violations.Err(), convey.ShouldNotBeNil)
+		})
+
+		convey.Convey("strict policy reports every violation", func() {
+			policy := &CertPolicy{
+				KeyAlgorithms:       []KeyAlgorithmPolicy{{Algorithm: KeyAlgorithmRsa, MinSize: 4096}},
+				SignatureAlgorithms: []string{"ECDSA-SHA384"},
+				MaxValidityDays:     365,
+				RequiredKeyUsages:   []string{"certSign", "decipherOnly"},
+				AllowedIssuers:      []string{"CN=other"},
+			}
+			convey.So(policy.Validate(), convey.ShouldBeNil)
+			violations := policy.CheckCerts(chainMgr.GetCerts())
+			convey.So(violatedRules(violations), convey.ShouldResemble, map[string]bool{
+				PolicyRuleKeySize: true, PolicyRuleSignatureAlgorithm: true, PolicyRuleValidity: true,
+				PolicyRuleKeyUsage: true, PolicyRuleIssuer: true,
+			})
+			convey.So(chainMgr.CheckCertChain(CaChainCheckOptions{Policy: policy}), convey.ShouldNotBeNil)
+		})
+
+		convey.Convey("key algorithm not allowed", func() {
+			policy := &CertPolicy{KeyAlgorithms: []KeyAlgorithmPolicy{{Algorithm: KeyAlgorithmEcdsa, MinSize: 256}}}
+			violations := policy.CheckCerts(chainMgr.GetCerts())
+			convey.So(violatedRules(violations), convey.ShouldResemble,
+				map[string]bool{PolicyRuleKeyAlgorithm: true})
+		})
+	})
+}
+
+func TestCertPolicyCheckCrls(t *testing.T) {
+	crls, err := ParseCrls(&CrlData{CrlPath: "./testdata/client.crl"})
+	if err != nil {
+		panic(err)
+	}
+
+	convey.Convey("test for CertPolicy.CheckCrls", t, func() {
+		convey.Convey("crl meets the policy", func() {
+			policy := DefaultCertPolicy()
+			policy.AllowedIssuers = []string{"CN=we-as-ca"}
+			convey.So(policy.CheckCrls(crls), convey.ShouldBeEmpty)
+		})
+
+		convey.Convey("crl violates the policy", func() {
+			policy := DefaultCertPolicy()
+			policy.SignatureAlgorithms = []string{"ECDSA-SHA256"}
+			policy.AllowedIssuers = []string{"CN=other"}
+			violations := policy.CheckCrls(crls)
+			convey.So(violatedRules(violations), convey.ShouldResemble,
+				map[string]bool{PolicyRuleSignatureAlgorithm: true, PolicyRuleIssuer: true})
+		})
+
+		convey.Convey("unknown and insecure signature algorithms are rejected by empty policy", func() {
+			policy := &CertPolicy{}
+			convey.So(policy.CheckCrls(crls), convey.ShouldBeEmpty)
+			for _, oid := range []asn1.ObjectIdentifier{{1, 2, 3, 4}, {1, 2, 840, 113549, 1, 1, 4},
+				{1, 2, 840, 10045, 4, 1}} {
+				crl := *crls[0]
+				crl.SignatureAlgorithm.Algorithm = oid
+				violations := policy.CheckCrls([]*pkix.CertificateList{&crl})
+				convey.So(violatedRules(violations), convey.ShouldResemble,
+					map[string]bool{PolicyRuleSignatureAlgorithm: true})
+			}
+		})
+	})
+}
+
+func TestCertPolicyValidate(t *testing.T) {
+	convey.Convey("test for CertPolicy.Validate", t, func() {
+		convey.Convey("invalid policies", func() {
+			policies := []*CertPolicy{
+				{},
+				{KeyAlgorithms: []KeyAlgorithmPolicy{{Algorithm: "DSA"}}},
+				{KeyAlgorithms: []KeyAlgorithmPolicy{{Algorithm: KeyAlgorithmRsa, MinSize: -1}}},
+				{KeyAlgorithms: DefaultCertPolicy().KeyAlgorithms, SignatureAlgorithms: []string{"SHA256"}},
+				{KeyAlgorithms: DefaultCertPolicy().KeyAlgorithms, MaxValidityDays: -1},
+				{KeyAlgorithms: DefaultCertPolicy().KeyAlgorithms, RequiredKeyUsages: []string{"serverAuth"}},
+				{KeyAlgorithms: DefaultCertPolicy().KeyAlgorithms, AllowedIssuers: []string{""}},
+			}
+			for _, policy := range policies {
+				convey.So(policy.Validate(), convey.ShouldNotBeNil)
+			}
+		})
+
+		convey.Convey("default policy is used when file does not exist", func() {
+			policy, err := LoadCertPolicy("./testdata/not-exist-policy.json")
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(policy, convey.ShouldResemble, DefaultCertPolicy())
+		})
+	})
+}
@@ -46,6 +46,11 @@ type TlsCertInfo struct {
 	IgnoreCltCert bool
 	RootCaOnly    bool // use root ca cert only, skip svc cert
 	WithBackup    bool // if true, all files from path will enable back up and restore
+	// SkipHostVerify the client verifies the cert chain of server only, without checking the host name of server.
+	// It is used between the nodes whose certs issued by the same root ca have no san, so the server is pinned by
+	// PeerCertSn, the serial number in hex of the server cert which is expected
+	SkipHostVerify bool
+	PeerCertSn     string
 }
 
 // CertSan [struct] for server cert san fields
@@ -204,6 +204,11 @@ func saveCertWithPem(certPath string, certDerBytes []byte) error {
 	return fileutils.SetPathPermission(certPath, fileutils.Mode400, false, false)
 }
 
+// SaveKeyWithPem [method] for saving rsa private key in pem format, which is encrypted by kmc
+func SaveKeyWithPem(keyPath string, priv *rsa.PrivateKey, kmcCfg *kmc.SubConfig) error {
+	return saveKeyWithPem(keyPath, priv, kmcCfg)
+}
+
 func saveKeyWithPem(keyPath string, keyDerBytes *rsa.PrivateKey, kmcCfg *kmc.SubConfig) error {
 	keyPem := PemWrapPrivKey(keyDerBytes)
 	defer hwX509.PaddingAndCleanSlice(keyPem)
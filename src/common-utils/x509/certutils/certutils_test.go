@@ -12,6 +12,9 @@
 package certutils
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
 	"errors"
 	"fmt"
 	"net"
@@ -176,3 +179,120 @@ func TestGetCrlContentWithBackup(t *testing.T) {
 		})
 	}
 }
+
+// startNodeCertServer starts a tls server with the cert which has no san like the certs of edge nodes
+func startNodeCertServer(testDir string) (TlsCertInfo, net.Listener, error) {
+	kmcCfg := &kmc.SubConfig{
+		SdpAlgID:       kmc.Aes256gcmId,
+		PrimaryKeyPath: path.Join(testDir, "master.ks"),
+		StandbyKeyPath: path.Join(testDir, "backup.ks"),
+		DoMainId:       kmc.DefaultDoMainId,
+	}
+	certInfo := TlsCertInfo{
+		RootCaPath: path.Join(testDir, "test_root.ca"),
+		CertPath:   path.Join(testDir, "test_cert.crt"),
+		KeyPath:    path.Join(testDir, "test_cert.key"),
+		KmcCfg:     kmcCfg,
+	}
+	rootCertMgr := InitRootCertMgr(certInfo.RootCaPath, path.Join(testDir, "test_root.key"), "MEF Test", kmcCfg)
+	if _, err := rootCertMgr.NewRootCa(); err != nil {
+		return certInfo, nil, err
+	}
+	nodeCert := SelfSignCert{RootCertMgr: rootCertMgr, SvcCertPath: certInfo.CertPath,
+		SvcKeyPath: certInfo.KeyPath, CommonNamePrefix: "MEF Test", KmcCfg: kmcCfg}
+	if err := nodeCert.CreateSignCert(); err != nil {
+		return certInfo, nil, err
+	}
+	svrInfo := certInfo
+	svrInfo.SvrFlag = true
+	svrCfg, err := GetTlsCfgWithPath(svrInfo)
+	if err != nil {
+		return certInfo, nil, err
+	}
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", svrCfg)
+	if err != nil {
+		return certInfo, nil, err
+	}
+	go func() {
+		for {
+			conn, acceptErr := listener.Accept()
+			if acceptErr != nil {
+				return
+			}
+			if tlsConn, ok := conn.(*tls.Conn); ok {
+				if handshakeErr := tlsConn.Handshake(); handshakeErr != nil {
+					fmt.Printf("handshake failed: %v\n", handshakeErr)
+				}
+			}
+			if closeErr := conn.Close(); closeErr != nil {
+				fmt.Printf("close conn failed: %v\n", closeErr)
+			}
+		}
+	}()
+	return certInfo, listener, nil
+}
+
+func getCertSnForUT(certPath string) string {
+	certPem, err := fileutils.LoadFile(certPath)
+	if err != nil {
+		return ""
+	}
+	block, _ := pem.Decode(certPem)
+	if block == nil {
+		return ""
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return ""
+	}
+	return cert.SerialNumber.Text(snHexBase)
+}
+
+func TestSkipHostVerify(t *testing.T) {
+	logConfig := &hwlog.LogConfig{OnlyToStdout: true}
+	if err := hwlog.InitHwLogger(logConfig, logConfig); err != nil {
+		hwlog.RunLog.Errorf("init hwlog failed, %v", err)
+	}
+	testDir := "/tmp/mef-test-skip-host-certs/"
+	defer func() {
+		if err := os.RemoveAll(testDir); err != nil {
+			return
+		}
+	}()
+	convey.Convey("test skip host verify", t, func() {
+		convey.So(fileutils.MakeSureDir(testDir), convey.ShouldBeNil)
+		certInfo, listener, err := startNodeCertServer(testDir)
+		convey.So(err, convey.ShouldBeNil)
+		defer listener.Close()
+
+		convey.Convey("host name of server without san is verified by default", func() {
+			cltCfg, err := GetTlsCfgWithPath(certInfo)
+			convey.So(err, convey.ShouldBeNil)
+			_, err = tls.Dial("tcp", listener.Addr().String(), cltCfg)
+			convey.So(err, convey.ShouldNotBeNil)
+		})
+		convey.Convey("cert chain and serial number of server are verified when host verify is skipped", func() {
+			certInfo.SkipHostVerify = true
+			certInfo.PeerCertSn = getCertSnForUT(certInfo.CertPath)
+			cltCfg, err := GetTlsCfgWithPath(certInfo)
+			convey.So(err, convey.ShouldBeNil)
+			conn, err := tls.Dial("tcp", listener.Addr().String(), cltCfg)
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(conn.Close(), convey.ShouldBeNil)
+		})
+		convey.Convey("server whose cert is not the pinned one is rejected", func() {
+			certInfo.SkipHostVerify = true
+			certInfo.PeerCertSn = "1A2B"
+			cltCfg, err := GetTlsCfgWithPath(certInfo)
+			convey.So(err, convey.ShouldBeNil)
+			_, err = tls.Dial("tcp", listener.Addr().String(), cltCfg)
+			convey.So(err, convey.ShouldNotBeNil)
+		})
+		convey.Convey("host verify is not skipped without the serial number to pin", func() {
+			certInfo.SkipHostVerify = true
+			certInfo.PeerCertSn = ""
+			_, err := GetTlsCfgWithPath(certInfo)
+			convey.So(err, convey.ShouldNotBeNil)
+		})
+	})
+}
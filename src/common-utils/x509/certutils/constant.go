@@ -34,4 +34,6 @@ const (
 	privKeyType = "RSA PRIVATE KEY"
 	// OneDayAgo for compatible with different time zone when issue cert
 	OneDayAgo = "-24h"
+	// snHexBase the serial number of the pinned cert is in hex
+	snHexBase = 16
 )
@@ -17,6 +17,7 @@ import (
 	"crypto/x509/pkix"
 	"errors"
 	"fmt"
+	"math/big"
 
 	"huawei.com/mindx/common/backuputils"
 	"huawei.com/mindx/common/fileutils"
@@ -38,15 +39,17 @@ var DefaultSafeCipherSuites = []uint16{
 }
 
 type tlsParam struct {
-	rootPem       []byte
-	certPem       []byte
-	keyPem        []byte
-	certPath      string
-	keyPath       string
-	KmcCfg        *kmc.SubConfig
-	crls          []*pkix.CertificateList
-	svrFlag       bool
-	ignoreCltCert bool
+	rootPem        []byte
+	certPem        []byte
+	keyPem         []byte
+	certPath       string
+	keyPath        string
+	KmcCfg         *kmc.SubConfig
+	crls           []*pkix.CertificateList
+	svrFlag        bool
+	ignoreCltCert  bool
+	skipHostVerify bool
+	peerCertSn     string
 }
 
 type certFilesChecker func(tlsCertInfo TlsCertInfo) error
@@ -200,15 +203,17 @@ func getTlsCfgWithPathWithoutBackup(tlsCertInfo TlsCertInfo) (*tls.Config, error
 		}
 	}
 	return getTlsConfig(tlsParam{
-		rootPem:       rootCaPemBytes,
-		certPem:       pemPair.CertPem,
-		keyPem:        pemPair.KeyPem,
-		certPath:      tlsCertInfo.CertPath,
-		keyPath:       tlsCertInfo.KeyPath,
-		KmcCfg:        tlsCertInfo.KmcCfg,
-		svrFlag:       tlsCertInfo.SvrFlag,
-		ignoreCltCert: tlsCertInfo.IgnoreCltCert,
-		crls:          crls,
+		rootPem:        rootCaPemBytes,
+		certPem:        pemPair.CertPem,
+		keyPem:         pemPair.KeyPem,
+		certPath:       tlsCertInfo.CertPath,
+		keyPath:        tlsCertInfo.KeyPath,
+		KmcCfg:         tlsCertInfo.KmcCfg,
+		svrFlag:        tlsCertInfo.SvrFlag,
+		ignoreCltCert:  tlsCertInfo.IgnoreCltCert,
+		skipHostVerify: tlsCertInfo.SkipHostVerify,
+		peerCertSn:     tlsCertInfo.PeerCertSn,
+		crls:           crls,
 	})
 }
 
@@ -248,10 +253,61 @@ func getTlsConfig(params tlsParam) (*tls.Config, error) {
 			return getCertPairFromParam(params)
 		}
 		tlsCfg.RootCAs = rootCaPool
+		if params.skipHostVerify {
+			if err := setSkipHostVerify(tlsCfg, rootCaPool, params); err != nil {
+				return nil, err
+			}
+		}
 	}
 	return tlsCfg, nil
 }
 
+// setSkipHostVerify the default verification of the server cert including its host name is skipped,
+// and the cert chain of server is verified by the root ca and crls instead. The server cert is pinned by its
+// serial number, since the certs issued by the same root ca to the nodes are not told apart by the host name
+func setSkipHostVerify(tlsCfg *tls.Config, rootCaPool *x509.CertPool, params tlsParam) error {
+	peerCertSn, ok := new(big.Int).SetString(params.peerCertSn, snHexBase)
+	if !ok || peerCertSn.Sign() <= 0 {
+		return errors.New("the serial number of server cert to pin is invalid")
+	}
+	tlsCfg.InsecureSkipVerify = true
+	tlsCfg.VerifyPeerCertificate = func(certificates [][]byte, _ [][]*x509.Certificate) error {
+		if len(certificates) == 0 {
+			return errors.New("no cert of server is provided")
+		}
+		certs := make([]*x509.Certificate, 0, len(certificates))
+		for _, raw := range certificates {
+			cert, err := x509.ParseCertificate(raw)
+			if err != nil {
+				return fmt.Errorf("parse cert of server failed: %v", err)
+			}
+			certs = append(certs, cert)
+		}
+		if certs[0].SerialNumber.Cmp(peerCertSn) != 0 {
+			return errors.New("the serial number of server cert does not match the pinned one")
+		}
+		opts := x509.VerifyOptions{
+			Roots:         rootCaPool,
+			Intermediates: x509.NewCertPool(),
+			KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		}
+		for _, cert := range certs[1:] {
+			opts.Intermediates.AddCert(cert)
+		}
+		verifiedChains, err := certs[0].Verify(opts)
+		if err != nil {
+			return fmt.Errorf("verify cert chain of server failed: %v", err)
+		}
+		for _, crl := range params.crls {
+			if err = checkCaFileInCrl(verifiedChains, crl); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return nil
+}
+
 func getTlsCfgRootCaOnly(rootPem []byte, crls []*pkix.CertificateList) (*tls.Config, error) {
 	rootCaPool := x509.NewCertPool()
 	if ok := rootCaPool.AppendCertsFromPEM(rootPem); !ok {
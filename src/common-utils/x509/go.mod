@@ -5,6 +5,7 @@ go 1.17
 require (
 	github.com/agiledragon/gomonkey/v2 v2.8.0
 	github.com/smartystreets/goconvey v1.7.2
+	golang.org/x/crypto v0.23.0 // indirect
 	huawei.com/mindx/common/backuputils v0.0.0
 	huawei.com/mindx/common/envutils v0.0.0
 	huawei.com/mindx/common/fileutils v0.0.4
@@ -13,6 +14,7 @@ require (
 	huawei.com/mindx/common/rand v0.0.0
 	huawei.com/mindx/common/terminal v0.0.5
 	huawei.com/mindx/common/utils v0.0.0
+	software.sslmate.com/src/go-pkcs12 v0.4.0
 )
 
 replace (
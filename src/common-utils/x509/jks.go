@@ -0,0 +1,231 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package x509
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"time"
+	"unicode/utf16"
+)
+
+// java keystore truststore, only the trusted cert entries are supported
+const (
+	jksMagic          uint32 = 0xFEEDFEED
+	jksVersion        uint32 = 2
+	jksTagPrivateKey  uint32 = 1
+	jksTagTrustedCert uint32 = 2
+	jksCertType              = "X.509"
+	// jksDigestWhitener the fixed string mixed into the integrity digest of jks
+	jksDigestWhitener = "Mighty Aphrodite"
+	maxJksEntryCount  = 1000
+)
+
+type jksReader struct {
+	reader *bytes.Reader
+}
+
+func (r *jksReader) readUint16() (uint16, error) {
+	var value uint16
+	err := binary.Read(r.reader, binary.BigEndian, &value)
+	return value, err
+}
+
+func (r *jksReader) readUint32() (uint32, error) {
+	var value uint32
+	err := binary.Read(r.reader, binary.BigEndian, &value)
+	return value, err
+}
+
+func (r *jksReader) readBytes(size int) ([]byte, error) {
+	if size > r.reader.Len() {
+		return nil, io.ErrUnexpectedEOF
+	}
+	data := make([]byte, size)
+	_, err := io.ReadFull(r.reader, data)
+	return data, err
+}
+
+func (r *jksReader) readUtf() (string, error) {
+	size, err := r.readUint16()
+	if err != nil {
+		return "", err
+	}
+	data, err := r.readBytes(int(size))
+	return string(data), err
+}
+
+func (r *jksReader) readCert() ([]byte, error) {
+	certType, err := r.readUtf()
+	if err != nil {
+		return nil, err
+	}
+	if certType != jksCertType {
+		return nil, fmt.Errorf("cert type %s is not supported", certType)
+	}
+	size, err := r.readUint32()
+	if err != nil {
+		return nil, err
+	}
+	return r.readBytes(int(size))
+}
+
+func (r *jksReader) readEntry() ([]byte, error) {
+	tag, err := r.readUint32()
+	if err != nil {
+		return nil, err
+	}
+	if tag != jksTagTrustedCert && tag != jksTagPrivateKey {
+		return nil, fmt.Errorf("entry tag %d is not supported", tag)
+	}
+	// alias and timestamp are not needed
+	if _, err = r.readUtf(); err != nil {
+		return nil, err
+	}
+	if _, err = r.readBytes(binary.Size(uint64(0))); err != nil {
+		return nil, err
+	}
+	if tag == jksTagTrustedCert {
+		return r.readCert()
+	}
+	// private key entries are not needed by truststore, skip the key and its cert chain
+	keySize, err := r.readUint32()
+	if err != nil {
+		return nil, err
+	}
+	if _, err = r.readBytes(int(keySize)); err != nil {
+		return nil, err
+	}
+	chainLen, err := r.readUint32()
+	if err != nil {
+		return nil, err
+	}
+	for i := uint32(0); i < chainLen; i++ {
+		if _, err = r.readCert(); err != nil {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+// bmpString encodes the string as BMPString, which is the big endian utf-16 without terminator
+func bmpString(s string) []byte {
+	codes := utf16.Encode([]rune(s))
+	ret := make([]byte, 0, len(codes)*2)
+	for _, code := range codes {
+		ret = append(ret, byte(code>>8), byte(code))
+	}
+	return ret
+}
+
+func jksDigest(content, password []byte) []byte {
+	bmpPwd := bmpString(string(password))
+	defer PaddingAndCleanSlice(bmpPwd)
+	h := sha1.New()
+	h.Write(bmpPwd)
+	h.Write([]byte(jksDigestWhitener))
+	h.Write(content)
+	return h.Sum(nil)
+}
+
+// decodeJksCerts returns the der certs of the trusted cert entries in jks after verifying its integrity
+func decodeJksCerts(content, password []byte) ([][]byte, error) {
+	if len(content) < sha1.Size {
+		return nil, errors.New("jks content is too short")
+	}
+	body := content[:len(content)-sha1.Size]
+	if !hmac.Equal(jksDigest(body, password), content[len(body):]) {
+		return nil, errors.New("verify jks integrity failed, the password may be incorrect")
+	}
+	reader := &jksReader{reader: bytes.NewReader(body)}
+	magic, err := reader.readUint32()
+	if err != nil || magic != jksMagic {
+		return nil, errors.New("content is not jks format")
+	}
+	version, err := reader.readUint32()
+	if err != nil || version != jksVersion {
+		return nil, errors.New("jks version is not supported")
+	}
+	count, err := reader.readUint32()
+	if err != nil || count > maxJksEntryCount {
+		return nil, errors.New("jks entry count is invalid")
+	}
+	var certs [][]byte
+	for i := uint32(0); i < count; i++ {
+		cert, err := reader.readEntry()
+		if err != nil {
+			return nil, fmt.Errorf("parse jks entry failed: %v", err)
+		}
+		if cert != nil {
+			certs = append(certs, cert)
+		}
+	}
+	if reader.reader.Len() != 0 {
+		return nil, errors.New("unexpected data in jks content")
+	}
+	return certs, nil
+}
+
+func writeJksUtf(buf *bytes.Buffer, value string) error {
+	if len(value) > math.MaxUint16 {
+		return errors.New("string is too long")
+	}
+	if err := binary.Write(buf, binary.BigEndian, uint16(len(value))); err != nil {
+		return err
+	}
+	_, err := buf.WriteString(value)
+	return err
+}
+
+func writeJksEntry(buf *bytes.Buffer, cert *x509.Certificate, alias string, timestamp int64) error {
+	if err := binary.Write(buf, binary.BigEndian, jksTagTrustedCert); err != nil {
+		return err
+	}
+	if err := writeJksUtf(buf, alias); err != nil {
+		return err
+	}
+	if err := binary.Write(buf, binary.BigEndian, timestamp); err != nil {
+		return err
+	}
+	if err := writeJksUtf(buf, jksCertType); err != nil {
+		return err
+	}
+	if err := binary.Write(buf, binary.BigEndian, uint32(len(cert.Raw))); err != nil {
+		return err
+	}
+	_, err := buf.Write(cert.Raw)
+	return err
+}
+
+// encodeJksCerts encodes the certs to jks truststore as trusted cert entries
+func encodeJksCerts(certs []*x509.Certificate, aliases []string, password []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	for _, value := range []uint32{jksMagic, jksVersion, uint32(len(certs))} {
+		if err := binary.Write(&buf, binary.BigEndian, value); err != nil {
+			return nil, err
+		}
+	}
+	timestamp := time.Now().UnixNano() / int64(time.Millisecond)
+	for idx, cert := range certs {
+		if err := writeJksEntry(&buf, cert, aliases[idx], timestamp); err != nil {
+			return nil, fmt.Errorf("write jks entry failed: %v", err)
+		}
+	}
+	content := buf.Bytes()
+	return append(content, jksDigest(content, password)...), nil
+}
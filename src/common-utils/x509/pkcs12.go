@@ -0,0 +1,52 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package x509
+
+import (
+	"crypto/x509"
+	"fmt"
+
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+// decodePkcs12Certs returns the der ca certs in pkcs12 content after verifying its mac. The keystore exported with
+// a private key, such as by openssl, is decoded as a chain, the key and its cert are dropped unless the cert is a ca.
+// The truststore without key is decoded as java truststore, whose certs must be marked as trusted
+func decodePkcs12Certs(content, password []byte) ([][]byte, error) {
+	_, cert, caCerts, err := pkcs12.DecodeChain(content, string(password))
+	if err != nil {
+		var trustErr error
+		if caCerts, trustErr = pkcs12.DecodeTrustStore(content, string(password)); trustErr != nil {
+			return nil, fmt.Errorf("decode pkcs12 as keystore failed: %v, as truststore failed: %v", err, trustErr)
+		}
+	} else if cert.IsCA {
+		caCerts = append([]*x509.Certificate{cert}, caCerts...)
+	}
+	derCerts := make([][]byte, 0, len(caCerts))
+	for _, caCert := range caCerts {
+		derCerts = append(derCerts, caCert.Raw)
+	}
+	return derCerts, nil
+}
+
+// encodePkcs12Certs encodes the certs to pkcs12 truststore, the certs are encrypted by pbes2 with aes-256-cbc and
+// protected by hmac-sha256, which is the default of openssl 3 and java 11 or later
+func encodePkcs12Certs(certs []*x509.Certificate, aliases []string, password []byte) ([]byte, error) {
+	entries := make([]pkcs12.TrustStoreEntry, 0, len(certs))
+	for idx, cert := range certs {
+		entries = append(entries, pkcs12.TrustStoreEntry{Cert: cert, FriendlyName: aliases[idx]})
+	}
+	content, err := pkcs12.Modern2023.EncodeTrustStoreEntries(entries, string(password))
+	if err != nil {
+		return nil, fmt.Errorf("encode pkcs12 truststore failed: %v", err)
+	}
+	return content, nil
+}
@@ -7,10 +7,12 @@ require (
 	github.com/fsnotify/fsnotify v1.7.0
 	github.com/smartystreets/goconvey v1.7.2
 	google.golang.org/grpc v1.79.3
+	huawei.com/mindx/common/fileutils v0.0.0
 	huawei.com/mindx/common/hwlog v0.0.0
 	huawei.com/mindx/common/limiter v0.0.0
 	k8s.io/apimachinery v0.28.1
 	k8s.io/kubelet v0.28.1
+	sigs.k8s.io/yaml v1.3.0
 )
 
 require (
@@ -23,8 +25,8 @@ require (
 	golang.org/x/text v0.32.0 // indirect
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20251202230838-ff82c1b0f217 // indirect
 	google.golang.org/protobuf v1.36.10 // indirect
+	gopkg.in/yaml.v2 v2.4.0 // indirect
 	huawei.com/mindx/common/cache v0.0.0 // indirect
-	huawei.com/mindx/common/fileutils v0.0.0 // indirect
 	huawei.com/mindx/common/rand v0.0.0 // indirect
 	huawei.com/mindx/common/utils v0.0.0 // indirect
 )
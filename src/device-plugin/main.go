@@ -52,6 +52,8 @@ var (
 		"The log file path, if the file size exceeds 20MB, will be rotate")
 	shareDevCount = flag.Uint("shareDevCount", 1, "share device function, enable the func by setting "+
 		"a value greater than 1, range is [1, 100], only support 310B")
+	simTopology = flag.String("simTopology", "", "The yaml or json topology file of simulated devices, "+
+		"the devices are simulated instead of using the npu driver if it is set, only for test")
 )
 
 var (
@@ -126,7 +128,7 @@ func main() {
 
 // InitFunction init function
 func InitFunction() (*server.HwDevManager, error) {
-	devM, err := devmanager.AutoInit("")
+	devM, err := initDevManager()
 	if err != nil {
 		hwlog.RunLog.Errorf("init devmanager failed, err: %#v", err)
 		return nil, err
@@ -140,10 +142,19 @@ func InitFunction() (*server.HwDevManager, error) {
 	return hdm, nil
 }
 
+func initDevManager() (*devmanager.DeviceManager, error) {
+	if *simTopology == "" {
+		return devmanager.AutoInit("")
+	}
+	hwlog.RunLog.Warnf("the devices are simulated by topology file %s, only for test", *simTopology)
+	return devmanager.SimInit(*simTopology)
+}
+
 func setParameters() {
 	common.ParamOption = common.Option{
 		GetFdFlag:          *fdFlag,
 		ListAndWatchPeriod: *listWatchPeriod,
 		ShareCount:         *shareDevCount,
+		SimMode:            *simTopology != "",
 	}
 }
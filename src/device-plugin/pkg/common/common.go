@@ -76,6 +76,9 @@ func setDeviceByPath(defaultDevices *[]string, device string) {
 
 // GetDefaultDevices get default device, for allocate mount
 func GetDefaultDevices(getFdFlag bool) ([]string, error) {
+	if ParamOption.SimMode {
+		return []string{HiAIManagerDevice, HiAIHDCDevice, HiAISVMDevice}, nil
+	}
 	davinciManager, err := getDavinciManagerPath()
 	if err != nil {
 		return nil, err
@@ -64,6 +64,7 @@ type Option struct {
 	AiCoreCount        int32    // found by dcmi interface
 	ProductTypes       []string // all product types
 	RealCardType       string   // real card type
+	SimMode            bool     // devices are simulated, the device files do not exist on host
 }
 
 // FileWatch is used to watch sock file
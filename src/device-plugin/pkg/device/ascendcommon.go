@@ -214,7 +214,7 @@ func (tool *AscendTools) syncDuoCardState(groupDevice map[string][]*common.NpuDe
 		hwlog.RunLog.Debugf("not found 310P devices")
 		return
 	}
-	unHealthyCards := getUnHealthyCard(ascend310PDevices)
+	unHealthyCards := GetUnHealthyCard(ascend310PDevices)
 	for devType, devices := range groupDevice {
 		if devType != common.Ascend310P {
 			continue
@@ -227,7 +227,8 @@ func (tool *AscendTools) syncDuoCardState(groupDevice map[string][]*common.NpuDe
 	}
 }
 
-func getUnHealthyCard(ascend310PDevices []*common.NpuDevice) map[int32]interface{} {
+// GetUnHealthyCard get the cards which have unhealthy chips
+func GetUnHealthyCard(ascend310PDevices []*common.NpuDevice) map[int32]interface{} {
 	unHealthyCards := make(map[int32]interface{}, len(ascend310PDevices))
 	for _, device := range ascend310PDevices {
 		if device.Health == v1beta1.Healthy {
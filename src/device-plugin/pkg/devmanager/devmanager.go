@@ -19,6 +19,7 @@ import (
 
 	"Ascend-device-plugin/pkg/devmanager/common"
 	"Ascend-device-plugin/pkg/devmanager/dcmi"
+	"Ascend-device-plugin/pkg/devmanager/simulator"
 )
 
 // DeviceInterface for common device interface
@@ -82,6 +83,25 @@ func AutoInit(dType string) (*DeviceManager, error) {
 	return devManager, nil
 }
 
+// SimInit return the processing object of the simulated devices described by the topology file, only for test
+func SimInit(topologyPath string) (*DeviceManager, error) {
+	topology, err := simulator.LoadTopology(topologyPath)
+	if err != nil {
+		return nil, fmt.Errorf("sim init failed, err: %s", err)
+	}
+	devType := topology.DevType()
+	switch devType {
+	case common.Ascend310P, common.Ascend310, common.Ascend310B:
+	default:
+		return nil, fmt.Errorf("unsupported device type (%s)", devType)
+	}
+	devManager := &DeviceManager{DcMgr: simulator.NewSimManager(topology), DevType: devType}
+	if err = devManager.Init(); err != nil {
+		return nil, fmt.Errorf("deviceManager init failed, err: %#v", err)
+	}
+	return devManager, nil
+}
+
 func getChipInfoForInit() (common.ChipInfo, error) {
 	dcMgr := dcmi.DcManager{}
 	if err := dcMgr.DcInit(); err != nil {
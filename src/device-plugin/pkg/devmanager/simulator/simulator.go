@@ -0,0 +1,246 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package simulator this for simulated npu driver without ascend hardware
+package simulator
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"huawei.com/mindx/common/hwlog"
+
+	"Ascend-device-plugin/pkg/devmanager/common"
+)
+
+// chipLocation the card id and device id of a chip, the logic id of a chip is its index in the locations
+type chipLocation struct {
+	cardID   int32
+	deviceID int32
+}
+
+// SimManager the simulated driver of the chips in the topology, it implements dcmi.DcDriverInterface
+type SimManager struct {
+	lock      sync.Mutex
+	topology  *Topology
+	locations []chipLocation
+	initTime  time.Time
+	// applied the number of the events already applied
+	applied int
+	now     func() time.Time
+}
+
+// NewSimManager create the simulated driver of the topology
+func NewSimManager(topology *Topology) *SimManager {
+	sm := &SimManager{topology: topology, now: time.Now}
+	for _, card := range topology.Cards {
+		for devID := range card.Chips {
+			sm.locations = append(sm.locations, chipLocation{cardID: card.CardID, deviceID: int32(devID)})
+		}
+	}
+	return sm
+}
+
+// DcInit start the clock of the scripted events
+func (sm *SimManager) DcInit() error {
+	sm.lock.Lock()
+	defer sm.lock.Unlock()
+	if sm.topology == nil {
+		return errors.New("topology is nil")
+	}
+	sm.initTime = sm.now()
+	hwlog.RunLog.Infof("simulated driver init, card num: %d, chip num: %d, event num: %d",
+		len(sm.topology.Cards), len(sm.locations), len(sm.topology.Events))
+	return nil
+}
+
+// DcShutDown nothing to release for the simulated driver
+func (sm *SimManager) DcShutDown() error {
+	hwlog.RunLog.Info("simulated driver shut down")
+	return nil
+}
+
+// DcGetDeviceCount get device count
+func (sm *SimManager) DcGetDeviceCount() (int32, error) {
+	return int32(len(sm.locations)), nil
+}
+
+// DcGetLogicIDList get device logic id list
+func (sm *SimManager) DcGetLogicIDList() (int32, []int32, error) {
+	logicIDs := make([]int32, 0, len(sm.locations))
+	for logicID := range sm.locations {
+		logicIDs = append(logicIDs, int32(logicID))
+	}
+	return int32(len(logicIDs)), logicIDs, nil
+}
+
+// DcGetDeviceHealth get device health, the scripted events are applied before querying
+func (sm *SimManager) DcGetDeviceHealth(cardID, deviceID int32) (int32, error) {
+	chip, err := sm.getChip(cardID, deviceID)
+	if err != nil {
+		return common.RetError, err
+	}
+	return chip.Health, nil
+}
+
+// DcGetDeviceErrorCode get the error count and errorcode of the device
+func (sm *SimManager) DcGetDeviceErrorCode(cardID, deviceID int32) (int32, int64, error) {
+	chip, err := sm.getChip(cardID, deviceID)
+	if err != nil {
+		return common.RetError, common.RetError, err
+	}
+	if chip.ErrorCode == 0 {
+		return 0, 0, nil
+	}
+	return 1, chip.ErrorCode, nil
+}
+
+// DcGetChipInfo get chip info
+func (sm *SimManager) DcGetChipInfo(cardID, deviceID int32) (*common.ChipInfo, error) {
+	chip, err := sm.getChip(cardID, deviceID)
+	if err != nil {
+		return nil, err
+	}
+	return &common.ChipInfo{Type: chip.ChipType, Name: chip.ChipName, Version: chip.ChipVersion}, nil
+}
+
+// DcGetPhysicIDFromLogicID get physic id from logic id
+func (sm *SimManager) DcGetPhysicIDFromLogicID(logicID int32) (int32, error) {
+	cardID, deviceID, err := sm.DcGetCardIDDeviceID(logicID)
+	if err != nil {
+		return common.RetError, err
+	}
+	chip, err := sm.getChip(cardID, deviceID)
+	if err != nil {
+		return common.RetError, err
+	}
+	return chip.PhyID, nil
+}
+
+// DcGetDeviceLogicID get device logic id by card id and device id
+func (sm *SimManager) DcGetDeviceLogicID(cardID, deviceID int32) (int32, error) {
+	for logicID, location := range sm.locations {
+		if location.cardID == cardID && location.deviceID == deviceID {
+			return int32(logicID), nil
+		}
+	}
+	return common.RetError, fmt.Errorf("chip of cardID(%d) and deviceID(%d) is not found", cardID, deviceID)
+}
+
+// DcGetCardList get card id list
+func (sm *SimManager) DcGetCardList() (int32, []int32, error) {
+	cardList := make([]int32, 0, len(sm.topology.Cards))
+	for _, card := range sm.topology.Cards {
+		cardList = append(cardList, card.CardID)
+	}
+	return int32(len(cardList)), cardList, nil
+}
+
+// DcGetDeviceNumInCard get device num in the card
+func (sm *SimManager) DcGetDeviceNumInCard(cardID int32) (int32, error) {
+	card, err := sm.getCard(cardID)
+	if err != nil {
+		return common.RetError, err
+	}
+	return int32(len(card.Chips)), nil
+}
+
+// DcGetCardIDDeviceID get card id and device id by logic id
+func (sm *SimManager) DcGetCardIDDeviceID(logicID int32) (int32, int32, error) {
+	if logicID < 0 || logicID >= int32(len(sm.locations)) {
+		return common.RetError, common.RetError, fmt.Errorf("input invalid logicID: %d", logicID)
+	}
+	location := sm.locations[logicID]
+	return location.cardID, location.deviceID, nil
+}
+
+// DcGetVDeviceInfo get the virtual devices of the chip
+func (sm *SimManager) DcGetVDeviceInfo(logicID int32) (common.VirtualDevInfo, error) {
+	cardID, deviceID, err := sm.DcGetCardIDDeviceID(logicID)
+	if err != nil {
+		return common.VirtualDevInfo{}, err
+	}
+	chip, err := sm.getChip(cardID, deviceID)
+	if err != nil {
+		return common.VirtualDevInfo{}, err
+	}
+	vDevInfo := common.VirtualDevInfo{
+		TotalResource: common.CgoSocTotalResource{VDevNum: uint32(len(chip.VirtualDevs))},
+	}
+	for _, vDev := range chip.VirtualDevs {
+		vDevInfo.TotalResource.VDevID = append(vDevInfo.TotalResource.VDevID, vDev.VDevID)
+		vDevInfo.VDevInfo = append(vDevInfo.VDevInfo, common.CgoVDevQueryStru{
+			VDevID: vDev.VDevID,
+			QueryInfo: common.CgoVDevQueryInfo{
+				Name:      vDev.Template,
+				Computing: common.CgoComputingResource{Aic: vDev.AiCore},
+			},
+		})
+	}
+	return vDevInfo, nil
+}
+
+// DcGetProductType get product type of the card
+func (sm *SimManager) DcGetProductType(cardID, deviceID int32) (string, error) {
+	if _, err := sm.getChip(cardID, deviceID); err != nil {
+		return "", err
+	}
+	card, err := sm.getCard(cardID)
+	if err != nil {
+		return "", err
+	}
+	return card.ProductType, nil
+}
+
+func (sm *SimManager) getCard(cardID int32) (*Card, error) {
+	for i := range sm.topology.Cards {
+		if sm.topology.Cards[i].CardID == cardID {
+			return &sm.topology.Cards[i], nil
+		}
+	}
+	return nil, fmt.Errorf("card of cardID(%d) is not found", cardID)
+}
+
+// getChip get a copy of the chip after the due events are applied
+func (sm *SimManager) getChip(cardID, deviceID int32) (Chip, error) {
+	sm.lock.Lock()
+	defer sm.lock.Unlock()
+	sm.applyEvents()
+	chip, err := sm.topology.findChip(cardID, deviceID)
+	if err != nil {
+		return Chip{}, err
+	}
+	return *chip, nil
+}
+
+// applyEvents the events are sorted by time, and each event is applied once
+func (sm *SimManager) applyEvents() {
+	if sm.initTime.IsZero() {
+		return
+	}
+	elapsed := sm.now().Sub(sm.initTime)
+	for ; sm.applied < len(sm.topology.Events); sm.applied++ {
+		event := sm.topology.Events[sm.applied]
+		if time.Duration(event.AfterSeconds)*time.Second > elapsed {
+			return
+		}
+		chip, err := sm.topology.findChip(event.CardID, event.DeviceID)
+		if err != nil {
+			hwlog.RunLog.Warnf("skip simulated event, %v", err)
+			continue
+		}
+		chip.Health = event.Health
+		chip.ErrorCode = event.ErrorCode
+		hwlog.RunLog.Infof("simulated chip of cardID(%d) and deviceID(%d) changes, health: %d, error code: %d",
+			event.CardID, event.DeviceID, event.Health, event.ErrorCode)
+	}
+}
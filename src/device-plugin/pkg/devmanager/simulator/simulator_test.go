@@ -0,0 +1,188 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package simulator this for simulated npu driver without ascend hardware
+package simulator
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/smartystreets/goconvey/convey"
+
+	"huawei.com/mindx/common/hwlog"
+
+	"Ascend-device-plugin/pkg/devmanager/common"
+)
+
+const (
+	duoTopology = `
+cards:
+  - cardId: 1
+    productType: Atlas 300I Duo
+    chips:
+      - phyId: 0
+        chipType: Ascend
+        chipName: 310P3
+        chipVersion: V1
+        virtualDevices:
+          - vdevId: 100
+            template: vir02
+            aiCore: 2
+          - vdevId: 101
+            template: vir04
+            aiCore: 4
+      - phyId: 1
+        chipType: Ascend
+        chipName: 310P3
+        chipVersion: V1
+events:
+  - afterSeconds: 60
+    cardId: 1
+    deviceId: 1
+    health: 0
+    errorCode: 0
+  - afterSeconds: 30
+    cardId: 1
+    deviceId: 1
+    health: 3
+    errorCode: 2147483650
+`
+	jsonTopology = `{"cards": [{"cardId": 0, "productType": "Atlas 200I", "chips": [{"phyId": 3, ` +
+		`"chipName": "310B1"}]}]}`
+	eventAfterSeconds    = 30
+	eventRecoverSeconds  = 60
+	unhealthyState       = 3
+	simulatedErrorCode   = 2147483650
+	simulatedVDevNum     = 2
+	simulatedVDevAiCore  = 2
+	simulatedProductType = "Atlas 300I Duo"
+)
+
+func init() {
+	hwLogConfig := hwlog.LogConfig{
+		OnlyToStdout: true,
+	}
+	hwlog.InitRunLogger(&hwLogConfig, context.Background())
+}
+
+func writeTopology(t *testing.T, content string) string {
+	topologyPath := filepath.Join(t.TempDir(), "topology.yaml")
+	if err := os.WriteFile(topologyPath, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return topologyPath
+}
+
+// TestLoadTopology for test LoadTopology
+func TestLoadTopology(t *testing.T) {
+	convey.Convey("test LoadTopology", t, func() {
+		convey.Convey("load yaml topology success, events are sorted by time", func() {
+			topology, err := LoadTopology(writeTopology(t, duoTopology))
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(topology.DevType(), convey.ShouldEqual, common.Ascend310P)
+			convey.So(topology.Events[0].AfterSeconds, convey.ShouldEqual, eventAfterSeconds)
+		})
+		convey.Convey("load json topology success", func() {
+			topology, err := LoadTopology(writeTopology(t, jsonTopology))
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(topology.DevType(), convey.ShouldEqual, common.Ascend310B)
+		})
+		convey.Convey("unknown field is rejected", func() {
+			_, err := LoadTopology(writeTopology(t, `{"cards": [], "unknown": 1}`))
+			convey.So(err, convey.ShouldNotBeNil)
+		})
+		convey.Convey("duplicated phy id is rejected", func() {
+			_, err := LoadTopology(writeTopology(t, `{"cards": [{"cardId": 0, "productType": "Atlas 200I", `+
+				`"chips": [{"phyId": 0, "chipName": "310B1"}, {"phyId": 0, "chipName": "310B1"}]}]}`))
+			convey.So(err, convey.ShouldNotBeNil)
+		})
+		convey.Convey("invalid template name is rejected", func() {
+			_, err := LoadTopology(writeTopology(t, `{"cards": [{"cardId": 0, "productType": "Atlas 300I", `+
+				`"chips": [{"phyId": 0, "chipName": "310P3", "virtualDevices": [{"vdevId": 100, `+
+				`"template": "vir03", "aiCore": 3}]}]}]}`))
+			convey.So(err, convey.ShouldNotBeNil)
+		})
+		convey.Convey("event of not existed chip is rejected", func() {
+			_, err := LoadTopology(writeTopology(t, `{"cards": [{"cardId": 0, "productType": "Atlas 200I", `+
+				`"chips": [{"phyId": 0, "chipName": "310B1"}]}], "events": [{"cardId": 0, "deviceId": 1}]}`))
+			convey.So(err, convey.ShouldNotBeNil)
+		})
+	})
+}
+
+// TestSimManager for test the queries of SimManager
+func TestSimManager(t *testing.T) {
+	convey.Convey("test SimManager", t, func() {
+		topology, err := LoadTopology(writeTopology(t, duoTopology))
+		convey.So(err, convey.ShouldBeNil)
+		sm := NewSimManager(topology)
+		convey.So(sm.DcInit(), convey.ShouldBeNil)
+
+		num, logicIDs, err := sm.DcGetLogicIDList()
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(num, convey.ShouldEqual, len(logicIDs))
+		cardID, deviceID, err := sm.DcGetCardIDDeviceID(logicIDs[1])
+		convey.So(err, convey.ShouldBeNil)
+		logicID, err := sm.DcGetDeviceLogicID(cardID, deviceID)
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(logicID, convey.ShouldEqual, logicIDs[1])
+		phyID, err := sm.DcGetPhysicIDFromLogicID(logicIDs[1])
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(phyID, convey.ShouldEqual, 1)
+		productType, err := sm.DcGetProductType(cardID, deviceID)
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(productType, convey.ShouldEqual, simulatedProductType)
+
+		vDevInfo, err := sm.DcGetVDeviceInfo(logicIDs[0])
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(vDevInfo.TotalResource.VDevNum, convey.ShouldEqual, simulatedVDevNum)
+		convey.So(vDevInfo.VDevInfo[0].QueryInfo.Computing.Aic, convey.ShouldEqual, simulatedVDevAiCore)
+
+		_, _, err = sm.DcGetCardIDDeviceID(num)
+		convey.So(err, convey.ShouldNotBeNil)
+		_, err = sm.DcGetChipInfo(cardID, common.HiAIMaxDeviceNum)
+		convey.So(err, convey.ShouldNotBeNil)
+	})
+}
+
+// TestSimManagerEvents for test the scripted events are applied by time
+func TestSimManagerEvents(t *testing.T) {
+	convey.Convey("test the scripted events of SimManager", t, func() {
+		topology, err := LoadTopology(writeTopology(t, duoTopology))
+		convey.So(err, convey.ShouldBeNil)
+		current := time.Now()
+		sm := NewSimManager(topology)
+		sm.now = func() time.Time { return current }
+		convey.So(sm.DcInit(), convey.ShouldBeNil)
+		const cardID, deviceID = 1, 1
+
+		health, err := sm.DcGetDeviceHealth(cardID, deviceID)
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(health, convey.ShouldEqual, 0)
+
+		current = current.Add(eventAfterSeconds * time.Second)
+		health, err = sm.DcGetDeviceHealth(cardID, deviceID)
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(health, convey.ShouldEqual, unhealthyState)
+		errCount, errCode, err := sm.DcGetDeviceErrorCode(cardID, deviceID)
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(errCount, convey.ShouldEqual, 1)
+		convey.So(errCode, convey.ShouldEqual, simulatedErrorCode)
+
+		current = current.Add((eventRecoverSeconds - eventAfterSeconds) * time.Second)
+		health, err = sm.DcGetDeviceHealth(cardID, deviceID)
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(health, convey.ShouldEqual, 0)
+	})
+}
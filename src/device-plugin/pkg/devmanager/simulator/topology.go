@@ -0,0 +1,185 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package simulator this for simulated npu devices described by a topology file
+package simulator
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"huawei.com/mindx/common/fileutils"
+	"sigs.k8s.io/yaml"
+
+	"Ascend-device-plugin/pkg/devmanager/common"
+)
+
+const (
+	maxTopologyFileSize = 1024 * 1024
+	maxEventNum         = 1024
+	maxVirtualDevNum    = 16
+	maxEventAfterSecond = 7 * 24 * 3600
+)
+
+// Topology the simulated cards, chips and the scripted changes of the chips, in yaml or json
+type Topology struct {
+	Cards  []Card  `json:"cards"`
+	Events []Event `json:"events"`
+}
+
+// Card a simulated card, all chips in the card have the same product type
+type Card struct {
+	CardID      int32  `json:"cardId"`
+	ProductType string `json:"productType"`
+	Chips       []Chip `json:"chips"`
+}
+
+// Chip a simulated chip, the device id is the index of the chip in its card
+type Chip struct {
+	PhyID       int32        `json:"phyId"`
+	ChipType    string       `json:"chipType"`
+	ChipName    string       `json:"chipName"`
+	ChipVersion string       `json:"chipVersion"`
+	Health      int32        `json:"health"`
+	ErrorCode   int64        `json:"errorCode"`
+	VirtualDevs []VirtualDev `json:"virtualDevices"`
+}
+
+// VirtualDev a virtual device created on the chip
+type VirtualDev struct {
+	VDevID   uint32  `json:"vdevId"`
+	Template string  `json:"template"`
+	AiCore   float32 `json:"aiCore"`
+}
+
+// Event changes the health and error code of a chip when the time after the driver init is reached
+type Event struct {
+	AfterSeconds int64 `json:"afterSeconds"`
+	CardID       int32 `json:"cardId"`
+	DeviceID     int32 `json:"deviceId"`
+	Health       int32 `json:"health"`
+	ErrorCode    int64 `json:"errorCode"`
+}
+
+// LoadTopology load and check the topology file
+func LoadTopology(topologyPath string) (*Topology, error) {
+	content, err := fileutils.ReadLimitBytes(topologyPath, maxTopologyFileSize)
+	if err != nil {
+		return nil, fmt.Errorf("read topology file failed, error: %v", err)
+	}
+	var topology Topology
+	if err = yaml.UnmarshalStrict(content, &topology); err != nil {
+		return nil, fmt.Errorf("parse topology file failed, error: %v", err)
+	}
+	if err = topology.check(); err != nil {
+		return nil, fmt.Errorf("check topology failed, error: %v", err)
+	}
+	sort.SliceStable(topology.Events, func(i, j int) bool {
+		return topology.Events[i].AfterSeconds < topology.Events[j].AfterSeconds
+	})
+	return &topology, nil
+}
+
+// DevType the device type of the chips, the chips in one topology are the same type
+func (t *Topology) DevType() string {
+	if len(t.Cards) == 0 || len(t.Cards[0].Chips) == 0 {
+		return ""
+	}
+	return common.GetDeviceTypeByChipName(t.Cards[0].Chips[0].ChipName)
+}
+
+func (t *Topology) check() error {
+	if len(t.Cards) == 0 || len(t.Cards) > common.HiAIMaxCardNum {
+		return fmt.Errorf("card num %d is out of range [1, %d]", len(t.Cards), common.HiAIMaxCardNum)
+	}
+	if len(t.Events) > maxEventNum {
+		return fmt.Errorf("event num %d exceeds the upper limit %d", len(t.Events), maxEventNum)
+	}
+	devType := t.DevType()
+	if devType == "" {
+		return errors.New("unknown device type of the chips")
+	}
+	cardIDs := make(map[int32]struct{}, len(t.Cards))
+	phyIDs := make(map[int32]struct{}, len(t.Cards)*common.HiAIMaxDeviceNum)
+	for _, card := range t.Cards {
+		if _, exist := cardIDs[card.CardID]; exist || !common.IsValidCardID(card.CardID) {
+			return fmt.Errorf("card id %d is invalid or duplicated", card.CardID)
+		}
+		cardIDs[card.CardID] = struct{}{}
+		if err := card.check(devType, phyIDs); err != nil {
+			return fmt.Errorf("card %d is invalid, %v", card.CardID, err)
+		}
+	}
+	for _, event := range t.Events {
+		if event.AfterSeconds < 0 || event.AfterSeconds > maxEventAfterSecond {
+			return fmt.Errorf("event time %d is out of range [0, %d]", event.AfterSeconds, maxEventAfterSecond)
+		}
+		if _, err := t.findChip(event.CardID, event.DeviceID); err != nil {
+			return fmt.Errorf("invalid event, %v", err)
+		}
+	}
+	return nil
+}
+
+func (c *Card) check(devType string, phyIDs map[int32]struct{}) error {
+	if c.ProductType == "" {
+		return errors.New("product type is empty")
+	}
+	if !common.IsValidDevNumInCard(int32(len(c.Chips))) {
+		return fmt.Errorf("chip num %d is out of range [1, %d]", len(c.Chips), common.HiAIMaxDeviceNum)
+	}
+	for _, chip := range c.Chips {
+		if chipType := common.GetDeviceTypeByChipName(chip.ChipName); chipType != devType {
+			return fmt.Errorf("chip name %s does not match the device type %s", chip.ChipName, devType)
+		}
+		if _, exist := phyIDs[chip.PhyID]; exist || !common.IsValidLogicIDOrPhyID(chip.PhyID) {
+			return fmt.Errorf("phy id %d is invalid or duplicated", chip.PhyID)
+		}
+		phyIDs[chip.PhyID] = struct{}{}
+		if err := chip.checkVirtualDevs(devType); err != nil {
+			return fmt.Errorf("chip %d is invalid, %v", chip.PhyID, err)
+		}
+	}
+	return nil
+}
+
+func (c *Chip) checkVirtualDevs(devType string) error {
+	if len(c.VirtualDevs) > maxVirtualDevNum {
+		return fmt.Errorf("virtual device num %d exceeds the upper limit %d", len(c.VirtualDevs), maxVirtualDevNum)
+	}
+	vDevIDs := make(map[uint32]struct{}, len(c.VirtualDevs))
+	for _, vDev := range c.VirtualDevs {
+		if _, exist := vDevIDs[vDev.VDevID]; exist {
+			return fmt.Errorf("virtual device id %d is duplicated", vDev.VDevID)
+		}
+		vDevIDs[vDev.VDevID] = struct{}{}
+		if !common.IsValidTemplateName(devType, vDev.Template) {
+			return fmt.Errorf("template name %s of virtual device %d is invalid", vDev.Template, vDev.VDevID)
+		}
+		if vDev.AiCore <= 0 {
+			return fmt.Errorf("ai core of virtual device %d must be positive", vDev.VDevID)
+		}
+	}
+	return nil
+}
+
+func (t *Topology) findChip(cardID, deviceID int32) (*Chip, error) {
+	for i := range t.Cards {
+		if t.Cards[i].CardID != cardID {
+			continue
+		}
+		if deviceID < 0 || deviceID >= int32(len(t.Cards[i].Chips)) {
+			break
+		}
+		return &t.Cards[i].Chips[deviceID], nil
+	}
+	return nil, fmt.Errorf("chip of cardID(%d) and deviceID(%d) is not found", cardID, deviceID)
+}
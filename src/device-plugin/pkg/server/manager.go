@@ -124,10 +124,16 @@ func (hdm *HwDevManager) updateDeviceHealth(curAllDevs []common.NpuDevice) {
 	}
 }
 
-// ListenDevice ListenDevice coroutine
+// ListenDevice ListenDevice coroutine, it returns after the serve coroutine stops
 func (hdm *HwDevManager) ListenDevice(ctx context.Context) {
 	hwlog.RunLog.Info("starting the listen device")
-	go hdm.Serve(ctx)
+	serveDone := make(chan struct{})
+	go func() {
+		defer close(serveDone)
+		hdm.Serve(ctx)
+	}()
+	defer func() { <-serveDone }()
+	period := time.Duration(common.ParamOption.ListAndWatchPeriod) * time.Second
 	for {
 		select {
 		case _, ok := <-ctx.Done():
@@ -137,7 +143,7 @@ func (hdm *HwDevManager) ListenDevice(ctx context.Context) {
 			hwlog.RunLog.Info("listen device stop")
 			return
 		default:
-			time.Sleep(time.Duration(common.ParamOption.ListAndWatchPeriod) * time.Second)
+			time.Sleep(period)
 			common.LockAllDeviceInfo()
 			hdm.notifyToK8s()
 			common.UnlockAllDeviceInfo()
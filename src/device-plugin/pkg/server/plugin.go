@@ -65,9 +65,12 @@ func (ps *PluginServer) deepCopyDevice(cachedDevices []*common.NpuDevice) {
 	ps.cachedDevices = ps.cachedDevices[:0]
 	for _, dev := range cachedDevices {
 		ps.cachedDevices = append(ps.cachedDevices, common.NpuDevice{
+			DevType:    dev.DevType,
 			DeviceName: dev.DeviceName,
 			Health:     dev.Health,
+			LogicID:    dev.LogicID,
 			PhyID:      dev.PhyID,
+			CardID:     dev.CardID,
 		})
 	}
 	ps.cachedLock.Unlock()
@@ -206,16 +209,31 @@ func (ps *PluginServer) Allocate(ctx context.Context, requests *v1beta1.Allocate
 	return resps, nil
 }
 
-// GetPreferredAllocation implement the kubelet device plugin interface
-func (ps *PluginServer) GetPreferredAllocation(context.Context, *v1beta1.PreferredAllocationRequest) (
+// GetPreferredAllocation is called by kubelet to choose the devices which are kept together on the cards and chips
+func (ps *PluginServer) GetPreferredAllocation(ctx context.Context, requests *v1beta1.PreferredAllocationRequest) (
 	*v1beta1.PreferredAllocationResponse, error) {
-	return nil, fmt.Errorf("not support")
+	if err := ps.checkPreferredAllocationRequest(requests); err != nil {
+		hwlog.RunLog.Error(err)
+		return nil, err
+	}
+	resps := new(v1beta1.PreferredAllocationResponse)
+	for _, rqt := range requests.ContainerRequests {
+		deviceIDs := ps.preferDevices(rqt)
+		hwlog.RunLog.Infof("preferred allocation: %v, available: %v", deviceIDs, rqt.AvailableDeviceIDs)
+		resps.ContainerResponses = append(resps.ContainerResponses,
+			&v1beta1.ContainerPreferredAllocationResponse{DeviceIDs: deviceIDs})
+	}
+	return resps, nil
 }
 
 // GetDevicePluginOptions is Standard interface to kubelet.
 func (ps *PluginServer) GetDevicePluginOptions(ctx context.Context, e *v1beta1.Empty) (*v1beta1.DevicePluginOptions,
 	error) {
-	return &v1beta1.DevicePluginOptions{}, nil
+	return devicePluginOptions(), nil
+}
+
+func devicePluginOptions() *v1beta1.DevicePluginOptions {
+	return &v1beta1.DevicePluginOptions{GetPreferredAllocationAvailable: true}
 }
 
 // PreStartContainer is Standard interface to kubelet with empty implement.
@@ -0,0 +1,168 @@
+/* Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+   MEF is licensed under Mulan PSL v2.
+   You can use this software according to the terms and conditions of the Mulan PSL v2.
+   You may obtain a copy of Mulan PSL v2 at:
+            http://license.coscl.org.cn/MulanPSL2
+   THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+   EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+   MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+   See the Mulan PSL v2 for more details.
+*/
+
+// Package server holds the implementation of registration to kubelet, k8s device plugin interface and grpc service.
+package server
+
+import (
+	"fmt"
+	"sort"
+
+	"k8s.io/kubelet/pkg/apis/deviceplugin/v1beta1"
+
+	"Ascend-device-plugin/pkg/common"
+	"Ascend-device-plugin/pkg/device"
+)
+
+// devGroup the available devices which should be allocated together, the physical devices on one card
+// or the virtual devices on one chip
+type devGroup struct {
+	key     string
+	devices []string
+}
+
+func (ps *PluginServer) checkPreferredAllocationRequest(requests *v1beta1.PreferredAllocationRequest) error {
+	if requests == nil {
+		return fmt.Errorf("invalid requests")
+	}
+	if len(requests.ContainerRequests) > common.MaxContainerLimit {
+		return fmt.Errorf("the number of container request %d exceeds the upper limit",
+			len(requests.ContainerRequests))
+	}
+	for _, rqt := range requests.ContainerRequests {
+		if len(rqt.AvailableDeviceIDs) > common.MaxDevicesNum*common.MinAICoreNum {
+			return fmt.Errorf("the available devices can't bigger than %d", common.MaxDevicesNum*common.MinAICoreNum)
+		}
+		if rqt.AllocationSize <= 0 || int(rqt.AllocationSize) > len(rqt.AvailableDeviceIDs) {
+			return fmt.Errorf("allocation size %d is invalid", rqt.AllocationSize)
+		}
+		for _, deviceName := range rqt.AvailableDeviceIDs {
+			if len(deviceName) > common.MaxDeviceNameLen {
+				return fmt.Errorf("length of device name %d is invalid", len(deviceName))
+			}
+		}
+	}
+	return nil
+}
+
+// preferDevices choose the devices from the available ones. The devices in the same group with the must include
+// devices are chosen first, then the groups on the healthy cards and the degraded cards at last. The groups in
+// the same tier are chosen by best fit, so the cards and chips are kept together as much as possible
+func (ps *PluginServer) preferDevices(rqt *v1beta1.ContainerPreferredAllocationRequest) []string {
+	size := int(rqt.AllocationSize)
+	chosen := make([]string, 0, size)
+	chosenSet := make(map[string]struct{}, size)
+	for _, deviceName := range rqt.MustIncludeDeviceIDs {
+		if _, exist := chosenSet[deviceName]; exist || len(chosen) >= size {
+			continue
+		}
+		chosen = append(chosen, deviceName)
+		chosenSet[deviceName] = struct{}{}
+	}
+
+	devices := ps.cachedDeviceMap()
+	unHealthyCards := ps.unHealthyCards()
+	usedGroups := make(map[string]struct{}, len(chosen))
+	for _, deviceName := range chosen {
+		if dev, exist := devices[deviceName]; exist {
+			usedGroups[groupKey(dev)] = struct{}{}
+		}
+	}
+	var usedTier, healthyTier, degradedTier, unknownTier []*devGroup
+	groups := make(map[string]*devGroup, len(rqt.AvailableDeviceIDs))
+	for _, deviceName := range rqt.AvailableDeviceIDs {
+		if _, exist := chosenSet[deviceName]; exist {
+			continue
+		}
+		dev, exist := devices[deviceName]
+		if !exist {
+			unknownTier = append(unknownTier, &devGroup{key: deviceName, devices: []string{deviceName}})
+			continue
+		}
+		key := groupKey(dev)
+		if group, exist := groups[key]; exist {
+			group.devices = append(group.devices, deviceName)
+			continue
+		}
+		group := &devGroup{key: key, devices: []string{deviceName}}
+		groups[key] = group
+		if _, used := usedGroups[key]; used {
+			usedTier = append(usedTier, group)
+		} else if _, unHealthy := unHealthyCards[dev.CardID]; unHealthy {
+			degradedTier = append(degradedTier, group)
+		} else {
+			healthyTier = append(healthyTier, group)
+		}
+	}
+	for _, tier := range [][]*devGroup{usedTier, healthyTier, degradedTier, unknownTier} {
+		chosen = chooseFromGroups(tier, chosen, size)
+	}
+	return chosen
+}
+
+// chooseFromGroups the smallest group which could meet the need is chosen, or the largest group is used up
+func chooseFromGroups(groups []*devGroup, chosen []string, size int) []string {
+	for _, group := range groups {
+		sort.Strings(group.devices)
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		if len(groups[i].devices) != len(groups[j].devices) {
+			return len(groups[i].devices) < len(groups[j].devices)
+		}
+		return groups[i].key < groups[j].key
+	})
+	for len(groups) > 0 && len(chosen) < size {
+		need := size - len(chosen)
+		index := len(groups) - 1
+		for i, group := range groups {
+			if len(group.devices) >= need {
+				index = i
+				break
+			}
+		}
+		group := groups[index]
+		if len(group.devices) > need {
+			chosen = append(chosen, group.devices[:need]...)
+			return chosen
+		}
+		chosen = append(chosen, group.devices...)
+		groups = append(groups[:index], groups[index+1:]...)
+	}
+	return chosen
+}
+
+// groupKey the virtual devices are grouped by chip, and the physical devices are grouped by card
+func groupKey(dev common.NpuDevice) string {
+	if common.IsVirtualDev(dev.DevType) {
+		return fmt.Sprintf("chip-%d", dev.PhyID)
+	}
+	return fmt.Sprintf("card-%d", dev.CardID)
+}
+
+func (ps *PluginServer) cachedDeviceMap() map[string]common.NpuDevice {
+	ps.cachedLock.RLock()
+	defer ps.cachedLock.RUnlock()
+	devices := make(map[string]common.NpuDevice, len(ps.cachedDevices))
+	for _, dev := range ps.cachedDevices {
+		devices[dev.DeviceName] = dev
+	}
+	return devices
+}
+
+func (ps *PluginServer) unHealthyCards() map[int32]interface{} {
+	ps.cachedLock.RLock()
+	defer ps.cachedLock.RUnlock()
+	devices := make([]*common.NpuDevice, 0, len(ps.cachedDevices))
+	for i := range ps.cachedDevices {
+		devices = append(devices, &ps.cachedDevices[i])
+	}
+	return device.GetUnHealthyCard(devices)
+}
@@ -131,6 +131,8 @@ func (ps *PluginServer) register() error {
 		Version:      v1beta1.Version,
 		Endpoint:     fmt.Sprintf("%s.sock", ps.deviceType),
 		ResourceName: common.ResourceNamePrefix + ps.deviceType,
+		// kubelet only asks for the preferred allocation when it is advertised at registration
+		Options: devicePluginOptions(),
 	}
 	if _, err = client.Register(context.Background(), reqt); err != nil {
 		return fmt.Errorf("register to kubelet fail: %#v", err)
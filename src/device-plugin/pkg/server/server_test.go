@@ -13,14 +13,25 @@
 package server
 
 import (
+	"context"
 	"testing"
 
 	"github.com/smartystreets/goconvey/convey"
 	"google.golang.org/grpc"
+	"k8s.io/kubelet/pkg/apis/deviceplugin/v1beta1"
+
+	"huawei.com/mindx/common/hwlog"
 
 	"Ascend-device-plugin/pkg/common"
 )
 
+func init() {
+	hwLogConfig := hwlog.LogConfig{
+		OnlyToStdout: true,
+	}
+	hwlog.InitRunLogger(&hwLogConfig, context.Background())
+}
+
 // TestPluginServerGetRestartFlag Test PluginServer GetRestartFlag()
 func TestPluginServerGetRestartFlag(t *testing.T) {
 	convey.Convey("test GetRestartFlag", t, func() {
@@ -49,3 +60,82 @@ func TestPluginServerStop(t *testing.T) {
 		convey.So(ps.isRunning.Load(), convey.ShouldBeFalse)
 	})
 }
+
+func newPreferredRequest(size int32, available, mustInclude []string) *v1beta1.PreferredAllocationRequest {
+	return &v1beta1.PreferredAllocationRequest{ContainerRequests: []*v1beta1.ContainerPreferredAllocationRequest{{
+		AvailableDeviceIDs:   available,
+		MustIncludeDeviceIDs: mustInclude,
+		AllocationSize:       size,
+	}}}
+}
+
+func getPreferredDevices(ps *PluginServer, rqt *v1beta1.PreferredAllocationRequest) []string {
+	resp, err := ps.GetPreferredAllocation(context.Background(), rqt)
+	convey.So(err, convey.ShouldBeNil)
+	convey.So(len(resp.ContainerResponses), convey.ShouldEqual, 1)
+	return resp.ContainerResponses[0].DeviceIDs
+}
+
+// TestGetPreferredAllocation Test PluginServer GetPreferredAllocation() with physical devices
+func TestGetPreferredAllocation(t *testing.T) {
+	convey.Convey("test GetPreferredAllocation", t, func() {
+		// Ascend310P-0 of card 0 is allocated, Ascend310P-5 of card 2 is unhealthy
+		devices := []*common.NpuDevice{
+			{DevType: common.Ascend310P, DeviceName: "Ascend310P-0", Health: v1beta1.Healthy, PhyID: 0, CardID: 0},
+			{DevType: common.Ascend310P, DeviceName: "Ascend310P-1", Health: v1beta1.Healthy, PhyID: 1, CardID: 0},
+			{DevType: common.Ascend310P, DeviceName: "Ascend310P-2", Health: v1beta1.Healthy, PhyID: 2, CardID: 1},
+			{DevType: common.Ascend310P, DeviceName: "Ascend310P-3", Health: v1beta1.Healthy, PhyID: 3, CardID: 1},
+			{DevType: common.Ascend310P, DeviceName: "Ascend310P-4", Health: v1beta1.Healthy, PhyID: 4, CardID: 2},
+			{DevType: common.Ascend310P, DeviceName: "Ascend310P-5", Health: v1beta1.Unhealthy, PhyID: 5, CardID: 2},
+		}
+		ps := NewPluginServer(common.Ascend310P, devices, nil, nil)
+		available := []string{"Ascend310P-4", "Ascend310P-3", "Ascend310P-2", "Ascend310P-1"}
+
+		convey.Convey("the card partly allocated is preferred for one chip", func() {
+			deviceIDs := getPreferredDevices(ps, newPreferredRequest(1, available, nil))
+			convey.So(deviceIDs, convey.ShouldResemble, []string{"Ascend310P-1"})
+		})
+		convey.Convey("the chips on the same card are kept together", func() {
+			deviceIDs := getPreferredDevices(ps, newPreferredRequest(2, available, nil))
+			convey.So(deviceIDs, convey.ShouldResemble, []string{"Ascend310P-2", "Ascend310P-3"})
+		})
+		convey.Convey("the sibling of the must include device is preferred", func() {
+			deviceIDs := getPreferredDevices(ps, newPreferredRequest(2, available, []string{"Ascend310P-3"}))
+			convey.So(deviceIDs, convey.ShouldResemble, []string{"Ascend310P-3", "Ascend310P-2"})
+		})
+		convey.Convey("the card with unhealthy sibling is chosen at last", func() {
+			deviceIDs := getPreferredDevices(ps, newPreferredRequest(4, available, nil))
+			convey.So(deviceIDs[len(deviceIDs)-1], convey.ShouldEqual, "Ascend310P-4")
+		})
+		convey.Convey("invalid allocation size", func() {
+			_, err := ps.GetPreferredAllocation(context.Background(), newPreferredRequest(5, available, nil))
+			convey.So(err, convey.ShouldNotBeNil)
+		})
+	})
+}
+
+// TestGetPreferredAllocationVirtual Test PluginServer GetPreferredAllocation() with virtual devices
+func TestGetPreferredAllocationVirtual(t *testing.T) {
+	convey.Convey("test GetPreferredAllocation with virtual devices", t, func() {
+		const vDevType = common.Ascend310P + "-2c"
+		devices := []*common.NpuDevice{
+			{DevType: vDevType, DeviceName: "Ascend310P-2c-100-0", Health: v1beta1.Healthy, PhyID: 0},
+			{DevType: vDevType, DeviceName: "Ascend310P-2c-101-0", Health: v1beta1.Healthy, PhyID: 0},
+			{DevType: vDevType, DeviceName: "Ascend310P-2c-102-1", Health: v1beta1.Healthy, PhyID: 1},
+		}
+		ps := NewPluginServer(vDevType, devices, nil, nil)
+		available := []string{"Ascend310P-2c-100-0", "Ascend310P-2c-101-0", "Ascend310P-2c-102-1"}
+		deviceIDs := getPreferredDevices(ps, newPreferredRequest(1, available, nil))
+		convey.So(deviceIDs, convey.ShouldResemble, []string{"Ascend310P-2c-102-1"})
+	})
+}
+
+// TestGetDevicePluginOptions Test PluginServer GetDevicePluginOptions()
+func TestGetDevicePluginOptions(t *testing.T) {
+	convey.Convey("test GetDevicePluginOptions", t, func() {
+		ps := &PluginServer{}
+		options, err := ps.GetDevicePluginOptions(context.Background(), &v1beta1.Empty{})
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(options.GetPreferredAllocationAvailable, convey.ShouldBeTrue)
+	})
+}
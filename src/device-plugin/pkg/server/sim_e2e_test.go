@@ -0,0 +1,199 @@
+/* Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+   MEF is licensed under Mulan PSL v2.
+   You can use this software according to the terms and conditions of the Mulan PSL v2.
+   You may obtain a copy of Mulan PSL v2 at:
+            http://license.coscl.org.cn/MulanPSL2
+   THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+   EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+   MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+   See the Mulan PSL v2 for more details.
+*/
+
+// Package server holds the implementation of registration to kubelet, k8s device plugin interface and grpc service.
+package server
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/smartystreets/goconvey/convey"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"k8s.io/kubelet/pkg/apis/deviceplugin/v1beta1"
+
+	"Ascend-device-plugin/pkg/common"
+	"Ascend-device-plugin/pkg/devmanager"
+)
+
+// the topology of one card with two chips, the chip with phy id 1 turns unhealthy 2 seconds after started
+const (
+	simTopology = `
+cards:
+  - cardId: 0
+    productType: Atlas 300I Pro
+    chips:
+      - phyId: 0
+        chipName: 310P3
+      - phyId: 1
+        chipName: 310P3
+events:
+  - afterSeconds: 2
+    cardId: 0
+    deviceId: 1
+    health: 3
+    errorCode: 2147483650
+`
+	simResourceName  = common.ResourceNamePrefix + common.Ascend310P
+	simE2eTimeout    = 30 * time.Second
+	simWatchPeriod   = 1
+	simAllocDevice   = "Ascend310P-0"
+	simAllocHostPath = "/dev/davinci0"
+	simUnhealthyDev  = "Ascend310P-1"
+)
+
+// stubKubelet serves the registration service of kubelet on a unix socket
+type stubKubelet struct {
+	v1beta1.UnimplementedRegistrationServer
+	registered chan *v1beta1.RegisterRequest
+}
+
+// Register records the registration of the device plugin
+func (k *stubKubelet) Register(_ context.Context, req *v1beta1.RegisterRequest) (*v1beta1.Empty, error) {
+	k.registered <- req
+	return &v1beta1.Empty{}, nil
+}
+
+func startStubKubelet(socketPath string) (*stubKubelet, *grpc.Server, error) {
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	kubelet := &stubKubelet{registered: make(chan *v1beta1.RegisterRequest, 1)}
+	grpcServer := grpc.NewServer()
+	v1beta1.RegisterRegistrationServer(grpcServer, kubelet)
+	go func() {
+		if err := grpcServer.Serve(listener); err != nil {
+			panic(err)
+		}
+	}()
+	return kubelet, grpcServer, nil
+}
+
+// patchPluginDir redirects the device plugin dir of kubelet to dir
+func patchPluginDir(dir string) *gomonkey.Patches {
+	redirect := func(path string) string {
+		if filepath.Clean(path) == filepath.Clean(v1beta1.DevicePluginPath) {
+			return dir
+		}
+		if path == v1beta1.KubeletSocket {
+			return filepath.Join(dir, filepath.Base(v1beta1.KubeletSocket))
+		}
+		return path
+	}
+	return gomonkey.ApplyFunc(common.VerifyPathAndPermission, func(path string) (string, bool) {
+		return redirect(path), true
+	}).ApplyFunc(os.Stat, func(path string) (os.FileInfo, error) {
+		return os.Lstat(redirect(path))
+	})
+}
+
+func dialPlugin(socketPath string) (*grpc.ClientConn, error) {
+	return grpc.Dial("unix://"+socketPath, grpc.WithTransportCredentials(insecure.NewCredentials()))
+}
+
+func newAllocateRequest(deviceIDs ...string) *v1beta1.AllocateRequest {
+	return &v1beta1.AllocateRequest{ContainerRequests: []*v1beta1.ContainerAllocateRequest{{DevicesIDs: deviceIDs}}}
+}
+
+func recvDevices(stream v1beta1.DevicePlugin_ListAndWatchClient) map[string]string {
+	resp, err := stream.Recv()
+	convey.So(err, convey.ShouldBeNil)
+	devices := make(map[string]string, len(resp.Devices))
+	for _, dev := range resp.Devices {
+		devices[dev.ID] = dev.Health
+	}
+	return devices
+}
+
+// TestSimE2e starts the device plugin with simulated devices, registers it to a stub kubelet, then calls the
+// device plugin service as kubelet does
+func TestSimE2e(t *testing.T) {
+	if os.Getuid() != common.RootUID {
+		t.Skip("the device plugin socket is owned by root, skip the e2e test")
+	}
+	dir := t.TempDir()
+	topologyPath := filepath.Join(dir, "topology.yaml")
+	if err := os.WriteFile(topologyPath, []byte(simTopology), 0600); err != nil {
+		t.Fatal(err)
+	}
+	oldOption := common.ParamOption
+	common.ParamOption = common.Option{ListAndWatchPeriod: simWatchPeriod, ShareCount: 1, SimMode: true}
+	defer func() { common.ParamOption = oldOption }()
+
+	convey.Convey("test the device plugin with simulated devices", t, func() {
+		patches := patchPluginDir(dir)
+		defer patches.Reset()
+		kubelet, kubeletServer, err := startStubKubelet(filepath.Join(dir, filepath.Base(v1beta1.KubeletSocket)))
+		convey.So(err, convey.ShouldBeNil)
+		defer kubeletServer.Stop()
+
+		devM, err := devmanager.SimInit(topologyPath)
+		convey.So(err, convey.ShouldBeNil)
+		hdm := NewHwDevManager(devM)
+		convey.So(hdm, convey.ShouldNotBeNil)
+		ctx, cancel := context.WithCancel(context.Background())
+		listenDone := make(chan struct{})
+		go func() {
+			defer close(listenDone)
+			hdm.ListenDevice(ctx)
+		}()
+		// the option is restored only after the coroutines reading it stop
+		defer func() {
+			cancel()
+			<-listenDone
+			hdm.stopAllSever()
+		}()
+
+		var req *v1beta1.RegisterRequest
+		select {
+		case req = <-kubelet.registered:
+		case <-time.After(simE2eTimeout):
+			t.Fatal("the device plugin is not registered to kubelet")
+		}
+		convey.So(req.ResourceName, convey.ShouldEqual, simResourceName)
+		convey.So(req.Options.GetPreferredAllocationAvailable, convey.ShouldBeTrue)
+
+		conn, err := dialPlugin(filepath.Join(dir, req.Endpoint))
+		convey.So(err, convey.ShouldBeNil)
+		defer conn.Close()
+		client := v1beta1.NewDevicePluginClient(conn)
+		stream, err := client.ListAndWatch(ctx, &v1beta1.Empty{})
+		convey.So(err, convey.ShouldBeNil)
+		devices := recvDevices(stream)
+		convey.So(devices, convey.ShouldResemble,
+			map[string]string{simAllocDevice: v1beta1.Healthy, simUnhealthyDev: v1beta1.Healthy})
+
+		resp, err := client.Allocate(ctx, newAllocateRequest(simAllocDevice))
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(len(resp.ContainerResponses), convey.ShouldEqual, 1)
+		var hostPaths []string
+		for _, dev := range resp.ContainerResponses[0].Devices {
+			hostPaths = append(hostPaths, dev.HostPath)
+		}
+		convey.So(hostPaths, convey.ShouldContain, simAllocHostPath)
+		convey.So(hostPaths, convey.ShouldContain, common.HiAIManagerDevice)
+
+		_, err = client.Allocate(ctx, newAllocateRequest("Ascend310P-7"))
+		convey.So(err, convey.ShouldNotBeNil)
+
+		// the scripted event is reported to kubelet by ListAndWatch
+		devices = recvDevices(stream)
+		convey.So(devices[simUnhealthyDev], convey.ShouldEqual, v1beta1.Unhealthy)
+		convey.So(devices[simAllocDevice], convey.ShouldEqual, v1beta1.Healthy)
+	})
+}
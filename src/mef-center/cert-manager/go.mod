@@ -6,6 +6,7 @@ require (
 	github.com/agiledragon/gomonkey/v2 v2.8.0
 	github.com/gin-gonic/gin v1.10.0
 	github.com/smartystreets/goconvey v1.7.2
+	golang.org/x/crypto v0.23.0
 	huawei.com/mindx/common/backuputils v0.0.1
 	huawei.com/mindx/common/checker v0.0.2
 	huawei.com/mindx/common/fileutils v0.0.14
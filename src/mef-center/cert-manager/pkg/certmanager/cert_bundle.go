@@ -0,0 +1,114 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package certmanager import and export root ca in pkcs12 or jks format
+package certmanager
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"huawei.com/mindx/common/hwlog"
+	"huawei.com/mindx/common/x509"
+
+	"huawei.com/mindxedge/base/common"
+
+	"cert-manager/pkg/certmanager/certchecker"
+)
+
+const bundleFileName = "root"
+
+var bundleFileSuffixes = map[string]string{
+	x509.BundleFormatPkcs12: ".p12",
+	x509.BundleFormatJks:    ".jks",
+}
+
+// convertImportBundle converts the pkcs12 or jks bundle in request to pem, which is checked and saved as before
+func convertImportBundle(req *importCertReq) error {
+	password := []byte(req.Password)
+	req.Password = ""
+	defer x509.PaddingAndCleanSlice(password)
+	if req.Format == "" || req.Format == x509.BundleFormatPem {
+		return nil
+	}
+	if checkResult := certchecker.NewImportBundleChecker().Check(exportBundleReq{Format: req.Format,
+		Password: string(password)}); !checkResult.Result {
+		return fmt.Errorf("%s", checkResult.Reason)
+	}
+	content, err := base64.StdEncoding.DecodeString(req.Cert)
+	if err != nil {
+		return fmt.Errorf("base64 decode ca content failed: %v", err)
+	}
+	caMgr, err := x509.NewCaChainMgrFromBundle(content, req.Format, password)
+	if err != nil {
+		return err
+	}
+	req.Cert = base64.StdEncoding.EncodeToString(caMgr.ExportPem())
+	req.Format = x509.BundleFormatPem
+	return nil
+}
+
+// ExportRootCaBundle export root ca in pkcs12 or jks format protected by password
+func ExportRootCaBundle(c *gin.Context) {
+	hwlog.RunLog.Info("export cert bundle start")
+	var req exportBundleReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		hwlog.RunLog.Errorf("parse export bundle request failed: %v", err)
+		common.ConstructResp(c, common.ErrorParamConvert, "parse request failed", nil)
+		return
+	}
+	password := []byte(req.Password)
+	req.Password = ""
+	defer x509.PaddingAndCleanSlice(password)
+	if checkResult := certchecker.NewExportBundleChecker().Check(exportBundleReq{Format: req.Format,
+		Password: string(password)}); !checkResult.Result {
+		hwlog.RunLog.Errorf("export bundle para check failed: %s", checkResult.Reason)
+		common.ConstructResp(c, common.ErrorParamInvalid, checkResult.Reason, nil)
+		return
+	}
+	bundle, err := exportRootCaBundle(req.CertName, req.Format, password)
+	if err != nil {
+		hwlog.RunLog.Error(err)
+		common.ConstructResp(c, common.ErrorExportRootCa, err.Error(), nil)
+		return
+	}
+	c.Writer.WriteHeader(http.StatusOK)
+	c.Header(common.ContentType, "application/octet-stream")
+	c.Header(common.ContentDisposition, fmt.Sprintf("attachment; filename=%s%s", bundleFileName,
+		bundleFileSuffixes[req.Format]))
+	c.Writer.WriteHeaderNow()
+	if _, err = c.Writer.Write(bundle); err != nil {
+		hwlog.RunLog.Errorf("export cert [%s] root ca bundle failed, error: %v", req.CertName, err)
+		return
+	}
+	c.Writer.Flush()
+	hwlog.RunLog.Infof("export cert [%s] root ca in %s format success", req.CertName, req.Format)
+}
+
+func exportRootCaBundle(certName, format string, password []byte) ([]byte, error) {
+	caBytes, err := loadExportRootCa(certName)
+	if err != nil {
+		return nil, err
+	}
+	caMgr, err := x509.NewCaChainMgr(caBytes)
+	if err != nil {
+		hwlog.RunLog.Errorf("parse cert [%s] root ca failed, error: %v", certName, err)
+		return nil, fmt.Errorf("parse cert [%s] root ca failed", certName)
+	}
+	bundle, err := caMgr.ExportBundle(format, password)
+	if err != nil {
+		hwlog.RunLog.Errorf("convert cert [%s] root ca to %s failed, error: %v", certName, format, err)
+		return nil, fmt.Errorf("convert cert [%s] root ca to %s failed", certName, format)
+	}
+	return bundle, nil
+}
@@ -0,0 +1,114 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package certmanager
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/smartystreets/goconvey/convey"
+
+	"huawei.com/mindx/common/x509"
+
+	"huawei.com/mindxedge/base/common"
+)
+
+const testBundlePassword = "Test@1234"
+
+func getTestPemCa() []byte {
+	content, err := base64.StdEncoding.DecodeString(testCrt)
+	if err != nil {
+		panic(err)
+	}
+	caMgr, err := x509.NewCaChainMgr(content)
+	if err != nil {
+		panic(err)
+	}
+	return caMgr.ExportPem()
+}
+
+func getTestBundle(format string) []byte {
+	caMgr, err := x509.NewCaChainMgr(getTestPemCa())
+	if err != nil {
+		panic(err)
+	}
+	bundle, err := caMgr.ExportBundle(format, []byte(testBundlePassword))
+	if err != nil {
+		panic(err)
+	}
+	return bundle
+}
+
+func TestConvertImportBundle(t *testing.T) {
+	convey.Convey("case: convert pkcs12 and jks success", t, func() {
+		for _, format := range []string{x509.BundleFormatPkcs12, x509.BundleFormatJks} {
+			req := importCertReq{CertName: common.SoftwareCertName, Format: format, Password: testBundlePassword,
+				Cert: base64.StdEncoding.EncodeToString(getTestBundle(format))}
+			convey.So(convertImportBundle(&req), convey.ShouldBeNil)
+			convey.So(req.Password, convey.ShouldBeEmpty)
+			convey.So(req.Cert, convey.ShouldEqual, base64.StdEncoding.EncodeToString(getTestPemCa()))
+		}
+	})
+
+	convey.Convey("case: pem is not converted", t, func() {
+		req := getTestImportCertReq()
+		convey.So(convertImportBundle(&req), convey.ShouldBeNil)
+		convey.So(req.Cert, convey.ShouldEqual, testCrt)
+	})
+
+	convey.Convey("case: convert failed", t, func() {
+		req := importCertReq{CertName: common.SoftwareCertName, Format: x509.BundleFormatJks, Password: "wrong",
+			Cert: base64.StdEncoding.EncodeToString(getTestBundle(x509.BundleFormatJks))}
+		convey.So(convertImportBundle(&req), convey.ShouldNotBeNil)
+		req.Format = "der"
+		convey.So(convertImportBundle(&req), convey.ShouldNotBeNil)
+	})
+}
+
+func TestExportRootCaBundle(t *testing.T) {
+	patch := gomonkey.ApplyFuncReturn(loadExportRootCa, getTestPemCa(), nil)
+	defer patch.Reset()
+	engine := gin.New()
+	engine.POST("/export", ExportRootCaBundle)
+	doExport := func(req exportBundleReq) *httptest.ResponseRecorder {
+		body, err := json.Marshal(req)
+		if err != nil {
+			panic(err)
+		}
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/export", bytes.NewReader(body)))
+		return w
+	}
+
+	convey.Convey("case: export jks success", t, func() {
+		w := doExport(exportBundleReq{CertName: common.NorthernCertName, Format: x509.BundleFormatJks,
+			Password: testBundlePassword})
+		convey.So(w.Code, convey.ShouldEqual, http.StatusOK)
+		caMgr, err := x509.NewCaChainMgrFromBundle(w.Body.Bytes(), x509.BundleFormatJks, []byte(testBundlePassword))
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(caMgr.ExportPem(), convey.ShouldResemble, getTestPemCa())
+	})
+
+	convey.Convey("case: param is invalid", t, func() {
+		w := doExport(exportBundleReq{CertName: common.NorthernCertName, Format: x509.BundleFormatPkcs12,
+			Password: "short"})
+		convey.So(w.Code, convey.ShouldEqual, http.StatusBadRequest)
+		w = doExport(exportBundleReq{CertName: common.NorthernCertName, Format: x509.BundleFormatPem,
+			Password: testBundlePassword})
+		convey.So(w.Code, convey.ShouldEqual, http.StatusBadRequest)
+	})
+}
@@ -73,6 +73,8 @@ func methodSelect(req *model.Message) *common.RespMsg {
 func (cm *certManager) Start() {
 	go certExpireCheck(cm.ctx)
 	go certMonitor.Run(cm.ctx)
+	loadIssuedCertIndexes()
+	go ocspPresign(cm.ctx)
 	for {
 		select {
 		case _, ok := <-cm.ctx.Done():
@@ -128,12 +130,16 @@ var handlerFuncMap = map[string]handlerFunc{
 	common.Combine(http.MethodPost, filepath.Join(certUrlRootPath, "delete-cert")): deleteRootCa,
 	common.Combine(http.MethodGet, filepath.Join(certUrlRootPath, "info")):         getCertInfo,
 	common.Combine(http.MethodPost, filepath.Join(crlUrlRootPath, "import")):       importCrl,
+	common.Combine(http.MethodPost, filepath.Join(certUrlRootPath, "revoke")):      revokeIssuedCert,
+	common.Combine(http.MethodGet, filepath.Join(certUrlRootPath, "issued")):       getIssuedCerts,
+	common.Combine(http.MethodPost, filepath.Join(certUrlRootPath, "lint")):        lintCert,
 
 	common.Combine(http.MethodGet, filepath.Join(innerCertUrlRootPath, "rootca")):         queryRootCa,
 	common.Combine(http.MethodGet, filepath.Join(innerCertUrlRootPath, "crl")):            queryCrl,
 	common.Combine(http.MethodPost, filepath.Join(innerCertUrlRootPath, "service")):       issueServiceCa,
 	common.Combine(http.MethodPost, filepath.Join(innerCertUrlRootPath, "update-result")): certsUpdateResult,
 	common.Combine(http.MethodGet, getImportedCertsInfoUrl):                               getImportedCertsInfo,
+	common.Combine(http.MethodPost, filepath.Join(innerCertUrlRootPath, "ocsp-response")): queryOcspResp,
 }
 
 func certExpireCheck(ctx context.Context) {
@@ -12,6 +12,7 @@
 package certmanager
 
 import (
+	"crypto/x509"
 	"encoding/base64"
 	"encoding/pem"
 	"errors"
@@ -192,11 +193,27 @@ func issueServiceCert(certName string, serviceCsr string) ([]byte, error) {
 		return nil, err
 	}
 
+	recordIssuedCert(certName, certBytes)
 	certPem := certutils.PemWrapCert(certBytes)
 	hwlog.RunLog.Info("issue service cert success")
 	return certPem, nil
 }
 
+// recordIssuedCert record the issued cert for ocsp, the issuing will not fail even if recording failed
+func recordIssuedCert(certName string, certBytes []byte) {
+	if !certchecker.CheckIfSupportOcsp(certName) {
+		return
+	}
+	cert, err := x509.ParseCertificate(certBytes)
+	if err != nil {
+		hwlog.RunLog.Errorf("parse issued [%s] service cert failed: %v", certName, err)
+		return
+	}
+	if err = addIssuedCertRecord(certName, cert); err != nil {
+		hwlog.RunLog.Errorf("record issued [%s] service cert failed: %v", certName, err)
+	}
+}
+
 func isRootCaFilesExist(caFilePath, keyFilePath string) bool {
 	paths := []string{caFilePath, caFilePath + backuputils.BackupSuffix,
 		keyFilePath, keyFilePath + backuputils.BackupSuffix}
@@ -294,13 +311,30 @@ func updateClientCert(certName, operation string) error {
 func ExportRootCa(c *gin.Context) {
 	hwlog.RunLog.Info("export cert file start")
 	certName := c.Query("certName")
-	if !certchecker.CheckIfCanExport(certName) {
-		msg := fmt.Sprintf("export cert [%s] root ca not support", certName)
-		hwlog.RunLog.Error(msg)
+	caBytes, err := loadExportRootCa(certName)
+	if err != nil {
+		hwlog.RunLog.Error(err)
+		common.ConstructResp(c, common.ErrorExportRootCa, err.Error(), nil)
+		return
+	}
+	c.Writer.WriteHeader(http.StatusOK)
+	c.Header(common.ContentType, "text/plain; charset=utf-8")
+	c.Header(common.ContentDisposition, fmt.Sprintf("attachment; filename=%s", util.RootCaFileName))
+	c.Writer.WriteHeaderNow()
+	if _, err = c.Writer.Write(caBytes); err != nil {
+		msg := fmt.Sprintf("export cert [%s] root ca failed", certName)
+		hwlog.RunLog.Errorf("%s, error: %v", msg, err)
 		common.ConstructResp(c, common.ErrorExportRootCa, msg, nil)
 		return
 	}
+	c.Writer.Flush()
+	hwlog.RunLog.Infof("export cert [%s] root ca success", certName)
+}
 
+func loadExportRootCa(certName string) ([]byte, error) {
+	if !certchecker.CheckIfCanExport(certName) {
+		return nil, fmt.Errorf("export cert [%s] root ca not support", certName)
+	}
 	var caBytes []byte
 	var err error
 	tempCaFilePath := getTempRootCaPath(certName)
@@ -312,22 +346,9 @@ func ExportRootCa(c *gin.Context) {
 	// if new temp ca exists, but get an error when load it, use old ca data.
 	if len(caBytes) == 0 {
 		if caBytes, err = getCertByCertName(certName); err != nil {
-			msg := fmt.Sprintf("get cert [%s] root ca failed", certName)
-			hwlog.RunLog.Errorf("%s, error: %v", msg, err)
-			common.ConstructResp(c, common.ErrorExportRootCa, msg, nil)
-			return
+			hwlog.RunLog.Errorf("load cert [%s] root ca failed, error: %v", certName, err)
+			return nil, fmt.Errorf("get cert [%s] root ca failed", certName)
 		}
 	}
-	c.Writer.WriteHeader(http.StatusOK)
-	c.Header(common.ContentType, "text/plain; charset=utf-8")
-	c.Header(common.ContentDisposition, fmt.Sprintf("attachment; filename=%s", util.RootCaFileName))
-	c.Writer.WriteHeaderNow()
-	if _, err = c.Writer.Write(caBytes); err != nil {
-		msg := fmt.Sprintf("export cert [%s] root ca failed", certName)
-		hwlog.RunLog.Errorf("%s, error: %v", msg, err)
-		common.ConstructResp(c, common.ErrorExportRootCa, msg, nil)
-		return
-	}
-	c.Writer.Flush()
-	hwlog.RunLog.Infof("export cert [%s] root ca success", certName)
+	return caBytes, nil
 }
@@ -0,0 +1,74 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package certmanager check ca with cert policy without importing it
+package certmanager
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	"huawei.com/mindx/common/hwlog"
+	"huawei.com/mindx/common/modulemgr/model"
+	"huawei.com/mindx/common/x509"
+
+	"huawei.com/mindxedge/base/common"
+
+	"cert-manager/pkg/certmanager/certchecker"
+	"cert-manager/pkg/config"
+)
+
+// lintCert evaluates the cert policy on the ca, all violations are returned instead of the first one
+func lintCert(msg *model.Message) common.RespMsg {
+	hwlog.RunLog.Info("lint cert start")
+	var req lintCertReq
+	if err := msg.ParseContent(&req); err != nil {
+		hwlog.RunLog.Errorf("parse content failed: %v", err)
+		return common.RespMsg{Status: common.ErrorParamConvert, Msg: "parse content failed", Data: nil}
+	}
+	importReq := importCertReq{Cert: req.Cert, Format: req.Format, Password: req.Password}
+	req.Password = ""
+	if err := convertImportBundle(&importReq); err != nil {
+		hwlog.RunLog.Errorf("convert ca bundle failed: %v", err)
+		return common.RespMsg{Status: common.ErrorParamInvalid, Msg: fmt.Sprintf("convert ca bundle failed: %v", err)}
+	}
+	if checkResult := certchecker.NewLintCertChecker().Check(importReq); !checkResult.Result {
+		hwlog.RunLog.Errorf("cert lint para check failed: %s", checkResult.Reason)
+		return common.RespMsg{Status: common.ErrorParamInvalid,
+			Msg: fmt.Sprintf("cert lint para check failed: %s", checkResult.Reason)}
+	}
+	result, err := lintCertContent(importReq.Cert)
+	if err != nil {
+		hwlog.RunLog.Errorf("lint cert failed: %v", err)
+		return common.RespMsg{Status: common.ErrorParamInvalid, Msg: fmt.Sprintf("lint cert failed: %v", err)}
+	}
+	hwlog.RunLog.Infof("lint cert finished, %d violations found", len(result.Violations))
+	return common.RespMsg{Status: common.Success, Msg: "lint cert success", Data: result}
+}
+
+func lintCertContent(cert string) (lintCertResult, error) {
+	content, err := base64.StdEncoding.DecodeString(cert)
+	if err != nil {
+		return lintCertResult{}, fmt.Errorf("base64 decode ca content failed: %v", err)
+	}
+	caMgr, err := x509.NewCaChainMgr(content)
+	if err != nil {
+		return lintCertResult{}, err
+	}
+	policy, err := config.GetCertPolicy()
+	if err != nil {
+		return lintCertResult{}, err
+	}
+	violations := policy.CheckCerts(caMgr.GetCerts())
+	if violations == nil {
+		violations = x509.PolicyViolations{}
+	}
+	return lintCertResult{Passed: len(violations) == 0, Violations: violations}, nil
+}
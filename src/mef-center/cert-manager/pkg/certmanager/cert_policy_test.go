@@ -0,0 +1,63 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package certmanager
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/smartystreets/goconvey/convey"
+
+	"huawei.com/mindx/common/x509"
+
+	"huawei.com/mindxedge/base/common"
+
+	"cert-manager/pkg/config"
+)
+
+func TestLintCert(t *testing.T) {
+	convey.Convey("case: cert meets the default policy", t, func() {
+		resp := lintCert(newMsgWithContentForUT(lintCertReq{Cert: testCrt}))
+		convey.So(resp.Status, convey.ShouldEqual, common.Success)
+		result, ok := resp.Data.(lintCertResult)
+		convey.So(ok, convey.ShouldBeTrue)
+		convey.So(result.Passed, convey.ShouldBeTrue)
+		convey.So(result.Violations, convey.ShouldBeEmpty)
+	})
+
+	convey.Convey("case: all violations are returned", t, func() {
+		policy := &x509.CertPolicy{
+			KeyAlgorithms:       []x509.KeyAlgorithmPolicy{{Algorithm: x509.KeyAlgorithmEcdsa, MinSize: 256}},
+			SignatureAlgorithms: []string{"ECDSA-SHA384"},
+			AllowedIssuers:      []string{"CN=other"},
+		}
+		patches := gomonkey.ApplyFuncReturn(config.GetCertPolicy, policy, nil)
+		defer patches.Reset()
+		req := lintCertReq{Cert: base64.StdEncoding.EncodeToString(getTestBundle(x509.BundleFormatPkcs12)),
+			Format: x509.BundleFormatPkcs12, Password: testBundlePassword}
+		resp := lintCert(newMsgWithContentForUT(req))
+		convey.So(resp.Status, convey.ShouldEqual, common.Success)
+		result, ok := resp.Data.(lintCertResult)
+		convey.So(ok, convey.ShouldBeTrue)
+		convey.So(result.Passed, convey.ShouldBeFalse)
+		convey.So(len(result.Violations), convey.ShouldBeGreaterThanOrEqualTo, 3)
+	})
+
+	convey.Convey("case: invalid cert", t, func() {
+		resp := lintCert(newMsgWithContentForUT("test content"))
+		convey.So(resp.Status, convey.ShouldEqual, common.ErrorParamConvert)
+		resp = lintCert(newMsgWithContentForUT(lintCertReq{}))
+		convey.So(resp.Status, convey.ShouldEqual, common.ErrorParamInvalid)
+		resp = lintCert(newMsgWithContentForUT(lintCertReq{Cert: base64.StdEncoding.EncodeToString([]byte("x"))}))
+		convey.So(resp.Status, convey.ShouldEqual, common.ErrorParamInvalid)
+	})
+}
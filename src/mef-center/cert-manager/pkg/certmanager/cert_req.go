@@ -11,6 +11,8 @@
 // Package certmanager cert manager module
 package certmanager
 
+import "huawei.com/mindx/common/x509"
+
 const (
 	updateSuccessCode int64 = 1
 	updateFailedCode  int64 = 2
@@ -20,6 +22,30 @@ const (
 type importCertReq struct {
 	CertName string `json:"certName" binding:"required,oneof=software image res_file apig alarm edge_core device_plugin"`
 	Cert     string `json:"cert" binding:"required,gte=1,lte=2000000"`
+	// Format format of the ca bundle, pem is used if empty
+	Format string `json:"format"`
+	// Password password to verify the integrity of pkcs12 or jks bundle
+	Password string `json:"password"`
+}
+
+// exportBundleReq export ca in pkcs12 or jks format req
+type exportBundleReq struct {
+	CertName string `json:"certName"`
+	Format   string `json:"format"`
+	Password string `json:"password"`
+}
+
+// lintCertReq check ca with cert policy without importing it req
+type lintCertReq struct {
+	Cert     string `json:"cert"`
+	Format   string `json:"format"`
+	Password string `json:"password"`
+}
+
+// lintCertResult all violations of cert policy found in the ca
+type lintCertResult struct {
+	Passed     bool                   `json:"passed"`
+	Violations []x509.PolicyViolation `json:"violations"`
 }
 
 // deleteCaReq delete ca req
@@ -134,6 +134,10 @@ func importRootCa(msg *model.Message) common.RespMsg {
 		hwlog.RunLog.Errorf("parse content failed: %v", err)
 		return common.RespMsg{Status: common.ErrorParamConvert, Msg: "parse content failed", Data: nil}
 	}
+	if err := convertImportBundle(&req); err != nil {
+		hwlog.RunLog.Errorf("convert ca bundle failed: %v", err)
+		return common.RespMsg{Status: common.ErrorParamInvalid, Msg: fmt.Sprintf("convert ca bundle failed: %v", err)}
+	}
 	if checkResult := certchecker.NewImportCertChecker().Check(req); !checkResult.Result {
 		hwlog.RunLog.Errorf("cert import para check failed: %s", checkResult.Reason)
 		return common.RespMsg{Status: common.ErrorParamInvalid,
@@ -0,0 +1,65 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package certchecker checker of importing and exporting ca bundles
+package certchecker
+
+import (
+	"fmt"
+
+	"huawei.com/mindx/common/checker"
+	"huawei.com/mindx/common/x509"
+)
+
+const (
+	minBundlePasswordLen = 8
+	maxBundlePasswordLen = 64
+)
+
+// NewImportBundleChecker [method] for getting the checker of ca bundle format and password when importing,
+// the password of bundles exported by other tools may be empty
+func NewImportBundleChecker() *bundleChecker {
+	return &bundleChecker{
+		formats:        []string{x509.BundleFormatPkcs12, x509.BundleFormatJks},
+		minPasswordLen: 0,
+	}
+}
+
+// NewExportBundleChecker [method] for getting the checker of ca bundle format and password when exporting,
+// the exported pkcs12 and jks must be protected by password
+func NewExportBundleChecker() *bundleChecker {
+	return &bundleChecker{
+		formats:        []string{x509.BundleFormatPkcs12, x509.BundleFormatJks},
+		minPasswordLen: minBundlePasswordLen,
+	}
+}
+
+type bundleChecker struct {
+	formats        []string
+	minPasswordLen int
+	modelChecker   checker.ModelChecker
+}
+
+func (bc *bundleChecker) init() {
+	bc.modelChecker.Checker = checker.GetAndChecker(
+		checker.GetStringChoiceChecker("Format", bc.formats, true),
+		checker.GetStringLengthChecker("Password", bc.minPasswordLen, maxBundlePasswordLen, true),
+	)
+}
+
+// Check [method] check main function
+func (bc *bundleChecker) Check(data interface{}) checker.CheckResult {
+	bc.init()
+	checkResult := bc.modelChecker.Check(data)
+	if !checkResult.Result {
+		return checker.NewFailedResult(fmt.Sprintf("ca bundle check failed: %s", checkResult.Reason))
+	}
+	return checker.NewSuccessResult()
+}
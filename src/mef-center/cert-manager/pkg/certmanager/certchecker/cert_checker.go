@@ -23,6 +23,8 @@ import (
 	"huawei.com/mindx/common/x509"
 
 	"huawei.com/mindxedge/base/common"
+
+	"cert-manager/pkg/config"
 )
 
 var lock sync.Mutex
@@ -42,6 +44,14 @@ func NewIssueCertChecker() *issueCertChecker {
 	return &issueCertChecker{}
 }
 
+// NewLintCertChecker [method] for getting the checker of cert content to be linted with cert policy
+func NewLintCertChecker() *checker.ModelChecker {
+	return &checker.ModelChecker{
+		Required: true,
+		Checker:  checker.GetStringLengthChecker("Cert", 1, maxCertContentLen, true),
+	}
+}
+
 type importCertChecker struct {
 	certChecker checker.ModelChecker
 }
@@ -135,7 +145,11 @@ func certContentChecker(certContent string) error {
 		return err
 	}
 
-	if err = caMgr.CheckCertChain(); err != nil {
+	policy, err := config.GetCertPolicy()
+	if err != nil {
+		return err
+	}
+	if err = caMgr.CheckCertChain(x509.CaChainCheckOptions{Policy: policy}); err != nil {
 		hwlog.RunLog.Errorf("check importing certs failed: %s", err.Error())
 		return err
 	}
@@ -14,6 +14,8 @@ package certchecker
 const (
 	csrReg      = "^[a-zA-Z0-9=+/]{1,}$"
 	maxCertSize = 1024 * 1024
-	minCsrLen   = 1
-	maxCsrLen   = 4096
+	// maxCertContentLen max length of base64 encoded cert content in request
+	maxCertContentLen = 2000000
+	minCsrLen         = 1
+	maxCsrLen         = 4096
 )
@@ -23,6 +23,8 @@ import (
 
 	"huawei.com/mindxedge/base/common"
 	"huawei.com/mindxedge/base/mef-center-install/pkg/util"
+
+	"cert-manager/pkg/config"
 )
 
 type importCrlChecker struct {
@@ -66,6 +68,14 @@ func checkCrlContent(name, content string) error {
 		hwlog.RunLog.Errorf("check crl content failed, error: %s", err.Error())
 		return err
 	}
+	policy, err := config.GetCertPolicy()
+	if err != nil {
+		return err
+	}
+	if err = policy.CheckCrls(mgr.GetCrls()).Err(); err != nil {
+		hwlog.RunLog.Errorf("check crl with cert policy failed, error: %s", err.Error())
+		return err
+	}
 	return nil
 }
 
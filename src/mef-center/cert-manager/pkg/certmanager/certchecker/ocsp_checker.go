@@ -0,0 +1,114 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package certchecker checker for revoke cert and query ocsp response
+package certchecker
+
+import (
+	"fmt"
+
+	"huawei.com/mindx/common/checker"
+
+	"huawei.com/mindxedge/base/common"
+)
+
+const (
+	serialNumberReg = "^[0-9a-fA-F]{1,40}$"
+	// reason code defined in RFC 5280, 7 is not used
+	minRevocationReason = 0
+	maxRevocationReason = 10
+	unusedReason        = 7
+)
+
+// ocspCertNames the root ca which is created by mef, its issued certs can be checked by ocsp
+var ocspCertNames = []string{
+	common.WsSerName,
+	common.WsCltName,
+	common.NginxCertName,
+	common.InnerName,
+	common.ThirdPartyCertName,
+}
+
+// GetOcspCertNames get the name of root ca which supports ocsp
+func GetOcspCertNames() []string {
+	return ocspCertNames
+}
+
+// CheckIfSupportOcsp check if the certs issued by the root ca can be checked by ocsp
+func CheckIfSupportOcsp(certName string) bool {
+	for _, name := range ocspCertNames {
+		if name == certName {
+			return true
+		}
+	}
+	return false
+}
+
+// NewRevokeCertChecker [method] for getting revoke cert checker struct
+func NewRevokeCertChecker() *revokeCertChecker {
+	return &revokeCertChecker{}
+}
+
+// NewOcspRespChecker [method] for getting query ocsp response checker struct
+func NewOcspRespChecker() *ocspRespChecker {
+	return &ocspRespChecker{}
+}
+
+// NewIssuedCertNameChecker [method] for getting the name checker of root ca which supports ocsp
+func NewIssuedCertNameChecker() *checker.ModelChecker {
+	return &checker.ModelChecker{
+		Required: true,
+		Checker:  checker.GetStringChoiceChecker("", ocspCertNames, true),
+	}
+}
+
+type revokeCertChecker struct {
+	certChecker checker.ModelChecker
+}
+
+func (rcc *revokeCertChecker) init() {
+	rcc.certChecker.Checker = checker.GetAndChecker(
+		checker.GetStringChoiceChecker("CertName", ocspCertNames, true),
+		checker.GetRegChecker("SerialNumber", serialNumberReg, true),
+		checker.GetOrChecker(
+			checker.GetIntChecker("Reason", minRevocationReason, unusedReason-1, true),
+			checker.GetIntChecker("Reason", unusedReason+1, maxRevocationReason, true),
+		),
+	)
+}
+
+func (rcc *revokeCertChecker) Check(data interface{}) checker.CheckResult {
+	rcc.init()
+	checkResult := rcc.certChecker.Check(data)
+	if !checkResult.Result {
+		return checker.NewFailedResult(fmt.Sprintf("revoke cert checker check failed: %s", checkResult.Reason))
+	}
+	return checker.NewSuccessResult()
+}
+
+type ocspRespChecker struct {
+	certChecker checker.ModelChecker
+}
+
+func (occ *ocspRespChecker) init() {
+	occ.certChecker.Checker = checker.GetAndChecker(
+		checker.GetStringChoiceChecker("CertName", ocspCertNames, true),
+		checker.GetRegChecker("SerialNumber", serialNumberReg, true),
+	)
+}
+
+func (occ *ocspRespChecker) Check(data interface{}) checker.CheckResult {
+	occ.init()
+	checkResult := occ.certChecker.Check(data)
+	if !checkResult.Result {
+		return checker.NewFailedResult(fmt.Sprintf("ocsp response checker check failed: %s", checkResult.Reason))
+	}
+	return checker.NewSuccessResult()
+}
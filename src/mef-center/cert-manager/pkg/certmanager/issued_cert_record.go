@@ -0,0 +1,275 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package certmanager records of the certificates issued by mef root ca
+package certmanager
+
+import (
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"huawei.com/mindx/common/backuputils"
+	"huawei.com/mindx/common/fileutils"
+	"huawei.com/mindx/common/hwlog"
+
+	"cert-manager/pkg/certmanager/certchecker"
+)
+
+const (
+	issuedCertRecordFile = "issued-certs.json"
+	maxIssuedCertRecords = 20000
+	// expired certs are kept for a while, so that responder can still answer revoked for them
+	expiredRecordKeepTime = 30 * 24 * time.Hour
+	// the validity of an unrecorded cert is unknown, its revocation is kept as long as the longest validity
+	unrecordedRevokedKeepTime = 10 * 365 * 24 * time.Hour
+	hexBase                   = 16
+)
+
+// the records are loaded from file once and answered from memory, the file is written on every change. The certs
+// issued before the records are kept, or evicted from the full records, are not in the records, ocsp answers
+// unknown for them and they can still be revoked
+var (
+	recordLock        sync.Mutex
+	issuedCertIndexes = map[string]map[string]*issuedCertRecord{}
+)
+
+// issuedCertRecord record of one certificate issued by mef root ca
+type issuedCertRecord struct {
+	SerialNumber     string `json:"serialNumber"`
+	Subject          string `json:"subject"`
+	NotBefore        int64  `json:"notBefore"`
+	NotAfter         int64  `json:"notAfter"`
+	Revoked          bool   `json:"revoked"`
+	RevokedAt        int64  `json:"revokedAt"`
+	RevocationReason int    `json:"revocationReason"`
+}
+
+func getIssuedCertRecordPath(certName string) string {
+	return filepath.Join(getRootCaDir(certName), issuedCertRecordFile)
+}
+
+// formatSerialNumber format serial number as upper case hex string, which is the key of records
+func formatSerialNumber(sn *big.Int) string {
+	return strings.ToUpper(sn.Text(hexBase))
+}
+
+func parseSerialNumber(snStr string) (*big.Int, error) {
+	sn, ok := new(big.Int).SetString(snStr, hexBase)
+	if !ok || sn.Sign() <= 0 {
+		return nil, fmt.Errorf("serial number [%s] is not a positive hex number", snStr)
+	}
+	return sn, nil
+}
+
+func loadIssuedCertRecords(certName string) (map[string]*issuedCertRecord, error) {
+	records := make(map[string]*issuedCertRecord)
+	recordPath := getIssuedCertRecordPath(certName)
+	if !fileutils.IsExist(recordPath) {
+		return records, nil
+	}
+	content, err := fileutils.LoadFile(recordPath)
+	if err != nil || json.Unmarshal(content, &records) != nil {
+		hwlog.RunLog.Warnf("load [%s] issued cert records failed, try to restore from backup", certName)
+		if err = backuputils.RestoreFiles(recordPath); err != nil {
+			return nil, fmt.Errorf("restore [%s] issued cert records failed: %v", certName, err)
+		}
+		if content, err = fileutils.LoadFile(recordPath); err != nil {
+			return nil, fmt.Errorf("load [%s] issued cert records failed: %v", certName, err)
+		}
+		if err = json.Unmarshal(content, &records); err != nil {
+			return nil, fmt.Errorf("unmarshal [%s] issued cert records failed: %v", certName, err)
+		}
+	}
+	return records, nil
+}
+
+// getIssuedCertIndex get the records in memory, they are loaded from file at the first time. The caller must hold
+// recordLock
+func getIssuedCertIndex(certName string) (map[string]*issuedCertRecord, error) {
+	if records, ok := issuedCertIndexes[certName]; ok {
+		return records, nil
+	}
+	records, err := loadIssuedCertRecords(certName)
+	if err != nil {
+		return nil, err
+	}
+	issuedCertIndexes[certName] = records
+	return records, nil
+}
+
+// saveIssuedCertIndex write the changed records to file, the records in memory are dropped if writing failed, so
+// that they are loaded from file again. The caller must hold recordLock
+func saveIssuedCertIndex(certName string, records map[string]*issuedCertRecord) error {
+	if err := saveIssuedCertRecords(certName, records); err != nil {
+		delete(issuedCertIndexes, certName)
+		return err
+	}
+	return nil
+}
+
+// loadIssuedCertIndexes load the records of all root ca supporting ocsp at startup
+func loadIssuedCertIndexes() {
+	recordLock.Lock()
+	defer recordLock.Unlock()
+	for _, certName := range certchecker.GetOcspCertNames() {
+		records, err := getIssuedCertIndex(certName)
+		if err != nil {
+			hwlog.RunLog.Errorf("load [%s] issued cert records failed: %v", certName, err)
+			continue
+		}
+		hwlog.RunLog.Infof("%d [%s] issued cert records are loaded", len(records), certName)
+	}
+}
+
+func saveIssuedCertRecords(certName string, records map[string]*issuedCertRecord) error {
+	recordPath := getIssuedCertRecordPath(certName)
+	content, err := json.Marshal(records)
+	if err != nil {
+		return fmt.Errorf("marshal [%s] issued cert records failed: %v", certName, err)
+	}
+	if err = fileutils.MakeSureDir(recordPath); err != nil {
+		return fmt.Errorf("create [%s] issued cert records folder failed: %v", certName, err)
+	}
+	if err = fileutils.WriteData(recordPath, content); err != nil {
+		return fmt.Errorf("save [%s] issued cert records failed: %v", certName, err)
+	}
+	if err = backuputils.BackUpFiles(recordPath); err != nil {
+		hwlog.RunLog.Warnf("back up [%s] issued cert records failed, %v", certName, err)
+	}
+	return nil
+}
+
+func removeOutdatedRecords(records map[string]*issuedCertRecord) {
+	deadline := time.Now().Add(-expiredRecordKeepTime).Unix()
+	for sn, record := range records {
+		if record.NotAfter < deadline {
+			delete(records, sn)
+		}
+	}
+}
+
+// makeRoomForRecord remove the outdated records, and evict the good record expiring earliest if the records are
+// still full. The revoked records are never evicted, since their revocation must not be lost
+func makeRoomForRecord(certName string, records map[string]*issuedCertRecord) error {
+	removeOutdatedRecords(records)
+	for len(records) >= maxIssuedCertRecords {
+		var evicted *issuedCertRecord
+		for _, record := range records {
+			if !record.Revoked && (evicted == nil || record.NotAfter < evicted.NotAfter) {
+				evicted = record
+			}
+		}
+		if evicted == nil {
+			return fmt.Errorf("the number of [%s] revoked cert records reaches the limit %d",
+				certName, maxIssuedCertRecords)
+		}
+		delete(records, evicted.SerialNumber)
+		invalidateOcspCache(certName, evicted.SerialNumber)
+		hwlog.RunLog.Warnf("[%s] issued cert records reach the limit %d, the record of cert [%s] is evicted, "+
+			"ocsp answers unknown for it", certName, maxIssuedCertRecords, evicted.SerialNumber)
+	}
+	return nil
+}
+
+// addIssuedCertRecord save the record of the newly issued certificate
+func addIssuedCertRecord(certName string, cert *x509.Certificate) error {
+	recordLock.Lock()
+	defer recordLock.Unlock()
+	records, err := getIssuedCertIndex(certName)
+	if err != nil {
+		return err
+	}
+	if err = makeRoomForRecord(certName, records); err != nil {
+		return err
+	}
+	sn := formatSerialNumber(cert.SerialNumber)
+	records[sn] = &issuedCertRecord{
+		SerialNumber: sn,
+		Subject:      cert.Subject.CommonName,
+		NotBefore:    cert.NotBefore.Unix(),
+		NotAfter:     cert.NotAfter.Unix(),
+	}
+	if err = saveIssuedCertIndex(certName, records); err != nil {
+		return err
+	}
+	invalidateOcspCache(certName, sn)
+	return nil
+}
+
+// getIssuedCertRecord get a copy of the record of issued certificate by serial number, return nil if not found
+func getIssuedCertRecord(certName string, sn string) (*issuedCertRecord, error) {
+	recordLock.Lock()
+	defer recordLock.Unlock()
+	records, err := getIssuedCertIndex(certName)
+	if err != nil {
+		return nil, err
+	}
+	record, ok := records[sn]
+	if !ok {
+		return nil, nil
+	}
+	recordCopy := *record
+	return &recordCopy, nil
+}
+
+// getAllIssuedCertRecords get all records of certificates issued by the root ca
+func getAllIssuedCertRecords(certName string) ([]issuedCertRecord, error) {
+	recordLock.Lock()
+	defer recordLock.Unlock()
+	records, err := getIssuedCertIndex(certName)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]issuedCertRecord, 0, len(records))
+	for _, record := range records {
+		result = append(result, *record)
+	}
+	return result, nil
+}
+
+// revokeIssuedCertRecord mark the issued certificate as revoked, the cert which is not recorded is recorded as
+// revoked, since it may be issued before the records are kept or evicted from the records
+func revokeIssuedCertRecord(certName string, sn string, reason int) error {
+	recordLock.Lock()
+	defer recordLock.Unlock()
+	records, err := getIssuedCertIndex(certName)
+	if err != nil {
+		return err
+	}
+	record, ok := records[sn]
+	if !ok {
+		if err = makeRoomForRecord(certName, records); err != nil {
+			return err
+		}
+		hwlog.RunLog.Warnf("cert [%s] issued by [%s] is not recorded, record it as revoked", sn, certName)
+		record = &issuedCertRecord{
+			SerialNumber: sn,
+			NotAfter:     time.Now().Add(unrecordedRevokedKeepTime).Unix(),
+		}
+		records[sn] = record
+	}
+	if record.Revoked {
+		return fmt.Errorf("cert [%s] issued by [%s] is already revoked", sn, certName)
+	}
+	record.Revoked = true
+	record.RevokedAt = time.Now().Unix()
+	record.RevocationReason = reason
+	if err = saveIssuedCertIndex(certName, records); err != nil {
+		return err
+	}
+	invalidateOcspCache(certName, sn)
+	return nil
+}
@@ -0,0 +1,466 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package certmanager ocsp responder defined in RFC 6960 for certs issued by mef root ca
+package certmanager
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/ocsp"
+
+	"huawei.com/mindx/common/fileutils"
+	"huawei.com/mindx/common/hwlog"
+	"huawei.com/mindx/common/x509/certutils"
+
+	"huawei.com/mindxedge/base/common"
+
+	"cert-manager/pkg/certmanager/certchecker"
+)
+
+const (
+	ocspRequestContentType  = "application/ocsp-request"
+	ocspResponseContentType = "application/ocsp-response"
+	maxOcspRequestSize      = 4 * 1024
+
+	ocspSignerKeyLen     = 3072
+	ocspSignerValidity   = 30 * 24 * time.Hour
+	ocspSignerRenewAhead = 2 * ocspRespValidity
+	ocspRespValidity     = 24 * time.Hour
+	ocspRespRefreshAhead = 6 * time.Hour
+	ocspPresignInterval  = time.Hour
+	maxOcspCacheSize     = 2 * maxIssuedCertRecords
+	// responses of unknown certs are cached in a small cache which drops the oldest ones, so that the repeated
+	// requests are not signed again and the cache is not filled by random serial numbers
+	maxUnknownCacheSize = 1024
+)
+
+// idPkixOcspNoCheck extension tells clients not to check the revocation of the delegated responder cert
+var idPkixOcspNoCheck = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 48, 1, 5}
+
+// ocspSigner delegated responder signed by the root ca
+type ocspSigner struct {
+	issuer *x509.Certificate
+	cert   *x509.Certificate
+	key    *rsa.PrivateKey
+}
+
+type cachedOcspResp struct {
+	der        []byte
+	nextUpdate time.Time
+}
+
+// ocspResponder answers the ocsp requests for certs issued by one root ca
+type ocspResponder struct {
+	certName string
+	lock     sync.Mutex
+	// key is the path of root ca, since the temp root ca also issues certs during cert update
+	signers map[string]*ocspSigner
+	// key is the path of root ca and the serial number of the cert
+	cache        map[string]cachedOcspResp
+	unknownCache map[string]cachedOcspResp
+	unknownKeys  []string
+	// generation increases when records change, responses signed with older records are not cached
+	generation uint64
+}
+
+var (
+	respondersLock sync.Mutex
+	ocspResponders = map[string]*ocspResponder{}
+)
+
+func getOcspResponder(certName string) *ocspResponder {
+	respondersLock.Lock()
+	defer respondersLock.Unlock()
+	responder, ok := ocspResponders[certName]
+	if !ok {
+		responder = &ocspResponder{
+			certName:     certName,
+			signers:      map[string]*ocspSigner{},
+			cache:        map[string]cachedOcspResp{},
+			unknownCache: map[string]cachedOcspResp{},
+		}
+		ocspResponders[certName] = responder
+	}
+	return responder
+}
+
+// invalidateOcspCache drop the cached responses of the cert, called when the record of the cert changes
+func invalidateOcspCache(certName string, sn string) {
+	if !certchecker.CheckIfSupportOcsp(certName) {
+		return
+	}
+	responder := getOcspResponder(certName)
+	responder.lock.Lock()
+	defer responder.lock.Unlock()
+	responder.generation++
+	for _, cache := range []map[string]cachedOcspResp{responder.cache, responder.unknownCache} {
+		for key := range cache {
+			if strings.HasSuffix(key, "/"+sn) {
+				delete(cache, key)
+			}
+		}
+	}
+}
+
+func loadCaCert(caPath string) (*x509.Certificate, error) {
+	caBytes, err := fileutils.LoadFile(caPath)
+	if err != nil {
+		return nil, fmt.Errorf("load root ca failed: %v", err)
+	}
+	block, _ := pem.Decode(caBytes)
+	if block == nil {
+		return nil, errors.New("decode root ca pem failed")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+func getIssuerKeyHash(issuer *x509.Certificate, hash crypto.Hash) ([]byte, error) {
+	var publicKeyInfo struct {
+		Algorithm pkix.AlgorithmIdentifier
+		PublicKey asn1.BitString
+	}
+	if _, err := asn1.Unmarshal(issuer.RawSubjectPublicKeyInfo, &publicKeyInfo); err != nil {
+		return nil, fmt.Errorf("unmarshal public key info of root ca failed: %v", err)
+	}
+	h := hash.New()
+	if _, err := h.Write(publicKeyInfo.PublicKey.RightAlign()); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
+
+func newOcspSigner(caPath, keyPath string) (*ocspSigner, error) {
+	caPair, err := certutils.GetCertPair(caPath, keyPath, nil)
+	if err != nil {
+		return nil, fmt.Errorf("get root ca pair failed: %v", err)
+	}
+	priv, err := rsa.GenerateKey(rand.Reader, ocspSignerKeyLen)
+	if err != nil {
+		return nil, fmt.Errorf("generate ocsp signer key failed: %v", err)
+	}
+	snBytes := make([]byte, serialNumberLen)
+	if _, err = rand.Read(snBytes); err != nil {
+		return nil, fmt.Errorf("generate ocsp signer serial number failed: %v", err)
+	}
+	now := time.Now().UTC()
+	notAfter := now.Add(ocspSignerValidity)
+	if notAfter.After(caPair.Cert.NotAfter) {
+		notAfter = caPair.Cert.NotAfter
+	}
+	template := &x509.Certificate{
+		SerialNumber: new(big.Int).SetBytes(snBytes),
+		Subject: pkix.Name{
+			Organization:       caPair.Cert.Subject.Organization,
+			OrganizationalUnit: caPair.Cert.Subject.OrganizationalUnit,
+			CommonName:         common.MefCertCommonNamePrefix + "-OCSP-" + caPair.Cert.Subject.CommonName,
+		},
+		NotBefore:       now.Add(-time.Hour),
+		NotAfter:        notAfter,
+		KeyUsage:        x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:     []x509.ExtKeyUsage{x509.ExtKeyUsageOCSPSigning},
+		ExtraExtensions: []pkix.Extension{{Id: idPkixOcspNoCheck, Value: asn1.NullBytes}},
+	}
+	certDer, err := x509.CreateCertificate(rand.Reader, template, caPair.Cert, &priv.PublicKey, caPair.PriKey)
+	if err != nil {
+		return nil, fmt.Errorf("create ocsp signer cert failed: %v", err)
+	}
+	cert, err := x509.ParseCertificate(certDer)
+	if err != nil {
+		return nil, fmt.Errorf("parse ocsp signer cert failed: %v", err)
+	}
+	return &ocspSigner{issuer: caPair.Cert, cert: cert, key: priv}, nil
+}
+
+func (s *ocspSigner) isUsable(issuer *x509.Certificate) bool {
+	return s.issuer.Equal(issuer) && time.Until(s.cert.NotAfter) > ocspSignerRenewAhead
+}
+
+func (s *ocspSigner) sign(sn *big.Int, record *issuedCertRecord) ([]byte, time.Time, error) {
+	now := time.Now().UTC().Truncate(time.Minute)
+	template := ocsp.Response{
+		Status:       ocsp.Unknown,
+		SerialNumber: sn,
+		ThisUpdate:   now,
+		NextUpdate:   now.Add(ocspRespValidity),
+		Certificate:  s.cert,
+	}
+	if record != nil {
+		template.Status = ocsp.Good
+		if record.Revoked {
+			template.Status = ocsp.Revoked
+			template.RevokedAt = time.Unix(record.RevokedAt, 0).UTC()
+			template.RevocationReason = record.RevocationReason
+		}
+	}
+	der, err := ocsp.CreateResponse(s.issuer, s.cert, template, s.key)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("create ocsp response failed: %v", err)
+	}
+	return der, template.NextUpdate, nil
+}
+
+// getIssuerPaths the root ca and the temp one (if exists) which can issue certs
+func (r *ocspResponder) getIssuerPaths() [][2]string {
+	paths := [][2]string{{getRootCaPath(r.certName), getRootKeyPath(r.certName)}}
+	tempCaPath := getTempRootCaPath(r.certName)
+	tempKeyPath := getTempRootKeyPath(r.certName)
+	if fileutils.IsExist(tempCaPath) && fileutils.IsExist(tempKeyPath) {
+		paths = append(paths, [2]string{tempCaPath, tempKeyPath})
+	}
+	return paths
+}
+
+func (r *ocspResponder) getSigner(caPath, keyPath string) (*ocspSigner, error) {
+	issuer, err := loadCaCert(caPath)
+	if err != nil {
+		return nil, err
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if signer, ok := r.signers[caPath]; ok && signer.isUsable(issuer) {
+		return signer, nil
+	}
+	signer, err := newOcspSigner(caPath, keyPath)
+	if err != nil {
+		return nil, err
+	}
+	r.signers[caPath] = signer
+	hwlog.RunLog.Infof("create [%s] ocsp signer success, valid until %s", r.certName,
+		signer.cert.NotAfter.Format(time.RFC3339))
+	return signer, nil
+}
+
+// findSigner find the signer whose root ca matches the issuer in the ocsp request
+func (r *ocspResponder) findSigner(req *ocsp.Request) (*ocspSigner, string, error) {
+	if !req.HashAlgorithm.Available() {
+		return nil, "", errors.New("hash algorithm of ocsp request is not supported")
+	}
+	for _, path := range r.getIssuerPaths() {
+		issuer, err := loadCaCert(path[0])
+		if err != nil {
+			hwlog.RunLog.Warnf("load [%s] root ca failed: %v", r.certName, err)
+			continue
+		}
+		keyHash, err := getIssuerKeyHash(issuer, req.HashAlgorithm)
+		if err != nil || !bytes.Equal(keyHash, req.IssuerKeyHash) {
+			continue
+		}
+		signer, err := r.getSigner(path[0], path[1])
+		return signer, path[0], err
+	}
+	return nil, "", errors.New("the issuer of ocsp request is not found")
+}
+
+func (r *ocspResponder) getCached(key string) ([]byte, bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	cached, ok := r.cache[key]
+	if !ok {
+		cached, ok = r.unknownCache[key]
+	}
+	if !ok || time.Until(cached.nextUpdate) < ocspRespRefreshAhead {
+		return nil, false
+	}
+	return cached.der, true
+}
+
+func (r *ocspResponder) respond(signer *ocspSigner, caPath string, sn *big.Int) ([]byte, error) {
+	snStr := formatSerialNumber(sn)
+	key := caPath + "/" + snStr
+	if der, ok := r.getCached(key); ok {
+		return der, nil
+	}
+
+	r.lock.Lock()
+	generation := r.generation
+	r.lock.Unlock()
+	record, err := getIssuedCertRecord(r.certName, snStr)
+	if err != nil {
+		return nil, err
+	}
+	der, nextUpdate, err := signer.sign(sn, record)
+	if err != nil {
+		return nil, err
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if generation != r.generation {
+		return der, nil
+	}
+	if record == nil {
+		r.cacheUnknown(key, cachedOcspResp{der: der, nextUpdate: nextUpdate})
+	} else if len(r.cache) < maxOcspCacheSize {
+		r.cache[key] = cachedOcspResp{der: der, nextUpdate: nextUpdate}
+	}
+	return der, nil
+}
+
+// cacheUnknown cache the response of unknown cert, the oldest one is dropped if the cache is full. The caller must
+// hold the lock of responder
+func (r *ocspResponder) cacheUnknown(key string, resp cachedOcspResp) {
+	if _, ok := r.unknownCache[key]; !ok {
+		for len(r.unknownKeys) > 0 && len(r.unknownCache) >= maxUnknownCacheSize {
+			delete(r.unknownCache, r.unknownKeys[0])
+			r.unknownKeys = r.unknownKeys[1:]
+		}
+		r.unknownKeys = append(r.unknownKeys, key)
+	}
+	r.unknownCache[key] = resp
+}
+
+// presign sign the responses of all valid issued certs in advance, so that requests can be answered from cache
+func (r *ocspResponder) presign() error {
+	caPath := getRootCaPath(r.certName)
+	if !fileutils.IsExist(caPath) {
+		return nil
+	}
+	records, err := getAllIssuedCertRecords(r.certName)
+	if err != nil {
+		return err
+	}
+	if len(records) == 0 {
+		return nil
+	}
+	signer, err := r.getSigner(caPath, getRootKeyPath(r.certName))
+	if err != nil {
+		return err
+	}
+	now := time.Now().Unix()
+	for _, record := range records {
+		if record.NotAfter < now {
+			continue
+		}
+		sn, err := parseSerialNumber(record.SerialNumber)
+		if err != nil {
+			hwlog.RunLog.Warnf("skip invalid [%s] issued cert record: %v", r.certName, err)
+			continue
+		}
+		if _, err = r.respond(signer, caPath, sn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func presignOcspResponses() {
+	for _, certName := range certchecker.GetOcspCertNames() {
+		if err := getOcspResponder(certName).presign(); err != nil {
+			hwlog.RunLog.Errorf("presign [%s] ocsp responses failed: %v", certName, err)
+		}
+	}
+}
+
+func ocspPresign(ctx context.Context) {
+	presignOcspResponses()
+	ticker := time.NewTicker(ocspPresignInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			hwlog.RunLog.Info("ocsp presign stopped")
+			return
+		case <-ticker.C:
+			presignOcspResponses()
+		}
+	}
+}
+
+// getOcspRespBySn get the ocsp response of cert issued by the current root ca
+func getOcspRespBySn(certName string, sn *big.Int) ([]byte, error) {
+	responder := getOcspResponder(certName)
+	caPath := getRootCaPath(certName)
+	signer, err := responder.getSigner(caPath, getRootKeyPath(certName))
+	if err != nil {
+		return nil, err
+	}
+	return responder.respond(signer, caPath, sn)
+}
+
+func getOcspResp(certName string, reqBytes []byte) []byte {
+	if !certchecker.CheckIfSupportOcsp(certName) {
+		hwlog.RunLog.Errorf("ocsp of [%s] is not supported", certName)
+		return ocsp.UnauthorizedErrorResponse
+	}
+	req, err := ocsp.ParseRequest(reqBytes)
+	if err != nil {
+		hwlog.RunLog.Errorf("parse ocsp request failed: %v", err)
+		return ocsp.MalformedRequestErrorResponse
+	}
+	responder := getOcspResponder(certName)
+	signer, caPath, err := responder.findSigner(req)
+	if err != nil {
+		hwlog.RunLog.Errorf("find [%s] ocsp signer failed: %v", certName, err)
+		return ocsp.UnauthorizedErrorResponse
+	}
+	respBytes, err := responder.respond(signer, caPath, req.SerialNumber)
+	if err != nil {
+		hwlog.RunLog.Errorf("get [%s] ocsp response failed: %v", certName, err)
+		return ocsp.InternalErrorErrorResponse
+	}
+	return respBytes
+}
+
+func readOcspRequest(c *gin.Context) ([]byte, error) {
+	if c.Request.Method == http.MethodGet {
+		reqStr, err := url.PathUnescape(strings.TrimPrefix(c.Param("request"), "/"))
+		if err != nil {
+			return nil, fmt.Errorf("unescape ocsp request failed: %v", err)
+		}
+		if len(reqStr) > base64.StdEncoding.EncodedLen(maxOcspRequestSize) {
+			return nil, errors.New("ocsp request is too large")
+		}
+		return base64.StdEncoding.DecodeString(reqStr)
+	}
+	if c.ContentType() != ocspRequestContentType {
+		return nil, fmt.Errorf("content type [%s] of ocsp request is not supported", c.ContentType())
+	}
+	reqBytes, err := io.ReadAll(io.LimitReader(c.Request.Body, maxOcspRequestSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("read ocsp request failed: %v", err)
+	}
+	if len(reqBytes) > maxOcspRequestSize {
+		return nil, errors.New("ocsp request is too large")
+	}
+	return reqBytes, nil
+}
+
+// HandleOcspRequest answer ocsp request for certs issued by mef root ca, both GET and POST in RFC 6960 are supported
+func HandleOcspRequest(c *gin.Context) {
+	certName := c.Param("certName")
+	var respBytes []byte
+	reqBytes, err := readOcspRequest(c)
+	if err != nil {
+		hwlog.RunLog.Errorf("read [%s] ocsp request failed: %v", certName, err)
+		respBytes = ocsp.MalformedRequestErrorResponse
+	} else {
+		respBytes = getOcspResp(certName, reqBytes)
+	}
+	c.Data(http.StatusOK, ocspResponseContentType, respBytes)
+}
@@ -0,0 +1,201 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package certmanager
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/smartystreets/goconvey/convey"
+	"golang.org/x/crypto/ocsp"
+
+	"huawei.com/mindx/common/fileutils"
+	"huawei.com/mindx/common/x509/certutils"
+
+	"huawei.com/mindxedge/base/common"
+)
+
+const testKeyLen = 2048
+
+func newTestCert(template, parent *x509.Certificate, parentKey *rsa.PrivateKey) (*x509.Certificate,
+	*rsa.PrivateKey) {
+	priv, err := rsa.GenerateKey(rand.Reader, testKeyLen)
+	if err != nil {
+		panic(err)
+	}
+	if parent == nil {
+		parent, parentKey = template, priv
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &priv.PublicKey, parentKey)
+	if err != nil {
+		panic(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		panic(err)
+	}
+	return cert, priv
+}
+
+func newTestCaAndLeaf() (*certutils.CaPairInfo, *x509.Certificate) {
+	now := time.Now()
+	caCert, caKey := newTestCert(&x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.AddDate(1, 0, 0),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}, nil, nil)
+	leaf, _ := newTestCert(&x509.Certificate{
+		SerialNumber: big.NewInt(0x1A2B),
+		Subject:      pkix.Name{CommonName: "test-leaf"},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.AddDate(0, 1, 0),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}, caCert, caKey)
+	return &certutils.CaPairInfo{Cert: caCert, PriKey: caKey}, leaf
+}
+
+func TestGetOcspResp(t *testing.T) {
+	caPair, leaf := newTestCaAndLeaf()
+	record := &issuedCertRecord{SerialNumber: formatSerialNumber(leaf.SerialNumber),
+		NotBefore: leaf.NotBefore.Unix(), NotAfter: leaf.NotAfter.Unix()}
+	patches := gomonkey.ApplyFuncReturn(certutils.GetCertPair, caPair, nil).
+		ApplyFuncReturn(loadCaCert, caPair.Cert, nil).
+		ApplyFuncReturn(fileutils.IsExist, false).
+		ApplyFunc(getIssuedCertRecord, func(string, string) (*issuedCertRecord, error) {
+			return record, nil
+		})
+	defer patches.Reset()
+	reqBytes, err := ocsp.CreateRequest(leaf, caPair.Cert, nil)
+	if err != nil {
+		panic(err)
+	}
+
+	convey.Convey("case: cert is good, response is signed by delegated responder", t, func() {
+		resp, err := ocsp.ParseResponseForCert(getOcspResp(common.WsSerName, reqBytes), leaf, caPair.Cert)
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(resp.Status, convey.ShouldEqual, ocsp.Good)
+		convey.So(resp.Certificate, convey.ShouldNotBeNil)
+		convey.So(resp.Certificate.ExtKeyUsage, convey.ShouldResemble, []x509.ExtKeyUsage{x509.ExtKeyUsageOCSPSigning})
+	})
+
+	convey.Convey("case: cert is revoked, cached response is invalidated", t, func() {
+		record.Revoked = true
+		record.RevokedAt = time.Now().Unix()
+		record.RevocationReason = ocsp.KeyCompromise
+		invalidateOcspCache(common.WsSerName, record.SerialNumber)
+		resp, err := ocsp.ParseResponseForCert(getOcspResp(common.WsSerName, reqBytes), leaf, caPair.Cert)
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(resp.Status, convey.ShouldEqual, ocsp.Revoked)
+		convey.So(resp.RevocationReason, convey.ShouldEqual, ocsp.KeyCompromise)
+	})
+
+	convey.Convey("case: cert is not issued by mef, the response is cached", t, func() {
+		record = nil
+		invalidateOcspCache(common.WsSerName, formatSerialNumber(leaf.SerialNumber))
+		respBytes := getOcspResp(common.WsSerName, reqBytes)
+		resp, err := ocsp.ParseResponseForCert(respBytes, leaf, caPair.Cert)
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(resp.Status, convey.ShouldEqual, ocsp.Unknown)
+		convey.So(getOcspResp(common.WsSerName, reqBytes), convey.ShouldResemble, respBytes)
+	})
+
+	convey.Convey("case: request is invalid", t, func() {
+		convey.So(getOcspResp(common.ImageCertName, reqBytes), convey.ShouldResemble, ocsp.UnauthorizedErrorResponse)
+		convey.So(getOcspResp(common.WsSerName, []byte(testContent)), convey.ShouldResemble,
+			ocsp.MalformedRequestErrorResponse)
+		otherCa, _ := newTestCaAndLeaf()
+		otherReq, err := ocsp.CreateRequest(leaf, otherCa.Cert, nil)
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(getOcspResp(common.WsSerName, otherReq), convey.ShouldResemble, ocsp.UnauthorizedErrorResponse)
+	})
+}
+
+func TestHandleOcspRequest(t *testing.T) {
+	reqBytes := []byte(testContent)
+	patch := gomonkey.ApplyFuncReturn(getOcspResp, []byte(testContent))
+	defer patch.Reset()
+	engine := gin.New()
+	engine.GET("/ocsp/:certName/*request", HandleOcspRequest)
+
+	convey.Convey("case: get method", t, func() {
+		w := httptest.NewRecorder()
+		reqUrl := "/ocsp/hub_svr/" + url.PathEscape(base64.StdEncoding.EncodeToString(reqBytes))
+		engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, reqUrl, nil))
+		convey.So(w.Code, convey.ShouldEqual, http.StatusOK)
+		convey.So(w.Header().Get("Content-Type"), convey.ShouldEqual, ocspResponseContentType)
+		convey.So(w.Body.String(), convey.ShouldEqual, testContent)
+	})
+
+	convey.Convey("case: request is not base64 encoded", t, func() {
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ocsp/hub_svr/%21%21", nil))
+		convey.So(w.Code, convey.ShouldEqual, http.StatusOK)
+		convey.So(w.Body.Bytes(), convey.ShouldResemble, ocsp.MalformedRequestErrorResponse)
+	})
+}
+
+func TestCacheUnknownOcspResp(t *testing.T) {
+	convey.Convey("case: the oldest unknown response is dropped when cache is full", t, func() {
+		responder := &ocspResponder{unknownCache: map[string]cachedOcspResp{}}
+		cached := cachedOcspResp{nextUpdate: time.Now().Add(ocspRespValidity)}
+		for i := 0; i <= maxUnknownCacheSize; i++ {
+			responder.cacheUnknown(fmt.Sprintf("ca/%X", i), cached)
+		}
+		convey.So(len(responder.unknownCache), convey.ShouldEqual, maxUnknownCacheSize)
+		_, ok := responder.getCached("ca/0")
+		convey.So(ok, convey.ShouldBeFalse)
+		_, ok = responder.getCached(fmt.Sprintf("ca/%X", maxUnknownCacheSize))
+		convey.So(ok, convey.ShouldBeTrue)
+	})
+}
+
+func TestMakeRoomForRecord(t *testing.T) {
+	now := time.Now()
+	records := map[string]*issuedCertRecord{}
+	for i := 0; i < maxIssuedCertRecords; i++ {
+		sn := fmt.Sprintf("%X", i+1)
+		records[sn] = &issuedCertRecord{SerialNumber: sn, NotAfter: now.Add(time.Duration(i+1) * time.Hour).Unix()}
+	}
+
+	convey.Convey("case: the good record expiring earliest is evicted", t, func() {
+		records["1"].Revoked = true
+		convey.So(makeRoomForRecord(common.WsSerName, records), convey.ShouldBeNil)
+		convey.So(len(records), convey.ShouldEqual, maxIssuedCertRecords-1)
+		convey.So(records["1"], convey.ShouldNotBeNil)
+		convey.So(records["2"], convey.ShouldBeNil)
+	})
+
+	convey.Convey("case: revoked records are never evicted", t, func() {
+		records["2"] = &issuedCertRecord{SerialNumber: "2", NotAfter: now.Add(time.Hour).Unix()}
+		for _, record := range records {
+			record.Revoked = true
+		}
+		convey.So(makeRoomForRecord(common.WsSerName, records), convey.ShouldNotBeNil)
+		convey.So(len(records), convey.ShouldEqual, maxIssuedCertRecords)
+	})
+}
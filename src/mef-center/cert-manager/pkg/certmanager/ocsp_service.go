@@ -0,0 +1,107 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package certmanager restful service of issued certs and their ocsp responses
+package certmanager
+
+import (
+	"encoding/base64"
+	"sort"
+
+	"huawei.com/mindx/common/hwlog"
+	"huawei.com/mindx/common/modulemgr/model"
+
+	"huawei.com/mindxedge/base/common"
+
+	"cert-manager/pkg/certmanager/certchecker"
+)
+
+type revokeCertJson struct {
+	CertName     string `json:"certName"`
+	SerialNumber string `json:"serialNumber"`
+	Reason       int    `json:"reason"`
+}
+
+type ocspRespJson struct {
+	CertName     string `json:"certName"`
+	SerialNumber string `json:"serialNumber"`
+}
+
+func getIssuedCerts(msg *model.Message) common.RespMsg {
+	var certName string
+	if err := msg.ParseContent(&certName); err != nil {
+		hwlog.RunLog.Errorf("query issued certs failed: parse content failed: %v", err)
+		return common.RespMsg{Status: common.ErrorParamConvert, Msg: "parse content failed", Data: nil}
+	}
+	if checkResult := certchecker.NewIssuedCertNameChecker().Check(certName); !checkResult.Result {
+		hwlog.RunLog.Errorf("query issued certs para check failed: %s", checkResult.Reason)
+		return common.RespMsg{Status: common.ErrorParamInvalid, Msg: "query issued certs para check failed", Data: nil}
+	}
+	records, err := getAllIssuedCertRecords(certName)
+	if err != nil {
+		hwlog.RunLog.Errorf("query [%s] issued certs failed: %v", certName, err)
+		return common.RespMsg{Status: common.ErrorGetRootCaInfo, Msg: "query issued certs failed", Data: nil}
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].NotBefore > records[j].NotBefore
+	})
+	hwlog.RunLog.Infof("query [%s] issued certs success", certName)
+	return common.RespMsg{Status: common.Success, Msg: "query issued certs success", Data: records}
+}
+
+func revokeIssuedCert(msg *model.Message) common.RespMsg {
+	var req revokeCertJson
+	if err := msg.ParseContent(&req); err != nil {
+		hwlog.RunLog.Errorf("parse content failed: %v", err)
+		return common.RespMsg{Status: common.ErrorParamConvert, Msg: "parse content failed", Data: nil}
+	}
+	if checkResult := certchecker.NewRevokeCertChecker().Check(req); !checkResult.Result {
+		hwlog.RunLog.Errorf("revoke cert para check failed: %s", checkResult.Reason)
+		return common.RespMsg{Status: common.ErrorParamInvalid, Msg: "revoke cert para check failed", Data: nil}
+	}
+	serialNumber, err := parseSerialNumber(req.SerialNumber)
+	if err != nil {
+		hwlog.RunLog.Errorf("revoke cert failed: %v", err)
+		return common.RespMsg{Status: common.ErrorParamInvalid, Msg: "serial number is invalid", Data: nil}
+	}
+	sn := formatSerialNumber(serialNumber)
+	if err = revokeIssuedCertRecord(req.CertName, sn, req.Reason); err != nil {
+		hwlog.RunLog.Errorf("revoke cert failed: %v", err)
+		return common.RespMsg{Status: common.ErrorRevokeCert, Msg: err.Error(), Data: nil}
+	}
+	hwlog.RunLog.Infof("revoke cert [%s] issued by [%s] success", sn, req.CertName)
+	return common.RespMsg{Status: common.Success, Msg: "revoke cert success", Data: nil}
+}
+
+// queryOcspResp query ocsp response for the given cert, which is used by ocsp stapling
+func queryOcspResp(msg *model.Message) common.RespMsg {
+	var req ocspRespJson
+	if err := msg.ParseContent(&req); err != nil {
+		hwlog.RunLog.Errorf("parse content failed: %v", err)
+		return common.RespMsg{Status: common.ErrorParamConvert, Msg: "parse content failed", Data: nil}
+	}
+	if checkResult := certchecker.NewOcspRespChecker().Check(req); !checkResult.Result {
+		hwlog.RunLog.Errorf("query ocsp response para check failed: %s", checkResult.Reason)
+		return common.RespMsg{Status: common.ErrorParamInvalid, Msg: "query ocsp response para check failed", Data: nil}
+	}
+	sn, err := parseSerialNumber(req.SerialNumber)
+	if err != nil {
+		hwlog.RunLog.Errorf("query ocsp response failed: %v", err)
+		return common.RespMsg{Status: common.ErrorParamInvalid, Msg: "serial number is invalid", Data: nil}
+	}
+	respBytes, err := getOcspRespBySn(req.CertName, sn)
+	if err != nil {
+		hwlog.RunLog.Errorf("query [%s] ocsp response failed: %v", req.CertName, err)
+		return common.RespMsg{Status: common.ErrorGetOcspResp, Msg: "query ocsp response failed", Data: nil}
+	}
+	hwlog.RunLog.Infof("query [%s] ocsp response success", req.CertName)
+	return common.RespMsg{Status: common.Success, Msg: "query ocsp response success",
+		Data: base64.StdEncoding.EncodeToString(respBytes)}
+}
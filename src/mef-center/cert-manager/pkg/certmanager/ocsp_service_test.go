@@ -0,0 +1,111 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package certmanager
+
+import (
+	"testing"
+	"time"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/smartystreets/goconvey/convey"
+
+	"huawei.com/mindx/common/test"
+
+	"huawei.com/mindxedge/base/common"
+)
+
+const testSerialNumber = "1A2B"
+
+func TestRevokeIssuedCert(t *testing.T) {
+	issuedCertIndexes = map[string]map[string]*issuedCertRecord{}
+	records := map[string]*issuedCertRecord{testSerialNumber: {SerialNumber: testSerialNumber,
+		NotAfter: time.Now().Add(time.Hour).Unix()}}
+	patches := gomonkey.ApplyFuncReturn(loadIssuedCertRecords, records, nil).
+		ApplyFuncReturn(saveIssuedCertRecords, nil)
+	defer patches.Reset()
+
+	convey.Convey("case: revoke success", t, func() {
+		msg := newMsgWithContentForUT(revokeCertJson{CertName: common.WsSerName, SerialNumber: "001a2b", Reason: 1})
+		resp := revokeIssuedCert(msg)
+		convey.So(resp.Status, convey.ShouldEqual, common.Success)
+		convey.So(records[testSerialNumber].Revoked, convey.ShouldBeTrue)
+		convey.So(records[testSerialNumber].RevocationReason, convey.ShouldEqual, 1)
+	})
+
+	convey.Convey("case: unrecorded cert is recorded as revoked", t, func() {
+		msg := newMsgWithContentForUT(revokeCertJson{CertName: common.WsSerName, SerialNumber: "FFFF"})
+		convey.So(revokeIssuedCert(msg).Status, convey.ShouldEqual, common.Success)
+		convey.So(records["FFFF"].Revoked, convey.ShouldBeTrue)
+		convey.So(records["FFFF"].NotAfter, convey.ShouldBeGreaterThan, time.Now().Unix())
+	})
+
+	convey.Convey("case: revoke failed", t, func() {
+		msg := newMsgWithContentForUT(revokeCertJson{CertName: common.WsSerName, SerialNumber: testSerialNumber})
+		convey.So(revokeIssuedCert(msg).Status, convey.ShouldEqual, common.ErrorRevokeCert)
+		msg = newMsgWithContentForUT(revokeCertJson{CertName: common.WsSerName, SerialNumber: "FFFF"})
+		convey.So(revokeIssuedCert(msg).Status, convey.ShouldEqual, common.ErrorRevokeCert)
+	})
+
+	convey.Convey("case: records are dropped from memory if saving failed", t, func() {
+		patch := gomonkey.ApplyFuncReturn(saveIssuedCertRecords, test.ErrTest)
+		defer patch.Reset()
+		msg := newMsgWithContentForUT(revokeCertJson{CertName: common.WsSerName, SerialNumber: "EEEE"})
+		convey.So(revokeIssuedCert(msg).Status, convey.ShouldEqual, common.ErrorRevokeCert)
+		_, ok := issuedCertIndexes[common.WsSerName]
+		convey.So(ok, convey.ShouldBeFalse)
+	})
+
+	convey.Convey("case: param is invalid", t, func() {
+		msg := newMsgWithContentForUT(revokeCertJson{CertName: common.ImageCertName, SerialNumber: testSerialNumber})
+		convey.So(revokeIssuedCert(msg).Status, convey.ShouldEqual, common.ErrorParamInvalid)
+		msg = newMsgWithContentForUT(revokeCertJson{CertName: common.WsSerName, SerialNumber: "xyz"})
+		convey.So(revokeIssuedCert(msg).Status, convey.ShouldEqual, common.ErrorParamInvalid)
+		const unusedReason = 7
+		msg = newMsgWithContentForUT(revokeCertJson{CertName: common.WsSerName, SerialNumber: testSerialNumber,
+			Reason: unusedReason})
+		convey.So(revokeIssuedCert(msg).Status, convey.ShouldEqual, common.ErrorParamInvalid)
+	})
+}
+
+func TestQueryOcspResp(t *testing.T) {
+	convey.Convey("case: query success", t, func() {
+		patch := gomonkey.ApplyFuncReturn(getOcspRespBySn, []byte(testContent), nil)
+		defer patch.Reset()
+		resp := queryOcspResp(newMsgWithContentForUT(ocspRespJson{CertName: common.NginxCertName,
+			SerialNumber: testSerialNumber}))
+		convey.So(resp.Status, convey.ShouldEqual, common.Success)
+	})
+
+	convey.Convey("case: query failed", t, func() {
+		patch := gomonkey.ApplyFuncReturn(getOcspRespBySn, nil, test.ErrTest)
+		defer patch.Reset()
+		resp := queryOcspResp(newMsgWithContentForUT(ocspRespJson{CertName: common.NginxCertName,
+			SerialNumber: testSerialNumber}))
+		convey.So(resp.Status, convey.ShouldEqual, common.ErrorGetOcspResp)
+	})
+}
+
+func TestGetIssuedCerts(t *testing.T) {
+	convey.Convey("case: query success", t, func() {
+		issuedCertIndexes = map[string]map[string]*issuedCertRecord{}
+		records := map[string]*issuedCertRecord{testSerialNumber: {SerialNumber: testSerialNumber}}
+		patch := gomonkey.ApplyFuncReturn(loadIssuedCertRecords, records, nil)
+		defer patch.Reset()
+		resp := getIssuedCerts(newMsgWithContentForUT(common.WsCltName))
+		convey.So(resp.Status, convey.ShouldEqual, common.Success)
+		convey.So(resp.Data, convey.ShouldResemble, []issuedCertRecord{{SerialNumber: testSerialNumber}})
+	})
+
+	convey.Convey("case: cert name is invalid", t, func() {
+		resp := getIssuedCerts(newMsgWithContentForUT(common.NorthernCertName))
+		convey.So(resp.Status, convey.ShouldEqual, common.ErrorParamInvalid)
+	})
+}
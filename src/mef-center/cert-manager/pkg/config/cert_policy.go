@@ -0,0 +1,30 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package config load the policy of imported third-party certs
+package config
+
+import (
+	"huawei.com/mindx/common/hwlog"
+	"huawei.com/mindx/common/x509"
+)
+
+// CertPolicyPath the policy of imported third-party certs, the default policy is used if it does not exist
+const CertPolicyPath = "/home/data/config/cert-policy.json"
+
+// GetCertPolicy load cert policy on every call, so that the modified policy takes effect without restarting
+func GetCertPolicy() (*x509.CertPolicy, error) {
+	policy, err := x509.LoadCertPolicy(CertPolicyPath)
+	if err != nil {
+		hwlog.RunLog.Errorf("load cert policy failed: %v", err)
+		return nil, err
+	}
+	return policy, nil
+}
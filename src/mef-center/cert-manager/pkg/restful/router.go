@@ -36,6 +36,14 @@ var certRouterDispatchers = map[string][]restfulmgr.DispatcherItf{
 			RelativePath: "/info",
 			Method:       http.MethodGet,
 			Destination:  common.CertManagerName}, "certName"},
+		restfulmgr.GenericDispatcher{
+			RelativePath: "/revoke",
+			Method:       http.MethodPost,
+			Destination:  common.CertManagerName},
+		queryDispatcher{restfulmgr.GenericDispatcher{
+			RelativePath: "/issued",
+			Method:       http.MethodGet,
+			Destination:  common.CertManagerName}, "certName"},
 	},
 	"/certmanager/v1/crl": {
 		restfulmgr.GenericDispatcher{
@@ -67,11 +75,18 @@ var innerCertRouterDispatchers = map[string][]restfulmgr.DispatcherItf{
 			RelativePath: "/imported-certs",
 			Method:       http.MethodGet,
 			Destination:  common.CertManagerName},
+		restfulmgr.GenericDispatcher{
+			RelativePath: "/ocsp-response",
+			Method:       http.MethodPost,
+			Destination:  common.CertManagerName},
 	},
 }
 
 func setRouter(engine *gin.Engine) {
 	engine.GET("/certmanager/v1/export", certmanager.ExportRootCa)
+	engine.POST("/certmanager/v1/export", certmanager.ExportRootCaBundle)
+	engine.POST("/certmanager/v1/ocsp/:certName", certmanager.HandleOcspRequest)
+	engine.GET("/certmanager/v1/ocsp/:certName/*request", certmanager.HandleOcspRequest)
 	restfulmgr.InitRouter(engine, certRouterDispatchers)
 	restfulmgr.InitRouter(engine, innerCertRouterDispatchers)
 }
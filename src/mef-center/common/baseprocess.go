@@ -53,6 +53,12 @@ func ClearSliceByteMemory(sliceByte []byte) {
 
 // SendSyncMessageByRestful send sync message by restful
 func SendSyncMessageByRestful(input interface{}, router *Router, timeout time.Duration) RespMsg {
+	return SendSyncMessageByRestfulWithTrace(input, router, timeout, "")
+}
+
+// SendSyncMessageByRestfulWithTrace send sync message by restful, the message carries the trace id of request
+func SendSyncMessageByRestfulWithTrace(input interface{}, router *Router, timeout time.Duration,
+	traceId string) RespMsg {
 	msg, err := model.NewMessage()
 	if err != nil {
 		hwlog.RunLog.Errorf("new message error: %v", err)
@@ -60,6 +66,7 @@ func SendSyncMessageByRestful(input interface{}, router *Router, timeout time.Du
 	}
 
 	msg.SetRouter(router.Source, router.Destination, router.Option, router.Resource)
+	msg.SetTraceId(traceId)
 	if err = msg.FillContent(input); err != nil {
 		hwlog.RunLog.Errorf("fill content failed: %v", err)
 		return RespMsg{Status: ErrorsSendSyncMessageByRestful, Msg: "", Data: nil}
@@ -67,7 +74,7 @@ func SendSyncMessageByRestful(input interface{}, router *Router, timeout time.Du
 
 	respMsg, err := modulemgr.SendSyncMessage(msg, timeout)
 	if err != nil {
-		hwlog.RunLog.Errorf("get response error: %v", err)
+		hwlog.RunLog.ErrorfWithCtx(msg.TraceContext(), "get response error: %v", err)
 		return RespMsg{Status: ErrorsSendSyncMessageByRestful, Msg: "", Data: nil}
 	}
 	var resp RespMsg
@@ -63,6 +63,7 @@ const (
 	ConfigManagerName    = "ConfigManager"
 	AlarmManagerName     = "AlarmManager"
 	CertUpdaterName      = "CertUpdater"
+	OutboxName           = "Outbox"
 )
 
 const (
@@ -104,6 +105,8 @@ const (
 	RestfulTimeout = 6 * time.Minute
 	// EdgeManagerRestfulWriteTimeout edge-manager restful write timeout time
 	EdgeManagerRestfulWriteTimeout = 2 * time.Hour
+	// TraceIdHeader response header carrying the trace id of restful request
+	TraceIdHeader = "X-Trace-Id"
 )
 
 const (
@@ -171,6 +174,12 @@ const (
 	ResEdgeDownloadInfo = "/edge/download"
 	// ResEdgeUpgradeInfo resource for effect software
 	ResEdgeUpgradeInfo = "/edge/upgrade"
+	// ResEdgeRollbackInfo resource for rolling back software to the inactive version
+	ResEdgeRollbackInfo = "/edge/rollback"
+	// ResEdgePreflight resource for checking whether the software can be upgraded without modifying anything
+	ResEdgePreflight = "/edge/preflight"
+	// ResEdgePreflightResult resource for edge reporting the results of preflight
+	ResEdgePreflightResult = "/edge/preflight/result"
 	// ResDownloadProgress resource progress report
 	ResDownloadProgress = "/edge/download-progress"
 	// ResSoftwareInfo resource software info
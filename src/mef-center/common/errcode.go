@@ -76,6 +76,8 @@ const (
 
 	// ErrorGetNodeSoftwareVersion failed to get node software version
 	ErrorGetNodeSoftwareVersion = "40012018"
+	// ErrorGetNodeConnStats failed to get the traffic statistics of node connection
+	ErrorGetNodeConnStats = "40012020"
 
 	// ErrorCheckAppMrgSize failed to check data size while creating
 	ErrorCheckAppMrgSize = "40021000"
@@ -177,6 +179,10 @@ const (
 	ErrorSaveCrl = "60001011"
 	// ErrorGetImportedCertsInfo failed to get imported certs info
 	ErrorGetImportedCertsInfo = "60001012"
+	// ErrorRevokeCert failed to revoke issued certificate
+	ErrorRevokeCert = "60001013"
+	// ErrorGetOcspResp failed to get ocsp response of issued certificate
+	ErrorGetOcspResp = "60001014"
 	// ErrorExportToken export token failed
 	ErrorExportToken = "60002001"
 	// ErrorContentTypeError message content type error
@@ -185,6 +191,24 @@ const (
 	ErrorCertTypeError = "60002003"
 	// ErrorNodeNotFound message content type error
 	ErrorNodeNotFound = "60002004"
+	// ErrorGetCertUpdateStatus failed to get edge nodes cert update status
+	ErrorGetCertUpdateStatus = "60002005"
+	// ErrorRetryCertUpdate failed to retry edge nodes cert update
+	ErrorRetryCertUpdate = "60002006"
+	// ErrorGetOutboxMessages failed to get pending outbox messages of edge node
+	ErrorGetOutboxMessages = "60002007"
+	// ErrorRemoteShellSession failed to operate remote shell session of edge node
+	ErrorRemoteShellSession = "60002008"
+	// ErrorPortForwardTunnel failed to operate port forward tunnel of edge node
+	ErrorPortForwardTunnel = "60002009"
+	// ErrorQueryTrace failed to query the log lines of a trace
+	ErrorQueryTrace = "60002010"
+	// ErrorUpgradeCampaign failed to operate upgrade campaign of edge software
+	ErrorUpgradeCampaign = "60002011"
+	// ErrorArtifact failed to operate artifact in the repository of edge-manager
+	ErrorArtifact = "60002012"
+	// ErrorUpgradePreflight failed to check the nodes before upgrading edge software
+	ErrorUpgradePreflight = "60002013"
 )
 
 // ErrorMap error code and error msg map
@@ -226,6 +250,8 @@ var ErrorMap = map[string]string{
 	ErrorSendMsgToNode: "failed to send msg to node",
 	// ErrorDeleteNodeFromGroup failed to send msg to node
 	ErrorGetNodeSoftwareVersion: "failed to get node version",
+	// ErrorGetNodeConnStats failed to get the traffic statistics of node connection
+	ErrorGetNodeConnStats: "failed to get node connection statistics",
 	// ErrorGetConfigData failed to get token
 	ErrorGetConfigData: "failed to get token",
 	// ErrorNodeGroupNotFound node group not found
@@ -303,6 +329,10 @@ var ErrorMap = map[string]string{
 	ErrorExportRootCa: "failed to export root ca",
 	// ErrorGetImportedCertsInfo failed to get imported certs info
 	ErrorGetImportedCertsInfo: "failed to get imported certs info",
+	// ErrorRevokeCert failed to revoke issued certificate
+	ErrorRevokeCert: "failed to revoke issued certificate",
+	// ErrorGetOcspResp failed to get ocsp response of issued certificate
+	ErrorGetOcspResp: "failed to get ocsp response of issued certificate",
 
 	// ErrorAccountOrPassword incorrect account or password
 	ErrorAccountOrPassword: "incorrect account or password",
@@ -346,4 +376,22 @@ var ErrorMap = map[string]string{
 	ErrorCertTypeError: "cert type error",
 
 	ErrorNodeNotFound: "empty node found",
+
+	ErrorGetCertUpdateStatus: "failed to get edge nodes cert update status",
+
+	ErrorRetryCertUpdate: "failed to retry edge nodes cert update",
+
+	ErrorGetOutboxMessages: "failed to get pending outbox messages of edge node",
+
+	ErrorRemoteShellSession: "failed to operate remote shell session of edge node",
+
+	ErrorPortForwardTunnel: "failed to operate port forward tunnel of edge node",
+
+	ErrorQueryTrace: "failed to query the log lines of trace",
+
+	ErrorUpgradeCampaign: "failed to operate upgrade campaign of edge software",
+
+	ErrorArtifact: "failed to operate artifact in the repository",
+
+	ErrorUpgradePreflight: "failed to check the nodes before upgrading edge software",
 }
@@ -28,9 +28,15 @@ const (
 	getRootCaUrl            = "inner/v1/certificates/rootca"
 	getCrlUrl               = "inner/v1/certificates/crl"
 	getImportedCertsInfoUrl = "inner/v1/certificates/imported-certs"
+	getOcspRespUrl          = "inner/v1/certificates/ocsp-response"
 	updateCertUrl           = "inner/v1/image/update"
 )
 
+type reqOcspRespBody struct {
+	CertName     string `json:"certName"`
+	SerialNumber string `json:"serialNumber"`
+}
+
 type reqIssueCertBody struct {
 	CertName string `json:"certName"`
 	Csr      string `json:"csr"`
@@ -118,6 +124,21 @@ func (rcp *ReqCertParams) GetImportedCertsInfo() (string, error) {
 	return rcp.parseResp(resp)
 }
 
+// GetOcspResp [method] for getting base64 encoded ocsp response of the cert issued by mef root ca
+func (rcp *ReqCertParams) GetOcspResp(certName, serialNumber string) (string, error) {
+	url := fmt.Sprintf("https://%s:%d/%s", common.CertMgrDns, common.CertMgrPort, getOcspRespUrl)
+	httpsReq := httpsmgr.GetHttpsReq(url, rcp.ClientTlsCert)
+	jsonBody, err := json.Marshal(&reqOcspRespBody{CertName: certName, SerialNumber: serialNumber})
+	if err != nil {
+		return "", err
+	}
+	respBytes, err := httpsReq.PostJson(jsonBody)
+	if err != nil {
+		return "", err
+	}
+	return rcp.parseResp(respBytes)
+}
+
 func (rcp *ReqCertParams) parseResp(respBytes []byte) (string, error) {
 	var resp common.RespMsg
 	err := json.Unmarshal(respBytes, &resp)
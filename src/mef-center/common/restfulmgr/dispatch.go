@@ -12,6 +12,7 @@
 package restfulmgr
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"path/filepath"
@@ -20,6 +21,7 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"huawei.com/mindx/common/hwlog"
+	"huawei.com/mindx/common/modulemgr/model"
 
 	"huawei.com/mindxedge/base/common"
 )
@@ -52,8 +54,8 @@ func (g GenericDispatcher) ParseData(c *gin.Context) (interface{}, error) {
 	return string(data), nil
 }
 
-// sendToModule [method] send to other module
-func (g GenericDispatcher) sendToModule(resource string, data interface{}) common.RespMsg {
+// sendToModule [method] send to other module, the message carries the trace id of request
+func (g GenericDispatcher) sendToModule(resource string, data interface{}, traceId string) common.RespMsg {
 	router := common.Router{
 		Source:      common.RestfulServiceName,
 		Destination: g.Destination,
@@ -61,7 +63,7 @@ func (g GenericDispatcher) sendToModule(resource string, data interface{}) commo
 		Resource:    resource,
 	}
 
-	return common.SendSyncMessageByRestful(data, &router, common.RestfulTimeout)
+	return common.SendSyncMessageByRestfulWithTrace(data, &router, common.RestfulTimeout, traceId)
 }
 
 func (g GenericDispatcher) response(c *gin.Context, result common.RespMsg) {
@@ -86,8 +88,16 @@ func (g GenericDispatcher) getRelativePath() string {
 
 func (g GenericDispatcher) dispatch(c *gin.Context) {
 	var res common.RespMsg
+	traceId, err := model.NewTraceId()
+	if err != nil {
+		hwlog.RunLog.Warnf("create trace id failed: %v", err)
+	}
+	ctx := context.WithValue(context.Background(), hwlog.ReqID, traceId)
+	c.Header(common.TraceIdHeader, traceId)
+	hwlog.RunLog.InfofWithCtx(ctx, "start deal %s %s", g.getMethod(), c.FullPath())
 	defer func() {
-		hwlog.RunLog.Infof("deal %s result is %t, msg:%s", c.FullPath(), res.Status == common.Success, res.Msg)
+		hwlog.RunLog.InfofWithCtx(ctx, "deal %s result is %t, msg:%s", c.FullPath(), res.Status == common.Success,
+			res.Msg)
 
 		if g.getMethod() == http.MethodGet {
 			return
@@ -114,7 +124,7 @@ func (g GenericDispatcher) dispatch(c *gin.Context) {
 		return
 	}
 
-	res = g.sendToModule(c.FullPath(), data)
+	res = g.sendToModule(c.FullPath(), data, traceId)
 	g.response(c, res)
 	return
 }
@@ -12,6 +12,7 @@
 package main
 
 import (
+	"compress/flate"
 	"context"
 	"encoding/json"
 	"errors"
@@ -33,6 +34,7 @@ import (
 	"huawei.com/mindx/common/modulemgr"
 
 	"edge-manager/pkg/appmanager"
+	"edge-manager/pkg/artifactmanager"
 	"edge-manager/pkg/certupdater"
 	"edge-manager/pkg/cloudhub"
 	"edge-manager/pkg/config"
@@ -41,7 +43,11 @@ import (
 	"edge-manager/pkg/kubeclient"
 	"edge-manager/pkg/logmanager"
 	"edge-manager/pkg/nodemanager"
+	"edge-manager/pkg/outbox"
+	"edge-manager/pkg/portforward"
+	"edge-manager/pkg/remoteshell"
 	"edge-manager/pkg/restfulservice"
+	"edge-manager/pkg/trace"
 
 	"huawei.com/mindxedge/base/common"
 	"huawei.com/mindxedge/base/common/logmgmt/hwlogconfig"
@@ -71,6 +77,9 @@ const (
 	maxBurstIP            = 100
 	defaultDataLimit      = 1024 * 1024
 	defaultCachSize       = 1024 * 1024 * 10
+	// status sync messages are json and compress well, small messages are sent as they are
+	defaultCompressThreshold = 1024
+	maxCompressThreshold     = 1024 * 1024
 )
 
 var (
@@ -91,6 +100,9 @@ var (
 	limitIPConn    int
 	limitTotalConn int
 	dataLimit      int64
+
+	wsCompressThreshold int
+	wsCompressLevel     int
 )
 
 func main() {
@@ -152,6 +164,10 @@ func init() {
 		"The max concurrency of the http server, range is [1-512]")
 	flag.Int64Var(&dataLimit, "dataLimit", defaultDataLimit,
 		"bytes, limit the data size of request's body, the default value is 1MB")
+	flag.IntVar(&wsCompressThreshold, "wsCompressThreshold", defaultCompressThreshold,
+		"bytes, the messages to edge nodes not smaller than it are compressed, range[0-1048576]")
+	flag.IntVar(&wsCompressLevel, "wsCompressLevel", flate.BestSpeed,
+		"The flate compression level of the messages to edge nodes, range[1-9]")
 	hwlogconfig.BindFlags(serverOpConf, serverRunConf)
 }
 
@@ -168,6 +184,14 @@ func validateFlags() error {
 	if err := common.LimitChecker(getLimitParam(), maxConcurrency, maxIPConnLimit); err != nil {
 		return err
 	}
+	if res := checker.GetIntChecker("", 0, maxCompressThreshold, true).Check(wsCompressThreshold); !res.Result {
+		return fmt.Errorf("wsCompressThreshold %d is not in [0, %d]", wsCompressThreshold, maxCompressThreshold)
+	}
+	levelChecker := checker.GetIntChecker("", flate.BestSpeed, flate.BestCompression, true)
+	if res := levelChecker.Check(wsCompressLevel); !res.Result {
+		return fmt.Errorf("wsCompressLevel %d is not in [%d, %d]", wsCompressLevel, flate.BestSpeed,
+			flate.BestCompression)
+	}
 	if authPort == wsPort {
 		return fmt.Errorf("authPort can not equals to wsPort")
 	}
@@ -287,7 +311,7 @@ func register(ctx context.Context) error {
 		return err
 	}
 	if err := modulemgr.Registry(cloudhub.NewCloudServer(true, wsPort, common.EdgeManagerInnerWsPort,
-		authPort, maxClientNum)); err != nil {
+		authPort, maxClientNum).SetCompressionCfg(wsCompressThreshold, wsCompressLevel)); err != nil {
 		return err
 	}
 	if err := modulemgr.Registry(edgemsgmanager.NewNodeMsgManager(true)); err != nil {
@@ -299,6 +323,21 @@ func register(ctx context.Context) error {
 	if err := modulemgr.Registry(logmanager.NewLogManager(ctx, true)); err != nil {
 		return err
 	}
+	if err := modulemgr.Registry(outbox.NewOutboxManager(true)); err != nil {
+		return err
+	}
+	if err := modulemgr.Registry(remoteshell.NewRemoteShellManager(true)); err != nil {
+		return err
+	}
+	if err := modulemgr.Registry(portforward.NewPortForwardManager(true)); err != nil {
+		return err
+	}
+	if err := modulemgr.Registry(trace.NewTraceManager(true)); err != nil {
+		return err
+	}
+	if err := modulemgr.Registry(artifactmanager.NewArtifactManager(true)); err != nil {
+		return err
+	}
 	modulemgr.Start()
 	return nil
 }
@@ -215,27 +215,28 @@ func getNodeGroupInfoList(appId uint64) ([]types.NodeGroupInfo, error) {
 
 // deployApp deploy application on node group
 func deployApp(msg *model.Message) common.RespMsg {
-	hwlog.RunLog.Info("start deploy app")
+	ctx := msg.TraceContext()
+	hwlog.RunLog.InfoWithCtx(ctx, "start deploy app")
 
 	var req DeployAppReq
 	if err := msg.ParseContent(&req); err != nil {
-		hwlog.RunLog.Errorf("parse content failed: %v", err)
+		hwlog.RunLog.ErrorfWithCtx(ctx, "parse content failed: %v", err)
 		return common.RespMsg{Status: common.ErrorParamConvert, Msg: "parse content failed", Data: nil}
 	}
 
 	if checkResult := appchecker.NewDeployAppChecker().Check(req); !checkResult.Result {
-		hwlog.RunLog.Errorf("deploy app para check failed: %s", checkResult.Reason)
+		hwlog.RunLog.ErrorfWithCtx(ctx, "deploy app para check failed: %s", checkResult.Reason)
 		return common.RespMsg{Status: common.ErrorParamInvalid, Msg: checkResult.Reason, Data: nil}
 	}
 
 	appInfo, err := AppRepositoryInstance().getAppInfoById(req.AppID)
 	if err == gorm.ErrRecordNotFound {
-		hwlog.RunLog.Errorf("app id [%d] not exist", req.AppID)
+		hwlog.RunLog.ErrorfWithCtx(ctx, "app id [%d] not exist", req.AppID)
 		return common.RespMsg{Status: common.ErrorAppMrgRecodeNoFound,
 			Msg: fmt.Sprintf("app id [%d] not exist, deploy app failed", req.AppID), Data: nil}
 	}
 	if err != nil {
-		hwlog.RunLog.Error("get app info error, deploy app failed")
+		hwlog.RunLog.ErrorWithCtx(ctx, "get app info error, deploy app failed")
 		return common.RespMsg{Status: common.ErrorDeployApp, Msg: "get app info error, deploy app failed", Data: nil}
 	}
 	deployRes, successGroups := deployAppToNodeGroups(appInfo, req.NodeGroupIds)
@@ -245,7 +246,7 @@ func deployApp(msg *model.Message) common.RespMsg {
 		return common.RespMsg{Status: common.ErrorDeployApp, Msg: "", Data: deployRes}
 	}
 
-	hwlog.RunLog.Info("all app daemonSets create success")
+	hwlog.RunLog.InfoWithCtx(ctx, "all app daemonSets create success")
 	return common.RespMsg{Status: common.Success, Msg: "", Data: nil}
 }
 
@@ -291,6 +292,11 @@ func deployAppToSingleNodeGroup(appInfo *AppInfo, nodeGroupId uint64, groupName
 			appInfo.AppName, nodeGroupId, groupName, err.Error())
 		return fmt.Errorf("init daemonSet app [%s] failed: %v", appInfo.AppName, err)
 	}
+	if err := checkNodeGroupFeatures(nodeGroupId, daemonSet); err != nil {
+		hwlog.RunLog.Errorf("check app [%s] features on node group id [%d](name=%s) failed: %s",
+			appInfo.AppName, nodeGroupId, groupName, err.Error())
+		return fmt.Errorf("check app [%s] features failed: %v", appInfo.AppName, err)
+	}
 	if err := checkNodeGroupResource(nodeGroupId, daemonSet); err != nil {
 		hwlog.RunLog.Errorf("check app [%s] resources on node group id [%d](name=%s) failed: %s",
 			appInfo.AppName, nodeGroupId, groupName, err.Error())
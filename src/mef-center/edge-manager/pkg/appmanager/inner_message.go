@@ -13,6 +13,7 @@ package appmanager
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 
 	appv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -22,7 +23,9 @@ import (
 	"huawei.com/mindx/common/modulemgr/model"
 
 	"huawei.com/mindxedge/base/common"
+	"huawei.com/mindxedge/base/common/requests"
 
+	"edge-manager/pkg/nodeprotocol"
 	"edge-manager/pkg/types"
 )
 
@@ -98,6 +101,61 @@ func getAppResReqs(daemonSet *appv1.DaemonSet) corev1.ResourceList {
 	return appResReqs
 }
 
+func getNodeSnsByGroup(nodeGroupId uint64) ([]string, error) {
+	router := common.Router{
+		Source:      common.AppManagerName,
+		Destination: common.NodeManagerName,
+		Option:      common.Get,
+		Resource:    common.GetSnsByGroup,
+	}
+	req, err := json.Marshal(requests.GetSnsReq{GroupId: nodeGroupId})
+	if err != nil {
+		return nil, errors.New("marshal request error")
+	}
+	resp := common.SendSyncMessageByRestful(string(req), &router, common.ResponseTimeout)
+	var sns []string
+	if err = parseDataFromResp(resp, &sns); err != nil {
+		return nil, err
+	}
+	return sns, nil
+}
+
+// getAppFeatures gets the features of app which need the support of edge nodes
+func getAppFeatures(daemonSet *appv1.DaemonSet) []nodeprotocol.Feature {
+	if daemonSet == nil {
+		return nil
+	}
+	for _, container := range daemonSet.Spec.Template.Spec.Containers {
+		for _, port := range container.Ports {
+			if port.Protocol == corev1.ProtocolUDP {
+				return []nodeprotocol.Feature{nodeprotocol.FeatureUdpContainerPort}
+			}
+		}
+	}
+	return nil
+}
+
+// checkNodeGroupFeatures refuses to deploy app to the node group, if any node of the group does not support the
+// features of app
+func checkNodeGroupFeatures(nodeGroupId uint64, daemonSet *appv1.DaemonSet) error {
+	features := getAppFeatures(daemonSet)
+	if len(features) == 0 {
+		return nil
+	}
+	sns, err := getNodeSnsByGroup(nodeGroupId)
+	if err != nil {
+		return fmt.Errorf("get nodes of group failed: %v", err)
+	}
+	for _, sn := range sns {
+		for _, feature := range features {
+			if err = nodeprotocol.CheckSupport(sn, feature); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 func getNodeGroupInfos(nodeGroupIds []uint64) ([]types.NodeGroupInfo, error) {
 	router := common.Router{
 		Source:      common.AppManagerName,
@@ -23,6 +23,7 @@ import (
 	"huawei.com/mindx/common/modulemgr/model"
 	"huawei.com/mindx/common/test"
 
+	"edge-manager/pkg/nodeprotocol"
 	"edge-manager/pkg/types"
 	"huawei.com/mindxedge/base/common"
 )
@@ -35,6 +36,7 @@ func TestInnerMessage(t *testing.T) {
 	convey.Convey("test getNodeInfoByUniqueName", t, testGetNodeInfoByUniqueName)
 	convey.Convey("test getNodeStatus", t, testGetNodeStatus)
 	convey.Convey("test getAppResReqs", t, testGetAppResReqs)
+	convey.Convey("test checkNodeGroupFeatures", t, testCheckNodeGroupFeatures)
 }
 
 func testGetAppInstanceCountByNodeGroup() {
@@ -186,3 +188,26 @@ func testGetAppResReqs() {
 	convey.So(reqs.Cpu().Value(), convey.ShouldResemble, exceptedCpu.Value())
 	convey.So(reqs.Memory().Value(), convey.ShouldResemble, exceptedMemory.Value())
 }
+
+func testCheckNodeGroupFeatures() {
+	const testSn = "2102312NSF10K8000130"
+	set := &appv1.DaemonSet{}
+	set.Spec.Template.Spec.Containers = []corev1.Container{
+		{Ports: []corev1.ContainerPort{{ContainerPort: 8080, Protocol: corev1.ProtocolUDP}}},
+	}
+	patches := gomonkey.ApplyFuncReturn(common.SendSyncMessageByRestful,
+		common.RespMsg{Status: common.Success, Data: []string{testSn}})
+	defer patches.Reset()
+
+	nodeprotocol.Record(testSn, nodeprotocol.CurrentVersion, []string{})
+	err := checkNodeGroupFeatures(1, set)
+	convey.So(errors.Is(err, nodeprotocol.ErrUnsupported), convey.ShouldBeTrue)
+
+	nodeprotocol.Record(testSn, nodeprotocol.CurrentVersion,
+		[]string{nodeprotocol.FeatureUdpContainerPort.Capability})
+	convey.So(checkNodeGroupFeatures(1, set), convey.ShouldBeNil)
+
+	set.Spec.Template.Spec.Containers[0].Ports[0].Protocol = corev1.ProtocolTCP
+	nodeprotocol.Record(testSn, nodeprotocol.CurrentVersion, []string{})
+	convey.So(checkNodeGroupFeatures(1, set), convey.ShouldBeNil)
+}
@@ -0,0 +1,272 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package artifactmanager handlers for creating, committing, querying and deleting artifacts
+package artifactmanager
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"huawei.com/mindx/common/checker"
+	"huawei.com/mindx/common/hwlog"
+	"huawei.com/mindx/common/modulemgr/model"
+
+	"huawei.com/mindxedge/base/common"
+	"huawei.com/mindxedge/base/common/logmgmt"
+
+	"edge-manager/pkg/constants"
+	"edge-manager/pkg/logmanager/utils"
+	"edge-manager/pkg/types"
+	"edge-manager/pkg/util"
+)
+
+func createArtifactHandler(msg *model.Message) common.RespMsg {
+	hwlog.RunLog.Info("start create artifact")
+	var req CreateArtifactReq
+	if err := msg.ParseContent(&req); err != nil {
+		hwlog.RunLog.Errorf("parse content failed: %v", err)
+		return common.RespMsg{Status: common.ErrorParamConvert, Msg: "parse content failed", Data: nil}
+	}
+	if checkResult := newCreateArtifactChecker().Check(req); !checkResult.Result {
+		hwlog.RunLog.Errorf("check create artifact para failed: %s", checkResult.Reason)
+		return common.RespMsg{Status: common.ErrorParamInvalid, Msg: checkResult.Reason, Data: nil}
+	}
+	id, err := doCreateArtifact(req)
+	if err != nil {
+		hwlog.RunLog.Errorf("create artifact [%s] failed: %v", req.Name, err)
+		return common.RespMsg{Status: common.ErrorArtifact, Msg: err.Error(), Data: nil}
+	}
+	hwlog.RunLog.Infof("create artifact [%s] success, id: %s", req.Name, id)
+	return common.RespMsg{Status: common.Success, Msg: "", Data: id}
+}
+
+func doCreateArtifact(req CreateArtifactReq) (string, error) {
+	count, err := countArtifacts()
+	if err != nil {
+		return "", errors.New("count artifacts failed")
+	}
+	if count >= maxArtifacts {
+		return "", fmt.Errorf("the count of artifacts reaches the limit %d", maxArtifacts)
+	}
+	id, err := newArtifactId()
+	if err != nil {
+		return "", err
+	}
+	if err = createArtifactDir(id); err != nil {
+		return "", err
+	}
+	if err = utils.CheckDiskSpace(artifactRootDir, uint64(req.Size)+maxSignFileSize*2); err != nil {
+		removeArtifactDir(id)
+		return "", errors.New("no enough disk space for the artifact")
+	}
+	now := time.Now().Unix()
+	record := &artifactRecord{
+		Id:          id,
+		Name:        req.Name,
+		Type:        req.Type,
+		Version:     req.Version,
+		Description: req.Description,
+		Size:        req.Size,
+		Sha256:      req.Sha256,
+		Status:      statusUploading,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err = createArtifact(record); err != nil {
+		removeArtifactDir(id)
+		hwlog.RunLog.Errorf("record artifact failed: %v", err)
+		return "", errors.New("the artifact of the same name, type and version exists")
+	}
+	return id, nil
+}
+
+func commitArtifactHandler(msg *model.Message) common.RespMsg {
+	hwlog.RunLog.Info("start commit artifact")
+	var req CommitArtifactReq
+	if err := msg.ParseContent(&req); err != nil {
+		hwlog.RunLog.Errorf("parse content failed: %v", err)
+		return common.RespMsg{Status: common.ErrorParamConvert, Msg: "parse content failed", Data: nil}
+	}
+	if res := checker.GetRegChecker("ID", constants.ArtifactIdRegexpStr, true).Check(req); !res.Result {
+		hwlog.RunLog.Errorf("check artifact id failed: %s", res.Reason)
+		return common.RespMsg{Status: common.ErrorParamInvalid, Msg: res.Reason, Data: nil}
+	}
+	lock := getArtifactLock(req.ID)
+	lock.Lock()
+	defer lock.Unlock()
+	record, err := getArtifactById(req.ID)
+	if err != nil {
+		hwlog.RunLog.Errorf("get artifact [%s] failed: %v", req.ID, err)
+		return common.RespMsg{Status: common.ErrorArtifact, Msg: "artifact is not found", Data: nil}
+	}
+	if record.Status != statusUploading {
+		hwlog.RunLog.Errorf("artifact [%s] is already committed", req.ID)
+		return common.RespMsg{Status: common.ErrorArtifact, Msg: "artifact is already committed", Data: nil}
+	}
+	if err = verifyArtifact(record); err != nil {
+		hwlog.RunLog.Errorf("verify artifact [%s] failed: %v", req.ID, err)
+		return common.RespMsg{Status: common.ErrorArtifact, Msg: err.Error(), Data: nil}
+	}
+	if err = updateArtifactStatus(req.ID, StatusReady, time.Now().Unix()); err != nil {
+		hwlog.RunLog.Errorf("update status of artifact [%s] failed: %v", req.ID, err)
+		return common.RespMsg{Status: common.ErrorArtifact, Msg: "update artifact status failed", Data: nil}
+	}
+	hwlog.RunLog.Infof("commit artifact [%s] success", req.ID)
+	return common.RespMsg{Status: common.Success, Msg: "", Data: nil}
+}
+
+func queryArtifactHandler(msg *model.Message) common.RespMsg {
+	var id string
+	if err := msg.ParseContent(&id); err != nil {
+		hwlog.RunLog.Errorf("parse content failed: %v", err)
+		return common.RespMsg{Status: common.ErrorParamConvert, Msg: "parse content failed", Data: nil}
+	}
+	if res := checker.GetRegChecker("", constants.ArtifactIdRegexpStr, true).Check(id); !res.Result {
+		hwlog.RunLog.Errorf("check artifact id failed: %s", res.Reason)
+		return common.RespMsg{Status: common.ErrorParamInvalid, Msg: res.Reason, Data: nil}
+	}
+	record, err := getArtifactById(id)
+	if err != nil {
+		hwlog.RunLog.Errorf("get artifact [%s] failed: %v", id, err)
+		return common.RespMsg{Status: common.ErrorArtifact, Msg: "artifact is not found", Data: nil}
+	}
+	info := newArtifactInfo(*record)
+	if record.Status == statusUploading {
+		info.UploadedSize = getUploadedSize(record, partPackage)
+	}
+	return common.RespMsg{Status: common.Success, Msg: "", Data: info}
+}
+
+func listArtifactsHandler(msg *model.Message) common.RespMsg {
+	var req types.ListReq
+	if err := msg.ParseContent(&req); err != nil {
+		hwlog.RunLog.Errorf("list artifacts parse content failed: %v", err)
+		return common.RespMsg{Status: common.ErrorParamConvert, Msg: "parse content failed", Data: nil}
+	}
+	if checkResult := util.NewPaginationQueryChecker().Check(req); !checkResult.Result {
+		hwlog.RunLog.Errorf("list artifacts para check failed: %s", checkResult.Reason)
+		return common.RespMsg{Status: common.ErrorParamInvalid, Msg: checkResult.Reason, Data: nil}
+	}
+	total, err := countArtifactsByName(req.Name)
+	if err != nil {
+		hwlog.RunLog.Errorf("count artifacts failed: %v", err)
+		return common.RespMsg{Status: common.ErrorArtifact, Msg: "count artifacts failed", Data: nil}
+	}
+	records, err := listArtifactsByName(req.PageNum, req.PageSize, req.Name)
+	if err != nil {
+		hwlog.RunLog.Errorf("list artifacts failed: %v", err)
+		return common.RespMsg{Status: common.ErrorArtifact, Msg: "list artifacts failed", Data: nil}
+	}
+	resp := ListArtifactResp{Total: total, Artifacts: make([]ArtifactInfo, 0, len(records))}
+	for _, record := range records {
+		resp.Artifacts = append(resp.Artifacts, newArtifactInfo(record))
+	}
+	return common.RespMsg{Status: common.Success, Msg: "", Data: resp}
+}
+
+func batchDeleteArtifactsHandler(msg *model.Message) common.RespMsg {
+	hwlog.RunLog.Info("start delete artifacts")
+	var req BatchDeleteArtifactReq
+	if err := msg.ParseContent(&req); err != nil {
+		hwlog.RunLog.Errorf("parse content failed: %v", err)
+		return common.RespMsg{Status: common.ErrorParamConvert, Msg: "parse content failed", Data: nil}
+	}
+	if checkResult := newBatchDeleteChecker().Check(req); !checkResult.Result {
+		hwlog.RunLog.Errorf("check delete artifacts para failed: %s", checkResult.Reason)
+		return common.RespMsg{Status: common.ErrorParamInvalid, Msg: checkResult.Reason, Data: nil}
+	}
+	var batchResp types.BatchResp
+	failedMap := make(map[string]string)
+	batchResp.FailedInfos = failedMap
+	for _, id := range req.IDs {
+		if err := deleteArtifact(id); err != nil {
+			hwlog.RunLog.Errorf("delete artifact [%s] failed: %v", id, err)
+			failedMap[id] = err.Error()
+			continue
+		}
+		batchResp.SuccessIDs = append(batchResp.SuccessIDs, id)
+	}
+	logmgmt.BatchOperationLog("delete artifacts", batchResp.SuccessIDs)
+	if len(failedMap) != 0 {
+		return common.RespMsg{Status: common.ErrorArtifact, Msg: "", Data: batchResp}
+	}
+	hwlog.RunLog.Info("delete artifacts success")
+	return common.RespMsg{Status: common.Success, Msg: "", Data: batchResp}
+}
+
+func deleteArtifact(id string) error {
+	lock := getArtifactLock(id)
+	lock.Lock()
+	defer lock.Unlock()
+	if _, err := getArtifactById(id); err != nil {
+		return errors.New("artifact is not found")
+	}
+	if err := deleteArtifactById(id); err != nil {
+		hwlog.RunLog.Errorf("delete record of artifact [%s] failed: %v", id, err)
+		return errors.New("delete artifact record failed")
+	}
+	removeArtifactDir(id)
+	artifactLocks.Delete(id)
+	return nil
+}
+
+// cleanStaleUploads deletes the artifacts which are not committed and have no chunk uploaded for a long time
+func cleanStaleUploads() {
+	records, err := queryStaleUploads(time.Now().Add(-staleUploadTimeout).Unix())
+	if err != nil {
+		hwlog.RunLog.Errorf("query stale uploads of artifacts failed: %v", err)
+		return
+	}
+	for _, record := range records {
+		if err = deleteArtifact(record.Id); err != nil {
+			hwlog.RunLog.Errorf("delete stale upload of artifact [%s] failed: %v", record.Id, err)
+			continue
+		}
+		hwlog.RunLog.Infof("stale upload of artifact [%s] is deleted", record.Id)
+	}
+}
+
+// removeLostArtifacts deletes the committed artifacts whose files are lost, e.g. the artifact dir is not persisted
+// or is cleared, so that edge is not told to download an artifact which can never be served
+func removeLostArtifacts() {
+	records, err := queryArtifactsByStatus(StatusReady)
+	if err != nil {
+		hwlog.RunLog.Errorf("query committed artifacts failed: %v", err)
+		return
+	}
+	for _, record := range records {
+		if err = checkArtifactFiles(&record); err == nil {
+			continue
+		}
+		hwlog.RunLog.Warnf("files of artifact [%s] are lost: %v, the artifact will be deleted", record.Id, err)
+		if err = deleteArtifact(record.Id); err != nil {
+			hwlog.RunLog.Errorf("delete lost artifact [%s] failed: %v", record.Id, err)
+		}
+	}
+}
+
+// GetReadyArtifact gets the artifact of the type which is verified and can be downloaded by edge
+func GetReadyArtifact(id, artifactType string) (*ArtifactInfo, error) {
+	record, err := getArtifactById(id)
+	if err != nil {
+		hwlog.RunLog.Errorf("get artifact [%s] failed: %v", id, err)
+		return nil, errors.New("artifact is not found")
+	}
+	if record.Type != artifactType {
+		return nil, fmt.Errorf("artifact is not of type %s", artifactType)
+	}
+	if record.Status != StatusReady {
+		return nil, errors.New("artifact is not committed")
+	}
+	info := newArtifactInfo(*record)
+	return &info, nil
+}
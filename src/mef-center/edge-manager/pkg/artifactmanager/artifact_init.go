@@ -0,0 +1,144 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package artifactmanager the repository of edge software packages hosted by edge-manager, the artifacts are
+// uploaded and verified by admin, and downloaded by edge over mutual tls
+package artifactmanager
+
+import (
+	"context"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	"huawei.com/mindx/common/hwlog"
+	"huawei.com/mindx/common/modulemgr"
+	"huawei.com/mindx/common/modulemgr/model"
+
+	"huawei.com/mindxedge/base/common"
+
+	"edge-manager/pkg/constants"
+)
+
+const (
+	staleUploadTimeout    = 24 * time.Hour
+	staleUploadCheckCycle = time.Hour
+)
+
+type handlerFunc func(message *model.Message) common.RespMsg
+
+type artifactManager struct {
+	enable bool
+	ctx    context.Context
+}
+
+// NewArtifactManager create artifact manager
+func NewArtifactManager(enable bool) model.Module {
+	return &artifactManager{
+		enable: enable,
+		ctx:    context.Background(),
+	}
+}
+
+func (am *artifactManager) Name() string {
+	return constants.ArtifactManagerName
+}
+
+func (am *artifactManager) Enable() bool {
+	if am.enable {
+		if err := initArtifactTable(); err != nil {
+			hwlog.RunLog.Errorf("module (%s) init artifact database table failed, cannot enable", am.Name())
+			return !am.enable
+		}
+		removeLostArtifacts()
+		if err := initSiteCacheTable(); err != nil {
+			hwlog.RunLog.Errorf("module (%s) init site cache database table failed, cannot enable", am.Name())
+			return !am.enable
+		}
+	}
+	return am.enable
+}
+
+func (am *artifactManager) Start() {
+	go periodCleanStaleUploads(am.ctx)
+	for {
+		select {
+		case _, ok := <-am.ctx.Done():
+			if !ok {
+				hwlog.RunLog.Info("catch stop signal channel is closed")
+			}
+			hwlog.RunLog.Info("has listened stop signal")
+			return
+		default:
+		}
+
+		req, err := modulemgr.ReceiveMessage(am.Name())
+		if err != nil {
+			hwlog.RunLog.Errorf("%s receive request from restful service failed", am.Name())
+			continue
+		}
+
+		go am.dispatch(req)
+	}
+}
+
+func (am *artifactManager) dispatch(req *model.Message) {
+	method, exist := handlerFuncMap[common.Combine(req.GetOption(), req.GetResource())]
+	if !exist {
+		hwlog.RunLog.Errorf("handler func is not exist, option: %s, resource: %s", req.GetOption(),
+			req.GetResource())
+		return
+	}
+	res := method(req)
+	resp, err := req.NewResponse()
+	if err != nil {
+		hwlog.RunLog.Errorf("%s new response failed", am.Name())
+		return
+	}
+	if err = resp.FillContent(res); err != nil {
+		hwlog.RunLog.Errorf("%s fill content failed: %v", am.Name(), err)
+		return
+	}
+	if err = modulemgr.SendMessage(resp); err != nil {
+		hwlog.RunLog.Errorf("%s send response failed", am.Name())
+		return
+	}
+}
+
+var (
+	artifactUrlRootPath  = constants.ArtifactUrlPrefix
+	siteCacheUrlRootPath = filepath.Join(artifactUrlRootPath, "site-cache")
+)
+
+var handlerFuncMap = map[string]handlerFunc{
+	common.Combine(http.MethodPost, artifactUrlRootPath):                                createArtifactHandler,
+	common.Combine(http.MethodPost, filepath.Join(artifactUrlRootPath, "commit")):       commitArtifactHandler,
+	common.Combine(http.MethodGet, artifactUrlRootPath):                                 queryArtifactHandler,
+	common.Combine(http.MethodGet, filepath.Join(artifactUrlRootPath, "list")):          listArtifactsHandler,
+	common.Combine(http.MethodPost, filepath.Join(artifactUrlRootPath, "batch-delete")): batchDeleteArtifactsHandler,
+
+	common.Combine(http.MethodPost, siteCacheUrlRootPath):                                setSiteCacheHandler,
+	common.Combine(http.MethodGet, filepath.Join(siteCacheUrlRootPath, "list")):          listSiteCachesHandler,
+	common.Combine(http.MethodPost, filepath.Join(siteCacheUrlRootPath, "batch-delete")): batchDeleteSiteCachesHandler,
+}
+
+func periodCleanStaleUploads(ctx context.Context) {
+	ticker := time.NewTicker(staleUploadCheckCycle)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			hwlog.RunLog.Info("stale uploads clean goroutine is stopped")
+			return
+		case <-ticker.C:
+			cleanStaleUploads()
+		}
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package artifactmanager servers for uploading artifacts by chunks and downloading artifacts by edge
+package artifactmanager
+
+import (
+	"net"
+	"net/http"
+	"os"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"huawei.com/mindx/common/fileutils"
+	"huawei.com/mindx/common/hwlog"
+
+	"huawei.com/mindxedge/base/common"
+
+	"edge-manager/pkg/constants"
+)
+
+const (
+	queryId     = "id"
+	queryPart   = "part"
+	queryOffset = "offset"
+
+	headerSha256Checksum = "Sha256-Checksum"
+)
+
+var artifactIdRegexp = regexp.MustCompile(constants.ArtifactIdRegexpStr)
+
+// HandleUpload handles the uploading of a chunk of an artifact part. The chunk is written at the offset which must
+// be the uploaded size of the part, so an interrupted uploading can be resumed from the uploaded size of the artifact
+func HandleUpload(c *gin.Context) {
+	id, part := c.Query(queryId), c.Query(queryPart)
+	if !artifactIdRegexp.MatchString(id) {
+		common.ConstructResp(c, common.ErrorParamInvalid, "invalid artifact id", nil)
+		return
+	}
+	if part != partPackage && part != partCms && part != partCrl {
+		common.ConstructResp(c, common.ErrorParamInvalid, "invalid artifact part", nil)
+		return
+	}
+	offset, err := strconv.ParseInt(c.Query(queryOffset), common.BaseHex, common.BitSize64)
+	if err != nil {
+		common.ConstructResp(c, common.ErrorParamInvalid, "invalid offset", nil)
+		return
+	}
+
+	lock := getArtifactLock(id)
+	lock.Lock()
+	defer lock.Unlock()
+	record, err := getArtifactById(id)
+	if err != nil {
+		hwlog.RunLog.Errorf("get artifact [%s] failed: %v", id, err)
+		common.ConstructResp(c, common.ErrorArtifact, "artifact is not found", nil)
+		return
+	}
+	if record.Status != statusUploading {
+		common.ConstructResp(c, common.ErrorArtifact, "artifact is already committed", nil)
+		return
+	}
+	uploaded, err := writeChunk(record, part, offset, c.Request.Body)
+	if err != nil {
+		hwlog.RunLog.Errorf("upload %s of artifact [%s] at offset %d failed: %v", part, id, offset, err)
+		common.ConstructResp(c, common.ErrorArtifact, err.Error(), uploaded)
+		return
+	}
+	if err = touchArtifact(id, time.Now().Unix()); err != nil {
+		hwlog.RunLog.Warnf("update upload time of artifact [%s] failed: %v", id, err)
+	}
+	common.ConstructResp(c, common.Success, "", uploaded)
+}
+
+// HandleDownload handles the downloading of artifacts by edge, range requests are supported for resuming
+func HandleDownload(w http.ResponseWriter, r *http.Request) {
+	clientIp, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		clientIp = r.RemoteAddr
+	}
+	id := r.URL.Query().Get(queryId)
+	if !artifactIdRegexp.MatchString(id) {
+		hwlog.RunLog.Error("abort the artifact download request, invalid artifact id")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	record, err := getArtifactById(id)
+	if err != nil || record.Status != StatusReady {
+		hwlog.RunLog.Errorf("abort the artifact download request, artifact [%s] is not ready", id)
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	pkgPath, err := getPartPath(record, partPackage)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if _, err = fileutils.CheckOriginPath(pkgPath); err != nil {
+		hwlog.RunLog.Errorf("abort the artifact download request, file is abnormal: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	file, err := os.Open(pkgPath)
+	if err != nil {
+		hwlog.RunLog.Errorf("abort the artifact download request, open file failed: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	defer fileutils.CloseFile(file)
+
+	w.Header().Set(headerSha256Checksum, record.Sha256)
+	http.ServeContent(w, r, record.Name, time.Unix(record.UpdatedAt, 0), file)
+	hwlog.OpLog.Infof("[edge@%s] %s %s %s success", clientIp, r.Method, r.URL.Path, id)
+}
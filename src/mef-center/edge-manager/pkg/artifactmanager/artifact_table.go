@@ -0,0 +1,137 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package artifactmanager database table of artifacts
+package artifactmanager
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"huawei.com/mindx/common/database"
+
+	"huawei.com/mindxedge/base/common"
+)
+
+const tableArtifact = "artifact"
+
+// artifactRecord one software package or model file hosted by edge-manager
+type artifactRecord struct {
+	Id          string `gorm:"column:id;primaryKey;size:32"`
+	Name        string `gorm:"column:name;size:128;not null;uniqueIndex:idx_artifact_name_type_version"`
+	Type        string `gorm:"column:type;size:16;not null;uniqueIndex:idx_artifact_name_type_version"`
+	Version     string `gorm:"column:version;size:64;not null;uniqueIndex:idx_artifact_name_type_version"`
+	Description string `gorm:"column:description;size:256"`
+	Size        int64  `gorm:"column:size"`
+	Sha256      string `gorm:"column:sha256;size:64"`
+	Status      string `gorm:"column:status;size:16"`
+	CreatedAt   int64  `gorm:"column:created_at"`
+	UpdatedAt   int64  `gorm:"column:updated_at"`
+}
+
+// TableName define database table name for artifact
+func (*artifactRecord) TableName() string {
+	return tableArtifact
+}
+
+func initArtifactTable() error {
+	return database.CreateTableIfNotExist(artifactRecord{})
+}
+
+func createArtifact(record *artifactRecord) error {
+	if err := database.GetDb().Create(record).Error; err != nil {
+		return fmt.Errorf("insert record to table [%s] error: %v", tableArtifact, err)
+	}
+	return nil
+}
+
+func getArtifactById(id string) (*artifactRecord, error) {
+	var record artifactRecord
+	if err := database.GetDb().Model(&artifactRecord{}).Where("id = ?", id).First(&record).Error; err != nil {
+		return nil, fmt.Errorf("query record in table [%s] error: %v", tableArtifact, err)
+	}
+	return &record, nil
+}
+
+func countArtifacts() (int64, error) {
+	var count int64
+	if err := database.GetDb().Model(&artifactRecord{}).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("count records in table [%s] error: %v", tableArtifact, err)
+	}
+	return count, nil
+}
+
+func countArtifactsByName(name string) (int64, error) {
+	var count int64
+	if err := database.GetDb().Model(&artifactRecord{}).Scopes(whereNameLike(name)).
+		Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("count records in table [%s] error: %v", tableArtifact, err)
+	}
+	return count, nil
+}
+
+func listArtifactsByName(pageNum, pageSize uint64, name string) ([]artifactRecord, error) {
+	var records []artifactRecord
+	if err := database.GetDb().Model(&artifactRecord{}).Scopes(common.Paginate(pageNum, pageSize),
+		whereNameLike(name)).Order("created_at desc").Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("query records in table [%s] error: %v", tableArtifact, err)
+	}
+	return records, nil
+}
+
+func queryArtifactsByStatus(status string) ([]artifactRecord, error) {
+	var records []artifactRecord
+	if err := database.GetDb().Model(&artifactRecord{}).Where("status = ?", status).
+		Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("query records in table [%s] error: %v", tableArtifact, err)
+	}
+	return records, nil
+}
+
+func queryStaleUploads(before int64) ([]artifactRecord, error) {
+	var records []artifactRecord
+	if err := database.GetDb().Model(&artifactRecord{}).Where("status = ? and updated_at <= ?",
+		statusUploading, before).Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("query records in table [%s] error: %v", tableArtifact, err)
+	}
+	return records, nil
+}
+
+func updateArtifactStatus(id, status string, updatedAt int64) error {
+	if err := database.GetDb().Model(&artifactRecord{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":     status,
+		"updated_at": updatedAt,
+	}).Error; err != nil {
+		return fmt.Errorf("update record in table [%s] error: %v", tableArtifact, err)
+	}
+	return nil
+}
+
+func touchArtifact(id string, updatedAt int64) error {
+	if err := database.GetDb().Model(&artifactRecord{}).Where("id = ?", id).
+		Update("updated_at", updatedAt).Error; err != nil {
+		return fmt.Errorf("update record in table [%s] error: %v", tableArtifact, err)
+	}
+	return nil
+}
+
+func deleteArtifactById(id string) error {
+	if err := database.GetDb().Where("id = ?", id).Delete(&artifactRecord{}).Error; err != nil {
+		return fmt.Errorf("delete record from table [%s] error: %v", tableArtifact, err)
+	}
+	return nil
+}
+
+func whereNameLike(name string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("INSTR(name, ?)", name)
+	}
+}
@@ -0,0 +1,279 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package artifactmanager test for artifact repository
+package artifactmanager
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/smartystreets/goconvey/convey"
+
+	"huawei.com/mindx/common/fileutils"
+	"huawei.com/mindx/common/websocketmgr"
+	"huawei.com/mindx/mef/common/cmsverify"
+
+	"huawei.com/mindxedge/base/common"
+
+	"edge-manager/pkg/constants"
+	"edge-manager/pkg/logmanager/utils"
+	"edge-manager/pkg/types"
+)
+
+const testArtifactDir = "/tmp/test_artifact_repository"
+
+var testContent = bytes.Repeat([]byte("0123456789"), 300)
+
+func TestArtifactRepository(t *testing.T) {
+	artifactRootDir = testArtifactDir
+	p := gomonkey.ApplyFuncReturn(utils.CheckDiskSpace, nil)
+	defer p.Reset()
+	defer func() {
+		if err := fileutils.DeleteAllFileWithConfusion(testArtifactDir); err != nil {
+			t.Logf("clear test dir failed: %v", err)
+		}
+	}()
+
+	convey.Convey("create artifact", t, testCreateArtifact)
+	convey.Convey("upload artifact by chunks and commit it", t, testUploadAndCommit)
+	convey.Convey("commit artifact failed", t, testCommitFailed)
+	convey.Convey("download artifact with range", t, testDownloadArtifact)
+	convey.Convey("list and delete artifacts", t, testListAndDelete)
+}
+
+func newCreateReq(name string, content []byte) CreateArtifactReq {
+	checksum := sha256.Sum256(content)
+	return CreateArtifactReq{
+		Name:    name,
+		Type:    TypeSoftware,
+		Version: "1.0.0",
+		Size:    int64(len(content)),
+		Sha256:  hex.EncodeToString(checksum[:]),
+	}
+}
+
+func mustCreate(req CreateArtifactReq) string {
+	resp := createArtifactHandler(newMsgWithContentForUT(req))
+	convey.So(resp.Status, convey.ShouldEqual, common.Success)
+	id, ok := resp.Data.(string)
+	convey.So(ok, convey.ShouldBeTrue)
+	return id
+}
+
+func uploadChunk(id, part string, offset int64, data []byte) common.RespMsg {
+	recorder := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(recorder)
+	ctx.Request = httptest.NewRequest(http.MethodPost, fmt.Sprintf("%s/upload?id=%s&part=%s&offset=%d",
+		constants.ArtifactUrlPrefix, id, part, offset), bytes.NewReader(data))
+	HandleUpload(ctx)
+	var resp common.RespMsg
+	convey.So(json.Unmarshal(recorder.Body.Bytes(), &resp), convey.ShouldBeNil)
+	return resp
+}
+
+func mustUploadAll(id string, content []byte) {
+	convey.So(uploadChunk(id, partPackage, 0, content).Status, convey.ShouldEqual, common.Success)
+	convey.So(uploadChunk(id, partCms, 0, []byte("cms")).Status, convey.ShouldEqual, common.Success)
+	convey.So(uploadChunk(id, partCrl, 0, []byte("crl")).Status, convey.ShouldEqual, common.Success)
+}
+
+func testCreateArtifact() {
+	req := newCreateReq("create.tar.gz", testContent)
+	mustCreate(req)
+	convey.So(createArtifactHandler(newMsgWithContentForUT(req)).Status, convey.ShouldEqual, common.ErrorArtifact)
+
+	req.Version, req.Size = "1.0.1", 0
+	convey.So(createArtifactHandler(newMsgWithContentForUT(req)).Status, convey.ShouldEqual,
+		common.ErrorParamInvalid)
+	req.Size, req.Name = int64(len(testContent)), "../create.tar.gz"
+	convey.So(createArtifactHandler(newMsgWithContentForUT(req)).Status, convey.ShouldEqual,
+		common.ErrorParamInvalid)
+	req.Name, req.Type = "model.tar.gz", "model"
+	convey.So(createArtifactHandler(newMsgWithContentForUT(req)).Status, convey.ShouldEqual,
+		common.ErrorParamInvalid)
+}
+
+func testUploadAndCommit() {
+	id := mustCreate(newCreateReq("upload.tar.gz", testContent))
+	const chunkSize = 1000
+
+	convey.So(uploadChunk(id, partPackage, 0, testContent[:chunkSize]).Data, convey.ShouldEqual, chunkSize)
+	resp := uploadChunk(id, partPackage, chunkSize/2, testContent[chunkSize/2:chunkSize])
+	convey.So(resp.Status, convey.ShouldEqual, common.ErrorArtifact)
+	convey.So(resp.Data, convey.ShouldEqual, chunkSize)
+	resp = queryArtifactHandler(newMsgWithContentForUT(id))
+	info, ok := resp.Data.(ArtifactInfo)
+	convey.So(ok, convey.ShouldBeTrue)
+	convey.So(info.UploadedSize, convey.ShouldEqual, chunkSize)
+
+	resp = uploadChunk(id, partPackage, chunkSize, append(testContent[chunkSize:], 'x'))
+	convey.So(resp.Status, convey.ShouldEqual, common.ErrorArtifact)
+	convey.So(resp.Data, convey.ShouldEqual, chunkSize)
+	convey.So(uploadChunk(id, partPackage, chunkSize, testContent[chunkSize:]).Data, convey.ShouldEqual,
+		len(testContent))
+
+	commitMsg := newMsgWithContentForUT(CommitArtifactReq{ID: id})
+	convey.So(commitArtifactHandler(commitMsg).Status, convey.ShouldEqual, common.ErrorArtifact)
+	convey.So(uploadChunk(id, partCms, 0, []byte("cms")).Status, convey.ShouldEqual, common.Success)
+	convey.So(uploadChunk(id, partCrl, 0, []byte("crl")).Status, convey.ShouldEqual, common.Success)
+	convey.So(commitArtifactHandler(commitMsg).Status, convey.ShouldEqual, common.Success)
+
+	info2, err := GetReadyArtifact(id, TypeSoftware)
+	convey.So(err, convey.ShouldBeNil)
+	convey.So(info2.Status, convey.ShouldEqual, StatusReady)
+	_, err = GetReadyArtifact(id, "model")
+	convey.So(err, convey.ShouldNotBeNil)
+	convey.So(uploadChunk(id, partPackage, 0, testContent).Status, convey.ShouldEqual, common.ErrorArtifact)
+	convey.So(commitArtifactHandler(commitMsg).Status, convey.ShouldEqual, common.ErrorArtifact)
+}
+
+func testCommitFailed() {
+	req := newCreateReq("commit.tar.gz", testContent)
+	req.Sha256 = hex.EncodeToString(make([]byte, sha256.Size))
+	id := mustCreate(req)
+	mustUploadAll(id, testContent)
+	commitMsg := newMsgWithContentForUT(CommitArtifactReq{ID: id})
+	convey.So(commitArtifactHandler(commitMsg).Msg, convey.ShouldContainSubstring, "sha256")
+
+	id = mustCreate(newCreateReq("verify.tar.gz", testContent))
+	mustUploadAll(id, testContent)
+	p := gomonkey.ApplyFuncReturn(cmsverify.VerifyPackage, errors.New("invalid signature"))
+	defer p.Reset()
+	commitMsg = newMsgWithContentForUT(CommitArtifactReq{ID: id})
+	convey.So(commitArtifactHandler(commitMsg).Msg, convey.ShouldContainSubstring, "invalid signature")
+	_, err := GetReadyArtifact(id, TypeSoftware)
+	convey.So(err, convey.ShouldNotBeNil)
+}
+
+func downloadArtifact(id string, header http.Header) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, constants.ArtifactDownloadUrl+"?id="+id, nil)
+	for key := range header {
+		req.Header.Set(key, header.Get(key))
+	}
+	HandleDownload(recorder, req)
+	return recorder
+}
+
+func testDownloadArtifact() {
+	id := mustCreate(newCreateReq("download.tar.gz", testContent))
+	mustUploadAll(id, testContent)
+	convey.So(downloadArtifact(id, nil).Code, convey.ShouldEqual, http.StatusNotFound)
+	convey.So(commitArtifactHandler(newMsgWithContentForUT(CommitArtifactReq{ID: id})).Status,
+		convey.ShouldEqual, common.Success)
+
+	recorder := downloadArtifact(id, nil)
+	convey.So(recorder.Code, convey.ShouldEqual, http.StatusOK)
+	convey.So(recorder.Body.Bytes(), convey.ShouldResemble, testContent)
+
+	const offset = 100
+	recorder = downloadArtifact(id, http.Header{"Range": []string{fmt.Sprintf("bytes=%d-", offset)}})
+	convey.So(recorder.Code, convey.ShouldEqual, http.StatusPartialContent)
+	convey.So(recorder.Body.Bytes(), convey.ShouldResemble, testContent[offset:])
+
+	convey.So(downloadArtifact("invalid", nil).Code, convey.ShouldEqual, http.StatusBadRequest)
+}
+
+func testListAndDelete() {
+	listMsg := newMsgWithContentForUT(types.ListReq{PageNum: 1, PageSize: 10, Name: "upload"})
+	resp := listArtifactsHandler(listMsg)
+	convey.So(resp.Status, convey.ShouldEqual, common.Success)
+	list, ok := resp.Data.(ListArtifactResp)
+	convey.So(ok, convey.ShouldBeTrue)
+	convey.So(list.Total, convey.ShouldEqual, 1)
+	id := list.Artifacts[0].ID
+
+	deleteMsg := newMsgWithContentForUT(BatchDeleteArtifactReq{IDs: []string{id}})
+	convey.So(batchDeleteArtifactsHandler(deleteMsg).Status, convey.ShouldEqual, common.Success)
+	convey.So(fileutils.IsExist(getArtifactDir(id)), convey.ShouldBeFalse)
+	convey.So(batchDeleteArtifactsHandler(deleteMsg).Status, convey.ShouldEqual, common.ErrorArtifact)
+
+	staleId := mustCreate(newCreateReq("stale.tar.gz", testContent))
+	convey.So(touchArtifact(staleId, 0), convey.ShouldBeNil)
+	cleanStaleUploads()
+	_, err := getArtifactById(staleId)
+	convey.So(err, convey.ShouldNotBeNil)
+
+	lostId := mustCreate(newCreateReq("lost.tar.gz", testContent))
+	mustUploadAll(lostId, testContent)
+	convey.So(commitArtifactHandler(newMsgWithContentForUT(CommitArtifactReq{ID: lostId})).Status,
+		convey.ShouldEqual, common.Success)
+	removeLostArtifacts()
+	_, err = getArtifactById(lostId)
+	convey.So(err, convey.ShouldBeNil)
+	removeArtifactDir(lostId)
+	removeLostArtifacts()
+	_, err = getArtifactById(lostId)
+	convey.So(err, convey.ShouldNotBeNil)
+}
+
+func TestSiteCache(t *testing.T) {
+	convey.Convey("set site cache", t, func() {
+		req := SetSiteCacheReq{GroupID: 1, SerialNumber: "node1", Ip: "192.168.1.10", Port: 8443}
+		resp := setSiteCacheHandler(newMsgWithContentForUT(req))
+		convey.So(resp.Status, convey.ShouldEqual, common.Success)
+		// the site cache of the group is replaced
+		req.Port = 9443
+		resp = setSiteCacheHandler(newMsgWithContentForUT(req))
+		convey.So(resp.Status, convey.ShouldEqual, common.Success)
+
+		siteCaches, err := GetSiteCaches()
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(len(siteCaches), convey.ShouldEqual, 1)
+		convey.So(siteCaches[0].Port, convey.ShouldEqual, req.Port)
+		convey.So(siteCaches[0].CertSn, convey.ShouldBeEmpty)
+
+		// the cert of the site cache node is known after it connects
+		RecordNodeCertSn(websocketmgr.WebsocketPeerInfo{Sn: req.SerialNumber, CertSn: "1A2B"})
+		siteCaches, err = GetSiteCaches()
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(siteCaches[0].CertSn, convey.ShouldEqual, "1A2B")
+	})
+
+	convey.Convey("set site cache with invalid para", t, func() {
+		invalidReqs := []SetSiteCacheReq{
+			{GroupID: 0, SerialNumber: "node1", Ip: "192.168.1.10", Port: 8443},
+			{GroupID: 1, SerialNumber: "-node1", Ip: "192.168.1.10", Port: 8443},
+			{GroupID: 1, SerialNumber: "node1", Ip: "192.168.1", Port: 8443},
+			{GroupID: 1, SerialNumber: "node1", Ip: "192.168.1.10", Port: 1024},
+		}
+		for _, req := range invalidReqs {
+			resp := setSiteCacheHandler(newMsgWithContentForUT(req))
+			convey.So(resp.Status, convey.ShouldEqual, common.ErrorParamInvalid)
+		}
+	})
+
+	convey.Convey("list and delete site caches", t, func() {
+		resp := listSiteCachesHandler(newMsgWithContentForUT(""))
+		convey.So(resp.Status, convey.ShouldEqual, common.Success)
+		siteCaches, ok := resp.Data.([]SiteCacheInfo)
+		convey.So(ok, convey.ShouldBeTrue)
+		convey.So(len(siteCaches), convey.ShouldEqual, 1)
+
+		resp = batchDeleteSiteCachesHandler(newMsgWithContentForUT(BatchDeleteSiteCacheReq{GroupIDs: []uint64{1}}))
+		convey.So(resp.Status, convey.ShouldEqual, common.Success)
+		siteCaches, err := GetSiteCaches()
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(siteCaches, convey.ShouldBeEmpty)
+
+		resp = batchDeleteSiteCachesHandler(newMsgWithContentForUT(BatchDeleteSiteCacheReq{}))
+		convey.So(resp.Status, convey.ShouldEqual, common.ErrorParamInvalid)
+	})
+}
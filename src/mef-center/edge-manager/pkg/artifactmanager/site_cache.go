@@ -0,0 +1,150 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package artifactmanager handlers for the site caches, the edge nodes of a node group download the artifacts from
+// the site cache of the group instead of edge-manager, so the artifacts are downloaded over the uplink of the site once
+package artifactmanager
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"huawei.com/mindx/common/hwlog"
+	"huawei.com/mindx/common/modulemgr/model"
+	"huawei.com/mindx/common/websocketmgr"
+
+	"huawei.com/mindxedge/base/common"
+	"huawei.com/mindxedge/base/common/logmgmt"
+
+	"edge-manager/pkg/types"
+)
+
+// nodeCertSns the serial numbers of the certs of nodes on their last connections, the peers pin the cert of the site
+// cache by it, since the certs of nodes have no san
+var nodeCertSns sync.Map
+
+// RecordNodeCertSn records the serial number of the cert of the connected node
+func RecordNodeCertSn(peerInfo websocketmgr.WebsocketPeerInfo) {
+	if peerInfo.CertSn == "" {
+		hwlog.RunLog.Warnf("the cert serial number of node [%s] is unknown", peerInfo.Sn)
+		return
+	}
+	nodeCertSns.Store(peerInfo.Sn, peerInfo.CertSn)
+}
+
+func getNodeCertSn(sn string) string {
+	value, ok := nodeCertSns.Load(sn)
+	if !ok {
+		return ""
+	}
+	certSn, ok := value.(string)
+	if !ok {
+		return ""
+	}
+	return certSn
+}
+
+func setSiteCacheHandler(msg *model.Message) common.RespMsg {
+	hwlog.RunLog.Info("start set site cache")
+	var req SetSiteCacheReq
+	if err := msg.ParseContent(&req); err != nil {
+		hwlog.RunLog.Errorf("parse content failed: %v", err)
+		return common.RespMsg{Status: common.ErrorParamConvert, Msg: "parse content failed", Data: nil}
+	}
+	if checkResult := newSetSiteCacheChecker().Check(req); !checkResult.Result {
+		hwlog.RunLog.Errorf("check set site cache para failed: %s", checkResult.Reason)
+		return common.RespMsg{Status: common.ErrorParamInvalid, Msg: checkResult.Reason, Data: nil}
+	}
+	if err := doSetSiteCache(req); err != nil {
+		hwlog.RunLog.Errorf("set site cache of group [%d] failed: %v", req.GroupID, err)
+		return common.RespMsg{Status: common.ErrorArtifact, Msg: err.Error(), Data: nil}
+	}
+	hwlog.RunLog.Infof("set site cache of group [%d] success, node: %s", req.GroupID, req.SerialNumber)
+	return common.RespMsg{Status: common.Success, Msg: "", Data: nil}
+}
+
+func doSetSiteCache(req SetSiteCacheReq) error {
+	if _, err := getSiteCacheByGroup(req.GroupID); err != nil {
+		count, err := countSiteCaches()
+		if err != nil {
+			return errors.New("count site caches failed")
+		}
+		if count >= maxSiteCaches {
+			return fmt.Errorf("the count of site caches reaches the limit %d", maxSiteCaches)
+		}
+	}
+	record := &siteCacheRecord{
+		GroupID:      req.GroupID,
+		SerialNumber: req.SerialNumber,
+		Ip:           req.Ip,
+		Port:         req.Port,
+		CreatedAt:    time.Now().Unix(),
+	}
+	if err := saveSiteCache(record); err != nil {
+		hwlog.RunLog.Errorf("record site cache failed: %v", err)
+		return errors.New("record site cache failed")
+	}
+	return nil
+}
+
+func listSiteCachesHandler(*model.Message) common.RespMsg {
+	infos, err := GetSiteCaches()
+	if err != nil {
+		return common.RespMsg{Status: common.ErrorArtifact, Msg: err.Error(), Data: nil}
+	}
+	return common.RespMsg{Status: common.Success, Msg: "", Data: infos}
+}
+
+func batchDeleteSiteCachesHandler(msg *model.Message) common.RespMsg {
+	hwlog.RunLog.Info("start delete site caches")
+	var req BatchDeleteSiteCacheReq
+	if err := msg.ParseContent(&req); err != nil {
+		hwlog.RunLog.Errorf("parse content failed: %v", err)
+		return common.RespMsg{Status: common.ErrorParamConvert, Msg: "parse content failed", Data: nil}
+	}
+	if checkResult := newBatchDeleteSiteCacheChecker().Check(req); !checkResult.Result {
+		hwlog.RunLog.Errorf("check delete site caches para failed: %s", checkResult.Reason)
+		return common.RespMsg{Status: common.ErrorParamInvalid, Msg: checkResult.Reason, Data: nil}
+	}
+	var batchResp types.BatchResp
+	failedMap := make(map[string]string)
+	batchResp.FailedInfos = failedMap
+	for _, groupID := range req.GroupIDs {
+		if err := deleteSiteCacheByGroup(groupID); err != nil {
+			hwlog.RunLog.Errorf("delete site cache of group [%d] failed: %v", groupID, err)
+			failedMap[strconv.FormatUint(groupID, common.BaseHex)] = "delete site cache record failed"
+			continue
+		}
+		batchResp.SuccessIDs = append(batchResp.SuccessIDs, groupID)
+	}
+	logmgmt.BatchOperationLog("delete site caches", batchResp.SuccessIDs)
+	if len(failedMap) != 0 {
+		return common.RespMsg{Status: common.ErrorArtifact, Msg: "", Data: batchResp}
+	}
+	hwlog.RunLog.Info("delete site caches success")
+	return common.RespMsg{Status: common.Success, Msg: "", Data: batchResp}
+}
+
+// GetSiteCaches gets the site caches of all node groups
+func GetSiteCaches() ([]SiteCacheInfo, error) {
+	records, err := listSiteCaches()
+	if err != nil {
+		hwlog.RunLog.Errorf("list site caches failed: %v", err)
+		return nil, errors.New("list site caches failed")
+	}
+	infos := make([]SiteCacheInfo, 0, len(records))
+	for _, record := range records {
+		infos = append(infos, newSiteCacheInfo(record))
+	}
+	return infos, nil
+}
@@ -0,0 +1,79 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package artifactmanager database table of site caches
+package artifactmanager
+
+import (
+	"fmt"
+
+	"gorm.io/gorm/clause"
+
+	"huawei.com/mindx/common/database"
+)
+
+const tableSiteCache = "site_cache"
+
+// siteCacheRecord the edge node serving the artifacts to the other nodes of its node group
+type siteCacheRecord struct {
+	GroupID      uint64 `gorm:"column:group_id;primaryKey;autoIncrement:false"`
+	SerialNumber string `gorm:"column:serial_number;size:64;not null"`
+	Ip           string `gorm:"column:ip;size:16;not null"`
+	Port         int    `gorm:"column:port;not null"`
+	CreatedAt    int64  `gorm:"column:created_at"`
+}
+
+// TableName define database table name for site cache
+func (*siteCacheRecord) TableName() string {
+	return tableSiteCache
+}
+
+func initSiteCacheTable() error {
+	return database.CreateTableIfNotExist(siteCacheRecord{})
+}
+
+func saveSiteCache(record *siteCacheRecord) error {
+	if err := database.GetDb().Clauses(clause.OnConflict{UpdateAll: true}).Create(record).Error; err != nil {
+		return fmt.Errorf("save record to table [%s] error: %v", tableSiteCache, err)
+	}
+	return nil
+}
+
+func countSiteCaches() (int64, error) {
+	var count int64
+	if err := database.GetDb().Model(&siteCacheRecord{}).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("count records in table [%s] error: %v", tableSiteCache, err)
+	}
+	return count, nil
+}
+
+func getSiteCacheByGroup(groupID uint64) (*siteCacheRecord, error) {
+	var record siteCacheRecord
+	if err := database.GetDb().Model(&siteCacheRecord{}).Where("group_id = ?", groupID).
+		First(&record).Error; err != nil {
+		return nil, fmt.Errorf("query record in table [%s] error: %v", tableSiteCache, err)
+	}
+	return &record, nil
+}
+
+func listSiteCaches() ([]siteCacheRecord, error) {
+	var records []siteCacheRecord
+	if err := database.GetDb().Model(&siteCacheRecord{}).Order("group_id").Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("query records in table [%s] error: %v", tableSiteCache, err)
+	}
+	return records, nil
+}
+
+func deleteSiteCacheByGroup(groupID uint64) error {
+	if err := database.GetDb().Where("group_id = ?", groupID).Delete(&siteCacheRecord{}).Error; err != nil {
+		return fmt.Errorf("delete record from table [%s] error: %v", tableSiteCache, err)
+	}
+	return nil
+}
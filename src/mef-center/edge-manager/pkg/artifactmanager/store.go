@@ -0,0 +1,216 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package artifactmanager files of artifacts stored by edge-manager
+package artifactmanager
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"huawei.com/mindx/common/fileutils"
+	"huawei.com/mindx/common/hwlog"
+	"huawei.com/mindx/mef/common/cmsverify"
+
+	"edge-manager/pkg/constants"
+)
+
+const artifactIdLen = 16
+
+var (
+	artifactRootDir = constants.ArtifactDir
+	artifactLocks   sync.Map
+)
+
+// getArtifactLock the uploading, committing and deleting of an artifact are serialized by its lock
+func getArtifactLock(id string) *sync.Mutex {
+	lock, _ := artifactLocks.LoadOrStore(id, &sync.Mutex{})
+	artifactLock, ok := lock.(*sync.Mutex)
+	if !ok {
+		return &sync.Mutex{}
+	}
+	return artifactLock
+}
+
+func newArtifactId() (string, error) {
+	buf := make([]byte, artifactIdLen)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generate artifact id failed: %v", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func getArtifactDir(id string) string {
+	return filepath.Join(artifactRootDir, id)
+}
+
+func getPartPath(record *artifactRecord, part string) (string, error) {
+	switch part {
+	case partPackage:
+		return filepath.Join(getArtifactDir(record.Id), record.Name), nil
+	case partCms:
+		return filepath.Join(getArtifactDir(record.Id), record.Name+cmsSuffix), nil
+	case partCrl:
+		return filepath.Join(getArtifactDir(record.Id), record.Name+crlSuffix), nil
+	default:
+		return "", fmt.Errorf("invalid artifact part [%s]", part)
+	}
+}
+
+func getPartSizeLimit(record *artifactRecord, part string) int64 {
+	if part == partPackage {
+		return record.Size
+	}
+	return maxSignFileSize
+}
+
+func createArtifactDir(id string) error {
+	if err := fileutils.CreateDir(artifactRootDir, fileutils.Mode700); err != nil {
+		return fmt.Errorf("create artifact root dir failed: %v", err)
+	}
+	if err := fileutils.CreateDir(getArtifactDir(id), fileutils.Mode700); err != nil {
+		return fmt.Errorf("create artifact dir failed: %v", err)
+	}
+	return nil
+}
+
+func removeArtifactDir(id string) {
+	if err := fileutils.DeleteAllFileWithConfusion(getArtifactDir(id)); err != nil {
+		hwlog.RunLog.Errorf("delete files of artifact [%s] failed: %v", id, err)
+	}
+}
+
+func getUploadedSize(record *artifactRecord, part string) int64 {
+	partPath, err := getPartPath(record, part)
+	if err != nil {
+		return 0
+	}
+	info, err := os.Stat(partPath)
+	if err != nil {
+		return 0
+	}
+	return info.Size()
+}
+
+// writeChunk writes the chunk into the part of the artifact at the offset, the offset must be the uploaded size of
+// the part, and the part is uploaded again if the offset is 0. The chunk is dropped entirely if writing it fails,
+// so it can be sent again at the same offset
+func writeChunk(record *artifactRecord, part string, offset int64, src io.Reader) (int64, error) {
+	partPath, err := getPartPath(record, part)
+	if err != nil {
+		return 0, err
+	}
+	limit := getPartSizeLimit(record, part)
+	if offset < 0 || offset > limit {
+		return 0, fmt.Errorf("offset %d is out of range", offset)
+	}
+	if _, err = fileutils.CheckOriginPath(partPath); err != nil {
+		return 0, fmt.Errorf("check artifact file failed: %v", err)
+	}
+	file, err := os.OpenFile(partPath, os.O_WRONLY|os.O_CREATE, fileutils.Mode600)
+	if err != nil {
+		return 0, fmt.Errorf("open artifact file failed: %v", err)
+	}
+	defer func() {
+		if err = file.Close(); err != nil {
+			hwlog.RunLog.Errorf("close artifact file failed: %v", err)
+		}
+	}()
+	info, err := file.Stat()
+	if err != nil {
+		return 0, fmt.Errorf("stat artifact file failed: %v", err)
+	}
+	if offset != 0 && offset != info.Size() {
+		return info.Size(), fmt.Errorf("offset %d does not match the uploaded size %d", offset, info.Size())
+	}
+	if err = file.Truncate(offset); err != nil {
+		return 0, fmt.Errorf("truncate artifact file failed: %v", err)
+	}
+	if _, err = file.Seek(offset, io.SeekStart); err != nil {
+		return 0, fmt.Errorf("seek artifact file failed: %v", err)
+	}
+	written, err := io.Copy(file, io.LimitReader(src, limit-offset+1))
+	if err == nil && offset+written > limit {
+		err = fmt.Errorf("the size of part [%s] exceeds %d", part, limit)
+	}
+	if err != nil {
+		if truncErr := file.Truncate(offset); truncErr != nil {
+			hwlog.RunLog.Errorf("drop the failed chunk of artifact file failed: %v", truncErr)
+		}
+		return offset, fmt.Errorf("write chunk failed: %v", err)
+	}
+	return offset + written, nil
+}
+
+// checkArtifactFiles checks that all parts of the artifact exist, the package is checked by its size only
+func checkArtifactFiles(record *artifactRecord) error {
+	if size := getUploadedSize(record, partPackage); size != record.Size {
+		return fmt.Errorf("the size %d of package does not match the size %d", size, record.Size)
+	}
+	for _, part := range []string{partCms, partCrl} {
+		if getUploadedSize(record, part) == 0 {
+			return fmt.Errorf("the %s file of package does not exist", part)
+		}
+	}
+	return nil
+}
+
+// verifyArtifact checks the size and checksum of the uploaded package, and verifies it by its cms and crl
+func verifyArtifact(record *artifactRecord) error {
+	pkgPath, err := getPartPath(record, partPackage)
+	if err != nil {
+		return err
+	}
+	if err = checkArtifactFiles(record); err != nil {
+		return err
+	}
+	checksum, err := calcSha256(pkgPath)
+	if err != nil {
+		return err
+	}
+	if checksum != record.Sha256 {
+		return errors.New("the sha256 checksum of package does not match")
+	}
+	cmsPath, err := getPartPath(record, partCms)
+	if err != nil {
+		return err
+	}
+	crlPath, err := getPartPath(record, partCrl)
+	if err != nil {
+		return err
+	}
+	if err = cmsverify.VerifyPackage(crlPath, cmsPath, pkgPath); err != nil {
+		return fmt.Errorf("verify package by cms failed: %v", err)
+	}
+	return nil
+}
+
+func calcSha256(filePath string) (string, error) {
+	if _, err := fileutils.CheckOriginPath(filePath); err != nil {
+		return "", fmt.Errorf("check artifact file failed: %v", err)
+	}
+	file, err := os.Open(filePath)
+	if err != nil {
+		return "", fmt.Errorf("open artifact file failed: %v", err)
+	}
+	defer fileutils.CloseFile(file)
+	hasher := sha256.New()
+	if _, err = io.Copy(hasher, file); err != nil {
+		return "", fmt.Errorf("calculate sha256 checksum failed: %v", err)
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
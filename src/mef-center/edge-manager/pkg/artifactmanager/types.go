@@ -0,0 +1,203 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package artifactmanager types and checkers of the artifact repository
+package artifactmanager
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"huawei.com/mindx/common/checker"
+
+	"huawei.com/mindxedge/base/common"
+
+	"edge-manager/pkg/constants"
+)
+
+// artifact types, only edge software packages are hosted, model files are downloaded by edge from their own urls
+const (
+	// TypeSoftware edge software package
+	TypeSoftware = "software"
+)
+
+// artifact status, the files of an artifact are uploaded by chunks while it is uploading, and it can be
+// downloaded by edge after it is committed and verified
+const (
+	statusUploading = "uploading"
+	// StatusReady the artifact is verified and can be downloaded
+	StatusReady = "ready"
+)
+
+// parts of an artifact, the package is verified by its cms signature and crl at committing
+const (
+	partPackage = "package"
+	partCms     = "cms"
+	partCrl     = "crl"
+
+	cmsSuffix = ".cms"
+	crlSuffix = ".crl"
+)
+
+const (
+	artifactNameReg    = `^[a-zA-Z0-9][-_a-zA-Z0-9.]{0,127}$`
+	artifactVersionReg = `^[a-zA-Z0-9][-_a-zA-Z0-9.]{0,63}$`
+	sha256Reg          = `^[a-f0-9]{64}$`
+	maxDescriptionLen  = 256
+	maxArtifactSize    = 8 * 1024 * common.MB
+	maxSignFileSize    = common.MB
+	maxArtifacts       = 64
+	maxBatchDelete     = maxArtifacts
+)
+
+const (
+	serialNumberReg  = `^[a-zA-Z0-9]([-_a-zA-Z0-9]{0,62}[a-zA-Z0-9])?$`
+	minSiteCachePort = 1025
+	maxSiteCachePort = 65535
+	maxSiteCaches    = common.MaxNodeGroup
+)
+
+// CreateArtifactReq request for creating an artifact, the files of it are uploaded by chunks before it is committed
+type CreateArtifactReq struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Version     string `json:"version"`
+	Description string `json:"description"`
+	Size        int64  `json:"size"`
+	Sha256      string `json:"sha256"`
+}
+
+// CommitArtifactReq request for committing an artifact after its files are uploaded
+type CommitArtifactReq struct {
+	ID string `json:"id"`
+}
+
+// BatchDeleteArtifactReq request for deleting artifacts
+type BatchDeleteArtifactReq struct {
+	IDs []string `json:"ids"`
+}
+
+// ArtifactInfo information of an artifact, the uploaded size is only filled for the uploading artifact
+type ArtifactInfo struct {
+	ID           string    `json:"id"`
+	Name         string    `json:"name"`
+	Type         string    `json:"type"`
+	Version      string    `json:"version"`
+	Description  string    `json:"description"`
+	Size         int64     `json:"size"`
+	Sha256       string    `json:"sha256"`
+	Status       string    `json:"status"`
+	UploadedSize int64     `json:"uploadedSize,omitempty"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+// ListArtifactResp response of listing artifacts
+type ListArtifactResp struct {
+	Total     int64          `json:"total"`
+	Artifacts []ArtifactInfo `json:"artifacts"`
+}
+
+func newArtifactInfo(record artifactRecord) ArtifactInfo {
+	return ArtifactInfo{
+		ID:          record.Id,
+		Name:        record.Name,
+		Type:        record.Type,
+		Version:     record.Version,
+		Description: record.Description,
+		Size:        record.Size,
+		Sha256:      record.Sha256,
+		Status:      record.Status,
+		CreatedAt:   time.Unix(record.CreatedAt, 0),
+	}
+}
+
+// SetSiteCacheReq request for setting the site cache of a node group, the site cache is a node of the group with
+// the site cache role enabled by edgectl, and the ip and port are the address it listens on for the other nodes
+type SetSiteCacheReq struct {
+	GroupID      uint64 `json:"groupID"`
+	SerialNumber string `json:"serialNumber"`
+	Ip           string `json:"ip"`
+	Port         int    `json:"port"`
+}
+
+// BatchDeleteSiteCacheReq request for deleting the site caches of node groups
+type BatchDeleteSiteCacheReq struct {
+	GroupIDs []uint64 `json:"groupIDs"`
+}
+
+// SiteCacheInfo the site cache of a node group, CertSn is the serial number of the cert of the site cache node on
+// its last connection, it is empty if the node has not connected
+type SiteCacheInfo struct {
+	GroupID      uint64    `json:"groupID"`
+	SerialNumber string    `json:"serialNumber"`
+	Ip           string    `json:"ip"`
+	Port         int       `json:"port"`
+	CreatedAt    time.Time `json:"createdAt"`
+	CertSn       string    `json:"-"`
+}
+
+func newSiteCacheInfo(record siteCacheRecord) SiteCacheInfo {
+	return SiteCacheInfo{
+		GroupID:      record.GroupID,
+		SerialNumber: record.SerialNumber,
+		Ip:           record.Ip,
+		Port:         record.Port,
+		CreatedAt:    time.Unix(record.CreatedAt, 0),
+		CertSn:       getNodeCertSn(record.SerialNumber),
+	}
+}
+
+type createArtifactChecker struct {
+	modelChecker checker.ModelChecker
+}
+
+func newCreateArtifactChecker() *createArtifactChecker {
+	return &createArtifactChecker{}
+}
+
+func (c *createArtifactChecker) init() {
+	c.modelChecker.Checker = checker.GetAndChecker(
+		checker.GetRegChecker("Name", artifactNameReg, true),
+		checker.GetStringChoiceChecker("Type", []string{TypeSoftware}, true),
+		checker.GetRegChecker("Version", artifactVersionReg, true),
+		checker.GetStringLengthChecker("Description", 0, maxDescriptionLen, false),
+		checker.GetIntChecker("Size", 1, maxArtifactSize, true),
+		checker.GetRegChecker("Sha256", sha256Reg, true),
+	)
+}
+
+func (c *createArtifactChecker) Check(data interface{}) checker.CheckResult {
+	c.init()
+	checkResult := c.modelChecker.Check(data)
+	if !checkResult.Result {
+		return checker.NewFailedResult(fmt.Sprintf("create artifact para check failed: %s", checkResult.Reason))
+	}
+	return checker.NewSuccessResult()
+}
+
+func newBatchDeleteChecker() *checker.UniqueListChecker {
+	return checker.GetUniqueListChecker("IDs",
+		checker.GetRegChecker("", constants.ArtifactIdRegexpStr, true), 1, maxBatchDelete, true)
+}
+
+func newSetSiteCacheChecker() *checker.AndChecker {
+	return checker.GetAndChecker(
+		checker.GetUintChecker("GroupID", 1, math.MaxUint32, true),
+		checker.GetRegChecker("SerialNumber", serialNumberReg, true),
+		checker.GetIpV4Checker("Ip", true),
+		checker.GetIntChecker("Port", minSiteCachePort, maxSiteCachePort, true),
+	)
+}
+
+func newBatchDeleteSiteCacheChecker() *checker.UniqueListChecker {
+	return checker.GetUniqueListChecker("GroupIDs",
+		checker.GetUintChecker("", 1, math.MaxUint32, true), 1, maxSiteCaches, true)
+}
@@ -16,9 +16,12 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sync"
 	"sync/atomic"
 	"time"
 
+	"gorm.io/gorm"
+
 	"huawei.com/mindx/common/httpsmgr"
 	"huawei.com/mindx/common/hwlog"
 	"huawei.com/mindx/common/modulemgr"
@@ -27,6 +30,8 @@ import (
 
 	"edge-manager/pkg/constants"
 	"edge-manager/pkg/nodemanager"
+	"edge-manager/pkg/nodeprotocol"
+	"edge-manager/pkg/outbox"
 	"edge-manager/pkg/types"
 
 	"huawei.com/mindxedge/base/common"
@@ -50,9 +55,11 @@ const (
 
 	failedTaskCheckInterval = time.Minute * 15
 	stopCondCheckInterval   = time.Hour
+	maxLastErrorLen         = 256
 )
 
 const (
+	certUpdateRootPath          = "/edgemanager/v1/cert/update"
 	urlReportUpdateResult       = "/inner/v1/certificates/update-result"
 	urlNgxSouthCert             = "/inner/v1/ngxmanager/cert/edge-manager"
 	updateSuccessCode     int64 = 1
@@ -157,7 +164,19 @@ func getAllNodeInfo() ([]nodemanager.NodeInfo, error) {
 	return nodes, nil
 }
 
-func sendCertUpdateNotifyToNode(serialNumber string, updatePayload string) error {
+// certUpdateFeatures the features of edge node needed by the update of each cert type
+var certUpdateFeatures = map[string]nodeprotocol.Feature{
+	CertTypeEdgeCa:  nodeprotocol.FeatureEdgeCaUpdate,
+	CertTypeEdgeSvc: nodeprotocol.FeatureEdgeSvcCertUpdate,
+}
+
+func sendCertUpdateNotifyToNode(serialNumber, certType, updatePayload string) error {
+	if feature, ok := certUpdateFeatures[certType]; ok {
+		// the node is recorded as failed with the reason, and notified again after it is upgraded
+		if err := nodeprotocol.CheckSupport(serialNumber, feature); err != nil {
+			return err
+		}
+	}
 	notifyMsg, err := model.NewMessage()
 	if err != nil {
 		return fmt.Errorf("create new message failed, error: %v", err)
@@ -167,7 +186,8 @@ func sendCertUpdateNotifyToNode(serialNumber string, updatePayload string) error
 	if err = notifyMsg.FillContent(updatePayload); err != nil {
 		return fmt.Errorf("fill content failed: %v", err)
 	}
-	if err = modulemgr.SendMessage(notifyMsg); err != nil {
+	opts := outbox.Options{Priority: outbox.PriorityHigh, Ttl: common.OneDay}
+	if err = outbox.SendToNode(notifyMsg, opts); err != nil {
 		return fmt.Errorf("%s sends message to %s failed, error: %v",
 			common.CertUpdaterName, common.CloudHubName, err)
 	}
@@ -241,12 +261,9 @@ func edgeSvcUpdatePostProcess(payload *CertUpdatePayload, cancel context.CancelF
 		hwlog.RunLog.Infof("send cert [%v] update abnormal alarm success", payload.CertType)
 	}
 
-	// 3. delete temporary db table after use.
-	if err := DeleteDBTable(edgeSvcCertStatus{}); err != nil {
-		hwlog.RunLog.Errorf("cleanup database table [%v] error: %v", TableEdgeSvcCertStatus, err)
-		return
-	}
-	hwlog.RunLog.Infof("cleanup database table [%v] success", TableEdgeSvcCertStatus)
+	// 3. keep the status table for querying the result of this operation,
+	// it will be rebuilt when next update operation starts.
+	hwlog.RunLog.Infof("keep database table [%v] for cert update status query", TableEdgeSvcCertStatus)
 
 }
 
@@ -277,12 +294,9 @@ func edgeCaUpdatePostProcess(payload *CertUpdatePayload, cancel context.CancelFu
 		hwlog.RunLog.Infof("send cert [%v] update abnormal alarm success", payload.CertType)
 	}
 
-	// 3. delete temporary db table after use.
-	if err := DeleteDBTable(edgeCaCertStatus{}); err != nil {
-		hwlog.RunLog.Errorf("cleanup database table [%v] error: %v", TableEdgeCaCertStatus, err)
-		return
-	}
-	hwlog.RunLog.Infof("cleanup database table [%v] success", TableEdgeCaCertStatus)
+	// 3. keep the status table for querying the result of this operation,
+	// it will be rebuilt when next update operation starts.
+	hwlog.RunLog.Infof("keep database table [%v] for cert update status query", TableEdgeCaCertStatus)
 }
 
 func sendAlarm(alarmId, notifyType string) error {
@@ -409,3 +423,40 @@ func sendForceUpdateSignal(payload *CertUpdatePayload) bool {
 		return false
 	}
 }
+
+// newAttemptFields build database fields for one cert update notify attempt
+func newAttemptFields(sendErr error) map[string]interface{} {
+	dbFields := map[string]interface{}{
+		"notify_timestamp": time.Now().Unix(),
+		"attempt_count":    gorm.Expr("attempt_count + ?", 1),
+	}
+	if sendErr != nil {
+		dbFields["status"] = UpdateStatusFail
+		dbFields["last_error"] = limitErrMsg(sendErr)
+	}
+	return dbFields
+}
+
+// limitErrMsg cut error message to fit database column length
+func limitErrMsg(err error) string {
+	if err == nil {
+		return ""
+	}
+	errMsg := err.Error()
+	if len(errMsg) > maxLastErrorLen {
+		return errMsg[:maxLastErrorLen]
+	}
+	return errMsg
+}
+
+func setRunningPayload(locker *sync.Mutex, target **CertUpdatePayload, payload *CertUpdatePayload) {
+	locker.Lock()
+	defer locker.Unlock()
+	*target = payload
+}
+
+func getRunningPayload(locker *sync.Mutex, target **CertUpdatePayload) *CertUpdatePayload {
+	locker.Lock()
+	defer locker.Unlock()
+	return *target
+}
@@ -46,6 +46,9 @@ type edgeCaCertStatus struct {
 	Ip              string `gorm:"column:ip;size:16;not null"`
 	Status          int64  `gorm:"column:status"`
 	NotifyTimestamp int64  `gorm:"column:notify_timestamp"`
+	ResultTimestamp int64  `gorm:"column:result_timestamp"`
+	AttemptCount    int64  `gorm:"column:attempt_count"`
+	LastError       string `gorm:"column:last_error;size:256"`
 }
 
 // edgeSvcCertStatus track each edge node status during service cert update
@@ -55,6 +58,9 @@ type edgeSvcCertStatus struct {
 	Ip              string `gorm:"column:ip;size:16;not null"`
 	Status          int64  `gorm:"column:status"`
 	NotifyTimestamp int64  `gorm:"column:notify_timestamp"`
+	ResultTimestamp int64  `gorm:"column:result_timestamp"`
+	AttemptCount    int64  `gorm:"column:attempt_count"`
+	LastError       string `gorm:"column:last_error;size:256"`
 }
 
 // TableName define database table name for edge root ca state
@@ -76,6 +82,7 @@ func getEdgeSvcCertStatusModInstance() *edgeSvcCertStatus {
 func getEdgeCaCertStatusDbModInstance() *edgeCaCertStatus {
 	return &EdgeCaCertStatusMod
 }
+
 func isValidModel(model interface{}) bool {
 	if model == nil {
 		return false
@@ -108,6 +115,14 @@ func RebuildDBTable(model interface{}) error {
 	return nil
 }
 
+// isDBTableExist check whether the status table is created by a cert update operation
+func isDBTableExist(model interface{}) bool {
+	if !isValidModel(model) {
+		return false
+	}
+	return database.GetDb().Migrator().HasTable(model)
+}
+
 // DeleteDBTable delete db table when cert update is finished
 func DeleteDBTable(model interface{}) error {
 	modTypeName := reflect.TypeOf(model).Name()
@@ -222,6 +237,46 @@ func (updater *edgeCaCertStatus) QueryUnsuccessfulRecords() ([]edgeCaCertStatus,
 	return updater.queryRecords(nil, notCond)
 }
 
+// QueryAllRecords query all records, wrap function queryRecords
+func (updater *edgeCaCertStatus) QueryAllRecords() ([]edgeCaCertStatus, error) {
+	return updater.queryRecords(nil, nil)
+}
+
+// QueryRecordsBySns query records by sn
+func (updater *edgeCaCertStatus) QueryRecordsBySns(sns []string) ([]edgeCaCertStatus, error) {
+	if updater == nil {
+		return nil, fmt.Errorf("invalid edgeCaCertStatus instance")
+	}
+	var records []edgeCaCertStatus
+	if len(sns) == 0 {
+		return records, nil
+	}
+	if err := database.GetDb().Model(updater).Where("sn IN ?", sns).Find(&records).Error; err != nil {
+		optErr := fmt.Errorf("query record(s) from table [%v] error: %v", TableEdgeCaCertStatus, err)
+		hwlog.RunLog.Error(optErr)
+		return nil, optErr
+	}
+	return records, nil
+}
+
+// queryUnsuccessfulRecordsBySns query unsuccessful records of the given nodes, all nodes if sns is empty
+func (updater *edgeCaCertStatus) queryUnsuccessfulRecordsBySns(sns []string) ([]edgeCaCertStatus, error) {
+	if len(sns) == 0 {
+		return updater.QueryUnsuccessfulRecords()
+	}
+	records, err := updater.QueryRecordsBySns(sns)
+	if err != nil {
+		return nil, err
+	}
+	unsuccessfulRecords := make([]edgeCaCertStatus, 0, len(records))
+	for _, record := range records {
+		if record.Status != UpdateStatusSuccess {
+			unsuccessfulRecords = append(unsuccessfulRecords, record)
+		}
+	}
+	return unsuccessfulRecords, nil
+}
+
 // CreateOneRecord insert one record at one time
 func (updater *edgeSvcCertStatus) CreateOneRecord() error {
 	if updater == nil {
@@ -321,3 +376,43 @@ func (updater *edgeSvcCertStatus) QueryUnsuccessfulRecords() ([]edgeSvcCertStatu
 	}
 	return updater.queryRecords(nil, notCond)
 }
+
+// QueryAllRecords query all records, wrap function queryRecords
+func (updater *edgeSvcCertStatus) QueryAllRecords() ([]edgeSvcCertStatus, error) {
+	return updater.queryRecords(nil, nil)
+}
+
+// QueryRecordsBySns query records by sn
+func (updater *edgeSvcCertStatus) QueryRecordsBySns(sns []string) ([]edgeSvcCertStatus, error) {
+	if updater == nil {
+		return nil, fmt.Errorf("invalid edgeSvcCertStatus instance")
+	}
+	var records []edgeSvcCertStatus
+	if len(sns) == 0 {
+		return records, nil
+	}
+	if err := database.GetDb().Model(updater).Where("sn IN ?", sns).Find(&records).Error; err != nil {
+		optErr := fmt.Errorf("query record(s) from table [%v] error: %v", TableEdgeSvcCertStatus, err)
+		hwlog.RunLog.Error(optErr)
+		return nil, optErr
+	}
+	return records, nil
+}
+
+// queryUnsuccessfulRecordsBySns query unsuccessful records of the given nodes, all nodes if sns is empty
+func (updater *edgeSvcCertStatus) queryUnsuccessfulRecordsBySns(sns []string) ([]edgeSvcCertStatus, error) {
+	if len(sns) == 0 {
+		return updater.QueryUnsuccessfulRecords()
+	}
+	records, err := updater.QueryRecordsBySns(sns)
+	if err != nil {
+		return nil, err
+	}
+	unsuccessfulRecords := make([]edgeSvcCertStatus, 0, len(records))
+	for _, record := range records {
+		if record.Status != UpdateStatusSuccess {
+			unsuccessfulRecords = append(unsuccessfulRecords, record)
+		}
+	}
+	return unsuccessfulRecords, nil
+}
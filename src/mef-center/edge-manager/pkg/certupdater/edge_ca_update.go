@@ -14,6 +14,7 @@ package certupdater
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"sync"
 	"sync/atomic"
@@ -35,6 +36,7 @@ var nodesChangeForCaChan = make(chan changedNodeInfo, workingQueueSize)
 var updateResultForCaChan = make(chan NodeCertUpdateResult, common.MaxNode)
 var forceUpdateCaCertChan = make(chan CertUpdatePayload)
 var edgeCaWorkingLocker = sync.Mutex{}
+var edgeCaRunningPayload *CertUpdatePayload
 var edgeCaUpdaterInstance edgeCaUpdater
 
 // StartEdgeCaCertUpdate  entry for edge root ca cert update operation
@@ -58,10 +60,12 @@ func StartEdgeCaCertUpdate(payload *CertUpdatePayload) {
 
 	// reset exit flag to default state
 	edgeCaNormalStopFlag = false
+	setRunningPayload(&edgeCaWorkingLocker, &edgeCaRunningPayload, payload)
 	ctx, cancel := context.WithCancel(context.Background())
 	defer func() {
 		select {
 		case _, _ = <-ctx.Done():
+			setRunningPayload(&edgeCaWorkingLocker, &edgeCaRunningPayload, nil)
 			atomic.StoreInt64(&edgeCaCertUpdateFlag, NotRunning)
 			hwlog.RunLog.Info("MEF Edge ca certs update process is finished")
 			// clear the alarm when cert update process is finished
@@ -185,9 +189,11 @@ func (ea *edgeCaUpdater) processAddedNodesInfo(nodesInfo []NodeInfo, payload *Ce
 			Ip:              info.Ip,
 			Status:          UpdateStatusInit,
 			NotifyTimestamp: time.Now().Unix(),
+			AttemptCount:    1,
 		}
-		if err = sendCertUpdateNotifyToNode(info.Sn, string(updatePayload)); err != nil {
+		if err = sendCertUpdateNotifyToNode(info.Sn, payload.CertType, string(updatePayload)); err != nil {
 			tempNodeInfo.Status = UpdateStatusFail
+			tempNodeInfo.LastError = limitErrMsg(err)
 			hwlog.RunLog.Errorf("send cert update notify to edge [%v] error: %v", info.Sn, err)
 		}
 		addedNodeData = append(addedNodeData, tempNodeInfo)
@@ -227,12 +233,11 @@ func (ea *edgeCaUpdater) notifyCertUpdateToEdgeNodes(payload *CertUpdatePayload)
 		return fmt.Errorf("serialize cert update payload error")
 	}
 	for _, info := range nodesInfo {
-		dbFields := map[string]interface{}{}
-		if err = sendCertUpdateNotifyToNode(info.Sn, string(updatePayload)); err != nil {
-			hwlog.RunLog.Errorf("send cert update notify to node [%s] error: %v", info.Sn, err)
-			dbFields["status"] = UpdateStatusFail
+		sendErr := sendCertUpdateNotifyToNode(info.Sn, payload.CertType, string(updatePayload))
+		if sendErr != nil {
+			hwlog.RunLog.Errorf("send cert update notify to node [%s] error: %v", info.Sn, sendErr)
 		}
-		dbFields["notify_timestamp"] = time.Now().Unix()
+		dbFields := newAttemptFields(sendErr)
 		if err = getEdgeCaCertStatusDbModInstance().UpdateRecordsBySns([]string{info.Sn}, dbFields); err != nil {
 			hwlog.RunLog.Errorf("update node [%s] info in database error: %v", info.Sn, err)
 		}
@@ -250,7 +255,7 @@ func (ea *edgeCaUpdater) handleFailedTask(ctx context.Context, payload *CertUpda
 	waitDuration := time.Duration(len(initStateNodes)) * notifyInterval
 	time.Sleep(waitDuration)
 
-	if err = ea.processFailedRecords(payload); err != nil {
+	if _, err = ea.processFailedRecords(payload, nil); err != nil {
 		hwlog.RunLog.Errorf("process failed operation nodes error: %v", err)
 	}
 
@@ -266,22 +271,24 @@ func (ea *edgeCaUpdater) handleFailedTask(ctx context.Context, payload *CertUpda
 				hwlog.RunLog.Warnf("failed-records check job timer is stopped")
 				return
 			}
-			if err = ea.processFailedRecords(payload); err != nil {
+			if _, err = ea.processFailedRecords(payload, nil); err != nil {
 				hwlog.RunLog.Errorf("process failed operation nodes error: %v", err)
 			}
 		}
 	}
 }
 
-// processFailedRecords:  re-send update notify, update database records
-func (ea *edgeCaUpdater) processFailedRecords(payload *CertUpdatePayload) error {
-	failedRecords, err := getEdgeCaCertStatusDbModInstance().QueryUnsuccessfulRecords()
+// processFailedRecords:  re-send update notify, update database records.
+// only the given nodes are processed if sns is not empty, otherwise all unsuccessful nodes are processed.
+// the re-send results are returned by the sn of each re-sent node.
+func (ea *edgeCaUpdater) processFailedRecords(payload *CertUpdatePayload, sns []string) (map[string]error, error) {
+	failedRecords, err := getEdgeCaCertStatusDbModInstance().queryUnsuccessfulRecordsBySns(sns)
 	if err != nil {
 		hwlog.RunLog.Errorf("query failed cert update records error: %v", err)
-		return fmt.Errorf("query failed cert update records error: %v", err)
+		return nil, fmt.Errorf("query failed cert update records error: %v", err)
 	}
 	if len(failedRecords) == 0 {
-		return nil
+		return nil, nil
 	}
 	payloadData := map[string]string{
 		"certType":  payload.CertType,
@@ -290,18 +297,21 @@ func (ea *edgeCaUpdater) processFailedRecords(payload *CertUpdatePayload) error
 	updatePayload, err := json.Marshal(payloadData)
 	if err != nil {
 		hwlog.RunLog.Errorf("serialize cert update payload error: %v", err)
-		return fmt.Errorf("serialize cert update payload error")
+		return nil, fmt.Errorf("serialize cert update payload error")
 	}
+	sendResults := make(map[string]error, len(failedRecords))
 	for _, info := range failedRecords {
-		if err = sendCertUpdateNotifyToNode(info.Sn, string(updatePayload)); err != nil {
-			hwlog.RunLog.Errorf("re-send update notify to node [%s] error: %v", info.Sn, err)
-			dbFields := map[string]interface{}{"status": UpdateStatusFail}
-			if err = getEdgeCaCertStatusDbModInstance().UpdateRecordsBySns([]string{info.Sn}, dbFields); err != nil {
-				hwlog.RunLog.Errorf("update node [%s] status error: %v", info.Sn, err)
-			}
+		sendErr := sendCertUpdateNotifyToNode(info.Sn, payload.CertType, string(updatePayload))
+		if sendErr != nil {
+			hwlog.RunLog.Errorf("re-send update notify to node [%s] error: %v", info.Sn, sendErr)
+		}
+		sendResults[info.Sn] = sendErr
+		dbFields := newAttemptFields(sendErr)
+		if err = getEdgeCaCertStatusDbModInstance().UpdateRecordsBySns([]string{info.Sn}, dbFields); err != nil {
+			hwlog.RunLog.Errorf("update node [%s] status error: %v", info.Sn, err)
 		}
 	}
-	return nil
+	return sendResults, nil
 }
 
 func (ea *edgeCaUpdater) exitConditionCheck(ctx context.Context, cf context.CancelFunc, payload *CertUpdatePayload) {
@@ -353,16 +363,20 @@ func (ea *edgeCaUpdater) exitConditionCheck(ctx context.Context, cf context.Canc
 }
 
 func (ea *edgeCaUpdater) syncUpdateResult(ctx context.Context) {
-	dbFields := make(map[string]interface{})
 	for {
 		select {
 		case <-ctx.Done():
 			hwlog.RunLog.Infof("stop update result sync job")
 			return
 		case result := <-updateResultForCaChan:
-			dbFields["status"] = UpdateStatusSuccess
+			dbFields := map[string]interface{}{
+				"status":           UpdateStatusSuccess,
+				"result_timestamp": time.Now().Unix(),
+				"last_error":       "",
+			}
 			if result.ResultCode != UpdateStatusSuccess {
 				dbFields["status"] = UpdateStatusFail
+				dbFields["last_error"] = limitErrMsg(errors.New(result.Desc))
 				hwlog.RunLog.Errorf("node [%v] reports failed result: %v", result.Sn, result.Desc)
 			}
 			if err := getEdgeCaCertStatusDbModInstance().UpdateRecordsBySns([]string{result.Sn}, dbFields); err != nil {
@@ -14,6 +14,7 @@ package certupdater
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"sync"
 	"sync/atomic"
@@ -35,6 +36,7 @@ var nodesChangeForSvcChan = make(chan changedNodeInfo, workingQueueSize)
 var updateResultForSvcChan = make(chan NodeCertUpdateResult, common.MaxNode)
 var forceUpdateSvcCertChan = make(chan CertUpdatePayload)
 var edgeSvcWorkingLocker = sync.Mutex{}
+var edgeSvcRunningPayload *CertUpdatePayload
 var edgeSvcUpdaterInstance edgeSvcUpdater
 
 // StartEdgeSvcCertUpdate  entry for edge service cert update operation
@@ -58,10 +60,12 @@ func StartEdgeSvcCertUpdate(payload *CertUpdatePayload) {
 
 	// reset exit flag to default state
 	edgeSvcNormalStopFlag = false
+	setRunningPayload(&edgeSvcWorkingLocker, &edgeSvcRunningPayload, payload)
 	ctx, cancel := context.WithCancel(context.Background())
 	defer func() {
 		select {
 		case _, _ = <-ctx.Done():
+			setRunningPayload(&edgeSvcWorkingLocker, &edgeSvcRunningPayload, nil)
 			atomic.StoreInt64(&edgeSvcCertUpdateFlag, NotRunning)
 			hwlog.RunLog.Info("MEF Edge service certs update process is finished")
 			// clear the alarm when cert update process is finished
@@ -185,9 +189,11 @@ func (es *edgeSvcUpdater) processAddedNodesInfo(nodesInfo []NodeInfo, payload *C
 			Ip:              info.Ip,
 			Status:          UpdateStatusInit,
 			NotifyTimestamp: time.Now().Unix(),
+			AttemptCount:    1,
 		}
-		if err := sendCertUpdateNotifyToNode(info.Sn, string(updatePayload)); err != nil {
+		if err := sendCertUpdateNotifyToNode(info.Sn, payload.CertType, string(updatePayload)); err != nil {
 			tempNodeInfo.Status = UpdateStatusFail
+			tempNodeInfo.LastError = limitErrMsg(err)
 			hwlog.RunLog.Errorf("send cert update notify to edge [%v] error: %v", info.Sn, err)
 		}
 		addedNodeData = append(addedNodeData, tempNodeInfo)
@@ -226,12 +232,11 @@ func (es *edgeSvcUpdater) notifyCertUpdateToEdgeNodes(payload *CertUpdatePayload
 		return fmt.Errorf("serialize cert update payload error")
 	}
 	for _, info := range initNodesInfo {
-		dbFields := make(map[string]interface{})
-		if err = sendCertUpdateNotifyToNode(info.Sn, string(updatePayload)); err != nil {
-			hwlog.RunLog.Errorf("send cert update notify to node [%s] error: %v", info.Sn, err)
-			dbFields["status"] = UpdateStatusFail
+		sendErr := sendCertUpdateNotifyToNode(info.Sn, payload.CertType, string(updatePayload))
+		if sendErr != nil {
+			hwlog.RunLog.Errorf("send cert update notify to node [%s] error: %v", info.Sn, sendErr)
 		}
-		dbFields["notify_timestamp"] = time.Now().Unix()
+		dbFields := newAttemptFields(sendErr)
 		if err = getEdgeSvcCertStatusModInstance().UpdateRecordsBySns([]string{info.Sn}, dbFields); err != nil {
 			hwlog.RunLog.Errorf("update node [%s] info in database error: %v", info.Sn, err)
 		}
@@ -249,7 +254,7 @@ func (es *edgeSvcUpdater) handleFailedTask(ctx context.Context, payload *CertUpd
 	waitDuration := time.Duration(len(initStateNodes)) * notifyInterval
 	time.Sleep(waitDuration)
 
-	if err = es.processFailedRecords(payload); err != nil {
+	if _, err = es.processFailedRecords(payload, nil); err != nil {
 		hwlog.RunLog.Errorf("process failed operation nodes error: %v", err)
 	}
 
@@ -265,22 +270,24 @@ func (es *edgeSvcUpdater) handleFailedTask(ctx context.Context, payload *CertUpd
 				hwlog.RunLog.Warnf("failed-records check job timer is stopped")
 				return
 			}
-			if err = es.processFailedRecords(payload); err != nil {
+			if _, err = es.processFailedRecords(payload, nil); err != nil {
 				hwlog.RunLog.Errorf("process failed operation nodes error: %v", err)
 			}
 		}
 	}
 }
 
-// processFailedRecords:  re-send update notify, update database records
-func (es *edgeSvcUpdater) processFailedRecords(payload *CertUpdatePayload) error {
-	failedRecords, err := getEdgeSvcCertStatusModInstance().QueryUnsuccessfulRecords()
+// processFailedRecords:  re-send update notify, update database records.
+// only the given nodes are processed if sns is not empty, otherwise all unsuccessful nodes are processed.
+// the re-send results are returned by the sn of each re-sent node.
+func (es *edgeSvcUpdater) processFailedRecords(payload *CertUpdatePayload, sns []string) (map[string]error, error) {
+	failedRecords, err := getEdgeSvcCertStatusModInstance().queryUnsuccessfulRecordsBySns(sns)
 	if err != nil {
 		hwlog.RunLog.Errorf("query failed cert update records error: %v", err)
-		return fmt.Errorf("query failed cert update records error: %v", err)
+		return nil, fmt.Errorf("query failed cert update records error: %v", err)
 	}
 	if len(failedRecords) == 0 {
-		return nil
+		return nil, nil
 	}
 	payloadData := map[string]string{
 		"certType": payload.CertType,
@@ -288,18 +295,21 @@ func (es *edgeSvcUpdater) processFailedRecords(payload *CertUpdatePayload) error
 	updatePayload, err := json.Marshal(payloadData)
 	if err != nil {
 		hwlog.RunLog.Errorf("serialize cert update payload error: %v", err)
-		return fmt.Errorf("serialize cert update payload error")
+		return nil, fmt.Errorf("serialize cert update payload error")
 	}
+	sendResults := make(map[string]error, len(failedRecords))
 	for _, info := range failedRecords {
-		if err = sendCertUpdateNotifyToNode(info.Sn, string(updatePayload)); err != nil {
-			hwlog.RunLog.Errorf("re-send update notify to node [%s] error: %v", info.Sn, err)
-			dbFields := map[string]interface{}{"status": UpdateStatusFail}
-			if err = getEdgeSvcCertStatusModInstance().UpdateRecordsBySns([]string{info.Sn}, dbFields); err != nil {
-				hwlog.RunLog.Errorf("update node [%s] status error: %v", info.Sn, err)
-			}
+		sendErr := sendCertUpdateNotifyToNode(info.Sn, payload.CertType, string(updatePayload))
+		if sendErr != nil {
+			hwlog.RunLog.Errorf("re-send update notify to node [%s] error: %v", info.Sn, sendErr)
+		}
+		sendResults[info.Sn] = sendErr
+		dbFields := newAttemptFields(sendErr)
+		if err = getEdgeSvcCertStatusModInstance().UpdateRecordsBySns([]string{info.Sn}, dbFields); err != nil {
+			hwlog.RunLog.Errorf("update node [%s] status error: %v", info.Sn, err)
 		}
 	}
-	return nil
+	return sendResults, nil
 }
 
 func (es *edgeSvcUpdater) exitConditionCheck(ctx context.Context, cf context.CancelFunc, payload *CertUpdatePayload) {
@@ -351,16 +361,20 @@ func (es *edgeSvcUpdater) exitConditionCheck(ctx context.Context, cf context.Can
 }
 
 func (es *edgeSvcUpdater) syncUpdateResult(ctx context.Context) {
-	dbFields := make(map[string]interface{})
 	for {
 		select {
 		case <-ctx.Done():
 			hwlog.RunLog.Infof("stop update result sync job")
 			return
 		case result := <-updateResultForSvcChan:
-			dbFields["status"] = UpdateStatusSuccess
+			dbFields := map[string]interface{}{
+				"status":           UpdateStatusSuccess,
+				"result_timestamp": time.Now().Unix(),
+				"last_error":       "",
+			}
 			if result.ResultCode != UpdateStatusSuccess {
 				dbFields["status"] = UpdateStatusFail
+				dbFields["last_error"] = limitErrMsg(errors.New(result.Desc))
 				hwlog.RunLog.Errorf("node [%v] reports failed result: %v", result.Sn, result.Desc)
 			}
 			if err := getEdgeSvcCertStatusModInstance().UpdateRecordsBySns([]string{result.Sn}, dbFields); err != nil {
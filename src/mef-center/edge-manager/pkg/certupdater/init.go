@@ -16,6 +16,8 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"path/filepath"
 	"sync/atomic"
 	"time"
 
@@ -28,9 +30,12 @@ import (
 
 type messageHandler func(*model.Message) error
 
+type restfulHandler func(*model.Message) common.RespMsg
+
 var (
 	messageHandlerMap          = make(map[string]messageHandler)
 	messageHandlerWithOpLogMap = make(map[string]messageHandler)
+	restfulHandlerMap          = make(map[string]restfulHandler)
 )
 
 func getMsgHandler(msg *model.Message) (messageHandler, bool) {
@@ -45,10 +50,18 @@ func getMsgHandlerWithOpLog(msg *model.Message) (messageHandler, bool) {
 	return handler, ok
 }
 
+func getRestfulHandler(msg *model.Message) (restfulHandler, bool) {
+	handlerKey := msg.GetOption() + msg.GetResource()
+	handler, ok := restfulHandlerMap[handlerKey]
+	return handler, ok
+}
+
 func initMsgHandler() {
 	messageHandlerMap[common.OptPost+common.ResCertUpdate] = handleCertUpdate
 	messageHandlerMap[common.OptPost+common.ResNodeChanged] = handleNodeChange
 	messageHandlerWithOpLogMap[common.OptResp+common.CertWillExpired] = handleUpdateResult
+	restfulHandlerMap[http.MethodGet+filepath.Join(certUpdateRootPath, "/status")] = queryCertUpdateStatus
+	restfulHandlerMap[http.MethodPost+filepath.Join(certUpdateRootPath, "/retry")] = retryCertUpdate
 }
 
 // EdgeCertUpdater dynamic update tls certs
@@ -99,6 +112,10 @@ func (updater *EdgeCertUpdater) Start() {
 }
 
 func (updater *EdgeCertUpdater) dispatch(msg *model.Message) {
+	if restHandler, ok := getRestfulHandler(msg); ok {
+		updater.dispatchRestful(msg, restHandler)
+		return
+	}
 	msgHandler, ok := getMsgHandler(msg)
 	if ok {
 		if err := msgHandler(msg); err != nil {
@@ -127,6 +144,22 @@ func (updater *EdgeCertUpdater) dispatch(msg *model.Message) {
 		ip, sn, msg.GetOption(), msg.GetResource())
 }
 
+func (updater *EdgeCertUpdater) dispatchRestful(msg *model.Message, handler restfulHandler) {
+	respContent := handler(msg)
+	respMsg, err := msg.NewResponse()
+	if err != nil {
+		hwlog.RunLog.Errorf("%s new response failed: %v", updater.Name(), err)
+		return
+	}
+	if err = respMsg.FillContent(respContent); err != nil {
+		hwlog.RunLog.Errorf("%s fill content failed: %v", updater.Name(), err)
+		return
+	}
+	if err = modulemgr.SendMessage(respMsg); err != nil {
+		hwlog.RunLog.Errorf("%s send response failed: %v", updater.Name(), err)
+	}
+}
+
 func handleCertUpdate(msg *model.Message) error {
 	var updateErr error
 	defer func() {
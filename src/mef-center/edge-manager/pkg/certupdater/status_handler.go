@@ -0,0 +1,278 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package certupdater query per-node cert update status and retry failed nodes
+package certupdater
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"huawei.com/mindx/common/checker"
+	"huawei.com/mindx/common/hwlog"
+	"huawei.com/mindx/common/modulemgr/model"
+
+	"huawei.com/mindxedge/base/common"
+	"huawei.com/mindxedge/base/common/logmgmt"
+
+	"edge-manager/pkg/types"
+)
+
+// node cert update status shown to user
+const (
+	statusNameInit    = "init"
+	statusNameSuccess = "success"
+	statusNameFailed  = "failed"
+)
+
+// NodeCertUpdateStatus cert update status of one edge node
+type NodeCertUpdateStatus struct {
+	SerialNumber    string `json:"serialNumber"`
+	Ip              string `json:"ip"`
+	Status          string `json:"status"`
+	AttemptCount    int64  `json:"attemptCount"`
+	LastError       string `json:"lastError"`
+	NotifyTimestamp int64  `json:"notifyTimestamp"`
+	ResultTimestamp int64  `json:"resultTimestamp"`
+}
+
+// CertUpdateProgress cert update status of all edge nodes for one cert type
+type CertUpdateProgress struct {
+	CertType     string                 `json:"certType"`
+	InProgress   bool                   `json:"inProgress"`
+	TotalCount   int                    `json:"totalCount"`
+	SuccessCount int                    `json:"successCount"`
+	FailedCount  int                    `json:"failedCount"`
+	Nodes        []NodeCertUpdateStatus `json:"nodes"`
+}
+
+// CertUpdateRetryReq request to re-send cert update notify to the given edge nodes
+type CertUpdateRetryReq struct {
+	CertType      string   `json:"certType"`
+	SerialNumbers []string `json:"serialNumbers"`
+}
+
+type retryReqChecker struct {
+	modelChecker checker.ModelChecker
+}
+
+func newRetryReqChecker() *retryReqChecker {
+	return &retryReqChecker{}
+}
+
+func (r *retryReqChecker) init() {
+	r.modelChecker.Checker = checker.GetAndChecker(
+		checker.GetStringChoiceChecker("CertType", []string{CertTypeEdgeCa, CertTypeEdgeSvc}, true),
+		checker.GetUniqueListChecker("SerialNumbers",
+			checker.GetRegChecker("", `^[a-zA-Z0-9]([-_a-zA-Z0-9]{0,62}[a-zA-Z0-9])?$`, true),
+			1, common.MaxNode, true),
+	)
+}
+
+// Check [method] check main function
+func (r *retryReqChecker) Check(data interface{}) checker.CheckResult {
+	r.init()
+
+	checkResult := r.modelChecker.Check(data)
+	if !checkResult.Result {
+		return checker.NewFailedResult(fmt.Sprintf("cert update retry para check failed: %s", checkResult.Reason))
+	}
+	return checker.NewSuccessResult()
+}
+
+func getStatusName(status int64) string {
+	switch status {
+	case UpdateStatusSuccess:
+		return statusNameSuccess
+	case UpdateStatusFail:
+		return statusNameFailed
+	default:
+		return statusNameInit
+	}
+}
+
+func newCertUpdateProgress(certType string, records []NodeCertUpdateStatus, inProgress bool) CertUpdateProgress {
+	progress := CertUpdateProgress{
+		CertType:   certType,
+		InProgress: inProgress,
+		TotalCount: len(records),
+		Nodes:      records,
+	}
+	for _, record := range records {
+		switch record.Status {
+		case statusNameSuccess:
+			progress.SuccessCount++
+		case statusNameFailed:
+			progress.FailedCount++
+		default:
+		}
+	}
+	return progress
+}
+
+func queryCaNodesStatus() ([]NodeCertUpdateStatus, error) {
+	nodesStatus := make([]NodeCertUpdateStatus, 0)
+	if !isDBTableExist(edgeCaCertStatus{}) {
+		return nodesStatus, nil
+	}
+	records, err := getEdgeCaCertStatusDbModInstance().QueryAllRecords()
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		nodesStatus = append(nodesStatus, NodeCertUpdateStatus{
+			SerialNumber:    record.Sn,
+			Ip:              record.Ip,
+			Status:          getStatusName(record.Status),
+			AttemptCount:    record.AttemptCount,
+			LastError:       record.LastError,
+			NotifyTimestamp: record.NotifyTimestamp,
+			ResultTimestamp: record.ResultTimestamp,
+		})
+	}
+	return nodesStatus, nil
+}
+
+func querySvcNodesStatus() ([]NodeCertUpdateStatus, error) {
+	nodesStatus := make([]NodeCertUpdateStatus, 0)
+	if !isDBTableExist(edgeSvcCertStatus{}) {
+		return nodesStatus, nil
+	}
+	records, err := getEdgeSvcCertStatusModInstance().QueryAllRecords()
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		nodesStatus = append(nodesStatus, NodeCertUpdateStatus{
+			SerialNumber:    record.Sn,
+			Ip:              record.Ip,
+			Status:          getStatusName(record.Status),
+			AttemptCount:    record.AttemptCount,
+			LastError:       record.LastError,
+			NotifyTimestamp: record.NotifyTimestamp,
+			ResultTimestamp: record.ResultTimestamp,
+		})
+	}
+	return nodesStatus, nil
+}
+
+func queryCertUpdateStatus(msg *model.Message) common.RespMsg {
+	hwlog.RunLog.Info("start query edge nodes cert update status")
+	var certType string
+	if err := msg.ParseContent(&certType); err != nil {
+		hwlog.RunLog.Errorf("query cert update status failed: parse content failed: %v", err)
+		return common.RespMsg{Status: common.ErrorParamConvert, Msg: "parse content failed", Data: nil}
+	}
+
+	var nodesStatus []NodeCertUpdateStatus
+	var inProgress bool
+	var err error
+	switch certType {
+	case CertTypeEdgeCa:
+		inProgress = atomic.LoadInt64(&edgeCaCertUpdateFlag) == InRunning
+		nodesStatus, err = queryCaNodesStatus()
+	case CertTypeEdgeSvc:
+		inProgress = atomic.LoadInt64(&edgeSvcCertUpdateFlag) == InRunning
+		nodesStatus, err = querySvcNodesStatus()
+	default:
+		hwlog.RunLog.Errorf("query cert update status failed: invalid cert type: %s", certType)
+		return common.RespMsg{Status: common.ErrorCertTypeError, Msg: "invalid cert type", Data: nil}
+	}
+	if err != nil {
+		hwlog.RunLog.Errorf("query cert [%s] update status failed: %v", certType, err)
+		return common.RespMsg{Status: common.ErrorGetCertUpdateStatus, Msg: "", Data: nil}
+	}
+
+	hwlog.RunLog.Info("query edge nodes cert update status success")
+	return common.RespMsg{Status: common.Success, Msg: "",
+		Data: newCertUpdateProgress(certType, nodesStatus, inProgress)}
+}
+
+func retryCertUpdate(msg *model.Message) common.RespMsg {
+	hwlog.RunLog.Info("start retry edge nodes cert update")
+	var req CertUpdateRetryReq
+	if err := msg.ParseContent(&req); err != nil {
+		hwlog.RunLog.Errorf("retry cert update failed: parse content failed: %v", err)
+		return common.RespMsg{Status: common.ErrorParamConvert, Msg: err.Error(), Data: nil}
+	}
+	if checkResult := newRetryReqChecker().Check(req); !checkResult.Result {
+		hwlog.RunLog.Errorf("check cert update retry para failed: %s", checkResult.Reason)
+		return common.RespMsg{Status: common.ErrorParamInvalid, Msg: checkResult.Reason, Data: nil}
+	}
+
+	var sendResults map[string]error
+	var err error
+	switch req.CertType {
+	case CertTypeEdgeCa:
+		sendResults, err = retryEdgeCaCertUpdate(req.SerialNumbers)
+	case CertTypeEdgeSvc:
+		sendResults, err = retryEdgeSvcCertUpdate(req.SerialNumbers)
+	default:
+		err = fmt.Errorf("cert [%v] update is not supported", req.CertType)
+	}
+	if err != nil {
+		hwlog.RunLog.Errorf("retry cert [%s] update failed: %v", req.CertType, err)
+		return common.RespMsg{Status: common.ErrorRetryCertUpdate, Msg: err.Error(), Data: nil}
+	}
+
+	batchResp := newRetryBatchResp(req.SerialNumbers, sendResults)
+	logmgmt.BatchOperationLog("retry cert update for edge nodes", batchResp.SuccessIDs)
+	if len(batchResp.FailedInfos) != 0 {
+		hwlog.RunLog.Error("retry edge nodes cert update failed for some nodes")
+		return common.RespMsg{Status: common.ErrorRetryCertUpdate, Msg: "", Data: batchResp}
+	}
+	hwlog.RunLog.Info("retry edge nodes cert update success")
+	return common.RespMsg{Status: common.Success, Msg: "", Data: batchResp}
+}
+
+// newRetryBatchResp only takes the nodes whose notify is re-sent as success,
+// the nodes without unsuccessful update record are skipped and reported with the reason.
+func newRetryBatchResp(sns []string, sendResults map[string]error) types.BatchResp {
+	batchResp := types.BatchResp{FailedInfos: make(map[string]string)}
+	for _, sn := range sns {
+		sendErr, ok := sendResults[sn]
+		if !ok {
+			batchResp.FailedInfos[sn] = "skipped, the node has no unsuccessful cert update record"
+			continue
+		}
+		if sendErr != nil {
+			batchResp.FailedInfos[sn] = fmt.Sprintf("re-send update notify failed: %v", sendErr)
+			continue
+		}
+		batchResp.SuccessIDs = append(batchResp.SuccessIDs, sn)
+	}
+	return batchResp
+}
+
+func retryEdgeCaCertUpdate(sns []string) (map[string]error, error) {
+	if atomic.LoadInt64(&edgeCaCertUpdateFlag) != InRunning {
+		return nil, fmt.Errorf("edge ca cert update is not running")
+	}
+	payload := getRunningPayload(&edgeCaWorkingLocker, &edgeCaRunningPayload)
+	if payload == nil {
+		return nil, fmt.Errorf("edge ca cert update is not ready")
+	}
+	edgeCaWorkingLocker.Lock()
+	defer edgeCaWorkingLocker.Unlock()
+	return edgeCaUpdaterInstance.processFailedRecords(payload, sns)
+}
+
+func retryEdgeSvcCertUpdate(sns []string) (map[string]error, error) {
+	if atomic.LoadInt64(&edgeSvcCertUpdateFlag) != InRunning {
+		return nil, fmt.Errorf("edge service cert update is not running")
+	}
+	payload := getRunningPayload(&edgeSvcWorkingLocker, &edgeSvcRunningPayload)
+	if payload == nil {
+		return nil, fmt.Errorf("edge service cert update is not ready")
+	}
+	edgeSvcWorkingLocker.Lock()
+	defer edgeSvcWorkingLocker.Unlock()
+	return edgeSvcUpdaterInstance.processFailedRecords(payload, sns)
+}
@@ -0,0 +1,140 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package certupdater test for querying cert update status and retrying failed nodes
+package certupdater
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/smartystreets/goconvey/convey"
+
+	"huawei.com/mindxedge/base/common"
+
+	"edge-manager/pkg/types"
+)
+
+const (
+	testSnSuccess = "2102312NSF10K8000130"
+	testSnFailed  = "2102312NSF10K8000131"
+)
+
+func prepareCaStatusRecords() {
+	records := []edgeCaCertStatus{
+		{Sn: testSnSuccess, Ip: "10.0.0.1", Status: UpdateStatusSuccess, AttemptCount: 1},
+		{Sn: testSnFailed, Ip: "10.0.0.2", Status: UpdateStatusFail, AttemptCount: 1, LastError: "conn lost"},
+	}
+	err := getEdgeCaCertStatusDbModInstance().DeleteRecordsBySns([]string{testSnSuccess, testSnFailed})
+	convey.So(err, convey.ShouldBeNil)
+	convey.So(getEdgeCaCertStatusDbModInstance().CreateMultipleRecords(records), convey.ShouldBeNil)
+}
+
+func TestQueryCertUpdateStatus(t *testing.T) {
+	convey.Convey("query cert update status should be success", t, testQueryCertUpdateStatus)
+	convey.Convey("query cert update status should be failed, invalid cert type", t,
+		testQueryCertUpdateStatusErrType)
+}
+
+func testQueryCertUpdateStatus() {
+	prepareCaStatusRecords()
+	resp := queryCertUpdateStatus(newMsgWithContentForUT(CertTypeEdgeCa))
+	convey.So(resp.Status, convey.ShouldEqual, common.Success)
+	progress, ok := resp.Data.(CertUpdateProgress)
+	convey.So(ok, convey.ShouldBeTrue)
+	convey.So(progress.InProgress, convey.ShouldBeFalse)
+	convey.So(progress.TotalCount, convey.ShouldEqual, len(progress.Nodes))
+	convey.So(progress.SuccessCount, convey.ShouldEqual, 1)
+	convey.So(progress.FailedCount, convey.ShouldEqual, 1)
+}
+
+func testQueryCertUpdateStatusErrType() {
+	resp := queryCertUpdateStatus(newMsgWithContentForUT("xxx"))
+	convey.So(resp.Status, convey.ShouldEqual, common.ErrorCertTypeError)
+}
+
+func TestRetryCertUpdate(t *testing.T) {
+	convey.Convey("retry cert update should be success", t, testRetryCertUpdate)
+	convey.Convey("retry cert update should be failed, notify is not re-sent", t, testRetryCertUpdateSendFailed)
+	convey.Convey("retry cert update should be failed, update is not running", t, testRetryCertUpdateNotRunning)
+	convey.Convey("retry cert update should be failed, invalid param", t, testRetryCertUpdateErrParam)
+}
+
+func setCaUpdateRunningForUT() func() {
+	atomic.StoreInt64(&edgeCaCertUpdateFlag, InRunning)
+	setRunningPayload(&edgeCaWorkingLocker, &edgeCaRunningPayload, &CertUpdatePayload{CertType: CertTypeEdgeCa})
+	return func() {
+		atomic.StoreInt64(&edgeCaCertUpdateFlag, NotRunning)
+		setRunningPayload(&edgeCaWorkingLocker, &edgeCaRunningPayload, nil)
+	}
+}
+
+func testRetryCertUpdate() {
+	prepareCaStatusRecords()
+	defer setCaUpdateRunningForUT()()
+	patches := gomonkey.ApplyFuncReturn(sendCertUpdateNotifyToNode, nil)
+	defer patches.Reset()
+
+	req := CertUpdateRetryReq{CertType: CertTypeEdgeCa, SerialNumbers: []string{testSnFailed}}
+	resp := retryCertUpdate(newMsgWithContentForUT(req))
+	convey.So(resp.Status, convey.ShouldEqual, common.Success)
+	batchResp, ok := resp.Data.(types.BatchResp)
+	convey.So(ok, convey.ShouldBeTrue)
+	convey.So(batchResp.SuccessIDs, convey.ShouldResemble, []interface{}{testSnFailed})
+	convey.So(batchResp.FailedInfos, convey.ShouldBeEmpty)
+}
+
+func testRetryCertUpdateSendFailed() {
+	prepareCaStatusRecords()
+	defer setCaUpdateRunningForUT()()
+	patches := gomonkey.ApplyFuncReturn(sendCertUpdateNotifyToNode, errors.New("node is offline"))
+	defer patches.Reset()
+
+	req := CertUpdateRetryReq{CertType: CertTypeEdgeCa, SerialNumbers: []string{testSnSuccess, testSnFailed}}
+	resp := retryCertUpdate(newMsgWithContentForUT(req))
+	convey.So(resp.Status, convey.ShouldEqual, common.ErrorRetryCertUpdate)
+	batchResp, ok := resp.Data.(types.BatchResp)
+	convey.So(ok, convey.ShouldBeTrue)
+	convey.So(batchResp.SuccessIDs, convey.ShouldBeEmpty)
+	convey.So(batchResp.FailedInfos[testSnSuccess], convey.ShouldContainSubstring, "skipped")
+	convey.So(batchResp.FailedInfos[testSnFailed], convey.ShouldContainSubstring, "node is offline")
+
+	records, err := getEdgeCaCertStatusDbModInstance().QueryRecordsBySns([]string{testSnSuccess, testSnFailed})
+	convey.So(err, convey.ShouldBeNil)
+	for _, record := range records {
+		if record.Sn == testSnSuccess {
+			convey.So(record.AttemptCount, convey.ShouldEqual, 1)
+			continue
+		}
+		convey.So(record.AttemptCount, convey.ShouldEqual, 2)
+		convey.So(record.LastError, convey.ShouldContainSubstring, "node is offline")
+	}
+}
+
+func testRetryCertUpdateNotRunning() {
+	req := CertUpdateRetryReq{CertType: CertTypeEdgeSvc, SerialNumbers: []string{testSnFailed}}
+	resp := retryCertUpdate(newMsgWithContentForUT(req))
+	convey.So(resp.Status, convey.ShouldEqual, common.ErrorRetryCertUpdate)
+}
+
+func testRetryCertUpdateErrParam() {
+	dataCases := []CertUpdateRetryReq{
+		{CertType: "xxx", SerialNumbers: []string{testSnFailed}},
+		{CertType: CertTypeEdgeCa, SerialNumbers: []string{}},
+		{CertType: CertTypeEdgeCa, SerialNumbers: []string{"_" + testSnFailed}},
+		{CertType: CertTypeEdgeCa, SerialNumbers: []string{testSnFailed, testSnFailed}},
+	}
+	for _, dataCase := range dataCases {
+		resp := retryCertUpdate(newMsgWithContentForUT(dataCase))
+		convey.So(resp.Status, convey.ShouldEqual, common.ErrorParamInvalid)
+	}
+}
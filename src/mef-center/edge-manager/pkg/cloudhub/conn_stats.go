@@ -0,0 +1,61 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package cloudhub the traffic statistics of edge node connections
+package cloudhub
+
+import (
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+
+	"huawei.com/mindx/common/hwlog"
+	"huawei.com/mindx/common/websocketmgr"
+
+	"huawei.com/mindxedge/base/common"
+)
+
+const querySerialNumber = "sn"
+
+var snReg = regexp.MustCompile(`^[a-zA-Z0-9]([-_a-zA-Z0-9]{0,62}[a-zA-Z0-9])?$`)
+
+// connStats traffic statistics of the connection to an edge node, the ratios are the percentage of wire bytes to
+// raw bytes, which show the effect of the compression config
+type connStats struct {
+	websocketmgr.CompressionStats
+	SendRatio    float64 `json:"sendRatio"`
+	ReceiveRatio float64 `json:"receiveRatio"`
+}
+
+// HandleConnStats reports the traffic statistics of the connection to the edge node given by serial number
+func HandleConnStats(c *gin.Context) {
+	sn := c.Query(querySerialNumber)
+	if !snReg.MatchString(sn) {
+		hwlog.RunLog.Error("check conn stats para failed: serial number is invalid")
+		common.ConstructResp(c, common.ErrorParamInvalid, "serial number is invalid", nil)
+		return
+	}
+	if server.serverProxy == nil {
+		hwlog.RunLog.Error("get conn stats failed: server proxy is not initialized")
+		common.ConstructResp(c, common.ErrorGetNodeConnStats, "", nil)
+		return
+	}
+	stats, err := server.serverProxy.GetCompressionStats(sn)
+	if err != nil {
+		hwlog.RunLog.Errorf("get conn stats of node [%s] failed: %v", sn, err)
+		common.ConstructResp(c, common.ErrorGetNodeConnStats, "", nil)
+		return
+	}
+	common.ConstructResp(c, common.Success, "", connStats{
+		CompressionStats: stats,
+		SendRatio:        stats.SendRatio(),
+		ReceiveRatio:     stats.ReceiveRatio(),
+	})
+}
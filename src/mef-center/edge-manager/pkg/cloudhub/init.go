@@ -29,6 +29,8 @@ import (
 
 	"edge-manager/pkg/cloudhub/innerwebsocket"
 	"edge-manager/pkg/constants"
+	"edge-manager/pkg/nodeprotocol"
+	"edge-manager/pkg/trace"
 
 	"huawei.com/mindxedge/base/common"
 	"huawei.com/mindxedge/base/common/requests"
@@ -68,6 +70,10 @@ func (c *CloudServer) initMsgHandler() {
 		HandlerFunc: innerwebsocket.AlarmClearHandler,
 		NeedLogging: false,
 	}
+	messageHandlerMap[common.OptReport+constants.ResEdgeCapability] = messageHandler{
+		HandlerFunc: updateNodeCapabilities,
+		NeedLogging: true,
+	}
 }
 
 // CloudServer wraps the struct WebSocketServer
@@ -81,6 +87,9 @@ type CloudServer struct {
 	writeLock    sync.RWMutex
 	ctx          context.Context
 	enable       bool
+	// compressThreshold and compressLevel the compression config of the connections to edge nodes
+	compressThreshold int
+	compressLevel     int
 }
 
 var server CloudServer
@@ -98,6 +107,14 @@ func NewCloudServer(enable bool, wsPort, innerWsPort, authPort, maxClientNum int
 	return &server
 }
 
+// SetCompressionCfg set the compression config of the connections to edge nodes, messages not smaller than
+// threshold in bytes are compressed with the flate level
+func (c *CloudServer) SetCompressionCfg(threshold, level int) *CloudServer {
+	c.compressThreshold = threshold
+	c.compressLevel = level
+	return c
+}
+
 // Name returns the name of websocket connection module
 func (c *CloudServer) Name() string {
 	return common.CloudHubName
@@ -172,6 +189,7 @@ func (c *CloudServer) dispatch(message *model.Message) {
 			}
 			hwlog.RunLog.Errorf("process message [option: %v res: %v]error: %v",
 				message.GetOption(), message.GetResource(), err)
+			c.response(message, common.FAIL)
 			return
 		}
 		if handler.NeedLogging {
@@ -185,7 +203,10 @@ func (c *CloudServer) dispatch(message *model.Message) {
 	}
 	if c.sendToEdge(retMsg) != nil {
 		c.response(retMsg, common.FAIL)
-	} else {
+		return
+	}
+	// the edge replies the ack to the message which needs ack after it is handled by the edge module
+	if !retMsg.GetNeedAck() {
 		c.response(retMsg, common.OK)
 	}
 }
@@ -221,13 +242,14 @@ func (c *CloudServer) sendToEdge(msg *model.Message) error {
 	msg.SetIsSync(false)
 	defer msg.SetIsSync(originalSync)
 	if err := sender.Send(msg.GetNodeId(), msg); err != nil {
-		hwlog.RunLog.Errorf("cloud hub send msg to edge node error: %v, operation is [%s], resource is [%s]",
-			err, msg.GetOption(), msg.GetResource())
+		hwlog.RunLog.ErrorfWithCtx(msg.TraceContext(), "cloud hub send msg to edge node error: %v, operation is [%s], "+
+			"resource is [%s]", err, msg.GetOption(), msg.GetResource())
 		return fmt.Errorf("send to client [%s] failed", msg.GetNodeId())
 	}
 
-	hwlog.RunLog.Infof("cloud hub send msg to edge node success, operation is [%s], resource is [%s]",
-		msg.GetOption(), msg.GetResource())
+	hwlog.RunLog.InfofWithCtx(msg.TraceContext(), "cloud hub send msg to edge node success, operation is [%s], "+
+		"resource is [%s]", msg.GetOption(), msg.GetResource())
+	trace.RecordNode(msg.GetTraceId(), msg.GetNodeId())
 
 	return nil
 }
@@ -326,6 +348,21 @@ func issueCertForEdge(msg *model.Message) (*model.Message, bool, error) {
 	return respMsg, true, nil
 }
 
+func updateNodeCapabilities(msg *model.Message) (*model.Message, bool, error) {
+	var report nodeprotocol.CapabilityReport
+	if err := msg.ParseContent(&report); err != nil {
+		hwlog.RunLog.Errorf("parse content failed: %v", err)
+		return nil, false, errors.New("parse content failed")
+	}
+	sn := msg.GetPeerInfo().Sn
+	if err := nodeprotocol.UpdateCapabilities(sn, report); err != nil {
+		hwlog.RunLog.Errorf("update capabilities of node [%s] failed: %v", sn, err)
+		return nil, false, errors.New("update capabilities failed")
+	}
+	hwlog.RunLog.Infof("capabilities of node [%s] are updated: %v", sn, report.Capabilities)
+	return nil, false, nil
+}
+
 func (c *CloudServer) getEdgeConnStatus(msg *model.Message) (*model.Message, bool, error) {
 	var snList []string
 	if err := msg.ParseContent(&snList); err != nil {
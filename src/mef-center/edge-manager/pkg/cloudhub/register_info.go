@@ -26,11 +26,18 @@ const (
 
 	defaultHandlerRate     = 16
 	defaultHandlerCapacity = 2048
+
+	remoteShellHandlerRate     = 64
+	remoteShellHandlerCapacity = 256
+
+	portForwardHandlerRate     = 1024
+	portForwardHandlerCapacity = 4096
 )
 
 var regInfoList = []*modulemgr.RegisterModuleInfo{
 	{MsgOpt: common.OptGet, MsgRes: common.ResConfig, ModuleName: common.NodeMsgManagerName},
 	{MsgOpt: common.OptReport, MsgRes: common.ResDownloadProgress, ModuleName: common.NodeMsgManagerName},
+	{MsgOpt: common.OptReport, MsgRes: common.ResEdgePreflightResult, ModuleName: common.NodeMsgManagerName},
 	{MsgOpt: common.OptReport, MsgRes: common.ResSoftwareInfo, ModuleName: common.NodeManagerName},
 	{MsgOpt: common.OptGet, MsgRes: common.ResDownLoadCert, ModuleName: common.NodeMsgManagerName},
 	{MsgOpt: common.OptPost, MsgRes: common.ResEdgeCert, ModuleName: common.CloudHubName},
@@ -38,6 +45,12 @@ var regInfoList = []*modulemgr.RegisterModuleInfo{
 	{MsgOpt: common.OptReport, MsgRes: constants.ResLogDumpError, ModuleName: constants.LogManagerName},
 	{MsgOpt: common.OptPost, MsgRes: requests.ReportAlarmRouter, ModuleName: common.CloudHubName,
 		Rps: alarmHandlerRate, Burst: alarmHandlerCapacity},
+	{MsgOpt: common.OptReport, MsgRes: constants.ResRemoteShellOutput, ModuleName: constants.RemoteShellName,
+		Rps: remoteShellHandlerRate, Burst: remoteShellHandlerCapacity},
+	{MsgOpt: common.OptReport, MsgRes: constants.ResPortForwardData, ModuleName: constants.PortForwardName,
+		Rps: portForwardHandlerRate, Burst: portForwardHandlerCapacity},
+	{MsgOpt: common.OptReport, MsgRes: constants.ResTraceLogData, ModuleName: constants.TraceName},
+	{MsgOpt: common.OptReport, MsgRes: constants.ResEdgeCapability, ModuleName: common.CloudHubName},
 }
 
 func getRegModuleInfoList() []modulemgr.MessageHandlerIntf {
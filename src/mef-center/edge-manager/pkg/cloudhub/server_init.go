@@ -23,8 +23,11 @@ import (
 	"huawei.com/mindx/common/websocketmgr"
 	"huawei.com/mindx/common/x509/certutils"
 
+	"edge-manager/pkg/artifactmanager"
 	"edge-manager/pkg/constants"
 	"edge-manager/pkg/logmanager"
+	"edge-manager/pkg/nodeprotocol"
+	"edge-manager/pkg/outbox"
 
 	"huawei.com/mindxedge/base/common"
 	"huawei.com/mindxedge/base/common/requests"
@@ -65,11 +68,19 @@ func InitServer() (*websocketmgr.WsServerProxy, error) {
 		return nil, errors.New("init proxy config failed")
 	}
 	proxyConfig.RegModInfos(getRegModuleInfoList())
+	if err = proxyConfig.SetProtocolInfo(nodeprotocol.CurrentVersion, nil); err != nil {
+		hwlog.RunLog.Errorf("init protocol info failed: %v", err)
+		return nil, fmt.Errorf("init protocol info failed: %v", err)
+	}
 	proxyConfig.SetTimeout(largeFileTimeout, largeFileTimeout, 0)
 	if err := proxyConfig.SetBandwidthLimiterCfg(wsMaxThroughput, wsThroughputPeriod); err != nil {
 		hwlog.RunLog.Errorf("init bandwidth limiter config failed: %v", err)
 		return nil, fmt.Errorf("init bandwidth limiter config failed: %v", err)
 	}
+	if err := proxyConfig.SetCompressionCfg(server.compressThreshold, server.compressLevel); err != nil {
+		hwlog.RunLog.Errorf("init compression config failed: %v", err)
+		return nil, fmt.Errorf("init compression config failed: %v", err)
+	}
 	proxy := &websocketmgr.WsServerProxy{
 		ProxyCfg: proxyConfig,
 	}
@@ -81,11 +92,15 @@ func InitServer() (*websocketmgr.WsServerProxy, error) {
 	}
 	proxy.AddDefaultHandler()
 	proxy.SetDisconnCallback(clearAlarm)
-	proxy.SetOnConnCallback(syncCertsToEdgeNode)
+	proxy.SetOnConnCallback(recordNodeProtocol, artifactmanager.RecordNodeCertSn, syncCertsToEdgeNode, flushOutbox)
 	if err = proxy.AddHandler(constants.LogUploadUrl, logmanager.HandleUpload); err != nil {
 		hwlog.RunLog.Error("add handler failed")
 		return nil, errors.New("add handler failed")
 	}
+	if err = proxy.AddHandler(constants.ArtifactDownloadUrl, artifactmanager.HandleDownload); err != nil {
+		hwlog.RunLog.Error("add handler failed")
+		return nil, errors.New("add handler failed")
+	}
 	if err = proxy.Start(); err != nil {
 		hwlog.RunLog.Errorf("proxy.Start failed: %v", err)
 		return nil, errors.New("proxy.Start failed")
@@ -161,6 +176,14 @@ func clearAlarm(peerInfo websocketmgr.WebsocketPeerInfo) {
 	}
 }
 
+// recordNodeProtocol records the protocol version and capabilities of node, it is called before the messages are
+// sent to the node, so that the modules checking the node get the negotiated ones
+func recordNodeProtocol(peerInfo websocketmgr.WebsocketPeerInfo) {
+	nodeprotocol.Record(peerInfo.Sn, peerInfo.ProtocolVersion, peerInfo.Capabilities)
+	hwlog.RunLog.Infof("edge node [%s] connects with protocol version %d, capabilities: %v", peerInfo.Sn,
+		peerInfo.ProtocolVersion, peerInfo.Capabilities)
+}
+
 func syncCertsToEdgeNode(peerInfo websocketmgr.WebsocketPeerInfo) {
 	hwlog.RunLog.Infof("start to send certs to edge node[name=%s][ip=%s]", peerInfo.Sn, peerInfo.Ip)
 	certs := []string{common.SoftwareCertName, common.ImageCertName}
@@ -172,6 +195,12 @@ func syncCertsToEdgeNode(peerInfo websocketmgr.WebsocketPeerInfo) {
 	}
 }
 
+// flushOutbox delivers messages queued while the node was offline, the on connect callbacks are called before the
+// connection starts to work, so the delivery is done asynchronously
+func flushOutbox(peerInfo websocketmgr.WebsocketPeerInfo) {
+	go outbox.FlushNode(peerInfo.Sn)
+}
+
 func sendCertMsg(cert string, peerInfo websocketmgr.WebsocketPeerInfo) error {
 	msg, err := model.NewMessage()
 	if err != nil {
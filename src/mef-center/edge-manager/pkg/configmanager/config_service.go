@@ -25,7 +25,6 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"huawei.com/mindx/common/hwlog"
-	"huawei.com/mindx/common/modulemgr"
 	"huawei.com/mindx/common/modulemgr/model"
 	"huawei.com/mindx/common/utils"
 	"huawei.com/mindx/common/x509"
@@ -35,6 +34,8 @@ import (
 	"edge-manager/pkg/config"
 	"edge-manager/pkg/configmanager/configchecker"
 	"edge-manager/pkg/kubeclient"
+	"edge-manager/pkg/nodeprotocol"
+	"edge-manager/pkg/outbox"
 	"edge-manager/pkg/util"
 
 	"huawei.com/mindxedge/base/common"
@@ -193,6 +194,11 @@ func reportCertToClient(req certutils.ClientCertResp) error {
 		Resource:    common.ResDownLoadCert,
 	}
 	for _, node := range nodes {
+		// the node not supporting cert sync keeps its cert, it gets the cert on connect after it is upgraded
+		if err := nodeprotocol.CheckSupport(node.SerialNumber, nodeprotocol.FeatureCertSync); err != nil {
+			hwlog.RunLog.Warnf("skip sending cert to node [%s]: %v", node.SerialNumber, err)
+			continue
+		}
 		if err := sendMessageToNode(node.SerialNumber, string(content), router); err != nil {
 			hwlog.RunLog.Warnf("send message to node [%s], error: %v", node.SerialNumber, err)
 			continue
@@ -211,7 +217,8 @@ func sendMessageToNode(serialNumber string, content string, router common.Router
 	if err = sendMsg.FillContent(content); err != nil {
 		return fmt.Errorf("fill content failed: %v", err)
 	}
-	if err = modulemgr.SendMessage(sendMsg); err != nil {
+	opts := outbox.Options{Priority: outbox.PriorityNormal, Ttl: common.OneDay}
+	if err = outbox.SendToNode(sendMsg, opts); err != nil {
 		return fmt.Errorf("%s sends message to %s failed, error: %v",
 			common.ConfigManagerName, common.CloudHubName, err)
 	}
@@ -23,6 +23,7 @@ import (
 	"edge-manager/pkg/config"
 	"edge-manager/pkg/kubeclient"
 	"edge-manager/pkg/nodemanager"
+	"edge-manager/pkg/outbox"
 	"edge-manager/pkg/util"
 	"huawei.com/mindxedge/base/common"
 )
@@ -73,6 +74,7 @@ func TestMain(m *testing.M) {
 			}},
 		}).
 		ApplyFuncReturn(modulemgr.SendMessage, nil).
+		ApplyFuncReturn(outbox.SendToNode, nil).
 		ApplyFuncReturn(util.GetImageAddress, "xxxx", nil).
 		ApplyFuncReturn(config.GetCertCrlPairCache, config.CertCrlPair{CertPEM: base64CertContent}, nil).
 		ApplyMethodReturn(&kubeclient.Client{}, "CreateOrUpdateSecret", nil, nil)
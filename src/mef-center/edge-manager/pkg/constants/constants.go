@@ -60,6 +60,39 @@ const (
 	LogUploadMaxSize = 200 * common.MB
 )
 
+const (
+	// RemoteShellUrlPrefix prefix for url of remote shell sessions
+	RemoteShellUrlPrefix = "/edgemanager/v1/remoteshell/session"
+	// ResRemoteShell resource for opening, writing, resizing and closing remote shell sessions on edge
+	ResRemoteShell = "/remoteshell/session"
+	// ResRemoteShellOutput resource for edge reporting output of remote shell sessions
+	ResRemoteShellOutput = "/remoteshell/output"
+	// PortForwardUrlPrefix prefix for url of port forward tunnels
+	PortForwardUrlPrefix = "/edgemanager/v1/portforward/tunnel"
+	// PortForwardStreamUrl url for the websocket carrying a stream of port forward tunnel
+	PortForwardStreamUrl = "/edgemanager/v1/portforward/stream"
+	// ResPortForward resource for connecting, writing and closing port forward streams on edge
+	ResPortForward = "/portforward/stream"
+	// ResPortForwardData resource for edge reporting data of port forward streams
+	ResPortForwardData = "/portforward/data"
+	// TraceUrlPrefix prefix for url of trace query
+	TraceUrlPrefix = "/edgemanager/v1/trace"
+	// ResTraceLog resource for collecting the log lines of a trace on edge
+	ResTraceLog = "/trace/log"
+	// ResTraceLogData resource for edge reporting the log lines of a trace
+	ResTraceLogData = "/trace/log/data"
+	// ResEdgeCapability resource for edge reporting its capabilities when they change after connect
+	ResEdgeCapability = "/edge/capability"
+	// ArtifactUrlPrefix prefix for url of the artifact repository
+	ArtifactUrlPrefix = "/edgemanager/v1/artifact"
+	// ArtifactDownloadUrl resource for edge downloading artifacts
+	ArtifactDownloadUrl = "/artifact/download"
+	// ArtifactDir the dir of artifacts hosted by edge-manager
+	ArtifactDir = "/home/MEFCenter/artifacts"
+	// ArtifactIdRegexpStr artifact id regexp
+	ArtifactIdRegexpStr = "^[a-f0-9]{32}$"
+)
+
 // consts for args in TaskSpec struct
 const (
 	NodeSnAndIp = "nodeSnAndIp"
@@ -70,6 +103,14 @@ const (
 const (
 	// LogManagerName LogManagerName
 	LogManagerName = "LogManager"
+	// RemoteShellName module name of remote shell
+	RemoteShellName = "RemoteShell"
+	// PortForwardName module name of port forward
+	PortForwardName = "PortForward"
+	// TraceName module name of trace query
+	TraceName = "Trace"
+	// ArtifactManagerName module name of artifact repository
+	ArtifactManagerName = "ArtifactManager"
 	// MefCenterUserName user name
 	MefCenterUserName = "MEFCenter"
 	// LocalHost ip
@@ -0,0 +1,438 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package edgemsgmanager upgrade campaign of edge software, the nodes of node groups are downloaded and effected in
+// waves, and the campaign halts when the failure ratio passes the threshold
+package edgemsgmanager
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"huawei.com/mindx/common/hwlog"
+	"huawei.com/mindx/common/modulemgr/model"
+	"huawei.com/mindx/common/utils"
+
+	"huawei.com/mindxedge/base/common"
+	"huawei.com/mindxedge/base/common/taskschedule"
+
+	"edge-manager/pkg/types"
+)
+
+const (
+	upgradeCampaignTaskName         = "upgradeCampaign"
+	campaignExecuteTimeout          = 7 * 24 * time.Hour
+	campaignGracefulShutdownTimeout = time.Minute
+	downloadFinishedProgress        = 100
+	maxPercent                      = 100
+
+	campaignReasonHalted  = "halted"
+	campaignReasonAborted = "aborted"
+	downloadResFailed     = "failed"
+	downloadResSuccess    = "success"
+)
+
+// campaignPollInterval interval of polling the download progress and software version of nodes in a wave
+var campaignPollInterval = 10 * time.Second
+
+var errCampaignAborted = errors.New("campaign is aborted")
+
+// campaign the running upgrade campaign, the download info with password is kept in memory only
+type campaign struct {
+	req      UpgradeCampaignReq
+	lock     sync.Mutex
+	paused   bool
+	resumeCh chan struct{}
+}
+
+type campaignRegistry struct {
+	lock      sync.Mutex
+	campaigns map[string]*campaign
+}
+
+var campaigns = &campaignRegistry{campaigns: make(map[string]*campaign)}
+
+func initCampaignTask() {
+	taskschedule.DefaultScheduler().RegisterExecutorFactory(
+		taskschedule.NewExecutorFactory(upgradeCampaignTaskName, doUpgradeCampaign))
+	taskschedule.DefaultScheduler().RegisterGoroutinePool(taskschedule.GoroutinePoolSpec{
+		Id:             upgradeCampaignTaskName,
+		MaxConcurrency: 1,
+		MaxCapacity:    0,
+	})
+}
+
+// submit submits the master task of campaign, only one campaign is allowed to run at a time, since the upgrades of
+// different campaigns on the same node conflict
+func (r *campaignRegistry) submit(req UpgradeCampaignReq) (string, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if len(r.campaigns) > 0 {
+		return "", errors.New("another upgrade campaign is running")
+	}
+	task := &taskschedule.TaskSpec{
+		Name:          upgradeCampaignTaskName,
+		GoroutinePool: upgradeCampaignTaskName,
+		Command:       upgradeCampaignTaskName,
+		Args: map[string]interface{}{
+			"nodeGroupIDs":     req.NodeGroupIDs,
+			"softwareName":     req.SoftwareName,
+			"version":          req.Version,
+			"wavePercents":     req.WavePercents,
+			"failureThreshold": req.FailureThreshold,
+			"waveTimeout":      req.WaveTimeout,
+		},
+		ExecuteTimeout:          campaignExecuteTimeout,
+		GracefulShutdownTimeout: campaignGracefulShutdownTimeout,
+	}
+	// the lock is held until the campaign is recorded, so the executor always finds its campaign
+	if err := taskschedule.DefaultScheduler().SubmitTask(task); err != nil {
+		return "", fmt.Errorf("submit campaign task failed: %v", err)
+	}
+	r.campaigns[task.Id] = &campaign{req: req}
+	return task.Id, nil
+}
+
+func (r *campaignRegistry) get(id string) *campaign {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.campaigns[id]
+}
+
+func (r *campaignRegistry) remove(id string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	delete(r.campaigns, id)
+}
+
+func (c *campaign) pause() error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.paused {
+		return errors.New("campaign is already paused")
+	}
+	c.paused = true
+	c.resumeCh = make(chan struct{})
+	return nil
+}
+
+func (c *campaign) resume() error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if !c.paused {
+		return errors.New("campaign is not paused")
+	}
+	c.paused = false
+	close(c.resumeCh)
+	return nil
+}
+
+func (c *campaign) isPaused() bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.paused
+}
+
+// waitResumed blocks before the next wave while the campaign is paused, the running wave is not interrupted
+func (c *campaign) waitResumed(ctx taskschedule.TaskContext, result *campaignResult) error {
+	c.lock.Lock()
+	paused, resumeCh := c.paused, c.resumeCh
+	c.lock.Unlock()
+	if !paused {
+		select {
+		case <-ctx.GracefulShutdown():
+			return errCampaignAborted
+		default:
+			return nil
+		}
+	}
+	hwlog.RunLog.Infof("upgrade campaign [%s] is paused before wave %d", ctx.Spec().Id, result.currentWave+1)
+	updateCampaignStatus(ctx, result.status(taskschedule.Processing, "", "campaign is paused"))
+	select {
+	case <-resumeCh:
+		hwlog.RunLog.Infof("upgrade campaign [%s] is resumed", ctx.Spec().Id)
+		return nil
+	case <-ctx.GracefulShutdown():
+		return errCampaignAborted
+	}
+}
+
+func doUpgradeCampaign(ctx taskschedule.TaskContext) {
+	id := ctx.Spec().Id
+	c := campaigns.get(id)
+	if c == nil {
+		hwlog.RunLog.Errorf("upgrade campaign [%s] is not found", id)
+		updateCampaignStatus(ctx, taskschedule.TaskStatus{Phase: taskschedule.Failed, Message: "campaign is lost"})
+		return
+	}
+	defer campaigns.remove(id)
+	defer clearCampaignPassword(c.req)
+	hwlog.RunLog.Infof("upgrade campaign [%s] starts", id)
+	status := c.run(ctx)
+	hwlog.RunLog.Infof("upgrade campaign [%s] finishes, phase: %s, message: %s", id, status.Phase, status.Message)
+	updateCampaignStatus(ctx, status)
+}
+
+func (c *campaign) run(ctx taskschedule.TaskContext) taskschedule.TaskStatus {
+	waves, total, err := c.planWaves()
+	if err != nil {
+		hwlog.RunLog.Errorf("plan waves of upgrade campaign failed: %v", err)
+		return taskschedule.TaskStatus{Phase: taskschedule.Failed, Message: err.Error()}
+	}
+	result := &campaignResult{total: total, waveNum: len(waves), failedInfos: make(map[string]string)}
+	for _, wave := range waves {
+		if err = c.waitResumed(ctx, result); err != nil {
+			return result.status(taskschedule.Failed, campaignReasonAborted, err.Error())
+		}
+		result.currentWave++
+		if len(wave) == 0 {
+			continue
+		}
+		updateCampaignStatus(ctx, result.status(taskschedule.Processing, "",
+			fmt.Sprintf("wave (%d/%d) is in progress", result.currentWave, result.waveNum)))
+		if err = c.runWave(ctx, wave, result); err != nil {
+			return result.status(taskschedule.Failed, campaignReasonAborted, err.Error())
+		}
+		if result.exceedThreshold(c.req.FailureThreshold) {
+			hwlog.RunLog.Errorf("failure ratio of upgrade campaign exceeds the threshold %d%%, halt the campaign",
+				c.req.FailureThreshold)
+			return result.status(taskschedule.Failed, campaignReasonHalted,
+				fmt.Sprintf("failure ratio exceeds the threshold %d%% after wave %d", c.req.FailureThreshold,
+					result.currentWave))
+		}
+	}
+	if len(result.failedInfos) != 0 {
+		return result.status(taskschedule.PartiallyFailed, "", "campaign partially failed")
+	}
+	return result.status(taskschedule.Succeed, "", "campaign succeeded")
+}
+
+// planWaves splits the nodes of each node group into waves by the cumulative percents, a node belonging to several
+// groups is upgraded with the first group only
+func (c *campaign) planWaves() ([][]string, int, error) {
+	var (
+		groups [][]string
+		total  int
+	)
+	seen := make(map[string]struct{})
+	for _, groupID := range c.req.NodeGroupIDs {
+		sns, err := getNodeSnsByGroup(groupID)
+		if err != nil {
+			return nil, 0, fmt.Errorf("get nodes of group [%d] failed: %v", groupID, err)
+		}
+		var nodes []string
+		for _, sn := range sns {
+			if _, ok := seen[sn]; ok {
+				continue
+			}
+			seen[sn] = struct{}{}
+			nodes = append(nodes, sn)
+		}
+		groups = append(groups, nodes)
+		total += len(nodes)
+	}
+	if total == 0 {
+		return nil, 0, errors.New("no node in the node groups")
+	}
+	if total > common.MaxNode {
+		return nil, 0, fmt.Errorf("the count of nodes exceeds the limit %d", common.MaxNode)
+	}
+	waves := make([][]string, len(c.req.WavePercents))
+	for _, nodes := range groups {
+		start := 0
+		for i, percent := range c.req.WavePercents {
+			end := (len(nodes)*int(percent) + maxPercent - 1) / maxPercent
+			waves[i] = append(waves[i], nodes[start:end]...)
+			start = end
+		}
+	}
+	return waves, total, nil
+}
+
+// runWave downloads the software to the nodes of wave, effects it on the nodes downloaded successfully, and waits
+// for the nodes to report the new version
+func (c *campaign) runWave(ctx taskschedule.TaskContext, wave []string, result *campaignResult) error {
+	deadline := time.Now().Add(time.Duration(c.req.WaveTimeout) * time.Second)
+	downloaded, err := c.download(wave, result)
+	if err != nil {
+		return err
+	}
+	downloaded, err = waitNodes(ctx, downloaded, deadline, result, checkDownloaded)
+	if err != nil {
+		return err
+	}
+	effected := c.effect(downloaded, result)
+	upgraded, err := waitNodes(ctx, effected, deadline, result, c.checkVersion)
+	if err != nil {
+		return err
+	}
+	result.succeeded += len(upgraded)
+	return nil
+}
+
+func (c *campaign) download(sns []string, result *campaignResult) ([]string, error) {
+	req := SoftwareDownloadInfo{SerialNumbers: sns, SoftwareName: c.req.SoftwareName, DownloadInfo: c.req.DownloadInfo}
+	content, err := json.Marshal(req)
+	if err != nil {
+		return nil, errors.New("marshal software download info failed")
+	}
+	defer utils.ClearSliceByteMemory(content)
+	msg, err := model.NewMessage()
+	if err != nil {
+		return nil, errors.New("create message failed")
+	}
+	msg.SetRouter(common.NodeMsgManagerName, common.CloudHubName, common.OptPost, common.ResEdgeDownloadInfo)
+	if err = msg.FillContent(content); err != nil {
+		return nil, fmt.Errorf("fill content failed: %v", err)
+	}
+	batchResp := sendDownloadInfo(req, msg)
+	for sn, reason := range batchResp.FailedInfos {
+		result.failedInfos[sn] = reason
+	}
+	var sent []string
+	for _, id := range batchResp.SuccessIDs {
+		if sn, ok := id.(string); ok {
+			sent = append(sent, sn)
+		}
+	}
+	return sent, nil
+}
+
+func (c *campaign) effect(sns []string, result *campaignResult) []string {
+	if len(sns) == 0 {
+		return nil
+	}
+	content, err := json.Marshal(UpgradeSoftwareReq{SerialNumbers: sns, SoftwareName: c.req.SoftwareName})
+	if err != nil {
+		for _, sn := range sns {
+			result.failedInfos[sn] = "marshal software upgrade info failed"
+		}
+		return nil
+	}
+	var effected []string
+	for _, sn := range sns {
+		if err = sendUpgradeConfigToEdge(sn, content, ""); err != nil {
+			hwlog.RunLog.Errorf("send upgrade msg to edge failed: %v", err)
+			result.failedInfos[sn] = err.Error()
+			continue
+		}
+		effected = append(effected, sn)
+	}
+	return effected
+}
+
+// waitNodes polls the state of nodes until all of them finish or the wave times out, the finished nodes are returned
+func waitNodes(ctx taskschedule.TaskContext, sns []string, deadline time.Time, result *campaignResult,
+	check func(sn string) (bool, error)) ([]string, error) {
+	ticker := time.NewTicker(campaignPollInterval)
+	defer ticker.Stop()
+	var finished []string
+	pending := sns
+	for len(pending) > 0 {
+		var next []string
+		for _, sn := range pending {
+			done, err := check(sn)
+			if err != nil {
+				result.failedInfos[sn] = err.Error()
+				continue
+			}
+			if done {
+				finished = append(finished, sn)
+				continue
+			}
+			next = append(next, sn)
+		}
+		pending = next
+		if len(pending) == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			for _, sn := range pending {
+				result.failedInfos[sn] = "wave timeout"
+			}
+			break
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.GracefulShutdown():
+			return nil, errCampaignAborted
+		}
+	}
+	return finished, nil
+}
+
+func checkDownloaded(sn string) (bool, error) {
+	value, err := nodesProgress.Get(sn)
+	if err != nil {
+		return false, errors.New("get download progress failed")
+	}
+	progress, ok := value.(types.ProgressInfo)
+	if !ok {
+		return false, errors.New("convert download progress failed")
+	}
+	if progress.Res == downloadResFailed {
+		return false, fmt.Errorf("download software failed: %s", progress.Msg)
+	}
+	return progress.Res == downloadResSuccess && progress.Progress == downloadFinishedProgress, nil
+}
+
+// checkVersion checks whether the node has reported the new version, the report may be not received yet
+func (c *campaign) checkVersion(sn string) (bool, error) {
+	softwareInfo, err := getNodeSoftwareInfo(sn)
+	if err != nil {
+		hwlog.RunLog.Warnf("get software info of node [%s] failed: %v", sn, err)
+		return false, nil
+	}
+	for _, info := range softwareInfo {
+		if info.Name == c.req.SoftwareName && info.Version == c.req.Version {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+type campaignResult struct {
+	total       int
+	succeeded   int
+	waveNum     int
+	currentWave int
+	failedInfos map[string]string
+}
+
+func (r *campaignResult) exceedThreshold(threshold uint64) bool {
+	finished := r.succeeded + len(r.failedInfos)
+	return finished > 0 && uint64(len(r.failedInfos))*maxPercent > threshold*uint64(finished)
+}
+
+func (r *campaignResult) status(phase taskschedule.TaskPhase, reason, message string) taskschedule.TaskStatus {
+	return taskschedule.TaskStatus{
+		Phase:    phase,
+		Reason:   reason,
+		Message:  message,
+		Progress: uint((r.succeeded + len(r.failedInfos)) * common.ProgressMax / r.total),
+		Data: map[string]interface{}{
+			"total":       r.total,
+			"succeeded":   r.succeeded,
+			"failed":      len(r.failedInfos),
+			"waveNum":     r.waveNum,
+			"currentWave": r.currentWave,
+			"failedInfos": r.failedInfos,
+		},
+	}
+}
+
+func updateCampaignStatus(ctx taskschedule.TaskContext, status taskschedule.TaskStatus) {
+	if err := ctx.UpdateStatus(status); err != nil {
+		hwlog.RunLog.Errorf("update status of upgrade campaign [%s] failed: %v", ctx.Spec().Id, err)
+	}
+}
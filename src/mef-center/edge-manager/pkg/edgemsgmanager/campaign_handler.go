@@ -0,0 +1,146 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package edgemsgmanager create, query, pause, resume and abort upgrade campaign of edge software
+package edgemsgmanager
+
+import (
+	"errors"
+
+	"huawei.com/mindx/common/checker"
+	"huawei.com/mindx/common/hwlog"
+	"huawei.com/mindx/common/modulemgr/model"
+	"huawei.com/mindx/common/utils"
+
+	"huawei.com/mindxedge/base/common"
+	"huawei.com/mindxedge/base/common/logmgmt"
+	"huawei.com/mindxedge/base/common/taskschedule"
+)
+
+const campaignIdReg = "^" + upgradeCampaignTaskName + `[-_a-zA-Z0-9.]{1,128}$`
+
+func createUpgradeCampaign(msg *model.Message) common.RespMsg {
+	hwlog.RunLog.Info("start create upgrade campaign")
+	var req UpgradeCampaignReq
+	if err := msg.ParseContent(&req); err != nil {
+		hwlog.RunLog.Errorf("parse content failed: %v", err)
+		return common.RespMsg{Status: common.ErrorParamConvert, Msg: "parse content failed", Data: nil}
+	}
+	if checkResult := newCampaignChecker().Check(req); !checkResult.Result {
+		clearCampaignPassword(req)
+		hwlog.RunLog.Errorf("check upgrade campaign para failed: %s", checkResult.Reason)
+		return common.RespMsg{Status: common.ErrorParamInvalid, Msg: checkResult.Reason, Data: nil}
+	}
+	if err := checkArtifact(req.DownloadInfo); err != nil {
+		clearCampaignPassword(req)
+		hwlog.RunLog.Errorf("check software artifact failed: %v", err)
+		return common.RespMsg{Status: common.ErrorParamInvalid, Msg: err.Error(), Data: nil}
+	}
+	id, err := campaigns.submit(req)
+	if err != nil {
+		clearCampaignPassword(req)
+		hwlog.RunLog.Errorf("create upgrade campaign failed: %v", err)
+		return common.RespMsg{Status: common.ErrorUpgradeCampaign, Msg: err.Error(), Data: nil}
+	}
+	logmgmt.BatchOperationLog("create upgrade campaign", []interface{}{id})
+	hwlog.RunLog.Infof("create upgrade campaign [%s] success", id)
+	return common.RespMsg{Status: common.Success, Msg: "", Data: id}
+}
+
+func queryUpgradeCampaign(msg *model.Message) common.RespMsg {
+	var id string
+	if err := msg.ParseContent(&id); err != nil {
+		hwlog.RunLog.Errorf("parse content failed: %v", err)
+		return common.RespMsg{Status: common.ErrorParamConvert, Msg: "parse content failed", Data: nil}
+	}
+	if res := checker.GetRegChecker("", campaignIdReg, true).Check(id); !res.Result {
+		hwlog.RunLog.Errorf("check upgrade campaign id failed: %s", res.Reason)
+		return common.RespMsg{Status: common.ErrorParamInvalid, Msg: res.Reason, Data: nil}
+	}
+	taskCtx, err := taskschedule.DefaultScheduler().GetTaskContext(id)
+	if err != nil {
+		hwlog.RunLog.Errorf("get upgrade campaign [%s] failed: %v", id, err)
+		return common.RespMsg{Status: common.ErrorUpgradeCampaign, Msg: "campaign is not found", Data: nil}
+	}
+	status, err := taskCtx.GetStatus()
+	if err != nil {
+		hwlog.RunLog.Errorf("get status of upgrade campaign [%s] failed: %v", id, err)
+		return common.RespMsg{Status: common.ErrorUpgradeCampaign, Msg: "get campaign status failed", Data: nil}
+	}
+	info := CampaignInfo{
+		ID:         id,
+		Phase:      status.Phase,
+		Reason:     status.Reason,
+		Message:    status.Message,
+		Progress:   status.Progress,
+		Data:       status.Data,
+		CreatedAt:  status.CreatedAt,
+		FinishedAt: status.FinishedAt,
+	}
+	if c := campaigns.get(id); c != nil {
+		info.Paused = c.isPaused()
+	}
+	return common.RespMsg{Status: common.Success, Msg: "", Data: info}
+}
+
+func pauseUpgradeCampaign(msg *model.Message) common.RespMsg {
+	return operateUpgradeCampaign(msg, "pause", func(id string, c *campaign) error {
+		return c.pause()
+	})
+}
+
+func resumeUpgradeCampaign(msg *model.Message) common.RespMsg {
+	return operateUpgradeCampaign(msg, "resume", func(id string, c *campaign) error {
+		return c.resume()
+	})
+}
+
+// abortUpgradeCampaign cancels the campaign task, the nodes being upgraded in current wave are not rolled back
+func abortUpgradeCampaign(msg *model.Message) common.RespMsg {
+	return operateUpgradeCampaign(msg, "abort", func(id string, c *campaign) error {
+		taskCtx, err := taskschedule.DefaultScheduler().GetTaskContext(id)
+		if err != nil {
+			return errors.New("campaign task is not found")
+		}
+		taskCtx.Cancel()
+		return nil
+	})
+}
+
+func operateUpgradeCampaign(msg *model.Message, operation string, operate func(string, *campaign) error) common.RespMsg {
+	hwlog.RunLog.Infof("start %s upgrade campaign", operation)
+	var req CampaignOperateReq
+	if err := msg.ParseContent(&req); err != nil {
+		hwlog.RunLog.Errorf("parse content failed: %v", err)
+		return common.RespMsg{Status: common.ErrorParamConvert, Msg: "parse content failed", Data: nil}
+	}
+	if res := checker.GetRegChecker("ID", campaignIdReg, true).Check(req); !res.Result {
+		hwlog.RunLog.Errorf("check upgrade campaign id failed: %s", res.Reason)
+		return common.RespMsg{Status: common.ErrorParamInvalid, Msg: res.Reason, Data: nil}
+	}
+	c := campaigns.get(req.ID)
+	if c == nil {
+		hwlog.RunLog.Errorf("upgrade campaign [%s] is not running", req.ID)
+		return common.RespMsg{Status: common.ErrorUpgradeCampaign, Msg: "campaign is not running", Data: nil}
+	}
+	if err := operate(req.ID, c); err != nil {
+		hwlog.RunLog.Errorf("%s upgrade campaign [%s] failed: %v", operation, req.ID, err)
+		return common.RespMsg{Status: common.ErrorUpgradeCampaign, Msg: err.Error(), Data: nil}
+	}
+	logmgmt.BatchOperationLog(operation+" upgrade campaign", []interface{}{req.ID})
+	hwlog.RunLog.Infof("%s upgrade campaign [%s] success", operation, req.ID)
+	return common.RespMsg{Status: common.Success, Msg: "", Data: nil}
+}
+
+func clearCampaignPassword(req UpgradeCampaignReq) {
+	if req.DownloadInfo.Password != nil {
+		utils.ClearSliceByteMemory(*req.DownloadInfo.Password)
+	}
+}
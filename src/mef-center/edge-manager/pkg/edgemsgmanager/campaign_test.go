@@ -0,0 +1,218 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package edgemsgmanager test for upgrade campaign of edge software
+package edgemsgmanager
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/smartystreets/goconvey/convey"
+
+	"huawei.com/mindx/common/modulemgr/model"
+
+	"huawei.com/mindxedge/base/common"
+	"huawei.com/mindxedge/base/common/taskschedule"
+
+	"edge-manager/pkg/types"
+)
+
+const testCampaignVersion = "7.0.RC1"
+
+type fakeCampaignCtx struct {
+	context.Context
+	shutdown chan struct{}
+	statuses []taskschedule.TaskStatus
+}
+
+func newFakeCampaignCtx() *fakeCampaignCtx {
+	return &fakeCampaignCtx{Context: context.Background(), shutdown: make(chan struct{})}
+}
+
+func (f *fakeCampaignCtx) Spec() taskschedule.TaskSpec {
+	return taskschedule.TaskSpec{Id: upgradeCampaignTaskName + ".test"}
+}
+
+func (f *fakeCampaignCtx) GracefulShutdown() <-chan struct{} {
+	return f.shutdown
+}
+
+func (f *fakeCampaignCtx) UpdateLiveness() error {
+	return nil
+}
+
+func (f *fakeCampaignCtx) UpdateStatus(status taskschedule.TaskStatus) error {
+	f.statuses = append(f.statuses, status)
+	return nil
+}
+
+func (f *fakeCampaignCtx) GetStatus() (taskschedule.TaskStatus, error) {
+	return taskschedule.TaskStatus{}, nil
+}
+
+func (f *fakeCampaignCtx) GetSubTaskTree() (taskschedule.TaskTreeNode, error) {
+	return taskschedule.TaskTreeNode{}, nil
+}
+
+func (f *fakeCampaignCtx) Cancel() {}
+
+func newTestCampaign(percents []uint64, threshold uint64) *campaign {
+	pwd := Password("Test@1234")
+	return &campaign{req: UpgradeCampaignReq{
+		NodeGroupIDs:     []uint64{1, 2},
+		SoftwareName:     common.MEFEdge,
+		Version:          testCampaignVersion,
+		DownloadInfo:     DownloadInfo{Package: "GET https://127.0.0.1/edge.tar.gz", UserName: "testuser", Password: &pwd},
+		WavePercents:     percents,
+		FailureThreshold: threshold,
+		WaveTimeout:      60,
+	}}
+}
+
+func nodeSnsOfTestGroups(groupID uint64) ([]string, error) {
+	const nodeNum = 4
+	var sns []string
+	for i := 0; i < nodeNum; i++ {
+		sns = append(sns, fmt.Sprintf("node%d%d", groupID, i))
+	}
+	// node shared by the two groups
+	sns = append(sns, "node0")
+	return sns, nil
+}
+
+// patchCampaignNodes simulates the nodes which download successfully except the failed ones, and report the version
+func patchCampaignNodes(failedNodes map[string]bool, version string) *gomonkey.Patches {
+	return gomonkey.ApplyFunc(getNodeSnsByGroup, nodeSnsOfTestGroups).
+		ApplyFunc(sendDownloadInfo, func(req SoftwareDownloadInfo, msg *model.Message) types.BatchResp {
+			resp := types.BatchResp{FailedInfos: map[string]string{}}
+			for _, sn := range req.SerialNumbers {
+				progress := types.ProgressInfo{Progress: downloadFinishedProgress, Res: downloadResSuccess}
+				if failedNodes[sn] {
+					progress = types.ProgressInfo{Res: downloadResFailed, Msg: "no space"}
+				}
+				if err := nodesProgress.Set(sn, progress, neverOverdue); err != nil {
+					resp.FailedInfos[sn] = err.Error()
+					continue
+				}
+				resp.SuccessIDs = append(resp.SuccessIDs, sn)
+			}
+			return resp
+		}).
+		ApplyFunc(sendUpgradeConfigToEdge, func(sn string, upgradeConfig []byte, traceId string) error {
+			return nil
+		}).
+		ApplyFunc(getNodeSoftwareInfo, func(sn string) ([]types.SoftwareInfo, error) {
+			return []types.SoftwareInfo{{Name: common.MEFEdge, Version: version}}, nil
+		})
+}
+
+func TestCampaignChecker(t *testing.T) {
+	convey.Convey("valid campaign request passes the check", t, func() {
+		req := newTestCampaign([]uint64{10, 50, 100}, 20).req
+		convey.So(newCampaignChecker().Check(req).Result, convey.ShouldBeTrue)
+	})
+
+	convey.Convey("wave percents should increase and end with 100", t, func() {
+		req := newTestCampaign([]uint64{50, 10, 100}, 20).req
+		convey.So(newCampaignChecker().Check(req).Result, convey.ShouldBeFalse)
+		req.WavePercents = []uint64{10, 50}
+		convey.So(newCampaignChecker().Check(req).Result, convey.ShouldBeFalse)
+		req.WavePercents = []uint64{100}
+		req.FailureThreshold = 101
+		convey.So(newCampaignChecker().Check(req).Result, convey.ShouldBeFalse)
+	})
+}
+
+func TestPlanWaves(t *testing.T) {
+	convey.Convey("waves take the cumulative percent of nodes of each group", t, func() {
+		patches := gomonkey.ApplyFunc(getNodeSnsByGroup, nodeSnsOfTestGroups)
+		defer patches.Reset()
+		waves, total, err := newTestCampaign([]uint64{20, 50, 100}, 0).planWaves()
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(total, convey.ShouldEqual, 9)
+		convey.So(waves, convey.ShouldResemble, [][]string{
+			{"node10", "node20"},
+			{"node11", "node12", "node21"},
+			{"node13", "node0", "node22", "node23"},
+		})
+	})
+}
+
+func TestRunCampaign(t *testing.T) {
+	pollInterval := campaignPollInterval
+	campaignPollInterval = time.Millisecond
+	defer func() { campaignPollInterval = pollInterval }()
+
+	convey.Convey("campaign succeeds when all nodes report the new version", t, func() {
+		patches := patchCampaignNodes(nil, testCampaignVersion)
+		defer patches.Reset()
+		status := newTestCampaign([]uint64{50, 100}, 0).run(newFakeCampaignCtx())
+		convey.So(status.Phase, convey.ShouldEqual, taskschedule.Succeed)
+		convey.So(status.Progress, convey.ShouldEqual, common.ProgressMax)
+	})
+
+	convey.Convey("campaign halts when failure ratio exceeds the threshold", t, func() {
+		patches := patchCampaignNodes(map[string]bool{"node10": true, "node20": true}, testCampaignVersion)
+		defer patches.Reset()
+		status := newTestCampaign([]uint64{20, 100}, 10).run(newFakeCampaignCtx())
+		convey.So(status.Phase, convey.ShouldEqual, taskschedule.Failed)
+		convey.So(status.Reason, convey.ShouldEqual, campaignReasonHalted)
+		convey.So(status.Data["failed"], convey.ShouldEqual, 2)
+		convey.So(status.Data["currentWave"], convey.ShouldEqual, 1)
+	})
+
+	convey.Convey("campaign partially fails when failure ratio is under the threshold", t, func() {
+		patches := patchCampaignNodes(map[string]bool{"node10": true}, testCampaignVersion)
+		defer patches.Reset()
+		status := newTestCampaign([]uint64{20, 100}, 50).run(newFakeCampaignCtx())
+		convey.So(status.Phase, convey.ShouldEqual, taskschedule.PartiallyFailed)
+	})
+
+	convey.Convey("node not reporting the new version fails when the wave times out", t, func() {
+		patches := patchCampaignNodes(nil, "6.0.0")
+		defer patches.Reset()
+		c := newTestCampaign([]uint64{100}, maxPercent)
+		c.req.WaveTimeout = 0
+		status := c.run(newFakeCampaignCtx())
+		convey.So(status.Phase, convey.ShouldEqual, taskschedule.PartiallyFailed)
+		convey.So(status.Data["failed"], convey.ShouldEqual, 9)
+	})
+}
+
+func TestPauseAndAbortCampaign(t *testing.T) {
+	convey.Convey("paused campaign waits before next wave and is aborted", t, func() {
+		patches := patchCampaignNodes(nil, testCampaignVersion)
+		defer patches.Reset()
+		c := newTestCampaign([]uint64{100}, 0)
+		convey.So(c.resume(), convey.ShouldNotBeNil)
+		convey.So(c.pause(), convey.ShouldBeNil)
+		convey.So(c.pause(), convey.ShouldNotBeNil)
+		ctx := newFakeCampaignCtx()
+		close(ctx.shutdown)
+		status := c.run(ctx)
+		convey.So(status.Phase, convey.ShouldEqual, taskschedule.Failed)
+		convey.So(status.Reason, convey.ShouldEqual, campaignReasonAborted)
+		convey.So(ctx.statuses[0].Message, convey.ShouldEqual, "campaign is paused")
+	})
+
+	convey.Convey("resumed campaign continues", t, func() {
+		patches := patchCampaignNodes(nil, testCampaignVersion)
+		defer patches.Reset()
+		c := newTestCampaign([]uint64{100}, 0)
+		convey.So(c.pause(), convey.ShouldBeNil)
+		convey.So(c.resume(), convey.ShouldBeNil)
+		status := c.run(newFakeCampaignCtx())
+		convey.So(status.Phase, convey.ShouldEqual, taskschedule.Succeed)
+	})
+}
@@ -19,11 +19,11 @@ import (
 	"github.com/smartystreets/goconvey/convey"
 
 	"huawei.com/mindx/common/hwlog"
-	"huawei.com/mindx/common/modulemgr"
 	"huawei.com/mindx/common/modulemgr/model"
 	"huawei.com/mindx/common/test"
 
 	"edge-manager/pkg/config"
+	"edge-manager/pkg/outbox"
 	"edge-manager/pkg/util"
 	"huawei.com/mindxedge/base/common"
 )
@@ -37,8 +37,8 @@ func TestGetCertInfo(t *testing.T) {
 	})
 	defer p2.Reset()
 
-	var p3 = gomonkey.ApplyFunc(modulemgr.SendMessage,
-		func(m *model.Message) error {
+	var p3 = gomonkey.ApplyFunc(outbox.SendToNode,
+		func(m *model.Message, opts outbox.Options) error {
 			return nil
 		})
 	defer p3.Reset()
@@ -128,8 +128,8 @@ func testGetCertInfoErrSendMsg() {
 	err = msg.FillContent(common.SoftwareCertName)
 	convey.So(err, convey.ShouldBeNil)
 
-	var p1 = gomonkey.ApplyFunc(modulemgr.SendMessage,
-		func(m *model.Message) error {
+	var p1 = gomonkey.ApplyFunc(outbox.SendToNode,
+		func(m *model.Message, opts outbox.Options) error {
 			return test.ErrTest
 		})
 	defer p1.Reset()
@@ -18,6 +18,8 @@ import (
 	"huawei.com/mindx/common/checker"
 
 	"huawei.com/mindxedge/base/common"
+
+	"edge-manager/pkg/constants"
 )
 
 // NewDownloadChecker is the struct to init a DownloadChecker
@@ -58,16 +60,43 @@ type downloadInfoChecker struct {
 }
 
 func (d *downloadInfoChecker) init() {
+	// the checksums and site cache are filled by edge-manager for each node
+	d.modelChecker.Checker = checker.GetAndChecker(
+		checker.GetRegChecker("Sha256", "^$", true),
+		checker.GetRegChecker("DeltaSha256", "^$", true),
+		checker.GetRegChecker("SiteCacheIp", "^$", true),
+		checker.GetIntChecker("SiteCachePort", 0, 0, true),
+		checker.GetRegChecker("SiteCacheCertSn", "^$", true),
+		newDownloadSourceChecker(),
+	)
+}
+
+// newDownloadSourceChecker the software is downloaded from the artifact repository or the package url, the delta
+// package is only supported for the artifact
+func newDownloadSourceChecker() *checker.OrChecker {
 	const minPwdLength = 8
 	const maxPwdLength = 20
-	d.modelChecker.Checker = checker.GetAndChecker(
-		checker.GetHttpsUrlChecker("Package", true, false),
-		checker.GetRegChecker("UserName", "^[a-zA-Z0-9]{6,32}$", true),
-		checker.GetListChecker("Password",
-			checker.GetUintChecker("", 0, math.MaxUint8, true),
-			minPwdLength,
-			maxPwdLength,
-			true,
+	return checker.GetOrChecker(
+		checker.GetAndChecker(
+			checker.GetRegChecker("ArtifactId", constants.ArtifactIdRegexpStr, true),
+			checker.GetOrChecker(
+				checker.GetRegChecker("DeltaArtifactId", "^$", true),
+				checker.GetRegChecker("DeltaArtifactId", constants.ArtifactIdRegexpStr, true),
+			),
+			checker.GetRegChecker("Package", "^$", true),
+			checker.GetRegChecker("UserName", "^$", true),
+		),
+		checker.GetAndChecker(
+			checker.GetRegChecker("ArtifactId", "^$", true),
+			checker.GetRegChecker("DeltaArtifactId", "^$", true),
+			checker.GetHttpsUrlChecker("Package", true, false),
+			checker.GetRegChecker("UserName", "^[a-zA-Z0-9]{6,32}$", true),
+			checker.GetListChecker("Password",
+				checker.GetUintChecker("", 0, math.MaxUint8, true),
+				minPwdLength,
+				maxPwdLength,
+				true,
+			),
 		),
 	)
 }
@@ -179,6 +208,11 @@ func (u *progressInfoChecker) init() {
 		checker.GetUintChecker("Progress", 0, maxProgress, true),
 		checker.GetStringChoiceChecker("Res", []string{"failed", "success"}, true),
 		checker.GetRegChecker("Msg", "^[\\S ]{0,512}$", true),
+		// the stage and downloaded size are not reported by the legacy edge
+		checker.GetStringChoiceChecker("Stage",
+			[]string{"", stagePreparing, stageDownloading, stageVerifying, stageDownloaded}, true),
+		checker.GetIntChecker("DownloadedBytes", 0, math.MaxInt64, true),
+		checker.GetIntChecker("Rate", 0, math.MaxInt64, true),
 	)
 }
 
@@ -193,3 +227,81 @@ func (d *progressInfoChecker) Check(data interface{}) checker.CheckResult {
 
 	return checker.NewSuccessResult()
 }
+
+type campaignChecker struct {
+	modelChecker checker.ModelChecker
+}
+
+func newCampaignChecker() *campaignChecker {
+	return &campaignChecker{}
+}
+
+func (c *campaignChecker) init() {
+	const (
+		maxWaveNum     = 10
+		maxPercent     = 100
+		minWaveTimeout = 60
+		maxWaveTimeout = 24 * 60 * 60
+	)
+	c.modelChecker.Checker = checker.GetAndChecker(
+		checker.GetUniqueListChecker("NodeGroupIDs",
+			checker.GetUintChecker("", 1, math.MaxUint32, true), 1, common.MaxNodeGroup, true),
+		checker.GetStringChoiceChecker("SoftwareName", []string{common.MEFEdge}, true),
+		checker.GetRegChecker("Version", `^[a-zA-Z0-9]([-_.a-zA-Z0-9]{0,62}[a-zA-Z0-9])?$`, true),
+		GetDownloadInfoChecker("DownloadInfo", true),
+		checker.GetListChecker("WavePercents",
+			checker.GetUintChecker("", 1, maxPercent, true), 1, maxWaveNum, true),
+		checker.GetUintChecker("FailureThreshold", 0, maxPercent, true),
+		checker.GetUintChecker("WaveTimeout", minWaveTimeout, maxWaveTimeout, true),
+	)
+}
+
+// Check [method] check main function, the wave percents must increase and end with 100
+func (c *campaignChecker) Check(data UpgradeCampaignReq) checker.CheckResult {
+	c.init()
+
+	checkResult := c.modelChecker.Check(data)
+	if !checkResult.Result {
+		return checker.NewFailedResult(fmt.Sprintf("upgrade campaign check failed: %s", checkResult.Reason))
+	}
+	const lastPercent = 100
+	for i := 1; i < len(data.WavePercents); i++ {
+		if data.WavePercents[i] <= data.WavePercents[i-1] {
+			return checker.NewFailedResult("upgrade campaign check failed: wave percents should be increasing")
+		}
+	}
+	if data.WavePercents[len(data.WavePercents)-1] != lastPercent {
+		return checker.NewFailedResult("upgrade campaign check failed: percent of the last wave should be 100")
+	}
+
+	return checker.NewSuccessResult()
+}
+
+type preflightChecker struct {
+	modelChecker checker.ModelChecker
+}
+
+func newPreflightChecker() *preflightChecker {
+	return &preflightChecker{}
+}
+
+func (p *preflightChecker) init() {
+	p.modelChecker.Checker = checker.GetAndChecker(
+		checker.GetUniqueListChecker("NodeGroupIDs",
+			checker.GetUintChecker("", 1, math.MaxUint32, true), 1, common.MaxNodeGroup, true),
+		checker.GetStringChoiceChecker("SoftwareName", []string{common.MEFEdge}, true),
+		checker.GetRegChecker("Version", `^[a-zA-Z0-9]([-_.a-zA-Z0-9]{0,62}[a-zA-Z0-9])?$`, true),
+		checker.GetRegChecker("InnerVersion", `^([0-9.]{1,16})?$`, true),
+	)
+}
+
+// Check [method] check main function
+func (p *preflightChecker) Check(data PreflightReq) checker.CheckResult {
+	p.init()
+
+	checkResult := p.modelChecker.Check(data)
+	if !checkResult.Result {
+		return checker.NewFailedResult(fmt.Sprintf("upgrade preflight check failed: %s", checkResult.Reason))
+	}
+	return checker.NewSuccessResult()
+}
@@ -15,16 +15,22 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 
 	"huawei.com/mindx/common/hwlog"
 	"huawei.com/mindx/common/modulemgr"
 	"huawei.com/mindx/common/modulemgr/model"
 
+	"edge-manager/pkg/outbox"
 	"edge-manager/pkg/types"
 
 	"huawei.com/mindxedge/base/common"
+	"huawei.com/mindxedge/base/common/requests"
 )
 
+// edgeMsgTtl messages to edge are mostly answers of user operations, they are meaningless after a long time
+const edgeMsgTtl = 10 * time.Minute
+
 func sendMessageToEdge(msg *model.Message, content string) error {
 	respMsg, err := model.NewMessage()
 	if err != nil {
@@ -39,7 +45,7 @@ func sendMessageToEdge(msg *model.Message, content string) error {
 	}
 	respMsg.SetRouter(common.NodeMsgManagerName, common.CloudHubName, common.OptPost, msg.GetResource())
 
-	if err = modulemgr.SendMessage(respMsg); err != nil {
+	if err = outbox.SendToNode(respMsg, outbox.Options{Priority: outbox.PriorityNormal, Ttl: edgeMsgTtl}); err != nil {
 		hwlog.RunLog.Errorf("edge msg manager send message failed, error: %v", err)
 		return fmt.Errorf("edge msg manager send message failed, error: %v", err)
 	}
@@ -91,3 +97,30 @@ func getNodeSoftwareInfo(serialNumber string) ([]types.SoftwareInfo, error) {
 
 	return nodeSoftwareInfo.SoftwareInfo, nil
 }
+
+func getNodeSnsByGroup(nodeGroupID uint64) ([]string, error) {
+	router := common.Router{
+		Source:      common.NodeMsgManagerName,
+		Destination: common.NodeManagerName,
+		Option:      common.Get,
+		Resource:    common.GetSnsByGroup,
+	}
+	req, err := json.Marshal(requests.GetSnsReq{GroupId: nodeGroupID})
+	if err != nil {
+		return nil, errors.New("marshal request error")
+	}
+	resp := common.SendSyncMessageByRestful(string(req), &router, common.ResponseTimeout)
+	if resp.Status != common.Success {
+		return nil, fmt.Errorf("get node sns failed, status: %s, msg: %s", resp.Status, resp.Msg)
+	}
+
+	data, err := json.Marshal(resp.Data)
+	if err != nil {
+		return nil, errors.New("marshal internal response error")
+	}
+	var sns []string
+	if err = json.Unmarshal(data, &sns); err != nil {
+		return nil, errors.New("unmarshal internal response error")
+	}
+	return sns, nil
+}
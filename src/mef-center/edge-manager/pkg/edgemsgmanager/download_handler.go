@@ -19,6 +19,7 @@ import (
 	"huawei.com/mindx/common/modulemgr/model"
 	"huawei.com/mindx/common/utils"
 
+	"edge-manager/pkg/artifactmanager"
 	"edge-manager/pkg/types"
 
 	"huawei.com/mindxedge/base/common"
@@ -40,6 +41,10 @@ func downloadSoftware(msg *model.Message) common.RespMsg {
 		hwlog.RunLog.Errorf("check software download para failed: %s", checkResult.Reason)
 		return common.RespMsg{Status: common.ErrorParamInvalid, Msg: checkResult.Reason, Data: nil}
 	}
+	if err := checkArtifact(req.DownloadInfo); err != nil {
+		hwlog.RunLog.Errorf("check software artifact failed: %v", err)
+		return common.RespMsg{Status: common.ErrorParamInvalid, Msg: err.Error(), Data: nil}
+	}
 	resp, err := model.NewMessage()
 	if err != nil {
 		hwlog.RunLog.Error("create message failed")
@@ -60,16 +65,44 @@ func downloadSoftware(msg *model.Message) common.RespMsg {
 	}
 }
 
+// checkArtifact checks the artifacts referred by the download info are committed software packages
+func checkArtifact(info DownloadInfo) error {
+	for _, artifactId := range []string{info.ArtifactId, info.DeltaArtifactId} {
+		if artifactId == "" {
+			continue
+		}
+		if _, err := artifactmanager.GetReadyArtifact(artifactId, artifactmanager.TypeSoftware); err != nil {
+			return fmt.Errorf("artifact [%s] is unavailable: %v", artifactId, err)
+		}
+	}
+	return nil
+}
+
 func sendDownloadInfo(req SoftwareDownloadInfo, msg *model.Message) types.BatchResp {
 	var batchResp types.BatchResp
 	failedMap := make(map[string]string)
 	batchResp.FailedInfos = failedMap
+	siteCaches, err := prepareArtifactDownload(&req)
+	if err != nil {
+		hwlog.RunLog.Errorf("prepare artifact download failed: %v", err)
+		for _, sn := range req.SerialNumbers {
+			failedMap[sn] = err.Error()
+		}
+		return batchResp
+	}
 	for _, sn := range req.SerialNumbers {
-		if err := nodesProgress.Set(sn, types.ProgressInfo{}, neverOverdue); err != nil {
+		if err = nodesProgress.Set(sn, types.ProgressInfo{}, neverOverdue); err != nil {
 			hwlog.RunLog.Errorf("set software download progress for %s failed: %v", sn, err)
 			failedMap[sn] = fmt.Sprintf("set software download progress failed: %v", err)
 			continue
 		}
+		if req.DownloadInfo.ArtifactId != "" {
+			if err = msg.FillContent(newNodeDownloadInfo(req, sn, siteCaches)); err != nil {
+				hwlog.RunLog.Errorf("fill software download info for %s failed: %v", sn, err)
+				failedMap[sn] = "fill software download info failed"
+				continue
+			}
+		}
 		msg.SetNodeId(sn)
 		hwlog.RunLog.Errorf("start to send msg to %v", msg.Router.Destination)
 		rsp, err := modulemgr.SendSyncMessage(msg, common.ResponseTimeout)
@@ -89,8 +122,92 @@ func sendDownloadInfo(req SoftwareDownloadInfo, msg *model.Message) types.BatchR
 			failedMap[sn] = "parse mef edge process software download info failed"
 			continue
 		}
+		recordProgress(sn, types.ProgressInfo{Stage: stageDownloadRequested})
 		batchResp.SuccessIDs = append(batchResp.SuccessIDs, sn)
 	}
 	logmgmt.BatchOperationLog("send software download msg to edge", batchResp.SuccessIDs)
 	return batchResp
 }
+
+// prepareArtifactDownload fills the checksum of the artifact, and gets the site caches of the nodes, the nodes
+// without site cache download the artifact from edge-manager
+func prepareArtifactDownload(req *SoftwareDownloadInfo) (map[string]artifactmanager.SiteCacheInfo, error) {
+	if req.DownloadInfo.ArtifactId == "" {
+		return nil, nil
+	}
+	artifact, err := artifactmanager.GetReadyArtifact(req.DownloadInfo.ArtifactId, artifactmanager.TypeSoftware)
+	if err != nil {
+		return nil, fmt.Errorf("artifact [%s] is unavailable: %v", req.DownloadInfo.ArtifactId, err)
+	}
+	req.DownloadInfo.Sha256 = artifact.Sha256
+	if deltaId := req.DownloadInfo.DeltaArtifactId; deltaId != "" {
+		delta, err := artifactmanager.GetReadyArtifact(deltaId, artifactmanager.TypeSoftware)
+		if err != nil {
+			return nil, fmt.Errorf("artifact [%s] is unavailable: %v", deltaId, err)
+		}
+		req.DownloadInfo.DeltaSha256 = delta.Sha256
+	}
+	return getSiteCachesOfNodes(req.SerialNumbers), nil
+}
+
+// getSiteCachesOfNodes the site cache of a node is the one of its node group, the first one is used if the node
+// is in several groups with site caches. The site cache downloads the artifact from edge-manager itself, and it is
+// skipped if its cert is unknown, since the nodes pin the cert of the site cache
+func getSiteCachesOfNodes(sns []string) map[string]artifactmanager.SiteCacheInfo {
+	siteCaches, err := artifactmanager.GetSiteCaches()
+	if err != nil {
+		hwlog.RunLog.Warnf("get site caches failed, the artifact is downloaded from edge-manager: %v", err)
+		return nil
+	}
+	wanted := make(map[string]struct{}, len(sns))
+	for _, sn := range sns {
+		wanted[sn] = struct{}{}
+	}
+	nodeCaches := make(map[string]artifactmanager.SiteCacheInfo)
+	for _, siteCache := range siteCaches {
+		groupSns, err := getNodeSnsByGroup(siteCache.GroupID)
+		if err != nil {
+			hwlog.RunLog.Warnf("get nodes of group [%d] failed, its site cache is skipped: %v",
+				siteCache.GroupID, err)
+			continue
+		}
+		if !containsSn(groupSns, siteCache.SerialNumber) {
+			hwlog.RunLog.Warnf("site cache node is not in group [%d], it is skipped", siteCache.GroupID)
+			continue
+		}
+		if siteCache.CertSn == "" {
+			hwlog.RunLog.Warnf("cert of site cache node of group [%d] is unknown, it is skipped", siteCache.GroupID)
+			continue
+		}
+		for _, sn := range groupSns {
+			if _, ok := wanted[sn]; !ok || sn == siteCache.SerialNumber {
+				continue
+			}
+			if _, ok := nodeCaches[sn]; !ok {
+				nodeCaches[sn] = siteCache
+			}
+		}
+	}
+	return nodeCaches
+}
+
+func containsSn(sns []string, sn string) bool {
+	for _, item := range sns {
+		if item == sn {
+			return true
+		}
+	}
+	return false
+}
+
+func newNodeDownloadInfo(req SoftwareDownloadInfo, sn string,
+	siteCaches map[string]artifactmanager.SiteCacheInfo) SoftwareDownloadInfo {
+	info := req
+	info.SerialNumbers = []string{sn}
+	if siteCache, ok := siteCaches[sn]; ok {
+		info.DownloadInfo.SiteCacheIp = siteCache.Ip
+		info.DownloadInfo.SiteCachePort = siteCache.Port
+		info.DownloadInfo.SiteCacheCertSn = siteCache.CertSn
+	}
+	return info
+}
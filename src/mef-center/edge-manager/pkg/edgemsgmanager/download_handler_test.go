@@ -26,6 +26,8 @@ import (
 	"huawei.com/mindx/common/utils"
 
 	"huawei.com/mindxedge/base/common"
+
+	"edge-manager/pkg/artifactmanager"
 )
 
 func TestDownloadInfo(t *testing.T) {
@@ -54,6 +56,9 @@ func TestDownloadInfo(t *testing.T) {
 	convey.Convey("test download software should be failed, invalid Password", t, testDownloadSfwErrPwd)
 	convey.Convey("test download software should be failed, new msg error", t, testDownloadErrNewMsg)
 	convey.Convey("test download software should be failed, send sync msg error", t, testDownloadErrSendSyncMsg)
+	convey.Convey("test download software from artifact repository", t, testDownloadSfwArtifact)
+	convey.Convey("test download software from site cache", t, testDownloadSfwSiteCache)
+	convey.Convey("test download software with delta artifact", t, testDownloadSfwDelta)
 }
 
 func createDownloadSfwBaseData() SoftwareDownloadInfo {
@@ -270,3 +275,132 @@ func testDownloadErrSendSyncMsg() {
 	resp := downloadSoftware(msg)
 	convey.So(resp.Status, convey.ShouldEqual, common.ErrorSendMsgToNode)
 }
+
+func createDownloadSfwMsg(req SoftwareDownloadInfo) *model.Message {
+	msg, err := model.NewMessage()
+	convey.So(err, convey.ShouldBeNil)
+	convey.So(msg.FillContent(&req), convey.ShouldBeNil)
+	return msg
+}
+
+func testDownloadSfwArtifact() {
+	const artifactId = "0123456789abcdef0123456789abcdef"
+	req := createDownloadSfwBaseData()
+	req.DownloadInfo = DownloadInfo{ArtifactId: artifactId}
+
+	p := gomonkey.ApplyFuncReturn(artifactmanager.GetReadyArtifact, &artifactmanager.ArtifactInfo{ID: artifactId}, nil).
+		ApplyFuncReturn(artifactmanager.GetSiteCaches, nil, nil)
+	resp := downloadSoftware(createDownloadSfwMsg(req))
+	convey.So(resp.Status, convey.ShouldEqual, common.Success)
+	p.Reset()
+
+	p = gomonkey.ApplyFuncReturn(artifactmanager.GetReadyArtifact, nil, test.ErrTest)
+	defer p.Reset()
+	resp = downloadSoftware(createDownloadSfwMsg(req))
+	convey.So(resp.Status, convey.ShouldEqual, common.ErrorParamInvalid)
+
+	req.DownloadInfo.ArtifactId = "invalid"
+	resp = downloadSoftware(createDownloadSfwMsg(req))
+	convey.So(resp.Status, convey.ShouldEqual, common.ErrorParamInvalid)
+
+	req.DownloadInfo = createDownloadSfwBaseData().DownloadInfo
+	req.DownloadInfo.ArtifactId = artifactId
+	resp = downloadSoftware(createDownloadSfwMsg(req))
+	convey.So(resp.Status, convey.ShouldEqual, common.ErrorParamInvalid)
+}
+
+func testDownloadSfwSiteCache() {
+	const (
+		artifactId = "0123456789abcdef0123456789abcdef"
+		checksum   = "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+		cacheSn    = "node10"
+		peerSn     = "node11"
+		otherSn    = "node20"
+		offlineSn  = "node30"
+		cacheCert  = "1A2B"
+	)
+	siteCaches := []artifactmanager.SiteCacheInfo{
+		{GroupID: 1, SerialNumber: cacheSn, Ip: "192.168.1.10", Port: 8443, CertSn: cacheCert},
+		{GroupID: 2, SerialNumber: "node99", Ip: "192.168.2.10", Port: 8443, CertSn: cacheCert},
+		{GroupID: 3, SerialNumber: "node31", Ip: "192.168.3.10", Port: 8443},
+	}
+	groupSns := map[uint64][]string{1: {cacheSn, peerSn}, 2: {otherSn}, 3: {"node31", offlineSn}}
+	p := gomonkey.ApplyFuncReturn(artifactmanager.GetReadyArtifact,
+		&artifactmanager.ArtifactInfo{ID: artifactId, Sha256: checksum}, nil).
+		ApplyFuncReturn(artifactmanager.GetSiteCaches, siteCaches, nil).
+		ApplyFunc(getNodeSnsByGroup, func(groupID uint64) ([]string, error) {
+			return groupSns[groupID], nil
+		})
+	defer p.Reset()
+
+	req := createDownloadSfwBaseData()
+	req.SerialNumbers = []string{cacheSn, peerSn, otherSn, offlineSn}
+	req.DownloadInfo = DownloadInfo{ArtifactId: artifactId}
+	nodeCaches, err := prepareArtifactDownload(&req)
+	convey.So(err, convey.ShouldBeNil)
+	convey.So(req.DownloadInfo.Sha256, convey.ShouldEqual, checksum)
+
+	info := newNodeDownloadInfo(req, peerSn, nodeCaches)
+	convey.So(info.SerialNumbers, convey.ShouldResemble, []string{peerSn})
+	convey.So(info.DownloadInfo.SiteCacheIp, convey.ShouldEqual, "192.168.1.10")
+	convey.So(info.DownloadInfo.SiteCachePort, convey.ShouldEqual, 8443)
+	convey.So(info.DownloadInfo.SiteCacheCertSn, convey.ShouldEqual, cacheCert)
+	// the site cache downloads from edge-manager, the site cache node out of its group and the one whose cert is
+	// unknown are skipped
+	for _, sn := range []string{cacheSn, otherSn, offlineSn} {
+		info = newNodeDownloadInfo(req, sn, nodeCaches)
+		convey.So(info.DownloadInfo.SiteCacheIp, convey.ShouldBeEmpty)
+		convey.So(info.DownloadInfo.Sha256, convey.ShouldEqual, checksum)
+	}
+
+	req.DownloadInfo = DownloadInfo{ArtifactId: artifactId}
+	resp := downloadSoftware(createDownloadSfwMsg(req))
+	convey.So(resp.Status, convey.ShouldEqual, common.Success)
+
+	req.DownloadInfo.SiteCacheIp = "192.168.1.10"
+	resp = downloadSoftware(createDownloadSfwMsg(req))
+	convey.So(resp.Status, convey.ShouldEqual, common.ErrorParamInvalid)
+
+	req.DownloadInfo = DownloadInfo{ArtifactId: artifactId, SiteCacheCertSn: cacheCert}
+	resp = downloadSoftware(createDownloadSfwMsg(req))
+	convey.So(resp.Status, convey.ShouldEqual, common.ErrorParamInvalid)
+}
+
+func testDownloadSfwDelta() {
+	const (
+		artifactId  = "0123456789abcdef0123456789abcdef"
+		deltaId     = "fedcba9876543210fedcba9876543210"
+		checksum    = "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+		deltaSha256 = "fedcba9876543210fedcba9876543210fedcba9876543210fedcba9876543210"
+	)
+	p := gomonkey.ApplyFunc(artifactmanager.GetReadyArtifact,
+		func(id string, _ string) (*artifactmanager.ArtifactInfo, error) {
+			if id == deltaId {
+				return &artifactmanager.ArtifactInfo{ID: id, Sha256: deltaSha256}, nil
+			}
+			return &artifactmanager.ArtifactInfo{ID: id, Sha256: checksum}, nil
+		}).
+		ApplyFuncReturn(artifactmanager.GetSiteCaches, nil, nil)
+	defer p.Reset()
+
+	req := createDownloadSfwBaseData()
+	req.DownloadInfo = DownloadInfo{ArtifactId: artifactId, DeltaArtifactId: deltaId}
+	_, err := prepareArtifactDownload(&req)
+	convey.So(err, convey.ShouldBeNil)
+	convey.So(req.DownloadInfo.Sha256, convey.ShouldEqual, checksum)
+	convey.So(req.DownloadInfo.DeltaSha256, convey.ShouldEqual, deltaSha256)
+
+	req.DownloadInfo = DownloadInfo{ArtifactId: artifactId, DeltaArtifactId: deltaId}
+	resp := downloadSoftware(createDownloadSfwMsg(req))
+	convey.So(resp.Status, convey.ShouldEqual, common.Success)
+
+	req.DownloadInfo.DeltaSha256 = deltaSha256
+	resp = downloadSoftware(createDownloadSfwMsg(req))
+	convey.So(resp.Status, convey.ShouldEqual, common.ErrorParamInvalid)
+
+	// the delta package is only supported for the artifact
+	req.DownloadInfo = createDownloadSfwBaseData().DownloadInfo
+	req.DownloadInfo.DeltaArtifactId = deltaId
+	resp = downloadSoftware(createDownloadSfwMsg(req))
+	convey.So(resp.Status, convey.ShouldEqual, common.ErrorParamInvalid)
+}
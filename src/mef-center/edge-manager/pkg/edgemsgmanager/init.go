@@ -46,6 +46,13 @@ func (nm *NodeMsgDealer) Name() string {
 
 // Enable indicates whether this module is enabled
 func (nm *NodeMsgDealer) Enable() bool {
+	if nm.enable {
+		if err := initProgressEventTable(); err != nil {
+			hwlog.RunLog.Errorf("module (%s) init progress event database table failed, cannot enable", nm.Name())
+			return !nm.enable
+		}
+		initCampaignTask()
+	}
 	return nm.enable
 }
 
@@ -152,18 +159,29 @@ func methodWithOpLogSelect(funcMap map[string]handlerFunc, req *model.Message) (
 
 var (
 	edgeSoftwareRootPath = "/edgemanager/v1/software/edge"
+	campaignPath         = filepath.Join(edgeSoftwareRootPath, "/campaign")
+	progressHistoryPath  = filepath.Join(edgeSoftwareRootPath, "/download-progress/history")
 )
 
 var handlerFuncMap = map[string]handlerFunc{
 	common.Combine(http.MethodPost, filepath.Join(edgeSoftwareRootPath, "/download")):         downloadSoftware,
 	common.Combine(http.MethodPost, filepath.Join(edgeSoftwareRootPath, "/upgrade")):          upgradeEdgeSoftware,
+	common.Combine(http.MethodPost, filepath.Join(edgeSoftwareRootPath, "/rollback")):         rollbackEdgeSoftware,
+	common.Combine(http.MethodPost, filepath.Join(edgeSoftwareRootPath, "/preflight")):        checkUpgradePreflight,
 	common.Combine(http.MethodGet, filepath.Join(edgeSoftwareRootPath, "/version-info")):      queryEdgeSoftwareVersion,
 	common.Combine(http.MethodGet, filepath.Join(edgeSoftwareRootPath, "/download-progress")): queryEdgeDownloadProgress,
+	common.Combine(http.MethodGet, progressHistoryPath):                                       queryEdgeProgressHistory,
+	common.Combine(http.MethodPost, campaignPath):                                             createUpgradeCampaign,
+	common.Combine(http.MethodGet, campaignPath):                                              queryUpgradeCampaign,
+	common.Combine(http.MethodPost, filepath.Join(campaignPath, "/pause")):                    pauseUpgradeCampaign,
+	common.Combine(http.MethodPost, filepath.Join(campaignPath, "/resume")):                   resumeUpgradeCampaign,
+	common.Combine(http.MethodPost, filepath.Join(campaignPath, "/abort")):                    abortUpgradeCampaign,
 
 	common.Combine(common.OptGet, common.ResConfig):       GetConfigInfo,
 	common.Combine(common.OptGet, common.ResDownLoadCert): GetCertInfo,
 }
 
 var handlerWithOpLogFuncMap = map[string]handlerFunc{
-	common.Combine(common.OptReport, common.ResDownloadProgress): UpdateEdgeDownloadProgress,
+	common.Combine(common.OptReport, common.ResDownloadProgress):    UpdateEdgeDownloadProgress,
+	common.Combine(common.OptReport, common.ResEdgePreflightResult): handleEdgePreflightResult,
 }
@@ -14,10 +14,17 @@ package edgemsgmanager
 import (
 	"testing"
 
+	"github.com/agiledragon/gomonkey/v2"
+
+	"huawei.com/mindx/common/database"
 	"huawei.com/mindx/common/test"
 )
 
 func TestMain(m *testing.M) {
-	tcBase := &test.TcBase{}
-	test.RunWithPatches(tcBase, m, nil)
+	tables := make([]interface{}, 0)
+	tcBaseWithDb := &test.TcBaseWithDb{
+		Tables: append(tables, &progressEventRecord{}),
+	}
+	patches := gomonkey.ApplyFunc(database.GetDb, test.MockGetDb)
+	test.RunWithPatches(tcBaseWithDb, m, patches)
 }
@@ -0,0 +1,320 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package edgemsgmanager check the nodes of node groups before upgrading edge software
+package edgemsgmanager
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+
+	"huawei.com/mindx/common/hwlog"
+	"huawei.com/mindx/common/modulemgr"
+	"huawei.com/mindx/common/modulemgr/model"
+
+	"edge-manager/pkg/nodeprotocol"
+
+	"huawei.com/mindxedge/base/common"
+	"huawei.com/mindxedge/base/common/logmgmt"
+)
+
+const (
+	maxPreflightChecks   = 4
+	preflightTimeout     = 60 * time.Second
+	preflightCheckIdLen  = 16
+	maxPreflightItems    = 16
+	maxPreflightItemSize = 256
+	maxPreflightSenders  = 16
+)
+
+var preflightCheckIdReg = regexp.MustCompile(`^[a-f0-9]{32}$`)
+
+// edgePreflightRequest request sent to edge for running the checks of preupgrade
+type edgePreflightRequest struct {
+	CheckId      string `json:"checkId"`
+	SoftwareName string `json:"softwareName"`
+	Version      string `json:"version"`
+	InnerVersion string `json:"innerVersion,omitempty"`
+}
+
+// edgePreflightReport results of preflight reported by edge
+type edgePreflightReport struct {
+	CheckId        string          `json:"checkId"`
+	CurrentVersion string          `json:"currentVersion"`
+	Items          []PreflightItem `json:"items"`
+}
+
+type preflightCheck struct {
+	results map[string]*NodePreflight
+	pending int
+	done    chan struct{}
+}
+
+type preflightMgr struct {
+	lock   sync.Mutex
+	checks map[string]*preflightCheck
+}
+
+var preflights = &preflightMgr{checks: make(map[string]*preflightCheck)}
+
+func newPreflightCheckId() (string, error) {
+	buf := make([]byte, preflightCheckIdLen)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generate check id failed: %v", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func (m *preflightMgr) add(id string, sns []string) (*preflightCheck, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if len(m.checks) >= maxPreflightChecks {
+		return nil, fmt.Errorf("the count of running preflight checks reaches the limit %d", maxPreflightChecks)
+	}
+	c := &preflightCheck{results: make(map[string]*NodePreflight, len(sns)), pending: len(sns),
+		done: make(chan struct{})}
+	for _, sn := range sns {
+		c.results[sn] = nil
+	}
+	if c.pending == 0 {
+		close(c.done)
+	}
+	m.checks[id] = c
+	return c, nil
+}
+
+func (m *preflightMgr) remove(id string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	delete(m.checks, id)
+}
+
+// setResult sets the result of node, the reports of the nodes not checked or answered before are dropped
+func (m *preflightMgr) setResult(id, sn string, result *NodePreflight) bool {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	c, ok := m.checks[id]
+	if !ok {
+		return false
+	}
+	if res, ok := c.results[sn]; !ok || res != nil {
+		return false
+	}
+	c.results[sn] = result
+	c.pending--
+	if c.pending == 0 {
+		close(c.done)
+	}
+	return true
+}
+
+// collect aggregates the results per node group, the nodes not answered are regarded as no response
+func (m *preflightMgr) collect(id string, groups []GroupPreflight, groupSns map[uint64][]string) PreflightResult {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	c := m.checks[id]
+	result := PreflightResult{CheckId: id, Passed: true, Groups: groups}
+	for i := range result.Groups {
+		group := &result.Groups[i]
+		if group.Error != "" {
+			result.Passed = false
+			continue
+		}
+		sns := groupSns[group.NodeGroupID]
+		group.Total = len(sns)
+		group.Nodes = make([]NodePreflight, 0, len(sns))
+		for _, sn := range sns {
+			res := c.results[sn]
+			switch {
+			case res == nil:
+				res = &NodePreflight{SerialNumber: sn, Items: []PreflightItem{},
+					Error: "wait for the preflight results timeout"}
+				group.NoResponse++
+			case res.Error != "":
+				group.NoResponse++
+			case res.Passed:
+				group.Passed++
+			default:
+				group.Failed++
+			}
+			group.Nodes = append(group.Nodes, *res)
+		}
+		if group.Passed != group.Total {
+			result.Passed = false
+		}
+	}
+	return result
+}
+
+// checkUpgradePreflight makes the nodes of node groups run the checks of preupgrade without modifying anything,
+// and waits for the results aggregated per node group, it is used before an upgrade campaign starts
+func checkUpgradePreflight(msg *model.Message) common.RespMsg {
+	hwlog.RunLog.Info("start check upgrade preflight")
+	var req PreflightReq
+	if err := msg.ParseContent(&req); err != nil {
+		hwlog.RunLog.Errorf("parse content failed: %v", err)
+		return common.RespMsg{Status: common.ErrorParamConvert, Msg: "parse content failed", Data: nil}
+	}
+	if checkResult := newPreflightChecker().Check(req); !checkResult.Result {
+		hwlog.RunLog.Errorf("check upgrade preflight para failed: %s", checkResult.Reason)
+		return common.RespMsg{Status: common.ErrorParamInvalid, Msg: checkResult.Reason, Data: nil}
+	}
+
+	groups := make([]GroupPreflight, 0, len(req.NodeGroupIDs))
+	groupSns := make(map[uint64][]string, len(req.NodeGroupIDs))
+	var sns []string
+	checked := make(map[string]struct{})
+	for _, groupID := range req.NodeGroupIDs {
+		group := GroupPreflight{NodeGroupID: groupID, Nodes: []NodePreflight{}}
+		nodeSns, err := getNodeSnsByGroup(groupID)
+		if err != nil {
+			hwlog.RunLog.Errorf("get nodes of node group [%d] failed: %v", groupID, err)
+			group.Error = "get nodes of node group failed"
+		}
+		groups = append(groups, group)
+		groupSns[groupID] = nodeSns
+		for _, sn := range nodeSns {
+			if _, ok := checked[sn]; !ok {
+				checked[sn] = struct{}{}
+				sns = append(sns, sn)
+			}
+		}
+	}
+
+	checkId, err := newPreflightCheckId()
+	if err != nil {
+		hwlog.RunLog.Errorf("check upgrade preflight failed: %v", err)
+		return common.RespMsg{Status: common.ErrorUpgradePreflight, Msg: "", Data: nil}
+	}
+	check, err := preflights.add(checkId, sns)
+	if err != nil {
+		hwlog.RunLog.Errorf("check upgrade preflight failed: %v", err)
+		return common.RespMsg{Status: common.ErrorUpgradePreflight, Msg: err.Error(), Data: nil}
+	}
+	defer preflights.remove(checkId)
+
+	edgeReq := edgePreflightRequest{CheckId: checkId, SoftwareName: req.SoftwareName, Version: req.Version,
+		InnerVersion: req.InnerVersion}
+	// the deadline covers both sending the requests and waiting for the results, it starts from the first send
+	timer := time.NewTimer(preflightTimeout)
+	defer timer.Stop()
+	stop := make(chan struct{})
+	defer close(stop)
+	go sendPreflights(checkId, sns, edgeReq, stop)
+	select {
+	case <-check.done:
+	case <-timer.C:
+		hwlog.RunLog.Warnf("wait for the results of preflight [%s] timeout", checkId)
+	}
+	result := preflights.collect(checkId, groups, groupSns)
+	logmgmt.BatchOperationLog("check upgrade preflight of node groups", []interface{}{req.NodeGroupIDs})
+	hwlog.RunLog.Infof("check upgrade preflight [%s] of %d nodes finished, passed: %v", checkId, len(sns),
+		result.Passed)
+	return common.RespMsg{Status: common.Success, Msg: "", Data: result}
+}
+
+// sendPreflights sends the preflight request to the nodes in parallel with at most maxPreflightSenders requests
+// on the way, the nodes not sent yet are skipped once stop is closed
+func sendPreflights(checkId string, sns []string, req edgePreflightRequest, stop <-chan struct{}) {
+	senders := make(chan struct{}, maxPreflightSenders)
+	for _, sn := range sns {
+		if err := nodeprotocol.CheckSupport(sn, nodeprotocol.FeatureUpgradePreflight); err != nil {
+			hwlog.RunLog.Warnf("skip preflight of node [%s]: %v", sn, err)
+			preflights.setResult(checkId, sn, &NodePreflight{SerialNumber: sn, Items: []PreflightItem{},
+				Error: err.Error()})
+			continue
+		}
+		select {
+		case senders <- struct{}{}:
+		case <-stop:
+			return
+		}
+		go func(sn string) {
+			defer func() { <-senders }()
+			if err := sendPreflightToEdge(sn, req); err != nil {
+				hwlog.RunLog.Errorf("send preflight request to node [%s] failed: %v", sn, err)
+				preflights.setResult(checkId, sn, &NodePreflight{SerialNumber: sn, Items: []PreflightItem{},
+					Error: "send request to node failed, the node may be disconnected"})
+			}
+		}(sn)
+	}
+}
+
+// handleEdgePreflightResult passes the results reported by edge to the waiting preflight check
+func handleEdgePreflightResult(msg *model.Message) common.RespMsg {
+	var report edgePreflightReport
+	if err := msg.ParseContent(&report); err != nil {
+		hwlog.RunLog.Errorf("parse preflight results failed: %v", err)
+		return common.RespMsg{Status: common.ErrorParamConvert, Msg: "parse content failed", Data: nil}
+	}
+	sn := msg.GetPeerInfo().Sn
+	if err := checkPreflightReport(report); err != nil {
+		hwlog.RunLog.Errorf("preflight results from node [%s] are invalid: %v", sn, err)
+		return common.RespMsg{Status: common.ErrorParamInvalid, Msg: err.Error(), Data: nil}
+	}
+	result := &NodePreflight{SerialNumber: sn, Passed: true, CurrentVersion: report.CurrentVersion,
+		Items: report.Items}
+	for _, item := range report.Items {
+		result.Passed = result.Passed && item.Passed
+	}
+	if !preflights.setResult(report.CheckId, sn, result) {
+		hwlog.RunLog.Warnf("drop preflight results of check [%s] from node [%s]", report.CheckId, sn)
+	}
+	return common.RespMsg{Status: common.Success, Msg: "", Data: nil}
+}
+
+func checkPreflightReport(report edgePreflightReport) error {
+	if !preflightCheckIdReg.MatchString(report.CheckId) {
+		return errors.New("check id is invalid")
+	}
+	if len(report.Items) == 0 || len(report.Items) > maxPreflightItems {
+		return errors.New("count of check items is invalid")
+	}
+	if len(report.CurrentVersion) > maxPreflightItemSize {
+		return errors.New("current version is too long")
+	}
+	for _, item := range report.Items {
+		if len(item.Name) > maxPreflightItemSize || len(item.Message) > maxPreflightItemSize {
+			return errors.New("check item is too long")
+		}
+	}
+	return nil
+}
+
+// sendPreflightToEdge send preflight request to edge and wait for the request to be sent out
+func sendPreflightToEdge(sn string, req edgePreflightRequest) error {
+	msg, err := model.NewMessage()
+	if err != nil {
+		return fmt.Errorf("create message for %s failed", sn)
+	}
+	msg.SetRouter(common.NodeMsgManagerName, common.CloudHubName, common.OptPost, common.ResEdgePreflight)
+	if err = msg.FillContent(req, true); err != nil {
+		return fmt.Errorf("fill content failed: %v", err)
+	}
+	msg.SetNodeId(sn)
+
+	rsp, err := modulemgr.SendSyncMessage(msg, common.ResponseTimeout)
+	if err != nil {
+		return fmt.Errorf("send msg failed: %v", err)
+	}
+	var respContent string
+	if err = rsp.ParseContent(&respContent); err != nil {
+		return fmt.Errorf("parse resp failed: %v", err)
+	}
+	if respContent != common.OK {
+		return fmt.Errorf("send preflight request to %s failed", sn)
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package edgemsgmanager test for checking the nodes before upgrading edge software
+package edgemsgmanager
+
+import (
+	"testing"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/smartystreets/goconvey/convey"
+
+	"huawei.com/mindx/common/modulemgr/model"
+	"huawei.com/mindx/common/test"
+
+	"huawei.com/mindxedge/base/common"
+
+	"edge-manager/pkg/nodeprotocol"
+)
+
+const (
+	preflightFailedNode  = "node13"
+	preflightOfflineNode = "node20"
+)
+
+func newPreflightMsg(req interface{}) *model.Message {
+	msg, err := model.NewMessage()
+	convey.So(err, convey.ShouldBeNil)
+	convey.So(msg.FillContent(req, true), convey.ShouldBeNil)
+	return msg
+}
+
+func newPreflightReportMsg(sn string, report edgePreflightReport) *model.Message {
+	msg := newPreflightMsg(report)
+	msg.SetPeerInfo(model.MsgPeerInfo{Sn: sn})
+	return msg
+}
+
+// reportPreflight simulates the node which runs the checks and reports the results after receiving the request,
+// it is called on the sending coroutines, so the message is built without convey
+func reportPreflight(sn string, req edgePreflightRequest) error {
+	if sn == preflightOfflineNode {
+		return test.ErrTest
+	}
+	report := edgePreflightReport{CheckId: req.CheckId, CurrentVersion: "5.0.RC1",
+		Items: []PreflightItem{{Name: "version", Passed: true}, {Name: "diskSpace", Passed: true}}}
+	if sn == preflightFailedNode {
+		report.Items[1] = PreflightItem{Name: "diskSpace", Passed: false, Message: "disk space is not enough"}
+	}
+	msg, err := model.NewMessage()
+	if err != nil {
+		return err
+	}
+	if err = msg.FillContent(report, true); err != nil {
+		return err
+	}
+	msg.SetPeerInfo(model.MsgPeerInfo{Sn: sn})
+	go handleEdgePreflightResult(msg)
+	return nil
+}
+
+func TestCheckUpgradePreflight(t *testing.T) {
+	req := PreflightReq{NodeGroupIDs: []uint64{1, 2}, SoftwareName: common.MEFEdge, Version: "5.0.RC2"}
+
+	convey.Convey("preflight should be aggregated per node group", t, func() {
+		p := gomonkey.ApplyFunc(getNodeSnsByGroup, nodeSnsOfTestGroups).
+			ApplyFuncReturn(nodeprotocol.CheckSupport, nil).
+			ApplyFunc(sendPreflightToEdge, reportPreflight)
+		defer p.Reset()
+		resp := checkUpgradePreflight(newPreflightMsg(req))
+		convey.So(resp.Status, convey.ShouldEqual, common.Success)
+		result, ok := resp.Data.(PreflightResult)
+		convey.So(ok, convey.ShouldBeTrue)
+		convey.So(result.Passed, convey.ShouldBeFalse)
+		convey.So(len(result.Groups), convey.ShouldEqual, len(req.NodeGroupIDs))
+		group := result.Groups[0]
+		convey.So(group.Total, convey.ShouldEqual, len(group.Nodes))
+		convey.So([]int{group.Passed, group.Failed, group.NoResponse}, convey.ShouldResemble, []int{4, 1, 0})
+		group = result.Groups[1]
+		convey.So([]int{group.Passed, group.Failed, group.NoResponse}, convey.ShouldResemble, []int{4, 0, 1})
+		convey.So(len(preflights.checks), convey.ShouldEqual, 0)
+	})
+
+	convey.Convey("nodes not supporting preflight should be regarded as no response", t, func() {
+		p := gomonkey.ApplyFunc(getNodeSnsByGroup, nodeSnsOfTestGroups).
+			ApplyFuncReturn(nodeprotocol.CheckSupport, test.ErrTest)
+		defer p.Reset()
+		resp := checkUpgradePreflight(newPreflightMsg(req))
+		convey.So(resp.Status, convey.ShouldEqual, common.Success)
+		result, ok := resp.Data.(PreflightResult)
+		convey.So(ok, convey.ShouldBeTrue)
+		convey.So(result.Groups[0].NoResponse, convey.ShouldEqual, result.Groups[0].Total)
+	})
+
+	convey.Convey("preflight should be failed, invalid param", t, func() {
+		resp := checkUpgradePreflight(&model.Message{Content: []byte("")})
+		convey.So(resp.Status, convey.ShouldEqual, common.ErrorParamConvert)
+		invalidReq := req
+		invalidReq.Version = "-5.0"
+		resp = checkUpgradePreflight(newPreflightMsg(invalidReq))
+		convey.So(resp.Status, convey.ShouldEqual, common.ErrorParamInvalid)
+	})
+}
+
+func TestHandleEdgePreflightResult(t *testing.T) {
+	report := edgePreflightReport{CheckId: "0123456789abcdef0123456789abcdef",
+		Items: []PreflightItem{{Name: "lock", Passed: true}}}
+
+	convey.Convey("results of unknown check should be dropped", t, func() {
+		resp := handleEdgePreflightResult(newPreflightReportMsg("node0", report))
+		convey.So(resp.Status, convey.ShouldEqual, common.Success)
+	})
+
+	convey.Convey("invalid results should be refused", t, func() {
+		invalidReport := report
+		invalidReport.CheckId = "invalid"
+		resp := handleEdgePreflightResult(newPreflightReportMsg("node0", invalidReport))
+		convey.So(resp.Status, convey.ShouldEqual, common.ErrorParamInvalid)
+		invalidReport = report
+		invalidReport.Items = nil
+		resp = handleEdgePreflightResult(newPreflightReportMsg("node0", invalidReport))
+		convey.So(resp.Status, convey.ShouldEqual, common.ErrorParamInvalid)
+	})
+}
@@ -0,0 +1,264 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package edgemsgmanager history and live stream of edge software progress
+package edgemsgmanager
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"huawei.com/mindx/common/hwlog"
+	"huawei.com/mindx/common/modulemgr/model"
+
+	"huawei.com/mindxedge/base/common"
+
+	"edge-manager/pkg/types"
+)
+
+// stages of progress events, the stages of downloading are reported by edge, the others are recorded by center
+// when the instruction is sent to edge
+const (
+	stagePreparing         = "preparing"
+	stageDownloading       = "downloading"
+	stageVerifying         = "verifying"
+	stageDownloaded        = "downloaded"
+	stageDownloadRequested = "downloadRequested"
+	stageUpgradeRequested  = "upgradeRequested"
+)
+
+const (
+	maxProgressSubscribers = 16
+	maxStreamNodes         = 2048
+	subscriberBufferSize   = 256
+	// the downloaded size is reported every few seconds, it is persisted at most once in the interval per node
+	bytesPersistInterval = time.Minute
+	keepAliveInterval    = 15 * time.Second
+	querySerialNumbers   = "serialNumbers"
+	progressEventName    = "progress"
+	keepAliveEventName   = "keepalive"
+)
+
+var snReg = regexp.MustCompile(`^[a-zA-Z0-9]([-_a-zA-Z0-9]{0,62}[a-zA-Z0-9])?$`)
+
+// ProgressEvent a download or upgrade progress event of an edge node
+type ProgressEvent struct {
+	SerialNumber    string `json:"serialNumber"`
+	Stage           string `json:"stage"`
+	Progress        uint64 `json:"progress"`
+	Res             string `json:"res,omitempty"`
+	Msg             string `json:"msg,omitempty"`
+	DownloadedBytes int64  `json:"downloadedBytes,omitempty"`
+	Rate            int64  `json:"rate,omitempty"`
+	CreatedAt       int64  `json:"createdAt"`
+}
+
+type progressSubscriber struct {
+	// sns the nodes watched, all nodes are watched if it is empty
+	sns     map[string]struct{}
+	events  chan ProgressEvent
+	dropped int
+}
+
+type progressHub struct {
+	lock        sync.Mutex
+	subscribers map[*progressSubscriber]struct{}
+	// lastPersisted the time when the downloaded size of node is persisted last time
+	lastPersisted map[string]time.Time
+}
+
+var progresses = &progressHub{
+	subscribers:   make(map[*progressSubscriber]struct{}),
+	lastPersisted: make(map[string]time.Time),
+}
+
+func (h *progressHub) subscribe(sns []string) (*progressSubscriber, error) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	if len(h.subscribers) >= maxProgressSubscribers {
+		return nil, fmt.Errorf("the count of progress streams reaches the limit %d", maxProgressSubscribers)
+	}
+	sub := &progressSubscriber{sns: make(map[string]struct{}, len(sns)),
+		events: make(chan ProgressEvent, subscriberBufferSize)}
+	for _, sn := range sns {
+		sub.sns[sn] = struct{}{}
+	}
+	h.subscribers[sub] = struct{}{}
+	return sub, nil
+}
+
+func (h *progressHub) unsubscribe(sub *progressSubscriber) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	delete(h.subscribers, sub)
+	if sub.dropped > 0 {
+		hwlog.RunLog.Warnf("%d progress events are dropped since the stream is not read in time", sub.dropped)
+	}
+}
+
+// publish sends the event to the subscribers watching the node, the event is dropped for the subscriber which does
+// not read in time, so that a slow stream does not block the reports of edge
+func (h *progressHub) publish(event ProgressEvent) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	for sub := range h.subscribers {
+		if _, ok := sub.sns[event.SerialNumber]; !ok && len(sub.sns) != 0 {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+			sub.dropped++
+		}
+	}
+}
+
+// shouldPersist the events of stage changes and failures are always persisted, the periodical reports of the
+// downloaded size are sampled
+func (h *progressHub) shouldPersist(event ProgressEvent) bool {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	if event.DownloadedBytes == 0 {
+		delete(h.lastPersisted, event.SerialNumber)
+		return true
+	}
+	now := time.Now()
+	if last, ok := h.lastPersisted[event.SerialNumber]; ok && now.Sub(last) < bytesPersistInterval {
+		return false
+	}
+	h.lastPersisted[event.SerialNumber] = now
+	return true
+}
+
+// recordProgress persists the progress event of node and sends it to the progress streams
+func recordProgress(sn string, info types.ProgressInfo) {
+	event := ProgressEvent{
+		SerialNumber:    sn,
+		Stage:           info.Stage,
+		Progress:        info.Progress,
+		Res:             info.Res,
+		Msg:             info.Msg,
+		DownloadedBytes: info.DownloadedBytes,
+		Rate:            info.Rate,
+		CreatedAt:       time.Now().Unix(),
+	}
+	progresses.publish(event)
+	if !progresses.shouldPersist(event) {
+		return
+	}
+	record := progressEventRecord{
+		SerialNumber:    event.SerialNumber,
+		Stage:           event.Stage,
+		Progress:        event.Progress,
+		Res:             event.Res,
+		Msg:             event.Msg,
+		DownloadedBytes: event.DownloadedBytes,
+		Rate:            event.Rate,
+		CreatedAt:       event.CreatedAt,
+	}
+	if err := createProgressEvent(&record); err != nil {
+		hwlog.RunLog.Errorf("persist progress event of node [%s] failed: %v", sn, err)
+	}
+}
+
+func queryEdgeProgressHistory(msg *model.Message) common.RespMsg {
+	hwlog.RunLog.Info("start query edge software progress history")
+	var serialNumber string
+	if err := msg.ParseContent(&serialNumber); err != nil {
+		hwlog.RunLog.Errorf("query edge software progress history failed: parse content failed: %v", err)
+		return common.RespMsg{Status: common.ErrorParamConvert, Msg: "parse content failed", Data: nil}
+	}
+	if !snReg.MatchString(serialNumber) {
+		hwlog.RunLog.Error("check progress history para failed: serial number is invalid")
+		return common.RespMsg{Status: common.ErrorParamInvalid, Msg: "serial number is invalid", Data: nil}
+	}
+	records, err := queryProgressEventsBySn(serialNumber)
+	if err != nil {
+		hwlog.RunLog.Errorf("get progress history for %s failed: %v", serialNumber, err)
+		return common.RespMsg{Status: common.ErrorGetSoftwareDownloadProgress,
+			Msg: "get progress history failed", Data: nil}
+	}
+	events := make([]ProgressEvent, 0, len(records))
+	for _, record := range records {
+		events = append(events, ProgressEvent{
+			SerialNumber:    record.SerialNumber,
+			Stage:           record.Stage,
+			Progress:        record.Progress,
+			Res:             record.Res,
+			Msg:             record.Msg,
+			DownloadedBytes: record.DownloadedBytes,
+			Rate:            record.Rate,
+			CreatedAt:       record.CreatedAt,
+		})
+	}
+	return common.RespMsg{Status: common.Success, Msg: "", Data: events}
+}
+
+func parseStreamNodes(value string) ([]string, error) {
+	if value == "" {
+		return nil, nil
+	}
+	sns := strings.Split(value, ",")
+	if len(sns) > maxStreamNodes {
+		return nil, fmt.Errorf("the count of nodes should not be more than %d", maxStreamNodes)
+	}
+	for _, sn := range sns {
+		if !snReg.MatchString(sn) {
+			return nil, errors.New("serial number is invalid")
+		}
+	}
+	return sns, nil
+}
+
+// HandleProgressStream streams the progress events of the nodes as server-sent events until the client goes away,
+// the nodes are given by comma separated serial numbers, all nodes are watched if no serial number is given
+func HandleProgressStream(c *gin.Context) {
+	sns, err := parseStreamNodes(c.Query(querySerialNumbers))
+	if err != nil {
+		hwlog.RunLog.Errorf("check progress stream para failed: %v", err)
+		common.ConstructResp(c, common.ErrorParamInvalid, err.Error(), nil)
+		return
+	}
+	sub, err := progresses.subscribe(sns)
+	if err != nil {
+		hwlog.RunLog.Errorf("open progress stream failed: %v", err)
+		common.ConstructResp(c, common.ErrorGetSoftwareDownloadProgress, err.Error(), nil)
+		return
+	}
+	defer progresses.unsubscribe(sub)
+	hwlog.RunLog.Infof("progress stream of %d nodes is opened, 0 means all nodes", len(sns))
+
+	// the headers are sent at once so that the client knows the stream is opened before any event
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Writer.WriteHeaderNow()
+	c.Writer.Flush()
+	ticker := time.NewTicker(keepAliveInterval)
+	defer ticker.Stop()
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case event := <-sub.events:
+			c.SSEvent(progressEventName, event)
+		case <-ticker.C:
+			c.SSEvent(keepAliveEventName, "")
+		}
+		return true
+	})
+	hwlog.RunLog.Info("progress stream is closed")
+}
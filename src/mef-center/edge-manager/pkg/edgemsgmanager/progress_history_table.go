@@ -0,0 +1,79 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package edgemsgmanager database table of software progress events
+package edgemsgmanager
+
+import (
+	"fmt"
+
+	"huawei.com/mindx/common/database"
+)
+
+const (
+	tableProgressEvent = "software_progress_event"
+	maxEventsPerNode   = 256
+	maxEventMsgLen     = 512
+)
+
+// progressEventRecord one download or upgrade progress event of an edge node
+type progressEventRecord struct {
+	Id              int64  `gorm:"column:id;primaryKey;autoIncrement"`
+	SerialNumber    string `gorm:"column:serial_number;size:64;not null;index"`
+	Stage           string `gorm:"column:stage;size:32"`
+	Progress        uint64 `gorm:"column:progress"`
+	Res             string `gorm:"column:res;size:16"`
+	Msg             string `gorm:"column:msg;size:512"`
+	DownloadedBytes int64  `gorm:"column:downloaded_bytes"`
+	Rate            int64  `gorm:"column:rate"`
+	CreatedAt       int64  `gorm:"column:created_at"`
+}
+
+// TableName define database table name for software progress event
+func (*progressEventRecord) TableName() string {
+	return tableProgressEvent
+}
+
+func initProgressEventTable() error {
+	return database.CreateTableIfNotExist(progressEventRecord{})
+}
+
+// createProgressEvent inserts the event, the oldest events of the node beyond the limit are deleted
+func createProgressEvent(record *progressEventRecord) error {
+	if len(record.Msg) > maxEventMsgLen {
+		record.Msg = record.Msg[:maxEventMsgLen]
+	}
+	if err := database.GetDb().Create(record).Error; err != nil {
+		return fmt.Errorf("insert record to table [%s] error: %v", tableProgressEvent, err)
+	}
+	var ids []int64
+	if err := database.GetDb().Model(&progressEventRecord{}).Where("serial_number = ?", record.SerialNumber).
+		Order("id desc").Offset(maxEventsPerNode).Limit(1).Pluck("id", &ids).Error; err != nil {
+		return fmt.Errorf("query records in table [%s] error: %v", tableProgressEvent, err)
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	if err := database.GetDb().Where("serial_number = ? AND id <= ?", record.SerialNumber, ids[0]).
+		Delete(&progressEventRecord{}).Error; err != nil {
+		return fmt.Errorf("delete records from table [%s] error: %v", tableProgressEvent, err)
+	}
+	return nil
+}
+
+// queryProgressEventsBySn events of one node from the oldest to the latest
+func queryProgressEventsBySn(sn string) ([]progressEventRecord, error) {
+	var records []progressEventRecord
+	if err := database.GetDb().Model(&progressEventRecord{}).Where("serial_number = ?", sn).
+		Order("id asc").Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("query records in table [%s] error: %v", tableProgressEvent, err)
+	}
+	return records, nil
+}
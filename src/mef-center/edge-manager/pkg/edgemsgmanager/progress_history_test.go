@@ -0,0 +1,135 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package edgemsgmanager test for history and live stream of edge software progress
+package edgemsgmanager
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/smartystreets/goconvey/convey"
+
+	"huawei.com/mindx/common/modulemgr/model"
+
+	"huawei.com/mindxedge/base/common"
+
+	"edge-manager/pkg/types"
+)
+
+const historyTestSn = "history-node"
+
+func queryHistoryForTest(sn string) []ProgressEvent {
+	msg, err := model.NewMessage()
+	convey.So(err, convey.ShouldBeNil)
+	convey.So(msg.FillContent(sn), convey.ShouldBeNil)
+	resp := queryEdgeProgressHistory(msg)
+	convey.So(resp.Status, convey.ShouldEqual, common.Success)
+	events, ok := resp.Data.([]ProgressEvent)
+	convey.So(ok, convey.ShouldBeTrue)
+	return events
+}
+
+func TestProgressHistory(t *testing.T) {
+	convey.Convey("progress events should be persisted and sampled", t, func() {
+		recordProgress(historyTestSn, types.ProgressInfo{Stage: stageDownloadRequested})
+		recordProgress(historyTestSn, types.ProgressInfo{Progress: 40, Res: "success", Stage: stageDownloading})
+		recordProgress(historyTestSn, types.ProgressInfo{Progress: 40, Res: "success", Stage: stageDownloading,
+			DownloadedBytes: common.MB, Rate: common.KB})
+		recordProgress(historyTestSn, types.ProgressInfo{Progress: 40, Res: "success", Stage: stageDownloading,
+			DownloadedBytes: 2 * common.MB, Rate: common.KB})
+		recordProgress(historyTestSn, types.ProgressInfo{Progress: 40, Res: "failed", Stage: stageDownloading,
+			Msg: "download failed"})
+		events := queryHistoryForTest(historyTestSn)
+		convey.So(len(events), convey.ShouldEqual, 4)
+		convey.So(events[0].Stage, convey.ShouldEqual, stageDownloadRequested)
+		convey.So(events[2].DownloadedBytes, convey.ShouldEqual, common.MB)
+		convey.So(events[3].Msg, convey.ShouldEqual, "download failed")
+	})
+
+	convey.Convey("the oldest events beyond the limit should be deleted", t, func() {
+		const sn = "history-limit-node"
+		for i := 0; i < maxEventsPerNode+1; i++ {
+			recordProgress(sn, types.ProgressInfo{Stage: stageUpgradeRequested})
+		}
+		convey.So(len(queryHistoryForTest(sn)), convey.ShouldEqual, maxEventsPerNode)
+	})
+
+	convey.Convey("query history should be failed, invalid param", t, func() {
+		msg, err := model.NewMessage()
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(msg.FillContent("-invalid"), convey.ShouldBeNil)
+		convey.So(queryEdgeProgressHistory(msg).Status, convey.ShouldEqual, common.ErrorParamInvalid)
+	})
+}
+
+func TestHandleProgressStream(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+	engine := gin.New()
+	engine.GET("/stream", HandleProgressStream)
+	server := httptest.NewServer(engine)
+	defer server.Close()
+
+	convey.Convey("progress events of the watched nodes should be streamed", t, func() {
+		// the stream is closed by canceling the request, the body of stream is not drained by closing it
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/stream?serialNumbers=stream-node", nil)
+		convey.So(err, convey.ShouldBeNil)
+		resp, err := http.DefaultClient.Do(req)
+		convey.So(err, convey.ShouldBeNil)
+		defer resp.Body.Close()
+		convey.So(resp.Header.Get("Content-Type"), convey.ShouldContainSubstring, "text/event-stream")
+
+		go func() {
+			for progressSubscriberCount() == 0 {
+				time.Sleep(time.Millisecond)
+			}
+			progresses.publish(ProgressEvent{SerialNumber: "other-node", Stage: stageDownloading})
+			progresses.publish(ProgressEvent{SerialNumber: "stream-node", Stage: stageVerifying, Progress: 70})
+		}()
+		reader := bufio.NewReader(resp.Body)
+		var data string
+		for data == "" {
+			line, err := reader.ReadString('\n')
+			convey.So(err, convey.ShouldBeNil)
+			if strings.HasPrefix(line, "data:") {
+				data = strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+			}
+		}
+		var event ProgressEvent
+		convey.So(json.Unmarshal([]byte(data), &event), convey.ShouldBeNil)
+		convey.So(event.SerialNumber, convey.ShouldEqual, "stream-node")
+		convey.So(event.Progress, convey.ShouldEqual, 70)
+		cancel()
+	})
+
+	convey.Convey("progress stream should be refused, invalid serial number", t, func() {
+		resp, err := http.Get(server.URL + "/stream?serialNumbers=node,-invalid")
+		convey.So(err, convey.ShouldBeNil)
+		defer resp.Body.Close()
+		var result common.RespMsg
+		convey.So(json.NewDecoder(resp.Body).Decode(&result), convey.ShouldBeNil)
+		convey.So(result.Status, convey.ShouldEqual, common.ErrorParamInvalid)
+	})
+}
+
+func progressSubscriberCount() int {
+	progresses.lock.Lock()
+	defer progresses.lock.Unlock()
+	return len(progresses.subscribers)
+}
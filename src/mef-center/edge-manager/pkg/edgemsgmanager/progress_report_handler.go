@@ -37,5 +37,6 @@ func UpdateEdgeDownloadProgress(msg *model.Message) common.RespMsg {
 		hwlog.RunLog.Errorf("set software download progress for %s failed: %v", sn, err)
 		return common.RespMsg{Status: common.ErrorUpdateSoftwareDownloadProgress, Msg: "set cache error", Data: nil}
 	}
+	recordProgress(sn, req.ProgressInfo)
 	return common.RespMsg{Status: common.Success, Msg: "", Data: nil}
 }
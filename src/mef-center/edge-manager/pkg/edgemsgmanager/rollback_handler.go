@@ -0,0 +1,86 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package edgemsgmanager roll back edge software to the inactive version
+package edgemsgmanager
+
+import (
+	"fmt"
+
+	"huawei.com/mindx/common/hwlog"
+	"huawei.com/mindx/common/modulemgr"
+	"huawei.com/mindx/common/modulemgr/model"
+
+	"edge-manager/pkg/types"
+
+	"huawei.com/mindxedge/base/common"
+	"huawei.com/mindxedge/base/common/logmgmt"
+)
+
+func rollbackEdgeSoftware(msg *model.Message) common.RespMsg {
+	ctx := msg.TraceContext()
+	hwlog.RunLog.InfoWithCtx(ctx, "start rollback edge software")
+	var req RollbackSoftwareReq
+	if err := msg.ParseContent(&req); err != nil {
+		return common.RespMsg{Status: common.ErrorParamConvert, Msg: err.Error(), Data: nil}
+	}
+
+	if checkResult := newUpgradeChecker().Check(req); !checkResult.Result {
+		hwlog.RunLog.ErrorfWithCtx(ctx, "check software rollback para failed: %s", checkResult.Reason)
+		return common.RespMsg{Status: common.ErrorParamInvalid, Msg: checkResult.Reason, Data: nil}
+	}
+
+	batchResp := types.BatchResp{FailedInfos: make(map[string]string)}
+	for _, sn := range req.SerialNumbers {
+		if err := sendRollbackToEdge(sn, msg.Content, msg.GetTraceId()); err != nil {
+			hwlog.RunLog.ErrorfWithCtx(ctx, "send rollback msg to edge failed: %v", err)
+			batchResp.FailedInfos[sn] = err.Error()
+			continue
+		}
+		batchResp.SuccessIDs = append(batchResp.SuccessIDs, sn)
+	}
+
+	logmgmt.BatchOperationLog("send rollback instruction to edge", batchResp.SuccessIDs)
+	if len(batchResp.FailedInfos) != 0 {
+		hwlog.RunLog.ErrorWithCtx(ctx, "deal edge software rollback failed")
+		return common.RespMsg{Status: common.ErrorSendMsgToNode, Msg: "", Data: batchResp}
+	}
+	hwlog.RunLog.InfoWithCtx(ctx, "deal edge software rollback success")
+	return common.RespMsg{Status: common.Success, Msg: "", Data: batchResp}
+}
+
+// sendRollbackToEdge send rollback instruction to edge, the message carries the trace id of rollback request
+func sendRollbackToEdge(sn string, content []byte, traceId string) error {
+	msg, err := model.NewMessage()
+	if err != nil {
+		return fmt.Errorf("create message for %s failed", sn)
+	}
+
+	msg.SetRouter(common.NodeMsgManagerName, common.CloudHubName, common.OptPost, common.ResEdgeRollbackInfo)
+	if err = msg.FillContent(content); err != nil {
+		return fmt.Errorf("fill content failed: %v", err)
+	}
+	msg.SetNodeId(sn)
+	msg.SetTraceId(traceId)
+
+	rsp, err := modulemgr.SendSyncMessage(msg, common.ResponseTimeout)
+	if err != nil {
+		return fmt.Errorf("send msg failed: %v", err)
+	}
+
+	var respContent string
+	if err = rsp.ParseContent(&respContent); err != nil {
+		return fmt.Errorf("parse resp failed: %v", err)
+	}
+	if respContent != common.OK {
+		return fmt.Errorf("mef edge process software rollback in %s failed", sn)
+	}
+	return nil
+}
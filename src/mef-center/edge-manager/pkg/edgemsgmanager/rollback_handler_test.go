@@ -0,0 +1,68 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package edgemsgmanager test for rolling back edge software
+package edgemsgmanager
+
+import (
+	"testing"
+	"time"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/smartystreets/goconvey/convey"
+
+	"huawei.com/mindx/common/modulemgr"
+	"huawei.com/mindx/common/modulemgr/model"
+	"huawei.com/mindx/common/test"
+
+	"huawei.com/mindxedge/base/common"
+)
+
+func newRollbackMsg(req RollbackSoftwareReq) *model.Message {
+	msg, err := model.NewMessage()
+	convey.So(err, convey.ShouldBeNil)
+	convey.So(msg.FillContent(req, true), convey.ShouldBeNil)
+	return msg
+}
+
+func TestRollbackSoftware(t *testing.T) {
+	req := RollbackSoftwareReq{SerialNumbers: []string{"2102312NSF10K8000130"}, SoftwareName: common.MEFEdge}
+
+	convey.Convey("rollback software should be success", t, func() {
+		var sentRes string
+		p := gomonkey.ApplyFunc(modulemgr.SendSyncMessage,
+			func(m *model.Message, duration time.Duration) (*model.Message, error) {
+				sentRes = m.GetResource()
+				rspMsg, err := model.NewMessage()
+				convey.So(err, convey.ShouldBeNil)
+				convey.So(rspMsg.FillContent(common.OK), convey.ShouldBeNil)
+				return rspMsg, nil
+			})
+		defer p.Reset()
+		resp := rollbackEdgeSoftware(newRollbackMsg(req))
+		convey.So(resp.Status, convey.ShouldEqual, common.Success)
+		convey.So(sentRes, convey.ShouldEqual, common.ResEdgeRollbackInfo)
+	})
+
+	convey.Convey("rollback software should be failed, invalid param", t, func() {
+		resp := rollbackEdgeSoftware(&model.Message{Content: []byte("")})
+		convey.So(resp.Status, convey.ShouldEqual, common.ErrorParamConvert)
+		invalidReq := RollbackSoftwareReq{SerialNumbers: req.SerialNumbers, SoftwareName: "AtlasEdge"}
+		resp = rollbackEdgeSoftware(newRollbackMsg(invalidReq))
+		convey.So(resp.Status, convey.ShouldEqual, common.ErrorParamInvalid)
+	})
+
+	convey.Convey("rollback software should be failed, send sync msg error", t, func() {
+		p := gomonkey.ApplyFuncReturn(modulemgr.SendSyncMessage, nil, test.ErrTest)
+		defer p.Reset()
+		resp := rollbackEdgeSoftware(newRollbackMsg(req))
+		convey.So(resp.Status, convey.ShouldEqual, common.ErrorSendMsgToNode)
+	})
+}
@@ -16,8 +16,10 @@ import (
 	"encoding/json"
 	"errors"
 	"strconv"
+	"time"
 
 	"huawei.com/mindxedge/base/common"
+	"huawei.com/mindxedge/base/common/taskschedule"
 )
 
 // SoftwareDownloadInfo content for download software
@@ -27,11 +29,25 @@ type SoftwareDownloadInfo struct {
 	DownloadInfo  DownloadInfo `json:"downloadInfo"`
 }
 
-// DownloadInfo [struct] to software download info
+// DownloadInfo [struct] to software download info, the software is downloaded from the artifact repository of
+// edge-manager if the artifact id is set, otherwise from the package url with the username and password
 type DownloadInfo struct {
-	Package  string    `json:"package"`
-	UserName string    `json:"username"`
-	Password *Password `json:"password"`
+	Package    string    `json:"package"`
+	UserName   string    `json:"username"`
+	Password   *Password `json:"password"`
+	ArtifactId string    `json:"artifactId,omitempty"`
+	// Sha256 and the site cache are filled by edge-manager for each node when the artifact id is set, the node
+	// downloads the artifact from the site cache of its node group, and checks it by the checksum. The site cache
+	// is authenticated by the serial number of its cert
+	Sha256          string `json:"sha256,omitempty"`
+	SiteCacheIp     string `json:"siteCacheIp,omitempty"`
+	SiteCachePort   int    `json:"siteCachePort,omitempty"`
+	SiteCacheCertSn string `json:"siteCacheCertSn,omitempty"`
+	// DeltaArtifactId the delta package from the installed version to the artifact, the node downloads the full
+	// artifact instead if its installed version is not the base of the delta package. DeltaSha256 is filled by
+	// edge-manager as Sha256
+	DeltaArtifactId string `json:"deltaArtifactId,omitempty"`
+	DeltaSha256     string `json:"deltaSha256,omitempty"`
 }
 
 // UpgradeSoftwareReq update software
@@ -40,6 +56,85 @@ type UpgradeSoftwareReq struct {
 	SoftwareName  string   `json:"softwareName"`
 }
 
+// RollbackSoftwareReq roll back software to the inactive version
+type RollbackSoftwareReq struct {
+	SerialNumbers []string `json:"serialNumbers"`
+	SoftwareName  string   `json:"softwareName"`
+}
+
+// UpgradeCampaignReq request for creating an upgrade campaign, the nodes of each node group are downloaded and
+// effected in waves, WavePercents are the cumulative percents of nodes of each group upgraded after each wave
+type UpgradeCampaignReq struct {
+	NodeGroupIDs     []uint64     `json:"nodeGroupIDs"`
+	SoftwareName     string       `json:"softwareName"`
+	Version          string       `json:"version"`
+	DownloadInfo     DownloadInfo `json:"downloadInfo"`
+	WavePercents     []uint64     `json:"wavePercents"`
+	FailureThreshold uint64       `json:"failureThreshold"`
+	WaveTimeout      uint64       `json:"waveTimeout"`
+}
+
+// PreflightReq request for checking whether the nodes of the node groups can be upgraded to the version, the nodes
+// run the checks of preupgrade without modifying anything
+type PreflightReq struct {
+	NodeGroupIDs []uint64 `json:"nodeGroupIDs"`
+	SoftwareName string   `json:"softwareName"`
+	Version      string   `json:"version"`
+	InnerVersion string   `json:"innerVersion,omitempty"`
+}
+
+// PreflightItem result of a check item of preflight on edge node
+type PreflightItem struct {
+	Name    string `json:"name"`
+	Passed  bool   `json:"passed"`
+	Message string `json:"message,omitempty"`
+}
+
+// NodePreflight results of preflight of a node, Error is set if the node does not report its results
+type NodePreflight struct {
+	SerialNumber   string          `json:"serialNumber"`
+	Passed         bool            `json:"passed"`
+	CurrentVersion string          `json:"currentVersion,omitempty"`
+	Items          []PreflightItem `json:"items"`
+	Error          string          `json:"error,omitempty"`
+}
+
+// GroupPreflight results of preflight aggregated per node group
+type GroupPreflight struct {
+	NodeGroupID uint64          `json:"nodeGroupID"`
+	Total       int             `json:"total"`
+	Passed      int             `json:"passed"`
+	Failed      int             `json:"failed"`
+	NoResponse  int             `json:"noResponse"`
+	Nodes       []NodePreflight `json:"nodes"`
+	Error       string          `json:"error,omitempty"`
+}
+
+// PreflightResult results of preflight, Passed is true only if all nodes of all node groups pass the checks
+type PreflightResult struct {
+	CheckId string           `json:"checkId"`
+	Passed  bool             `json:"passed"`
+	Groups  []GroupPreflight `json:"groups"`
+}
+
+// CampaignOperateReq request for pausing, resuming or aborting an upgrade campaign
+type CampaignOperateReq struct {
+	ID string `json:"id"`
+}
+
+// CampaignInfo status of an upgrade campaign
+type CampaignInfo struct {
+	ID         string                  `json:"id"`
+	Phase      taskschedule.TaskPhase  `json:"phase"`
+	Reason     string                  `json:"reason"`
+	Message    string                  `json:"message"`
+	Progress   uint                    `json:"progress"`
+	Paused     bool                    `json:"paused"`
+	Data       taskschedule.JsonObject `json:"data"`
+	CreatedAt  time.Time               `json:"createdAt"`
+	FinishedAt time.Time               `json:"finishedAt"`
+}
+
 // Password the password struct
 type Password []byte
 
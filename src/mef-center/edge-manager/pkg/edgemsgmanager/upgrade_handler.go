@@ -25,7 +25,8 @@ import (
 )
 
 func upgradeEdgeSoftware(msg *model.Message) common.RespMsg {
-	hwlog.RunLog.Info("start effect edge software")
+	ctx := msg.TraceContext()
+	hwlog.RunLog.InfoWithCtx(ctx, "start effect edge software")
 	var req UpgradeSoftwareReq
 	var err error
 	if err = msg.ParseContent(&req); err != nil {
@@ -33,7 +34,7 @@ func upgradeEdgeSoftware(msg *model.Message) common.RespMsg {
 	}
 
 	if checkResult := newUpgradeChecker().Check(req); !checkResult.Result {
-		hwlog.RunLog.Errorf("check software upgrade para failed: %s", checkResult.Reason)
+		hwlog.RunLog.ErrorfWithCtx(ctx, "check software upgrade para failed: %s", checkResult.Reason)
 		return common.RespMsg{Status: common.ErrorParamInvalid, Msg: checkResult.Reason, Data: nil}
 	}
 
@@ -41,8 +42,8 @@ func upgradeEdgeSoftware(msg *model.Message) common.RespMsg {
 	failedMap := make(map[string]string)
 	batchResp.FailedInfos = failedMap
 	for _, sn := range req.SerialNumbers {
-		if err = sendUpgradeConfigToEdge(sn, msg.Content); err != nil {
-			hwlog.RunLog.Errorf("send upgrade msg to edge failed: %v", err)
+		if err = sendUpgradeConfigToEdge(sn, msg.Content, msg.GetTraceId()); err != nil {
+			hwlog.RunLog.ErrorfWithCtx(ctx, "send upgrade msg to edge failed: %v", err)
 			failedMap[sn] = err.Error()
 			continue
 		}
@@ -52,15 +53,16 @@ func upgradeEdgeSoftware(msg *model.Message) common.RespMsg {
 
 	logmgmt.BatchOperationLog("send upgrade instruction to edge", batchResp.SuccessIDs)
 	if len(batchResp.FailedInfos) != 0 {
-		hwlog.RunLog.Error("deal edge software upgrade info failed")
+		hwlog.RunLog.ErrorWithCtx(ctx, "deal edge software upgrade info failed")
 		return common.RespMsg{Status: common.ErrorSendMsgToNode, Msg: "", Data: batchResp}
 	} else {
-		hwlog.RunLog.Info("deal edge software upgrade info success")
+		hwlog.RunLog.InfoWithCtx(ctx, "deal edge software upgrade info success")
 		return common.RespMsg{Status: common.Success, Msg: "", Data: batchResp}
 	}
 }
 
-func sendUpgradeConfigToEdge(sn string, upgradeConfig []byte) error {
+// sendUpgradeConfigToEdge send upgrade config to edge, the message carries the trace id of upgrade request
+func sendUpgradeConfigToEdge(sn string, upgradeConfig []byte, traceId string) error {
 	msg, err := model.NewMessage()
 	if err != nil {
 		return fmt.Errorf("create message for %s failed", sn)
@@ -71,6 +73,7 @@ func sendUpgradeConfigToEdge(sn string, upgradeConfig []byte) error {
 		return fmt.Errorf("fill content failed: %v", err)
 	}
 	msg.SetNodeId(sn)
+	msg.SetTraceId(traceId)
 
 	rsp, err := modulemgr.SendSyncMessage(msg, common.ResponseTimeout)
 	if err != nil {
@@ -88,6 +91,7 @@ func sendUpgradeConfigToEdge(sn string, upgradeConfig []byte) error {
 	if err := nodesProgress.Set(sn, types.ProgressInfo{}, neverOverdue); err != nil {
 		return fmt.Errorf("reset software download progress for %s failed: %v", sn, err)
 	}
+	recordProgress(sn, types.ProgressInfo{Stage: stageUpgradeRequested})
 
 	return nil
 }
@@ -0,0 +1,37 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package nodeprotocol features of edge nodes checked by the modules
+package nodeprotocol
+
+// Feature an operation of center which needs the support of edge node. The node negotiating protocol version must
+// declare the capability, the legacy node is regarded as supporting the features whose min version is 0
+type Feature struct {
+	Name       string
+	Capability string
+	MinVersion int
+}
+
+var (
+	// FeatureUdpContainerPort container port with UDP protocol of app
+	FeatureUdpContainerPort = Feature{Name: "UDP container port", Capability: "support_udp_container_port"}
+	// FeatureCertSync sync of image registry cert and software repository cert
+	FeatureCertSync = Feature{Name: "cert sync", Capability: "cert_sync"}
+	// FeatureEdgeCaUpdate update of the root ca trusted by edge
+	FeatureEdgeCaUpdate = Feature{Name: "root ca update", Capability: "edge_ca_update"}
+	// FeatureEdgeSvcCertUpdate update of the service cert of edge
+	FeatureEdgeSvcCertUpdate = Feature{Name: "service cert update", Capability: "edge_svc_cert_update"}
+	// FeatureTraceLog collecting the log lines of a trace, it is introduced with protocol version 1
+	FeatureTraceLog = Feature{Name: "trace log query", Capability: "trace_log", MinVersion: 1}
+	// FeatureUpgradePreflight checking the node before upgrading, it is introduced with protocol version 1
+	FeatureUpgradePreflight = Feature{Name: "upgrade preflight", Capability: "upgrade_preflight", MinVersion: 1}
+	// FeatureOutboxAck acking the messages delivered from outbox, it is introduced with protocol version 1
+	FeatureOutboxAck = Feature{Name: "outbox ack", Capability: "outbox_ack", MinVersion: 1}
+)
@@ -0,0 +1,128 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package nodeprotocol records the protocol version and capabilities of edge nodes negotiated on connect, so that
+// the modules can refuse or downgrade the operations not supported by the nodes
+package nodeprotocol
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sync"
+
+	"huawei.com/mindx/common/websocketmgr"
+
+	"huawei.com/mindxedge/base/common"
+)
+
+const (
+	// CurrentVersion protocol version of center
+	CurrentVersion = 1
+
+	maxCapabilityNum = 64
+)
+
+var capabilityReg = regexp.MustCompile(`^[a-z0-9_]{1,64}$`)
+
+// ErrUnsupported the operation is not supported by edge node
+var ErrUnsupported = errors.New("operation is not supported by edge node")
+
+// NodeProtocol protocol version and capabilities of an edge node
+type NodeProtocol struct {
+	Version      int      `json:"version"`
+	Capabilities []string `json:"capabilities"`
+}
+
+// CapabilityReport capabilities reported by edge when they change after connect
+type CapabilityReport struct {
+	Capabilities []string `json:"capabilities"`
+}
+
+type protocolStore struct {
+	lock  sync.RWMutex
+	nodes map[string]NodeProtocol
+}
+
+var store = &protocolStore{nodes: make(map[string]NodeProtocol)}
+
+// Record records the protocol version and capabilities of node on connect
+func Record(sn string, version int, capabilities []string) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	if _, ok := store.nodes[sn]; !ok && len(store.nodes) >= common.MaxNode {
+		// records of deleted nodes are left behind, one of them is dropped, the dropped node is regarded as legacy
+		// until it connects again
+		for node := range store.nodes {
+			delete(store.nodes, node)
+			break
+		}
+	}
+	store.nodes[sn] = NodeProtocol{Version: version, Capabilities: append([]string{}, capabilities...)}
+}
+
+// UpdateCapabilities updates the capabilities reported by node, the version negotiated on connect is kept
+func UpdateCapabilities(sn string, report CapabilityReport) error {
+	if len(report.Capabilities) > maxCapabilityNum {
+		return fmt.Errorf("the count of capabilities exceeds the limit %d", maxCapabilityNum)
+	}
+	for _, capability := range report.Capabilities {
+		if !capabilityReg.MatchString(capability) {
+			return errors.New("capability is invalid")
+		}
+	}
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	protocol, ok := store.nodes[sn]
+	if !ok || protocol.Version == websocketmgr.LegacyProtocolVersion {
+		return fmt.Errorf("node [%s] does not negotiate protocol version on connect", sn)
+	}
+	protocol.Capabilities = append([]string{}, report.Capabilities...)
+	store.nodes[sn] = protocol
+	return nil
+}
+
+// Get gets the protocol of node, the node which has not connected since center starts is regarded as legacy
+func Get(sn string) NodeProtocol {
+	store.lock.RLock()
+	defer store.lock.RUnlock()
+	protocol, ok := store.nodes[sn]
+	if !ok {
+		return NodeProtocol{Version: websocketmgr.LegacyProtocolVersion, Capabilities: []string{}}
+	}
+	protocol.Capabilities = append([]string{}, protocol.Capabilities...)
+	return protocol
+}
+
+// CheckSupport checks whether the feature is supported by node
+func CheckSupport(sn string, feature Feature) error {
+	protocol := Get(sn)
+	if protocol.supports(feature) {
+		return nil
+	}
+	return fmt.Errorf("%w: node [%s] with protocol version %d does not support %s, please upgrade the node",
+		ErrUnsupported, sn, protocol.Version, feature.Name)
+}
+
+func (p NodeProtocol) supports(feature Feature) bool {
+	if p.Version < feature.MinVersion {
+		return false
+	}
+	// capabilities of legacy node are unknown, the features of legacy protocol are regarded as supported
+	if p.Version == websocketmgr.LegacyProtocolVersion || feature.Capability == "" {
+		return true
+	}
+	for _, capability := range p.Capabilities {
+		if capability == feature.Capability {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,62 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package nodeprotocol test for node protocol
+package nodeprotocol
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/smartystreets/goconvey/convey"
+)
+
+const (
+	testSn       = "2102312NSF10K8000130"
+	testLegacySn = "2102312NSF10K8000131"
+)
+
+func TestCheckSupport(t *testing.T) {
+	Record(testSn, CurrentVersion, []string{FeatureEdgeCaUpdate.Capability, FeatureTraceLog.Capability})
+	Record(testLegacySn, 0, nil)
+
+	convey.Convey("node negotiating protocol supports the declared capabilities only", t, func() {
+		convey.So(CheckSupport(testSn, FeatureEdgeCaUpdate), convey.ShouldBeNil)
+		convey.So(CheckSupport(testSn, FeatureTraceLog), convey.ShouldBeNil)
+		err := CheckSupport(testSn, FeatureUdpContainerPort)
+		convey.So(errors.Is(err, ErrUnsupported), convey.ShouldBeTrue)
+		convey.So(err.Error(), convey.ShouldContainSubstring, FeatureUdpContainerPort.Name)
+	})
+
+	convey.Convey("legacy node supports the features of legacy protocol only", t, func() {
+		convey.So(CheckSupport(testLegacySn, FeatureUdpContainerPort), convey.ShouldBeNil)
+		convey.So(CheckSupport(testLegacySn, FeatureTraceLog), convey.ShouldNotBeNil)
+		convey.So(CheckSupport("unknown", FeatureEdgeSvcCertUpdate), convey.ShouldBeNil)
+		convey.So(CheckSupport("unknown", FeatureTraceLog), convey.ShouldNotBeNil)
+	})
+}
+
+func TestUpdateCapabilities(t *testing.T) {
+	Record(testSn, CurrentVersion, nil)
+	Record(testLegacySn, 0, nil)
+
+	convey.Convey("capabilities reported after connect are updated", t, func() {
+		err := UpdateCapabilities(testSn, CapabilityReport{Capabilities: []string{FeatureCertSync.Capability}})
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(Get(testSn), convey.ShouldResemble,
+			NodeProtocol{Version: CurrentVersion, Capabilities: []string{FeatureCertSync.Capability}})
+	})
+
+	convey.Convey("invalid report or report of legacy node is rejected", t, func() {
+		convey.So(UpdateCapabilities(testSn, CapabilityReport{Capabilities: []string{"a,b"}}), convey.ShouldNotBeNil)
+		convey.So(UpdateCapabilities(testLegacySn, CapabilityReport{}), convey.ShouldNotBeNil)
+		convey.So(UpdateCapabilities("unknown", CapabilityReport{}), convey.ShouldNotBeNil)
+	})
+}
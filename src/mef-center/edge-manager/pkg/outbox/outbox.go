@@ -0,0 +1,272 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package outbox store-and-forward messages from center to edge nodes which are offline, the messages of a node are
+// delivered one by one in the order they are sent, and each one is kept until it is acked by the edge
+package outbox
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"huawei.com/mindx/common/hwlog"
+	"huawei.com/mindx/common/modulemgr"
+	"huawei.com/mindx/common/modulemgr/model"
+
+	"huawei.com/mindxedge/base/common"
+
+	"edge-manager/pkg/nodeprotocol"
+)
+
+// message priority, the messages are delivered in order regardless of priority, a message with lower priority is
+// rejected earlier when the outbox of node is filling up, so that there is room for the important ones
+const (
+	PriorityLow    = 1
+	PriorityNormal = 5
+	PriorityHigh   = 9
+)
+
+const (
+	deliverTimeout       = 10 * time.Second
+	maxPendingPerNode    = 1000
+	defaultMessageTtl    = time.Hour
+	maxMessageTtl        = 7 * common.OneDay
+	minMessagePriority   = PriorityLow
+	maxMessagePriority   = PriorityHigh
+	flushInterval        = 5 * time.Minute
+	expiredCleanInterval = 10 * time.Minute
+)
+
+// Options delivery options of an outbox message
+type Options struct {
+	Priority int
+	Ttl      time.Duration
+}
+
+// queueLocks guard the capacity check and persistence of the messages of a node, flushLocks serialize the delivery
+// of them, so that queueing a message never waits for the delivery of the earlier ones
+var (
+	queueLocks    sync.Map
+	flushLocks    sync.Map
+	flushingNodes sync.Map
+)
+
+func getNodeLock(locks *sync.Map, sn string) *sync.Mutex {
+	lock, _ := locks.LoadOrStore(sn, &sync.Mutex{})
+	nodeLock, ok := lock.(*sync.Mutex)
+	if !ok {
+		return &sync.Mutex{}
+	}
+	return nodeLock
+}
+
+func (opts Options) normalize() Options {
+	if opts.Priority < minMessagePriority || opts.Priority > maxMessagePriority {
+		opts.Priority = PriorityNormal
+	}
+	if opts.Ttl <= 0 {
+		opts.Ttl = defaultMessageTtl
+	}
+	if opts.Ttl > maxMessageTtl {
+		opts.Ttl = maxMessageTtl
+	}
+	return opts
+}
+
+// capacity the max count of pending messages of a node when a message of the priority is queued
+func (opts Options) capacity() int64 {
+	return int64(maxPendingPerNode * opts.Priority / maxMessagePriority)
+}
+
+// SendToNode sends message to the edge node through cloud hub. The message is kept in outbox until it is acked by
+// the edge, it is delivered in background after the earlier pending ones in order, so the caller is not blocked by
+// the delivery. The pending messages are delivered again when the node reconnects
+func SendToNode(msg *model.Message, opts Options) error {
+	if msg == nil {
+		return errors.New("message is nil")
+	}
+	sn := msg.GetNodeId()
+	if sn == "" {
+		return errors.New("node id of message is empty")
+	}
+	opts = opts.normalize()
+	count, err := queueMessage(msg, opts)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		hwlog.RunLog.Infof("message [%s %s] to node [%s] is queued after %d pending messages", msg.GetOption(),
+			msg.GetResource(), sn, count)
+	}
+	triggerFlush(sn)
+	return nil
+}
+
+// queueMessage persists the message into outbox of the node if there is room for its priority, and returns the count
+// of the messages pending before it
+func queueMessage(msg *model.Message, opts Options) (int64, error) {
+	sn := msg.GetNodeId()
+	lock := getNodeLock(&queueLocks, sn)
+	lock.Lock()
+	defer lock.Unlock()
+
+	count, err := countPendingBySn(sn)
+	if err != nil {
+		return 0, err
+	}
+	if count >= opts.capacity() {
+		return 0, fmt.Errorf("outbox of node [%s] is full for messages of priority %d", sn, opts.Priority)
+	}
+	now := time.Now()
+	record := &outboxMessage{
+		SerialNumber: sn,
+		MsgId:        msg.GetId(),
+		Priority:     opts.Priority,
+		Source:       msg.GetSource(),
+		Option:       msg.GetOption(),
+		Resource:     msg.GetResource(),
+		Content:      msg.Content,
+		CreatedAt:    now.Unix(),
+		ExpireAt:     now.Add(opts.Ttl).Unix(),
+	}
+	if err = createMessage(record); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// deliver sends message to cloud hub and waits for the ack. The node supporting outbox ack acks the message after
+// it is handled by the edge module, for the legacy node cloud hub acks after the message is written to websocket
+func deliver(msg *model.Message) error {
+	msg.SetNeedAck(nodeprotocol.CheckSupport(msg.GetNodeId(), nodeprotocol.FeatureOutboxAck) == nil)
+	resp, err := modulemgr.SendSyncMessage(msg, deliverTimeout)
+	if err != nil {
+		return fmt.Errorf("wait for ack of message failed: %v", err)
+	}
+	var content string
+	if err = resp.ParseContent(&content); err != nil {
+		return fmt.Errorf("parse ack content failed: %v", err)
+	}
+	if content != common.OK {
+		return errors.New("node is not connected or message is not acked")
+	}
+	return nil
+}
+
+// toMessage rebuilds the message with its original id, so that the edge acks the redelivered message without
+// handling it again
+func (record *outboxMessage) toMessage() (*model.Message, error) {
+	msg, err := model.NewMessage()
+	if err != nil {
+		return nil, fmt.Errorf("create new message failed: %v", err)
+	}
+	if record.MsgId != "" {
+		msg.Header.Id = record.MsgId
+	}
+	msg.SetNodeId(record.SerialNumber)
+	msg.SetRouter(record.Source, common.CloudHubName, record.Option, record.Resource)
+	msg.Content = record.Content
+	return msg, nil
+}
+
+// triggerFlush flushes the node in background, the triggers during a pending flush are merged into it
+func triggerFlush(sn string) {
+	if _, loaded := flushingNodes.LoadOrStore(sn, struct{}{}); loaded {
+		return
+	}
+	go func() {
+		lock := getNodeLock(&flushLocks, sn)
+		lock.Lock()
+		defer lock.Unlock()
+		flushingNodes.Delete(sn)
+		flushNode(sn)
+	}()
+}
+
+// FlushNode delivers pending messages of the node in order, it is called when the node reconnects
+func FlushNode(sn string) {
+	lock := getNodeLock(&flushLocks, sn)
+	lock.Lock()
+	defer lock.Unlock()
+	flushNode(sn)
+}
+
+// flushNode delivers pending messages of the node one by one, each one is deleted after it is acked. It stops at
+// the first failure so that the order of the remaining messages is kept, and returns whether all are delivered.
+// The caller must hold the flush lock of the node
+func flushNode(sn string) bool {
+	records, err := queryPendingBySn(sn)
+	if err != nil {
+		hwlog.RunLog.Errorf("query pending messages of node [%s] failed: %v", sn, err)
+		return false
+	}
+	if len(records) == 0 {
+		return true
+	}
+	hwlog.RunLog.Infof("start to deliver %d pending messages to node [%s]", len(records), sn)
+	var delivered int
+	now := time.Now().Unix()
+	for i := range records {
+		record := &records[i]
+		if record.ExpireAt <= now {
+			hwlog.RunLog.Warnf("message [%s %s] to node [%s] is expired, drop it", record.Option,
+				record.Resource, sn)
+			if err = deleteMessageById(record.Id); err != nil {
+				hwlog.RunLog.Error(err)
+			}
+			continue
+		}
+		msg, err := record.toMessage()
+		if err != nil {
+			hwlog.RunLog.Errorf("rebuild message of node [%s] failed: %v", sn, err)
+			return false
+		}
+		if err = deliver(msg); err != nil {
+			hwlog.RunLog.Warnf("deliver pending message [%s %s] to node [%s] failed: %v", record.Option,
+				record.Resource, sn, err)
+			if err = updateDeliveryFailure(record.Id, err.Error()); err != nil {
+				hwlog.RunLog.Error(err)
+			}
+			hwlog.RunLog.Infof("%d pending messages are delivered to node [%s]", delivered, sn)
+			return false
+		}
+		if err = deleteMessageById(record.Id); err != nil {
+			hwlog.RunLog.Error(err)
+			return false
+		}
+		delivered++
+	}
+	hwlog.RunLog.Infof("%d pending messages are delivered to node [%s]", delivered, sn)
+	return true
+}
+
+func flushAllNodes() {
+	sns, err := queryPendingSns()
+	if err != nil {
+		hwlog.RunLog.Errorf("query nodes with pending messages failed: %v", err)
+		return
+	}
+	for _, sn := range sns {
+		FlushNode(sn)
+	}
+}
+
+func cleanExpiredMessages() {
+	count, err := deleteExpiredMessages(time.Now().Unix())
+	if err != nil {
+		hwlog.RunLog.Errorf("clean expired outbox messages failed: %v", err)
+		return
+	}
+	if count > 0 {
+		hwlog.RunLog.Infof("%d expired outbox messages are cleaned", count)
+	}
+}
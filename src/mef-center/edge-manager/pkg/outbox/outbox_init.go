@@ -0,0 +1,125 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package outbox to init outbox module
+package outbox
+
+import (
+	"context"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	"huawei.com/mindx/common/hwlog"
+	"huawei.com/mindx/common/modulemgr"
+	"huawei.com/mindx/common/modulemgr/model"
+
+	"huawei.com/mindxedge/base/common"
+)
+
+type handlerFunc func(message *model.Message) common.RespMsg
+
+type outboxManager struct {
+	enable bool
+	ctx    context.Context
+}
+
+// NewOutboxManager create outbox manager
+func NewOutboxManager(enable bool) model.Module {
+	return &outboxManager{
+		enable: enable,
+		ctx:    context.Background(),
+	}
+}
+
+func (om *outboxManager) Name() string {
+	return common.OutboxName
+}
+
+func (om *outboxManager) Enable() bool {
+	if om.enable {
+		if err := initOutboxTable(); err != nil {
+			hwlog.RunLog.Errorf("module (%s) init outbox database table failed, cannot enable", om.Name())
+			return !om.enable
+		}
+	}
+	return om.enable
+}
+
+func (om *outboxManager) Start() {
+	go periodCheckOutbox(om.ctx)
+	for {
+		select {
+		case _, ok := <-om.ctx.Done():
+			if !ok {
+				hwlog.RunLog.Info("catch stop signal channel is closed")
+			}
+			hwlog.RunLog.Info("has listened stop signal")
+			return
+		default:
+		}
+
+		req, err := modulemgr.ReceiveMessage(om.Name())
+		if err != nil {
+			hwlog.RunLog.Errorf("%s receive request from restful service failed", om.Name())
+			continue
+		}
+
+		go om.dispatch(req)
+	}
+}
+
+func (om *outboxManager) dispatch(req *model.Message) {
+	method, exist := handlerFuncMap[common.Combine(req.GetOption(), req.GetResource())]
+	if !exist {
+		hwlog.RunLog.Errorf("handler func is not exist, option: %s, resource: %s", req.GetOption(),
+			req.GetResource())
+		return
+	}
+	res := method(req)
+	resp, err := req.NewResponse()
+	if err != nil {
+		hwlog.RunLog.Errorf("%s new response failed", om.Name())
+		return
+	}
+	if err = resp.FillContent(res); err != nil {
+		hwlog.RunLog.Errorf("%s fill content failed: %v", om.Name(), err)
+		return
+	}
+	if err = modulemgr.SendMessage(resp); err != nil {
+		hwlog.RunLog.Errorf("%s send response failed", om.Name())
+		return
+	}
+}
+
+var outboxUrlRootPath = "/edgemanager/v1/outbox"
+
+var handlerFuncMap = map[string]handlerFunc{
+	common.Combine(http.MethodGet, filepath.Join(outboxUrlRootPath, "pending")): queryPendingMessages,
+}
+
+// periodCheckOutbox retries the pending messages in case the reconnect callback is missed, and drops the expired ones
+func periodCheckOutbox(ctx context.Context) {
+	flushTicker := time.NewTicker(flushInterval)
+	defer flushTicker.Stop()
+	cleanTicker := time.NewTicker(expiredCleanInterval)
+	defer cleanTicker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			hwlog.RunLog.Info("outbox check goroutine is stopped")
+			return
+		case <-cleanTicker.C:
+			cleanExpiredMessages()
+		case <-flushTicker.C:
+			flushAllNodes()
+		}
+	}
+}
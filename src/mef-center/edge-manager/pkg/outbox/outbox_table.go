@@ -0,0 +1,112 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package outbox database table of pending messages
+package outbox
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"huawei.com/mindx/common/database"
+)
+
+const (
+	tableOutboxMessage = "outbox_message"
+	maxLastErrorLen    = 256
+)
+
+// outboxMessage one message waiting to be delivered to an offline edge node
+type outboxMessage struct {
+	Id           int64  `gorm:"column:id;primaryKey;autoIncrement"`
+	SerialNumber string `gorm:"column:serial_number;size:64;not null;index"`
+	MsgId        string `gorm:"column:msg_id;size:64"`
+	Priority     int    `gorm:"column:priority"`
+	Source       string `gorm:"column:source;size:64"`
+	Option       string `gorm:"column:option;size:64"`
+	Resource     string `gorm:"column:resource;size:256"`
+	Content      []byte `gorm:"column:content"`
+	CreatedAt    int64  `gorm:"column:created_at"`
+	ExpireAt     int64  `gorm:"column:expire_at"`
+	AttemptCount int64  `gorm:"column:attempt_count"`
+	LastError    string `gorm:"column:last_error;size:256"`
+}
+
+// TableName define database table name for outbox message
+func (*outboxMessage) TableName() string {
+	return tableOutboxMessage
+}
+
+func initOutboxTable() error {
+	return database.CreateTableIfNotExist(outboxMessage{})
+}
+
+func createMessage(record *outboxMessage) error {
+	if err := database.GetDb().Create(record).Error; err != nil {
+		return fmt.Errorf("insert record to table [%s] error: %v", tableOutboxMessage, err)
+	}
+	return nil
+}
+
+func countPendingBySn(sn string) (int64, error) {
+	var count int64
+	if err := database.GetDb().Model(&outboxMessage{}).Where("serial_number = ?", sn).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("count records in table [%s] error: %v", tableOutboxMessage, err)
+	}
+	return count, nil
+}
+
+// queryPendingBySn pending messages of one node in delivery order: first in first out
+func queryPendingBySn(sn string) ([]outboxMessage, error) {
+	var records []outboxMessage
+	if err := database.GetDb().Model(&outboxMessage{}).Where("serial_number = ?", sn).
+		Order("id asc").Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("query records in table [%s] error: %v", tableOutboxMessage, err)
+	}
+	return records, nil
+}
+
+func queryPendingSns() ([]string, error) {
+	var sns []string
+	if err := database.GetDb().Model(&outboxMessage{}).Distinct("serial_number").
+		Pluck("serial_number", &sns).Error; err != nil {
+		return nil, fmt.Errorf("query node sn in table [%s] error: %v", tableOutboxMessage, err)
+	}
+	return sns, nil
+}
+
+func deleteMessageById(id int64) error {
+	if err := database.GetDb().Where("id = ?", id).Delete(&outboxMessage{}).Error; err != nil {
+		return fmt.Errorf("delete record from table [%s] error: %v", tableOutboxMessage, err)
+	}
+	return nil
+}
+
+func deleteExpiredMessages(now int64) (int64, error) {
+	result := database.GetDb().Where("expire_at <= ?", now).Delete(&outboxMessage{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("delete expired records from table [%s] error: %v", tableOutboxMessage, result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+func updateDeliveryFailure(id int64, lastErr string) error {
+	if len(lastErr) > maxLastErrorLen {
+		lastErr = lastErr[:maxLastErrorLen]
+	}
+	if err := database.GetDb().Model(&outboxMessage{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"attempt_count": gorm.Expr("attempt_count + ?", 1),
+		"last_error":    lastErr,
+	}).Error; err != nil {
+		return fmt.Errorf("update record in table [%s] error: %v", tableOutboxMessage, err)
+	}
+	return nil
+}
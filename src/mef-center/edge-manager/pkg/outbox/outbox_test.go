@@ -0,0 +1,279 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package outbox test for store-and-forward of edge messages
+package outbox
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/smartystreets/goconvey/convey"
+
+	"huawei.com/mindx/common/database"
+	"huawei.com/mindx/common/modulemgr"
+	"huawei.com/mindx/common/modulemgr/model"
+
+	"huawei.com/mindxedge/base/common"
+
+	"edge-manager/pkg/nodeprotocol"
+)
+
+const testSn = "2102312NSF10K8000130"
+
+func newNodeMsgForUT(resource string) *model.Message {
+	msg := newMsgWithContentForUT("payload")
+	msg.SetNodeId(testSn)
+	msg.SetRouter(common.ConfigManagerName, common.CloudHubName, common.OptPost, resource)
+	return msg
+}
+
+func clearOutbox() {
+	err := database.GetDb().Where("serial_number = ?", testSn).Delete(&outboxMessage{}).Error
+	convey.So(err, convey.ShouldBeNil)
+}
+
+func pendingResources() []string {
+	records, err := queryPendingBySn(testSn)
+	convey.So(err, convey.ShouldBeNil)
+	resources := make([]string, 0, len(records))
+	for _, record := range records {
+		resources = append(resources, record.Resource)
+	}
+	return resources
+}
+
+func queueForUT(resource string, priority int) {
+	msg := newNodeMsgForUT(resource)
+	now := time.Now()
+	convey.So(createMessage(&outboxMessage{
+		SerialNumber: testSn,
+		MsgId:        msg.GetId(),
+		Priority:     priority,
+		Source:       msg.GetSource(),
+		Option:       msg.GetOption(),
+		Resource:     msg.GetResource(),
+		Content:      msg.Content,
+		CreatedAt:    now.Unix(),
+		ExpireAt:     now.Add(time.Hour).Unix(),
+	}), convey.ShouldBeNil)
+}
+
+func waitOutboxEmpty() {
+	const waitTimes = 100
+	for i := 0; i < waitTimes; i++ {
+		count, err := countPendingBySn(testSn)
+		convey.So(err, convey.ShouldBeNil)
+		if count == 0 {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// waitFlushDone waits for the background flush triggered by sending to finish
+func waitFlushDone() {
+	const waitTimes = 100
+	for i := 0; i < waitTimes; i++ {
+		if _, ok := flushingNodes.Load(testSn); !ok {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	lock := getNodeLock(&flushLocks, testSn)
+	lock.Lock()
+	lock.Unlock()
+}
+
+func TestSendToNode(t *testing.T) {
+	convey.Convey("message is delivered directly when node is online", t, testSendToNodeOnline)
+	convey.Convey("message is queued when node is offline", t, testSendToNodeOffline)
+	convey.Convey("message is delivered after the pending ones", t, testSendToNodeAfterPending)
+	convey.Convey("message with low priority is rejected earlier", t, testSendToNodeFull)
+	convey.Convey("send to node should be failed, invalid message", t, testSendToNodeErrMsg)
+}
+
+func testSendToNodeOnline() {
+	clearOutbox()
+	patches := gomonkey.ApplyFuncReturn(deliver, nil)
+	defer patches.Reset()
+
+	convey.So(SendToNode(newNodeMsgForUT("res1"), Options{}), convey.ShouldBeNil)
+	waitFlushDone()
+	convey.So(pendingResources(), convey.ShouldBeEmpty)
+}
+
+func testSendToNodeOffline() {
+	clearOutbox()
+	patches := gomonkey.ApplyFuncReturn(deliver, errors.New("node is offline"))
+	defer patches.Reset()
+	msg := newNodeMsgForUT("res1")
+	convey.So(SendToNode(msg, Options{}), convey.ShouldBeNil)
+	waitFlushDone()
+	records, err := queryPendingBySn(testSn)
+	convey.So(err, convey.ShouldBeNil)
+	convey.So(len(records), convey.ShouldEqual, 1)
+	convey.So(records[0].MsgId, convey.ShouldEqual, msg.GetId())
+	convey.So(records[0].AttemptCount, convey.ShouldEqual, 1)
+}
+
+func testSendToNodeAfterPending() {
+	clearOutbox()
+	queueForUT("res1", PriorityLow)
+	var delivered []string
+	var lock sync.Mutex
+	patches := gomonkey.ApplyFunc(deliver, func(msg *model.Message) error {
+		lock.Lock()
+		defer lock.Unlock()
+		delivered = append(delivered, msg.GetResource())
+		return nil
+	})
+	defer patches.Reset()
+
+	// the later message must not overtake the pending one even if it has higher priority
+	convey.So(SendToNode(newNodeMsgForUT("res2"), Options{Priority: PriorityHigh}), convey.ShouldBeNil)
+	waitOutboxEmpty()
+	lock.Lock()
+	defer lock.Unlock()
+	convey.So(delivered, convey.ShouldResemble, []string{"res1", "res2"})
+}
+
+func testSendToNodeFull() {
+	clearOutbox()
+	lowCapacity := Options{Priority: PriorityLow}.capacity()
+	for i := int64(0); i < lowCapacity; i++ {
+		queueForUT("pending", PriorityNormal)
+	}
+	patches := gomonkey.ApplyFuncReturn(triggerFlush)
+	defer patches.Reset()
+	convey.So(SendToNode(newNodeMsgForUT("low"), Options{Priority: PriorityLow}), convey.ShouldNotBeNil)
+	convey.So(SendToNode(newNodeMsgForUT("high"), Options{Priority: PriorityHigh}), convey.ShouldBeNil)
+	clearOutbox()
+}
+
+func testSendToNodeErrMsg() {
+	convey.So(SendToNode(nil, Options{}), convey.ShouldNotBeNil)
+	convey.So(SendToNode(newMsgWithContentForUT("payload"), Options{}), convey.ShouldNotBeNil)
+}
+
+func TestFlushNode(t *testing.T) {
+	convey.Convey("pending messages are delivered in order", t, testFlushNode)
+	convey.Convey("flush stops at the first failed message", t, testFlushNodeErrDeliver)
+	convey.Convey("expired messages are dropped", t, testCleanExpiredMessages)
+}
+
+func prepareOutbox() {
+	clearOutbox()
+	queueForUT("low", PriorityLow)
+	queueForUT("normal1", PriorityNormal)
+	queueForUT("high", PriorityHigh)
+	queueForUT("normal2", PriorityNormal)
+}
+
+func testFlushNode() {
+	prepareOutbox()
+	records, err := queryPendingBySn(testSn)
+	convey.So(err, convey.ShouldBeNil)
+	var delivered []string
+	patches := gomonkey.ApplyFunc(deliver, func(msg *model.Message) error {
+		convey.So(msg.GetNodeId(), convey.ShouldEqual, testSn)
+		convey.So(msg.GetSource(), convey.ShouldEqual, common.ConfigManagerName)
+		convey.So(msg.GetId(), convey.ShouldEqual, records[len(delivered)].MsgId)
+		delivered = append(delivered, msg.GetResource())
+		return nil
+	})
+	defer patches.Reset()
+
+	FlushNode(testSn)
+	convey.So(delivered, convey.ShouldResemble, []string{"low", "normal1", "high", "normal2"})
+	convey.So(pendingResources(), convey.ShouldBeEmpty)
+}
+
+func testFlushNodeErrDeliver() {
+	prepareOutbox()
+	var count int
+	patches := gomonkey.ApplyFunc(deliver, func(msg *model.Message) error {
+		count++
+		if count > 1 {
+			return errors.New("connection lost")
+		}
+		return nil
+	})
+	defer patches.Reset()
+
+	FlushNode(testSn)
+	convey.So(pendingResources(), convey.ShouldResemble, []string{"normal1", "high", "normal2"})
+	records, err := queryPendingBySn(testSn)
+	convey.So(err, convey.ShouldBeNil)
+	convey.So(records[0].AttemptCount, convey.ShouldEqual, 1)
+	convey.So(records[0].LastError, convey.ShouldEqual, "connection lost")
+	convey.So(records[1].AttemptCount, convey.ShouldEqual, 0)
+}
+
+func testCleanExpiredMessages() {
+	prepareOutbox()
+	err := database.GetDb().Model(&outboxMessage{}).Where("resource = ?", "low").
+		Update("expire_at", time.Now().Add(-time.Minute).Unix()).Error
+	convey.So(err, convey.ShouldBeNil)
+
+	cleanExpiredMessages()
+	convey.So(pendingResources(), convey.ShouldResemble, []string{"normal1", "high", "normal2"})
+}
+
+func TestDeliver(t *testing.T) {
+	convey.Convey("deliver should be success when cloud hub acks", t, func() {
+		patches := gomonkey.ApplyFuncReturn(modulemgr.SendSyncMessage, newMsgWithContentForUT(common.OK), nil)
+		defer patches.Reset()
+		msg := newNodeMsgForUT("res")
+		convey.So(deliver(msg), convey.ShouldBeNil)
+		convey.So(msg.GetNeedAck(), convey.ShouldBeFalse)
+	})
+
+	convey.Convey("message should need ack when node supports outbox ack", t, func() {
+		patches := gomonkey.ApplyFuncReturn(modulemgr.SendSyncMessage, newMsgWithContentForUT(common.OK), nil).
+			ApplyFuncReturn(nodeprotocol.CheckSupport, nil)
+		defer patches.Reset()
+		msg := newNodeMsgForUT("res")
+		convey.So(deliver(msg), convey.ShouldBeNil)
+		convey.So(msg.GetNeedAck(), convey.ShouldBeTrue)
+	})
+
+	convey.Convey("deliver should be failed when node is not connected", t, func() {
+		patches := gomonkey.ApplyFuncReturn(modulemgr.SendSyncMessage, newMsgWithContentForUT(common.FAIL), nil)
+		defer patches.Reset()
+		convey.So(deliver(newNodeMsgForUT("res")), convey.ShouldNotBeNil)
+	})
+
+	convey.Convey("deliver should be failed when ack times out", t, func() {
+		patches := gomonkey.ApplyFuncReturn(modulemgr.SendSyncMessage, nil, errors.New("timeout"))
+		defer patches.Reset()
+		convey.So(deliver(newNodeMsgForUT("res")), convey.ShouldNotBeNil)
+	})
+}
+
+func TestQueryPendingMessages(t *testing.T) {
+	convey.Convey("query pending messages should be success", t, func() {
+		prepareOutbox()
+		resp := queryPendingMessages(newMsgWithContentForUT(testSn))
+		convey.So(resp.Status, convey.ShouldEqual, common.Success)
+		result, ok := resp.Data.(NodePendingMessages)
+		convey.So(ok, convey.ShouldBeTrue)
+		convey.So(result.TotalCount, convey.ShouldEqual, len(result.Messages))
+		convey.So(result.Messages[0].Resource, convey.ShouldEqual, "low")
+	})
+
+	convey.Convey("query pending messages should be failed, invalid serial number", t, func() {
+		resp := queryPendingMessages(newMsgWithContentForUT("-invalid sn"))
+		convey.So(resp.Status, convey.ShouldEqual, common.ErrorParamInvalid)
+	})
+}
@@ -0,0 +1,75 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package outbox query pending messages of edge node
+package outbox
+
+import (
+	"huawei.com/mindx/common/checker"
+	"huawei.com/mindx/common/hwlog"
+	"huawei.com/mindx/common/modulemgr/model"
+
+	"huawei.com/mindxedge/base/common"
+)
+
+const snPattern = `^[a-zA-Z0-9]([-_a-zA-Z0-9]{0,62}[a-zA-Z0-9])?$`
+
+// PendingMessage pending message shown to user, content is not shown since it may contain sensitive data
+type PendingMessage struct {
+	Id           int64  `json:"id"`
+	Priority     int    `json:"priority"`
+	Option       string `json:"option"`
+	Resource     string `json:"resource"`
+	CreatedAt    int64  `json:"createdAt"`
+	ExpireAt     int64  `json:"expireAt"`
+	AttemptCount int64  `json:"attemptCount"`
+	LastError    string `json:"lastError"`
+}
+
+// NodePendingMessages pending messages of one edge node in delivery order
+type NodePendingMessages struct {
+	SerialNumber string           `json:"serialNumber"`
+	TotalCount   int              `json:"totalCount"`
+	Messages     []PendingMessage `json:"messages"`
+}
+
+func queryPendingMessages(msg *model.Message) common.RespMsg {
+	hwlog.RunLog.Info("start query pending outbox messages")
+	var sn string
+	if err := msg.ParseContent(&sn); err != nil {
+		hwlog.RunLog.Errorf("query pending outbox messages failed: parse content failed: %v", err)
+		return common.RespMsg{Status: common.ErrorParamConvert, Msg: "parse content failed", Data: nil}
+	}
+	if checkResult := checker.GetRegChecker("", snPattern, true).Check(sn); !checkResult.Result {
+		hwlog.RunLog.Errorf("query pending outbox messages failed: %s", checkResult.Reason)
+		return common.RespMsg{Status: common.ErrorParamInvalid, Msg: checkResult.Reason, Data: nil}
+	}
+
+	records, err := queryPendingBySn(sn)
+	if err != nil {
+		hwlog.RunLog.Errorf("query pending outbox messages of node [%s] failed: %v", sn, err)
+		return common.RespMsg{Status: common.ErrorGetOutboxMessages, Msg: "", Data: nil}
+	}
+	result := NodePendingMessages{SerialNumber: sn, TotalCount: len(records), Messages: []PendingMessage{}}
+	for _, record := range records {
+		result.Messages = append(result.Messages, PendingMessage{
+			Id:           record.Id,
+			Priority:     record.Priority,
+			Option:       record.Option,
+			Resource:     record.Resource,
+			CreatedAt:    record.CreatedAt,
+			ExpireAt:     record.ExpireAt,
+			AttemptCount: record.AttemptCount,
+			LastError:    record.LastError,
+		})
+	}
+	hwlog.RunLog.Info("query pending outbox messages success")
+	return common.RespMsg{Status: common.Success, Msg: "", Data: result}
+}
@@ -0,0 +1,53 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package portforward for package main test
+package portforward
+
+import (
+	"encoding/json"
+	"testing"
+
+	"huawei.com/mindx/common/modulemgr/model"
+	"huawei.com/mindx/common/test"
+
+	"edge-manager/pkg/types"
+)
+
+const (
+	testUser = "[admin]"
+	testIp   = "10.0.0.1"
+)
+
+func TestMain(m *testing.M) {
+	tcBase := &test.TcBase{}
+	test.RunWithPatches(tcBase, m, nil)
+}
+
+func newCallerMsgForUT(user string, body interface{}) *model.Message {
+	req := types.CallerReq{User: user, Ip: testIp}
+	if str, ok := body.(string); ok {
+		req.Body = str
+	} else {
+		data, err := json.Marshal(body)
+		if err != nil {
+			panic(err)
+		}
+		req.Body = string(data)
+	}
+	msg, err := model.NewMessage()
+	if err != nil {
+		panic(err)
+	}
+	if err = msg.FillContent(req); err != nil {
+		panic(err)
+	}
+	return msg
+}
@@ -0,0 +1,122 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package portforward to init port forward module
+package portforward
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"huawei.com/mindx/common/hwlog"
+	"huawei.com/mindx/common/modulemgr"
+	"huawei.com/mindx/common/modulemgr/model"
+
+	"huawei.com/mindxedge/base/common"
+
+	"edge-manager/pkg/constants"
+)
+
+type handlerFunc func(message *model.Message) common.RespMsg
+
+type portForwardManager struct {
+	enable bool
+	ctx    context.Context
+}
+
+// NewPortForwardManager create port forward manager
+func NewPortForwardManager(enable bool) model.Module {
+	return &portForwardManager{
+		enable: enable,
+		ctx:    context.Background(),
+	}
+}
+
+func (pm *portForwardManager) Name() string {
+	return constants.PortForwardName
+}
+
+func (pm *portForwardManager) Enable() bool {
+	return pm.enable
+}
+
+func (pm *portForwardManager) Start() {
+	go periodCheckTunnels(pm.ctx)
+	for {
+		select {
+		case _, ok := <-pm.ctx.Done():
+			if !ok {
+				hwlog.RunLog.Info("catch stop signal channel is closed")
+			}
+			hwlog.RunLog.Info("has listened stop signal")
+			return
+		default:
+		}
+
+		req, err := modulemgr.ReceiveMessage(pm.Name())
+		if err != nil {
+			hwlog.RunLog.Errorf("%s receive request failed", pm.Name())
+			continue
+		}
+
+		go pm.dispatch(req)
+	}
+}
+
+func (pm *portForwardManager) dispatch(req *model.Message) {
+	// data reported by edge is asynchronous, no response is needed
+	if req.GetOption() == common.OptReport && req.GetResource() == constants.ResPortForwardData {
+		handleEdgeData(req)
+		return
+	}
+	method, exist := handlerFuncMap[common.Combine(req.GetOption(), req.GetResource())]
+	if !exist {
+		hwlog.RunLog.Errorf("handler func is not exist, option: %s, resource: %s", req.GetOption(),
+			req.GetResource())
+		return
+	}
+	res := method(req)
+	resp, err := req.NewResponse()
+	if err != nil {
+		hwlog.RunLog.Errorf("%s new response failed", pm.Name())
+		return
+	}
+	if err = resp.FillContent(res); err != nil {
+		hwlog.RunLog.Errorf("%s fill content failed: %v", pm.Name(), err)
+		return
+	}
+	if err = modulemgr.SendMessage(resp); err != nil {
+		hwlog.RunLog.Errorf("%s send response failed", pm.Name())
+		return
+	}
+}
+
+var handlerFuncMap = map[string]handlerFunc{
+	common.Combine(http.MethodPost, constants.PortForwardUrlPrefix):          openTunnel,
+	common.Combine(http.MethodPost, constants.PortForwardUrlPrefix+"/close"): closeTunnel,
+	common.Combine(http.MethodGet, constants.PortForwardUrlPrefix):           queryTunnels,
+	common.Combine(http.MethodGet, constants.PortForwardUrlPrefix+"/list"):   queryTunnels,
+}
+
+// periodCheckTunnels closes the tunnels which have no stream for a long time
+func periodCheckTunnels(ctx context.Context) {
+	ticker := time.NewTicker(tunnelCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			hwlog.RunLog.Info("port forward check goroutine is stopped")
+			return
+		case <-ticker.C:
+			tunnels.checkTunnels()
+		}
+	}
+}
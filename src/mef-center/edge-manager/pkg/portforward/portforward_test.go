@@ -0,0 +1,324 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package portforward test for port forward tunnels
+package portforward
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/smartystreets/goconvey/convey"
+
+	"huawei.com/mindx/common/modulemgr/model"
+
+	"huawei.com/mindxedge/base/common"
+
+	"edge-manager/pkg/constants"
+)
+
+const (
+	testSn          = "2102312NSF10K8000130"
+	testWaitTimeout = 5 * time.Second
+)
+
+var validOpenReq = OpenReq{SerialNumber: testSn, Target: "host:8080"}
+
+func resetTunnels() {
+	tunnels.lock.Lock()
+	tunnels.tunnels = make(map[string]*tunnel)
+	tunnels.streams = make(map[string]*stream)
+	tunnels.lock.Unlock()
+}
+
+func newEdgeDataMsgForUT(sn string, data edgeForwardData) *model.Message {
+	msg, err := model.NewMessage()
+	if err != nil {
+		panic(err)
+	}
+	msg.SetPeerInfo(model.MsgPeerInfo{Sn: sn})
+	if err = msg.FillContent(data, true); err != nil {
+		panic(err)
+	}
+	return msg
+}
+
+func openTunnelForUT() OpenResp {
+	resp := openTunnel(newCallerMsgForUT(testUser, validOpenReq))
+	convey.So(resp.Status, convey.ShouldEqual, common.Success)
+	openResp, ok := resp.Data.(OpenResp)
+	convey.So(ok, convey.ShouldBeTrue)
+	return openResp
+}
+
+// newStreamServerForUT the user is set as the authenticated user of nginx does
+func newStreamServerForUT(user string) *httptest.Server {
+	gin.SetMode(gin.ReleaseMode)
+	engine := gin.New()
+	engine.Use(func(c *gin.Context) {
+		c.Request.Header["user"] = []string{user}
+	})
+	engine.GET(constants.PortForwardStreamUrl, HandleStream)
+	return httptest.NewServer(engine)
+}
+
+func dialStreamForUT(server *httptest.Server, address string) (*websocket.Conn, *http.Response, error) {
+	return websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+address, nil)
+}
+
+// readStreamForUT read the binary messages until the websocket is closed normally
+func readStreamForUT(conn *websocket.Conn) (string, error) {
+	if err := conn.SetReadDeadline(time.Now().Add(testWaitTimeout)); err != nil {
+		return "", err
+	}
+	var received []byte
+	for {
+		_, data, err := conn.ReadMessage()
+		if websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+			return string(received), nil
+		}
+		if err != nil {
+			return string(received), err
+		}
+		received = append(received, data...)
+	}
+}
+
+func patchNotifyEdge() (*gomonkey.Patches, <-chan edgeForwardRequest) {
+	sent := make(chan edgeForwardRequest, maxPendingFrames)
+	patches := gomonkey.ApplyFunc(notifyEdge, func(sn string, req edgeForwardRequest, waitSent bool) error {
+		sent <- req
+		return nil
+	})
+	return patches, sent
+}
+
+func waitEdgeRequest(sent <-chan edgeForwardRequest, action string) (edgeForwardRequest, bool) {
+	timer := time.NewTimer(testWaitTimeout)
+	defer timer.Stop()
+	for {
+		select {
+		case req := <-sent:
+			if req.Action == action {
+				return req, true
+			}
+		case <-timer.C:
+			return edgeForwardRequest{}, false
+		}
+	}
+}
+
+func TestOpenTunnel(t *testing.T) {
+	patches, _ := patchNotifyEdge()
+	defer patches.Reset()
+
+	server := newStreamServerForUT("admin")
+	defer server.Close()
+
+	convey.Convey("open tunnel should be success", t, func() {
+		resetTunnels()
+		openResp := openTunnelForUT()
+		convey.So(idReg.MatchString(openResp.TunnelId), convey.ShouldBeTrue)
+		convey.So(openResp.Address, convey.ShouldStartWith, constants.PortForwardStreamUrl)
+		conn, _, err := dialStreamForUT(server, openResp.Address)
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(conn.Close(), convey.ShouldBeNil)
+	})
+
+	convey.Convey("open tunnel should be failed, invalid param", t, func() {
+		invalidReqs := []OpenReq{
+			{SerialNumber: "../sn", Target: "host:8080"},
+			{SerialNumber: testSn, Target: "vm:8080"},
+			{SerialNumber: testSn, Target: "host:0"},
+			{SerialNumber: testSn, Target: "host:65536"},
+			{SerialNumber: testSn, Target: "host:8080", Container: "3f2a9c"},
+			{SerialNumber: testSn, Target: "container:8080"},
+			{SerialNumber: testSn, Target: "container:8080", Container: "-it"},
+		}
+		for _, req := range invalidReqs {
+			resp := openTunnel(newCallerMsgForUT(testUser, req))
+			convey.So(resp.Status, convey.ShouldEqual, common.ErrorParamInvalid)
+		}
+		resp := openTunnel(newCallerMsgForUT(testUser, "not json"))
+		convey.So(resp.Status, convey.ShouldEqual, common.ErrorParamConvert)
+	})
+
+	convey.Convey("stream can only be opened by the user who opened the tunnel", t, func() {
+		resetTunnels()
+		openResp := openTunnelForUT()
+		otherServer := newStreamServerForUT("other")
+		defer otherServer.Close()
+		_, resp, err := dialStreamForUT(otherServer, openResp.Address)
+		convey.So(err, convey.ShouldNotBeNil)
+		convey.So(resp, convey.ShouldNotBeNil)
+		convey.So(resp.StatusCode, convey.ShouldNotEqual, http.StatusSwitchingProtocols)
+		_, _, err = dialStreamForUT(server, constants.PortForwardStreamUrl+"?tunnelId=../x")
+		convey.So(err, convey.ShouldNotBeNil)
+	})
+
+	convey.Convey("tunnels of one node are limited", t, func() {
+		resetTunnels()
+		for i := 0; i < maxTunnelsPerNode; i++ {
+			openTunnelForUT()
+		}
+		resp := openTunnel(newCallerMsgForUT(testUser, validOpenReq))
+		convey.So(resp.Status, convey.ShouldEqual, common.ErrorPortForwardTunnel)
+	})
+}
+
+func TestStream(t *testing.T) {
+	patches, sent := patchNotifyEdge()
+	defer patches.Reset()
+	server := newStreamServerForUT("admin")
+	defer server.Close()
+
+	convey.Convey("data is forwarded in order in both directions", t, func() {
+		resetTunnels()
+		conn, _, err := dialStreamForUT(server, openTunnelForUT().Address)
+		convey.So(err, convey.ShouldBeNil)
+		defer conn.Close()
+		connectReq, ok := waitEdgeRequest(sent, actionConnect)
+		convey.So(ok, convey.ShouldBeTrue)
+		convey.So(connectReq.Target, convey.ShouldEqual, validOpenReq.Target)
+		streamId := connectReq.StreamId
+
+		convey.So(conn.WriteMessage(websocket.BinaryMessage, []byte("ping")), convey.ShouldBeNil)
+		dataReq, ok := waitEdgeRequest(sent, actionData)
+		convey.So(ok, convey.ShouldBeTrue)
+		convey.So(dataReq.Seq, convey.ShouldEqual, 1)
+		convey.So(string(dataReq.Data), convey.ShouldEqual, "ping")
+
+		handleEdgeData(newEdgeDataMsgForUT(testSn, edgeForwardData{StreamId: streamId, Seq: 2, Data: []byte("world")}))
+		handleEdgeData(newEdgeDataMsgForUT("other-node", edgeForwardData{StreamId: streamId, Seq: 3, Closed: true}))
+		handleEdgeData(newEdgeDataMsgForUT(testSn, edgeForwardData{StreamId: streamId, Seq: 1,
+			Data: []byte("hello ")}))
+		handleEdgeData(newEdgeDataMsgForUT(testSn, edgeForwardData{StreamId: streamId, Seq: 3, Closed: true,
+			Reason: "connection closed by target"}))
+		received, err := readStreamForUT(conn)
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(received, convey.ShouldEqual, "hello world")
+		convey.So(len(tunnels.streams), convey.ShouldEqual, 0)
+	})
+
+	convey.Convey("end of client data is sent to edge after the data", t, func() {
+		resetTunnels()
+		conn, _, err := dialStreamForUT(server, openTunnelForUT().Address)
+		convey.So(err, convey.ShouldBeNil)
+		_, ok := waitEdgeRequest(sent, actionConnect)
+		convey.So(ok, convey.ShouldBeTrue)
+		convey.So(conn.WriteMessage(websocket.BinaryMessage, []byte("GET / HTTP/1.0\r\n\r\n")),
+			convey.ShouldBeNil)
+		convey.So(conn.WriteMessage(websocket.BinaryMessage, nil), convey.ShouldBeNil)
+		closeReq, ok := waitEdgeRequest(sent, actionClose)
+		convey.So(ok, convey.ShouldBeTrue)
+		convey.So(closeReq.Seq, convey.ShouldEqual, 2)
+		convey.So(conn.Close(), convey.ShouldBeNil)
+	})
+
+	convey.Convey("unknown stream reported by edge is closed", t, func() {
+		resetTunnels()
+		unknownId := "0123456789abcdef0123456789abcdef"
+		handleEdgeData(newEdgeDataMsgForUT(testSn, edgeForwardData{StreamId: unknownId, Seq: 1, Data: []byte("x")}))
+		closeReq, ok := waitEdgeRequest(sent, actionClose)
+		convey.So(ok, convey.ShouldBeTrue)
+		convey.So(closeReq, convey.ShouldResemble, edgeForwardRequest{StreamId: unknownId, Action: actionClose})
+	})
+}
+
+func TestStreamWindow(t *testing.T) {
+	convey.Convey("sending waits for ack when the window is full", t, func() {
+		s := newStream("0123456789abcdef0123456789abcdef", &tunnel{}, nil)
+		s.sentLen = windowSize
+		result := make(chan bool, 1)
+		go func() { result <- s.waitWindow() }()
+		time.Sleep(100 * time.Millisecond)
+		convey.So(len(result), convey.ShouldEqual, 0)
+		convey.So(s.receive(&edgeForwardData{StreamId: s.id, Ack: windowSize / 2}), convey.ShouldBeNil)
+		convey.So(<-result, convey.ShouldBeTrue)
+	})
+}
+
+func TestStreamConnectFailed(t *testing.T) {
+	convey.Convey("stream is finished when edge can not be reached", t, func() {
+		resetTunnels()
+		patches := gomonkey.ApplyFuncReturn(notifyEdge, errors.New("node is disconnected"))
+		defer patches.Reset()
+		server := newStreamServerForUT("admin")
+		defer server.Close()
+		conn, _, err := dialStreamForUT(server, openTunnelForUT().Address)
+		convey.So(err, convey.ShouldBeNil)
+		defer conn.Close()
+		received, err := readStreamForUT(conn)
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(received, convey.ShouldBeEmpty)
+	})
+}
+
+func TestCloseTunnel(t *testing.T) {
+	patches, sent := patchNotifyEdge()
+	defer patches.Reset()
+	server := newStreamServerForUT("admin")
+	defer server.Close()
+
+	convey.Convey("tunnel can only be closed by the user who opened it", t, func() {
+		resetTunnels()
+		openResp := openTunnelForUT()
+		resp := closeTunnel(newCallerMsgForUT("[other]", CloseReq{TunnelId: openResp.TunnelId}))
+		convey.So(resp.Status, convey.ShouldEqual, common.ErrorPortForwardTunnel)
+		resp = queryTunnels(newCallerMsgForUT("[other]", openResp.TunnelId))
+		convey.So(resp.Status, convey.ShouldEqual, common.ErrorPortForwardTunnel)
+		resp = queryTunnels(newCallerMsgForUT(testUser, ""))
+		convey.So(resp.Status, convey.ShouldEqual, common.Success)
+		convey.So(len(resp.Data.([]TunnelStatus)), convey.ShouldEqual, 1)
+	})
+
+	convey.Convey("streams are aborted when tunnel is closed", t, func() {
+		resetTunnels()
+		openResp := openTunnelForUT()
+		conn, _, err := dialStreamForUT(server, openResp.Address)
+		convey.So(err, convey.ShouldBeNil)
+		defer conn.Close()
+		connectReq, ok := waitEdgeRequest(sent, actionConnect)
+		convey.So(ok, convey.ShouldBeTrue)
+
+		resp := closeTunnel(newCallerMsgForUT(testUser, CloseReq{TunnelId: openResp.TunnelId}))
+		convey.So(resp.Status, convey.ShouldEqual, common.Success)
+		closeReq, ok := waitEdgeRequest(sent, actionClose)
+		convey.So(ok, convey.ShouldBeTrue)
+		convey.So(closeReq, convey.ShouldResemble,
+			edgeForwardRequest{StreamId: connectReq.StreamId, Action: actionClose})
+		_, err = readStreamForUT(conn)
+		convey.So(err, convey.ShouldBeNil)
+		_, _, err = dialStreamForUT(server, openResp.Address)
+		convey.So(err, convey.ShouldNotBeNil)
+		resp = queryTunnels(newCallerMsgForUT(testUser, openResp.TunnelId))
+		convey.So(resp.Status, convey.ShouldEqual, common.ErrorPortForwardTunnel)
+	})
+}
+
+func TestCheckTunnels(t *testing.T) {
+	convey.Convey("tunnel without stream for a long time is closed", t, func() {
+		resetTunnels()
+		openResp := openTunnelForUT()
+		tunnels.lock.Lock()
+		tunnels.tunnels[openResp.TunnelId].lastActive = time.Now().Add(-tunnelIdleTimeout - time.Second).UnixNano()
+		tunnels.lock.Unlock()
+		tunnels.checkTunnels()
+		resp := queryTunnels(newCallerMsgForUT(testUser, openResp.TunnelId))
+		convey.So(resp.Status, convey.ShouldEqual, common.ErrorPortForwardTunnel)
+	})
+}
@@ -0,0 +1,244 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package portforward streams of port forward, the streams of a node are multiplexed over its cloudhub connection
+package portforward
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+
+	"huawei.com/mindx/common/hwlog"
+
+	"huawei.com/mindxedge/base/common"
+)
+
+const (
+	actionConnect = "connect"
+	actionData    = "data"
+	actionAck     = "ack"
+	actionClose   = "close"
+
+	windowSize       = 256 * common.KB
+	maxFrameLen      = 16 * common.KB
+	maxPendingFrames = 64
+)
+
+// edgeForwardRequest request sent to edge. The data frames and the close frame are numbered from 1 in order,
+// a close frame without seq aborts the stream immediately
+type edgeForwardRequest struct {
+	StreamId  string `json:"streamId"`
+	Action    string `json:"action"`
+	Target    string `json:"target,omitempty"`
+	Container string `json:"container,omitempty"`
+	Seq       uint64 `json:"seq,omitempty"`
+	Data      []byte `json:"data,omitempty"`
+	Ack       uint64 `json:"ack,omitempty"`
+}
+
+// edgeForwardData data reported by edge, it is numbered in the same way as request. Ack is the count of bytes
+// which have been written to the target on edge
+type edgeForwardData struct {
+	StreamId string `json:"streamId"`
+	Seq      uint64 `json:"seq,omitempty"`
+	Data     []byte `json:"data,omitempty"`
+	Ack      uint64 `json:"ack,omitempty"`
+	Closed   bool   `json:"closed,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+type stream struct {
+	id        string
+	tunnel    *tunnel
+	conn      io.ReadWriteCloser
+	done      chan struct{}
+	closeOnce sync.Once
+
+	// receiving side, frames from edge may arrive out of order
+	recvLock sync.Mutex
+	nextSeq  uint64
+	pending  map[uint64]*edgeForwardData
+	written  uint64
+	ackSent  uint64
+
+	// sending side, no more than windowSize bytes are in flight
+	sentLen   uint64
+	peerAcked uint64
+	ackCh     chan struct{}
+}
+
+func newStream(id string, t *tunnel, conn io.ReadWriteCloser) *stream {
+	return &stream{
+		id:      id,
+		tunnel:  t,
+		conn:    conn,
+		done:    make(chan struct{}),
+		nextSeq: 1,
+		pending: make(map[uint64]*edgeForwardData),
+		ackCh:   make(chan struct{}, 1),
+	}
+}
+
+// serve connects the target on edge, then forwards the data from client to edge until the client finishes sending.
+// The data from edge is still forwarded to client until edge closes the stream
+func (s *stream) serve() {
+	sn := s.tunnel.serialNumber
+	req := edgeForwardRequest{StreamId: s.id, Action: actionConnect, Target: s.tunnel.target,
+		Container: s.tunnel.container}
+	if err := notifyEdge(sn, req, true); err != nil {
+		s.finish(fmt.Sprintf("connect to edge failed: %v", err))
+		return
+	}
+	hwlog.RunLog.Infof("port forward stream [%s] of tunnel [%s] started", s.id, s.tunnel.id)
+
+	seq := uint64(1)
+	buf := make([]byte, maxFrameLen)
+	for s.waitWindow() {
+		n, err := s.conn.Read(buf)
+		if n > 0 {
+			data := make([]byte, n)
+			copy(data, buf[:n])
+			req = edgeForwardRequest{StreamId: s.id, Action: actionData, Seq: seq, Data: data}
+			if sendErr := notifyEdge(sn, req, true); sendErr != nil {
+				s.abort(fmt.Sprintf("send data to edge failed: %v", sendErr))
+				return
+			}
+			seq++
+			atomic.AddUint64(&s.sentLen, uint64(n))
+			atomic.AddUint64(&s.tunnel.bytesOut, uint64(n))
+		}
+		if err != nil {
+			break
+		}
+	}
+	if s.isDone() {
+		return
+	}
+	if err := notifyEdge(sn, edgeForwardRequest{StreamId: s.id, Action: actionClose, Seq: seq}, false); err != nil {
+		s.abort(fmt.Sprintf("notify edge the end of data failed: %v", err))
+	}
+}
+
+// receive buffers the frame until all the frames before it are received, then writes them to client in order
+func (s *stream) receive(data *edgeForwardData) error {
+	if data.Seq == 0 {
+		if data.Ack > 0 {
+			s.updatePeerAcked(data.Ack)
+		}
+		if data.Closed {
+			s.finish(data.Reason)
+		}
+		return nil
+	}
+
+	s.recvLock.Lock()
+	defer s.recvLock.Unlock()
+	if data.Seq < s.nextSeq {
+		return nil
+	}
+	if len(data.Data) > maxFrameLen {
+		return fmt.Errorf("frame should not be longer than %d", maxFrameLen)
+	}
+	if len(s.pending) >= maxPendingFrames {
+		return errors.New("too many frames are out of order")
+	}
+	s.pending[data.Seq] = data
+	for {
+		frame, ok := s.pending[s.nextSeq]
+		if !ok {
+			return nil
+		}
+		delete(s.pending, s.nextSeq)
+		s.nextSeq++
+		if frame.Closed {
+			s.finish(frame.Reason)
+			return nil
+		}
+		if err := s.write(frame.Data); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *stream) write(data []byte) error {
+	if _, err := s.conn.Write(data); err != nil {
+		return fmt.Errorf("write to client failed: %v", err)
+	}
+	written := atomic.AddUint64(&s.written, uint64(len(data)))
+	atomic.AddUint64(&s.tunnel.bytesIn, uint64(len(data)))
+	if written-s.ackSent >= windowSize/4 {
+		s.ackSent = written
+		req := edgeForwardRequest{StreamId: s.id, Action: actionAck, Ack: written}
+		if err := notifyEdge(s.tunnel.serialNumber, req, false); err != nil {
+			return fmt.Errorf("send ack to edge failed: %v", err)
+		}
+	}
+	return nil
+}
+
+func (s *stream) updatePeerAcked(acked uint64) {
+	for {
+		old := atomic.LoadUint64(&s.peerAcked)
+		if acked <= old || atomic.CompareAndSwapUint64(&s.peerAcked, old, acked) {
+			break
+		}
+	}
+	select {
+	case s.ackCh <- struct{}{}:
+	default:
+	}
+}
+
+// waitWindow waits until the data in flight is less than the window, returns false if stream is finished
+func (s *stream) waitWindow() bool {
+	for atomic.LoadUint64(&s.sentLen)-atomic.LoadUint64(&s.peerAcked) >= windowSize {
+		select {
+		case <-s.ackCh:
+		case <-s.done:
+			return false
+		}
+	}
+	return !s.isDone()
+}
+
+func (s *stream) isDone() bool {
+	select {
+	case <-s.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// abort closes the stream on edge and finishes it
+func (s *stream) abort(reason string) {
+	if s.isDone() {
+		return
+	}
+	if err := notifyEdge(s.tunnel.serialNumber, edgeForwardRequest{StreamId: s.id, Action: actionClose},
+		false); err != nil {
+		hwlog.RunLog.Warnf("notify edge to close port forward stream [%s] failed: %v", s.id, err)
+	}
+	s.finish(reason)
+}
+
+// finish closing connection interrupts the pending read and write of client
+func (s *stream) finish(reason string) {
+	s.closeOnce.Do(func() {
+		close(s.done)
+		closeConn(s.conn)
+		tunnels.removeStream(s)
+		hwlog.RunLog.Infof("port forward stream [%s] of tunnel [%s] finished, sent %d bytes, received %d bytes: %s",
+			s.id, s.tunnel.id, atomic.LoadUint64(&s.sentLen), atomic.LoadUint64(&s.written), reason)
+	})
+}
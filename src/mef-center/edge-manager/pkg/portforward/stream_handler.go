@@ -0,0 +1,125 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package portforward websocket carrying the streams of port forward tunnels
+package portforward
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+
+	"huawei.com/mindx/common/hwlog"
+
+	"huawei.com/mindxedge/base/common"
+)
+
+const (
+	queryTunnelId      = "tunnelId"
+	wsBufferSize       = maxFrameLen
+	wsCloseWaitTime    = time.Second
+	wsHandshakeTimeout = 10 * time.Second
+)
+
+var upgrader = websocket.Upgrader{
+	HandshakeTimeout: wsHandshakeTimeout,
+	ReadBufferSize:   wsBufferSize,
+	WriteBufferSize:  wsBufferSize,
+}
+
+// wsConn the data of a stream is carried by the binary messages, an empty binary message from client means the end
+// of client data, and the data from edge can still be received until the websocket is closed
+type wsConn struct {
+	conn      *websocket.Conn
+	reader    io.Reader
+	msgLen    int
+	eof       bool
+	writeLock sync.Mutex
+}
+
+func (w *wsConn) Read(p []byte) (int, error) {
+	for !w.eof {
+		if w.reader == nil {
+			msgType, reader, err := w.conn.NextReader()
+			if err != nil {
+				return 0, err
+			}
+			if msgType != websocket.BinaryMessage {
+				continue
+			}
+			w.reader = reader
+			w.msgLen = 0
+		}
+		n, err := w.reader.Read(p)
+		w.msgLen += n
+		if err == io.EOF {
+			w.eof = w.msgLen == 0
+			w.reader = nil
+			err = nil
+		}
+		if n > 0 || err != nil {
+			return n, err
+		}
+	}
+	return 0, io.EOF
+}
+
+func (w *wsConn) Write(p []byte) (int, error) {
+	w.writeLock.Lock()
+	defer w.writeLock.Unlock()
+	if err := w.conn.WriteMessage(websocket.BinaryMessage, p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Close sends the close message before closing, so that client knows the stream ends normally
+func (w *wsConn) Close() error {
+	msg := websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")
+	if err := w.conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(wsCloseWaitTime)); err != nil {
+		hwlog.RunLog.Warnf("send close message of websocket failed: %v", err)
+	}
+	return w.conn.Close()
+}
+
+// HandleStream forwards the websocket as a stream of the tunnel, the websocket is opened through the authenticated
+// https session and only the user who opened the tunnel can use it
+func HandleStream(c *gin.Context) {
+	id := c.Query(queryTunnelId)
+	if !idReg.MatchString(id) {
+		hwlog.RunLog.Error("open port forward stream failed: tunnel id is invalid")
+		common.ConstructResp(c, common.ErrorParamInvalid, "tunnel id is invalid", nil)
+		return
+	}
+	t, err := tunnels.get(id, fmt.Sprintf("%s", c.Request.Header["user"]))
+	if err != nil {
+		hwlog.RunLog.Errorf("open port forward stream failed: %v", err)
+		common.ConstructResp(c, common.ErrorPortForwardTunnel, err.Error(), nil)
+		return
+	}
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// the upgrader has responded the error to client
+		hwlog.RunLog.Errorf("upgrade port forward stream of tunnel [%s] failed: %v", id, err)
+		return
+	}
+	ws := &wsConn{conn: conn}
+	s, err := tunnels.addStream(t, ws)
+	if err != nil {
+		hwlog.RunLog.Errorf("open port forward stream of tunnel [%s] failed: %v", id, err)
+		closeConn(ws)
+		return
+	}
+	s.serve()
+}
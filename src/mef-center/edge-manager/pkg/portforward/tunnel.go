@@ -0,0 +1,238 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package portforward tunnels of port forward, each websocket opened by the owner of a tunnel is forwarded as
+// a stream to the target on edge
+package portforward
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"huawei.com/mindx/common/hwlog"
+	"huawei.com/mindx/common/modulemgr"
+	"huawei.com/mindx/common/modulemgr/model"
+
+	"huawei.com/mindxedge/base/common"
+
+	"edge-manager/pkg/constants"
+)
+
+const (
+	maxTunnels          = 16
+	maxTunnelsPerNode   = 4
+	maxStreamsPerTunnel = 8
+	tunnelIdleTimeout   = 30 * time.Minute
+	tunnelCheckInterval = time.Minute
+	idLen               = 16
+)
+
+// tunnelInfo identity of a tunnel, used for recording operation log
+type tunnelInfo struct {
+	id           string
+	serialNumber string
+	target       string
+	container    string
+	user         string
+	ip           string
+}
+
+type tunnel struct {
+	tunnelInfo
+	createdAt  time.Time
+	lastActive int64
+	bytesIn    uint64
+	bytesOut   uint64
+}
+
+type tunnelMgr struct {
+	lock    sync.Mutex
+	tunnels map[string]*tunnel
+	streams map[string]*stream
+}
+
+var tunnels = &tunnelMgr{tunnels: make(map[string]*tunnel), streams: make(map[string]*stream)}
+
+func newId() (string, error) {
+	buf := make([]byte, idLen)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generate id failed: %v", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// add create a tunnel, the streams are opened by its owner through the authenticated websocket later
+func (m *tunnelMgr) add(info tunnelInfo) (*tunnel, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if len(m.tunnels) >= maxTunnels {
+		return nil, fmt.Errorf("the count of port forward tunnels reaches the limit %d", maxTunnels)
+	}
+	nodeCount := 0
+	for _, t := range m.tunnels {
+		if t.serialNumber == info.serialNumber {
+			nodeCount++
+		}
+	}
+	if nodeCount >= maxTunnelsPerNode {
+		return nil, fmt.Errorf("the count of port forward tunnels on node reaches the limit %d", maxTunnelsPerNode)
+	}
+	t := &tunnel{tunnelInfo: info, createdAt: time.Now()}
+	t.touch()
+	m.tunnels[info.id] = t
+	return t, nil
+}
+
+// get get the tunnel which belongs to the user
+func (m *tunnelMgr) get(id, user string) (*tunnel, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	t, ok := m.tunnels[id]
+	if !ok || t.user != user {
+		return nil, errors.New("tunnel does not exist")
+	}
+	return t, nil
+}
+
+// remove delete the tunnel and abort all its streams, returns false if it has been removed before
+func (m *tunnelMgr) remove(id string) bool {
+	m.lock.Lock()
+	_, ok := m.tunnels[id]
+	delete(m.tunnels, id)
+	var streams []*stream
+	for _, s := range m.streams {
+		if s.tunnel.id == id {
+			streams = append(streams, s)
+		}
+	}
+	m.lock.Unlock()
+	if !ok {
+		return false
+	}
+
+	for _, s := range streams {
+		s.abort("tunnel is closed")
+	}
+	return true
+}
+
+func (m *tunnelMgr) addStream(t *tunnel, conn io.ReadWriteCloser) (*stream, error) {
+	id, err := newId()
+	if err != nil {
+		return nil, err
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if _, ok := m.tunnels[t.id]; !ok {
+		return nil, errors.New("tunnel is closed")
+	}
+	if m.streamCount(t.id) >= maxStreamsPerTunnel {
+		return nil, fmt.Errorf("the count of streams reaches the limit %d", maxStreamsPerTunnel)
+	}
+	s := newStream(id, t, conn)
+	m.streams[id] = s
+	t.touch()
+	return s, nil
+}
+
+func (m *tunnelMgr) streamCount(tunnelId string) int {
+	count := 0
+	for _, s := range m.streams {
+		if s.tunnel.id == tunnelId {
+			count++
+		}
+	}
+	return count
+}
+
+func (m *tunnelMgr) removeStream(s *stream) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	delete(m.streams, s.id)
+	s.tunnel.touch()
+}
+
+// getStream get the stream which is forwarded to the node
+func (m *tunnelMgr) getStream(id, sn string) (*stream, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	s, ok := m.streams[id]
+	if !ok || s.tunnel.serialNumber != sn {
+		return nil, errors.New("stream does not exist")
+	}
+	return s, nil
+}
+
+// checkTunnels close the tunnels which have no stream for a long time
+func (m *tunnelMgr) checkTunnels() {
+	var idleTunnels []tunnelInfo
+	m.lock.Lock()
+	for id, t := range m.tunnels {
+		if m.streamCount(id) == 0 && t.idleTime() > tunnelIdleTimeout {
+			idleTunnels = append(idleTunnels, t.tunnelInfo)
+		}
+	}
+	m.lock.Unlock()
+
+	for _, info := range idleTunnels {
+		if m.remove(info.id) {
+			hwlog.OpLog.Infof("[%s@%s] port forward tunnel [%s] to [%s] of node [%s] closed: idle timeout",
+				info.user, info.ip, info.id, info.target, info.serialNumber)
+		}
+	}
+}
+
+func (t *tunnel) touch() {
+	atomic.StoreInt64(&t.lastActive, time.Now().UnixNano())
+}
+
+func (t *tunnel) idleTime() time.Duration {
+	return time.Since(time.Unix(0, atomic.LoadInt64(&t.lastActive)))
+}
+
+func closeConn(conn io.Closer) {
+	if err := conn.Close(); err != nil {
+		hwlog.RunLog.Warnf("close connection failed: %v", err)
+	}
+}
+
+// notifyEdge send request to edge through cloudhub, waitSent means waiting for the request to be sent out
+func notifyEdge(sn string, req edgeForwardRequest, waitSent bool) error {
+	msg, err := model.NewMessage()
+	if err != nil {
+		return fmt.Errorf("create new message failed: %v", err)
+	}
+	msg.SetNodeId(sn)
+	msg.SetRouter(constants.PortForwardName, common.CloudHubName, common.OptPost, constants.ResPortForward)
+	if err = msg.FillContent(req, true); err != nil {
+		return fmt.Errorf("fill content failed: %v", err)
+	}
+	if !waitSent {
+		return modulemgr.SendMessage(msg)
+	}
+	resp, err := modulemgr.SendSyncMessage(msg, common.ResponseTimeout)
+	if err != nil {
+		return fmt.Errorf("send message to %s failed: %v", common.CloudHubName, err)
+	}
+	var result string
+	if err = resp.ParseContent(&result); err != nil {
+		return fmt.Errorf("parse response failed: %v", err)
+	}
+	if result != common.OK {
+		return errors.New("send message to edge node failed, the node may be disconnected")
+	}
+	return nil
+}
@@ -0,0 +1,241 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package portforward handlers of port forward apis and data reported by edge
+package portforward
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"huawei.com/mindx/common/hwlog"
+	"huawei.com/mindx/common/modulemgr/model"
+
+	"huawei.com/mindxedge/base/common"
+
+	"edge-manager/pkg/constants"
+	"edge-manager/pkg/types"
+)
+
+const (
+	targetTypeHost      = "host"
+	targetTypeContainer = "container"
+
+	minPort = 1
+	maxPort = 65535
+)
+
+var (
+	snReg            = regexp.MustCompile(`^[a-zA-Z0-9]([-_a-zA-Z0-9]{0,62}[a-zA-Z0-9])?$`)
+	idReg            = regexp.MustCompile(`^[a-f0-9]{32}$`)
+	targetReg        = regexp.MustCompile(`^(host|container):[0-9]{1,5}$`)
+	containerNameReg = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]{0,254}$`)
+)
+
+// OpenReq request to open a port forward tunnel, target is such as "host:8080" or "container:8080" and it should be
+// in the allow list of edge. Container is necessary for the container target
+type OpenReq struct {
+	SerialNumber string `json:"serialNumber"`
+	Target       string `json:"target"`
+	Container    string `json:"container"`
+}
+
+// OpenResp response of opening a port forward tunnel, each websocket opened to the address by the same user is
+// forwarded to the target
+type OpenResp struct {
+	TunnelId string `json:"tunnelId"`
+	Address  string `json:"address"`
+}
+
+// CloseReq request to close a port forward tunnel
+type CloseReq struct {
+	TunnelId string `json:"tunnelId"`
+}
+
+// TunnelStatus status of a port forward tunnel, bytes in and out are counted from the view of edge-manager
+type TunnelStatus struct {
+	TunnelId     string `json:"tunnelId"`
+	SerialNumber string `json:"serialNumber"`
+	Target       string `json:"target"`
+	Container    string `json:"container"`
+	Address      string `json:"address"`
+	Streams      int    `json:"streams"`
+	BytesIn      uint64 `json:"bytesIn"`
+	BytesOut     uint64 `json:"bytesOut"`
+	CreatedAt    string `json:"createdAt"`
+}
+
+func streamAddress(id string) string {
+	return fmt.Sprintf("%s?%s=%s", constants.PortForwardStreamUrl, queryTunnelId, id)
+}
+
+func parseCallerReq(msg *model.Message, body interface{}) (types.CallerReq, error) {
+	var req types.CallerReq
+	if err := msg.ParseContent(&req); err != nil {
+		return req, fmt.Errorf("parse content failed: %v", err)
+	}
+	if err := json.Unmarshal([]byte(req.Body), body); err != nil {
+		return req, errors.New("unmarshal request body failed")
+	}
+	return req, nil
+}
+
+func checkOpenReq(req *OpenReq) error {
+	if !snReg.MatchString(req.SerialNumber) {
+		return errors.New("serial number is invalid")
+	}
+	if !targetReg.MatchString(req.Target) {
+		return fmt.Errorf("target should be %s:<port> or %s:<port>", targetTypeHost, targetTypeContainer)
+	}
+	port, err := strconv.Atoi(req.Target[strings.Index(req.Target, ":")+1:])
+	if err != nil || port < minPort || port > maxPort {
+		return fmt.Errorf("port should be in range [%d, %d]", minPort, maxPort)
+	}
+	if strings.HasPrefix(req.Target, targetTypeHost) {
+		if req.Container != "" {
+			return errors.New("container should be empty for host target")
+		}
+		return nil
+	}
+	if !containerNameReg.MatchString(req.Container) {
+		return errors.New("container is invalid")
+	}
+	return nil
+}
+
+func openTunnel(msg *model.Message) common.RespMsg {
+	hwlog.RunLog.Info("start open port forward tunnel")
+	var req OpenReq
+	caller, err := parseCallerReq(msg, &req)
+	if err != nil {
+		hwlog.RunLog.Errorf("open port forward tunnel failed: %v", err)
+		return common.RespMsg{Status: common.ErrorParamConvert, Msg: "parse request failed", Data: nil}
+	}
+	if err = checkOpenReq(&req); err != nil {
+		hwlog.RunLog.Errorf("open port forward tunnel failed: %v", err)
+		return common.RespMsg{Status: common.ErrorParamInvalid, Msg: err.Error(), Data: nil}
+	}
+	id, err := newId()
+	if err != nil {
+		hwlog.RunLog.Errorf("open port forward tunnel failed: %v", err)
+		return common.RespMsg{Status: common.ErrorPortForwardTunnel, Msg: "", Data: nil}
+	}
+	info := tunnelInfo{id: id, serialNumber: req.SerialNumber, target: req.Target, container: req.Container,
+		user: caller.User, ip: caller.Ip}
+	t, err := tunnels.add(info)
+	if err != nil {
+		hwlog.RunLog.Errorf("open port forward tunnel failed: %v", err)
+		hwlog.OpLog.Errorf("[%s@%s] open port forward tunnel to [%s] of node [%s] failed", info.user, info.ip,
+			info.target, info.serialNumber)
+		return common.RespMsg{Status: common.ErrorPortForwardTunnel, Msg: err.Error(), Data: nil}
+	}
+	address := streamAddress(t.id)
+	hwlog.OpLog.Infof("[%s@%s] open port forward tunnel [%s] to [%s] of node [%s] success, address: %s", info.user,
+		info.ip, id, info.target, info.serialNumber, address)
+	hwlog.RunLog.Infof("open port forward tunnel [%s] success", id)
+	return common.RespMsg{Status: common.Success, Msg: "", Data: OpenResp{TunnelId: id, Address: address}}
+}
+
+func closeTunnel(msg *model.Message) common.RespMsg {
+	var req CloseReq
+	caller, err := parseCallerReq(msg, &req)
+	if err != nil {
+		hwlog.RunLog.Errorf("close port forward tunnel failed: %v", err)
+		return common.RespMsg{Status: common.ErrorParamConvert, Msg: "parse request failed", Data: nil}
+	}
+	t, err := tunnels.get(req.TunnelId, caller.User)
+	if err != nil {
+		hwlog.RunLog.Errorf("close port forward tunnel failed: %v", err)
+		return common.RespMsg{Status: common.ErrorPortForwardTunnel, Msg: err.Error(), Data: nil}
+	}
+	if tunnels.remove(t.id) {
+		hwlog.OpLog.Infof("[%s@%s] port forward tunnel [%s] to [%s] of node [%s] closed: closed by user, "+
+			"%d bytes in, %d bytes out", t.user, t.ip, t.id, t.target, t.serialNumber,
+			atomic.LoadUint64(&t.bytesIn), atomic.LoadUint64(&t.bytesOut))
+	}
+	return common.RespMsg{Status: common.Success, Msg: "", Data: nil}
+}
+
+// queryTunnels query the tunnel of id, or all the tunnels of user when listing
+func queryTunnels(msg *model.Message) common.RespMsg {
+	var caller types.CallerReq
+	if err := msg.ParseContent(&caller); err != nil {
+		hwlog.RunLog.Errorf("query port forward tunnels failed: parse content failed: %v", err)
+		return common.RespMsg{Status: common.ErrorParamConvert, Msg: "parse request failed", Data: nil}
+	}
+	if caller.Body != "" && !idReg.MatchString(caller.Body) {
+		hwlog.RunLog.Error("query port forward tunnels failed: tunnel id is invalid")
+		return common.RespMsg{Status: common.ErrorParamInvalid, Msg: "tunnel id is invalid", Data: nil}
+	}
+	result := tunnels.status(caller.Body, caller.User)
+	if caller.Body != "" && len(result) == 0 {
+		hwlog.RunLog.Error("query port forward tunnels failed: tunnel does not exist")
+		return common.RespMsg{Status: common.ErrorPortForwardTunnel, Msg: "tunnel does not exist", Data: nil}
+	}
+	return common.RespMsg{Status: common.Success, Msg: "", Data: result}
+}
+
+func (m *tunnelMgr) status(id, user string) []TunnelStatus {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	result := make([]TunnelStatus, 0, len(m.tunnels))
+	for _, t := range m.tunnels {
+		if t.user != user || (id != "" && t.id != id) {
+			continue
+		}
+		result = append(result, TunnelStatus{
+			TunnelId:     t.id,
+			SerialNumber: t.serialNumber,
+			Target:       t.target,
+			Container:    t.container,
+			Address:      streamAddress(t.id),
+			Streams:      m.streamCount(t.id),
+			BytesIn:      atomic.LoadUint64(&t.bytesIn),
+			BytesOut:     atomic.LoadUint64(&t.bytesOut),
+			CreatedAt:    t.createdAt.Format(time.RFC3339),
+		})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].CreatedAt < result[j].CreatedAt })
+	return result
+}
+
+// handleEdgeData forward the data reported by edge to client, the stream unknown is closed on edge since
+// it may be left by the restart of edge-manager
+func handleEdgeData(msg *model.Message) {
+	var data edgeForwardData
+	if err := msg.ParseContent(&data); err != nil {
+		hwlog.RunLog.Errorf("parse port forward data failed: %v", err)
+		return
+	}
+	sn := msg.GetPeerInfo().Sn
+	s, err := tunnels.getStream(data.StreamId, sn)
+	if err != nil {
+		if data.Closed || !idReg.MatchString(data.StreamId) {
+			return
+		}
+		hwlog.RunLog.Warnf("drop data of port forward stream [%s] from node [%s]: %v", data.StreamId, sn, err)
+		if err = notifyEdge(sn, edgeForwardRequest{StreamId: data.StreamId, Action: actionClose},
+			false); err != nil {
+			hwlog.RunLog.Errorf("close unknown port forward stream [%s] failed: %v", data.StreamId, err)
+		}
+		return
+	}
+	if err = s.receive(&data); err != nil {
+		hwlog.RunLog.Errorf("receive data of port forward stream [%s] failed: %v", s.id, err)
+		s.abort(err.Error())
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package remoteshell to init remote shell module
+package remoteshell
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"huawei.com/mindx/common/hwlog"
+	"huawei.com/mindx/common/modulemgr"
+	"huawei.com/mindx/common/modulemgr/model"
+
+	"huawei.com/mindxedge/base/common"
+
+	"edge-manager/pkg/constants"
+)
+
+type handlerFunc func(message *model.Message) common.RespMsg
+
+type remoteShellManager struct {
+	enable bool
+	ctx    context.Context
+}
+
+// NewRemoteShellManager create remote shell manager
+func NewRemoteShellManager(enable bool) model.Module {
+	return &remoteShellManager{
+		enable: enable,
+		ctx:    context.Background(),
+	}
+}
+
+func (rm *remoteShellManager) Name() string {
+	return constants.RemoteShellName
+}
+
+func (rm *remoteShellManager) Enable() bool {
+	return rm.enable
+}
+
+func (rm *remoteShellManager) Start() {
+	go periodCheckSessions(rm.ctx)
+	for {
+		select {
+		case _, ok := <-rm.ctx.Done():
+			if !ok {
+				hwlog.RunLog.Info("catch stop signal channel is closed")
+			}
+			hwlog.RunLog.Info("has listened stop signal")
+			return
+		default:
+		}
+
+		req, err := modulemgr.ReceiveMessage(rm.Name())
+		if err != nil {
+			hwlog.RunLog.Errorf("%s receive request failed", rm.Name())
+			continue
+		}
+
+		go rm.dispatch(req)
+	}
+}
+
+func (rm *remoteShellManager) dispatch(req *model.Message) {
+	// output reported by edge is asynchronous, no response is needed
+	if req.GetOption() == common.OptReport && req.GetResource() == constants.ResRemoteShellOutput {
+		handleEdgeOutput(req)
+		return
+	}
+	method, exist := handlerFuncMap[common.Combine(req.GetOption(), req.GetResource())]
+	if !exist {
+		hwlog.RunLog.Errorf("handler func is not exist, option: %s, resource: %s", req.GetOption(),
+			req.GetResource())
+		return
+	}
+	res := method(req)
+	resp, err := req.NewResponse()
+	if err != nil {
+		hwlog.RunLog.Errorf("%s new response failed", rm.Name())
+		return
+	}
+	if err = resp.FillContent(res); err != nil {
+		hwlog.RunLog.Errorf("%s fill content failed: %v", rm.Name(), err)
+		return
+	}
+	if err = modulemgr.SendMessage(resp); err != nil {
+		hwlog.RunLog.Errorf("%s send response failed", rm.Name())
+		return
+	}
+}
+
+var handlerFuncMap = map[string]handlerFunc{
+	common.Combine(http.MethodPost, constants.RemoteShellUrlPrefix):           openSession,
+	common.Combine(http.MethodPost, constants.RemoteShellUrlPrefix+"/input"):  inputSession,
+	common.Combine(http.MethodPost, constants.RemoteShellUrlPrefix+"/resize"): resizeSession,
+	common.Combine(http.MethodPost, constants.RemoteShellUrlPrefix+"/close"):  closeSession,
+	common.Combine(http.MethodGet, constants.RemoteShellUrlPrefix+"/output"):  queryOutput,
+}
+
+// periodCheckSessions closes the idle sessions and drops the closed ones whose output has been kept long enough
+func periodCheckSessions(ctx context.Context) {
+	ticker := time.NewTicker(sessionCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			hwlog.RunLog.Info("remote shell check goroutine is stopped")
+			return
+		case <-ticker.C:
+			sessions.checkSessions()
+		}
+	}
+}
@@ -0,0 +1,209 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package remoteshell test for remote shell sessions
+package remoteshell
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/smartystreets/goconvey/convey"
+
+	"huawei.com/mindx/common/modulemgr/model"
+
+	"huawei.com/mindxedge/base/common"
+)
+
+const testSn = "2102312NSF10K8000130"
+
+var validOpenReq = OpenReq{SerialNumber: testSn, Type: shellTypeContainer, Container: "3f2a9c", Rows: 24, Cols: 80}
+
+func resetSessions() {
+	sessions.lock.Lock()
+	sessions.sessions = make(map[string]*session)
+	sessions.lock.Unlock()
+}
+
+func newEdgeOutputMsgForUT(sn string, output edgeShellOutput) *model.Message {
+	msg, err := model.NewMessage()
+	if err != nil {
+		panic(err)
+	}
+	msg.SetPeerInfo(model.MsgPeerInfo{Sn: sn})
+	if err = msg.FillContent(output, true); err != nil {
+		panic(err)
+	}
+	return msg
+}
+
+func openSessionForUT() string {
+	resp := openSession(newCallerMsgForUT(testUser, validOpenReq))
+	convey.So(resp.Status, convey.ShouldEqual, common.Success)
+	openResp, ok := resp.Data.(OpenResp)
+	convey.So(ok, convey.ShouldBeTrue)
+	return openResp.SessionId
+}
+
+func TestOpenSession(t *testing.T) {
+	var sent []edgeShellRequest
+	patches := gomonkey.ApplyFunc(notifyEdge, func(sn string, req edgeShellRequest, waitSent bool) error {
+		sent = append(sent, req)
+		return nil
+	})
+	defer patches.Reset()
+
+	convey.Convey("open session should be success", t, func() {
+		resetSessions()
+		id := openSessionForUT()
+		convey.So(sessionIdReg.MatchString(id), convey.ShouldBeTrue)
+		convey.So(sent[len(sent)-1], convey.ShouldResemble, edgeShellRequest{SessionId: id, Action: actionOpen,
+			Type: shellTypeContainer, Container: "3f2a9c", Rows: 24, Cols: 80})
+	})
+
+	convey.Convey("open session should be failed, invalid param", t, func() {
+		invalidReqs := []OpenReq{
+			{SerialNumber: "../sn", Type: shellTypeContainer, Container: "3f2a9c"},
+			{SerialNumber: testSn, Type: "vm", Command: []string{"df"}},
+			{SerialNumber: testSn, Type: shellTypeHost},
+			{SerialNumber: testSn, Type: shellTypeContainer, Container: "-it"},
+			{SerialNumber: testSn, Type: shellTypeHost, Command: []string{"df", ""}},
+			{SerialNumber: testSn, Type: shellTypeHost, Command: make([]string, maxCommandArgs+1)},
+			{SerialNumber: testSn, Type: shellTypeHost, Command: []string{"top"}, Rows: maxTermSize + 1},
+		}
+		for _, req := range invalidReqs {
+			resp := openSession(newCallerMsgForUT(testUser, req))
+			convey.So(resp.Status, convey.ShouldEqual, common.ErrorParamInvalid)
+		}
+		resp := openSession(newCallerMsgForUT(testUser, "not json"))
+		convey.So(resp.Status, convey.ShouldEqual, common.ErrorParamConvert)
+	})
+
+	convey.Convey("sessions of one node are limited", t, func() {
+		resetSessions()
+		for i := 0; i < maxSessionsPerNode; i++ {
+			openSessionForUT()
+		}
+		resp := openSession(newCallerMsgForUT(testUser, validOpenReq))
+		convey.So(resp.Status, convey.ShouldEqual, common.ErrorRemoteShellSession)
+	})
+}
+
+func TestOpenSessionNodeOffline(t *testing.T) {
+	convey.Convey("session is not kept when edge can not be reached", t, func() {
+		resetSessions()
+		patches := gomonkey.ApplyFuncReturn(notifyEdge, errors.New("node is disconnected"))
+		defer patches.Reset()
+		resp := openSession(newCallerMsgForUT(testUser, validOpenReq))
+		convey.So(resp.Status, convey.ShouldEqual, common.ErrorRemoteShellSession)
+		convey.So(len(sessions.sessions), convey.ShouldEqual, 0)
+	})
+}
+
+func TestSessionIo(t *testing.T) {
+	var sent []edgeShellRequest
+	patches := gomonkey.ApplyFunc(notifyEdge, func(sn string, req edgeShellRequest, waitSent bool) error {
+		sent = append(sent, req)
+		return nil
+	})
+	defer patches.Reset()
+
+	convey.Convey("input, output and close of session", t, func() {
+		resetSessions()
+		id := openSessionForUT()
+
+		resp := inputSession(newCallerMsgForUT(testUser, InputReq{SessionId: id, Data: []byte("ls\n")}))
+		convey.So(resp.Status, convey.ShouldEqual, common.Success)
+		convey.So(sent[len(sent)-1].Data, convey.ShouldResemble, []byte("ls\n"))
+		resp = resizeSession(newCallerMsgForUT(testUser, ResizeReq{SessionId: id, Rows: 40, Cols: 120}))
+		convey.So(resp.Status, convey.ShouldEqual, common.Success)
+
+		handleEdgeOutput(newEdgeOutputMsgForUT(testSn, edgeShellOutput{SessionId: id, Data: []byte("app.py\r\n")}))
+		handleEdgeOutput(newEdgeOutputMsgForUT("other-node", edgeShellOutput{SessionId: id, Data: []byte("x")}))
+		resp = queryOutput(newCallerMsgForUT(testUser, id))
+		convey.So(resp.Status, convey.ShouldEqual, common.Success)
+		output, ok := resp.Data.(SessionOutput)
+		convey.So(ok, convey.ShouldBeTrue)
+		convey.So(string(output.Data), convey.ShouldEqual, "app.py\r\n")
+		convey.So(output.Closed, convey.ShouldBeFalse)
+
+		resp = closeSession(newCallerMsgForUT(testUser, CloseReq{SessionId: id}))
+		convey.So(resp.Status, convey.ShouldEqual, common.Success)
+		convey.So(sent[len(sent)-1].Action, convey.ShouldEqual, actionClose)
+		resp = inputSession(newCallerMsgForUT(testUser, InputReq{SessionId: id, Data: []byte("ls\n")}))
+		convey.So(resp.Status, convey.ShouldEqual, common.ErrorRemoteShellSession)
+		resp = queryOutput(newCallerMsgForUT(testUser, id))
+		convey.So(resp.Data.(SessionOutput).Closed, convey.ShouldBeTrue)
+		convey.So(len(sessions.sessions), convey.ShouldEqual, 0)
+	})
+
+	convey.Convey("session can only be operated by the user who opened it", t, func() {
+		resetSessions()
+		id := openSessionForUT()
+		resp := inputSession(newCallerMsgForUT("[other]", InputReq{SessionId: id, Data: []byte("ls\n")}))
+		convey.So(resp.Status, convey.ShouldEqual, common.ErrorRemoteShellSession)
+		resp = queryOutput(newCallerMsgForUT("[other]", id))
+		convey.So(resp.Status, convey.ShouldEqual, common.ErrorRemoteShellSession)
+		resp = inputSession(newCallerMsgForUT(testUser, InputReq{SessionId: id, Data: make([]byte, maxInputLen+1)}))
+		convey.So(resp.Status, convey.ShouldEqual, common.ErrorParamInvalid)
+	})
+
+	convey.Convey("output is dropped from the oldest when it is not read in time", t, func() {
+		resetSessions()
+		id := openSessionForUT()
+		handleEdgeOutput(newEdgeOutputMsgForUT(testSn, edgeShellOutput{SessionId: id,
+			Data: make([]byte, maxOutputBufLen)}))
+		handleEdgeOutput(newEdgeOutputMsgForUT(testSn, edgeShellOutput{SessionId: id, Data: []byte("end"),
+			Closed: true, Reason: "command exited"}))
+		output := queryOutput(newCallerMsgForUT(testUser, id)).Data.(SessionOutput)
+		convey.So(len(output.Data), convey.ShouldEqual, maxOutputBufLen)
+		convey.So(string(output.Data[maxOutputBufLen-len("end"):]), convey.ShouldEqual, "end")
+		convey.So(output.DroppedLen, convey.ShouldEqual, len("end"))
+		convey.So(output.Reason, convey.ShouldEqual, "command exited")
+	})
+
+	convey.Convey("unknown session reported by edge is closed", t, func() {
+		resetSessions()
+		unknownId := "0123456789abcdef0123456789abcdef"
+		handleEdgeOutput(newEdgeOutputMsgForUT(testSn, edgeShellOutput{SessionId: unknownId, Data: []byte("x")}))
+		convey.So(sent[len(sent)-1], convey.ShouldResemble,
+			edgeShellRequest{SessionId: unknownId, Action: actionClose})
+	})
+}
+
+func TestCheckSessions(t *testing.T) {
+	var sent []edgeShellRequest
+	patches := gomonkey.ApplyFunc(notifyEdge, func(sn string, req edgeShellRequest, waitSent bool) error {
+		sent = append(sent, req)
+		return nil
+	})
+	defer patches.Reset()
+
+	convey.Convey("idle session is closed and closed session is dropped in time", t, func() {
+		resetSessions()
+		idleId := openSessionForUT()
+		closedId := openSessionForUT()
+		sessions.lock.Lock()
+		sessions.sessions[idleId].lastActive = time.Now().Add(-sessionIdleTimeout - time.Second)
+		sessions.sessions[closedId].markClosed("command exited")
+		sessions.sessions[closedId].closedAt = time.Now().Add(-closedSessionKeepTime - time.Second)
+		sessions.lock.Unlock()
+
+		sessions.checkSessions()
+		convey.So(sent[len(sent)-1], convey.ShouldResemble, edgeShellRequest{SessionId: idleId, Action: actionClose})
+		output := queryOutput(newCallerMsgForUT(testUser, idleId)).Data.(SessionOutput)
+		convey.So(output.Closed, convey.ShouldBeTrue)
+		convey.So(output.Reason, convey.ShouldEqual, "idle timeout")
+		resp := queryOutput(newCallerMsgForUT(testUser, closedId))
+		convey.So(resp.Status, convey.ShouldEqual, common.ErrorRemoteShellSession)
+	})
+}
@@ -0,0 +1,258 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package remoteshell sessions of remote shell, a session is bound to the user who opened it
+package remoteshell
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"huawei.com/mindx/common/hwlog"
+	"huawei.com/mindx/common/modulemgr"
+	"huawei.com/mindx/common/modulemgr/model"
+
+	"huawei.com/mindxedge/base/common"
+
+	"edge-manager/pkg/constants"
+)
+
+const (
+	shellTypeHost      = "host"
+	shellTypeContainer = "container"
+
+	actionOpen   = "open"
+	actionInput  = "input"
+	actionResize = "resize"
+	actionClose  = "close"
+
+	maxSessions           = 16
+	maxSessionsPerNode    = 4
+	sessionIdleTimeout    = 10 * time.Minute
+	closedSessionKeepTime = time.Minute
+	sessionCheckInterval  = 30 * time.Second
+	maxOutputBufLen       = common.MB
+	sessionIdLen          = 16
+)
+
+// edgeShellRequest request sent to edge, the sessions of a node are multiplexed over its cloudhub connection by id
+type edgeShellRequest struct {
+	SessionId string   `json:"sessionId"`
+	Action    string   `json:"action"`
+	Type      string   `json:"type,omitempty"`
+	Container string   `json:"container,omitempty"`
+	Command   []string `json:"command,omitempty"`
+	Rows      uint16   `json:"rows,omitempty"`
+	Cols      uint16   `json:"cols,omitempty"`
+	Data      []byte   `json:"data,omitempty"`
+}
+
+// edgeShellOutput output reported by edge, closed is set on the last report of the session
+type edgeShellOutput struct {
+	SessionId string `json:"sessionId"`
+	Data      []byte `json:"data,omitempty"`
+	Closed    bool   `json:"closed,omitempty"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+// sessionInfo identity of a session, used for recording operation log
+type sessionInfo struct {
+	id           string
+	serialNumber string
+	user         string
+	ip           string
+	target       string
+}
+
+type session struct {
+	sessionInfo
+	lastActive time.Time
+	output     []byte
+	droppedLen int
+	closed     bool
+	closedAt   time.Time
+	reason     string
+}
+
+type sessionMgr struct {
+	lock     sync.Mutex
+	sessions map[string]*session
+}
+
+var sessions = &sessionMgr{sessions: make(map[string]*session)}
+
+func newSessionId() (string, error) {
+	buf := make([]byte, sessionIdLen)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generate session id failed: %v", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func (m *sessionMgr) add(info sessionInfo) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if len(m.sessions) >= maxSessions {
+		return fmt.Errorf("the count of remote shell sessions reaches the limit %d", maxSessions)
+	}
+	nodeCount := 0
+	for _, s := range m.sessions {
+		if s.serialNumber == info.serialNumber && !s.closed {
+			nodeCount++
+		}
+	}
+	if nodeCount >= maxSessionsPerNode {
+		return fmt.Errorf("the count of remote shell sessions on node reaches the limit %d", maxSessionsPerNode)
+	}
+	m.sessions[info.id] = &session{sessionInfo: info, lastActive: time.Now()}
+	return nil
+}
+
+func (m *sessionMgr) remove(id string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	delete(m.sessions, id)
+}
+
+// getActive get the session which is not closed and belongs to the user, it is also marked as active
+func (m *sessionMgr) getActive(id, user string) (sessionInfo, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	s, ok := m.sessions[id]
+	if !ok || s.user != user {
+		return sessionInfo{}, errors.New("session does not exist")
+	}
+	if s.closed {
+		return sessionInfo{}, fmt.Errorf("session is closed: %s", s.reason)
+	}
+	s.lastActive = time.Now()
+	return s.sessionInfo, nil
+}
+
+// appendOutput append output reported by edge, the oldest output is dropped if the user does not read it in time.
+// It returns whether the session is closed by this report
+func (m *sessionMgr) appendOutput(sn string, output edgeShellOutput) (sessionInfo, bool, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	s, ok := m.sessions[output.SessionId]
+	if !ok || s.serialNumber != sn {
+		return sessionInfo{}, false, errors.New("session does not exist")
+	}
+	s.output = append(s.output, output.Data...)
+	if overflow := len(s.output) - maxOutputBufLen; overflow > 0 {
+		s.output = append([]byte{}, s.output[overflow:]...)
+		s.droppedLen += overflow
+	}
+	if output.Closed && !s.closed {
+		s.markClosed(output.Reason)
+		return s.sessionInfo, true, nil
+	}
+	return s.sessionInfo, false, nil
+}
+
+// markClosed mark session as closed, returns false if it has been closed before
+func (m *sessionMgr) markClosed(id, reason string) bool {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	s, ok := m.sessions[id]
+	if !ok || s.closed {
+		return false
+	}
+	s.markClosed(reason)
+	return true
+}
+
+func (s *session) markClosed(reason string) {
+	s.closed = true
+	s.closedAt = time.Now()
+	s.reason = reason
+}
+
+// readOutput read and clear the output buffered, the closed session is dropped once its output is read
+func (m *sessionMgr) readOutput(id, user string) (SessionOutput, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	s, ok := m.sessions[id]
+	if !ok || s.user != user {
+		return SessionOutput{}, errors.New("session does not exist")
+	}
+	result := SessionOutput{
+		SessionId:  id,
+		Data:       s.output,
+		DroppedLen: s.droppedLen,
+		Closed:     s.closed,
+		Reason:     s.reason,
+	}
+	s.output, s.droppedLen = nil, 0
+	s.lastActive = time.Now()
+	if s.closed {
+		delete(m.sessions, id)
+	}
+	return result, nil
+}
+
+// checkSessions close the sessions which the user does not operate for a long time, drop the closed sessions
+// whose output is not read in time
+func (m *sessionMgr) checkSessions() {
+	var idleSessions []sessionInfo
+	m.lock.Lock()
+	for id, s := range m.sessions {
+		if s.closed && time.Since(s.closedAt) > closedSessionKeepTime {
+			delete(m.sessions, id)
+			continue
+		}
+		if !s.closed && time.Since(s.lastActive) > sessionIdleTimeout {
+			s.markClosed("idle timeout")
+			idleSessions = append(idleSessions, s.sessionInfo)
+		}
+	}
+	m.lock.Unlock()
+
+	for _, info := range idleSessions {
+		hwlog.OpLog.Infof("[%s@%s] remote shell session [%s] on node [%s] closed: idle timeout", info.user,
+			info.ip, info.id, info.serialNumber)
+		if err := notifyEdge(info.serialNumber, edgeShellRequest{SessionId: info.id, Action: actionClose},
+			false); err != nil {
+			hwlog.RunLog.Errorf("close idle remote shell session [%s] on edge failed: %v", info.id, err)
+		}
+	}
+}
+
+// notifyEdge send request to edge through cloudhub, waitSent means waiting for the request to be sent out
+func notifyEdge(sn string, req edgeShellRequest, waitSent bool) error {
+	msg, err := model.NewMessage()
+	if err != nil {
+		return fmt.Errorf("create new message failed: %v", err)
+	}
+	msg.SetNodeId(sn)
+	msg.SetRouter(constants.RemoteShellName, common.CloudHubName, common.OptPost, constants.ResRemoteShell)
+	if err = msg.FillContent(req, true); err != nil {
+		return fmt.Errorf("fill content failed: %v", err)
+	}
+	if !waitSent {
+		return modulemgr.SendMessage(msg)
+	}
+	resp, err := modulemgr.SendSyncMessage(msg, common.ResponseTimeout)
+	if err != nil {
+		return fmt.Errorf("send message to %s failed: %v", common.CloudHubName, err)
+	}
+	var result string
+	if err = resp.ParseContent(&result); err != nil {
+		return fmt.Errorf("parse response failed: %v", err)
+	}
+	if result != common.OK {
+		return errors.New("send message to edge node failed, the node may be disconnected")
+	}
+	return nil
+}
@@ -0,0 +1,293 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package remoteshell handlers of remote shell apis and output reported by edge
+package remoteshell
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"huawei.com/mindx/common/hwlog"
+	"huawei.com/mindx/common/modulemgr/model"
+
+	"huawei.com/mindxedge/base/common"
+
+	"edge-manager/pkg/types"
+)
+
+const (
+	maxInputLen      = 4 * common.KB
+	maxCommandArgs   = 16
+	maxCommandArgLen = 256
+	maxTermSize      = 1000
+)
+
+var (
+	snReg            = regexp.MustCompile(`^[a-zA-Z0-9]([-_a-zA-Z0-9]{0,62}[a-zA-Z0-9])?$`)
+	sessionIdReg     = regexp.MustCompile(`^[a-f0-9]{32}$`)
+	containerNameReg = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]{0,254}$`)
+)
+
+// OpenReq request to open a remote shell session, host type runs a command in the allow list of edge on host,
+// container type runs command in an application container, /bin/sh by default
+type OpenReq struct {
+	SerialNumber string   `json:"serialNumber"`
+	Type         string   `json:"type"`
+	Container    string   `json:"container"`
+	Command      []string `json:"command"`
+	Rows         uint16   `json:"rows"`
+	Cols         uint16   `json:"cols"`
+}
+
+// OpenResp response of opening a remote shell session
+type OpenResp struct {
+	SessionId string `json:"sessionId"`
+}
+
+// InputReq request to write input into a remote shell session, data is base64 encoded
+type InputReq struct {
+	SessionId string `json:"sessionId"`
+	Data      []byte `json:"data"`
+}
+
+// ResizeReq request to resize the terminal of a remote shell session
+type ResizeReq struct {
+	SessionId string `json:"sessionId"`
+	Rows      uint16 `json:"rows"`
+	Cols      uint16 `json:"cols"`
+}
+
+// CloseReq request to close a remote shell session
+type CloseReq struct {
+	SessionId string `json:"sessionId"`
+}
+
+// SessionOutput output of a remote shell session since last query, data is base64 encoded. DroppedLen is the length
+// of output dropped since it is not read in time
+type SessionOutput struct {
+	SessionId  string `json:"sessionId"`
+	Data       []byte `json:"data"`
+	DroppedLen int    `json:"droppedLen"`
+	Closed     bool   `json:"closed"`
+	Reason     string `json:"reason"`
+}
+
+func parseCallerReq(msg *model.Message, body interface{}) (types.CallerReq, error) {
+	var req types.CallerReq
+	if err := msg.ParseContent(&req); err != nil {
+		return req, fmt.Errorf("parse content failed: %v", err)
+	}
+	if err := json.Unmarshal([]byte(req.Body), body); err != nil {
+		return req, errors.New("unmarshal request body failed")
+	}
+	return req, nil
+}
+
+func checkOpenReq(req *OpenReq) error {
+	if !snReg.MatchString(req.SerialNumber) {
+		return errors.New("serial number is invalid")
+	}
+	switch req.Type {
+	case shellTypeHost:
+		if len(req.Command) == 0 {
+			return errors.New("command is necessary for host shell")
+		}
+	case shellTypeContainer:
+		if !containerNameReg.MatchString(req.Container) {
+			return errors.New("container is invalid")
+		}
+	default:
+		return fmt.Errorf("type should be %s or %s", shellTypeHost, shellTypeContainer)
+	}
+	if len(req.Command) > maxCommandArgs {
+		return fmt.Errorf("the count of command args should not be more than %d", maxCommandArgs)
+	}
+	for _, arg := range req.Command {
+		if arg == "" || len(arg) > maxCommandArgLen || strings.ContainsAny(arg, "\x00\n") {
+			return errors.New("command arg is invalid")
+		}
+	}
+	if req.Rows > maxTermSize || req.Cols > maxTermSize {
+		return fmt.Errorf("terminal size should not be more than %d", maxTermSize)
+	}
+	return nil
+}
+
+func getTarget(req *OpenReq) string {
+	if req.Type == shellTypeHost {
+		return fmt.Sprintf("host %q", strings.Join(req.Command, " "))
+	}
+	return fmt.Sprintf("container [%s] %q", req.Container, strings.Join(req.Command, " "))
+}
+
+func openSession(msg *model.Message) common.RespMsg {
+	hwlog.RunLog.Info("start open remote shell session")
+	var req OpenReq
+	caller, err := parseCallerReq(msg, &req)
+	if err != nil {
+		hwlog.RunLog.Errorf("open remote shell session failed: %v", err)
+		return common.RespMsg{Status: common.ErrorParamConvert, Msg: "parse request failed", Data: nil}
+	}
+	if err = checkOpenReq(&req); err != nil {
+		hwlog.RunLog.Errorf("open remote shell session failed: %v", err)
+		return common.RespMsg{Status: common.ErrorParamInvalid, Msg: err.Error(), Data: nil}
+	}
+	id, err := newSessionId()
+	if err != nil {
+		hwlog.RunLog.Errorf("open remote shell session failed: %v", err)
+		return common.RespMsg{Status: common.ErrorRemoteShellSession, Msg: "", Data: nil}
+	}
+	info := sessionInfo{id: id, serialNumber: req.SerialNumber, user: caller.User, ip: caller.Ip,
+		target: getTarget(&req)}
+	if err = sessions.add(info); err != nil {
+		hwlog.RunLog.Errorf("open remote shell session failed: %v", err)
+		return common.RespMsg{Status: common.ErrorRemoteShellSession, Msg: err.Error(), Data: nil}
+	}
+	edgeReq := edgeShellRequest{SessionId: id, Action: actionOpen, Type: req.Type, Container: req.Container,
+		Command: req.Command, Rows: req.Rows, Cols: req.Cols}
+	if err = notifyEdge(req.SerialNumber, edgeReq, true); err != nil {
+		sessions.remove(id)
+		hwlog.RunLog.Errorf("open remote shell session failed: %v", err)
+		hwlog.OpLog.Errorf("[%s@%s] open remote shell session on node [%s] failed, target: %s", info.user, info.ip,
+			info.serialNumber, info.target)
+		return common.RespMsg{Status: common.ErrorRemoteShellSession, Msg: err.Error(), Data: nil}
+	}
+	hwlog.OpLog.Infof("[%s@%s] open remote shell session [%s] on node [%s] success, target: %s", info.user, info.ip,
+		id, info.serialNumber, info.target)
+	hwlog.RunLog.Infof("open remote shell session [%s] success", id)
+	return common.RespMsg{Status: common.Success, Msg: "", Data: OpenResp{SessionId: id}}
+}
+
+func inputSession(msg *model.Message) common.RespMsg {
+	var req InputReq
+	caller, err := parseCallerReq(msg, &req)
+	if err != nil {
+		hwlog.RunLog.Errorf("write remote shell session failed: %v", err)
+		return common.RespMsg{Status: common.ErrorParamConvert, Msg: "parse request failed", Data: nil}
+	}
+	if len(req.Data) == 0 || len(req.Data) > maxInputLen {
+		hwlog.RunLog.Error("write remote shell session failed: input length is invalid")
+		return common.RespMsg{Status: common.ErrorParamInvalid,
+			Msg: fmt.Sprintf("input length should be in range [1, %d]", maxInputLen), Data: nil}
+	}
+	info, err := sessions.getActive(req.SessionId, caller.User)
+	if err != nil {
+		hwlog.RunLog.Errorf("write remote shell session failed: %v", err)
+		return common.RespMsg{Status: common.ErrorRemoteShellSession, Msg: err.Error(), Data: nil}
+	}
+	// all the input is recorded, it is the only way to audit what has been done on edge
+	hwlog.OpLog.Infof("[%s@%s] remote shell session [%s] input: %q", info.user, info.ip, info.id, req.Data)
+	edgeReq := edgeShellRequest{SessionId: info.id, Action: actionInput, Data: req.Data}
+	if err = notifyEdge(info.serialNumber, edgeReq, true); err != nil {
+		hwlog.RunLog.Errorf("write remote shell session [%s] failed: %v", info.id, err)
+		return common.RespMsg{Status: common.ErrorRemoteShellSession, Msg: err.Error(), Data: nil}
+	}
+	return common.RespMsg{Status: common.Success, Msg: "", Data: nil}
+}
+
+func resizeSession(msg *model.Message) common.RespMsg {
+	var req ResizeReq
+	caller, err := parseCallerReq(msg, &req)
+	if err != nil {
+		hwlog.RunLog.Errorf("resize remote shell session failed: %v", err)
+		return common.RespMsg{Status: common.ErrorParamConvert, Msg: "parse request failed", Data: nil}
+	}
+	if req.Rows == 0 || req.Cols == 0 || req.Rows > maxTermSize || req.Cols > maxTermSize {
+		hwlog.RunLog.Error("resize remote shell session failed: terminal size is invalid")
+		return common.RespMsg{Status: common.ErrorParamInvalid,
+			Msg: fmt.Sprintf("terminal size should be in range [1, %d]", maxTermSize), Data: nil}
+	}
+	info, err := sessions.getActive(req.SessionId, caller.User)
+	if err != nil {
+		hwlog.RunLog.Errorf("resize remote shell session failed: %v", err)
+		return common.RespMsg{Status: common.ErrorRemoteShellSession, Msg: err.Error(), Data: nil}
+	}
+	edgeReq := edgeShellRequest{SessionId: info.id, Action: actionResize, Rows: req.Rows, Cols: req.Cols}
+	if err = notifyEdge(info.serialNumber, edgeReq, true); err != nil {
+		hwlog.RunLog.Errorf("resize remote shell session [%s] failed: %v", info.id, err)
+		return common.RespMsg{Status: common.ErrorRemoteShellSession, Msg: err.Error(), Data: nil}
+	}
+	return common.RespMsg{Status: common.Success, Msg: "", Data: nil}
+}
+
+func closeSession(msg *model.Message) common.RespMsg {
+	var req CloseReq
+	caller, err := parseCallerReq(msg, &req)
+	if err != nil {
+		hwlog.RunLog.Errorf("close remote shell session failed: %v", err)
+		return common.RespMsg{Status: common.ErrorParamConvert, Msg: "parse request failed", Data: nil}
+	}
+	info, err := sessions.getActive(req.SessionId, caller.User)
+	if err != nil {
+		hwlog.RunLog.Errorf("close remote shell session failed: %v", err)
+		return common.RespMsg{Status: common.ErrorRemoteShellSession, Msg: err.Error(), Data: nil}
+	}
+	if err = notifyEdge(info.serialNumber, edgeShellRequest{SessionId: info.id, Action: actionClose},
+		true); err != nil {
+		hwlog.RunLog.Warnf("notify edge to close remote shell session [%s] failed: %v", info.id, err)
+	}
+	if sessions.markClosed(info.id, "closed by user") {
+		hwlog.OpLog.Infof("[%s@%s] remote shell session [%s] on node [%s] closed: closed by user", info.user,
+			info.ip, info.id, info.serialNumber)
+	}
+	return common.RespMsg{Status: common.Success, Msg: "", Data: nil}
+}
+
+func queryOutput(msg *model.Message) common.RespMsg {
+	var caller types.CallerReq
+	if err := msg.ParseContent(&caller); err != nil {
+		hwlog.RunLog.Errorf("query remote shell output failed: parse content failed: %v", err)
+		return common.RespMsg{Status: common.ErrorParamConvert, Msg: "parse request failed", Data: nil}
+	}
+	if !sessionIdReg.MatchString(caller.Body) {
+		hwlog.RunLog.Error("query remote shell output failed: session id is invalid")
+		return common.RespMsg{Status: common.ErrorParamInvalid, Msg: "session id is invalid", Data: nil}
+	}
+	output, err := sessions.readOutput(caller.Body, caller.User)
+	if err != nil {
+		hwlog.RunLog.Errorf("query remote shell output failed: %v", err)
+		return common.RespMsg{Status: common.ErrorRemoteShellSession, Msg: err.Error(), Data: nil}
+	}
+	return common.RespMsg{Status: common.Success, Msg: "", Data: output}
+}
+
+// handleEdgeOutput buffer the output for user and record it, the session unknown is closed on edge since
+// it may be left by the restart of edge-manager
+func handleEdgeOutput(msg *model.Message) {
+	var output edgeShellOutput
+	if err := msg.ParseContent(&output); err != nil {
+		hwlog.RunLog.Errorf("parse remote shell output failed: %v", err)
+		return
+	}
+	sn := msg.GetPeerInfo().Sn
+	info, closedNow, err := sessions.appendOutput(sn, output)
+	if err != nil {
+		hwlog.RunLog.Warnf("drop output of remote shell session [%s] from node [%s]: %v", output.SessionId, sn, err)
+		if output.Closed || !sessionIdReg.MatchString(output.SessionId) {
+			return
+		}
+		if err = notifyEdge(sn, edgeShellRequest{SessionId: output.SessionId, Action: actionClose},
+			false); err != nil {
+			hwlog.RunLog.Errorf("close unknown remote shell session [%s] failed: %v", output.SessionId, err)
+		}
+		return
+	}
+	if len(output.Data) > 0 {
+		hwlog.OpLog.Infof("[%s@%s] remote shell session [%s] output: %q", info.user, info.ip, info.id, output.Data)
+	}
+	if closedNow {
+		hwlog.OpLog.Infof("[%s@%s] remote shell session [%s] on node [%s] closed: %s", info.user, info.ip, info.id,
+			info.serialNumber, output.Reason)
+	}
+}
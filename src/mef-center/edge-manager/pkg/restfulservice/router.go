@@ -25,9 +25,13 @@ import (
 	"huawei.com/mindxedge/base/common"
 	"huawei.com/mindxedge/base/common/restfulmgr"
 
+	"edge-manager/pkg/artifactmanager"
+	"edge-manager/pkg/cloudhub"
 	"edge-manager/pkg/config"
 	"edge-manager/pkg/constants"
+	"edge-manager/pkg/edgemsgmanager"
 	"edge-manager/pkg/logmanager"
+	"edge-manager/pkg/portforward"
 	"edge-manager/pkg/types"
 )
 
@@ -82,6 +86,115 @@ var configRouterDispatchers = map[string][]restfulmgr.DispatcherItf{
 	},
 }
 
+var certUpdateRouterDispatchers = map[string][]restfulmgr.DispatcherItf{
+	"/edgemanager/v1/cert/update": {
+		queryDispatcher{restfulmgr.GenericDispatcher{
+			RelativePath: "/status",
+			Method:       http.MethodGet,
+			Destination:  common.CertUpdaterName}, "certType", true},
+		restfulmgr.GenericDispatcher{
+			RelativePath: "/retry",
+			Method:       http.MethodPost,
+			Destination:  common.CertUpdaterName},
+	},
+}
+
+var outboxRouterDispatchers = map[string][]restfulmgr.DispatcherItf{
+	"/edgemanager/v1/outbox": {
+		queryDispatcher{restfulmgr.GenericDispatcher{
+			RelativePath: "/pending",
+			Method:       http.MethodGet,
+			Destination:  common.OutboxName}, "serialNumber", true},
+	},
+}
+
+var remoteShellRouterDispatchers = map[string][]restfulmgr.DispatcherItf{
+	constants.RemoteShellUrlPrefix: {
+		callerDispatcher{restfulmgr.GenericDispatcher{
+			Method:      http.MethodPost,
+			Destination: constants.RemoteShellName}, ""},
+		callerDispatcher{restfulmgr.GenericDispatcher{
+			RelativePath: "/input",
+			Method:       http.MethodPost,
+			Destination:  constants.RemoteShellName}, ""},
+		callerDispatcher{restfulmgr.GenericDispatcher{
+			RelativePath: "/resize",
+			Method:       http.MethodPost,
+			Destination:  constants.RemoteShellName}, ""},
+		callerDispatcher{restfulmgr.GenericDispatcher{
+			RelativePath: "/close",
+			Method:       http.MethodPost,
+			Destination:  constants.RemoteShellName}, ""},
+		callerDispatcher{restfulmgr.GenericDispatcher{
+			RelativePath: "/output",
+			Method:       http.MethodGet,
+			Destination:  constants.RemoteShellName}, "sessionId"},
+	},
+}
+
+var portForwardRouterDispatchers = map[string][]restfulmgr.DispatcherItf{
+	constants.PortForwardUrlPrefix: {
+		callerDispatcher{restfulmgr.GenericDispatcher{
+			Method:      http.MethodPost,
+			Destination: constants.PortForwardName}, ""},
+		callerDispatcher{restfulmgr.GenericDispatcher{
+			RelativePath: "/close",
+			Method:       http.MethodPost,
+			Destination:  constants.PortForwardName}, ""},
+		callerDispatcher{restfulmgr.GenericDispatcher{
+			Method:      http.MethodGet,
+			Destination: constants.PortForwardName}, "tunnelId"},
+		callerDispatcher{restfulmgr.GenericDispatcher{
+			RelativePath: "/list",
+			Method:       http.MethodGet,
+			Destination:  constants.PortForwardName}, ""},
+	},
+}
+
+var traceRouterDispatchers = map[string][]restfulmgr.DispatcherItf{
+	constants.TraceUrlPrefix: {
+		pathParamDispatcher{restfulmgr.GenericDispatcher{
+			RelativePath: "/:id",
+			Method:       http.MethodGet,
+			Destination:  constants.TraceName}, "id"},
+	},
+}
+
+var artifactRouterDispatchers = map[string][]restfulmgr.DispatcherItf{
+	constants.ArtifactUrlPrefix: {
+		restfulmgr.GenericDispatcher{
+			Method:      http.MethodPost,
+			Destination: constants.ArtifactManagerName},
+		restfulmgr.GenericDispatcher{
+			RelativePath: "/commit",
+			Method:       http.MethodPost,
+			Destination:  constants.ArtifactManagerName},
+		queryDispatcher{restfulmgr.GenericDispatcher{
+			Method:      http.MethodGet,
+			Destination: constants.ArtifactManagerName}, "id", true},
+		listDispatcher{restfulmgr.GenericDispatcher{
+			RelativePath: "/list",
+			Method:       http.MethodGet,
+			Destination:  constants.ArtifactManagerName}},
+		restfulmgr.GenericDispatcher{
+			RelativePath: "/batch-delete",
+			Method:       http.MethodPost,
+			Destination:  constants.ArtifactManagerName},
+		restfulmgr.GenericDispatcher{
+			RelativePath: "/site-cache",
+			Method:       http.MethodPost,
+			Destination:  constants.ArtifactManagerName},
+		restfulmgr.GenericDispatcher{
+			RelativePath: "/site-cache/list",
+			Method:       http.MethodGet,
+			Destination:  constants.ArtifactManagerName},
+		restfulmgr.GenericDispatcher{
+			RelativePath: "/site-cache/batch-delete",
+			Method:       http.MethodPost,
+			Destination:  constants.ArtifactManagerName},
+	},
+}
+
 var tokenRouterDispatchers = map[string][]restfulmgr.DispatcherItf{
 	"/edgemanager/v1/token": {
 		restfulmgr.GenericDispatcher{
@@ -109,6 +222,10 @@ func setRouter(engine *gin.Engine) {
 	engine.GET("/edgemanager/v1/version", versionQuery)
 	engine.GET(filepath.Join(constants.LogDumpUrlPrefix, constants.ResDownload, constants.EdgeNodesTarGzFileName),
 		logmanager.HandleDownload)
+	engine.POST(filepath.Join(constants.ArtifactUrlPrefix, "upload"), artifactmanager.HandleUpload)
+	engine.GET("/edgemanager/v1/software/edge/download-progress/stream", edgemsgmanager.HandleProgressStream)
+	engine.GET(constants.PortForwardStreamUrl, portforward.HandleStream)
+	engine.GET("/edgemanager/v1/node/conn-stats", cloudhub.HandleConnStats)
 	restfulmgr.InitRouter(engine, nodeRouterDispatchers)
 	restfulmgr.InitRouter(engine, nodeGroupRouterDispatchers)
 	restfulmgr.InitRouter(engine, appRouterDispatchers)
@@ -117,6 +234,12 @@ func setRouter(engine *gin.Engine) {
 	restfulmgr.InitRouter(engine, softwareRouterDispatchers)
 	restfulmgr.InitRouter(engine, logCollectRouterDispatchers)
 	restfulmgr.InitRouter(engine, tokenRouterDispatchers)
+	restfulmgr.InitRouter(engine, certUpdateRouterDispatchers)
+	restfulmgr.InitRouter(engine, outboxRouterDispatchers)
+	restfulmgr.InitRouter(engine, remoteShellRouterDispatchers)
+	restfulmgr.InitRouter(engine, portForwardRouterDispatchers)
+	restfulmgr.InitRouter(engine, traceRouterDispatchers)
+	restfulmgr.InitRouter(engine, artifactRouterDispatchers)
 }
 
 func versionQuery(c *gin.Context) {
@@ -212,6 +335,14 @@ var softwareRouterDispatchers = map[string][]restfulmgr.DispatcherItf{
 			RelativePath: "/upgrade",
 			Method:       http.MethodPost,
 			Destination:  common.NodeMsgManagerName},
+		restfulmgr.GenericDispatcher{
+			RelativePath: "/rollback",
+			Method:       http.MethodPost,
+			Destination:  common.NodeMsgManagerName},
+		restfulmgr.GenericDispatcher{
+			RelativePath: "/preflight",
+			Method:       http.MethodPost,
+			Destination:  common.NodeMsgManagerName},
 		queryDispatcher{restfulmgr.GenericDispatcher{
 			RelativePath: "/version-info",
 			Method:       http.MethodGet,
@@ -220,6 +351,30 @@ var softwareRouterDispatchers = map[string][]restfulmgr.DispatcherItf{
 			RelativePath: "/download-progress",
 			Method:       http.MethodGet,
 			Destination:  common.NodeMsgManagerName}, "serialNumber", true},
+		queryDispatcher{restfulmgr.GenericDispatcher{
+			RelativePath: "/download-progress/history",
+			Method:       http.MethodGet,
+			Destination:  common.NodeMsgManagerName}, "serialNumber", true},
+		restfulmgr.GenericDispatcher{
+			RelativePath: "/campaign",
+			Method:       http.MethodPost,
+			Destination:  common.NodeMsgManagerName},
+		queryDispatcher{restfulmgr.GenericDispatcher{
+			RelativePath: "/campaign",
+			Method:       http.MethodGet,
+			Destination:  common.NodeMsgManagerName}, "id", true},
+		restfulmgr.GenericDispatcher{
+			RelativePath: "/campaign/pause",
+			Method:       http.MethodPost,
+			Destination:  common.NodeMsgManagerName},
+		restfulmgr.GenericDispatcher{
+			RelativePath: "/campaign/resume",
+			Method:       http.MethodPost,
+			Destination:  common.NodeMsgManagerName},
+		restfulmgr.GenericDispatcher{
+			RelativePath: "/campaign/abort",
+			Method:       http.MethodPost,
+			Destination:  common.NodeMsgManagerName},
 	},
 }
 
@@ -293,6 +448,47 @@ func (list listDispatcher) ParseData(c *gin.Context) (interface{}, error) {
 	return pageUtil(c)
 }
 
+// callerDispatcher passes the caller along with the request body, or the query parameter for GET method
+type callerDispatcher struct {
+	restfulmgr.GenericDispatcher
+	queryName string
+}
+
+func (cd callerDispatcher) ParseData(c *gin.Context) (interface{}, error) {
+	req := types.CallerReq{User: fmt.Sprintf("%s", c.Request.Header["user"]), Ip: c.ClientIP()}
+	if c.Request.Method == http.MethodGet {
+		if cd.queryName == "" {
+			return req, nil
+		}
+		value, err := getStringReqPara(c, cd.queryName)
+		if err != nil {
+			return nil, err
+		}
+		req.Body = value
+		return req, nil
+	}
+	data, err := c.GetRawData()
+	if err != nil {
+		return nil, errors.New("get input parameter failed")
+	}
+	req.Body = string(data)
+	return req, nil
+}
+
+// pathParamDispatcher passes the parameter in url path
+type pathParamDispatcher struct {
+	restfulmgr.GenericDispatcher
+	paramName string
+}
+
+func (pd pathParamDispatcher) ParseData(c *gin.Context) (interface{}, error) {
+	value := c.Param(pd.paramName)
+	if value == "" {
+		return nil, fmt.Errorf("req path para [%s] is invalid", pd.paramName)
+	}
+	return value, nil
+}
+
 type queryNodeDispatcher struct {
 	restfulmgr.GenericDispatcher
 }
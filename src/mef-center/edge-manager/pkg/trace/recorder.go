@@ -0,0 +1,95 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package trace records the edge nodes which the traces passed through, so that the log lines of a trace can be
+// collected from these nodes on demand
+package trace
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	maxTraceRecords   = 4096
+	maxNodesPerTrace  = 64
+	traceRecordExpire = 24 * time.Hour
+)
+
+type traceRecord struct {
+	nodes     []string
+	updatedAt time.Time
+}
+
+type traceRecorder struct {
+	lock    sync.Mutex
+	records map[string]*traceRecord
+}
+
+var recorder = &traceRecorder{records: make(map[string]*traceRecord)}
+
+// RecordNode records that a message of the trace has been sent to the node
+func RecordNode(traceId, sn string) {
+	if traceId == "" || sn == "" {
+		return
+	}
+	recorder.add(traceId, sn)
+}
+
+func (r *traceRecorder) add(traceId, sn string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	record, ok := r.records[traceId]
+	if !ok {
+		if len(r.records) >= maxTraceRecords {
+			r.evict()
+		}
+		record = &traceRecord{}
+		r.records[traceId] = record
+	}
+	record.updatedAt = time.Now()
+	if len(record.nodes) >= maxNodesPerTrace {
+		return
+	}
+	for _, node := range record.nodes {
+		if node == sn {
+			return
+		}
+	}
+	record.nodes = append(record.nodes, sn)
+}
+
+// evict removes the expired records, or the oldest one if none is expired
+func (r *traceRecorder) evict() {
+	var oldestId string
+	var oldest time.Time
+	for id, record := range r.records {
+		if time.Since(record.updatedAt) > traceRecordExpire {
+			delete(r.records, id)
+			continue
+		}
+		if oldestId == "" || record.updatedAt.Before(oldest) {
+			oldestId, oldest = id, record.updatedAt
+		}
+	}
+	if len(r.records) >= maxTraceRecords {
+		delete(r.records, oldestId)
+	}
+}
+
+func (r *traceRecorder) nodes(traceId string) []string {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	record, ok := r.records[traceId]
+	if !ok || time.Since(record.updatedAt) > traceRecordExpire {
+		return nil
+	}
+	return append([]string{}, record.nodes...)
+}
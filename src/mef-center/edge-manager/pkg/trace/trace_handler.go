@@ -0,0 +1,247 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package trace handlers of trace query and the log lines reported by edge
+package trace
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+
+	"huawei.com/mindx/common/hwlog"
+	"huawei.com/mindx/common/modulemgr"
+	"huawei.com/mindx/common/modulemgr/model"
+
+	"huawei.com/mindxedge/base/common"
+
+	"edge-manager/pkg/constants"
+	"edge-manager/pkg/nodeprotocol"
+)
+
+const (
+	maxTraceQueries   = 8
+	traceQueryTimeout = 15 * time.Second
+	queryIdLen        = 16
+)
+
+var (
+	traceIdReg = regexp.MustCompile(`^[0-9a-f]{8}(-[0-9a-f]{4}){3}-[0-9a-f]{12}$`)
+	queryIdReg = regexp.MustCompile(`^[a-f0-9]{32}$`)
+)
+
+// NodeLogs log lines of a trace collected from an edge node
+type NodeLogs struct {
+	SerialNumber string   `json:"serialNumber"`
+	Lines        []string `json:"lines"`
+	Truncated    bool     `json:"truncated"`
+	Error        string   `json:"error,omitempty"`
+}
+
+// TraceResult log lines of a trace collected from the edge nodes which the trace passed through
+type TraceResult struct {
+	TraceId string     `json:"traceId"`
+	Nodes   []NodeLogs `json:"nodes"`
+}
+
+// edgeTraceLogRequest request sent to edge for collecting the log lines of a trace
+type edgeTraceLogRequest struct {
+	QueryId string `json:"queryId"`
+	TraceId string `json:"traceId"`
+}
+
+// edgeTraceLogData log lines of a trace reported by edge
+type edgeTraceLogData struct {
+	QueryId   string   `json:"queryId"`
+	Lines     []string `json:"lines"`
+	Truncated bool     `json:"truncated,omitempty"`
+	Error     string   `json:"error,omitempty"`
+}
+
+type traceQuery struct {
+	results map[string]*NodeLogs
+	pending int
+	done    chan struct{}
+}
+
+type queryMgr struct {
+	lock    sync.Mutex
+	queries map[string]*traceQuery
+}
+
+var queries = &queryMgr{queries: make(map[string]*traceQuery)}
+
+func newQueryId() (string, error) {
+	buf := make([]byte, queryIdLen)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generate query id failed: %v", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func (m *queryMgr) add(id string, sns []string) (*traceQuery, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if len(m.queries) >= maxTraceQueries {
+		return nil, fmt.Errorf("the count of trace queries reaches the limit %d", maxTraceQueries)
+	}
+	q := &traceQuery{results: make(map[string]*NodeLogs, len(sns)), pending: len(sns), done: make(chan struct{})}
+	for _, sn := range sns {
+		q.results[sn] = nil
+	}
+	m.queries[id] = q
+	return q, nil
+}
+
+func (m *queryMgr) remove(id string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	delete(m.queries, id)
+}
+
+// setResult sets the result of node, the reports of the nodes not queried or answered before are dropped
+func (m *queryMgr) setResult(id, sn string, logs *NodeLogs) bool {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	q, ok := m.queries[id]
+	if !ok {
+		return false
+	}
+	if result, ok := q.results[sn]; !ok || result != nil {
+		return false
+	}
+	q.results[sn] = logs
+	q.pending--
+	if q.pending == 0 {
+		close(q.done)
+	}
+	return true
+}
+
+// collect returns the results in the order of nodes, the nodes not answered are regarded as timeout
+func (m *queryMgr) collect(id string, sns []string) []NodeLogs {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	q := m.queries[id]
+	result := make([]NodeLogs, 0, len(sns))
+	for _, sn := range sns {
+		logs := q.results[sn]
+		if logs == nil {
+			logs = &NodeLogs{SerialNumber: sn, Lines: []string{}, Error: "wait for the log lines timeout"}
+		}
+		result = append(result, *logs)
+	}
+	return result
+}
+
+// queryTrace collects the log lines of trace from the edge nodes which the trace passed through
+func queryTrace(msg *model.Message) common.RespMsg {
+	var traceId string
+	if err := msg.ParseContent(&traceId); err != nil {
+		hwlog.RunLog.Errorf("query trace failed: parse content failed: %v", err)
+		return common.RespMsg{Status: common.ErrorParamConvert, Msg: "parse request failed", Data: nil}
+	}
+	if !traceIdReg.MatchString(traceId) {
+		hwlog.RunLog.Error("query trace failed: trace id is invalid")
+		return common.RespMsg{Status: common.ErrorParamInvalid, Msg: "trace id is invalid", Data: nil}
+	}
+	sns := recorder.nodes(traceId)
+	result := TraceResult{TraceId: traceId, Nodes: []NodeLogs{}}
+	if len(sns) == 0 {
+		hwlog.RunLog.Infof("trace [%s] did not pass through any edge node", traceId)
+		return common.RespMsg{Status: common.Success, Msg: "", Data: result}
+	}
+
+	queryId, err := newQueryId()
+	if err != nil {
+		hwlog.RunLog.Errorf("query trace [%s] failed: %v", traceId, err)
+		return common.RespMsg{Status: common.ErrorQueryTrace, Msg: "", Data: nil}
+	}
+	q, err := queries.add(queryId, sns)
+	if err != nil {
+		hwlog.RunLog.Errorf("query trace [%s] failed: %v", traceId, err)
+		return common.RespMsg{Status: common.ErrorQueryTrace, Msg: err.Error(), Data: nil}
+	}
+	defer queries.remove(queryId)
+
+	req := edgeTraceLogRequest{QueryId: queryId, TraceId: traceId}
+	for _, sn := range sns {
+		if err = nodeprotocol.CheckSupport(sn, nodeprotocol.FeatureTraceLog); err != nil {
+			hwlog.RunLog.Warnf("skip collecting trace log from node [%s]: %v", sn, err)
+			queries.setResult(queryId, sn, &NodeLogs{SerialNumber: sn, Lines: []string{}, Error: err.Error()})
+			continue
+		}
+		if err = notifyEdge(sn, req); err != nil {
+			hwlog.RunLog.Errorf("send trace log request to node [%s] failed: %v", sn, err)
+			queries.setResult(queryId, sn, &NodeLogs{SerialNumber: sn, Lines: []string{},
+				Error: "send request to node failed, the node may be disconnected"})
+		}
+	}
+	timer := time.NewTimer(traceQueryTimeout)
+	defer timer.Stop()
+	select {
+	case <-q.done:
+	case <-timer.C:
+		hwlog.RunLog.Warnf("wait for the log lines of trace [%s] timeout", traceId)
+	}
+	result.Nodes = queries.collect(queryId, sns)
+	hwlog.RunLog.Infof("query trace [%s] from %d nodes success", traceId, len(sns))
+	return common.RespMsg{Status: common.Success, Msg: "", Data: result}
+}
+
+// handleEdgeLogs passes the log lines reported by edge to the waiting query
+func handleEdgeLogs(msg *model.Message) {
+	var data edgeTraceLogData
+	if err := msg.ParseContent(&data); err != nil {
+		hwlog.RunLog.Errorf("parse trace log data failed: %v", err)
+		return
+	}
+	sn := msg.GetPeerInfo().Sn
+	if !queryIdReg.MatchString(data.QueryId) {
+		hwlog.RunLog.Errorf("query id of trace log data from node [%s] is invalid", sn)
+		return
+	}
+	if data.Lines == nil {
+		data.Lines = []string{}
+	}
+	logs := &NodeLogs{SerialNumber: sn, Lines: data.Lines, Truncated: data.Truncated, Error: data.Error}
+	if !queries.setResult(data.QueryId, sn, logs) {
+		hwlog.RunLog.Warnf("drop trace log data of query [%s] from node [%s]", data.QueryId, sn)
+	}
+}
+
+// notifyEdge send request to edge through cloudhub and wait for the request to be sent out
+func notifyEdge(sn string, req edgeTraceLogRequest) error {
+	msg, err := model.NewMessage()
+	if err != nil {
+		return fmt.Errorf("create new message failed: %v", err)
+	}
+	msg.SetNodeId(sn)
+	msg.SetRouter(constants.TraceName, common.CloudHubName, common.OptPost, constants.ResTraceLog)
+	if err = msg.FillContent(req, true); err != nil {
+		return fmt.Errorf("fill content failed: %v", err)
+	}
+	resp, err := modulemgr.SendSyncMessage(msg, common.ResponseTimeout)
+	if err != nil {
+		return fmt.Errorf("send message to %s failed: %v", common.CloudHubName, err)
+	}
+	var result string
+	if err = resp.ParseContent(&result); err != nil {
+		return fmt.Errorf("parse response failed: %v", err)
+	}
+	if result != common.OK {
+		return errors.New("send message to edge node failed")
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package trace to init trace query module
+package trace
+
+import (
+	"context"
+	"net/http"
+
+	"huawei.com/mindx/common/hwlog"
+	"huawei.com/mindx/common/modulemgr"
+	"huawei.com/mindx/common/modulemgr/model"
+
+	"huawei.com/mindxedge/base/common"
+
+	"edge-manager/pkg/constants"
+)
+
+type handlerFunc func(message *model.Message) common.RespMsg
+
+type traceManager struct {
+	enable bool
+	ctx    context.Context
+}
+
+// NewTraceManager create trace manager
+func NewTraceManager(enable bool) model.Module {
+	return &traceManager{
+		enable: enable,
+		ctx:    context.Background(),
+	}
+}
+
+func (tm *traceManager) Name() string {
+	return constants.TraceName
+}
+
+func (tm *traceManager) Enable() bool {
+	return tm.enable
+}
+
+func (tm *traceManager) Start() {
+	for {
+		select {
+		case _, ok := <-tm.ctx.Done():
+			if !ok {
+				hwlog.RunLog.Info("catch stop signal channel is closed")
+			}
+			hwlog.RunLog.Info("has listened stop signal")
+			return
+		default:
+		}
+
+		req, err := modulemgr.ReceiveMessage(tm.Name())
+		if err != nil {
+			hwlog.RunLog.Errorf("%s receive request failed", tm.Name())
+			continue
+		}
+
+		go tm.dispatch(req)
+	}
+}
+
+func (tm *traceManager) dispatch(req *model.Message) {
+	// log lines reported by edge is asynchronous, no response is needed
+	if req.GetOption() == common.OptReport && req.GetResource() == constants.ResTraceLogData {
+		handleEdgeLogs(req)
+		return
+	}
+	method, exist := handlerFuncMap[common.Combine(req.GetOption(), req.GetResource())]
+	if !exist {
+		hwlog.RunLog.Errorf("handler func is not exist, option: %s, resource: %s", req.GetOption(),
+			req.GetResource())
+		return
+	}
+	res := method(req)
+	resp, err := req.NewResponse()
+	if err != nil {
+		hwlog.RunLog.Errorf("%s new response failed", tm.Name())
+		return
+	}
+	if err = resp.FillContent(res); err != nil {
+		hwlog.RunLog.Errorf("%s fill content failed: %v", tm.Name(), err)
+		return
+	}
+	if err = modulemgr.SendMessage(resp); err != nil {
+		hwlog.RunLog.Errorf("%s send response failed", tm.Name())
+		return
+	}
+}
+
+var handlerFuncMap = map[string]handlerFunc{
+	common.Combine(http.MethodGet, constants.TraceUrlPrefix+"/:id"): queryTrace,
+}
@@ -0,0 +1,142 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package trace test for trace query
+package trace
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/smartystreets/goconvey/convey"
+
+	"huawei.com/mindx/common/modulemgr/model"
+
+	"huawei.com/mindxedge/base/common"
+
+	"edge-manager/pkg/nodeprotocol"
+)
+
+const (
+	testTraceId = "5f0c2a1e-7b3d-4c8e-9a6f-1d2e3f4a5b6c"
+	testSn      = "2102312NSF10K8000130"
+	testOtherSn = "2102312NSF10K8000131"
+)
+
+func newMsgForUT(sn string, content interface{}) *model.Message {
+	msg, err := model.NewMessage()
+	if err != nil {
+		panic(err)
+	}
+	msg.SetPeerInfo(model.MsgPeerInfo{Sn: sn})
+	if err = msg.FillContent(content, true); err != nil {
+		panic(err)
+	}
+	return msg
+}
+
+func resetRecorder() {
+	recorder.lock.Lock()
+	recorder.records = make(map[string]*traceRecord)
+	recorder.lock.Unlock()
+}
+
+func TestRecordNode(t *testing.T) {
+	convey.Convey("nodes of trace are recorded without duplication", t, func() {
+		resetRecorder()
+		RecordNode(testTraceId, testSn)
+		RecordNode(testTraceId, testSn)
+		RecordNode(testTraceId, testOtherSn)
+		RecordNode("", testSn)
+		convey.So(recorder.nodes(testTraceId), convey.ShouldResemble, []string{testSn, testOtherSn})
+	})
+
+	convey.Convey("the oldest trace is evicted when records are full", t, func() {
+		resetRecorder()
+		for i := 0; i < maxTraceRecords; i++ {
+			RecordNode(fmt.Sprintf("trace-%d", i), testSn)
+		}
+		recorder.lock.Lock()
+		recorder.records["trace-0"].updatedAt = time.Now().Add(-time.Hour)
+		recorder.lock.Unlock()
+		RecordNode(testTraceId, testSn)
+		convey.So(len(recorder.records), convey.ShouldEqual, maxTraceRecords)
+		convey.So(recorder.nodes("trace-0"), convey.ShouldBeNil)
+		convey.So(recorder.nodes(testTraceId), convey.ShouldResemble, []string{testSn})
+	})
+}
+
+func TestQueryTrace(t *testing.T) {
+	convey.Convey("invalid trace id is rejected", t, func() {
+		resp := queryTrace(newMsgForUT("", "../trace"))
+		convey.So(resp.Status, convey.ShouldEqual, common.ErrorParamInvalid)
+	})
+
+	convey.Convey("trace without edge node returns empty result", t, func() {
+		resetRecorder()
+		resp := queryTrace(newMsgForUT("", testTraceId))
+		convey.So(resp.Status, convey.ShouldEqual, common.Success)
+		convey.So(resp.Data.(TraceResult).Nodes, convey.ShouldBeEmpty)
+	})
+
+	convey.Convey("log lines are collected from the nodes of trace", t, func() {
+		resetRecorder()
+		RecordNode(testTraceId, testSn)
+		RecordNode(testTraceId, testOtherSn)
+		nodeprotocol.Record(testSn, nodeprotocol.CurrentVersion, []string{nodeprotocol.FeatureTraceLog.Capability})
+		nodeprotocol.Record(testOtherSn, nodeprotocol.CurrentVersion, []string{nodeprotocol.FeatureTraceLog.Capability})
+		patches := gomonkey.ApplyFunc(notifyEdge, func(sn string, req edgeTraceLogRequest) error {
+			if sn == testOtherSn {
+				return errors.New("node is disconnected")
+			}
+			go handleEdgeLogs(newMsgForUT(sn, edgeTraceLogData{QueryId: req.QueryId,
+				Lines: []string{"[edge_main] " + req.TraceId}}))
+			return nil
+		})
+		defer patches.Reset()
+		resp := queryTrace(newMsgForUT("", testTraceId))
+		convey.So(resp.Status, convey.ShouldEqual, common.Success)
+		nodes := resp.Data.(TraceResult).Nodes
+		convey.So(len(nodes), convey.ShouldEqual, 2)
+		convey.So(nodes[0].SerialNumber, convey.ShouldEqual, testSn)
+		convey.So(nodes[0].Lines, convey.ShouldResemble, []string{"[edge_main] " + testTraceId})
+		convey.So(nodes[1].Error, convey.ShouldNotBeEmpty)
+		convey.So(len(queries.queries), convey.ShouldEqual, 0)
+	})
+
+	convey.Convey("log lines are not collected from legacy node", t, func() {
+		resetRecorder()
+		RecordNode(testTraceId, testSn)
+		nodeprotocol.Record(testSn, 0, nil)
+		patches := gomonkey.ApplyFuncReturn(notifyEdge, errors.New("should not be called"))
+		defer patches.Reset()
+		resp := queryTrace(newMsgForUT("", testTraceId))
+		convey.So(resp.Status, convey.ShouldEqual, common.Success)
+		nodes := resp.Data.(TraceResult).Nodes
+		convey.So(len(nodes), convey.ShouldEqual, 1)
+		convey.So(nodes[0].Error, convey.ShouldContainSubstring, nodeprotocol.FeatureTraceLog.Name)
+	})
+}
+
+func TestHandleEdgeLogs(t *testing.T) {
+	convey.Convey("log lines from the node not queried are dropped", t, func() {
+		queryId := "0123456789abcdef0123456789abcdef"
+		_, err := queries.add(queryId, []string{testSn})
+		convey.So(err, convey.ShouldBeNil)
+		defer queries.remove(queryId)
+		handleEdgeLogs(newMsgForUT(testOtherSn, edgeTraceLogData{QueryId: queryId, Lines: []string{"x"}}))
+		handleEdgeLogs(newMsgForUT(testSn, edgeTraceLogData{QueryId: queryId}))
+		nodes := queries.collect(queryId, []string{testSn})
+		convey.So(nodes, convey.ShouldResemble, []NodeLogs{{SerialNumber: testSn, Lines: []string{}}})
+	})
+}
@@ -31,6 +31,14 @@ type ListReq struct {
 	Name     string
 }
 
+// CallerReq restful request with the user and client ip of the caller, used by the apis which bind resources to
+// the caller
+type CallerReq struct {
+	User string `json:"user"`
+	Ip   string `json:"ip"`
+	Body string `json:"body"`
+}
+
 // BatchResp batch request deal result
 type BatchResp struct {
 	SuccessIDs  []interface{}     `json:"successIDs"`
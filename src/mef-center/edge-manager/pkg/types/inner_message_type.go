@@ -95,11 +95,15 @@ type EdgeReportSoftwareInfoReq struct {
 	SoftwareInfo []SoftwareInfo `json:"softwareInfo"`
 }
 
-// ProgressInfo [struct] to report edge software download result info
+// ProgressInfo [struct] to report edge software download result info, DownloadedBytes and Rate (bytes per second)
+// are reported periodically while downloading
 type ProgressInfo struct {
-	Progress uint64 `json:"progress"`
-	Res      string `json:"res"`
-	Msg      string `json:"msg"`
+	Progress        uint64 `json:"progress"`
+	Res             string `json:"res"`
+	Msg             string `json:"msg"`
+	Stage           string `json:"stage,omitempty"`
+	DownloadedBytes int64  `json:"downloadedBytes,omitempty"`
+	Rate            int64  `json:"rate,omitempty"`
 }
 
 // EdgeDownloadResInfo [struct] to report edge software download result info
@@ -18,6 +18,7 @@ require (
 	huawei.com/mindx/common/limiter v0.0.0
 	huawei.com/mindx/common/modulemgr v0.0.1
 	huawei.com/mindx/common/rand v0.0.1
+	huawei.com/mindx/common/terminal v0.0.5
 	huawei.com/mindx/common/test v0.0.1
 	huawei.com/mindx/common/utils v0.1.13
 	huawei.com/mindx/common/x509 v0.0.12
@@ -60,7 +61,6 @@ require (
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 	gorm.io/driver/sqlite v1.5.3 // indirect
 	huawei.com/mindx/common/cache v0.0.0 // indirect
-	huawei.com/mindx/common/terminal v0.0.5 // indirect
 )
 
 replace (
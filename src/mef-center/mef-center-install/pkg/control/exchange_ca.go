@@ -16,10 +16,12 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"huawei.com/mindx/common/backuputils"
 	"huawei.com/mindx/common/fileutils"
 	"huawei.com/mindx/common/hwlog"
+	"huawei.com/mindx/common/terminal"
 	"huawei.com/mindx/common/x509"
 	"huawei.com/mindx/common/x509/certutils"
 
@@ -27,6 +29,16 @@ import (
 	"huawei.com/mindxedge/base/mef-center-install/pkg/util"
 )
 
+const (
+	stdinFd                = 0
+	minBundlePasswordLen   = 8
+	maxBundlePasswordLen   = 64
+	enterPasswordWaitTime  = time.Minute
+	importPasswordPrompt   = "Please enter the password of the importing %s file: "
+	exportPasswordPrompt   = "Please enter the password to protect the exporting %s file: "
+	exportPasswordReprompt = "Please enter the password again: "
+)
+
 // ExchangeCaFlow is used to exchange root ca with north module
 type ExchangeCaFlow struct {
 	pathMgr          *util.InstallDirPathMgr
@@ -38,6 +50,10 @@ type ExchangeCaFlow struct {
 	uid              uint32
 	gid              uint32
 	printFingerprint bool
+	importFormat     string
+	exportFormat     string
+	// importContent the importing pkcs12 or jks bundle converted to pem
+	importContent []byte
 }
 
 // NewExchangeCaFlow an ExchangeCaFlow struct
@@ -75,6 +91,12 @@ func NewExchangeCaFlow(importPath, exportPath, component string,
 	}, nil
 }
 
+// SetBundleFormats sets the format of importing and exporting file, the importing format is detected if empty
+func (ecf *ExchangeCaFlow) SetBundleFormats(importFormat, exportFormat string) {
+	ecf.importFormat = importFormat
+	ecf.exportFormat = exportFormat
+}
+
 // DoExchange is the main func to exchange certs
 func (ecf *ExchangeCaFlow) DoExchange() error {
 	var upgradeTasks = []func() error{
@@ -120,15 +142,84 @@ func (ecf *ExchangeCaFlow) checkParam() error {
 		hwlog.RunLog.Errorf("exportPath [%s] check failed, cannot overwrite existed file", ecf.exportPath)
 		return fmt.Errorf("exportPath [%s] check failed, cannot overwrite existed file", ecf.exportPath)
 	}
+	return ecf.checkBundleFormats()
+}
+
+func (ecf *ExchangeCaFlow) checkBundleFormats() error {
+	if ecf.exportFormat == "" {
+		ecf.exportFormat = x509.BundleFormatPem
+	}
+	for _, format := range []string{ecf.importFormat, ecf.exportFormat} {
+		if format != "" && !isSupportedBundleFormat(format) {
+			hwlog.RunLog.Errorf("bundle format [%s] is not supported", format)
+			return fmt.Errorf("bundle format [%s] is not supported, support: %v", format, x509.BundleFormats)
+		}
+	}
+	return nil
+}
+
+func isSupportedBundleFormat(format string) bool {
+	for _, supported := range x509.BundleFormats {
+		if format == supported {
+			return true
+		}
+	}
+	return false
+}
+
+func readBundlePassword(prompt string) ([]byte, error) {
+	fmt.Print(prompt)
+	password, err := terminal.ReadPasswordWithTimeout(stdinFd, maxBundlePasswordLen, enterPasswordWaitTime)
+	fmt.Println()
+	if err != nil {
+		hwlog.RunLog.Errorf("read password failed: %v", err)
+		return nil, errors.New("read password failed")
+	}
+	return password, nil
+}
+
+// loadImportBundle converts the importing pkcs12 or jks bundle to pem after verifying its integrity
+func (ecf *ExchangeCaFlow) loadImportBundle() error {
+	content, err := fileutils.LoadFile(ecf.importPath)
+	if err != nil {
+		hwlog.RunLog.Errorf("load importing file failed: %s", err.Error())
+		return errors.New("load importing file failed")
+	}
+	if ecf.importFormat == "" {
+		ecf.importFormat = x509.DetectBundleFormat(content)
+	}
+	if ecf.importFormat == x509.BundleFormatPem {
+		return nil
+	}
+	password, err := readBundlePassword(fmt.Sprintf(importPasswordPrompt, ecf.importFormat))
+	if err != nil {
+		return err
+	}
+	defer x509.PaddingAndCleanSlice(password)
+	caMgr, err := x509.NewCaChainMgrFromBundle(content, ecf.importFormat, password)
+	if err != nil {
+		hwlog.RunLog.Errorf("convert importing %s file failed: %s", ecf.importFormat, err.Error())
+		return fmt.Errorf("convert importing %s file failed", ecf.importFormat)
+	}
+	if err = caMgr.CheckCertChain(); err != nil {
+		hwlog.RunLog.Errorf("check importing cert failed: %s", err.Error())
+		return errors.New("check importing cert failed")
+	}
+	ecf.importContent = caMgr.ExportPem()
 	return nil
 }
 
 func (ecf *ExchangeCaFlow) checkCa() error {
 	hwlog.RunLog.Infof("start to check [%s] cert", ecf.component)
 
-	if _, err := x509.CheckCertsChainReturnContent(ecf.importPath); err != nil {
-		hwlog.RunLog.Errorf("check importing cert failed: %s", err.Error())
-		return fmt.Errorf("check importing cert failed")
+	if err := ecf.loadImportBundle(); err != nil {
+		return err
+	}
+	if ecf.importContent == nil {
+		if _, err := x509.CheckCertsChainReturnContent(ecf.importPath); err != nil {
+			hwlog.RunLog.Errorf("check importing cert failed: %s", err.Error())
+			return fmt.Errorf("check importing cert failed")
+		}
 	}
 
 	hash, err := fileutils.GetFileSha256(ecf.importPath)
@@ -224,7 +315,12 @@ func (ecf *ExchangeCaFlow) copyCaToCertManager() error {
 		hwlog.RunLog.Errorf("delete original crt [%s] failed: %s", ecf.savePath, err.Error())
 		return errors.New("delete original crt failed")
 	}
-	if err := ecf.copyWithRootPrivilege(ecf.importPath, ecf.savePath, true); err != nil {
+	if ecf.importContent != nil {
+		if err := ecf.writeWithRootPrivilege(ecf.importContent, ecf.savePath, true); err != nil {
+			hwlog.RunLog.Errorf("write converted crt to dst failed: %s", err.Error())
+			return errors.New("write converted crt to dst failed")
+		}
+	} else if err := ecf.copyWithRootPrivilege(ecf.importPath, ecf.savePath, true); err != nil {
 		hwlog.RunLog.Errorf("copy temp crt to dst failed: %s", err.Error())
 		return errors.New("copy temp crt to dst failed")
 	}
@@ -277,7 +373,8 @@ func (ecf *ExchangeCaFlow) exportCa() (err error) {
 		return fmt.Errorf("check path [%s] failed", ecf.srcPath)
 	}
 
-	if _, err = certutils.GetCertContentWithBackup(ecf.srcPath); err != nil {
+	caContent, err := certutils.GetCertContentWithBackup(ecf.srcPath)
+	if err != nil {
 		hwlog.RunLog.Errorf("check cert [%s] failed: %s, cannot export", ecf.srcPath, err.Error())
 		return fmt.Errorf("check cert [%s] failed", ecf.srcPath)
 	}
@@ -290,6 +387,9 @@ func (ecf *ExchangeCaFlow) exportCa() (err error) {
 		return errors.New("reset apig crt file right failed")
 	}
 
+	if ecf.exportFormat != x509.BundleFormatPem {
+		return ecf.exportBundle(caContent)
+	}
 	if err := ecf.copyWithRootPrivilege(ecf.srcPath, ecf.exportPath, false); err != nil {
 		hwlog.RunLog.Errorf("export ca failed: %s", err.Error())
 		return errors.New("export ca failed")
@@ -299,6 +399,53 @@ func (ecf *ExchangeCaFlow) exportCa() (err error) {
 	return nil
 }
 
+func readExportPassword(format string) ([]byte, error) {
+	password, err := readBundlePassword(fmt.Sprintf(exportPasswordPrompt, format))
+	if err != nil {
+		return nil, err
+	}
+	if len(password) < minBundlePasswordLen {
+		x509.PaddingAndCleanSlice(password)
+		return nil, fmt.Errorf("the length of password should be in range [%d, %d]",
+			minBundlePasswordLen, maxBundlePasswordLen)
+	}
+	confirm, err := readBundlePassword(exportPasswordReprompt)
+	if err != nil {
+		x509.PaddingAndCleanSlice(password)
+		return nil, err
+	}
+	defer x509.PaddingAndCleanSlice(confirm)
+	if string(password) != string(confirm) {
+		x509.PaddingAndCleanSlice(password)
+		return nil, errors.New("the two passwords are not the same")
+	}
+	return password, nil
+}
+
+func (ecf *ExchangeCaFlow) exportBundle(caContent []byte) error {
+	caMgr, err := x509.NewCaChainMgr(caContent)
+	if err != nil {
+		hwlog.RunLog.Errorf("parse ca failed: %s", err.Error())
+		return errors.New("parse ca failed")
+	}
+	password, err := readExportPassword(ecf.exportFormat)
+	if err != nil {
+		return err
+	}
+	defer x509.PaddingAndCleanSlice(password)
+	bundle, err := caMgr.ExportBundle(ecf.exportFormat, password)
+	if err != nil {
+		hwlog.RunLog.Errorf("convert ca to %s failed: %s", ecf.exportFormat, err.Error())
+		return fmt.Errorf("convert ca to %s failed", ecf.exportFormat)
+	}
+	if err = ecf.writeWithRootPrivilege(bundle, ecf.exportPath, false); err != nil {
+		hwlog.RunLog.Errorf("export ca failed: %s", err.Error())
+		return errors.New("export ca failed")
+	}
+	hwlog.RunLog.Infof("export ca in %s format success", ecf.exportFormat)
+	return nil
+}
+
 func (ecf *ExchangeCaFlow) setDirOwnerAndPermission(mode os.FileMode, recursive bool, paths ...string) error {
 	for _, path := range paths {
 		ownerParam := fileutils.SetOwnerParam{
@@ -339,6 +486,26 @@ func (ecf *ExchangeCaFlow) copyWithRootPrivilege(src, dst string, setOwnerAndPer
 	return ecf.setDirOwnerAndPermission(fileutils.Mode600, false, dst)
 }
 
+func (ecf *ExchangeCaFlow) writeWithRootPrivilege(content []byte, dst string, setOwnerAndPermission bool) error {
+	if err := util.ResetPriv(); err != nil {
+		hwlog.RunLog.Errorf("reset euid/gid back to root failed: %s", err.Error())
+		return errors.New("reset euid/gid back to root failed")
+	}
+	defer func() {
+		if err := util.ReducePriv(); err != nil {
+			hwlog.RunLog.Errorf("reduce euid/gid to MEFCenter failed: %s", err.Error())
+		}
+	}()
+
+	if err := fileutils.WriteData(dst, content); err != nil {
+		return err
+	}
+	if !setOwnerAndPermission {
+		return nil
+	}
+	return ecf.setDirOwnerAndPermission(fileutils.Mode600, false, dst)
+}
+
 func withMEFCenterUser(f func() error) func() error {
 	return func() error {
 		if err := util.ReducePriv(); err != nil {
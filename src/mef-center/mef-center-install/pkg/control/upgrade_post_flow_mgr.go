@@ -63,6 +63,7 @@ func (upf *UpgradePostFlowMgr) DoUpgrade() error {
 		upf.createFlag,
 		upf.prepareWorkCDir,
 		upf.prepareLogDumpDir,
+		upf.prepareArtifactDir,
 		upf.prepareCerts,
 		upf.prepareYaml,
 		upf.smoothUpgrade,
@@ -227,6 +228,16 @@ func (upf *UpgradePostFlowMgr) prepareLogDumpDir() error {
 	return nil
 }
 
+func (upf *UpgradePostFlowMgr) prepareArtifactDir() error {
+	hwlog.RunLog.Info("start to prepare artifact dir")
+	if err := util.PrepareArtifactDir(); err != nil {
+		hwlog.RunLog.Errorf("prepare artifact dir failed, %v", err)
+		return fmt.Errorf("prepare artifact dir failed, %v", err)
+	}
+	hwlog.RunLog.Info("prepare artifact dir successful")
+	return nil
+}
+
 func (upf *UpgradePostFlowMgr) prepareYaml() error {
 	hwlog.RunLog.Info("start to prepare components' yaml")
 	for _, component := range upf.Components {
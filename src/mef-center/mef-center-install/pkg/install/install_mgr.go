@@ -44,6 +44,7 @@ func (sic *SftInstallCtl) DoInstall() error {
 		sic.prepareComponentLogDir,
 		sic.prepareComponentLogBackupDir,
 		sic.prepareLogDumpDir,
+		sic.prepareArtifactDir,
 		sic.prepareWorkingDir,
 		sic.setInstallJson,
 		sic.prepareK8sLabel,
@@ -263,6 +264,16 @@ func (sic *SftInstallCtl) prepareLogDumpDir() error {
 	return nil
 }
 
+func (sic *SftInstallCtl) prepareArtifactDir() error {
+	hwlog.RunLog.Info("start to prepare artifact dir")
+	if err := util.PrepareArtifactDir(); err != nil {
+		hwlog.RunLog.Errorf("prepare artifact dir failed, %v", err)
+		return fmt.Errorf("prepare artifact dir failed, %v", err)
+	}
+	hwlog.RunLog.Info("prepare artifact dir successful")
+	return nil
+}
+
 func (sic *SftInstallCtl) prepareInstallPkgDir() error {
 	hwlog.RunLog.Info("start to prepare install_package dir")
 	installPkgDir := sic.InstallPathMgr.GetInstallPkgDir() + "/"
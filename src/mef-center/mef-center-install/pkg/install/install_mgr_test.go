@@ -50,6 +50,7 @@ func DoInstallMgrTest() {
 	convey.Convey("setCenterMode func", SetCenterModeTest)
 	convey.Convey("prepareInstallPkgDir func", PrepareInstallPkgDirTest)
 	convey.Convey("prepareLogDumpDir func", PrepareLogDumpDirTest)
+	convey.Convey("prepareArtifactDir func", PrepareArtifactDirTest)
 	convey.Convey("prepareConfigDir func", PrepareConfigDirTest)
 	convey.Convey("setConfigOwner func", SetConfigOwnerTest)
 	convey.Convey("configBackup func", ConfigBackupTest)
@@ -72,6 +73,7 @@ func DoInstallTest() {
 			ApplyPrivateMethod(ins, "setInstallJson", func(_ *SftInstallCtl) error { return nil }).
 			ApplyPrivateMethod(ins, "componentsInstall", func(_ *SftInstallCtl) error { return nil }).
 			ApplyPrivateMethod(ins, "prepareLogDumpDir", func(_ *SftInstallCtl) error { return nil }).
+			ApplyPrivateMethod(ins, "prepareArtifactDir", func(_ *SftInstallCtl) error { return nil }).
 			ApplyPrivateMethod(ins, "configBackup", func(_ *SftInstallCtl) error { return nil }).
 			ApplyPrivateMethod(ins, "setConfigOwner", func(_ *SftInstallCtl) error { return nil }).
 			ApplyPrivateMethod(ins, "setCenterMode", func(_ *SftInstallCtl) error { return nil })
@@ -617,6 +619,25 @@ func PrepareLogDumpDirTest() {
 	})
 }
 
+// PrepareArtifactDirTest tests the success and failure scenarios of preparing the artifact directory.
+func PrepareArtifactDirTest() {
+	ins, err := GetSftInstallMgrIns([]string{"edge-manager"}, "", "", "")
+	convey.So(err, convey.ShouldBeNil)
+
+	convey.Convey("test prepare artifact dir success", func() {
+		patch := gomonkey.ApplyFuncReturn(util.PrepareArtifactDir, nil)
+		defer patch.Reset()
+		convey.So(ins.prepareArtifactDir(), convey.ShouldBeNil)
+	})
+
+	convey.Convey("test prepare artifact dir failed", func() {
+		patch := gomonkey.ApplyFuncReturn(util.PrepareArtifactDir, ErrTest)
+		defer patch.Reset()
+		convey.So(ins.prepareArtifactDir(), convey.ShouldResemble,
+			fmt.Errorf("prepare artifact dir failed, %v", ErrTest))
+	})
+}
+
 // PrepareConfigDirTest tests the success and failure scenarios of preparing the configuration directory.
 func PrepareConfigDirTest() {
 	ins, err := GetSftInstallMgrIns([]string{"edge-manager"}, "", "", "")
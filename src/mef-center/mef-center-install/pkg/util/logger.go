@@ -29,6 +29,8 @@ const (
 
 	// LogDumpRootDir is the dir to store log-dumping temp files
 	LogDumpRootDir = "/var/mef_logcollect"
+	// ArtifactRootDir is the dir to store the artifacts hosted by edge-manager, it is kept across upgrades
+	ArtifactRootDir = "/var/mef_artifacts"
 )
 
 func newLogConfig(LogFileName string, logBackupDir string, maxBackups int) *hwlog.LogConfig {
@@ -89,3 +91,26 @@ func PrepareLogDumpDir() error {
 	}
 	return nil
 }
+
+// PrepareArtifactDir prepares the artifact dir, the artifacts already in it are kept
+func PrepareArtifactDir() error {
+	if _, err := fileutils.RealDirCheck(filepath.Dir(ArtifactRootDir), true, false); err != nil {
+		return fmt.Errorf("check parent dir of artifact dir failed, %v", err)
+	}
+	if fileutils.IsExist(ArtifactRootDir) {
+		if _, err := fileutils.RealDirCheck(ArtifactRootDir, true, false); err != nil {
+			return fmt.Errorf("check artifact dir failed, %v", err)
+		}
+	} else if err := fileutils.CreateDir(ArtifactRootDir, common.Mode700); err != nil {
+		return fmt.Errorf("create artifact dir failed, %v", err)
+	}
+	uid, gid, err := GetMefId()
+	if err != nil {
+		return fmt.Errorf("get mef id failed, %v", err)
+	}
+	if err = fileutils.SetPathOwnerGroup(
+		fileutils.SetOwnerParam{Path: ArtifactRootDir, Uid: uid, Gid: gid, Recursive: true}); err != nil {
+		return fmt.Errorf("set artifact dir ownership failed, %v", err)
+	}
+	return nil
+}
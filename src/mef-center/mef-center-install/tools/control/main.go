@@ -21,6 +21,7 @@ import (
 	"huawei.com/mindx/common/fileutils"
 	"huawei.com/mindx/common/hwlog"
 	"huawei.com/mindx/common/utils"
+	"huawei.com/mindx/common/x509"
 
 	"huawei.com/mindxedge/base/mef-center-install/pkg/control"
 	"huawei.com/mindxedge/base/mef-center-install/pkg/control/kmcupdate"
@@ -246,17 +247,25 @@ type exchangeCertsController struct {
 	installParam *util.InstallParamJsonTemplate
 	importPath   string
 	exportPath   string
+	importFormat string
+	exportFormat string
 	component    string
 }
 
 const (
-	importPathFlag = "import_path"
-	exportPathFlag = "export_path"
+	importPathFlag   = "import_path"
+	exportPathFlag   = "export_path"
+	importFormatFlag = "import_format"
+	exportFormatFlag = "export_format"
 )
 
 func (ecc *exchangeCertsController) bindFlag() bool {
 	flag.StringVar(&(ecc.importPath), importPathFlag, "", "path that saves ca cert to import")
 	flag.StringVar(&(ecc.exportPath), exportPathFlag, "", "path to export MEF ca cert")
+	flag.StringVar(&(ecc.importFormat), importFormatFlag, "",
+		"format of the importing file: pem, pkcs12 or jks, detected by content if not set")
+	flag.StringVar(&(ecc.exportFormat), exportFormatFlag, x509.BundleFormatPem,
+		"format of the exporting file: pem, pkcs12 or jks, pkcs12 and jks are protected by password")
 	utils.MarkFlagRequired(importPathFlag)
 	utils.MarkFlagRequired(exportPathFlag)
 	return true
@@ -276,6 +285,7 @@ func (ecc *exchangeCertsController) doControl() error {
 	if err != nil {
 		return err
 	}
+	exchangeFlow.SetBundleFormats(ecc.importFormat, ecc.exportFormat)
 	if err = exchangeFlow.DoExchange(); err != nil {
 		hwlog.RunLog.Errorf("execute exchange flow failed: %s", err.Error())
 		return err
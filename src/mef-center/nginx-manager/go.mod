@@ -4,6 +4,8 @@ go 1.20
 
 require (
 	github.com/gin-gonic/gin v1.10.0
+	github.com/smartystreets/goconvey v1.7.2
+	golang.org/x/crypto v0.23.0
 	huawei.com/mindx/common/backuputils v0.0.1
 	huawei.com/mindx/common/checker v0.0.3
 	huawei.com/mindx/common/envutils v0.0.4
@@ -13,6 +15,7 @@ require (
 	huawei.com/mindx/common/kmc v0.1.0
 	huawei.com/mindx/common/logmgmt v0.0.1
 	huawei.com/mindx/common/modulemgr v0.0.1
+	huawei.com/mindx/common/test v0.0.1
 	huawei.com/mindx/common/x509 v0.0.12
 	huawei.com/mindxedge/base v0.0.1
 )
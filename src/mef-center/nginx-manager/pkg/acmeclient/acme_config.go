@@ -0,0 +1,124 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package acmeclient obtain and renew the northbound nginx server cert by acme defined in RFC 8555
+package acmeclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"huawei.com/mindx/common/checker"
+	"huawei.com/mindx/common/fileutils"
+	"huawei.com/mindx/common/hwlog"
+
+	"nginx-manager/pkg/nginxcom"
+)
+
+// challenge types supported by acme client
+const (
+	ChallengeHttp01 = "http-01"
+	ChallengeDns01  = "dns-01"
+)
+
+const (
+	maxDomainCount     = 10
+	minRenewBeforeDays = 1
+	maxRenewBeforeDays = 60
+	hoursPerDay        = 24
+	acmeUrlReg         = `^https://[a-zA-Z0-9.:\-/_~%]{1,500}$`
+	// the optional fields below can be empty
+	emailReg      = `^$|^[a-zA-Z0-9._%+\-]{1,64}@[a-zA-Z0-9.\-]{1,190}$`
+	listenAddrReg = `^$|^(\d{1,3}(\.\d{1,3}){3})?:\d{1,5}$`
+	absPathReg    = `^$|^/[a-zA-Z0-9._\-/]{1,1023}$`
+)
+
+// AcmeConfig config of obtaining northbound server cert by acme
+type AcmeConfig struct {
+	Enable bool `json:"enable"`
+	// DirectoryUrl directory url of acme server, such as a local test server
+	DirectoryUrl string   `json:"directoryUrl"`
+	Email        string   `json:"email"`
+	Domains      []string `json:"domains"`
+	Challenge    string   `json:"challenge"`
+	// HttpListenAddr address on which the http-01 challenge responses are served
+	HttpListenAddr string `json:"httpListenAddr"`
+	// DnsHookPath executable to add or remove dns TXT record for dns-01 challenge
+	DnsHookPath string `json:"dnsHookPath"`
+	// CaFile root ca to verify the acme server, system root cas are used if empty
+	CaFile          string `json:"caFile"`
+	RenewBeforeDays int    `json:"renewBeforeDays"`
+}
+
+type acmeConfigChecker struct {
+	modelChecker checker.ModelChecker
+}
+
+func (a *acmeConfigChecker) init() {
+	a.modelChecker.Checker = checker.GetAndChecker(
+		checker.GetRegChecker("DirectoryUrl", acmeUrlReg, true),
+		checker.GetRegChecker("Email", emailReg, false),
+		checker.GetUniqueListChecker("Domains",
+			checker.GetDomainChecker("", true, false, true), 1, maxDomainCount, true),
+		checker.GetStringChoiceChecker("Challenge", []string{ChallengeHttp01, ChallengeDns01}, true),
+		checker.GetRegChecker("HttpListenAddr", listenAddrReg, false),
+		checker.GetRegChecker("DnsHookPath", absPathReg, false),
+		checker.GetRegChecker("CaFile", absPathReg, false),
+		checker.GetIntChecker("RenewBeforeDays", minRenewBeforeDays, maxRenewBeforeDays, true),
+	)
+}
+
+// Check [method] check main function
+func (a *acmeConfigChecker) Check(data interface{}) checker.CheckResult {
+	a.init()
+	checkResult := a.modelChecker.Check(data)
+	if !checkResult.Result {
+		return checker.NewFailedResult(fmt.Sprintf("acme config check failed: %s", checkResult.Reason))
+	}
+	cfg, ok := data.(AcmeConfig)
+	if !ok {
+		return checker.NewFailedResult("acme config check failed: invalid data type")
+	}
+	if cfg.Challenge == ChallengeHttp01 && cfg.HttpListenAddr == "" {
+		return checker.NewFailedResult("acme config check failed: httpListenAddr is required by http-01")
+	}
+	if cfg.Challenge == ChallengeDns01 && cfg.DnsHookPath == "" {
+		return checker.NewFailedResult("acme config check failed: dnsHookPath is required by dns-01")
+	}
+	return checker.NewSuccessResult()
+}
+
+func (c *AcmeConfig) renewBefore() time.Duration {
+	return time.Duration(c.RenewBeforeDays) * hoursPerDay * time.Hour
+}
+
+// LoadAcmeConfig load acme config, return nil if acme is not configured or not enabled
+func LoadAcmeConfig() (*AcmeConfig, error) {
+	if !fileutils.IsExist(nginxcom.AcmeConfigFile) {
+		return nil, nil
+	}
+	content, err := fileutils.LoadFile(nginxcom.AcmeConfigFile)
+	if err != nil {
+		return nil, fmt.Errorf("load acme config failed: %v", err)
+	}
+	var cfg AcmeConfig
+	if err = json.Unmarshal(content, &cfg); err != nil {
+		return nil, fmt.Errorf("unmarshal acme config failed: %v", err)
+	}
+	if !cfg.Enable {
+		hwlog.RunLog.Info("acme is not enabled, northbound server cert is issued by mef")
+		return nil, nil
+	}
+	if checkResult := (&acmeConfigChecker{}).Check(cfg); !checkResult.Result {
+		return nil, fmt.Errorf("check acme config failed: %s", checkResult.Reason)
+	}
+	return &cfg, nil
+}
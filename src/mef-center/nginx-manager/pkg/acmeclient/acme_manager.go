@@ -0,0 +1,349 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package acmeclient obtain the northbound server cert from acme server
+package acmeclient
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/acme"
+
+	"huawei.com/mindx/common/fileutils"
+	"huawei.com/mindx/common/hwlog"
+	hwX509 "huawei.com/mindx/common/x509"
+	"huawei.com/mindx/common/x509/certutils"
+
+	"nginx-manager/pkg/nginxcom"
+)
+
+const (
+	accountKeyLength = 3072
+	serverKeyLength  = 3072
+	acmeOrderTimeout = 10 * time.Minute
+	acmeHttpTimeout  = 30 * time.Second
+	acmeTempSuffix   = ".acme"
+	acmeBackupSuffix = ".acme.bak"
+	emailPrefix      = "mailto:"
+)
+
+// Manager obtain and renew the northbound server cert by acme
+type Manager struct {
+	cfg            *AcmeConfig
+	certPath       string
+	keyPath        string
+	accountKeyPath string
+}
+
+// NewManager create the acme manager with the loaded config
+func NewManager(cfg *AcmeConfig) *Manager {
+	return &Manager{
+		cfg:            cfg,
+		certPath:       nginxcom.ServerCertFile,
+		keyPath:        nginxcom.ServerCertKeyFile,
+		accountKeyPath: nginxcom.AcmeAccountKeyFile,
+	}
+}
+
+// RenewBefore the duration before expiration to renew the cert
+func (m *Manager) RenewBefore() time.Duration {
+	return m.cfg.renewBefore()
+}
+
+// NeedRenew check whether the cert is missing, does not match the key, is not for the configured domains
+// or is about to expire
+func (m *Manager) NeedRenew() bool {
+	cert, err := loadLeafCert(m.certPath)
+	if err != nil {
+		hwlog.RunLog.Infof("northbound server cert is not usable: %v, it needs to be obtained by acme", err)
+		return true
+	}
+	if err = checkKeyMatch(m.certPath, m.keyPath); err != nil {
+		hwlog.RunLog.Infof("northbound server key is not usable: %v, it needs to be obtained by acme", err)
+		return true
+	}
+	for _, domain := range m.cfg.Domains {
+		if err = cert.VerifyHostname(domain); err != nil {
+			hwlog.RunLog.Infof("northbound server cert does not match the domain %s, it needs to be renewed", domain)
+			return true
+		}
+	}
+	if time.Until(cert.NotAfter) < m.cfg.renewBefore() {
+		hwlog.RunLog.Infof("northbound server cert expires at %s, it needs to be renewed",
+			cert.NotAfter.Format(time.RFC3339))
+		return true
+	}
+	return false
+}
+
+func loadLeafCert(certPath string) (*x509.Certificate, error) {
+	if !fileutils.IsExist(certPath) {
+		return nil, errors.New("cert file does not exist")
+	}
+	certBytes, err := fileutils.LoadFile(certPath)
+	if err != nil {
+		return nil, fmt.Errorf("load cert failed: %v", err)
+	}
+	block, _ := pem.Decode(certBytes)
+	if block == nil {
+		return nil, errors.New("decode cert pem failed")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse cert failed: %v", err)
+	}
+	if time.Now().After(cert.NotAfter) {
+		return nil, errors.New("cert is expired")
+	}
+	return cert, nil
+}
+
+func (m *Manager) newHttpClient() (*http.Client, error) {
+	tlsCfg := &tls.Config{MinVersion: tls.VersionTLS12}
+	if m.cfg.CaFile != "" {
+		caBytes, err := fileutils.LoadFile(m.cfg.CaFile)
+		if err != nil {
+			return nil, fmt.Errorf("load acme server ca failed: %v", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caBytes) {
+			return nil, errors.New("parse acme server ca failed")
+		}
+		tlsCfg.RootCAs = pool
+	}
+	return &http.Client{
+		Timeout:   acmeHttpTimeout,
+		Transport: &http.Transport{TLSClientConfig: tlsCfg, Proxy: http.ProxyFromEnvironment},
+	}, nil
+}
+
+// checkKeyMatch check whether the key is the one of the cert, they are mismatched if the replacement is interrupted
+func checkKeyMatch(certPath, keyPath string) error {
+	pair, err := certutils.GetCertPairForPem(certPath, keyPath, nil)
+	if err != nil {
+		return err
+	}
+	defer hwX509.PaddingAndCleanSlice(pair.KeyPem)
+	if _, err = tls.X509KeyPair(pair.CertPem, pair.KeyPem); err != nil {
+		return fmt.Errorf("key does not match the cert: %v", err)
+	}
+	return nil
+}
+
+// loadAccountKey load the account key encrypted by kmc, and create a new one if not exist
+func loadAccountKey(accountKeyPath string) (*rsa.PrivateKey, error) {
+	if fileutils.IsExist(accountKeyPath) {
+		keyPem, err := certutils.GetKeyContentWithBackup(accountKeyPath, nil)
+		if err != nil {
+			return nil, fmt.Errorf("load acme account key failed: %v", err)
+		}
+		priv := certutils.PemUnwrapPrivKey(keyPem)
+		if priv == nil {
+			return nil, errors.New("parse acme account key failed")
+		}
+		return priv, nil
+	}
+	priv, err := rsa.GenerateKey(rand.Reader, accountKeyLength)
+	if err != nil {
+		return nil, fmt.Errorf("generate acme account key failed: %v", err)
+	}
+	if err = certutils.SaveKeyWithPem(accountKeyPath, priv, nil); err != nil {
+		return nil, fmt.Errorf("save acme account key failed: %v", err)
+	}
+	hwlog.RunLog.Info("create acme account key success")
+	return priv, nil
+}
+
+func (m *Manager) newClient(ctx context.Context) (*acme.Client, error) {
+	accountKey, err := loadAccountKey(m.accountKeyPath)
+	if err != nil {
+		return nil, err
+	}
+	httpClient, err := m.newHttpClient()
+	if err != nil {
+		return nil, err
+	}
+	client := &acme.Client{Key: accountKey, HTTPClient: httpClient, DirectoryURL: m.cfg.DirectoryUrl}
+	account := &acme.Account{}
+	if m.cfg.Email != "" {
+		account.Contact = []string{emailPrefix + m.cfg.Email}
+	}
+	if _, err = client.Register(ctx, account, acme.AcceptTOS); err != nil &&
+		!errors.Is(err, acme.ErrAccountAlreadyExists) {
+		return nil, fmt.Errorf("register acme account failed: %v", err)
+	}
+	return client, nil
+}
+
+func pickChallenge(authz *acme.Authorization, challengeType string) *acme.Challenge {
+	for _, chal := range authz.Challenges {
+		if chal.Type == challengeType {
+			return chal
+		}
+	}
+	return nil
+}
+
+func (m *Manager) authorize(ctx context.Context, client *acme.Client, solver ChallengeSolver, authzUrl string) error {
+	authz, err := client.GetAuthorization(ctx, authzUrl)
+	if err != nil {
+		return fmt.Errorf("get authorization failed: %v", err)
+	}
+	if authz.Status == acme.StatusValid {
+		return nil
+	}
+	domain := authz.Identifier.Value
+	chal := pickChallenge(authz, solver.Type())
+	if chal == nil {
+		return fmt.Errorf("acme server does not offer %s challenge for %s", solver.Type(), domain)
+	}
+	defer func() {
+		if err := solver.CleanUp(client, domain, chal); err != nil {
+			hwlog.RunLog.Warnf("clean up %s challenge for %s failed: %v", solver.Type(), domain, err)
+		}
+	}()
+	if err = solver.Present(client, domain, chal); err != nil {
+		return fmt.Errorf("present %s challenge for %s failed: %v", solver.Type(), domain, err)
+	}
+	if _, err = client.Accept(ctx, chal); err != nil {
+		return fmt.Errorf("accept %s challenge for %s failed: %v", solver.Type(), domain, err)
+	}
+	if _, err = client.WaitAuthorization(ctx, authz.URI); err != nil {
+		return fmt.Errorf("wait authorization for %s failed: %v", domain, err)
+	}
+	hwlog.RunLog.Infof("authorization for %s is valid", domain)
+	return nil
+}
+
+// ObtainCert obtain the northbound server cert from acme server, and replace the current cert and key,
+// the locker is held when replacing files, so that nginx does not reload with a mismatched pair
+func (m *Manager) ObtainCert(ctx context.Context, locker *sync.Mutex) error {
+	ctx, cancel := context.WithTimeout(ctx, acmeOrderTimeout)
+	defer cancel()
+	solver, err := newSolver(m.cfg)
+	if err != nil {
+		return err
+	}
+	client, err := m.newClient(ctx)
+	if err != nil {
+		return err
+	}
+	order, err := client.AuthorizeOrder(ctx, acme.DomainIDs(m.cfg.Domains...))
+	if err != nil {
+		return fmt.Errorf("create acme order failed: %v", err)
+	}
+	for _, authzUrl := range order.AuthzURLs {
+		if err = m.authorize(ctx, client, solver, authzUrl); err != nil {
+			return err
+		}
+	}
+	if order, err = client.WaitOrder(ctx, order.URI); err != nil {
+		return fmt.Errorf("wait acme order ready failed: %v", err)
+	}
+
+	tempKeyPath := m.keyPath + acmeTempSuffix
+	tempCertPath := m.certPath + acmeTempSuffix
+	defer func() {
+		if err := fileutils.DeleteAllFileWithConfusion(tempKeyPath); err != nil {
+			hwlog.RunLog.Warnf("remove temp key file failed: %v", err)
+		}
+		if err := fileutils.DeleteFile(tempCertPath); err != nil {
+			hwlog.RunLog.Warnf("remove temp cert file failed: %v", err)
+		}
+	}()
+	csr, err := createServerCsr(tempKeyPath, m.cfg.Domains)
+	if err != nil {
+		return fmt.Errorf("create csr for acme order failed: %v", err)
+	}
+	chain, _, err := client.CreateOrderCert(ctx, order.FinalizeURL, csr, true)
+	if err != nil {
+		return fmt.Errorf("finalize acme order failed: %v", err)
+	}
+	var certPem []byte
+	for _, der := range chain {
+		certPem = append(certPem, certutils.PemWrapCert(der)...)
+	}
+	if err = fileutils.WriteData(tempCertPath, certPem); err != nil {
+		return fmt.Errorf("save cert from acme server failed: %v", err)
+	}
+	return m.replaceServerCert(tempKeyPath, tempCertPath, locker)
+}
+
+// createServerCsr create the csr of server cert for the domains, the first domain is taken as the common name,
+// the new key is saved to keyPath
+func createServerCsr(keyPath string, domains []string) ([]byte, error) {
+	priv, err := rsa.GenerateKey(rand.Reader, serverKeyLength)
+	if err != nil {
+		return nil, fmt.Errorf("generate server key failed: %v", err)
+	}
+	template := x509.CertificateRequest{
+		Subject:  pkix.Name{CommonName: domains[0]},
+		DNSNames: domains,
+	}
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &template, priv)
+	if err != nil {
+		return nil, fmt.Errorf("generate server csr failed: %v", err)
+	}
+	if err = certutils.SaveKeyWithPem(keyPath, priv, nil); err != nil {
+		return nil, fmt.Errorf("save server key failed: %v", err)
+	}
+	return csr, nil
+}
+
+// replaceServerCert replace the key and then the cert, the old key is restored if the cert is not replaced,
+// so that the key always matches the cert in use
+func (m *Manager) replaceServerCert(tempKeyPath, tempCertPath string, locker *sync.Mutex) error {
+	locker.Lock()
+	defer locker.Unlock()
+	backupKeyPath := m.keyPath + acmeBackupSuffix
+	hasOldKey := fileutils.IsExist(m.keyPath)
+	if hasOldKey {
+		if err := fileutils.CopyFile(m.keyPath, backupKeyPath); err != nil {
+			return fmt.Errorf("backup key file error: %v", err)
+		}
+		defer func() {
+			if err := fileutils.DeleteAllFileWithConfusion(backupKeyPath); err != nil {
+				hwlog.RunLog.Warnf("remove backup key file failed: %v", err)
+			}
+		}()
+	}
+	if err := fileutils.RenameFile(tempKeyPath, m.keyPath); err != nil {
+		return fmt.Errorf("replace key file error: %v", err)
+	}
+	if err := fileutils.RenameFile(tempCertPath, m.certPath); err != nil {
+		if rollbackErr := m.rollbackKey(backupKeyPath, hasOldKey); rollbackErr != nil {
+			hwlog.RunLog.Errorf("restore key file failed: %v", rollbackErr)
+		}
+		return fmt.Errorf("replace cert file error: %v", err)
+	}
+	hwlog.RunLog.Info("replace northbound server cert with the one from acme server success")
+	return nil
+}
+
+func (m *Manager) rollbackKey(backupKeyPath string, hasOldKey bool) error {
+	if !hasOldKey {
+		return fileutils.DeleteAllFileWithConfusion(m.keyPath)
+	}
+	if err := fileutils.RenameFile(backupKeyPath, m.keyPath); err != nil {
+		return err
+	}
+	return fileutils.SetPathPermission(m.keyPath, fileutils.Mode400, false, false)
+}
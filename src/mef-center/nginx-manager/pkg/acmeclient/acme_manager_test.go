@@ -0,0 +1,344 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package acmeclient test for obtaining the northbound server cert from a local acme server
+package acmeclient
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/smartystreets/goconvey/convey"
+
+	"huawei.com/mindx/common/fileutils"
+	"huawei.com/mindx/common/test"
+	"huawei.com/mindx/common/x509/certutils"
+)
+
+const (
+	testDomain      = "mef.example.com"
+	testAltDomain   = "www.mef.example.com"
+	testKeyLength   = 2048
+	testCertDays    = 90
+	testRenewDays   = 30
+	testChalToken   = "test-token"
+	testNonce       = "test-nonce"
+	statusPending   = "pending"
+	statusValid     = "valid"
+	wellKnownPrefix = "/.well-known/acme-challenge/"
+)
+
+func TestMain(m *testing.M) {
+	tcBase := &test.TcBase{}
+	test.RunWithPatches(tcBase, m, nil)
+}
+
+// acmeStub a minimal acme server which validates http-01 challenges and issues certs by a test ca
+type acmeStub struct {
+	server      *httptest.Server
+	caCert      *x509.Certificate
+	caKey       *rsa.PrivateKey
+	chalAddr    string
+	lock        sync.Mutex
+	authzStatus string
+	issued      []byte
+}
+
+func newAcmeStub(chalAddr string) *acmeStub {
+	stub := &acmeStub{chalAddr: chalAddr, authzStatus: statusPending}
+	stub.caKey, stub.caCert = newTestCa()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/directory", stub.directory)
+	mux.HandleFunc("/new-nonce", stub.nonce)
+	mux.HandleFunc("/new-account", stub.newAccount)
+	mux.HandleFunc("/new-order", stub.order)
+	mux.HandleFunc("/order", stub.order)
+	mux.HandleFunc("/authz", stub.authz)
+	mux.HandleFunc("/chal", stub.challenge)
+	mux.HandleFunc("/finalize", stub.finalize)
+	mux.HandleFunc("/cert", stub.cert)
+	stub.server = httptest.NewTLSServer(mux)
+	return stub
+}
+
+func newTestCa() (*rsa.PrivateKey, *x509.Certificate) {
+	key, err := rsa.GenerateKey(rand.Reader, testKeyLength)
+	convey.So(err, convey.ShouldBeNil)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "acme test ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().AddDate(1, 0, 0),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	convey.So(err, convey.ShouldBeNil)
+	cert, err := x509.ParseCertificate(der)
+	convey.So(err, convey.ShouldBeNil)
+	return key, cert
+}
+
+func (s *acmeStub) url(path string) string {
+	return s.server.URL + path
+}
+
+func (s *acmeStub) writeJson(w http.ResponseWriter, status int, location string, data interface{}) {
+	w.Header().Set("Replay-Nonce", testNonce)
+	w.Header().Set("Content-Type", "application/json")
+	if location != "" {
+		w.Header().Set("Location", location)
+	}
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		fmt.Printf("write acme stub response failed: %v\n", err)
+	}
+}
+
+func (s *acmeStub) directory(w http.ResponseWriter, _ *http.Request) {
+	s.writeJson(w, http.StatusOK, "", map[string]string{
+		"newNonce":   s.url("/new-nonce"),
+		"newAccount": s.url("/new-account"),
+		"newOrder":   s.url("/new-order"),
+	})
+}
+
+func (s *acmeStub) nonce(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Replay-Nonce", testNonce)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *acmeStub) newAccount(w http.ResponseWriter, _ *http.Request) {
+	s.writeJson(w, http.StatusCreated, s.url("/account"), map[string]string{"status": statusValid})
+}
+
+func (s *acmeStub) order(w http.ResponseWriter, r *http.Request) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	status := s.authzStatus
+	if status == statusValid {
+		status = "ready"
+	}
+	if s.issued != nil {
+		status = statusValid
+	}
+	httpStatus := http.StatusOK
+	if r.URL.Path == "/new-order" {
+		httpStatus = http.StatusCreated
+	}
+	s.writeJson(w, httpStatus, s.url("/order"), map[string]interface{}{
+		"status":         status,
+		"authorizations": []string{s.url("/authz")},
+		"finalize":       s.url("/finalize"),
+		"certificate":    s.url("/cert"),
+	})
+}
+
+func (s *acmeStub) authz(w http.ResponseWriter, _ *http.Request) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.writeJson(w, http.StatusOK, "", map[string]interface{}{
+		"status":     s.authzStatus,
+		"identifier": map[string]string{"type": "dns", "value": testDomain},
+		"challenges": []map[string]string{
+			{"type": ChallengeDns01, "url": s.url("/chal"), "token": testChalToken, "status": statusPending},
+			{"type": ChallengeHttp01, "url": s.url("/chal"), "token": testChalToken, "status": statusPending},
+		},
+	})
+}
+
+// challenge visit the http-01 challenge server like a real acme server
+func (s *acmeStub) challenge(w http.ResponseWriter, _ *http.Request) {
+	resp, err := http.Get("http://" + s.chalAddr + wellKnownPrefix + testChalToken)
+	status := "invalid"
+	if err == nil {
+		body, readErr := io.ReadAll(resp.Body)
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			fmt.Printf("close challenge response failed: %v\n", closeErr)
+		}
+		if readErr == nil && strings.HasPrefix(string(body), testChalToken+".") {
+			status = statusValid
+		}
+	}
+	s.lock.Lock()
+	s.authzStatus = status
+	s.lock.Unlock()
+	s.writeJson(w, http.StatusOK, "", map[string]string{
+		"type": ChallengeHttp01, "url": s.url("/chal"), "token": testChalToken, "status": status,
+	})
+}
+
+func parseJwsPayload(r *http.Request, payload interface{}) error {
+	var jws struct {
+		Payload string `json:"payload"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&jws); err != nil {
+		return err
+	}
+	data, err := base64.RawURLEncoding.DecodeString(jws.Payload)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, payload)
+}
+
+func (s *acmeStub) finalize(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Csr string `json:"csr"`
+	}
+	if err := parseJwsPayload(r, &req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	csrDer, err := base64.RawURLEncoding.DecodeString(req.Csr)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	csr, err := x509.ParseCertificateRequest(csrDer)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      csr.Subject,
+		DNSNames:     csr.DNSNames,
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().AddDate(0, 0, testCertDays),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, s.caCert, csr.PublicKey, s.caKey)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	s.lock.Lock()
+	s.issued = append(certutils.PemWrapCert(der), certutils.PemWrapCert(s.caCert.Raw)...)
+	s.lock.Unlock()
+	s.order(w, r)
+}
+
+func (s *acmeStub) cert(w http.ResponseWriter, _ *http.Request) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	w.Header().Set("Replay-Nonce", testNonce)
+	w.Header().Set("Content-Type", "application/pem-certificate-chain")
+	if _, err := w.Write(s.issued); err != nil {
+		fmt.Printf("write cert chain failed: %v\n", err)
+	}
+}
+
+func getFreeAddr() string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	convey.So(err, convey.ShouldBeNil)
+	addr := listener.Addr().String()
+	convey.So(listener.Close(), convey.ShouldBeNil)
+	return addr
+}
+
+func newManagerForUT(dir string, stub *acmeStub) *Manager {
+	caFile := filepath.Join(dir, "acme-ca.crt")
+	convey.So(fileutils.WriteData(caFile, certutils.PemWrapCert(stub.server.Certificate().Raw)), convey.ShouldBeNil)
+	cfg := &AcmeConfig{
+		Enable:          true,
+		DirectoryUrl:    stub.url("/directory"),
+		Domains:         []string{testDomain, testAltDomain},
+		Challenge:       ChallengeHttp01,
+		HttpListenAddr:  stub.chalAddr,
+		CaFile:          caFile,
+		RenewBeforeDays: testRenewDays,
+	}
+	return &Manager{
+		cfg:            cfg,
+		certPath:       filepath.Join(dir, "server.crt"),
+		keyPath:        filepath.Join(dir, "server.key"),
+		accountKeyPath: filepath.Join(dir, "acme-account.key"),
+	}
+}
+
+func TestObtainCert(t *testing.T) {
+	convey.Convey("obtain cert from local acme server should be success", t, func() {
+		stub := newAcmeStub(getFreeAddr())
+		defer stub.server.Close()
+		manager := newManagerForUT(t.TempDir(), stub)
+		convey.So(manager.NeedRenew(), convey.ShouldBeTrue)
+
+		convey.So(manager.ObtainCert(context.Background(), &sync.Mutex{}), convey.ShouldBeNil)
+		cert, err := loadLeafCert(manager.certPath)
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(cert.Subject.CommonName, convey.ShouldEqual, testDomain)
+		convey.So(cert.Subject.Organization, convey.ShouldBeEmpty)
+		convey.So(cert.DNSNames, convey.ShouldResemble, []string{testDomain, testAltDomain})
+		convey.So(checkKeyMatch(manager.certPath, manager.keyPath), convey.ShouldBeNil)
+		convey.So(manager.NeedRenew(), convey.ShouldBeFalse)
+		convey.So(fileutils.IsExist(manager.keyPath+acmeTempSuffix), convey.ShouldBeFalse)
+		convey.So(fileutils.IsExist(manager.certPath+acmeTempSuffix), convey.ShouldBeFalse)
+	})
+
+	convey.Convey("obtain cert should be failed, the challenge is not served", t, func() {
+		stub := newAcmeStub(getFreeAddr())
+		defer stub.server.Close()
+		manager := newManagerForUT(t.TempDir(), stub)
+		manager.cfg.HttpListenAddr = getFreeAddr()
+		err := manager.ObtainCert(context.Background(), &sync.Mutex{})
+		convey.So(err, convey.ShouldNotBeNil)
+		convey.So(fileutils.IsExist(manager.certPath), convey.ShouldBeFalse)
+	})
+}
+
+func TestReplaceServerCert(t *testing.T) {
+	convey.Convey("the old key should be restored if the cert is not replaced", t, func() {
+		dir := t.TempDir()
+		manager := &Manager{cfg: &AcmeConfig{}, certPath: filepath.Join(dir, "server.crt"),
+			keyPath: filepath.Join(dir, "server.key")}
+		oldKey := []byte("old key")
+		convey.So(fileutils.WriteData(manager.keyPath, oldKey), convey.ShouldBeNil)
+		tempKeyPath := manager.keyPath + acmeTempSuffix
+		convey.So(fileutils.WriteData(tempKeyPath, []byte("new key")), convey.ShouldBeNil)
+
+		err := manager.replaceServerCert(tempKeyPath, manager.certPath+acmeTempSuffix, &sync.Mutex{})
+		convey.So(err, convey.ShouldNotBeNil)
+		keyContent, err := fileutils.LoadFile(manager.keyPath)
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(keyContent, convey.ShouldResemble, oldKey)
+		convey.So(fileutils.IsExist(manager.keyPath+acmeBackupSuffix), convey.ShouldBeFalse)
+	})
+
+	convey.Convey("the new key should be removed if the cert is not replaced and there is no old key", t, func() {
+		dir := t.TempDir()
+		manager := &Manager{cfg: &AcmeConfig{}, certPath: filepath.Join(dir, "server.crt"),
+			keyPath: filepath.Join(dir, "server.key")}
+		tempKeyPath := manager.keyPath + acmeTempSuffix
+		convey.So(fileutils.WriteData(tempKeyPath, []byte("new key")), convey.ShouldBeNil)
+
+		err := manager.replaceServerCert(tempKeyPath, manager.certPath+acmeTempSuffix, &sync.Mutex{})
+		convey.So(err, convey.ShouldNotBeNil)
+		convey.So(fileutils.IsExist(manager.keyPath), convey.ShouldBeFalse)
+	})
+}
@@ -0,0 +1,189 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package acmeclient challenge solvers to prove the control of domains
+package acmeclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/acme"
+
+	"huawei.com/mindx/common/envutils"
+	"huawei.com/mindx/common/fileutils"
+	"huawei.com/mindx/common/hwlog"
+)
+
+const (
+	dnsHookTimeout     = 60
+	dnsHookPresent     = "present"
+	dnsHookCleanUp     = "cleanup"
+	dnsRecordPrefix    = "_acme-challenge."
+	httpReadTimeout    = 10 * time.Second
+	httpShutdownWait   = 5 * time.Second
+	httpMaxHeaderBytes = 4 * 1024
+)
+
+// ChallengeSolver solve one type of acme challenge
+type ChallengeSolver interface {
+	// Type the challenge type solved by the solver, such as http-01
+	Type() string
+	// Present make the challenge response reachable by acme server
+	Present(client *acme.Client, domain string, chal *acme.Challenge) error
+	// CleanUp remove the challenge response after the authorization is finished
+	CleanUp(client *acme.Client, domain string, chal *acme.Challenge) error
+}
+
+// SolverCreator create a challenge solver with acme config
+type SolverCreator func(cfg *AcmeConfig) (ChallengeSolver, error)
+
+var (
+	solverLock     sync.Mutex
+	solverCreators = map[string]SolverCreator{
+		ChallengeHttp01: newHttp01Solver,
+		ChallengeDns01:  newDns01Solver,
+	}
+)
+
+// RegisterSolver register the creator of challenge solver, the builtin one of the same type is replaced
+func RegisterSolver(challengeType string, creator SolverCreator) {
+	solverLock.Lock()
+	defer solverLock.Unlock()
+	solverCreators[challengeType] = creator
+}
+
+func newSolver(cfg *AcmeConfig) (ChallengeSolver, error) {
+	solverLock.Lock()
+	creator, ok := solverCreators[cfg.Challenge]
+	solverLock.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("challenge type [%s] is not supported", cfg.Challenge)
+	}
+	return creator(cfg)
+}
+
+// http01Solver serve the key authorizations on the configured address, which the acme server visits on port 80
+type http01Solver struct {
+	listenAddr string
+	lock       sync.Mutex
+	responses  map[string]string
+	server     *http.Server
+}
+
+func newHttp01Solver(cfg *AcmeConfig) (ChallengeSolver, error) {
+	return &http01Solver{listenAddr: cfg.HttpListenAddr, responses: map[string]string{}}, nil
+}
+
+// Type the challenge type solved by the solver
+func (s *http01Solver) Type() string {
+	return ChallengeHttp01
+}
+
+func (s *http01Solver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.lock.Lock()
+	resp, ok := s.responses[r.URL.Path]
+	s.lock.Unlock()
+	if r.Method != http.MethodGet || !ok {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain")
+	if _, err := w.Write([]byte(resp)); err != nil {
+		hwlog.RunLog.Errorf("write http-01 challenge response failed: %v", err)
+	}
+}
+
+// Present start the http server if not started, and serve the key authorization of the challenge
+func (s *http01Solver) Present(client *acme.Client, domain string, chal *acme.Challenge) error {
+	resp, err := client.HTTP01ChallengeResponse(chal.Token)
+	if err != nil {
+		return fmt.Errorf("get http-01 challenge response failed: %v", err)
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.responses[client.HTTP01ChallengePath(chal.Token)] = resp
+	if s.server != nil {
+		return nil
+	}
+	listener, err := net.Listen("tcp", s.listenAddr)
+	if err != nil {
+		return fmt.Errorf("listen on %s for http-01 challenge failed: %v", s.listenAddr, err)
+	}
+	s.server = &http.Server{Handler: s, ReadTimeout: httpReadTimeout, MaxHeaderBytes: httpMaxHeaderBytes}
+	go func(server *http.Server) {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			hwlog.RunLog.Errorf("serve http-01 challenge failed: %v", err)
+		}
+	}(s.server)
+	hwlog.RunLog.Infof("serve http-01 challenge on %s for %s", s.listenAddr, domain)
+	return nil
+}
+
+// CleanUp remove the key authorization, and stop the http server if there is nothing to serve
+func (s *http01Solver) CleanUp(client *acme.Client, domain string, chal *acme.Challenge) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	delete(s.responses, client.HTTP01ChallengePath(chal.Token))
+	if len(s.responses) != 0 || s.server == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), httpShutdownWait)
+	defer cancel()
+	err := s.server.Shutdown(ctx)
+	s.server = nil
+	if err != nil {
+		return fmt.Errorf("stop http-01 challenge server failed: %v", err)
+	}
+	return nil
+}
+
+// dns01Solver call the hook to add and remove TXT record, usage: <hook> present|cleanup <record name> <value>
+type dns01Solver struct {
+	hookPath string
+}
+
+func newDns01Solver(cfg *AcmeConfig) (ChallengeSolver, error) {
+	if _, err := fileutils.CheckOriginPath(cfg.DnsHookPath); err != nil {
+		return nil, fmt.Errorf("check dns hook failed: %v", err)
+	}
+	return &dns01Solver{hookPath: cfg.DnsHookPath}, nil
+}
+
+// Type the challenge type solved by the solver
+func (s *dns01Solver) Type() string {
+	return ChallengeDns01
+}
+
+func (s *dns01Solver) runHook(action string, client *acme.Client, domain string, chal *acme.Challenge) error {
+	record, err := client.DNS01ChallengeRecord(chal.Token)
+	if err != nil {
+		return fmt.Errorf("get dns-01 challenge record failed: %v", err)
+	}
+	if _, err = envutils.RunCommand(s.hookPath, dnsHookTimeout, action, dnsRecordPrefix+domain, record); err != nil {
+		return fmt.Errorf("run dns hook to %s record for %s failed: %v", action, domain, err)
+	}
+	return nil
+}
+
+// Present add the TXT record of the challenge by hook
+func (s *dns01Solver) Present(client *acme.Client, domain string, chal *acme.Challenge) error {
+	return s.runHook(dnsHookPresent, client, domain, chal)
+}
+
+// CleanUp remove the TXT record of the challenge by hook
+func (s *dns01Solver) CleanUp(client *acme.Client, domain string, chal *acme.Challenge) error {
+	return s.runHook(dnsHookCleanUp, client, domain, chal)
+}
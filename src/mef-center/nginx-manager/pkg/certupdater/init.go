@@ -64,6 +64,8 @@ func (updater *SouthCertUpdater) Enable() bool {
 // Start initializes the websocket server
 func (updater *SouthCertUpdater) Start() {
 	initMsgHandler()
+	go refreshOcspStapling(updater.ctx)
+	go renewNorthCertByAcme(updater.ctx)
 	for {
 		select {
 		case <-updater.ctx.Done():
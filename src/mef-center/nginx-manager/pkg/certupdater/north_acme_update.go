@@ -0,0 +1,71 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package certupdater obtain and renew the northbound server cert by acme
+package certupdater
+
+import (
+	"context"
+	"time"
+
+	"huawei.com/mindx/common/hwlog"
+
+	"nginx-manager/pkg/acmeclient"
+)
+
+const (
+	// wait for nginx to start before the first check
+	acmeFirstCheckDelay = time.Minute
+	acmeCheckInterval   = 12 * time.Hour
+	acmeRetryInterval   = time.Hour
+)
+
+func renewNorthCertByAcme(ctx context.Context) {
+	acmeCfg, err := acmeclient.LoadAcmeConfig()
+	if err != nil {
+		hwlog.RunLog.Errorf("load acme config failed: %v, northbound server cert is not renewed by acme", err)
+		return
+	}
+	if acmeCfg == nil {
+		return
+	}
+	manager := acmeclient.NewManager(acmeCfg)
+	timer := time.NewTimer(acmeFirstCheckDelay)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			hwlog.RunLog.Info("acme cert renewer is stopped")
+			return
+		case <-timer.C:
+		}
+		if err = renewNorthCert(ctx, manager); err != nil {
+			hwlog.RunLog.Errorf("renew northbound server cert by acme failed: %v, retry later", err)
+			timer.Reset(acmeRetryInterval)
+			continue
+		}
+		timer.Reset(acmeCheckInterval)
+	}
+}
+
+func renewNorthCert(ctx context.Context, manager *acmeclient.Manager) error {
+	if !manager.NeedRenew() {
+		return nil
+	}
+	if err := manager.ObtainCert(ctx, &nginxReloadLocker); err != nil {
+		return err
+	}
+	if err := reloadNginxConf(); err != nil {
+		hwlog.RunLog.Errorf("reload nginx configuration for acme cert error: %v", err)
+		return err
+	}
+	hwlog.RunLog.Info("renew northbound server cert by acme success")
+	return nil
+}
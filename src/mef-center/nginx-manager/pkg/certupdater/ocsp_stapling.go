@@ -0,0 +1,45 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package certupdater refresh the ocsp responses stapled by nginx
+package certupdater
+
+import (
+	"context"
+	"time"
+
+	"huawei.com/mindx/common/hwlog"
+
+	"nginx-manager/pkg/nginxmgr"
+)
+
+// the ocsp responses from cert manager are valid for 24 hours
+const ocspStaplingRefreshInterval = 6 * time.Hour
+
+func refreshOcspStapling(ctx context.Context) {
+	ticker := time.NewTicker(ocspStaplingRefreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			hwlog.RunLog.Info("ocsp stapling refresher is stopped")
+			return
+		case <-ticker.C:
+		}
+		if !nginxmgr.RefreshOcspStaplingFiles() {
+			continue
+		}
+		if err := reloadNginxConf(); err != nil {
+			hwlog.RunLog.Errorf("reload nginx configuration for ocsp stapling error: %v", err)
+			continue
+		}
+		hwlog.RunLog.Info("refresh ocsp stapling success")
+	}
+}
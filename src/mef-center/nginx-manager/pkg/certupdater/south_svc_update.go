@@ -61,6 +61,8 @@ func updateSouthSvcCert(payload *CertUpdatePayload) error {
 		return optErr
 	}
 	hwlog.RunLog.Info("update south auth and websocket service certs success")
+	// the stapled ocsp response belongs to the old websocket service cert
+	nginxmgr.RefreshOcspStaplingFiles()
 	if err := reloadNginxConf(); err != nil {
 		optErr = fmt.Errorf("reload nginx configuration error: %v", err)
 		hwlog.RunLog.Error(optErr)
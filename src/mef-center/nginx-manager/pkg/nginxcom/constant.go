@@ -28,6 +28,12 @@ const (
 	WebsocketPortKey = "WebsocketPort"
 	// CrlConfigKey 证书吊销列表配置对应key
 	CrlConfigKey = "SslCrlPath"
+	// SslStaplingKey 是否开启OCSP stapling对应的key
+	SslStaplingKey = "SslStapling"
+	// StaplingConfigKey 北向服务OCSP stapling配置对应key
+	StaplingConfigKey = "SslStaplingConfig"
+	// WebsocketStaplingConfigKey 南向服务OCSP stapling配置对应key
+	WebsocketStaplingConfigKey = "WebsocketSslStaplingConfig"
 	// NginxConfigPath nginx配置文件
 	NginxConfigPath = "/home/MEFCenter/conf/nginx.conf"
 	// ServerCertFile nginx对外服务证书
@@ -38,6 +44,12 @@ const (
 	NorthernCertFile = "/home/data/config/mef-certs/northern-root.crt"
 	// NorthernCrlFile 北向证书吊销列表文件
 	NorthernCrlFile = "/home/data/config/mef-certs/northern-root.crl"
+	// AcmeConfigFile 通过ACME申请北向服务证书的配置文件，不存在时不启用ACME
+	AcmeConfigFile = "/home/data/config/acme-config.json"
+	// AcmeAccountKeyFile ACME账号私钥文件
+	AcmeAccountKeyFile = "/home/data/config/mef-certs/acme-account.key"
+	// ServerOcspFile nginx对外服务证书的OCSP响应文件
+	ServerOcspFile = "/home/data/config/mef-certs/nginx-manager-server.ocsp"
 	// SouthAuthCertFile  南向认证接口服务证书
 	SouthAuthCertFile = "/home/data/config/mef-certs/south-auth-server.crt"
 	// SouthAuthCertKeyFile  南向认证接口证书私钥文件
@@ -46,6 +58,8 @@ const (
 	WebsocketCertFile = "/home/data/config/mef-certs/south-websocket-server.crt"
 	// WebsocketCertKeyFile  南向服务接口证书私钥文件
 	WebsocketCertKeyFile = "/home/data/config/mef-certs/south-websocket-server.key"
+	// WebsocketOcspFile  南向服务接口服务证书的OCSP响应文件
+	WebsocketOcspFile = "/home/data/config/mef-certs/south-websocket-server.ocsp"
 	// SouthernCertFile  南向证书文件
 	SouthernCertFile = "/home/data/config/mef-certs/southern-root.crt"
 	// ClientCertFile 内部转发消息的证书
@@ -82,6 +82,8 @@ func newEnvironmentMgr() *environmentMgr {
 			createIntChecker(common.MinPort, common.MaxPort)},
 		PodIpKey: {PodIpKey, "", "", true,
 			createIpChecker()},
+		SslStaplingKey: {SslStaplingKey, "", "false", false,
+			createBoolChoiceChecker()},
 	}
 	return &environmentMgr{valuers: valuers}
 }
@@ -26,6 +26,7 @@ import (
 	"huawei.com/mindx/common/kmc"
 	"huawei.com/mindx/common/x509/certutils"
 
+	"nginx-manager/pkg/acmeclient"
 	"nginx-manager/pkg/nginxcom"
 
 	"huawei.com/mindxedge/base/common"
@@ -42,7 +43,7 @@ const (
 var errNotImported = errors.New("cert is not imported yet")
 
 func prepareCert() error {
-	if err := prepareServerCert(nginxcom.ServerCertKeyFile, nginxcom.ServerCertFile, common.NginxCertName); err != nil {
+	if err := prepareNorthServerCert(); err != nil {
 		return err
 	}
 	if err := prepareRootCert(nginxcom.NorthernCertFile, common.NorthernCertName, false); err != nil {
@@ -63,6 +64,20 @@ func prepareCert() error {
 		common.ThirdPartyCertName)
 }
 
+// prepareNorthServerCert keep the cert obtained by acme if it is still usable, otherwise issue one by mef,
+// which is replaced when the acme cert is obtained later
+func prepareNorthServerCert() error {
+	acmeCfg, err := acmeclient.LoadAcmeConfig()
+	if err != nil {
+		hwlog.RunLog.Warnf("load acme config failed: %v, northbound server cert is issued by mef", err)
+	}
+	if acmeCfg != nil && !acmeclient.NewManager(acmeCfg).NeedRenew() {
+		hwlog.RunLog.Info("northbound server cert obtained by acme is usable")
+		return nil
+	}
+	return prepareServerCert(nginxcom.ServerCertKeyFile, nginxcom.ServerCertFile, common.NginxCertName)
+}
+
 func prepareRootCert(certPath, certName string, retry bool) error {
 	reqCertParams := requests.ReqCertParams{
 		ClientTlsCert: certutils.TlsCertInfo{
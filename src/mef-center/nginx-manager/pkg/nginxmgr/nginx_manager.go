@@ -60,6 +60,10 @@ func initResource() error {
 		return err
 	}
 
+	if err := prepareOcspStapling(); err != nil {
+		return err
+	}
+
 	if err := prepareFilesMode(); err != nil {
 		return err
 	}
@@ -0,0 +1,160 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package nginxmgr this file is for ocsp stapling of nginx service certs
+package nginxmgr
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+
+	"huawei.com/mindx/common/fileutils"
+	"huawei.com/mindx/common/hwlog"
+
+	"nginx-manager/pkg/acmeclient"
+	"nginx-manager/pkg/nginxcom"
+
+	"huawei.com/mindxedge/base/common"
+)
+
+const hexBase = 16
+
+// staplingTarget the service cert whose ocsp response is stapled by nginx, the cert which may be obtained by acme
+// is not issued by mef, so cert manager has no ocsp response for it
+type staplingTarget struct {
+	configKey string
+	certName  string
+	certPath  string
+	ocspPath  string
+	byAcme    bool
+}
+
+var staplingTargets = []staplingTarget{
+	{nginxcom.StaplingConfigKey, common.NginxCertName, nginxcom.ServerCertFile, nginxcom.ServerOcspFile, true},
+	{nginxcom.WebsocketStaplingConfigKey, common.WsSerName, nginxcom.WebsocketCertFile, nginxcom.WebsocketOcspFile,
+		false},
+}
+
+func isOcspStaplingEnabled() bool {
+	enabled, err := nginxcom.GetEnvManager().Get(nginxcom.SslStaplingKey)
+	if err != nil {
+		hwlog.RunLog.Warnf("get ocsp stapling switch failed: %v, ocsp stapling is disabled", err)
+		return false
+	}
+	return enabled == "true"
+}
+
+// isAcmeEnabled the north server cert falls back to the one issued by mef if acme config is invalid
+func isAcmeEnabled() bool {
+	acmeCfg, err := acmeclient.LoadAcmeConfig()
+	if err != nil {
+		hwlog.RunLog.Warnf("load acme config failed: %v, northbound server cert is issued by mef", err)
+		return false
+	}
+	return acmeCfg != nil
+}
+
+func getCertSerialNumber(certPath string) (string, error) {
+	certBytes, err := fileutils.LoadFile(certPath)
+	if err != nil {
+		return "", fmt.Errorf("load cert failed: %v", err)
+	}
+	block, _ := pem.Decode(certBytes)
+	if block == nil {
+		return "", errors.New("decode cert pem failed")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return "", fmt.Errorf("parse cert failed: %v", err)
+	}
+	return strings.ToUpper(cert.SerialNumber.Text(hexBase)), nil
+}
+
+// fetchOcspResp get the ocsp response of service cert from cert manager and save it for nginx
+func fetchOcspResp(target staplingTarget) error {
+	sn, err := getCertSerialNumber(target.certPath)
+	if err != nil {
+		return err
+	}
+	reqCertParams := getReqCertParams()
+	respStr, err := reqCertParams.GetOcspResp(target.certName, sn)
+	if err != nil {
+		return fmt.Errorf("get ocsp response from cert manager failed: %v", err)
+	}
+	respBytes, err := base64.StdEncoding.DecodeString(respStr)
+	if err != nil {
+		return fmt.Errorf("base64 decode ocsp response failed: %v", err)
+	}
+	if err = fileutils.WriteData(target.ocspPath, respBytes); err != nil {
+		return fmt.Errorf("save ocsp response failed: %v", err)
+	}
+	return nil
+}
+
+// prepareOcspStapling config ocsp stapling for service certs issued by mef, nginx runs without stapling if failed
+func prepareOcspStapling() error {
+	content, err := loadConf(nginxcom.NginxConfigPath)
+	if err != nil {
+		return err
+	}
+	enabled := isOcspStaplingEnabled()
+	acmeEnabled := enabled && isAcmeEnabled()
+	for _, target := range staplingTargets {
+		if err = fileutils.DeleteFile(target.ocspPath); err != nil {
+			return err
+		}
+		skipped := target.byAcme && acmeEnabled
+		if skipped {
+			hwlog.RunLog.Infof("%s is obtained by acme, skip ocsp stapling for it", target.certPath)
+		}
+		var staplingConfig string
+		if enabled && !skipped {
+			if err = fetchOcspResp(target); err == nil {
+				staplingConfig = fmt.Sprintf("ssl_stapling on; ssl_stapling_file %s;", target.ocspPath)
+			} else {
+				hwlog.RunLog.Warnf("prepare ocsp stapling for %s failed: %v, nginx will run without it",
+					target.certPath, err)
+			}
+		}
+		content = bytes.ReplaceAll(content, []byte(nginxcom.KeyPrefix+target.configKey), []byte(staplingConfig))
+	}
+	if err = fileutils.WriteData(nginxcom.NginxConfigPath, content); err != nil {
+		hwlog.RunLog.Errorf("writeFile failed. error:%s", err.Error())
+		return fmt.Errorf("writeFile failed. error:%s", err.Error())
+	}
+	hwlog.RunLog.Infof("prepare nginx ocsp stapling success, enabled: %v", enabled)
+	return nil
+}
+
+// RefreshOcspStaplingFiles refresh the stapled ocsp responses before they expire,
+// return true if any file is refreshed and nginx should reload its configuration
+func RefreshOcspStaplingFiles() bool {
+	if !isOcspStaplingEnabled() {
+		return false
+	}
+	var refreshed bool
+	for _, target := range staplingTargets {
+		// stapling of the cert is not configured when nginx starts
+		if !fileutils.IsExist(target.ocspPath) {
+			continue
+		}
+		if err := fetchOcspResp(target); err != nil {
+			hwlog.RunLog.Warnf("refresh ocsp response for %s failed: %v", target.certPath, err)
+			continue
+		}
+		refreshed = true
+	}
+	return refreshed
+}
@@ -19,6 +19,7 @@ import (
 	"edge-installer/pkg/edge-main/cloudcoreproxy"
 	"edge-installer/pkg/edge-main/downloadmgr"
 	"edge-installer/pkg/edge-main/edgehub"
+	"edge-installer/pkg/edge-main/sitecache"
 )
 
 func moduleExt(netType string) []model.Module {
@@ -30,5 +31,6 @@ func moduleExt(netType string) []model.Module {
 		edgehub.NewEdgeHub(true),
 		cloudcoreproxy.NewCloudCoreProxy(true),
 		downloadmgr.NewDownloadMgr(true),
+		sitecache.NewSiteCacheMgr(true),
 	}
 }
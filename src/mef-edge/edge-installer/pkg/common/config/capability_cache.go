@@ -14,6 +14,7 @@ import (
 	"context"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"huawei.com/mindx/common/hwlog"
@@ -30,6 +31,7 @@ var instance *CapabilityCache
 type CapabilityCache struct {
 	capabilities sync.Map
 	eventChan    chan interface{}
+	changeHook   atomic.Value
 }
 
 // GetCapabilityCache create or get a cache
@@ -41,9 +43,21 @@ func GetCapabilityCache() *CapabilityCache {
 	return instance
 }
 
-// Set put a val
+// Set put a val, the change hook is called if the val changes
 func (c *CapabilityCache) Set(key string, val bool) {
+	old, loaded := c.capabilities.Load(key)
 	c.capabilities.Store(key, val)
+	if loaded && old == val {
+		return
+	}
+	if hook, ok := c.changeHook.Load().(func()); ok {
+		hook()
+	}
+}
+
+// SetChangeHook set the hook called when capabilities change, it should return quickly
+func (c *CapabilityCache) SetChangeHook(hook func()) {
+	c.changeHook.Store(hook)
 }
 
 // SetEdgeOmCaps set capabilities by the messages from edge-om
@@ -67,7 +81,8 @@ func (c *CapabilityCache) Notify() {
 	c.eventChan <- struct{}{}
 }
 
-func (c *CapabilityCache) getCaps() []string {
+// GetCaps get the effective capabilities in cache
+func (c *CapabilityCache) GetCaps() []string {
 	var effectiveCaps []string
 	c.capabilities.Range(func(key, val interface{}) bool {
 		capabilityName, ok := key.(string)
@@ -118,7 +133,7 @@ func (c *CapabilityCache) StartReportJob(ctx context.Context) {
 
 func reportCapabilities() {
 	var toFdInfo StaticInfo
-	toFdInfo.ProductCapabilityEdge = GetCapabilityCache().getCaps()
+	toFdInfo.ProductCapabilityEdge = GetCapabilityCache().GetCaps()
 	if len(toFdInfo.ProductCapabilityEdge) == 0 {
 		return
 	}
@@ -36,6 +36,19 @@ func TestCapabilityCache(t *testing.T) {
 		GetCapabilityCache().capabilities = sync.Map{}
 	})
 
+	convey.Convey("test CapabilityCache change hook is called when capability changes", t, func() {
+		GetCapabilityCache().capabilities = sync.Map{}
+		changed := 0
+		GetCapabilityCache().SetChangeHook(func() { changed++ })
+		defer GetCapabilityCache().SetChangeHook(func() {})
+		GetCapabilityCache().Set(constants.CapabilityPodConfig, true)
+		GetCapabilityCache().Set(constants.CapabilityPodConfig, true)
+		GetCapabilityCache().Set(constants.CapabilityPodConfig, false)
+		convey.So(changed, convey.ShouldEqual, 2)
+		convey.So(GetCapabilityCache().GetCaps(), convey.ShouldBeEmpty)
+		GetCapabilityCache().capabilities = sync.Map{}
+	})
+
 	convey.Convey("test CapabilityCache method Notify / StartReportJob", t, func() {
 		GetCapabilityCache().Notify()
 		go GetCapabilityCache().StartReportJob(context.Background())
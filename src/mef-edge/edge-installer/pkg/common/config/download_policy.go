@@ -0,0 +1,115 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package config this file for bandwidth cap and time window of downloads
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"huawei.com/mindx/common/fileutils"
+)
+
+const (
+	maxDownloadPolicySize = 1024
+	// MaxBandwidthLimit the max bandwidth cap of downloads in KB/s
+	MaxBandwidthLimit = 10 * 1024 * 1024
+	// DownloadWindowLayout the layout of start and end of the download time window
+	DownloadWindowLayout = "15:04"
+)
+
+// DownloadPolicy the optional bandwidth cap and time window of software and model file downloads of the node
+type DownloadPolicy struct {
+	// BandwidthLimit in KB/s, 0 means no limit
+	BandwidthLimit int64 `json:"bandwidthLimit"`
+	// WindowStart and WindowEnd are local time in HH:MM, downloads are paused out of the window.
+	// The window crosses midnight if the end is earlier than the start, empty means downloading at any time
+	WindowStart string `json:"windowStart"`
+	WindowEnd   string `json:"windowEnd"`
+}
+
+// Check check whether the download policy is valid
+func (dp DownloadPolicy) Check() error {
+	if dp.BandwidthLimit < 0 || dp.BandwidthLimit > MaxBandwidthLimit {
+		return fmt.Errorf("bandwidth limit should be within [0, %d] KB/s", MaxBandwidthLimit)
+	}
+	if dp.WindowStart == "" && dp.WindowEnd == "" {
+		return nil
+	}
+	start, err := time.Parse(DownloadWindowLayout, dp.WindowStart)
+	if err != nil {
+		return errors.New("window start should be in format HH:MM")
+	}
+	end, err := time.Parse(DownloadWindowLayout, dp.WindowEnd)
+	if err != nil {
+		return errors.New("window end should be in format HH:MM")
+	}
+	if start.Equal(end) {
+		return errors.New("window start and end should not be the same")
+	}
+	return nil
+}
+
+// WaitTime the duration to wait from now until the download window opens, 0 means downloading is allowed now.
+// The policy should be checked before
+func (dp DownloadPolicy) WaitTime(now time.Time) time.Duration {
+	if dp.WindowStart == "" {
+		return 0
+	}
+	start, startErr := time.Parse(DownloadWindowLayout, dp.WindowStart)
+	end, endErr := time.Parse(DownloadWindowLayout, dp.WindowEnd)
+	if startErr != nil || endErr != nil {
+		return 0
+	}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	sinceMidnight := now.Sub(today)
+	startOffset := time.Duration(start.Hour())*time.Hour + time.Duration(start.Minute())*time.Minute
+	endOffset := time.Duration(end.Hour())*time.Hour + time.Duration(end.Minute())*time.Minute
+
+	inWindow := sinceMidnight >= startOffset && sinceMidnight < endOffset
+	if startOffset > endOffset {
+		inWindow = sinceMidnight >= startOffset || sinceMidnight < endOffset
+	}
+	if inWindow {
+		return 0
+	}
+	if sinceMidnight < startOffset {
+		return startOffset - sinceMidnight
+	}
+	return startOffset + time.Hour*24 - sinceMidnight
+}
+
+// SaveDownloadPolicy save the download policy into file
+func SaveDownloadPolicy(policyPath string, policy DownloadPolicy) error {
+	data, err := json.Marshal(policy)
+	if err != nil {
+		return fmt.Errorf("marshal download policy failed: %v", err)
+	}
+	if err = fileutils.WriteData(policyPath, data); err != nil {
+		return fmt.Errorf("write download policy failed: %v", err)
+	}
+	return nil
+}
+
+// LoadDownloadPolicy load the download policy from file
+func LoadDownloadPolicy(policyPath string) (*DownloadPolicy, error) {
+	data, err := fileutils.ReadLimitBytes(policyPath, maxDownloadPolicySize)
+	if err != nil {
+		return nil, fmt.Errorf("read download policy failed: %v", err)
+	}
+	var policy DownloadPolicy
+	if err = json.Unmarshal(data, &policy); err != nil {
+		return nil, fmt.Errorf("unmarshal download policy failed: %v", err)
+	}
+	return &policy, nil
+}
@@ -0,0 +1,78 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package config test for download policy
+package config
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/smartystreets/goconvey/convey"
+
+	"huawei.com/mindx/common/fileutils"
+)
+
+func TestDownloadPolicyCheck(t *testing.T) {
+	convey.Convey("valid download policy", t, func() {
+		convey.So(DownloadPolicy{}.Check(), convey.ShouldBeNil)
+		convey.So(DownloadPolicy{BandwidthLimit: 1024, WindowStart: "22:00", WindowEnd: "06:00"}.Check(),
+			convey.ShouldBeNil)
+	})
+
+	convey.Convey("invalid download policy", t, func() {
+		convey.So(DownloadPolicy{BandwidthLimit: -1}.Check(), convey.ShouldNotBeNil)
+		convey.So(DownloadPolicy{WindowStart: "22:00"}.Check(), convey.ShouldNotBeNil)
+		convey.So(DownloadPolicy{WindowStart: "25:00", WindowEnd: "06:00"}.Check(), convey.ShouldNotBeNil)
+		convey.So(DownloadPolicy{WindowStart: "06:00", WindowEnd: "06:00"}.Check(), convey.ShouldNotBeNil)
+	})
+}
+
+func TestDownloadPolicyWaitTime(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2025, time.March, 1, hour, minute, 0, 0, time.Local)
+	}
+
+	convey.Convey("no window allows downloading at any time", t, func() {
+		convey.So(DownloadPolicy{}.WaitTime(at(12, 0)), convey.ShouldEqual, 0)
+	})
+
+	convey.Convey("window within a day", t, func() {
+		policy := DownloadPolicy{WindowStart: "01:00", WindowEnd: "05:00"}
+		convey.So(policy.WaitTime(at(2, 0)), convey.ShouldEqual, 0)
+		convey.So(policy.WaitTime(at(0, 30)), convey.ShouldEqual, time.Minute*30)
+		convey.So(policy.WaitTime(at(5, 0)), convey.ShouldEqual, time.Hour*20)
+	})
+
+	convey.Convey("window crossing midnight", t, func() {
+		policy := DownloadPolicy{WindowStart: "22:00", WindowEnd: "06:00"}
+		convey.So(policy.WaitTime(at(23, 0)), convey.ShouldEqual, 0)
+		convey.So(policy.WaitTime(at(3, 0)), convey.ShouldEqual, 0)
+		convey.So(policy.WaitTime(at(12, 0)), convey.ShouldEqual, time.Hour*10)
+	})
+}
+
+func TestSaveAndLoadDownloadPolicy(t *testing.T) {
+	policyPath := filepath.Join("/tmp", "test_download_policy", "download-policy.json")
+	defer func() {
+		if err := fileutils.DeleteAllFileWithConfusion(filepath.Dir(policyPath)); err != nil {
+			t.Logf("clear download policy failed: %v", err)
+		}
+	}()
+
+	convey.Convey("save and load download policy", t, func() {
+		policy := DownloadPolicy{BandwidthLimit: 512, WindowStart: "22:00", WindowEnd: "06:00"}
+		convey.So(SaveDownloadPolicy(policyPath, policy), convey.ShouldBeNil)
+		loaded, err := LoadDownloadPolicy(policyPath)
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(*loaded, convey.ShouldResemble, policy)
+	})
+}
@@ -0,0 +1,64 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package config this file for the compression of the connection to MEF Center
+package config
+
+import (
+	"compress/flate"
+	"encoding/json"
+	"fmt"
+
+	"huawei.com/mindx/common/fileutils"
+
+	"edge-installer/pkg/common/constants"
+)
+
+const (
+	maxLinkCompressionConfigSize = 1024
+	// MaxMsgCompressThreshold the max threshold of message compression in bytes
+	MaxMsgCompressThreshold = 1 * constants.MB
+)
+
+// LinkCompressionConfig the compression of the messages sent to MEF Center, messages not smaller than Threshold in
+// bytes are compressed with the flate Level
+type LinkCompressionConfig struct {
+	Threshold int `json:"threshold"`
+	Level     int `json:"level"`
+}
+
+// DefaultLinkCompressionConfig the compression config used when it is not set
+func DefaultLinkCompressionConfig() LinkCompressionConfig {
+	return LinkCompressionConfig{Threshold: constants.MsgCompressThreshold, Level: constants.MsgCompressLevel}
+}
+
+// Check check whether the link compression config is valid
+func (lc LinkCompressionConfig) Check() error {
+	if lc.Threshold < 0 || lc.Threshold > MaxMsgCompressThreshold {
+		return fmt.Errorf("threshold should be within [0, %d] bytes", MaxMsgCompressThreshold)
+	}
+	if lc.Level < flate.BestSpeed || lc.Level > flate.BestCompression {
+		return fmt.Errorf("level should be within [%d, %d]", flate.BestSpeed, flate.BestCompression)
+	}
+	return nil
+}
+
+// LoadLinkCompressionConfig load the link compression config from file, the fields not set keep the default values
+func LoadLinkCompressionConfig(cfgPath string) (*LinkCompressionConfig, error) {
+	data, err := fileutils.ReadLimitBytes(cfgPath, maxLinkCompressionConfigSize)
+	if err != nil {
+		return nil, fmt.Errorf("read link compression config failed: %v", err)
+	}
+	cfg := DefaultLinkCompressionConfig()
+	if err = json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("unmarshal link compression config failed: %v", err)
+	}
+	return &cfg, nil
+}
@@ -0,0 +1,59 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package config test for link compression config
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/smartystreets/goconvey/convey"
+
+	"huawei.com/mindx/common/fileutils"
+)
+
+func TestLinkCompressionConfigCheck(t *testing.T) {
+	convey.Convey("valid link compression config", t, func() {
+		convey.So(DefaultLinkCompressionConfig().Check(), convey.ShouldBeNil)
+		convey.So(LinkCompressionConfig{Threshold: 0, Level: 9}.Check(), convey.ShouldBeNil)
+	})
+
+	convey.Convey("invalid link compression config", t, func() {
+		convey.So(LinkCompressionConfig{Threshold: -1, Level: 1}.Check(), convey.ShouldNotBeNil)
+		convey.So(LinkCompressionConfig{Threshold: MaxMsgCompressThreshold + 1, Level: 1}.Check(),
+			convey.ShouldNotBeNil)
+		convey.So(LinkCompressionConfig{Threshold: 1, Level: 0}.Check(), convey.ShouldNotBeNil)
+		convey.So(LinkCompressionConfig{Threshold: 1, Level: 10}.Check(), convey.ShouldNotBeNil)
+	})
+}
+
+func TestLoadLinkCompressionConfig(t *testing.T) {
+	cfgPath := filepath.Join("/tmp", "test_link_compression", "link-compression.json")
+	defer func() {
+		if err := fileutils.DeleteAllFileWithConfusion(filepath.Dir(cfgPath)); err != nil {
+			t.Logf("clear link compression config failed: %v", err)
+		}
+	}()
+
+	convey.Convey("fields not set keep the default values", t, func() {
+		convey.So(fileutils.WriteData(cfgPath, []byte(`{"level":6}`)), convey.ShouldBeNil)
+		cfg, err := LoadLinkCompressionConfig(cfgPath)
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(cfg.Level, convey.ShouldEqual, 6)
+		convey.So(cfg.Threshold, convey.ShouldEqual, DefaultLinkCompressionConfig().Threshold)
+	})
+
+	convey.Convey("invalid json is rejected", t, func() {
+		convey.So(fileutils.WriteData(cfgPath, []byte(`{"level":`)), convey.ShouldBeNil)
+		_, err := LoadLinkCompressionConfig(cfgPath)
+		convey.So(err, convey.ShouldNotBeNil)
+	})
+}
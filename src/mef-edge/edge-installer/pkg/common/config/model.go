@@ -47,6 +47,11 @@ type ContainerConfig struct {
 	TotalModelFileNumber      int      `json:"totalModelFileNumber"`
 	SystemReservedCPUQuota    float64  `json:"systemReservedCPUQuota"`
 	SystemReservedMemoryQuota int64    `json:"systemReservedMemoryQuota"` // unit is MB
+	// PortForwardAllowList targets that can be forwarded from center, such as "host:8080" or "container:8080"
+	PortForwardAllowList []string `json:"portForwardAllowList"`
+	PortForwardBandwidth int      `json:"portForwardBandwidth"` // unit is KB/s
+	// RemoteShellEnabled whether the remote shell and exec sessions from center are allowed, disabled by default
+	RemoteShellEnabled bool `json:"remoteShellEnabled"`
 }
 
 // StaticInfo static info
@@ -31,6 +31,29 @@ type NetManager struct {
 	AuthPort int
 	WithOm   bool
 	Token    []byte
+	// CenterAddrs ordered addresses (ip or domain name) of MEF Center for failover, IP is the first one
+	CenterAddrs []string
+	// ProxyUrl http CONNECT proxy for connections to MEF Center and file servers, empty means connecting directly
+	ProxyUrl  string
+	ProxyUser string
+	// ProxyPwd password of proxy user, it is encrypted by kmc in database
+	ProxyPwd []byte
+	// NoProxy hosts connected directly without proxy
+	NoProxy []string
+}
+
+// GetCenterAddrs get the ordered addresses of MEF Center, it is IP only for config set by old version
+func (n *NetManager) GetCenterAddrs() []string {
+	if len(n.CenterAddrs) == 0 {
+		return []string{n.IP}
+	}
+	return n.CenterAddrs
+}
+
+// ActiveCenter the MEF Center address which edge-main is connected to
+type ActiveCenter struct {
+	Addr        string `json:"addr"`
+	ConnectedAt string `json:"connectedAt"`
 }
 
 // ImageConfig image config struct
@@ -38,11 +61,15 @@ type ImageConfig struct {
 	ImageAddress string
 }
 
-// ProgressInfo [struct] to report edge software upgrade result info
+// ProgressInfo [struct] to report edge software upgrade result info, DownloadedBytes and Rate (bytes per second)
+// are reported periodically while downloading
 type ProgressInfo struct {
-	Progress uint64 `json:"progress"`
-	Res      string `json:"res"`
-	Msg      string `json:"msg"`
+	Progress        uint64 `json:"progress"`
+	Res             string `json:"res"`
+	Msg             string `json:"msg"`
+	Stage           string `json:"stage,omitempty"`
+	DownloadedBytes int64  `json:"downloadedBytes,omitempty"`
+	Rate            int64  `json:"rate,omitempty"`
 }
 
 // CertReq [struct] request cert from edge-om
@@ -62,4 +89,6 @@ type CertResp struct {
 type DirReq struct {
 	Path     string
 	ToDelete bool
+	// KeepFiles names of files in Path which are kept when preparing it, e.g. the partial file to resume
+	KeepFiles []string
 }
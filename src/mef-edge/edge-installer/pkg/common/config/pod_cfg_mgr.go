@@ -12,9 +12,13 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
 
 	"huawei.com/mindx/common/backuputils"
 	"huawei.com/mindx/common/fileutils"
@@ -24,6 +28,18 @@ import (
 	"edge-installer/pkg/common/path"
 )
 
+const (
+	// PortForwardHost port forward target on host
+	PortForwardHost = "host"
+	// PortForwardContainer port forward target in application container
+	PortForwardContainer = "container"
+
+	minPort = 1
+	maxPort = 65535
+)
+
+var portForwardTargetReg = regexp.MustCompile(`^(host|container):[0-9]{1,5}$`)
+
 // LoadPodConfig [method] loading pod config files for edge-om
 func LoadPodConfig() (*PodConfig, error) {
 	config, err := loadPodConfigWithBackup()
@@ -83,6 +99,8 @@ func checkAndModifyPodConfigPara(podConfig *PodConfig) {
 	podConfig.MaxContainerNumber = checkAndModifyMaxContainerNumber(podConfig.MaxContainerNumber)
 	podConfig.SystemReservedCPUQuota = checkAndModifySystemReservedCPUQuota(podConfig.SystemReservedCPUQuota)
 	podConfig.SystemReservedMemoryQuota = checkAndModifySystemReservedMemoryQuota(podConfig.SystemReservedMemoryQuota)
+	podConfig.PortForwardAllowList = checkAndModifyPortForwardAllowList(podConfig.PortForwardAllowList)
+	podConfig.PortForwardBandwidth = checkAndModifyPortForwardBandwidth(podConfig.PortForwardBandwidth)
 }
 
 func checkAndModifyHostPath(hostPath []string) []string {
@@ -199,3 +217,45 @@ func checkAndModifySystemReservedMemoryQuota(quota int64) int64 {
 	}
 	return quota
 }
+
+func checkAndModifyPortForwardAllowList(allowList []string) []string {
+	const maxAllowListNumber = 64
+	allowListTmp := make([]string, 0, len(allowList))
+	for _, target := range allowList {
+		if len(allowListTmp) >= maxAllowListNumber {
+			hwlog.RunLog.Warnf("the count of port forward targets exceeds %d, the others won't be effective",
+				maxAllowListNumber)
+			break
+		}
+		if _, err := ParsePortForwardTarget(target); err != nil {
+			hwlog.RunLog.Errorf("checking port forward target [%s] failed: %v, and it won't be effective", target, err)
+			continue
+		}
+		allowListTmp = append(allowListTmp, target)
+	}
+	return allowListTmp
+}
+
+// ParsePortForwardTarget parse the port of port forward target such as "host:8080"
+func ParsePortForwardTarget(target string) (int, error) {
+	if !portForwardTargetReg.MatchString(target) {
+		return 0, errors.New("target format is invalid")
+	}
+	port, err := strconv.Atoi(target[strings.LastIndex(target, ":")+1:])
+	if err != nil || port < minPort || port > maxPort {
+		return 0, fmt.Errorf("port should be in range [%d, %d]", minPort, maxPort)
+	}
+	return port, nil
+}
+
+func checkAndModifyPortForwardBandwidth(bandwidth int) int {
+	const (
+		defaultBandwidth = 10240
+		minBandwidth     = 64
+		maxBandwidth     = 102400
+	)
+	if bandwidth < minBandwidth || bandwidth > maxBandwidth {
+		return defaultBandwidth
+	}
+	return bandwidth
+}
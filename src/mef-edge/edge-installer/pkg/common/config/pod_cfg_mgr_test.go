@@ -207,3 +207,17 @@ func TestCheckHostPathMode(t *testing.T) {
 		convey.So(err, convey.ShouldResemble, expErr)
 	})
 }
+
+func TestCheckAndModifyPortForwardConfig(t *testing.T) {
+	convey.Convey("invalid port forward targets won't be effective", t, func() {
+		allowList := checkAndModifyPortForwardAllowList([]string{"host:8080", "container:8888", "host:0",
+			"host:65536", "vm:22", "host:80 ", "container:*"})
+		convey.So(allowList, convey.ShouldResemble, []string{"host:8080", "container:8888"})
+	})
+
+	convey.Convey("bandwidth out of range is modified to default", t, func() {
+		convey.So(checkAndModifyPortForwardBandwidth(1024), convey.ShouldEqual, 1024)
+		convey.So(checkAndModifyPortForwardBandwidth(0), convey.ShouldEqual, 10240)
+		convey.So(checkAndModifyPortForwardBandwidth(1024*1024), convey.ShouldEqual, 10240)
+	})
+}
@@ -0,0 +1,60 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package config this file for rollback guard of the effected version
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"huawei.com/mindx/common/fileutils"
+)
+
+const maxRollbackGuardSize = 1024
+
+// RollbackGuard is written when a new version is effected, the software is rolled back to PrevVersion
+// if the new version does not reconnect to center before Deadline
+type RollbackGuard struct {
+	PrevVersion string `json:"prevVersion"`
+	Version     string `json:"version"`
+	Deadline    int64  `json:"deadline"`
+}
+
+// IsExpired whether the deadline of the guard has passed
+func (rg RollbackGuard) IsExpired() bool {
+	return time.Now().Unix() >= rg.Deadline
+}
+
+// SaveRollbackGuard save the rollback guard into file
+func SaveRollbackGuard(guardPath string, guard RollbackGuard) error {
+	data, err := json.Marshal(guard)
+	if err != nil {
+		return fmt.Errorf("marshal rollback guard failed: %v", err)
+	}
+	if err = fileutils.WriteData(guardPath, data); err != nil {
+		return fmt.Errorf("write rollback guard failed: %v", err)
+	}
+	return nil
+}
+
+// LoadRollbackGuard load the rollback guard from file
+func LoadRollbackGuard(guardPath string) (*RollbackGuard, error) {
+	data, err := fileutils.ReadLimitBytes(guardPath, maxRollbackGuardSize)
+	if err != nil {
+		return nil, fmt.Errorf("read rollback guard failed: %v", err)
+	}
+	var guard RollbackGuard
+	if err = json.Unmarshal(data, &guard); err != nil {
+		return nil, fmt.Errorf("unmarshal rollback guard failed: %v", err)
+	}
+	return &guard, nil
+}
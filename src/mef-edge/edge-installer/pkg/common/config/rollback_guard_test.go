@@ -0,0 +1,47 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package config test for rollback guard
+package config
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/smartystreets/goconvey/convey"
+
+	"huawei.com/mindx/common/fileutils"
+)
+
+func TestRollbackGuard(t *testing.T) {
+	guardPath := filepath.Join("/tmp", "test_rollback_guard", "rollback_guard.json")
+	defer func() {
+		if err := fileutils.DeleteAllFileWithConfusion(filepath.Dir(guardPath)); err != nil {
+			t.Logf("clear rollback guard failed: %v", err)
+		}
+	}()
+
+	convey.Convey("save and load rollback guard", t, func() {
+		guard := RollbackGuard{PrevVersion: "5.0.0", Version: "6.0.0", Deadline: time.Now().Add(time.Hour).Unix()}
+		convey.So(SaveRollbackGuard(guardPath, guard), convey.ShouldBeNil)
+		loaded, err := LoadRollbackGuard(guardPath)
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(*loaded, convey.ShouldResemble, guard)
+		convey.So(loaded.IsExpired(), convey.ShouldBeFalse)
+		loaded.Deadline = time.Now().Unix()
+		convey.So(loaded.IsExpired(), convey.ShouldBeTrue)
+	})
+
+	convey.Convey("load rollback guard failed", t, func() {
+		_, err := LoadRollbackGuard(filepath.Join("/tmp", "test_rollback_guard", "not_exist.json"))
+		convey.So(err, convey.ShouldNotBeNil)
+	})
+}
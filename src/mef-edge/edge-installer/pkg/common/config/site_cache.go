@@ -0,0 +1,80 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package config this file for the site cache role of the node
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"huawei.com/mindx/common/fileutils"
+)
+
+const (
+	maxSiteCacheConfigSize = 1024
+	// MinSiteCachePort the min listening port of site cache
+	MinSiteCachePort = 1025
+	// MaxSiteCachePort the max listening port of site cache
+	MaxSiteCachePort = 65535
+	// MaxSiteCacheCapacity the max capacity of site cache in GB
+	MaxSiteCacheCapacity = 1024
+	// DefaultSiteCacheCapacity the default capacity of site cache in GB
+	DefaultSiteCacheCapacity = 32
+)
+
+// SiteCacheConfig the site cache role of the node, the site cache serves the artifacts hosted by center to the
+// peers in the same site, and fetches the artifacts from center when they are not cached
+type SiteCacheConfig struct {
+	Enable bool `json:"enable"`
+	// Port the port listened for the peers
+	Port int `json:"port"`
+	// Capacity in GB, the least recently used artifacts are evicted when it is exceeded
+	Capacity int64 `json:"capacity"`
+}
+
+// Check check whether the site cache config is valid
+func (sc SiteCacheConfig) Check() error {
+	if !sc.Enable {
+		return nil
+	}
+	if sc.Port < MinSiteCachePort || sc.Port > MaxSiteCachePort {
+		return fmt.Errorf("port should be within [%d, %d]", MinSiteCachePort, MaxSiteCachePort)
+	}
+	if sc.Capacity < 1 || sc.Capacity > MaxSiteCacheCapacity {
+		return fmt.Errorf("capacity should be within [1, %d] GB", MaxSiteCacheCapacity)
+	}
+	return nil
+}
+
+// SaveSiteCacheConfig save the site cache config into file
+func SaveSiteCacheConfig(cfgPath string, cfg SiteCacheConfig) error {
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return fmt.Errorf("marshal site cache config failed: %v", err)
+	}
+	if err = fileutils.WriteData(cfgPath, data); err != nil {
+		return fmt.Errorf("write site cache config failed: %v", err)
+	}
+	return nil
+}
+
+// LoadSiteCacheConfig load the site cache config from file
+func LoadSiteCacheConfig(cfgPath string) (*SiteCacheConfig, error) {
+	data, err := fileutils.ReadLimitBytes(cfgPath, maxSiteCacheConfigSize)
+	if err != nil {
+		return nil, fmt.Errorf("read site cache config failed: %v", err)
+	}
+	var cfg SiteCacheConfig
+	if err = json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("unmarshal site cache config failed: %v", err)
+	}
+	return &cfg, nil
+}
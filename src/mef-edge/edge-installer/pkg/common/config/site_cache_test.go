@@ -0,0 +1,53 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package config test for site cache config
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/smartystreets/goconvey/convey"
+
+	"huawei.com/mindx/common/fileutils"
+)
+
+func TestSiteCacheConfigCheck(t *testing.T) {
+	convey.Convey("valid site cache config", t, func() {
+		convey.So(SiteCacheConfig{}.Check(), convey.ShouldBeNil)
+		convey.So(SiteCacheConfig{Enable: true, Port: 8443, Capacity: DefaultSiteCacheCapacity}.Check(),
+			convey.ShouldBeNil)
+	})
+
+	convey.Convey("invalid site cache config", t, func() {
+		convey.So(SiteCacheConfig{Enable: true, Port: 80, Capacity: 1}.Check(), convey.ShouldNotBeNil)
+		convey.So(SiteCacheConfig{Enable: true, Port: 8443}.Check(), convey.ShouldNotBeNil)
+		convey.So(SiteCacheConfig{Enable: true, Port: 8443, Capacity: MaxSiteCacheCapacity + 1}.Check(),
+			convey.ShouldNotBeNil)
+	})
+}
+
+func TestSaveAndLoadSiteCacheConfig(t *testing.T) {
+	cfgPath := filepath.Join("/tmp", "test_site_cache", "site-cache.json")
+	defer func() {
+		if err := fileutils.DeleteAllFileWithConfusion(filepath.Dir(cfgPath)); err != nil {
+			t.Logf("clear site cache config failed: %v", err)
+		}
+	}()
+
+	convey.Convey("save and load site cache config", t, func() {
+		cfg := SiteCacheConfig{Enable: true, Port: 8443, Capacity: 16}
+		convey.So(SaveSiteCacheConfig(cfgPath, cfg), convey.ShouldBeNil)
+		loaded, err := LoadSiteCacheConfig(cfgPath)
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(*loaded, convey.ShouldResemble, cfg)
+	})
+}
@@ -48,6 +48,10 @@ const (
 	MEFEdgeLogSyncName      = "MEFEdge_logsync"
 	ConfigBackup            = "config_backup"
 	ConfigBackupTmp         = "config_backup_temp"
+	ConfigRollback          = "config_rollback"
+	ConfigRollbackTmp       = "config_rollback_temp"
+	RollbackGuardFile       = "rollback_guard.json"
+	CenterConnectedFlagFile = "center_connected"
 	SoftwareDir             = "software"
 	SoftwareDirA            = "software_A"
 	SoftwareDirB            = "software_B"
@@ -93,6 +97,11 @@ const (
 	EdgeCoreLogFile         = "edge_core_run.log"
 	NetCfgTempDirName       = "temp_netconfig"
 	SnFileName              = "serial-number.json"
+	CertPolicyFileName      = "cert-policy.json"
+	ActiveCenterFileName    = "active-center.json"
+	DownloadPolicyFileName  = "download-policy.json"
+	SiteCacheFileName       = "site-cache.json"
+	LinkCompressionFileName = "link-compression.json"
 
 	RunScript             = "run.sh"
 	DockerIsolationScript = "mef_docker_isolation.sh"
@@ -367,6 +376,16 @@ const (
 	ResDownloadProgress = "/edge/download-progress"
 	// ResDumpLogTaskError resource for log-dumping errors
 	ResDumpLogTaskError = "/logmgmt/dump/error"
+	// ResRemoteShellOutput resource for edge-om to report output of remote shell sessions
+	ResRemoteShellOutput = "/remoteshell/output"
+	// ResPortForwardData resource for edge-om to report data of port forward streams
+	ResPortForwardData = "/portforward/data"
+	// ResTraceLogData resource for edge-om to report the log lines of a trace
+	ResTraceLogData = "/trace/log/data"
+	// ResPreflightResult resource for edge-om to report the results of preflight checks
+	ResPreflightResult = "/edge/preflight/result"
+	// ResEdgeCapability resource for edge-main to report capabilities when they change after connect
+	ResEdgeCapability = "/edge/capability"
 )
 
 // module constants
@@ -490,6 +509,9 @@ const (
 	UpgradeMode  = "upgrade"
 	EffectMode   = "effect"
 	DefaultMode  = "default"
+	RollbackMode = "rollback"
+	// RollbackGuardTimeout the time for the effected version to reconnect to center before rolled back
+	RollbackGuardTimeout = 10 * time.Minute
 )
 
 // systemctl
@@ -577,6 +599,20 @@ const (
 	CapabilityUdpContainerPort = "support_udp_container_port"
 )
 
+// protocol version and capabilities negotiated with MEF-Center on connect
+const (
+	// ProtocolVersion is increased when the messages between edge and center change incompatibly
+	ProtocolVersion             = 1
+	CapabilityCertSync          = "cert_sync"
+	CapabilityEdgeCaUpdate      = "edge_ca_update"
+	CapabilityEdgeSvcCertUpdate = "edge_svc_cert_update"
+	CapabilityTraceLog          = "trace_log"
+	CapabilityUpgradePreflight  = "upgrade_preflight"
+	CapabilityOutboxAck         = "outbox_ack"
+	// CapabilityReportDelay merges the capability changes in a short time into one report
+	CapabilityReportDelay = time.Second
+)
+
 // constants that uses in prepare_edgecore cmd
 const (
 	EdgeCorePipePath = "/run/edgecore.pipe"
@@ -616,16 +652,20 @@ const (
 	ModelFileRootPath   = "/var/lib/docker"
 	ModeFileActiveDir   = "/var/lib/docker/modelfile"
 	ModeFileDownloadDir = "/var/lib/docker/model_file_download"
+	ModeFilePartialDir  = "/var/lib/docker/model_file_download/.partial"
 	TargetTypeAll       = "all"
 	TargetTypeTemp      = "temp"
 )
 
 // const for msg limiter
 const (
-	MsgRate             = 5
-	BurstSize           = 5
-	MaxMsgThroughput    = 10 * MB
-	MsgThroughputPeriod = 30 * time.Second
+	MsgRate              = 5
+	BurstSize            = 5
+	MaxMsgThroughput     = 10 * MB
+	MsgThroughputPeriod  = 30 * time.Second
+	MsgCompressThreshold = 1 * KB
+	// MsgCompressLevel flate best speed
+	MsgCompressLevel = 1
 )
 
 // const for log recover
@@ -55,8 +55,9 @@ const (
 
 // MEFEdgeSDK upgrade manager constants
 const (
-	UpgradeManagerName  = "UpgradeManager"
-	DownloadManagerName = "DownloadManager"
+	UpgradeManagerName   = "UpgradeManager"
+	DownloadManagerName  = "DownloadManager"
+	SiteCacheManagerName = "SiteCacheManager"
 
 	EdgeInstallerFileName      = "edge-installer"
 	LogCollectTempDir          = "/home/data/mef_logcollect"
@@ -64,6 +65,7 @@ const (
 	BackUpPkgPath              = "/home/data/mefedge/backup"
 	PkgPath                    = "/home/data/mefedge/package"
 	EdgeDownloadPath           = "/home/data/MEFEdgeDownload"
+	EdgeSiteCachePath          = "/home/data/MEFEdgeSiteCache"
 	ConfigCertPathName         = "root-ca"
 	InstallerExtractOnlineMin  = 220 * MB
 	InstallerExtractWithZipMin = 290 * MB
@@ -84,6 +86,10 @@ const (
 	SignExt      = ".tar.gz.sig"
 )
 
+// DeltaBaseMismatch the error of applying a delta package against the installed software which is not its base,
+// the full package is downloaded instead
+const DeltaBaseMismatch = "the installed software is not the base of delta package"
+
 // MEF-Edge message resource constants
 const (
 	// ResEdgeCloudConnection is resource for edge-main to report if connection of edge-center is ready
@@ -92,6 +98,10 @@ const (
 	ResEdgeDownloadInfo = "/edge/download"
 	// ResUpgradeInfo is resource for online upgrading
 	ResUpgradeInfo = "/edge/upgrade"
+	// ResRollbackInfo is resource for rolling back to the inactive version
+	ResRollbackInfo = "/edge/rollback"
+	// ResPreflight is resource for checking the environment before upgrading without modifying anything
+	ResPreflight = "/edge/preflight"
 	// InnerPrepareDir resource that edge-main request edge-om for preparing work dir of download
 	InnerPrepareDir = "/inner/preparedir"
 	// InnerSoftwareVerification resource edge-main request edge-om for verify and unpack software downloaded online
@@ -100,10 +110,20 @@ const (
 	InnerSoftwareVersion = "/inner/version-info"
 	// ResDumpLogTask resource for the log-dumping tasks
 	ResDumpLogTask = "/logmgmt/dump/task"
+	// ArtifactDownloadUrl url for downloading the artifacts hosted by center
+	ArtifactDownloadUrl = "/artifact/download"
+	// ArtifactIdReg the id of artifacts hosted by center
+	ArtifactIdReg = "^[a-f0-9]{32}$"
 	// ResPackLogRequest is resource for the log-packing request
 	ResPackLogRequest = "/inner/logmgmt/pack/request"
 	// ResPackLogResponse is resource for the log-packing response
 	ResPackLogResponse = "/inner/logmgmt/pack/response"
+	// ResRemoteShell is resource for center to open, write, resize and close remote shell sessions
+	ResRemoteShell = "/remoteshell/session"
+	// ResPortForward is resource for center to connect, write and close port forward streams
+	ResPortForward = "/portforward/stream"
+	// ResTraceLog is resource for center to collect the log lines of a trace
+	ResTraceLog = "/trace/log"
 )
 
 // Location of software name and version in url
@@ -0,0 +1,103 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+//go:build MEFEdge_SDK
+
+package deltapkg
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"huawei.com/mindx/common/fileutils"
+	"huawei.com/mindx/mef/common/cmsverify"
+
+	"edge-installer/pkg/common/config"
+	"edge-installer/pkg/common/constants"
+)
+
+// ErrBaseMismatch the installed software is not the base of delta package, the full package should be used instead
+var ErrBaseMismatch = errors.New(constants.DeltaBaseMismatch)
+
+// Apply reconstructs the tree of the target full package into outDir, from the unpacked delta package in deltaDir
+// and the installed software in workDir. The manifest is verified by its signature, and every file reconstructed
+// is verified by the sha256 checksum in the manifest, so the result is the same as the signed target package
+func Apply(deltaDir, workDir, outDir string) error {
+	err := cmsverify.VerifyPackage(filepath.Join(deltaDir, ManifestCrlName), filepath.Join(deltaDir, ManifestCmsName),
+		filepath.Join(deltaDir, ManifestName))
+	if err != nil {
+		return fmt.Errorf("verify manifest of delta package failed: %v", err)
+	}
+	manifest, err := loadManifest(filepath.Join(deltaDir, ManifestName))
+	if err != nil {
+		return err
+	}
+	installedVersion, err := config.NewVersionXmlMgr(filepath.Join(workDir, constants.VersionXml)).GetVersion()
+	if err != nil {
+		return fmt.Errorf("get installed version failed: %v", err)
+	}
+	if installedVersion != manifest.BaseVersion {
+		return fmt.Errorf("%w: installed version is %s, base version is %s",
+			ErrBaseMismatch, installedVersion, manifest.BaseVersion)
+	}
+	realWorkDir, err := fileutils.EvalSymlinks(workDir)
+	if err != nil {
+		return fmt.Errorf("get real path of work dir failed: %v", err)
+	}
+	if err = fileutils.CreateDir(outDir, constants.Mode700); err != nil {
+		return fmt.Errorf("create dir [%s] failed: %v", outDir, err)
+	}
+	for _, entry := range manifest.Entries {
+		if err = applyEntry(entry, deltaDir, realWorkDir, outDir); err != nil {
+			return fmt.Errorf("reconstruct [%s] failed: %w", entry.Path, err)
+		}
+	}
+	// the modes of dirs are set at last, since a read-only dir cannot be filled
+	for i := len(manifest.Entries) - 1; i >= 0; i-- {
+		entry := manifest.Entries[i]
+		if entry.Type != typeDir {
+			continue
+		}
+		if err = os.Chmod(filepath.Join(outDir, entry.Path), os.FileMode(entry.Mode)); err != nil {
+			return fmt.Errorf("set mode of [%s] failed: %v", entry.Path, err)
+		}
+	}
+	return nil
+}
+
+func applyEntry(entry Entry, deltaDir, workDir, outDir string) error {
+	dst := filepath.Join(outDir, entry.Path)
+	switch entry.Type {
+	case typeDir:
+		return os.Mkdir(dst, constants.Mode700)
+	case typeSymlink:
+		return os.Symlink(entry.LinkTarget, dst)
+	default:
+	}
+	src := filepath.Join(deltaDir, filesDir, entry.Path)
+	if entry.FromBase {
+		src, _ = installedPath(workDir, entry.Path)
+	}
+	sum, err := copyAndHash(src, dst)
+	if err != nil {
+		if entry.FromBase {
+			return fmt.Errorf("%w: %v", ErrBaseMismatch, err)
+		}
+		return err
+	}
+	if sum != entry.Sha256 {
+		if entry.FromBase {
+			return fmt.Errorf("%w: the installed file is modified", ErrBaseMismatch)
+		}
+		return errors.New("the sha256 checksum of file in delta package is not matched")
+	}
+	return os.Chmod(dst, os.FileMode(entry.Mode))
+}
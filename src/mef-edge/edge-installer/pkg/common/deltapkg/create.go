@@ -0,0 +1,225 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+//go:build MEFEdge_SDK
+
+package deltapkg
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"syscall"
+
+	"huawei.com/mindx/common/fileutils"
+	"huawei.com/mindx/common/hwlog"
+	"huawei.com/mindx/mef/common/cmsverify"
+
+	"edge-installer/pkg/common/config"
+	"edge-installer/pkg/common/constants"
+)
+
+// Create creates the unpacked delta package in outDir from the signed full packages of base and target version,
+// the manifest in outDir should be signed as the full packages are before packing
+func Create(basePkg, targetPkg, outDir string) error {
+	for _, pkg := range []string{basePkg, targetPkg} {
+		if err := cmsverify.VerifyPackage(pkg+".crl", pkg+".cms", pkg); err != nil {
+			return fmt.Errorf("verify package [%s] failed: %v", filepath.Base(pkg), err)
+		}
+	}
+	tempDir, err := os.MkdirTemp("", "deltapkg")
+	if err != nil {
+		return fmt.Errorf("create temp dir failed: %v", err)
+	}
+	defer func() {
+		if err = fileutils.DeleteAllFileWithConfusion(tempDir); err != nil {
+			hwlog.RunLog.Warnf("delete temp dir failed: %v", err)
+		}
+	}()
+	baseDir := filepath.Join(tempDir, "base")
+	targetDir := filepath.Join(tempDir, "target")
+	manifest := &Manifest{FormatVersion: formatVersion}
+	if manifest.BaseVersion, err = unpackPackage(basePkg, baseDir); err != nil {
+		return err
+	}
+	if manifest.TargetVersion, err = unpackPackage(targetPkg, targetDir); err != nil {
+		return err
+	}
+	if err = fileutils.CreateDir(filepath.Join(outDir, filesDir), constants.Mode700); err != nil {
+		return fmt.Errorf("create dir of delta package failed: %v", err)
+	}
+	if manifest.Entries, err = diffTree(baseDir, targetDir, filepath.Join(outDir, filesDir)); err != nil {
+		return err
+	}
+	if err = manifest.check(); err != nil {
+		return fmt.Errorf("check manifest failed: %v", err)
+	}
+	return saveManifest(filepath.Join(outDir, ManifestName), manifest)
+}
+
+func unpackPackage(pkg, dir string) (string, error) {
+	if err := fileutils.ExtraTarGzFile(pkg, dir, true); err != nil {
+		return "", fmt.Errorf("unpack package [%s] failed: %v", filepath.Base(pkg), err)
+	}
+	version, err := config.NewVersionXmlMgr(filepath.Join(dir, constants.VersionXml)).GetVersion()
+	if err != nil {
+		return "", fmt.Errorf("get version of package [%s] failed: %v", filepath.Base(pkg), err)
+	}
+	return version, nil
+}
+
+// diffTree lists the whole target tree, and copies the files which cannot be taken from the base into filesPath
+func diffTree(baseDir, targetDir, filesPath string) ([]Entry, error) {
+	var entries []Entry
+	err := filepath.WalkDir(targetDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(targetDir, path)
+		if err != nil || relPath == "." {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		entry := Entry{Path: relPath, Mode: uint32(info.Mode().Perm())}
+		switch {
+		case d.IsDir():
+			entry.Type = typeDir
+		case d.Type()&fs.ModeSymlink != 0:
+			entry.Type = typeSymlink
+			if entry.LinkTarget, err = os.Readlink(path); err != nil {
+				return err
+			}
+			entry.Mode = 0
+		case d.Type().IsRegular():
+			entry.Type = typeFile
+			if entry.Sha256, entry.FromBase, err = diffFile(baseDir, targetDir, relPath); err != nil {
+				return err
+			}
+			if !entry.FromBase {
+				if err = copyToFilesDir(path, filepath.Join(filesPath, relPath)); err != nil {
+					return err
+				}
+			}
+		default:
+			return fmt.Errorf("unsupported file type of [%s]", relPath)
+		}
+		entries = append(entries, entry)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("diff the trees of packages failed: %v", err)
+	}
+	return entries, nil
+}
+
+// diffFile returns the sha256 checksum of the target file, and whether the file can be taken from the base
+func diffFile(baseDir, targetDir, relPath string) (string, bool, error) {
+	targetSum, err := fileSha256(filepath.Join(targetDir, relPath))
+	if err != nil {
+		return "", false, err
+	}
+	if _, ok := installedPath("", relPath); !ok {
+		return targetSum, false, nil
+	}
+	basePath := filepath.Join(baseDir, relPath)
+	info, err := os.Lstat(basePath)
+	if err != nil || !info.Mode().IsRegular() {
+		return targetSum, false, nil
+	}
+	baseSum, err := fileSha256(basePath)
+	if err != nil {
+		return "", false, err
+	}
+	return targetSum, baseSum == targetSum, nil
+}
+
+func fileSha256(path string) (string, error) {
+	file, err := os.OpenFile(path, os.O_RDONLY|syscall.O_NOFOLLOW, 0)
+	if err != nil {
+		return "", fmt.Errorf("open [%s] failed: %v", filepath.Base(path), err)
+	}
+	defer fileutils.CloseFile(file)
+	return hashCopy(io.Discard, file)
+}
+
+func copyToFilesDir(src, dst string) error {
+	if err := fileutils.CreateDir(filepath.Dir(dst), constants.Mode700); err != nil {
+		return fmt.Errorf("create dir of [%s] failed: %v", filepath.Base(dst), err)
+	}
+	_, err := copyAndHash(src, dst)
+	return err
+}
+
+// Pack packs the unpacked delta package in dir whose manifest has been signed into a tar.gz file
+func Pack(dir, out string) error {
+	for _, name := range []string{ManifestName, ManifestCmsName, ManifestCrlName} {
+		if !fileutils.IsExist(filepath.Join(dir, name)) {
+			return fmt.Errorf("%s does not exist in delta package", name)
+		}
+	}
+	outFile, err := os.OpenFile(out, os.O_WRONLY|os.O_CREATE|os.O_EXCL, constants.Mode600)
+	if err != nil {
+		return fmt.Errorf("create [%s] failed: %v", filepath.Base(out), err)
+	}
+	defer fileutils.CloseFile(outFile)
+	gzWriter := gzip.NewWriter(outFile)
+	tarWriter := tar.NewWriter(gzWriter)
+	for _, name := range []string{ManifestName, ManifestCmsName, ManifestCrlName, filesDir} {
+		if err = filepath.WalkDir(filepath.Join(dir, name), func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			return addToTar(tarWriter, dir, path, d)
+		}); err != nil {
+			return fmt.Errorf("pack delta package failed: %v", err)
+		}
+	}
+	if err = tarWriter.Close(); err != nil {
+		return fmt.Errorf("close tar writer failed: %v", err)
+	}
+	if err = gzWriter.Close(); err != nil {
+		return fmt.Errorf("close gzip writer failed: %v", err)
+	}
+	return nil
+}
+
+func addToTar(tarWriter *tar.Writer, dir, path string, d fs.DirEntry) error {
+	if !d.IsDir() && !d.Type().IsRegular() {
+		return errors.New("only dirs and regular files are allowed in delta package")
+	}
+	info, err := d.Info()
+	if err != nil {
+		return err
+	}
+	header, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return err
+	}
+	if header.Name, err = filepath.Rel(dir, path); err != nil {
+		return err
+	}
+	if err = tarWriter.WriteHeader(header); err != nil || d.IsDir() {
+		return err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer fileutils.CloseFile(file)
+	_, err = io.Copy(tarWriter, file)
+	return err
+}
@@ -0,0 +1,243 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+//go:build MEFEdge_SDK
+
+// Package deltapkg test for delta package
+package deltapkg
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/smartystreets/goconvey/convey"
+
+	"huawei.com/mindx/common/fileutils"
+	"huawei.com/mindx/mef/common/cmsverify"
+
+	"edge-installer/pkg/common/constants"
+)
+
+const (
+	baseVersion   = "5.0.RC1"
+	targetVersion = "5.0.RC2"
+	binPath       = "software/edge_main/bin/edge-main"
+	libPath       = "software/edge_main/lib/libcommon.so"
+	newPath       = "software/edge_om/bin/edge-om"
+	scriptPath    = "software/edge_installer/script/upgrade.sh"
+)
+
+func versionXml(version string) string {
+	return fmt.Sprintf("<SoftwarePackage><Package><Version>%s</Version>"+
+		"<InnerVersion>1.0</InnerVersion></Package></SoftwarePackage>", version)
+}
+
+func writeTree(dir string, files map[string]string) error {
+	for path, content := range files {
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(dir, path)), constants.Mode700); err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(dir, path), []byte(content), constants.Mode600); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// tarGzTree packs the tree in dir into pkg as the release process does
+func tarGzTree(dir, pkg string) error {
+	out, err := os.Create(pkg)
+	if err != nil {
+		return err
+	}
+	defer fileutils.CloseFile(out)
+	gzWriter := gzip.NewWriter(out)
+	tarWriter := tar.NewWriter(gzWriter)
+	if err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || path == dir {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		link := ""
+		if d.Type()&fs.ModeSymlink != 0 {
+			if link, err = os.Readlink(path); err != nil {
+				return err
+			}
+		}
+		header, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		if header.Name, err = filepath.Rel(dir, path); err != nil {
+			return err
+		}
+		if err = tarWriter.WriteHeader(header); err != nil || !d.Type().IsRegular() {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		_, err = tarWriter.Write(data)
+		return err
+	}); err != nil {
+		return err
+	}
+	if err = tarWriter.Close(); err != nil {
+		return err
+	}
+	return gzWriter.Close()
+}
+
+// preparePackages prepares the base and target full packages, and the installed software of base version
+func preparePackages(root string) (string, string, string, error) {
+	baseFiles := map[string]string{
+		constants.VersionXml: versionXml(baseVersion),
+		binPath:              "edge-main of base",
+		libPath:              "common lib",
+		scriptPath:           "upgrade script of base",
+	}
+	targetFiles := map[string]string{
+		constants.VersionXml: versionXml(targetVersion),
+		binPath:              "edge-main of target",
+		libPath:              "common lib",
+		newPath:              "edge-om of target",
+		scriptPath:           "upgrade script of base",
+	}
+	baseDir, targetDir := filepath.Join(root, "base"), filepath.Join(root, "target")
+	if err := writeTree(baseDir, baseFiles); err != nil {
+		return "", "", "", err
+	}
+	if err := writeTree(targetDir, targetFiles); err != nil {
+		return "", "", "", err
+	}
+	if err := os.Symlink("bin/edge-main", filepath.Join(targetDir, "software/edge_main/edge-main")); err != nil {
+		return "", "", "", err
+	}
+	basePkg, targetPkg := filepath.Join(root, "base.tar.gz"), filepath.Join(root, "target.tar.gz")
+	if err := tarGzTree(baseDir, basePkg); err != nil {
+		return "", "", "", err
+	}
+	if err := tarGzTree(targetDir, targetPkg); err != nil {
+		return "", "", "", err
+	}
+	workDir := filepath.Join(root, "installed")
+	if err := os.Rename(filepath.Join(baseDir, constants.SoftwareDir), workDir); err != nil {
+		return "", "", "", err
+	}
+	if err := os.Rename(filepath.Join(baseDir, constants.VersionXml),
+		filepath.Join(workDir, constants.VersionXml)); err != nil {
+		return "", "", "", err
+	}
+	return basePkg, targetPkg, workDir, nil
+}
+
+// signManifest stands for the release signing of the manifest
+func signManifest(deltaDir string) error {
+	for _, name := range []string{ManifestCmsName, ManifestCrlName} {
+		if err := os.WriteFile(filepath.Join(deltaDir, name), []byte(name), constants.Mode600); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestDeltaPackage(t *testing.T) {
+	patches := gomonkey.ApplyFuncReturn(cmsverify.VerifyPackage, nil)
+	defer patches.Reset()
+	root := t.TempDir()
+	basePkg, targetPkg, workDir, err := preparePackages(root)
+	if err != nil {
+		panic(err)
+	}
+	deltaDir := filepath.Join(root, "delta")
+	convey.Convey("create and pack delta package should be success", t, func() {
+		convey.So(Create(basePkg, targetPkg, deltaDir), convey.ShouldBeNil)
+		convey.So(fileutils.IsExist(filepath.Join(deltaDir, filesDir, libPath)), convey.ShouldBeFalse)
+		convey.So(fileutils.IsExist(filepath.Join(deltaDir, filesDir, binPath)), convey.ShouldBeTrue)
+		convey.So(Pack(deltaDir, filepath.Join(root, "delta.tar.gz")), convey.ShouldNotBeNil)
+		convey.So(signManifest(deltaDir), convey.ShouldBeNil)
+		convey.So(Pack(deltaDir, filepath.Join(root, "delta.tar.gz")), convey.ShouldBeNil)
+	})
+	unpackDir := filepath.Join(root, "unpack")
+	convey.Convey("apply delta package should be success", t, func() {
+		convey.So(fileutils.ExtraTarGzFile(filepath.Join(root, "delta.tar.gz"), unpackDir, false), convey.ShouldBeNil)
+		convey.So(IsDeltaPackage(unpackDir), convey.ShouldBeTrue)
+		fullDir := filepath.Join(root, "full")
+		convey.So(Apply(unpackDir, workDir, fullDir), convey.ShouldBeNil)
+		data, err := os.ReadFile(filepath.Join(fullDir, libPath))
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(string(data), convey.ShouldEqual, "common lib")
+		link, err := os.Readlink(filepath.Join(fullDir, "software/edge_main/edge-main"))
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(link, convey.ShouldEqual, "bin/edge-main")
+		fullPkg := filepath.Join(root, "full.tar.gz")
+		convey.So(tarGzTree(fullDir, fullPkg), convey.ShouldBeNil)
+		version, err := unpackPackage(fullPkg, filepath.Join(root, "check"))
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(version, convey.ShouldEqual, targetVersion)
+	})
+	convey.Convey("apply delta package against modified software should be failed", t, func() {
+		convey.So(os.WriteFile(filepath.Join(workDir, "edge_main/lib/libcommon.so"), []byte("modified"),
+			constants.Mode600), convey.ShouldBeNil)
+		err := Apply(unpackDir, workDir, filepath.Join(root, "modified"))
+		convey.So(errors.Is(err, ErrBaseMismatch), convey.ShouldBeTrue)
+	})
+	convey.Convey("apply delta package should be failed", t, func() {
+		testApplyFailed(root, unpackDir, workDir)
+	})
+}
+
+func testApplyFailed(root, unpackDir, workDir string) {
+	convey.Convey("apply delta package against other version should be failed", func() {
+		convey.So(os.WriteFile(filepath.Join(workDir, constants.VersionXml), []byte(versionXml("5.0.RC0")),
+			constants.Mode600), convey.ShouldBeNil)
+		err := Apply(unpackDir, workDir, filepath.Join(root, "other"))
+		convey.So(errors.Is(err, ErrBaseMismatch), convey.ShouldBeTrue)
+	})
+	convey.Convey("apply delta package with invalid signature should be failed", func() {
+		patches := gomonkey.ApplyFuncReturn(cmsverify.VerifyPackage, errors.New("verify failed"))
+		defer patches.Reset()
+		err := Apply(unpackDir, workDir, filepath.Join(root, "invalid"))
+		convey.So(err, convey.ShouldNotBeNil)
+		convey.So(errors.Is(err, ErrBaseMismatch), convey.ShouldBeFalse)
+	})
+}
+
+func TestManifestCheck(t *testing.T) {
+	const sha256Sum = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	newManifest := func(entries ...Entry) *Manifest {
+		return &Manifest{FormatVersion: formatVersion, BaseVersion: baseVersion, TargetVersion: targetVersion,
+			Entries: entries}
+	}
+	softwareDir := Entry{Path: constants.SoftwareDir, Type: typeDir, Mode: constants.Mode700}
+	convey.Convey("check manifest", t, func() {
+		convey.So(newManifest(softwareDir, Entry{Path: "software/a", Type: typeFile, Sha256: sha256Sum,
+			FromBase: true}).check(), convey.ShouldBeNil)
+		convey.So(newManifest(Entry{Path: "../a", Type: typeFile, Sha256: sha256Sum}).check(),
+			convey.ShouldNotBeNil)
+		convey.So(newManifest(Entry{Path: "software/a", Type: typeFile, Sha256: sha256Sum}).check(),
+			convey.ShouldNotBeNil)
+		convey.So(newManifest(Entry{Path: "a", Type: typeFile, Sha256: sha256Sum, FromBase: true}).check(),
+			convey.ShouldNotBeNil)
+		convey.So(newManifest(softwareDir, Entry{Path: "software/a", Type: typeSymlink,
+			LinkTarget: "../../etc"}).check(), convey.ShouldNotBeNil)
+		convey.So(newManifest(softwareDir, softwareDir).check(), convey.ShouldNotBeNil)
+	})
+}
@@ -0,0 +1,203 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+//go:build MEFEdge_SDK
+
+// Package deltapkg the delta package of edge software, it is produced from two signed full packages, and applied
+// against the installed software to reconstruct the tree of the target full package
+package deltapkg
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"syscall"
+
+	"huawei.com/mindx/common/fileutils"
+
+	"edge-installer/pkg/common/constants"
+)
+
+// files in the delta package, the manifest is signed as the full packages are
+const (
+	// ManifestName the manifest describing the whole tree of the target package
+	ManifestName = "delta.json"
+	// ManifestCmsName the cms signature of the manifest
+	ManifestCmsName = ManifestName + ".cms"
+	// ManifestCrlName the crl for verifying the signature of the manifest
+	ManifestCrlName = ManifestName + ".crl"
+	// filesDir the dir of the files which are added or changed in the target package
+	filesDir = "files"
+)
+
+const (
+	formatVersion   = 1
+	maxManifestSize = 16 * constants.MB
+	maxEntries      = 100000
+	maxFileMode     = 0777
+)
+
+// entry types of the tree
+const (
+	typeFile    = "file"
+	typeDir     = "dir"
+	typeSymlink = "symlink"
+)
+
+var sha256Pattern = regexp.MustCompile("^[a-f0-9]{64}$")
+
+// Manifest the manifest of a delta package, the files of the target package are checked by it after reconstructing
+type Manifest struct {
+	FormatVersion int     `json:"formatVersion"`
+	BaseVersion   string  `json:"baseVersion"`
+	TargetVersion string  `json:"targetVersion"`
+	Entries       []Entry `json:"entries"`
+}
+
+// Entry an entry in the tree of the target package, the parent dirs are listed before their children
+type Entry struct {
+	Path       string `json:"path"`
+	Type       string `json:"type"`
+	Mode       uint32 `json:"mode"`
+	Sha256     string `json:"sha256,omitempty"`
+	LinkTarget string `json:"linkTarget,omitempty"`
+	// FromBase the file is the same in the base and target packages, it is copied from the installed software
+	FromBase bool `json:"fromBase,omitempty"`
+}
+
+// IsDeltaPackage whether the unpacked package in dir is a delta package
+func IsDeltaPackage(dir string) bool {
+	return fileutils.IsExist(filepath.Join(dir, ManifestName))
+}
+
+// installedPath the path of the file of package in the installed software, the files in software dir of package
+// are installed into the work dir
+func installedPath(workDir, entryPath string) (string, bool) {
+	relPath, err := filepath.Rel(constants.SoftwareDir, entryPath)
+	if err != nil || relPath == "." || strings.HasPrefix(relPath, "..") {
+		return "", false
+	}
+	return filepath.Join(workDir, relPath), true
+}
+
+func loadManifest(manifestPath string) (*Manifest, error) {
+	data, err := fileutils.ReadLimitBytes(manifestPath, maxManifestSize)
+	if err != nil {
+		return nil, fmt.Errorf("load manifest failed: %v", err)
+	}
+	var manifest Manifest
+	if err = json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("unmarshal manifest failed: %v", err)
+	}
+	if err = manifest.check(); err != nil {
+		return nil, fmt.Errorf("check manifest failed: %v", err)
+	}
+	return &manifest, nil
+}
+
+func saveManifest(manifestPath string, manifest *Manifest) error {
+	data, err := json.MarshalIndent(manifest, "", "    ")
+	if err != nil {
+		return fmt.Errorf("marshal manifest failed: %v", err)
+	}
+	if err = fileutils.WriteData(manifestPath, data); err != nil {
+		return fmt.Errorf("save manifest failed: %v", err)
+	}
+	return nil
+}
+
+func (m *Manifest) check() error {
+	if m.FormatVersion != formatVersion {
+		return fmt.Errorf("unsupported format version %d", m.FormatVersion)
+	}
+	if m.BaseVersion == "" || m.TargetVersion == "" {
+		return errors.New("the base or target version is empty")
+	}
+	if len(m.Entries) == 0 || len(m.Entries) > maxEntries {
+		return fmt.Errorf("the count of entries is not in [1, %d]", maxEntries)
+	}
+	paths := make(map[string]string, len(m.Entries))
+	for _, entry := range m.Entries {
+		if err := entry.check(); err != nil {
+			return fmt.Errorf("entry [%s] is invalid: %v", entry.Path, err)
+		}
+		if _, ok := paths[entry.Path]; ok {
+			return fmt.Errorf("entry [%s] is duplicated", entry.Path)
+		}
+		if parent := filepath.Dir(entry.Path); parent != "." && paths[parent] != typeDir {
+			return fmt.Errorf("the parent dir of entry [%s] is not listed before it", entry.Path)
+		}
+		paths[entry.Path] = entry.Type
+	}
+	return nil
+}
+
+func (e *Entry) check() error {
+	if e.Path == "" || filepath.IsAbs(e.Path) || filepath.Clean(e.Path) != e.Path ||
+		strings.HasPrefix(e.Path, "..") {
+		return errors.New("the path is not a clean relative path")
+	}
+	if e.Mode > maxFileMode {
+		return errors.New("the mode is invalid")
+	}
+	switch e.Type {
+	case typeDir:
+		return nil
+	case typeFile:
+		if !sha256Pattern.MatchString(e.Sha256) {
+			return errors.New("the sha256 checksum is invalid")
+		}
+		if _, ok := installedPath("", e.Path); e.FromBase && !ok {
+			return errors.New("only the files in software dir can be copied from the installed software")
+		}
+		return nil
+	case typeSymlink:
+		target := filepath.Join(filepath.Dir(e.Path), e.LinkTarget)
+		if e.LinkTarget == "" || filepath.IsAbs(e.LinkTarget) || strings.HasPrefix(target, "..") {
+			return errors.New("the link target is out of the package")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported type %s", e.Type)
+	}
+}
+
+// copyAndHash copies the regular file to dst which does not exist, and returns the sha256 checksum of the content
+func copyAndHash(src, dst string) (string, error) {
+	srcFile, err := os.OpenFile(src, os.O_RDONLY|syscall.O_NOFOLLOW, 0)
+	if err != nil {
+		return "", fmt.Errorf("open [%s] failed: %v", src, err)
+	}
+	defer fileutils.CloseFile(srcFile)
+	info, err := srcFile.Stat()
+	if err != nil || !info.Mode().IsRegular() {
+		return "", fmt.Errorf("[%s] is not a regular file", src)
+	}
+	dstFile, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, constants.Mode600)
+	if err != nil {
+		return "", fmt.Errorf("create [%s] failed: %v", dst, err)
+	}
+	defer fileutils.CloseFile(dstFile)
+	return hashCopy(dstFile, srcFile)
+}
+
+func hashCopy(dst io.Writer, src io.Reader) (string, error) {
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(dst, hash), src); err != nil {
+		return "", fmt.Errorf("copy file failed: %v", err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -59,6 +59,27 @@ func (cpm *ConfigPathMgr) GetConfigBackupTempDir() string {
 	return filepath.Join(cpm.GetMefEdgeDir(), constants.ConfigBackupTmp)
 }
 
+// GetConfigRollbackDir get config snapshot dir for rollback. default: /usr/local/mindx/MEFEdge/config_rollback
+func (cpm *ConfigPathMgr) GetConfigRollbackDir() string {
+	return filepath.Join(cpm.GetMefEdgeDir(), constants.ConfigRollback)
+}
+
+// GetConfigRollbackTempDir get config rollback temp dir. default: /usr/local/mindx/MEFEdge/config_rollback_temp
+func (cpm *ConfigPathMgr) GetConfigRollbackTempDir() string {
+	return filepath.Join(cpm.GetMefEdgeDir(), constants.ConfigRollbackTmp)
+}
+
+// GetRollbackGuardPath get rollback guard path. default: /usr/local/mindx/MEFEdge/rollback_guard.json
+func (cpm *ConfigPathMgr) GetRollbackGuardPath() string {
+	return filepath.Join(cpm.GetMefEdgeDir(), constants.RollbackGuardFile)
+}
+
+// GetCenterConnectedFlagPath get the flag of having connected to center.
+// default: /usr/local/mindx/MEFEdge/center_connected
+func (cpm *ConfigPathMgr) GetCenterConnectedFlagPath() string {
+	return filepath.Join(cpm.GetMefEdgeDir(), constants.CenterConnectedFlagFile)
+}
+
 // GetCompConfigDir get component config dir. e.g. /usr/local/mindx/MEFEdge/config/edge_main
 func (cpm *ConfigPathMgr) GetCompConfigDir(component string) string {
 	return filepath.Join(cpm.GetConfigDir(), component)
@@ -125,6 +146,12 @@ func (cpm *ConfigPathMgr) GetSnPath() string {
 	return filepath.Join(cpm.GetCompConfigDir(constants.EdgeInstaller), constants.SnFileName)
 }
 
+// GetCertPolicyPath get the policy of imported third-party certs.
+// default: /usr/local/mindx/MEFEdge/config/edge_installer/cert-policy.json
+func (cpm *ConfigPathMgr) GetCertPolicyPath() string {
+	return filepath.Join(cpm.GetCompConfigDir(constants.EdgeInstaller), constants.CertPolicyFileName)
+}
+
 // GetDockerBackupPath get docker.service.bak path.
 // default: /usr/local/mindx/MEFEdge/config/edge_installer/docker.service.bak
 func (cpm *ConfigPathMgr) GetDockerBackupPath() string {
@@ -136,6 +163,24 @@ func (cpm *ConfigPathMgr) GetEdgeMainDbPath() string {
 	return filepath.Join(cpm.GetCompConfigDir(constants.EdgeMain), constants.DbEdgeMainPath)
 }
 
+// GetDownloadPolicyPath get the path of bandwidth cap and time window of downloads.
+// default: /usr/local/mindx/MEFEdge/config/edge_main/download-policy.json
+func (cpm *ConfigPathMgr) GetDownloadPolicyPath() string {
+	return filepath.Join(cpm.GetCompConfigDir(constants.EdgeMain), constants.DownloadPolicyFileName)
+}
+
+// GetSiteCachePath get the path of site cache role config.
+// default: /usr/local/mindx/MEFEdge/config/edge_main/site-cache.json
+func (cpm *ConfigPathMgr) GetSiteCachePath() string {
+	return filepath.Join(cpm.GetCompConfigDir(constants.EdgeMain), constants.SiteCacheFileName)
+}
+
+// GetLinkCompressionPath get the path of compression config of the connection to MEF Center.
+// default: /usr/local/mindx/MEFEdge/config/edge_main/link-compression.json
+func (cpm *ConfigPathMgr) GetLinkCompressionPath() string {
+	return filepath.Join(cpm.GetCompConfigDir(constants.EdgeMain), constants.LinkCompressionFileName)
+}
+
 // GetOMCertDir get mindXOm cert dir. default: /usr/local/mindx/MEFEdge/config/edge_main/peer_certs/mindXOM
 func (cpm *ConfigPathMgr) GetOMCertDir() string {
 	return filepath.Join(cpm.GetCompConfigDir(constants.EdgeMain), constants.PeerCerts, constants.MindXOMDir)
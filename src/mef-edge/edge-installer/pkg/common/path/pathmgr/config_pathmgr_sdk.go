@@ -92,3 +92,9 @@ func (cpm *ConfigPathMgr) GetNetCfgTempRootCertPath() string {
 func (cpm *ConfigPathMgr) GetNetCfgTempRootCertBackupPath() string {
 	return filepath.Join(cpm.GetNetConfigTempDir(), constants.RootCertBackUpName)
 }
+
+// GetActiveCenterPath get the path of MEF Center address which edge-main is connected to.
+// default: /usr/local/mindx/MEFEdge/config/edge_main/active-center.json
+func (cpm *ConfigPathMgr) GetActiveCenterPath() string {
+	return filepath.Join(cpm.GetCompConfigDir(constants.EdgeMain), constants.ActiveCenterFileName)
+}
@@ -107,6 +107,10 @@ func testConfigPathMgr() {
 	configPathMgr.GetTempCertsDir()
 	configPathMgr.GetConfigBackupDir()
 	configPathMgr.GetConfigBackupTempDir()
+	configPathMgr.GetConfigRollbackDir()
+	configPathMgr.GetConfigRollbackTempDir()
+	configPathMgr.GetRollbackGuardPath()
+	configPathMgr.GetCenterConnectedFlagPath()
 	configPathMgr.GetCompConfigDir(constants.EdgeMain)
 	configPathMgr.GetCompKmcDir(constants.EdgeMain)
 	configPathMgr.GetCompKmcConfigPath(constants.EdgeMain)
@@ -122,6 +126,9 @@ func testConfigPathMgr() {
 	configPathMgr.GetSnPath()
 	configPathMgr.GetDockerBackupPath()
 	configPathMgr.GetEdgeMainDbPath()
+	configPathMgr.GetDownloadPolicyPath()
+	configPathMgr.GetSiteCachePath()
+	configPathMgr.GetLinkCompressionPath()
 	configPathMgr.GetOMCertDir()
 	configPathMgr.GetOMRootCertPath()
 	configPathMgr.GetContainerConfigPath()
@@ -31,6 +31,7 @@ import (
 type FlagLock interface {
 	Lock() error
 	Unlock() error
+	Check() error
 }
 
 type flagLock struct {
@@ -108,6 +109,17 @@ func (f *flagLock) Unlock() error {
 	return nil
 }
 
+// Check checks whether the process flag can be locked, the flag file is not modified
+func (f *flagLock) Check() error {
+	if atomic.LoadUint32(&f.status) == locking {
+		return errors.New("process flag is already locked")
+	}
+	if !fileutils.IsExist(f.flagPath) {
+		return nil
+	}
+	return f.checkProcessLocked()
+}
+
 func (f *flagLock) writeFlag() error {
 	pid := os.Getpid()
 	pidString := strconv.Itoa(pid)
@@ -191,3 +191,27 @@ func testUnlockErrDelete() {
 	err := lock.Unlock()
 	convey.So(err, convey.ShouldResemble, errors.New("remove process flag file failed"))
 }
+
+func TestCheck(t *testing.T) {
+	convey.Convey("check should be failed, process flag is already locked", t, func() {
+		atomic.StoreUint32(&lock.status, locking)
+		defer atomic.StoreUint32(&lock.status, none)
+		convey.So(lock.Check(), convey.ShouldResemble, errors.New("process flag is already locked"))
+	})
+
+	convey.Convey("check should be success, flag file does not exist", t, func() {
+		var p1 = gomonkey.ApplyFuncReturn(fileutils.IsExist, false).
+			ApplyFuncReturn(fileutils.DeleteFile, testErr)
+		defer p1.Reset()
+		convey.So(lock.Check(), convey.ShouldBeNil)
+	})
+
+	convey.Convey("check should be failed, another process is running", t, func() {
+		var p1 = gomonkey.ApplyFuncReturn(fileutils.IsExist, true).
+			ApplyFuncReturn(fileutils.LoadFile, []byte("1\nedge-om\nupgrade"), nil).
+			ApplyFuncReturn(IsProcessActive, true).
+			ApplyFuncReturn(GetProcName, "edge-om", nil)
+		defer p1.Reset()
+		convey.So(lock.Check(), convey.ShouldNotBeNil)
+	})
+}
@@ -18,11 +18,46 @@ type SoftwareDownloadInfo struct {
 	DownloadInfo DownloadInfo `json:"downloadInfo"`
 }
 
-// DownloadInfo [struct] to software download info
+// DownloadInfo [struct] to software download info, the software is downloaded from the artifact repository of
+// center if the artifact id is set, otherwise from the package url with the username and password.
+// The artifact is downloaded from the site cache first if it is set, and from center if the site cache fails.
+// The site cache is authenticated by the serial number of its cert delivered by center.
+// The delta artifact is downloaded instead of the full one if it is set, and the full one is downloaded if the
+// installed software is not the base of the delta package
 type DownloadInfo struct {
-	Package  string `json:"package"`
-	UserName string `json:"username"`
-	Password []byte `json:"password"`
+	Package         string `json:"package"`
+	UserName        string `json:"username"`
+	Password        []byte `json:"password"`
+	ArtifactId      string `json:"artifactId,omitempty"`
+	Sha256          string `json:"sha256,omitempty"`
+	SiteCacheIp     string `json:"siteCacheIp,omitempty"`
+	SiteCachePort   int    `json:"siteCachePort,omitempty"`
+	SiteCacheCertSn string `json:"siteCacheCertSn,omitempty"`
+	DeltaArtifactId string `json:"deltaArtifactId,omitempty"`
+	DeltaSha256     string `json:"deltaSha256,omitempty"`
+}
+
+// PreflightRequest request from center for checking whether the software can be upgraded to the version, the
+// checks of preupgrade are run without modifying anything
+type PreflightRequest struct {
+	CheckId      string `json:"checkId"`
+	SoftwareName string `json:"softwareName"`
+	Version      string `json:"version"`
+	InnerVersion string `json:"innerVersion,omitempty"`
+}
+
+// PreflightItem the result of a check item of preflight
+type PreflightItem struct {
+	Name    string `json:"name"`
+	Passed  bool   `json:"passed"`
+	Message string `json:"message,omitempty"`
+}
+
+// PreflightReport the results of preflight reported to center
+type PreflightReport struct {
+	CheckId        string          `json:"checkId"`
+	CurrentVersion string          `json:"currentVersion"`
+	Items          []PreflightItem `json:"items"`
 }
 
 // SoftwareUpdateInfo content for download software
@@ -112,14 +112,13 @@ func (cm *certManager) connectTest() bool {
 	}
 
 	hwlog.RunLog.Info("start to check connection and certs with cloud core")
-	netCfg := configpara.GetNetConfig()
-	serverAddr := fmt.Sprintf("https://%s:%d", netCfg.IP, constants.DefaultCloudCoreWsPort)
+	serverAddr := fmt.Sprintf("https://%s:%d", configpara.GetCenterAddr(), constants.DefaultCloudCoreWsPort)
 
 	certInfo, err := getWsCertInfo()
 	if err != nil {
 		return false
 	}
-	if _, err := httpsmgr.GetHttpsReq(serverAddr, *certInfo).Get(nil); err != nil &&
+	if _, err := httpsmgr.GetHttpsReq(serverAddr, *certInfo).SetProxy(configpara.GetProxyFunc()).Get(nil); err != nil &&
 		!strings.HasPrefix(err.Error(), "https return error status code:") {
 		hwlog.RunLog.Errorf("establish tls connection with cloud core error: %v", err)
 		return false
@@ -244,10 +243,9 @@ func (cm *certManager) getServerCert() error {
 		WithBackup: true,
 	}
 
-	netCfg := configpara.GetNetConfig()
-
-	content, err := httpsmgr.GetHttpsReq(fmt.Sprintf("https://%s:%d/edge.crt", netCfg.IP,
-		constants.DefaultCloudCoreCertPort), tlsCfg, reqHeaders).Get(bytes.NewReader(csr))
+	content, err := httpsmgr.GetHttpsReq(fmt.Sprintf("https://%s:%d/edge.crt", configpara.GetCenterAddr(),
+		constants.DefaultCloudCoreCertPort), tlsCfg, reqHeaders).SetProxy(configpara.GetProxyFunc()).
+		Get(bytes.NewReader(csr))
 	if err != nil {
 		return err
 	}
@@ -151,6 +151,7 @@ func (c *cloudCoreProxy) connectToCloud() error {
 		HandshakeTimeout: handshakeTimeout,
 		ReadBufferSize:   readBufferSize,
 		WriteBufferSize:  writeBufferSize,
+		Proxy:            configpara.GetProxyFunc(),
 	}
 
 	netConfig := configpara.GetNetConfig()
@@ -167,7 +168,7 @@ func (c *cloudCoreProxy) connectToCloud() error {
 	headers.Set("node_id", strings.ToLower(sn))
 	headers.Set("project_id", defaultProjectID)
 	headers.Set("ConnectionUse", "msg")
-	serverAddr := fmt.Sprintf("wss://%s:%d/%s/%s/events", netConfig.IP,
+	serverAddr := fmt.Sprintf("wss://%s:%d/%s/%s/events", configpara.GetCenterAddr(),
 		constants.DefaultCloudCoreWsPort, defaultProjectID, strings.ToLower(sn))
 	hwlog.RunLog.Info("websocket client try to connect server")
 
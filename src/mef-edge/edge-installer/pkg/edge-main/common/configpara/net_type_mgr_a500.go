@@ -13,6 +13,8 @@
 package configpara
 
 import (
+	"huawei.com/mindx/common/httpsmgr"
+
 	"edge-installer/pkg/common/config"
 	"edge-installer/pkg/common/constants"
 )
@@ -25,3 +27,8 @@ func GetNetType() (string, error) {
 func setNetConfig(cfg *config.NetManager) {
 	netCfg = *cfg
 }
+
+// GetProxyFunc [method] connections are not proxied on this platform
+func GetProxyFunc() httpsmgr.ProxyFunc {
+	return nil
+}
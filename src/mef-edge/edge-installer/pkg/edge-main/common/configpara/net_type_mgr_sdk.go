@@ -13,12 +13,24 @@
 package configpara
 
 import (
+	"errors"
+	"net/http"
+	"net/url"
+	"sync"
+
+	"huawei.com/mindx/common/httpsmgr"
+	"huawei.com/mindx/common/hwlog"
 	"huawei.com/mindx/common/utils"
 
 	"edge-installer/pkg/common/config"
 	"edge-installer/pkg/common/constants"
 )
 
+var (
+	activeCenter     string
+	activeCenterLock sync.RWMutex
+)
+
 // GetNetType [method] for get net type
 func GetNetType() (string, error) {
 	return constants.MEF, nil
@@ -26,5 +38,42 @@ func GetNetType() (string, error) {
 
 func setNetConfig(cfg *config.NetManager) {
 	utils.ClearSliceByteMemory(netCfg.Token)
+	utils.ClearSliceByteMemory(netCfg.ProxyPwd)
 	netCfg = *cfg
 }
+
+// GetProxyFunc [method] get the http proxy for connections to MEF Center and file servers, nil means connecting
+// directly. If the proxy config is invalid, the connections fail instead of bypassing the proxy
+func GetProxyFunc() httpsmgr.ProxyFunc {
+	proxyCfg := &httpsmgr.ProxyCfg{
+		Url:      netCfg.ProxyUrl,
+		UserName: netCfg.ProxyUser,
+		Password: netCfg.ProxyPwd,
+		NoProxy:  netCfg.NoProxy,
+	}
+	proxy, err := proxyCfg.GetProxyFunc()
+	if err != nil {
+		hwlog.RunLog.Errorf("http proxy config is invalid, connections are refused, error: %v", err)
+		return func(*http.Request) (*url.URL, error) {
+			return nil, errors.New("http proxy config is invalid")
+		}
+	}
+	return proxy
+}
+
+// SetActiveCenter [method] set the MEF Center address which edgehub is connected to
+func SetActiveCenter(addr string) {
+	activeCenterLock.Lock()
+	defer activeCenterLock.Unlock()
+	activeCenter = addr
+}
+
+// GetCenterAddr [method] get the MEF Center address in use, it is the first configured address before connected
+func GetCenterAddr() string {
+	activeCenterLock.RLock()
+	defer activeCenterLock.RUnlock()
+	if activeCenter != "" {
+		return activeCenter
+	}
+	return netCfg.IP
+}
@@ -26,7 +26,8 @@ import (
 )
 
 var (
-	centerIp       string
+	// centerAddr the address (ip or domain name) of MEF Center in use
+	centerAddr     string
 	originalMsgMap sync.Map
 )
 
@@ -36,12 +37,12 @@ func MEFOpLog(msg *model.Message) {
 		return
 	}
 
-	centerIp = configpara.GetNetConfig().IP
+	centerAddr = configpara.GetCenterAddr()
 	operation := msg.KubeEdgeRouter.Operation
 	resource := msg.KubeEdgeRouter.Resource
 	id := msg.Header.ID
 
-	recordMEFOpLog(centerIp, operation, resource, constants.Start, id)
+	recordMEFOpLog(centerAddr, operation, resource, constants.Start, id)
 	originalMsgMap.Store(id, operation+":"+resource)
 	go deleteUnusedMsg(id)
 	return
@@ -79,14 +80,14 @@ func MEFOpLogWithRes(resp *model.Message) {
 	defer originalMsgMap.Delete(resp.Header.ParentID)
 	var content string
 	if err = resp.ParseContent(&content); err != nil {
-		recordMEFOpLog(centerIp, originalOpt, originalRes, constants.Failed, originalId)
+		recordMEFOpLog(centerAddr, originalOpt, originalRes, constants.Failed, originalId)
 		return
 	}
 	if content != constants.OK {
-		recordMEFOpLog(centerIp, originalOpt, originalRes, constants.Failed, originalId)
+		recordMEFOpLog(centerAddr, originalOpt, originalRes, constants.Failed, originalId)
 		return
 	}
-	recordMEFOpLog(centerIp, originalOpt, originalRes, constants.Success, originalId)
+	recordMEFOpLog(centerAddr, originalOpt, originalRes, constants.Success, originalId)
 }
 
 func ignoreMEFOpLogWithRes(resp *model.Message) bool {
@@ -111,12 +112,12 @@ func getMEFOriginalOpAndRes(msg *model.Message) (string, string, error) {
 	return opAndRes[0], opAndRes[1], nil
 }
 
-func recordMEFOpLog(ip, operation, resource, result, msgId string) {
+func recordMEFOpLog(addr, operation, resource, result, msgId string) {
 	switch result {
 	case constants.Start, constants.Success:
-		hwlog.OpLog.Infof("[%s@%s] %s %s %s, [msgId:%s]", constants.MEF, ip, operation, resource, result, msgId)
+		hwlog.OpLog.Infof("[%s@%s] %s %s %s, [msgId:%s]", constants.MEF, addr, operation, resource, result, msgId)
 	case constants.Failed:
-		hwlog.OpLog.Errorf("[%s@%s] %s %s %s, [msgId:%s]", constants.MEF, ip, operation, resource, result, msgId)
+		hwlog.OpLog.Errorf("[%s@%s] %s %s %s, [msgId:%s]", constants.MEF, addr, operation, resource, result, msgId)
 	default:
 		hwlog.RunLog.Error("error operation log result")
 	}
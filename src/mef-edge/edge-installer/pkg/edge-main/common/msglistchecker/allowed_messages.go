@@ -197,6 +197,11 @@ func initMefUpstreamMsgWhiteList() {
 		newResourceInfo(noParentID, asyncMessage, constants.ResSoftwareVersion),
 		newResourceInfo(noParentID, asyncMessage, constants.ResDownloadProgress),
 		newResourceInfo(noParentID, asyncMessage, constants.ResDumpLogTaskError),
+		newResourceInfo(noParentID, asyncMessage, constants.ResRemoteShellOutput),
+		newResourceInfo(noParentID, asyncMessage, constants.ResPortForwardData),
+		newResourceInfo(noParentID, asyncMessage, constants.ResTraceLogData),
+		newResourceInfo(noParentID, asyncMessage, constants.ResPreflightResult),
+		newResourceInfo(noParentID, asyncMessage, constants.ResEdgeCapability),
 	}
 
 	edgeToCenterByPost := []resourceInfo{
@@ -63,6 +63,7 @@ func (mhv MsgHeaderValidator) Check(msg *model.Message) bool {
 	checkItems := []func(msg *model.Message) bool{
 		mhv.checkHeaderId,
 		mhv.checkHeaderParentId,
+		mhv.checkHeaderTraceId,
 		mhv.checkHeaderResourceVersion,
 		mhv.checkRoute,
 	}
@@ -97,6 +98,19 @@ func (mhv MsgHeaderValidator) checkHeaderParentId(msg *model.Message) bool {
 	return true
 }
 
+func (mhv MsgHeaderValidator) checkHeaderTraceId(msg *model.Message) bool {
+	if msg.Header.TraceId == "" {
+		return true
+	}
+
+	if !checker.RegexStringChecker(msg.Header.TraceId, "^"+constants.UUIDRegex+"$") {
+		hwlog.RunLog.Error("trace id check failed")
+		return false
+	}
+
+	return true
+}
+
 func (mhv MsgHeaderValidator) checkHeaderResourceVersion(msg *model.Message) bool {
 	if !checker.RegexStringChecker(msg.Header.ResourceVersion, constants.ResourceVersionRegex) {
 		hwlog.RunLog.Error("ResourceVersion check failed")
@@ -172,6 +186,7 @@ func (mhv MefMsgHeaderValidator) Check(msg *model.Message) bool {
 	checkItems := []func(msg *model.Message) bool{
 		mhv.checkHeaderId,
 		mhv.checkHeaderParentId,
+		mhv.checkHeaderTraceId,
 		mhv.checkHeaderResourceVersion,
 		mhv.checkRoute,
 	}
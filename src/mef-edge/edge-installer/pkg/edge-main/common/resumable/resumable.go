@@ -0,0 +1,370 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package resumable for downloading files with http range resume, retry and the download policy of the node
+package resumable
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"time"
+
+	"huawei.com/mindx/common/fileutils"
+	"huawei.com/mindx/common/httpsmgr"
+	"huawei.com/mindx/common/hwlog"
+
+	"edge-installer/pkg/common/config"
+	"edge-installer/pkg/common/constants"
+	"edge-installer/pkg/common/path"
+)
+
+const (
+	// CheckpointSuffix the checkpoint of a partial file is saved beside it with this suffix
+	CheckpointSuffix   = ".ckpt"
+	checkpointInterval = 8 * constants.MB
+	maxCheckpointSize  = 1024
+	defaultRetryTimes  = 5
+	defaultBackoff     = 2 * time.Second
+	maxBackoff         = time.Minute
+	bytesPerKB         = 1024
+)
+
+var errOutOfWindow = errors.New("download is paused out of the download time window")
+
+// checkpoint the downloaded size and the sha256 of the downloaded part, the partial file is resumed
+// from the checkpoint only if it is for the same url and the downloaded part is not changed
+type checkpoint struct {
+	Url    string `json:"url"`
+	Offset int64  `json:"offset"`
+	Sha256 string `json:"sha256"`
+}
+
+// Task a download task which resumes from the last checkpoint of the partial file
+type Task struct {
+	// OnWrite is called with the downloaded size after each write, the download aborts if it returns error
+	OnWrite func(downloaded int64) error
+	Policy  config.DownloadPolicy
+	// RetryAfterLimit the total time to wait when the server asks to retry later, the retries are not counted
+	// until it is used up. The Retry-After of the server is not honored if it is 0
+	RetryAfterLimit time.Duration
+
+	url        string
+	savePath   string
+	sizeLimit  int64
+	newReq     func() *httpsmgr.HttpsRequest
+	ctx        context.Context
+	retryTimes int
+	backoff    time.Duration
+
+	restored   bool
+	offset     int64
+	ckptOffset int64
+	hash       hash.Hash
+	abortErr   error
+}
+
+// NewTask create a resumable download task, newReq creates the request of url for each attempt
+func NewTask(ctx context.Context, url, savePath string, sizeLimit int64,
+	newReq func() *httpsmgr.HttpsRequest) *Task {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return &Task{
+		Policy:     LoadPolicy(),
+		url:        url,
+		savePath:   savePath,
+		sizeLimit:  sizeLimit,
+		newReq:     newReq,
+		ctx:        ctx,
+		retryTimes: defaultRetryTimes,
+		backoff:    defaultBackoff,
+		hash:       sha256.New(),
+	}
+}
+
+// LoadPolicy load the download policy of the node, no limit is applied if it is not set or invalid
+func LoadPolicy() config.DownloadPolicy {
+	configPathMgr, err := path.GetConfigPathMgr()
+	if err != nil {
+		hwlog.RunLog.Warnf("get config path manager failed, download without policy: %v", err)
+		return config.DownloadPolicy{}
+	}
+	policyPath := configPathMgr.GetDownloadPolicyPath()
+	if !fileutils.IsExist(policyPath) {
+		return config.DownloadPolicy{}
+	}
+	policy, err := config.LoadDownloadPolicy(policyPath)
+	if err != nil {
+		hwlog.RunLog.Warnf("load download policy failed, download without policy: %v", err)
+		return config.DownloadPolicy{}
+	}
+	if err = policy.Check(); err != nil {
+		hwlog.RunLog.Warnf("download policy is invalid, download without policy: %v", err)
+		return config.DownloadPolicy{}
+	}
+	return *policy
+}
+
+// GetCheckpointPath get the checkpoint path of the file to download
+func GetCheckpointPath(savePath string) string {
+	return savePath + CheckpointSuffix
+}
+
+// Run download the file, the partial file and its checkpoint are kept if it fails for resuming next time.
+// The retries are counted from the last attempt that made progress, a slow but advancing link is not given up
+func (t *Task) Run() error {
+	backoff := t.backoff
+	var retryAfterWaited time.Duration
+	for retry := 0; ; {
+		if err := t.waitWindow(); err != nil {
+			return err
+		}
+		lastOffset := t.offset
+		err := t.downloadOnce()
+		if err == nil {
+			t.removeCheckpoint()
+			return nil
+		}
+		if t.abortErr != nil {
+			return t.abortErr
+		}
+		if errors.Is(err, errOutOfWindow) {
+			continue
+		}
+		if errors.Is(err, httpsmgr.ErrRangeNotSupported) {
+			hwlog.RunLog.Warn("server does not support range, download from the beginning")
+			t.reset()
+			continue
+		}
+		var statusErr *httpsmgr.StatusError
+		if errors.As(err, &statusErr) && statusErr.RetryAfter > 0 &&
+			retryAfterWaited+statusErr.RetryAfter <= t.RetryAfterLimit {
+			retryAfterWaited += statusErr.RetryAfter
+			hwlog.RunLog.Infof("server asks to retry after %v", statusErr.RetryAfter)
+			if sleepErr := t.sleep(statusErr.RetryAfter); sleepErr != nil {
+				return sleepErr
+			}
+			continue
+		}
+		if t.offset > lastOffset {
+			retry, backoff = 0, t.backoff
+		}
+		if retry >= t.retryTimes {
+			return err
+		}
+		retry++
+		hwlog.RunLog.Warnf("download failed at offset %d, retry %d/%d after %v: %v",
+			t.offset, retry, t.retryTimes, backoff, err)
+		if sleepErr := t.sleep(backoff); sleepErr != nil {
+			return sleepErr
+		}
+		if backoff *= 2; backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+func (t *Task) downloadOnce() error {
+	file, err := t.openPartialFile()
+	if err != nil {
+		// the local file errors are not retried
+		t.abortErr = err
+		return err
+	}
+	defer func() {
+		if err = file.Close(); err != nil {
+			hwlog.RunLog.Error("close file error")
+		}
+	}()
+
+	writer := &taskWriter{task: t, file: file, start: time.Now()}
+	downloadErr := t.newReq().GetRangeRespToFile(writer, t.offset, t.sizeLimit-t.offset)
+	t.saveCheckpoint(file)
+	if writer.outOfWindow {
+		return errOutOfWindow
+	}
+	return downloadErr
+}
+
+func (t *Task) openPartialFile() (*os.File, error) {
+	if _, err := fileutils.CheckOriginPath(t.savePath); err != nil {
+		hwlog.RunLog.Error("create file from https req failed, path is no a file")
+		return nil, errors.New("path is no a file")
+	}
+	file, err := os.OpenFile(t.savePath, os.O_RDWR|os.O_CREATE, fileutils.Mode600)
+	if err != nil {
+		return nil, err
+	}
+	if !t.restored {
+		t.restore(file)
+		t.restored = true
+	}
+	if err = file.Truncate(t.offset); err != nil {
+		fileutils.CloseFile(file)
+		return nil, fmt.Errorf("truncate partial file failed: %v", err)
+	}
+	if _, err = file.Seek(t.offset, io.SeekStart); err != nil {
+		fileutils.CloseFile(file)
+		return nil, fmt.Errorf("seek partial file failed: %v", err)
+	}
+	if t.offset > 0 {
+		hwlog.RunLog.Infof("resume download from offset %d", t.offset)
+	}
+	return file, nil
+}
+
+// restore the partial file from its checkpoint, it is downloaded from the beginning if the checkpoint is not valid
+func (t *Task) restore(file *os.File) {
+	t.offset, t.ckptOffset = 0, 0
+	t.hash = sha256.New()
+	ckptPath := GetCheckpointPath(t.savePath)
+	if !fileutils.IsExist(ckptPath) {
+		return
+	}
+	data, err := fileutils.ReadLimitBytes(ckptPath, maxCheckpointSize)
+	if err != nil {
+		hwlog.RunLog.Warnf("read download checkpoint failed: %v", err)
+		return
+	}
+	var ckpt checkpoint
+	if err = json.Unmarshal(data, &ckpt); err != nil {
+		hwlog.RunLog.Warnf("unmarshal download checkpoint failed: %v", err)
+		return
+	}
+	if ckpt.Url != t.url || ckpt.Offset <= 0 || ckpt.Offset > t.sizeLimit {
+		hwlog.RunLog.Info("download checkpoint does not match, download from the beginning")
+		return
+	}
+	hasher := sha256.New()
+	if _, err = io.CopyN(hasher, file, ckpt.Offset); err != nil {
+		hwlog.RunLog.Warnf("partial file is shorter than the checkpoint, download from the beginning: %v", err)
+		return
+	}
+	if hex.EncodeToString(hasher.Sum(nil)) != ckpt.Sha256 {
+		hwlog.RunLog.Warn("partial file does not match the checkpoint, download from the beginning")
+		return
+	}
+	t.offset, t.ckptOffset, t.hash = ckpt.Offset, ckpt.Offset, hasher
+}
+
+func (t *Task) reset() {
+	t.offset, t.ckptOffset = 0, 0
+	t.hash = sha256.New()
+	t.removeCheckpoint()
+}
+
+func (t *Task) saveCheckpoint(file *os.File) {
+	if t.offset == t.ckptOffset {
+		return
+	}
+	if err := file.Sync(); err != nil {
+		hwlog.RunLog.Warnf("sync partial file failed, checkpoint is not saved: %v", err)
+		return
+	}
+	data, err := json.Marshal(checkpoint{Url: t.url, Offset: t.offset, Sha256: hex.EncodeToString(t.hash.Sum(nil))})
+	if err != nil {
+		hwlog.RunLog.Warnf("marshal download checkpoint failed: %v", err)
+		return
+	}
+	if err = fileutils.WriteData(GetCheckpointPath(t.savePath), data); err != nil {
+		hwlog.RunLog.Warnf("save download checkpoint failed: %v", err)
+		return
+	}
+	t.ckptOffset = t.offset
+}
+
+func (t *Task) removeCheckpoint() {
+	ckptPath := GetCheckpointPath(t.savePath)
+	if !fileutils.IsExist(ckptPath) {
+		return
+	}
+	if err := fileutils.DeleteFile(ckptPath); err != nil {
+		hwlog.RunLog.Warnf("remove download checkpoint failed: %v", err)
+	}
+}
+
+func (t *Task) waitWindow() error {
+	waitTime := t.Policy.WaitTime(time.Now())
+	if waitTime == 0 {
+		return nil
+	}
+	hwlog.RunLog.Infof("out of the download time window [%s-%s], wait %v", t.Policy.WindowStart,
+		t.Policy.WindowEnd, waitTime)
+	return t.sleep(waitTime)
+}
+
+func (t *Task) sleep(duration time.Duration) error {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+	select {
+	case <-t.ctx.Done():
+		return errors.New("download task canceled")
+	case <-timer.C:
+		return nil
+	}
+}
+
+// taskWriter writes the response into the partial file, it checkpoints periodically and throttles the writing
+// to the bandwidth cap
+type taskWriter struct {
+	task        *Task
+	file        *os.File
+	start       time.Time
+	written     int64
+	outOfWindow bool
+}
+
+func (w *taskWriter) Write(p []byte) (int, error) {
+	t := w.task
+	if t.ctx.Err() != nil {
+		t.abortErr = errors.New("download task canceled")
+		return 0, t.abortErr
+	}
+	if t.Policy.WaitTime(time.Now()) > 0 {
+		w.outOfWindow = true
+		return 0, errOutOfWindow
+	}
+	n, err := w.file.Write(p)
+	t.hash.Write(p[:n])
+	t.offset += int64(n)
+	if err != nil {
+		return n, err
+	}
+	if t.OnWrite != nil {
+		if err = t.OnWrite(t.offset); err != nil {
+			t.abortErr = err
+			return n, err
+		}
+	}
+	if t.offset-t.ckptOffset >= checkpointInterval {
+		t.saveCheckpoint(w.file)
+	}
+	w.throttle(n)
+	return n, nil
+}
+
+func (w *taskWriter) throttle(n int) {
+	w.written += int64(n)
+	if w.task.Policy.BandwidthLimit <= 0 {
+		return
+	}
+	expected := time.Duration(float64(w.written) / float64(w.task.Policy.BandwidthLimit*bytesPerKB) *
+		float64(time.Second))
+	if elapsed := time.Since(w.start); elapsed < expected {
+		time.Sleep(expected - elapsed)
+	}
+}
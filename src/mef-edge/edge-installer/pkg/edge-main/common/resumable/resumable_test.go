@@ -0,0 +1,290 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package resumable test for resumable downloads
+package resumable
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/smartystreets/goconvey/convey"
+
+	"huawei.com/mindx/common/fileutils"
+	"huawei.com/mindx/common/httpsmgr"
+	"huawei.com/mindx/common/test"
+	"huawei.com/mindx/common/x509/certutils"
+
+	"edge-installer/pkg/common/config"
+)
+
+const (
+	testUrl     = "https://127.0.0.1/model.zip"
+	testLimit   = 1024 * 1024
+	contentSize = 20 * 1024
+)
+
+var (
+	testDir      = "/tmp/test_resumable_download"
+	testSavePath = filepath.Join(testDir, "model.zip")
+	testContent  = bytes.Repeat([]byte("0123456789abcdef"), contentSize/16)
+	errTestBreak = errors.New("connection reset")
+)
+
+func TestMain(m *testing.M) {
+	tcBase := &test.TcBase{}
+	test.RunWithPatches(tcBase, m, nil)
+}
+
+// fakeServer serves the test content from the offset, the first breakAt bytes are served before
+// the connection breaks for each attempt if breakAt is set, the connection breaks after step bytes are served for
+// each attempt if step is set, the first busyTimes attempts are asked to retry later
+type fakeServer struct {
+	offsets       []int64
+	breakAt       int64
+	step          int64
+	ignoreRange   bool
+	breakOnceOnly bool
+	busyTimes     int
+}
+
+func (f *fakeServer) patch() *gomonkey.Patches {
+	return gomonkey.ApplyMethod(&httpsmgr.HttpsRequest{}, "GetRangeRespToFile",
+		func(_ *httpsmgr.HttpsRequest, writer io.Writer, offset, limit int64) error {
+			f.offsets = append(f.offsets, offset)
+			if f.busyTimes > 0 {
+				f.busyTimes--
+				return &httpsmgr.StatusError{StatusCode: http.StatusServiceUnavailable, RetryAfter: time.Millisecond}
+			}
+			if f.ignoreRange && offset > 0 {
+				return httpsmgr.ErrRangeNotSupported
+			}
+			data := testContent[offset:]
+			if f.step > 0 && int64(len(data)) > f.step {
+				if _, err := writer.Write(data[:f.step]); err != nil {
+					return err
+				}
+				return errTestBreak
+			}
+			if f.breakAt > 0 && f.breakAt > offset {
+				if _, err := writer.Write(testContent[offset:f.breakAt]); err != nil {
+					return err
+				}
+				if f.breakOnceOnly {
+					f.breakAt = 0
+				}
+				return errTestBreak
+			}
+			_, err := writer.Write(data)
+			return err
+		})
+}
+
+func newTestTask() *Task {
+	task := NewTask(context.Background(), testUrl, testSavePath, testLimit, func() *httpsmgr.HttpsRequest {
+		return httpsmgr.GetHttpsReq(testUrl, certutils.TlsCertInfo{})
+	})
+	task.backoff = time.Millisecond
+	return task
+}
+
+func clearTestFiles() {
+	for _, file := range []string{testSavePath, GetCheckpointPath(testSavePath)} {
+		if err := fileutils.DeleteAllFileWithConfusion(file); err != nil {
+			panic(err)
+		}
+	}
+}
+
+func checkDownloadedContent() {
+	data, err := fileutils.ReadLimitBytes(testSavePath, testLimit)
+	convey.So(err, convey.ShouldBeNil)
+	convey.So(bytes.Equal(data, testContent), convey.ShouldBeTrue)
+	convey.So(fileutils.IsExist(GetCheckpointPath(testSavePath)), convey.ShouldBeFalse)
+}
+
+func TestResumableDownload(t *testing.T) {
+	if err := fileutils.CreateDir(testDir, fileutils.Mode700); err != nil {
+		panic(err)
+	}
+	defer func() {
+		if err := fileutils.DeleteAllFileWithConfusion(testDir); err != nil {
+			t.Logf("clear test dir failed: %v", err)
+		}
+	}()
+
+	convey.Convey("broken download resumes from the broken offset", t, resumeAfterBreak)
+	convey.Convey("download resumes from the checkpoint of last run", t, resumeFromCheckpoint)
+	convey.Convey("download restarts if the server does not support range", t, restartWithoutRange)
+	convey.Convey("download fails after retries", t, failAfterRetries)
+	convey.Convey("retries are counted from the last progress", t, resetRetriesOnProgress)
+	convey.Convey("download waits when the server asks to retry later", t, waitForRetryAfter)
+	convey.Convey("download aborts without retry", t, abortWithoutRetry)
+	convey.Convey("download waits for the time window", t, waitForWindow)
+	convey.Convey("download is throttled by the bandwidth cap", t, throttleByBandwidth)
+}
+
+func resumeAfterBreak() {
+	defer clearTestFiles()
+	server := &fakeServer{breakAt: contentSize / 2, breakOnceOnly: true}
+	p := server.patch()
+	defer p.Reset()
+
+	convey.So(newTestTask().Run(), convey.ShouldBeNil)
+	convey.So(server.offsets, convey.ShouldResemble, []int64{0, contentSize / 2})
+	checkDownloadedContent()
+}
+
+func resumeFromCheckpoint() {
+	defer clearTestFiles()
+	server := &fakeServer{breakAt: contentSize / 4}
+	p := server.patch()
+	task := newTestTask()
+	task.retryTimes = 0
+	convey.So(task.Run(), convey.ShouldResemble, errTestBreak)
+	convey.So(fileutils.IsExist(GetCheckpointPath(testSavePath)), convey.ShouldBeTrue)
+	p.Reset()
+
+	server = &fakeServer{}
+	p = server.patch()
+	defer p.Reset()
+	convey.So(newTestTask().Run(), convey.ShouldBeNil)
+	convey.So(server.offsets, convey.ShouldResemble, []int64{contentSize / 4})
+	checkDownloadedContent()
+
+	convey.Convey("checkpoint of another url is not resumed", func() {
+		convey.So(fileutils.WriteData(testSavePath, testContent[:contentSize/4]), convey.ShouldBeNil)
+		convey.So(fileutils.WriteData(GetCheckpointPath(testSavePath),
+			[]byte(`{"url":"https://127.0.0.1/other.zip","offset":5120,"sha256":""}`)), convey.ShouldBeNil)
+		server.offsets = nil
+		convey.So(newTestTask().Run(), convey.ShouldBeNil)
+		convey.So(server.offsets, convey.ShouldResemble, []int64{0})
+		checkDownloadedContent()
+	})
+}
+
+func restartWithoutRange() {
+	defer clearTestFiles()
+	server := &fakeServer{breakAt: contentSize / 2, breakOnceOnly: true, ignoreRange: true}
+	p := server.patch()
+	defer p.Reset()
+
+	convey.So(newTestTask().Run(), convey.ShouldBeNil)
+	convey.So(server.offsets, convey.ShouldResemble, []int64{0, contentSize / 2, 0})
+	checkDownloadedContent()
+}
+
+func failAfterRetries() {
+	defer clearTestFiles()
+	p := gomonkey.ApplyMethodReturn(&httpsmgr.HttpsRequest{}, "GetRangeRespToFile", errTestBreak)
+	defer p.Reset()
+
+	task := newTestTask()
+	convey.So(task.Run(), convey.ShouldResemble, errTestBreak)
+	convey.So(fileutils.IsExist(GetCheckpointPath(testSavePath)), convey.ShouldBeFalse)
+}
+
+func resetRetriesOnProgress() {
+	defer clearTestFiles()
+	const steps = 4
+	server := &fakeServer{step: contentSize / steps}
+	p := server.patch()
+	defer p.Reset()
+
+	task := newTestTask()
+	task.retryTimes = 1
+	convey.So(task.Run(), convey.ShouldBeNil)
+	convey.So(server.offsets, convey.ShouldResemble,
+		[]int64{0, contentSize / steps, contentSize / steps * 2, contentSize / steps * 3})
+	checkDownloadedContent()
+}
+
+func waitForRetryAfter() {
+	defer clearTestFiles()
+	const busyTimes = defaultRetryTimes + 1
+	server := &fakeServer{busyTimes: busyTimes}
+	p := server.patch()
+	defer p.Reset()
+
+	task := newTestTask()
+	task.retryTimes = 0
+	task.RetryAfterLimit = busyTimes * time.Millisecond
+	convey.So(task.Run(), convey.ShouldBeNil)
+	convey.So(len(server.offsets), convey.ShouldEqual, busyTimes+1)
+	checkDownloadedContent()
+
+	convey.Convey("retry after is not honored beyond the limit", func() {
+		server.busyTimes = busyTimes
+		task = newTestTask()
+		task.retryTimes = 0
+		task.RetryAfterLimit = time.Millisecond
+		var statusErr *httpsmgr.StatusError
+		convey.So(errors.As(task.Run(), &statusErr), convey.ShouldBeTrue)
+	})
+}
+
+func abortWithoutRetry() {
+	defer clearTestFiles()
+	server := &fakeServer{}
+	p := server.patch()
+	defer p.Reset()
+
+	task := newTestTask()
+	errAbort := errors.New("exceed wanted file size")
+	task.OnWrite = func(downloaded int64) error {
+		return errAbort
+	}
+	convey.So(task.Run(), convey.ShouldResemble, errAbort)
+	convey.So(server.offsets, convey.ShouldResemble, []int64{0})
+	convey.So(fileutils.IsExist(GetCheckpointPath(testSavePath)), convey.ShouldBeTrue)
+}
+
+func waitForWindow() {
+	defer clearTestFiles()
+	server := &fakeServer{}
+	p := server.patch()
+	defer p.Reset()
+
+	now := time.Now()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*10)
+	defer cancel()
+	task := NewTask(ctx, testUrl, testSavePath, testLimit, func() *httpsmgr.HttpsRequest {
+		return httpsmgr.GetHttpsReq(testUrl, certutils.TlsCertInfo{})
+	})
+	task.Policy = config.DownloadPolicy{
+		WindowStart: now.Add(time.Hour).Format(config.DownloadWindowLayout),
+		WindowEnd:   now.Add(time.Hour * 2).Format(config.DownloadWindowLayout),
+	}
+	convey.So(task.Run(), convey.ShouldResemble, errors.New("download task canceled"))
+	convey.So(server.offsets, convey.ShouldBeEmpty)
+}
+
+func throttleByBandwidth() {
+	defer clearTestFiles()
+	server := &fakeServer{}
+	p := server.patch()
+	defer p.Reset()
+
+	const bandwidthLimit = 100
+	task := newTestTask()
+	task.Policy = config.DownloadPolicy{BandwidthLimit: bandwidthLimit}
+	start := time.Now()
+	convey.So(task.Run(), convey.ShouldBeNil)
+	convey.So(time.Since(start), convey.ShouldBeGreaterThanOrEqualTo,
+		time.Duration(contentSize*int64(time.Second)/(bandwidthLimit*bytesPerKB)))
+	checkDownloadedContent()
+}
@@ -26,6 +26,7 @@ import (
 
 type downloadMgr struct {
 	ctx    context.Context
+	cancel context.CancelFunc
 	enable bool
 	lock   sync.Mutex
 }
@@ -35,7 +36,7 @@ func NewDownloadMgr(enable bool) model.Module {
 	module := &downloadMgr{
 		enable: enable,
 	}
-	module.ctx = context.Background()
+	module.ctx, module.cancel = context.WithCancel(context.Background())
 	return module
 }
 
@@ -49,6 +50,12 @@ func (d *downloadMgr) Enable() bool {
 	return d.enable
 }
 
+// Stop [method] stop this module, the running download is canceled and its partial file is kept
+func (d *downloadMgr) Stop() bool {
+	d.cancel()
+	return true
+}
+
 // Start [method] start this module
 func (d *downloadMgr) Start() {
 	hwlog.RunLog.Info("download manager start success")
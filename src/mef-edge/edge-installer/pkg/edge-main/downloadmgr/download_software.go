@@ -12,13 +12,14 @@
 package downloadmgr
 
 import (
+	"context"
 	"encoding/base64"
 	"errors"
 	"fmt"
 	"net/http"
-	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"huawei.com/mindx/common/envutils"
 	"huawei.com/mindx/common/fileutils"
@@ -30,15 +31,20 @@ import (
 	"edge-installer/pkg/common/constants"
 	"edge-installer/pkg/common/util"
 	"edge-installer/pkg/edge-main/common/configpara"
+	"edge-installer/pkg/edge-main/common/resumable"
+	"edge-installer/pkg/edge-main/sitecache"
 )
 
 const (
 	downloadPackageType = "package"
 	defaultFileSize     = 1 * constants.MB
 	softwarePackageSize = constants.InstallerTarGzSizeMaxInMB * constants.MB
+	// siteCacheWaitLimit the total time to wait for the site cache fetching the artifact from center
+	siteCacheWaitLimit = 30 * time.Minute
 )
 
 type downloadParams struct {
+	ctx         context.Context
 	savePath    string
 	sizeLimit   int64
 	downloadUrl string
@@ -46,6 +52,15 @@ type downloadParams struct {
 	password    []byte
 	caContent   []byte
 	crlContent  []byte
+	centerTls   *certutils.TlsCertInfo
+	// siteCacheUrl the url of the artifact served by the site cache, the artifact is downloaded from center if
+	// the site cache fails. The site cache is pinned by siteCacheCertSn, the serial number of its cert
+	siteCacheUrl    string
+	siteCacheCertSn string
+	// onWrite is called with the downloaded size while downloading
+	onWrite func(downloaded int64) error
+	// retryAfterLimit the total time to wait when the server asks to retry later
+	retryAfterLimit time.Duration
 }
 
 func (dp *downloadProcess) downloadSoftware() error {
@@ -70,6 +85,7 @@ func (dp *downloadProcess) downloadSoftware() error {
 		}
 
 		info := downloadParams{
+			ctx:         dp.ctx,
 			savePath:    filePath,
 			sizeLimit:   limit,
 			downloadUrl: url,
@@ -77,11 +93,25 @@ func (dp *downloadProcess) downloadSoftware() error {
 			password:    dp.sfwDownloadInfo.DownloadInfo.Password,
 			caContent:   dp.cert,
 			crlContent:  dp.crlContent,
+			centerTls:   dp.centerTls,
+			onWrite:     newBytesReporter().onWrite,
+		}
+		if downloadInfo := dp.sfwDownloadInfo.DownloadInfo; downloadInfo.SiteCacheIp != "" {
+			info.siteCacheUrl = sitecache.GetPeerArtifactUrl(downloadInfo.SiteCacheIp, downloadInfo.SiteCachePort,
+				downloadInfo.ArtifactId)
+			info.siteCacheCertSn = downloadInfo.SiteCacheCertSn
 		}
 
 		if err = createHttpsReqAndSaveToFile(info); err != nil {
+			dp.keepPartial = fileutils.IsExist(resumable.GetCheckpointPath(filePath))
 			return err
 		}
+		// the artifact hosted by center is checked end to end by its checksum, no matter where it is downloaded from
+		if checksum := dp.sfwDownloadInfo.DownloadInfo.Sha256; checksum != "" {
+			if err = sitecache.CheckSha256(filePath, checksum); err != nil {
+				return err
+			}
+		}
 	}
 	dp.progress = progressVerifying
 	return nil
@@ -106,6 +136,10 @@ func parseUrlInfo(url string) (string, error) {
 
 func getValidUrls(downloadRequire util.SoftwareDownloadInfo) (map[string]string, error) {
 	var urls = make(map[string]string)
+	if artifactId := downloadRequire.DownloadInfo.ArtifactId; artifactId != "" {
+		urls[downloadPackageType] = sitecache.GetCenterArtifactUrl(artifactId)
+		return urls, nil
+	}
 	var url string
 	var err error
 	if url, err = parseUrlInfo(downloadRequire.DownloadInfo.Package); err != nil {
@@ -118,22 +152,9 @@ func getValidUrls(downloadRequire util.SoftwareDownloadInfo) (map[string]string,
 }
 
 func createHttpsReqAndSaveToFile(params downloadParams) error {
-	if _, err := fileutils.CheckOriginPath(params.savePath); err != nil {
-		hwlog.RunLog.Error("create file from https req failed, path is no a file")
-		return errors.New("path is no a file")
-	}
-	fileWriter, err := os.OpenFile(params.savePath, os.O_RDWR|os.O_CREATE, fileutils.Mode600)
-	if err != nil {
-		return err
+	if params.centerTls != nil {
+		return downloadFromCenter(params)
 	}
-	defer func() {
-		err = fileWriter.Close()
-		if err != nil {
-			hwlog.RunLog.Error("close file error")
-			return
-		}
-	}()
-
 	tlsCfg := certutils.TlsCertInfo{
 		RootCaContent: params.caContent,
 		CrlContent:    params.crlContent,
@@ -154,8 +175,53 @@ func createHttpsReqAndSaveToFile(params downloadParams) error {
 	defer utils.ClearStringMemory(enCodeCloudUsrAndPwdStr)
 	defer utils.ClearStringMemory(authorization)
 
-	return httpsmgr.GetHttpsReq(params.downloadUrl, tlsCfg, reqHeaders).
-		GetRespToFileWithLimit(fileWriter, params.sizeLimit)
+	newReq := func() *httpsmgr.HttpsRequest {
+		return httpsmgr.GetHttpsReq(params.downloadUrl, tlsCfg, reqHeaders).SetProxy(configpara.GetProxyFunc())
+	}
+	return runDownloadTask(params, newReq)
+}
+
+// downloadFromCenter downloads the artifact hosted by center, the edge is authenticated by its cert.
+// The artifact is downloaded from the site cache in the same site first if it is set
+func downloadFromCenter(params downloadParams) error {
+	reqHeaders := map[string]interface{}{
+		"nodeID": configpara.GetInstallerConfig().SerialNumber,
+	}
+	if params.siteCacheUrl != "" {
+		err := downloadFromSiteCache(params, reqHeaders)
+		if err == nil {
+			return nil
+		}
+		hwlog.RunLog.Warnf("download from site cache failed, download from center instead: %v", err)
+	}
+	newReq := func() *httpsmgr.HttpsRequest {
+		return httpsmgr.GetHttpsReq(params.downloadUrl, *params.centerTls, reqHeaders).
+			SetProxy(configpara.GetProxyFunc())
+	}
+	return runDownloadTask(params, newReq)
+}
+
+// downloadFromSiteCache the site cache is authenticated by its cert issued by center, which has no host name, so it
+// is pinned by the serial number of the cert delivered by center.
+// The task is identified by the url of center, so the partial file from site cache is resumed from center.
+// The peer waits while the site cache is fetching the artifact from center
+func downloadFromSiteCache(params downloadParams, reqHeaders map[string]interface{}) error {
+	params.retryAfterLimit = siteCacheWaitLimit
+	siteCacheTls := *params.centerTls
+	siteCacheTls.SkipHostVerify = true
+	siteCacheTls.PeerCertSn = params.siteCacheCertSn
+	newReq := func() *httpsmgr.HttpsRequest {
+		return httpsmgr.GetHttpsReq(params.siteCacheUrl, siteCacheTls, reqHeaders)
+	}
+	return runDownloadTask(params, newReq)
+}
+
+// runDownloadTask the task is identified by the url of params, newReq may request another url serving the same file
+func runDownloadTask(params downloadParams, newReq func() *httpsmgr.HttpsRequest) error {
+	task := resumable.NewTask(params.ctx, params.downloadUrl, params.savePath, params.sizeLimit, newReq)
+	task.OnWrite = params.onWrite
+	task.RetryAfterLimit = params.retryAfterLimit
+	return task.Run()
 }
 
 func getTargetFilePath(softwareName string, packageType string) (string, error) {
@@ -15,7 +15,6 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
 	"os"
 	"testing"
 
@@ -25,10 +24,13 @@ import (
 
 	"huawei.com/mindx/common/envutils"
 	"huawei.com/mindx/common/fileutils"
-	"huawei.com/mindx/common/httpsmgr"
 	"huawei.com/mindx/common/modulemgr/model"
+	"huawei.com/mindx/common/x509/certutils"
 
+	"edge-installer/pkg/common/constants"
 	"edge-installer/pkg/common/util"
+	"edge-installer/pkg/edge-main/common/configpara"
+	"edge-installer/pkg/edge-main/common/resumable"
 )
 
 func TestDownloadSoftware(t *testing.T) {
@@ -39,13 +41,7 @@ func TestDownloadSoftware(t *testing.T) {
 	convey.Convey("success case", t, func() {
 		patches := []*gomonkey.Patches{
 			gomonkey.ApplyFuncReturn(envutils.CheckDiskSpace, nil),
-			gomonkey.ApplyFuncReturn(fileutils.CheckOriginPath, "", nil),
-			gomonkey.ApplyFuncReturn(os.OpenFile, &os.File{}, nil),
-			gomonkey.ApplyMethodReturn(&fileutils.FileLinkChecker{}, "Check", nil),
-			gomonkey.ApplyPrivateMethod(&httpsmgr.HttpsRequest{}, "GetRespToFileWithLimit",
-				func(writer io.Writer, limit int64) error {
-					return nil
-				}),
+			gomonkey.ApplyMethodReturn(&resumable.Task{}, "Run", nil),
 		}
 		defer func() {
 			for _, patch := range patches {
@@ -55,6 +51,16 @@ func TestDownloadSoftware(t *testing.T) {
 		processErr := dp.downloadSoftware()
 		convey.So(processErr, convey.ShouldBeNil)
 	})
+
+	convey.Convey("partial package is kept when download failed", t, func() {
+		patches := gomonkey.ApplyFuncReturn(envutils.CheckDiskSpace, nil).
+			ApplyMethodReturn(&resumable.Task{}, "Run", errors.New("connection reset")).
+			ApplyFuncReturn(fileutils.IsExist, true)
+		defer patches.Reset()
+		processErr := dp.downloadSoftware()
+		convey.So(processErr, convey.ShouldNotBeNil)
+		convey.So(dp.keepPartial, convey.ShouldBeTrue)
+	})
 }
 
 func TestParseUrlInfo(t *testing.T) {
@@ -87,6 +93,44 @@ func TestGetValidUrls(t *testing.T) {
 		sfwDownloadInfo.DownloadInfo.Package = temp
 	})
 
+	convey.Convey("artifact url of center", t, func() {
+		const artifactId = "0123456789abcdef0123456789abcdef"
+		configpara.SetActiveCenter("127.0.0.1")
+		defer configpara.SetActiveCenter("")
+		info := sfwDownloadInfo
+		info.DownloadInfo.ArtifactId = artifactId
+		urls, processErr := getValidUrls(info)
+		convey.So(processErr, convey.ShouldBeNil)
+		convey.So(urls[downloadPackageType], convey.ShouldStartWith, "https://127.0.0.1:")
+		convey.So(urls[downloadPackageType], convey.ShouldEndWith, constants.ArtifactDownloadUrl+"?id="+artifactId)
+	})
+}
+
+func TestDownloadFromCenter(t *testing.T) {
+	params := downloadParams{
+		savePath:     "/tmp/test_download_from_center",
+		downloadUrl:  "https://127.0.0.1:30003/artifact/download?id=0123456789abcdef0123456789abcdef",
+		centerTls:    &certutils.TlsCertInfo{},
+		siteCacheUrl: "https://192.168.1.10:8443/artifact/download?id=0123456789abcdef0123456789abcdef",
+	}
+	convey.Convey("download from site cache", t, func() {
+		patches := gomonkey.ApplyMethodReturn(&resumable.Task{}, "Run", nil)
+		defer patches.Reset()
+		convey.So(downloadFromCenter(params), convey.ShouldBeNil)
+	})
+	convey.Convey("download from center when site cache failed", t, func() {
+		patches := gomonkey.ApplyMethodSeq(&resumable.Task{}, "Run", []gomonkey.OutputCell{
+			{Values: gomonkey.Params{errors.New("site cache is unreachable")}},
+			{Values: gomonkey.Params{nil}},
+		})
+		defer patches.Reset()
+		convey.So(downloadFromCenter(params), convey.ShouldBeNil)
+	})
+	convey.Convey("download from center failed", t, func() {
+		patches := gomonkey.ApplyMethodReturn(&resumable.Task{}, "Run", errors.New("connection reset"))
+		defer patches.Reset()
+		convey.So(downloadFromCenter(params), convey.ShouldNotBeNil)
+	})
 }
 
 func TestCreateHttpsReqAndSaveToFile(t *testing.T) {
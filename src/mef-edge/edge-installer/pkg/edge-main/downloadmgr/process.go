@@ -12,9 +12,12 @@
 package downloadmgr
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"math"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"huawei.com/mindx/common/checker"
@@ -24,11 +27,15 @@ import (
 	"huawei.com/mindx/common/modulemgr"
 	"huawei.com/mindx/common/modulemgr/model"
 	"huawei.com/mindx/common/utils"
+	"huawei.com/mindx/common/x509/certutils"
 
 	"edge-installer/pkg/common/config"
 	"edge-installer/pkg/common/constants"
 	"edge-installer/pkg/common/util"
+	"edge-installer/pkg/edge-main/common/cloudcert"
 	"edge-installer/pkg/edge-main/common/configpara"
+	"edge-installer/pkg/edge-main/common/resumable"
+	"edge-installer/pkg/edge-main/sitecache"
 )
 
 const (
@@ -39,13 +46,31 @@ const (
 	progressSuccess        = 100
 	minPwdLength           = 8
 	maxPwdLength           = 20
+	bytesReportInterval    = 5 * time.Second
+	// certSnReg the serial number of the site cache cert in hex, at most 20 bytes
+	certSnReg = "^[0-9a-fA-F]{1,40}$"
+
+	stagePreparing   = "preparing"
+	stageDownloading = "downloading"
+	stageVerifying   = "verifying"
+	stageDownloaded  = "downloaded"
 )
 
+var errDeltaBaseMismatch = errors.New(constants.DeltaBaseMismatch)
+
 type downloadProcess struct {
+	// ctx the download is canceled when the module stops
+	ctx             context.Context
 	cert            []byte
 	crlContent      []byte
 	progress        uint64
 	sfwDownloadInfo util.SoftwareDownloadInfo
+	// centerTls the tls cert info for downloading the artifact hosted by center
+	centerTls *certutils.TlsCertInfo
+	// keepPartial the partial package is kept for resuming if downloading fails
+	keepPartial bool
+	// useDelta the delta package is downloaded instead of the full one
+	useDelta bool
 }
 
 type edgeReportUpgradeResInfo struct {
@@ -55,7 +80,7 @@ type edgeReportUpgradeResInfo struct {
 
 func (d *downloadMgr) processDownloadSoftware(msg model.Message) error {
 	hwlog.RunLog.Info("start to process download software")
-	var dp downloadProcess
+	dp := downloadProcess{ctx: d.ctx}
 	if err := msg.ParseContent(&dp.sfwDownloadInfo); err != nil {
 		hwlog.RunLog.Errorf("get download process failed: %v", err)
 		return errors.New("get download process failed")
@@ -70,12 +95,32 @@ func (d *downloadMgr) processDownloadSoftware(msg model.Message) error {
 		dp.downloadSoftware,
 		dp.verifyAndUnpack,
 	}
+	err := dp.runTasks(processTasks)
+	if !errors.Is(err, errDeltaBaseMismatch) {
+		return err
+	}
+	hwlog.RunLog.Warn("the installed software is not the base of delta package, download the full package instead")
+	dp.useFullPackage()
+	return dp.runTasks([]func() error{
+		dp.prepareDownloadDir,
+		dp.checkDownloadDir,
+		dp.downloadSoftware,
+		dp.verifyAndUnpack,
+	})
+}
 
+func (dp *downloadProcess) runTasks(processTasks []func() error) error {
 	for _, task := range processTasks {
 		err := task()
+		// the full package is downloaded when the delta package cannot be applied, it is not a failure
+		if errors.Is(err, errDeltaBaseMismatch) {
+			return err
+		}
 		reportDownloadProcess(dp.progress, err)
 		if err != nil {
-			dp.cleanDownloadDir()
+			if !dp.keepPartial {
+				dp.cleanDownloadDir()
+			}
 			hwlog.RunLog.Errorf("process download software failed, %v", err)
 			return err
 		}
@@ -83,30 +128,83 @@ func (d *downloadMgr) processDownloadSoftware(msg model.Message) error {
 	return nil
 }
 
+// useDeltaPackage downloads the delta artifact instead of the full one if it is set
+func (dp *downloadProcess) useDeltaPackage() {
+	info := &dp.sfwDownloadInfo.DownloadInfo
+	if info.ArtifactId == "" || info.DeltaArtifactId == "" {
+		return
+	}
+	info.ArtifactId, info.DeltaArtifactId = info.DeltaArtifactId, info.ArtifactId
+	info.Sha256, info.DeltaSha256 = info.DeltaSha256, info.Sha256
+	dp.useDelta = true
+}
+
+func (dp *downloadProcess) useFullPackage() {
+	info := &dp.sfwDownloadInfo.DownloadInfo
+	info.ArtifactId, info.DeltaArtifactId = info.DeltaArtifactId, ""
+	info.Sha256, info.DeltaSha256 = info.DeltaSha256, ""
+	dp.useDelta = false
+	dp.keepPartial = false
+}
+
 func (dp *downloadProcess) checkDownloadInfo() error {
 	infoChecker := checker.GetAndChecker(checker.GetStringChoiceChecker("SoftwareName",
 		[]string{constants.MEFEdgeName}, true),
 		&checker.ModelChecker{
 			Field:    "DownloadInfo",
 			Required: true,
-			Checker: checker.GetAndChecker(
-				checker.GetHttpsUrlChecker("Package", true, true),
-				checker.GetRegChecker("UserName", "^[a-zA-Z0-9]{6,32}$", true),
-				checker.GetListChecker("Password",
-					checker.GetUintChecker("", 0, math.MaxUint8, true),
-					minPwdLength,
-					maxPwdLength,
-					true,
+			Checker: checker.GetOrChecker(
+				newArtifactChecker(),
+				checker.GetAndChecker(
+					checker.GetHttpsUrlChecker("Package", true, true),
+					checker.GetRegChecker("UserName", "^[a-zA-Z0-9]{6,32}$", true),
+					checker.GetListChecker("Password",
+						checker.GetUintChecker("", 0, math.MaxUint8, true),
+						minPwdLength,
+						maxPwdLength,
+						true,
+					),
 				),
 			),
 		})
 	if ret := infoChecker.Check(dp.sfwDownloadInfo); !ret.Result {
 		return fmt.Errorf("check software download para failed: %s", ret.Reason)
 	}
+	dp.useDeltaPackage()
 	dp.progress = progressPreparing
 	return nil
 }
 
+// newArtifactChecker the site cache and the delta artifact are optional for downloading the artifact hosted by center
+func newArtifactChecker() *checker.AndChecker {
+	return checker.GetAndChecker(
+		checker.GetRegChecker("ArtifactId", constants.ArtifactIdReg, true),
+		checker.GetRegChecker("Sha256", sitecache.Sha256Reg, true),
+		checker.GetOrChecker(
+			checker.GetAndChecker(
+				checker.GetRegChecker("DeltaArtifactId", "^$", true),
+				checker.GetRegChecker("DeltaSha256", "^$", true),
+			),
+			checker.GetAndChecker(
+				checker.GetRegChecker("DeltaArtifactId", constants.ArtifactIdReg, true),
+				checker.GetRegChecker("DeltaSha256", sitecache.Sha256Reg, true),
+			),
+		),
+		checker.GetOrChecker(
+			checker.GetAndChecker(
+				checker.GetRegChecker("SiteCacheIp", "^$", true),
+				checker.GetIntChecker("SiteCachePort", 0, 0, true),
+				checker.GetRegChecker("SiteCacheCertSn", "^$", true),
+			),
+			checker.GetAndChecker(
+				checker.GetIpV4Checker("SiteCacheIp", true),
+				checker.GetIntChecker("SiteCachePort", config.MinSiteCachePort, config.MaxSiteCachePort, true),
+				checker.GetRegChecker("SiteCacheCertSn", certSnReg, true),
+			),
+		),
+	)
+}
+
 func (dp *downloadProcess) cleanDownloadDir() {
 	req := config.DirReq{
 		Path:     constants.EdgeDownloadPath,
@@ -118,10 +216,16 @@ func (dp *downloadProcess) cleanDownloadDir() {
 }
 
 func (dp *downloadProcess) prepareDownloadDir() error {
+	filePath, err := getTargetFilePath(dp.sfwDownloadInfo.SoftwareName, downloadPackageType)
+	if err != nil {
+		return err
+	}
+	// keep the partial package and its checkpoint of last download for resuming
 	req := config.DirReq{
-		Path: constants.EdgeDownloadPath,
+		Path:      constants.EdgeDownloadPath,
+		KeepFiles: []string{filepath.Base(filePath), filepath.Base(resumable.GetCheckpointPath(filePath))},
 	}
-	if err := sendDirReq(req); err != nil {
+	if err = sendDirReq(req); err != nil {
 		return fmt.Errorf("prepare download dir failed, %v", err)
 	}
 	return nil
@@ -169,6 +273,16 @@ func (dp *downloadProcess) checkDownloadDir() error {
 }
 
 func (dp *downloadProcess) getSoftwareCert() error {
+	// the artifacts hosted by center are downloaded over mutual tls with the certs of edge hub
+	if dp.sfwDownloadInfo.DownloadInfo.ArtifactId != "" {
+		tlsCertInfo, err := cloudcert.GetEdgeHubCertInfo()
+		if err != nil {
+			return fmt.Errorf("get tls cert info of center failed: %v", err)
+		}
+		dp.centerTls = tlsCertInfo
+		dp.progress = progressDownloading
+		return nil
+	}
 	req := config.CertReq{
 		CertName: constants.SoftwareCertName,
 	}
@@ -221,6 +335,9 @@ func (dp *downloadProcess) verifyAndUnpack() error {
 		return fmt.Errorf("get resp content failed: %v", err)
 	}
 
+	if dp.useDelta && strings.Contains(respContent, constants.DeltaBaseMismatch) {
+		return errDeltaBaseMismatch
+	}
 	if respContent != "OK" {
 		return fmt.Errorf("verify and unpack downloaded files failed, edge-om process error: %s", respContent)
 	}
@@ -232,6 +349,7 @@ func reportDownloadProcess(progress uint64, err error) {
 	hwlog.RunLog.Info("report software download progress to cloud")
 	info := config.ProgressInfo{
 		Progress: progress,
+		Stage:    progressStage(progress),
 	}
 	if err != nil {
 		info.Res = constants.Failed
@@ -239,7 +357,56 @@ func reportDownloadProcess(progress uint64, err error) {
 	} else {
 		info.Res = constants.Success
 	}
+	sendProgressInfo(info)
+}
+
+func progressStage(progress uint64) string {
+	switch {
+	case progress >= progressSuccess:
+		return stageDownloaded
+	case progress >= progressVerifying:
+		return stageVerifying
+	case progress >= progressDownloading:
+		return stageDownloading
+	default:
+		return stagePreparing
+	}
+}
+
+// bytesReporter reports the downloaded size and the rate periodically while downloading, the rate is measured from
+// the first write so that the resumed part is not counted
+type bytesReporter struct {
+	lastTime  time.Time
+	lastBytes int64
+}
+
+func newBytesReporter() *bytesReporter {
+	return &bytesReporter{}
+}
+
+func (r *bytesReporter) onWrite(downloaded int64) error {
+	now := time.Now()
+	// the downloaded size restarts from the checkpoint when the download is retried
+	if r.lastTime.IsZero() || downloaded < r.lastBytes {
+		r.lastTime, r.lastBytes = now, downloaded
+		return nil
+	}
+	elapsed := now.Sub(r.lastTime)
+	if elapsed < bytesReportInterval {
+		return nil
+	}
+	sendProgressInfo(config.ProgressInfo{
+		Progress:        progressDownloading,
+		Res:             constants.Success,
+		Stage:           stageDownloading,
+		DownloadedBytes: downloaded,
+		Rate:            int64(float64(downloaded-r.lastBytes) / elapsed.Seconds()),
+	})
+	r.lastTime, r.lastBytes = now, downloaded
+	return nil
+}
 
+func sendProgressInfo(info config.ProgressInfo) {
 	var edgeReport = edgeReportUpgradeResInfo{
 		SerialNumber: configpara.GetInstallerConfig().SerialNumber,
 		ProgressInfo: info,
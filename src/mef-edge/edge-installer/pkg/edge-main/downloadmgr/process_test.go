@@ -17,7 +17,9 @@ import (
 	"fmt"
 	"net"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/agiledragon/gomonkey/v2"
 	"github.com/smartystreets/assertions"
@@ -28,9 +30,13 @@ import (
 	"huawei.com/mindx/common/modulemgr"
 	"huawei.com/mindx/common/modulemgr/model"
 
+	"edge-installer/pkg/common/config"
+	"edge-installer/pkg/common/constants"
 	"edge-installer/pkg/common/util"
 )
 
+const sha256HexLen = 64
+
 var downloadInfoJson = `{"softwareName":"MEFEdge","downloadInfo":{` +
 	`"package":"GET https://xxx.xxx/xxx/?contentType=MEFEdge\u0026version=1.0` +
 	`\u0026fileName=Ascend-mindxedge-mefedgesdk_5.0.RC3_linux-aarch64.tar.gz",` +
@@ -41,6 +47,7 @@ func TestProcessDownloadSoftware(t *testing.T) {
 	defer p.Reset()
 	convey.Convey("process method success case", t, testProcessDownloadSfwWith)
 	convey.Convey("param decode error case", t, testProcessDownloadSfwWithErrorMsg)
+	convey.Convey("delta package fallback case", t, testProcessDownloadDeltaSfw)
 }
 
 func testProcessDownloadSfwWith() {
@@ -83,6 +90,45 @@ func testProcessDownloadSfwWithErrorMsg() {
 	convey.So(fmt.Sprintf("%v", processErr), convey.ShouldContainSubstring, "get download process failed")
 }
 
+func testProcessDownloadDeltaSfw() {
+	const (
+		fullArtifactId  = "0123456789abcdef0123456789abcdef"
+		deltaArtifactId = "fedcba9876543210fedcba9876543210"
+	)
+	respMismatch, respOk := &model.Message{}, &model.Message{}
+	convey.So(respMismatch.FillContent(constants.DeltaBaseMismatch+": installed version is 5.0.RC0"),
+		convey.ShouldBeNil)
+	convey.So(respOk.FillContent("OK"), convey.ShouldBeNil)
+	var downloadedIds []string
+	fakeDp := downloadProcess{}
+	patches := gomonkey.ApplyFuncSeq(modulemgr.SendSyncMessage, []gomonkey.OutputCell{
+		{Values: gomonkey.Params{respMismatch, nil}, Times: 1},
+		{Values: gomonkey.Params{respOk, nil}, Times: 1},
+	}).
+		ApplyPrivateMethod(&fakeDp, "getSoftwareCert", func() error { return nil }).
+		ApplyPrivateMethod(&fakeDp, "prepareDownloadDir", func() error { return nil }).
+		ApplyPrivateMethod(&fakeDp, "checkDownloadDir", func() error { return nil }).
+		ApplyPrivateMethod(&fakeDp, "downloadSoftware", func(dp *downloadProcess) error {
+			downloadedIds = append(downloadedIds, dp.sfwDownloadInfo.DownloadInfo.ArtifactId)
+			return nil
+		}).
+		ApplyFuncReturn(modulemgr.SendMessage, nil)
+	defer patches.Reset()
+	msg := model.Message{}
+	convey.So(msg.FillContent(util.SoftwareDownloadInfo{
+		SoftwareName: constants.MEFEdgeName,
+		DownloadInfo: util.DownloadInfo{
+			ArtifactId:      fullArtifactId,
+			Sha256:          strings.Repeat("a", sha256HexLen),
+			DeltaArtifactId: deltaArtifactId,
+			DeltaSha256:     strings.Repeat("b", sha256HexLen),
+		},
+	}), convey.ShouldBeNil)
+	d := downloadMgr{}
+	convey.So(d.processDownloadSoftware(msg), convey.ShouldBeNil)
+	convey.So(downloadedIds, convey.ShouldResemble, []string{deltaArtifactId, fullArtifactId})
+}
+
 func TestCheckDownloadInfo(t *testing.T) {
 	convey.Convey("check download info case", t, func() {
 		dp := downloadProcess{}
@@ -92,6 +138,47 @@ func TestCheckDownloadInfo(t *testing.T) {
 		processErr := dp.checkDownloadInfo()
 		convey.So(processErr, convey.ShouldBeNil)
 	})
+
+	convey.Convey("check artifact download info case", t, func() {
+		dp := downloadProcess{}
+		dp.sfwDownloadInfo.SoftwareName = constants.MEFEdgeName
+		dp.sfwDownloadInfo.DownloadInfo = util.DownloadInfo{
+			ArtifactId: "0123456789abcdef0123456789abcdef",
+			Sha256:     strings.Repeat("a", sha256HexLen),
+		}
+		convey.So(dp.checkDownloadInfo(), convey.ShouldBeNil)
+
+		dp.sfwDownloadInfo.DownloadInfo.SiteCacheIp = "192.168.1.10"
+		dp.sfwDownloadInfo.DownloadInfo.SiteCachePort = 8443
+		convey.So(dp.checkDownloadInfo(), convey.ShouldNotBeNil)
+
+		// the site cache is pinned by the serial number of its cert
+		dp.sfwDownloadInfo.DownloadInfo.SiteCacheCertSn = "1A2B"
+		convey.So(dp.checkDownloadInfo(), convey.ShouldBeNil)
+
+		dp.sfwDownloadInfo.DownloadInfo.SiteCachePort = 0
+		convey.So(dp.checkDownloadInfo(), convey.ShouldNotBeNil)
+
+		dp.sfwDownloadInfo.DownloadInfo.SiteCacheIp = ""
+		dp.sfwDownloadInfo.DownloadInfo.Sha256 = ""
+		convey.So(dp.checkDownloadInfo(), convey.ShouldNotBeNil)
+	})
+
+	convey.Convey("check delta artifact download info case", t, func() {
+		dp := downloadProcess{}
+		dp.sfwDownloadInfo.SoftwareName = constants.MEFEdgeName
+		dp.sfwDownloadInfo.DownloadInfo = util.DownloadInfo{
+			ArtifactId:      "0123456789abcdef0123456789abcdef",
+			Sha256:          strings.Repeat("a", sha256HexLen),
+			DeltaArtifactId: "fedcba9876543210fedcba9876543210",
+		}
+		convey.So(dp.checkDownloadInfo(), convey.ShouldNotBeNil)
+
+		dp.sfwDownloadInfo.DownloadInfo.DeltaSha256 = strings.Repeat("b", sha256HexLen)
+		convey.So(dp.checkDownloadInfo(), convey.ShouldBeNil)
+		convey.So(dp.useDelta, convey.ShouldBeTrue)
+		convey.So(dp.sfwDownloadInfo.DownloadInfo.Sha256, convey.ShouldEqual, strings.Repeat("b", sha256HexLen))
+	})
 }
 
 func TestPrepareDownloadDir(t *testing.T) {
@@ -191,3 +278,31 @@ func TestVerifyAndUnpack(t *testing.T) {
 		p3.Reset()
 	})
 }
+
+func TestBytesReporter(t *testing.T) {
+	convey.Convey("downloaded size and rate should be reported periodically", t, func() {
+		var reports []config.ProgressInfo
+		p := gomonkey.ApplyFunc(sendProgressInfo, func(info config.ProgressInfo) {
+			reports = append(reports, info)
+		})
+		defer p.Reset()
+		const resumedBytes, downloadedBytes = 1024, 11264
+		reporter := newBytesReporter()
+		convey.So(reporter.onWrite(resumedBytes), convey.ShouldBeNil)
+		convey.So(reporter.onWrite(resumedBytes+1), convey.ShouldBeNil)
+		convey.So(len(reports), convey.ShouldEqual, 0)
+
+		reporter.lastTime = time.Now().Add(-2 * bytesReportInterval)
+		convey.So(reporter.onWrite(downloadedBytes), convey.ShouldBeNil)
+		convey.So(len(reports), convey.ShouldEqual, 1)
+		convey.So(reports[0].Stage, convey.ShouldEqual, stageDownloading)
+		convey.So(reports[0].DownloadedBytes, convey.ShouldEqual, downloadedBytes)
+		convey.So(reports[0].Rate, convey.ShouldBeBetween, 0, downloadedBytes-resumedBytes)
+	})
+
+	convey.Convey("stage should be derived from progress", t, func() {
+		convey.So(progressStage(progressPreparing), convey.ShouldEqual, stagePreparing)
+		convey.So(progressStage(progressVerifying), convey.ShouldEqual, stageVerifying)
+		convey.So(progressStage(progressSuccess), convey.ShouldEqual, stageDownloaded)
+	})
+}
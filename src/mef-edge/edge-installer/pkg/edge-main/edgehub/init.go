@@ -103,8 +103,8 @@ func (m *edgeHub) Start() {
 			hwlog.RunLog.Errorf("edge hub get receive module message failed, error: %v", err)
 			continue
 		}
-		hwlog.RunLog.Infof("[routeToCenter], route: %+v, {ID: %s, parentID: %s}", receivedMsg.Router,
-			receivedMsg.Header.Id, receivedMsg.Header.ParentId)
+		hwlog.RunLog.InfofWithCtx(receivedMsg.TraceContext(), "[routeToCenter], route: %+v, {ID: %s, parentID: %s}",
+			receivedMsg.Router, receivedMsg.Header.Id, receivedMsg.Header.ParentId)
 
 		if err = m.dispatch(receivedMsg, cancel); err != nil {
 			hwlog.RunLog.Errorf("edgehub send message [header: %+v, router: %+v] to mef-center failed, error: %v",
@@ -13,11 +13,14 @@
 package edgehub
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"huawei.com/mindx/common/fileutils"
 	"huawei.com/mindx/common/httpsmgr"
@@ -30,6 +33,7 @@ import (
 	"edge-installer/pkg/common/certmgr"
 	"edge-installer/pkg/common/config"
 	"edge-installer/pkg/common/constants"
+	"edge-installer/pkg/common/path"
 	"edge-installer/pkg/common/util"
 	"edge-installer/pkg/edge-main/common/cloudcert"
 	"edge-installer/pkg/edge-main/common/configpara"
@@ -58,23 +62,64 @@ func initClient(netConfig *config.NetManager) error {
 		hwlog.RunLog.Errorf("init proxy config failed, error: %v", err)
 		return errors.New("init proxy config failed")
 	}
+	if err = proxyConfig.SetServerHosts(netConfig.GetCenterAddrs(), netConfig.Port); err != nil {
+		hwlog.RunLog.Errorf("init center addresses failed, error: %v", err)
+		return errors.New("init center addresses failed")
+	}
+	proxyConfig.SetHttpProxy(configpara.GetProxyFunc())
 	proxyConfig.RegModInfos(getRegModuleInfoList())
+	if err = proxyConfig.SetProtocolInfo(constants.ProtocolVersion, getCapabilities); err != nil {
+		hwlog.RunLog.Errorf("init protocol info failed, error: %v", err)
+		return fmt.Errorf("init protocol info failed, error: %v", err)
+	}
 	if err := proxyConfig.SetBandwidthLimiterCfg(constants.MaxMsgThroughput, constants.MsgThroughputPeriod); err != nil {
 		hwlog.RunLog.Errorf("init tps limiter config failed, error: %v", err)
 		return fmt.Errorf("init tps limiter config failed, error: %v", err)
 	}
+	compressCfg := loadLinkCompressionConfig()
+	if err := proxyConfig.SetCompressionCfg(compressCfg.Threshold, compressCfg.Level); err != nil {
+		hwlog.RunLog.Errorf("init compression config failed, error: %v", err)
+		return fmt.Errorf("init compression config failed, error: %v", err)
+	}
 	proxy = &websocketmgr.WsClientProxy{
 		ProxyCfg: proxyConfig,
 	}
-	proxy.SetReConnCallback(reportSoftwareVersion)
+	proxy.SetReConnCallback(recordActiveCenter, reportSoftwareVersion)
+	config.GetCapabilityCache().SetChangeHook(notifyCapsChanged)
 	if err = proxy.Start(); err != nil {
 		hwlog.RunLog.Errorf("init edgehub client failed: %v", err)
 		return errors.New("init edgehub client failed")
 	}
+	recordActiveCenter()
 
 	return nil
 }
 
+// loadLinkCompressionConfig load the compression config of the connection to MEF Center, the default config is used
+// if it is not set or invalid
+func loadLinkCompressionConfig() config.LinkCompressionConfig {
+	configPathMgr, err := path.GetConfigPathMgr()
+	if err != nil {
+		hwlog.RunLog.Warnf("get config path manager failed, use default link compression config: %v", err)
+		return config.DefaultLinkCompressionConfig()
+	}
+	cfgPath := configPathMgr.GetLinkCompressionPath()
+	if !fileutils.IsExist(cfgPath) {
+		return config.DefaultLinkCompressionConfig()
+	}
+	cfg, err := config.LoadLinkCompressionConfig(cfgPath)
+	if err != nil {
+		hwlog.RunLog.Warnf("load link compression config failed, use default config: %v", err)
+		return config.DefaultLinkCompressionConfig()
+	}
+	if err = cfg.Check(); err != nil {
+		hwlog.RunLog.Warnf("link compression config is invalid, use default config: %v", err)
+		return config.DefaultLinkCompressionConfig()
+	}
+	hwlog.RunLog.Infof("link compression threshold: %d bytes, level: %d", cfg.Threshold, cfg.Level)
+	return *cfg
+}
+
 func getCltSender() (*websocketmgr.WsClientProxy, error) {
 	if proxy != nil && !proxy.IsConnected() {
 		return nil, errors.New("edgehub ws client proxy is nil or client is not connected")
@@ -98,16 +143,20 @@ func checkAndGetCert(netCfg *config.NetManager, certInfo *certutils.TlsCertInfo)
 		}
 		hwlog.RunLog.Info("delete invalid edgehub cert success")
 	}
-	if err := getCertFromCenter(netCfg, certInfo); err != nil {
-		hwlog.RunLog.Error(err)
-		return fmt.Errorf("get cert from center error: %w", err)
+	var err error
+	for _, addr := range netCfg.GetCenterAddrs() {
+		if err = getCertFromCenter(addr, netCfg, certInfo); err == nil {
+			return nil
+		}
+		hwlog.RunLog.Errorf("get cert from center [%s] failed: %v", addr, err)
 	}
-	return nil
+	return fmt.Errorf("get cert from center error: %w", err)
 }
 
-func getCertFromCenter(netCfg *config.NetManager, certInfo *certutils.TlsCertInfo) error {
-	hwlog.RunLog.Info("start to auth from center")
-	url := fmt.Sprintf("https://%s:%d%s", netCfg.IP, netCfg.AuthPort, constants.MefCenterTokenUrl)
+func getCertFromCenter(addr string, netCfg *config.NetManager, certInfo *certutils.TlsCertInfo) error {
+	hwlog.RunLog.Infof("start to auth from center [%s]", addr)
+	url := fmt.Sprintf("https://%s%s", net.JoinHostPort(addr, strconv.Itoa(netCfg.AuthPort)),
+		constants.MefCenterTokenUrl)
 	tlsCfg := certutils.TlsCertInfo{
 		RootCaPath: certInfo.RootCaPath,
 		RootCaOnly: true,
@@ -123,7 +172,7 @@ func getCertFromCenter(netCfg *config.NetManager, certInfo *certutils.TlsCertInf
 	if err != nil {
 		return err
 	}
-	resp, err := httpsmgr.GetHttpsReq(url, tlsCfg, reqHeaders).PostJson(csrData)
+	resp, err := httpsmgr.GetHttpsReq(url, tlsCfg, reqHeaders).SetProxy(configpara.GetProxyFunc()).PostJson(csrData)
 	if err != nil {
 		if strings.Contains(err.Error(), strconv.Itoa(http.StatusUnauthorized)) {
 			return fmt.Errorf("%w: token is incorrect", errCloudhubAuth)
@@ -154,14 +203,42 @@ func checkEdgeCertValid(netCfg *config.NetManager, certInfo *certutils.TlsCertIn
 		return errors.New("edge cert or key file not exists")
 	}
 	hwlog.RunLog.Info("start to check connection and certs with edge manager")
-	serverAddr := fmt.Sprintf("https://%s:%d", netCfg.IP, netCfg.Port)
+	for _, addr := range netCfg.GetCenterAddrs() {
+		serverAddr := fmt.Sprintf("https://%s", net.JoinHostPort(addr, strconv.Itoa(netCfg.Port)))
+		if _, err := httpsmgr.GetHttpsReq(serverAddr, *certInfo).SetProxy(configpara.GetProxyFunc()).Get(nil); err != nil {
+			hwlog.RunLog.Errorf("establish tls connection with mef center [%s] error: %v", addr, err)
+			continue
+		}
+		hwlog.RunLog.Info("check connection and certs with edge manager success")
+		return nil
+	}
+	return errors.New("establish tls connection with mef center failed, please check net or cert")
+}
 
-	if _, err := httpsmgr.GetHttpsReq(serverAddr, *certInfo).Get(nil); err != nil {
-		hwlog.RunLog.Errorf("establish tls connection with mef center error: %v", err)
-		return errors.New("establish tls connection with mef center failed, please check net or cert")
+// recordActiveCenter records the center address connected, so that other modules follow the failover and
+// edgectl shows it
+func recordActiveCenter() {
+	host, _, err := net.SplitHostPort(proxy.GetActiveHost())
+	if err != nil {
+		hwlog.RunLog.Errorf("get active center address failed: %v", err)
+		return
+	}
+	configpara.SetActiveCenter(host)
+	hwlog.RunLog.Infof("edgehub is connected to center [%s]", host)
+
+	configPathMgr, err := path.GetConfigPathMgr()
+	if err != nil {
+		hwlog.RunLog.Errorf("get config path manager failed, error: %v", err)
+		return
+	}
+	data, err := json.Marshal(config.ActiveCenter{Addr: host, ConnectedAt: time.Now().Format(time.RFC3339)})
+	if err != nil {
+		hwlog.RunLog.Errorf("marshal active center failed: %v", err)
+		return
+	}
+	if err = fileutils.WriteData(configPathMgr.GetActiveCenterPath(), data); err != nil {
+		hwlog.RunLog.Errorf("save active center failed: %v", err)
 	}
-	hwlog.RunLog.Info("check connection and certs with edge manager success")
-	return nil
 }
 
 func reportSoftwareVersion() {
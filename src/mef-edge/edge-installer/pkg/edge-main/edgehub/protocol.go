@@ -0,0 +1,83 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+//go:build MEFEdge_SDK
+
+// Package edgehub this file for the protocol version and capabilities negotiated with center
+package edgehub
+
+import (
+	"sync"
+	"time"
+
+	"huawei.com/mindx/common/hwlog"
+
+	"edge-installer/pkg/common/config"
+	"edge-installer/pkg/common/constants"
+	"edge-installer/pkg/common/util"
+)
+
+// protocolCaps capabilities of the messages from center handled by edge, they are declared together with the
+// capabilities of device
+var protocolCaps = []string{
+	constants.CapabilityCertSync,
+	constants.CapabilityEdgeCaUpdate,
+	constants.CapabilityEdgeSvcCertUpdate,
+	constants.CapabilityTraceLog,
+	constants.CapabilityUpgradePreflight,
+	constants.CapabilityOutboxAck,
+}
+
+type capabilityReport struct {
+	Capabilities []string `json:"capabilities"`
+}
+
+var (
+	capsReportLock  sync.Mutex
+	capsReportTimer *time.Timer
+)
+
+// getCapabilities is called on each connect, so that center gets the capabilities at the moment
+func getCapabilities() []string {
+	return append(append([]string{}, protocolCaps...), config.GetCapabilityCache().GetCaps()...)
+}
+
+// notifyCapsChanged reports capabilities to center after a short delay, the changes in the delay are merged
+func notifyCapsChanged() {
+	capsReportLock.Lock()
+	defer capsReportLock.Unlock()
+	if capsReportTimer != nil {
+		capsReportTimer.Stop()
+	}
+	capsReportTimer = time.AfterFunc(constants.CapabilityReportDelay, reportCapabilities)
+}
+
+func reportCapabilities() {
+	if proxy == nil || !proxy.IsConnected() {
+		// the capabilities are sent to center on connect
+		hwlog.RunLog.Info("edge hub is not connected, skip reporting capabilities")
+		return
+	}
+	msg, err := util.NewInnerMsgWithFullParas(util.InnerMsgParams{
+		Source:      constants.ModEdgeHub,
+		Destination: constants.ModEdgeHub,
+		Operation:   constants.OptReport,
+		Resource:    constants.ResEdgeCapability,
+		Content:     capabilityReport{Capabilities: getCapabilities()},
+	})
+	if err != nil {
+		hwlog.RunLog.Errorf("report capabilities failed, create message error: %v", err)
+		return
+	}
+	if err = sendMsgToServer(msg); err != nil {
+		hwlog.RunLog.Errorf("report capabilities failed: %v", err)
+		return
+	}
+	hwlog.RunLog.Info("report capabilities to center success")
+}
@@ -22,9 +22,14 @@ var regInfoList = []*modulemgr.RegisterModuleInfo{
 	{MsgOpt: constants.OptPost, MsgRes: constants.ResDownloadCert, ModuleName: constants.ModEdgeOm},
 	{MsgOpt: constants.OptPost, MsgRes: constants.ResEdgeDownloadInfo, ModuleName: constants.DownloadManagerName},
 	{MsgOpt: constants.OptPost, MsgRes: constants.ResUpgradeInfo, ModuleName: constants.ModEdgeOm},
+	{MsgOpt: constants.OptPost, MsgRes: constants.ResRollbackInfo, ModuleName: constants.ModEdgeOm},
+	{MsgOpt: constants.OptPost, MsgRes: constants.ResPreflight, ModuleName: constants.ModEdgeOm},
 	{MsgOpt: constants.OptGet, MsgRes: constants.ResCertUpdate, ModuleName: constants.ModEdgeHub},
 	{MsgOpt: constants.OptDelete, MsgRes: constants.DeleteNodeMsg, ModuleName: constants.ModEdgeHub},
 	{MsgOpt: constants.OptPost, MsgRes: constants.ResDumpLogTask, ModuleName: constants.ModHandlerMgr},
+	{MsgOpt: constants.OptPost, MsgRes: constants.ResRemoteShell, ModuleName: constants.ModEdgeOm},
+	{MsgOpt: constants.OptPost, MsgRes: constants.ResPortForward, ModuleName: constants.ModEdgeOm},
+	{MsgOpt: constants.OptPost, MsgRes: constants.ResTraceLog, ModuleName: constants.ModEdgeOm},
 }
 
 func getRegModuleInfoList() []modulemgr.MessageHandlerIntf {
@@ -25,6 +25,14 @@ var staticResourceRouterListSdk = []modulemgr.RegisterModuleInfo{
 		MsgRes: constants.ResDownloadCert, ModuleName: constants.ModEdgeHub},
 	{Src: constants.ModEdgeOm, MsgOpt: constants.OptResponse,
 		MsgRes: constants.ResPackLogResponse, ModuleName: constants.ModHandlerMgr},
+	{Src: constants.ModEdgeOm, MsgOpt: constants.OptReport,
+		MsgRes: constants.ResRemoteShellOutput, ModuleName: constants.ModEdgeHub},
+	{Src: constants.ModEdgeOm, MsgOpt: constants.OptReport,
+		MsgRes: constants.ResPortForwardData, ModuleName: constants.ModEdgeHub},
+	{Src: constants.ModEdgeOm, MsgOpt: constants.OptReport,
+		MsgRes: constants.ResTraceLogData, ModuleName: constants.ModEdgeHub},
+	{Src: constants.ModEdgeOm, MsgOpt: constants.OptReport,
+		MsgRes: constants.ResPreflightResult, ModuleName: constants.ModEdgeHub},
 }
 
 func init() {
@@ -206,14 +206,14 @@ func (p *dumpLogProcess) uploadLogs() error {
 	}
 
 	hwlog.RunLog.Info("start to upload file")
-	url := fmt.Sprintf("https://%s:%d/logmgmt/dump/upload", p.netConfig.IP, p.netConfig.Port)
+	url := fmt.Sprintf("https://%s:%d/logmgmt/dump/upload", configpara.GetCenterAddr(), p.netConfig.Port)
 	headers := map[string]interface{}{
 		"Task-Id":         p.taskId,
 		"Package-Size":    localFileStat.Size(),
 		"Sha256-Checksum": fmt.Sprintf("%x", sha256Inst.Sum(nil)),
 	}
 	respBytes, err := httpsmgr.GetHttpsReq(url, *p.tlsCertInfo, headers).SetReadTimeout(uploadLogTimeout).
-		PostFile(localFilePath)
+		SetProxy(configpara.GetProxyFunc()).PostFile(localFilePath)
 	if err != nil {
 		return fmt.Errorf("failed to upload file, %v", err)
 	}
@@ -26,6 +26,7 @@ type FailStatus struct {
 	reason string
 }
 
+// start the partial file is kept in the partial dir for resuming, only the downloaded file of the task is removed
 func (t *FailStatus) start() error {
 	filePath := filepath.Join(constants.ModeFileDownloadDir, t.Task.Uuid, t.Task.ModelFile.Name)
 	if err := fileutils.DeleteAllFileWithConfusion(filePath); err != nil {
@@ -14,10 +14,13 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"huawei.com/mindx/common/fileutils"
@@ -28,9 +31,18 @@ import (
 
 	"edge-installer/pkg/common/constants"
 	"edge-installer/pkg/common/types"
+	"edge-installer/pkg/edge-main/common/configpara"
+	"edge-installer/pkg/edge-main/common/resumable"
 )
 
-const progressReportInterval = time.Second * 5
+const (
+	progressReportInterval = time.Second * 5
+	// partialFileExpiry the partial files not resumed for a long time are removed
+	partialFileExpiry = time.Hour * 24 * 7
+)
+
+// partialInUse the partial files being downloaded, the tasks of the same file at the same time do not share it
+var partialInUse sync.Map
 
 // UrlDownloader struct for download
 type UrlDownloader struct {
@@ -38,6 +50,7 @@ type UrlDownloader struct {
 	name           string
 	url            string
 	savePath       string
+	partialPath    string
 	checkCode      string
 	checkType      string
 	userName       string
@@ -50,17 +63,46 @@ type UrlDownloader struct {
 
 // NewUrlDownloader create a downloader
 func NewUrlDownloader(uuid, savePath string, size int, m types.ModelFile, ctx context.Context) UrlDownloader {
+	url := strings.Split(m.FileServer.Path, " ")[1]
 	return UrlDownloader{
-		uuid:      uuid,
-		name:      m.Name,
-		url:       strings.Split(m.FileServer.Path, " ")[1],
-		savePath:  savePath,
-		checkCode: m.CheckCode,
-		checkType: m.CheckType,
-		userName:  m.FileServer.UserName,
-		passWord:  m.FileServer.PassWord,
-		filesize:  size,
-		cancelCtx: ctx,
+		uuid:        uuid,
+		name:        m.Name,
+		url:         url,
+		savePath:    savePath,
+		partialPath: getPartialPath(url, m.CheckCode),
+		checkCode:   m.CheckCode,
+		checkType:   m.CheckType,
+		userName:    m.FileServer.UserName,
+		passWord:    m.FileServer.PassWord,
+		filesize:    size,
+		cancelCtx:   ctx,
+	}
+}
+
+// getPartialPath the partial file is identified by the content instead of the task, so that the download of the
+// same file is resumed by the next task after the task fails
+func getPartialPath(url, checkCode string) string {
+	sum := sha256.Sum256([]byte(url + "\n" + checkCode))
+	return filepath.Join(constants.ModeFilePartialDir, hex.EncodeToString(sum[:]))
+}
+
+func cleanExpiredPartialFiles() {
+	reader, dirEntries, err := fileutils.ReadDir(constants.ModeFilePartialDir)
+	if err != nil {
+		hwlog.RunLog.Warnf("read partial model file dir failed: %v", err)
+		return
+	}
+	defer fileutils.CloseFile(reader)
+	for _, dirEntry := range dirEntries {
+		info, err := dirEntry.Info()
+		if err != nil || time.Since(info.ModTime()) < partialFileExpiry {
+			continue
+		}
+		path := filepath.Join(constants.ModeFilePartialDir, dirEntry.Name())
+		hwlog.RunLog.Infof("delete expired partial model file [%s]", path)
+		if err = fileutils.DeleteAllFileWithConfusion(path); err != nil {
+			hwlog.RunLog.Warnf("delete expired partial model file [%s] failed: %v", path, err)
+		}
 	}
 }
 
@@ -71,21 +113,6 @@ func (u *UrlDownloader) setCa(ca []byte) {
 func (u *UrlDownloader) download() {
 	defer utils.ClearSliceByteMemory(u.ca)
 	defer utils.ClearStringMemory(u.passWord)
-	fileWriter, err := os.OpenFile(u.savePath, os.O_RDWR|os.O_CREATE, utils.FileMode)
-	if err != nil {
-		downFinishEvent := NewDownloadFinishEvent(u.uuid, u.name,
-			"cannot create file when download", false)
-		GetModelMgr().Notify(downFinishEvent)
-		return
-	}
-	defer func() {
-		err = fileWriter.Close()
-		if err != nil {
-			hwlog.RunLog.Error("close file error")
-			return
-		}
-	}()
-	pWriter := &ProgressWriter{writer: fileWriter, downloader: u, lastReportTime: time.Now()}
 	tlsCfg := certutils.TlsCertInfo{
 		RootCaContent: u.ca,
 		RootCaOnly:    true,
@@ -100,54 +127,75 @@ func (u *UrlDownloader) download() {
 	headers := make(map[string]interface{})
 	headers["Authorization"] = authStr
 	defer delete(headers, "Authorization")
-	err = httpsmgr.GetHttpsReq(u.url, tlsCfg, headers).
-		SetReadTimeout(time.Hour).GetRespToFileWithLimit(pWriter, constants.ModelFileMaxSize)
-	if err != nil {
-		hwlog.RunLog.Error("download from url failed")
+	newReq := func() *httpsmgr.HttpsRequest {
+		return httpsmgr.GetHttpsReq(u.url, tlsCfg, headers).SetProxy(configpara.GetProxyFunc()).
+			SetReadTimeout(time.Hour)
+	}
+	if err := u.preparePartialPath(); err != nil {
+		hwlog.RunLog.Errorf("prepare partial model file failed: %v", err)
+		downFinishEvent := NewDownloadFinishEvent(u.uuid, u.name, "prepare partial model file failed", false)
+		GetModelMgr().Notify(downFinishEvent)
+		return
+	}
+	defer partialInUse.Delete(u.partialPath)
+	task := resumable.NewTask(u.cancelCtx, u.url, u.partialPath, constants.ModelFileMaxSize, newReq)
+	pWriter := &progressReporter{downloader: u, lastReportTime: time.Now()}
+	task.OnWrite = pWriter.onWrite
+	// the partial file and its checkpoint are kept for resuming by the next task
+	if err := task.Run(); err != nil {
+		hwlog.RunLog.Errorf("download from url failed: %v", err)
 		downFinishEvent := NewDownloadFinishEvent(u.uuid, u.name, "download from url failed", false)
 		GetModelMgr().Notify(downFinishEvent)
 		return
 	}
-	if !u.checkFileValid(u.savePath, u.checkCode, u.checkType, u.filesize) {
+	if !u.checkFileValid(u.partialPath, u.checkCode, u.checkType, u.filesize) {
+		if err := fileutils.DeleteAllFileWithConfusion(u.partialPath); err != nil {
+			hwlog.RunLog.Warnf("remove invalid partial model file failed: %v", err)
+		}
 		downFinishEvent := NewDownloadFinishEvent(u.uuid, u.name, "check download file valid fail", false)
 		GetModelMgr().Notify(downFinishEvent)
 		return
 	}
+	if err := fileutils.RenameFile(u.partialPath, u.savePath); err != nil {
+		hwlog.RunLog.Errorf("move downloaded model file failed: %v", err)
+		downFinishEvent := NewDownloadFinishEvent(u.uuid, u.name, "move downloaded model file failed", false)
+		GetModelMgr().Notify(downFinishEvent)
+		return
+	}
 	downFinishEvent := NewDownloadFinishEvent(u.uuid, u.name, "", true)
 	GetModelMgr().Notify(downFinishEvent)
 	return
 }
 
-func (u *UrlDownloader) updateDownloadedSize(down int) {
-	u.downloadedSize = down
+func (u *UrlDownloader) preparePartialPath() error {
+	if err := fileutils.CreateDir(constants.ModeFilePartialDir, fileutils.Mode700); err != nil {
+		return fmt.Errorf("create dir %s failed: %v", constants.ModeFilePartialDir, err)
+	}
+	cleanExpiredPartialFiles()
+	if _, loaded := partialInUse.LoadOrStore(u.partialPath, struct{}{}); loaded {
+		hwlog.RunLog.Warn("the same model file is being downloaded by another task, download it separately")
+		u.partialPath = fmt.Sprintf("%s.%s", u.partialPath, u.uuid)
+		partialInUse.Store(u.partialPath, struct{}{})
+	}
+	return nil
 }
 
-func (u *UrlDownloader) isTaskCanceled() bool {
-	select {
-	case <-u.cancelCtx.Done():
-		return true
-	default:
-		return false
-	}
+func (u *UrlDownloader) updateDownloadedSize(down int) {
+	u.downloadedSize = down
 }
 
-// ProgressWriter a writer which can report progress
-type ProgressWriter struct {
-	writer         io.Writer
+// progressReporter reports the progress of the download, the downloaded size includes the resumed part
+type progressReporter struct {
 	downloader     *UrlDownloader
 	lastReportTime time.Time
 }
 
-func (l *ProgressWriter) Write(p []byte) (int, error) {
-	if l.downloader.isTaskCanceled() {
-		return 0, fmt.Errorf("task canceled by user")
-	}
-	n, err := l.writer.Write(p)
-	l.downloader.updateDownloadedSize(l.downloader.downloadedSize + n)
+func (l *progressReporter) onWrite(downloaded int64) error {
+	l.downloader.updateDownloadedSize(int(downloaded))
 	if l.downloader.downloadedSize > l.downloader.filesize {
 		hwlog.RunLog.Errorf("downloaded file size %d, exceed wanted file size: %d",
 			l.downloader.downloadedSize, l.downloader.filesize)
-		return n, fmt.Errorf("downloaded file size %d, exceed wanted file size: %d",
+		return fmt.Errorf("downloaded file size %d, exceed wanted file size: %d",
 			l.downloader.downloadedSize, l.downloader.filesize)
 	}
 	now := time.Now()
@@ -156,7 +204,7 @@ func (l *ProgressWriter) Write(p []byte) (int, error) {
 		progressEvent := NewProgressEvent(l.downloader.uuid, l.downloader.name, l.downloader.downloadedSize)
 		GetModelMgr().Notify(progressEvent)
 	}
-	return n, err
+	return nil
 }
 
 func (u *UrlDownloader) checkFileValid(filepath, checkCode, checkType string, filesize int) bool {
@@ -109,7 +109,7 @@ func (p *Proxy) DispatchMessage(message *model.Message) error {
 		return p.DispatchFunc(message)
 	}
 	if err := handler.dispatchMessage(message, p.DispatchFunc); err != nil {
-		hwlog.RunLog.Errorf("route: %+v, error: %v", message.KubeEdgeRouter, err)
+		hwlog.RunLog.ErrorfWithCtx(message.TraceContext(), "route: %+v, error: %v", message.KubeEdgeRouter, err)
 		return err
 	}
 	return nil
@@ -157,6 +157,8 @@ func (h *messageForwardingHandler) tryForward(event Event, message *model.Messag
 		if err != nil {
 			return err
 		}
+		hwlog.RunLog.DebugfWithCtx(forwardedMessage.TraceContext(), "forward message [%s] to [%s]",
+			message.KubeEdgeRouter.Resource, rule.Destination)
 		resp, err := modulemgr.SendSyncMessage(forwardedMessage, syncMessageTimeout)
 		if err != nil {
 			return err
@@ -181,6 +183,7 @@ func (h *messageForwardingHandler) createForwardedMessage(
 		return nil, err
 	}
 	forwardedMessage.SetIsSync(true)
+	forwardedMessage.SetTraceId(message.GetTraceId())
 
 	forwardedMessage.Content = make([]byte, len(message.Content))
 	copy(forwardedMessage.Content, message.Content)
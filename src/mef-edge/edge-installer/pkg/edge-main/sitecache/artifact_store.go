@@ -0,0 +1,233 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+//go:build MEFEdge_SDK
+
+package sitecache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"huawei.com/mindx/common/fileutils"
+	"huawei.com/mindx/common/httpsmgr"
+	"huawei.com/mindx/common/hwlog"
+
+	"edge-installer/pkg/common/constants"
+	"edge-installer/pkg/edge-main/common/cloudcert"
+	"edge-installer/pkg/edge-main/common/configpara"
+	"edge-installer/pkg/edge-main/common/resumable"
+)
+
+const (
+	partSuffix     = ".part"
+	checksumSuffix = ".sha256"
+	checksumSize   = 64
+	// headerSha256Checksum center responds the checksum of its artifact record with the artifact
+	headerSha256Checksum = "Sha256-Checksum"
+	maxFetchingArtifacts = 4
+	// fetchRetryInterval the artifact failed to be fetched is not fetched again in the interval, the peers
+	// download it from center instead
+	fetchRetryInterval = 5 * time.Minute
+)
+
+var (
+	artifactIdPattern = regexp.MustCompile(constants.ArtifactIdReg)
+	sha256Pattern     = regexp.MustCompile(Sha256Reg)
+	// errFetching the artifact is being fetched from center in background, the peer should retry later
+	errFetching = errors.New("artifact is being fetched from center")
+)
+
+// artifactStore the artifacts cached in dir, each artifact is saved as a file named by its id with a checksum file.
+// The artifact is fetched from center in background when it is requested by a peer and not cached, and it is
+// checked by the checksum of the artifact record of center
+type artifactStore struct {
+	ctx      context.Context
+	dir      string
+	capacity int64
+	lock     sync.Mutex
+	fetching map[string]struct{}
+	failed   map[string]time.Time
+}
+
+type cachedArtifact struct {
+	id      string
+	size    int64
+	modTime time.Time
+}
+
+func newArtifactStore(ctx context.Context, dir string, capacity int64) *artifactStore {
+	return &artifactStore{
+		ctx:      ctx,
+		dir:      dir,
+		capacity: capacity,
+		fetching: make(map[string]struct{}),
+		failed:   make(map[string]time.Time),
+	}
+}
+
+func (as *artifactStore) getPath(id string) string {
+	return filepath.Join(as.dir, id)
+}
+
+// open opens the cached artifact. errFetching is returned if it is not cached, and it is fetched in background
+// so the peer is not held until the fetching finishes
+func (as *artifactStore) open(id string) (*os.File, error) {
+	as.lock.Lock()
+	defer as.lock.Unlock()
+	if _, fetching := as.fetching[id]; fetching {
+		return nil, errFetching
+	}
+	artifactPath := as.getPath(id)
+	if !fileutils.IsExist(artifactPath) {
+		if failedAt, failed := as.failed[id]; failed {
+			if time.Since(failedAt) < fetchRetryInterval {
+				return nil, fmt.Errorf("artifact [%s] failed to be fetched recently", id)
+			}
+			delete(as.failed, id)
+		}
+		if len(as.fetching) >= maxFetchingArtifacts {
+			return nil, errFetching
+		}
+		as.fetching[id] = struct{}{}
+		go as.fetchInBackground(id)
+		return nil, errFetching
+	}
+	now := time.Now()
+	if err := os.Chtimes(artifactPath, now, now); err != nil {
+		hwlog.RunLog.Warnf("update access time of cached artifact [%s] failed: %v", id, err)
+	}
+	if _, err := fileutils.CheckOriginPath(artifactPath); err != nil {
+		return nil, fmt.Errorf("check cached artifact failed: %v", err)
+	}
+	return os.Open(artifactPath)
+}
+
+func (as *artifactStore) fetchInBackground(id string) {
+	err := as.fetch(id)
+	as.lock.Lock()
+	defer as.lock.Unlock()
+	delete(as.fetching, id)
+	if err != nil {
+		hwlog.RunLog.Errorf("fetch artifact [%s] failed: %v", id, err)
+		as.failed[id] = time.Now()
+		return
+	}
+	as.evict(id)
+}
+
+// fetch downloads the artifact from center over mutual tls, the partial file is kept for resuming if it fails
+func (as *artifactStore) fetch(id string) error {
+	hwlog.RunLog.Infof("artifact [%s] is not cached, fetch it from center", id)
+	centerTls, err := cloudcert.GetEdgeHubCertInfo()
+	if err != nil {
+		return fmt.Errorf("get tls cert info of center failed: %v", err)
+	}
+	artifactUrl := GetCenterArtifactUrl(id)
+	reqHeaders := map[string]interface{}{
+		"nodeID": configpara.GetInstallerConfig().SerialNumber,
+	}
+	var lastReq *httpsmgr.HttpsRequest
+	newReq := func() *httpsmgr.HttpsRequest {
+		lastReq = httpsmgr.GetHttpsReq(artifactUrl, *centerTls, reqHeaders).SetProxy(configpara.GetProxyFunc())
+		return lastReq
+	}
+	partPath := as.getPath(id) + partSuffix
+	if err = resumable.NewTask(as.ctx, artifactUrl, partPath, as.capacity, newReq).Run(); err != nil {
+		return fmt.Errorf("fetch artifact [%s] from center failed: %v", id, err)
+	}
+	checksum := getCenterChecksum(lastReq)
+	if !sha256Pattern.MatchString(checksum) {
+		as.remove(id + partSuffix)
+		return fmt.Errorf("center responds no valid checksum of artifact [%s]", id)
+	}
+	if err = CheckSha256(partPath, checksum); err != nil {
+		as.remove(id + partSuffix)
+		return err
+	}
+	if err = fileutils.WriteData(as.getPath(id)+checksumSuffix, []byte(checksum)); err != nil {
+		as.remove(id + partSuffix)
+		return fmt.Errorf("save checksum of artifact [%s] failed: %v", id, err)
+	}
+	if err = os.Rename(partPath, as.getPath(id)); err != nil {
+		as.remove(id + partSuffix)
+		return fmt.Errorf("save artifact [%s] failed: %v", id, err)
+	}
+	hwlog.RunLog.Infof("artifact [%s] is cached", id)
+	return nil
+}
+
+// getCenterChecksum the checksum of the artifact record of center, which is responded with the artifact
+func getCenterChecksum(req *httpsmgr.HttpsRequest) string {
+	if req == nil {
+		return ""
+	}
+	return req.GetRespHeader().Get(headerSha256Checksum)
+}
+
+// evict deletes the least recently used artifacts except the one to keep until the capacity is not exceeded,
+// it is called with the lock held
+func (as *artifactStore) evict(keepId string) {
+	cached, err := as.list()
+	if err != nil {
+		hwlog.RunLog.Errorf("list cached artifacts failed: %v", err)
+		return
+	}
+	var total int64
+	for _, artifact := range cached {
+		total += artifact.size
+	}
+	sort.Slice(cached, func(i, j int) bool {
+		return cached[i].modTime.Before(cached[j].modTime)
+	})
+	for _, artifact := range cached {
+		if total <= as.capacity {
+			return
+		}
+		if artifact.id == keepId {
+			continue
+		}
+		as.remove(artifact.id)
+		as.remove(artifact.id + checksumSuffix)
+		total -= artifact.size
+		hwlog.RunLog.Infof("cached artifact [%s] is evicted", artifact.id)
+	}
+}
+
+func (as *artifactStore) list() ([]cachedArtifact, error) {
+	entries, err := os.ReadDir(as.dir)
+	if err != nil {
+		return nil, err
+	}
+	var cached []cachedArtifact
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() || !artifactIdPattern.MatchString(entry.Name()) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		cached = append(cached, cachedArtifact{id: entry.Name(), size: info.Size(), modTime: info.ModTime()})
+	}
+	return cached, nil
+}
+
+func (as *artifactStore) remove(name string) {
+	if err := fileutils.DeleteFile(filepath.Join(as.dir, name)); err != nil {
+		hwlog.RunLog.Warnf("delete [%s] in site cache failed: %v", name, err)
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+//go:build MEFEdge_SDK
+
+package sitecache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"huawei.com/mindx/common/fileutils"
+
+	"edge-installer/pkg/common/constants"
+	"edge-installer/pkg/edge-main/common/configpara"
+)
+
+const (
+	queryId = "id"
+	// Sha256Reg the sha256 checksum of artifacts in lower case hex
+	Sha256Reg = "^[a-f0-9]{64}$"
+)
+
+// GetCenterArtifactUrl get the url of the artifact hosted by center
+func GetCenterArtifactUrl(id string) string {
+	return fmt.Sprintf("https://%s:%d%s?%s=%s", configpara.GetCenterAddr(), configpara.GetNetConfig().Port,
+		constants.ArtifactDownloadUrl, queryId, url.QueryEscape(id))
+}
+
+// GetPeerArtifactUrl get the url of the artifact served by the site cache, the site cache checks the artifact
+// fetched from center by the checksum of the artifact record of center
+func GetPeerArtifactUrl(ip string, port int, id string) string {
+	return fmt.Sprintf("https://%s:%d%s?%s=%s", ip, port, constants.ArtifactDownloadUrl, queryId, url.QueryEscape(id))
+}
+
+// CheckSha256 check the sha256 checksum of the file
+func CheckSha256(filePath, checksum string) error {
+	if _, err := fileutils.CheckOriginPath(filePath); err != nil {
+		return fmt.Errorf("check file failed: %v", err)
+	}
+	file, err := os.Open(filePath)
+	if err != nil {
+		return fmt.Errorf("open file failed: %v", err)
+	}
+	defer fileutils.CloseFile(file)
+	hasher := sha256.New()
+	if _, err = io.Copy(hasher, file); err != nil {
+		return fmt.Errorf("calculate sha256 checksum failed: %v", err)
+	}
+	if hex.EncodeToString(hasher.Sum(nil)) != checksum {
+		return fmt.Errorf("the sha256 checksum of [%s] does not match", filepath.Base(filePath))
+	}
+	return nil
+}
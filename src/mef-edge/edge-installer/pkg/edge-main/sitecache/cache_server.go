@@ -0,0 +1,132 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+//go:build MEFEdge_SDK
+
+package sitecache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"huawei.com/mindx/common/hwlog"
+	"huawei.com/mindx/common/x509/certutils"
+
+	"edge-installer/pkg/common/config"
+	"edge-installer/pkg/common/constants"
+	"edge-installer/pkg/edge-main/common/cloudcert"
+)
+
+const (
+	bytesPerGB        = 1024 * constants.MB
+	readHeaderTimeout = 10 * time.Second
+	maxHeaderBytes    = 1024
+	maxServingPeers   = 32
+	// retryAfterSeconds the peers are asked to retry later when the artifact is being fetched or the site cache
+	// is busy
+	retryAfterSeconds = "30"
+)
+
+// cacheServer serves the cached artifacts to the peers over mutual tls, both the site cache and the peers are
+// authenticated by the certs issued by center
+type cacheServer struct {
+	store   *artifactStore
+	server  *http.Server
+	serving chan struct{}
+}
+
+func startCacheServer(ctx context.Context, cfg config.SiteCacheConfig, dir string) (*cacheServer, error) {
+	certInfo, err := cloudcert.GetEdgeHubCertInfo()
+	if err != nil {
+		return nil, fmt.Errorf("get tls cert info failed: %v", err)
+	}
+	certInfo.SvrFlag = true
+	tlsCfg, err := certutils.GetTlsCfgWithPath(*certInfo)
+	if err != nil {
+		return nil, fmt.Errorf("get tls config failed: %v", err)
+	}
+	cs := &cacheServer{
+		store:   newArtifactStore(ctx, dir, cfg.Capacity*bytesPerGB),
+		serving: make(chan struct{}, maxServingPeers),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc(constants.ArtifactDownloadUrl, cs.handleDownload)
+	cs.server = &http.Server{
+		Addr:              fmt.Sprintf(":%d", cfg.Port),
+		Handler:           mux,
+		TLSConfig:         tlsCfg,
+		ReadHeaderTimeout: readHeaderTimeout,
+		MaxHeaderBytes:    maxHeaderBytes,
+	}
+	listener, err := net.Listen("tcp", cs.server.Addr)
+	if err != nil {
+		return nil, fmt.Errorf("listen on port %d failed: %v", cfg.Port, err)
+	}
+	go func() {
+		if err := cs.server.ServeTLS(listener, "", ""); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			hwlog.RunLog.Errorf("site cache server stopped: %v", err)
+		}
+	}()
+	return cs, nil
+}
+
+func (cs *cacheServer) stop() {
+	if err := cs.server.Close(); err != nil {
+		hwlog.RunLog.Warnf("close site cache server failed: %v", err)
+	}
+}
+
+// handleDownload serves the artifact with range support, so the peers resume the partial artifacts.
+// The peers are asked to retry later instead of being held when the artifact is being fetched from center
+func (cs *cacheServer) handleDownload(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	select {
+	case cs.serving <- struct{}{}:
+		defer func() { <-cs.serving }()
+	default:
+		hwlog.RunLog.Warnf("too many peers are being served, reject the request from %s", r.RemoteAddr)
+		w.Header().Set("Retry-After", retryAfterSeconds)
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	id := r.URL.Query().Get(queryId)
+	if !artifactIdPattern.MatchString(id) {
+		hwlog.RunLog.Errorf("invalid artifact request from %s", r.RemoteAddr)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	file, err := cs.store.open(id)
+	if errors.Is(err, errFetching) {
+		hwlog.RunLog.Infof("artifact [%s] is being fetched, ask %s to retry later", id, r.RemoteAddr)
+		w.Header().Set("Retry-After", retryAfterSeconds)
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		hwlog.RunLog.Errorf("open artifact [%s] for %s failed: %v", id, r.RemoteAddr, err)
+		w.WriteHeader(http.StatusBadGateway)
+		return
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		hwlog.RunLog.Errorf("stat artifact [%s] failed: %v", id, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	hwlog.RunLog.Infof("serve artifact [%s] for %s, range: [%s]", id, r.RemoteAddr, r.Header.Get("Range"))
+	http.ServeContent(w, r, id, info.ModTime(), file)
+}
@@ -0,0 +1,127 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+//go:build MEFEdge_SDK
+
+// Package sitecache for the site cache role of edge-main, the site cache serves the artifacts hosted by center
+// to the peers in the same site, so each artifact is downloaded over the uplink of the site only once
+package sitecache
+
+import (
+	"context"
+	"time"
+
+	"huawei.com/mindx/common/fileutils"
+	"huawei.com/mindx/common/hwlog"
+	"huawei.com/mindx/common/modulemgr/model"
+
+	"edge-installer/pkg/common/config"
+	"edge-installer/pkg/common/constants"
+	"edge-installer/pkg/common/path"
+)
+
+const configCheckInterval = time.Minute
+
+// siteCacheDir the dir of cached artifacts, it is a variable for test
+var siteCacheDir = constants.EdgeSiteCachePath
+
+type siteCacheMgr struct {
+	ctx    context.Context
+	enable bool
+	cfg    config.SiteCacheConfig
+	server *cacheServer
+}
+
+// NewSiteCacheMgr create new module instance
+func NewSiteCacheMgr(enable bool) model.Module {
+	return &siteCacheMgr{
+		ctx:    context.Background(),
+		enable: enable,
+	}
+}
+
+// Name [method] return name of module
+func (m *siteCacheMgr) Name() string {
+	return constants.SiteCacheManagerName
+}
+
+// Enable [method] decide this module should be enabled
+func (m *siteCacheMgr) Enable() bool {
+	return m.enable
+}
+
+// Start [method] start this module, the site cache config set by edgectl is checked periodically,
+// and the site cache server is started or stopped by it
+func (m *siteCacheMgr) Start() {
+	hwlog.RunLog.Info("site cache manager start success")
+	ticker := time.NewTicker(configCheckInterval)
+	defer ticker.Stop()
+	for {
+		m.reload()
+		select {
+		case _, _ = <-m.ctx.Done():
+			if m.server != nil {
+				m.server.stop()
+			}
+			hwlog.RunLog.Info("-------------------site cache manager exit-------------------")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (m *siteCacheMgr) reload() {
+	cfg := loadSiteCacheConfig()
+	if cfg == m.cfg && (m.server != nil) == cfg.Enable {
+		return
+	}
+	if m.server != nil {
+		m.server.stop()
+		m.server = nil
+		hwlog.RunLog.Info("site cache server is stopped")
+	}
+	m.cfg = cfg
+	if !cfg.Enable {
+		return
+	}
+	if !fileutils.IsExist(siteCacheDir) {
+		hwlog.RunLog.Errorf("site cache dir does not exist, set the site cache by edgectl again")
+		return
+	}
+	server, err := startCacheServer(m.ctx, cfg, siteCacheDir)
+	if err != nil {
+		hwlog.RunLog.Errorf("start site cache server failed, it is retried later: %v", err)
+		return
+	}
+	m.server = server
+	hwlog.RunLog.Infof("site cache server is started on port %d, capacity: %d GB", cfg.Port, cfg.Capacity)
+}
+
+// loadSiteCacheConfig load the site cache config of the node, the site cache is disabled if it is not set or invalid
+func loadSiteCacheConfig() config.SiteCacheConfig {
+	configPathMgr, err := path.GetConfigPathMgr()
+	if err != nil {
+		hwlog.RunLog.Warnf("get config path manager failed, site cache is disabled: %v", err)
+		return config.SiteCacheConfig{}
+	}
+	cfgPath := configPathMgr.GetSiteCachePath()
+	if !fileutils.IsExist(cfgPath) {
+		return config.SiteCacheConfig{}
+	}
+	cfg, err := config.LoadSiteCacheConfig(cfgPath)
+	if err != nil {
+		hwlog.RunLog.Warnf("load site cache config failed, site cache is disabled: %v", err)
+		return config.SiteCacheConfig{}
+	}
+	if err = cfg.Check(); err != nil {
+		hwlog.RunLog.Warnf("site cache config is invalid, site cache is disabled: %v", err)
+		return config.SiteCacheConfig{}
+	}
+	return *cfg
+}
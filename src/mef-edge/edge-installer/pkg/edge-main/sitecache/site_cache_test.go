@@ -0,0 +1,222 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+//go:build MEFEdge_SDK
+
+package sitecache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/smartystreets/goconvey/convey"
+
+	"huawei.com/mindx/common/fileutils"
+	"huawei.com/mindx/common/x509/certutils"
+
+	"edge-installer/pkg/common/config"
+	"edge-installer/pkg/edge-main/common/cloudcert"
+	"edge-installer/pkg/edge-main/common/resumable"
+)
+
+const (
+	testArtifactId  = "0123456789abcdef0123456789abcdef"
+	testArtifactId2 = "fedcba9876543210fedcba9876543210"
+)
+
+var testContent = []byte("artifact content")
+
+func getChecksum(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+func newTestStore(capacity int64) *artifactStore {
+	dir := filepath.Join(os.TempDir(), "site_cache_test")
+	if err := fileutils.DeleteAllFileWithConfusion(dir); err != nil {
+		panic(err)
+	}
+	if err := fileutils.CreateDir(dir, fileutils.Mode700); err != nil {
+		panic(err)
+	}
+	return newArtifactStore(context.Background(), dir, capacity)
+}
+
+// patchFetch the fetching writes the content to the partial file, and center responds the checksum
+func patchFetch(store *artifactStore, id string, content []byte, checksum string) *gomonkey.Patches {
+	return gomonkey.ApplyFuncReturn(cloudcert.GetEdgeHubCertInfo, &certutils.TlsCertInfo{}, nil).
+		ApplyMethod(&resumable.Task{}, "Run", func(*resumable.Task) error {
+			return fileutils.WriteData(store.getPath(id)+partSuffix, content)
+		}).
+		ApplyFuncReturn(getCenterChecksum, checksum)
+}
+
+// waitFetched wait for the background fetching of the artifact
+func waitFetched(store *artifactStore, id string) {
+	const checkInterval = 10 * time.Millisecond
+	for {
+		store.lock.Lock()
+		_, fetching := store.fetching[id]
+		store.lock.Unlock()
+		if !fetching {
+			return
+		}
+		time.Sleep(checkInterval)
+	}
+}
+
+func TestArtifactStore(t *testing.T) {
+	convey.Convey("artifact is fetched in background when it is not cached", t, func() {
+		store := newTestStore(int64(len(testContent)))
+		patches := patchFetch(store, testArtifactId, testContent, getChecksum(testContent))
+		defer patches.Reset()
+		_, err := store.open(testArtifactId)
+		convey.So(err, convey.ShouldResemble, errFetching)
+		waitFetched(store, testArtifactId)
+		file, err := store.open(testArtifactId)
+		convey.So(err, convey.ShouldBeNil)
+		defer file.Close()
+		convey.So(fileutils.IsExist(store.getPath(testArtifactId)+partSuffix), convey.ShouldBeFalse)
+		convey.So(fileutils.IsExist(store.getPath(testArtifactId)+checksumSuffix), convey.ShouldBeTrue)
+	})
+
+	convey.Convey("partial artifact is deleted when it does not match the checksum of center", t, func() {
+		store := newTestStore(int64(len(testContent)))
+		patches := patchFetch(store, testArtifactId, []byte("tampered content"), getChecksum(testContent))
+		defer patches.Reset()
+		_, err := store.open(testArtifactId)
+		convey.So(err, convey.ShouldResemble, errFetching)
+		waitFetched(store, testArtifactId)
+		convey.So(fileutils.IsExist(store.getPath(testArtifactId)), convey.ShouldBeFalse)
+		convey.So(fileutils.IsExist(store.getPath(testArtifactId)+partSuffix), convey.ShouldBeFalse)
+		_, err = store.open(testArtifactId)
+		convey.So(err, convey.ShouldNotBeNil)
+		convey.So(errors.Is(err, errFetching), convey.ShouldBeFalse)
+	})
+
+	convey.Convey("artifact is rejected when center responds no checksum", t, func() {
+		store := newTestStore(int64(len(testContent)))
+		patches := patchFetch(store, testArtifactId, testContent, "")
+		defer patches.Reset()
+		_, err := store.open(testArtifactId)
+		convey.So(err, convey.ShouldResemble, errFetching)
+		waitFetched(store, testArtifactId)
+		convey.So(fileutils.IsExist(store.getPath(testArtifactId)), convey.ShouldBeFalse)
+	})
+
+	convey.Convey("artifact failed to be fetched is not fetched again in the interval", t, func() {
+		store := newTestStore(int64(len(testContent)))
+		patches := gomonkey.ApplyFuncReturn(cloudcert.GetEdgeHubCertInfo, &certutils.TlsCertInfo{}, nil).
+			ApplyMethodReturn(&resumable.Task{}, "Run", errors.New("connection reset"))
+		defer patches.Reset()
+		_, err := store.open(testArtifactId)
+		convey.So(err, convey.ShouldResemble, errFetching)
+		waitFetched(store, testArtifactId)
+		_, err = store.open(testArtifactId)
+		convey.So(err, convey.ShouldNotBeNil)
+		convey.So(errors.Is(err, errFetching), convey.ShouldBeFalse)
+
+		store.failed[testArtifactId] = time.Now().Add(-fetchRetryInterval)
+		_, err = store.open(testArtifactId)
+		convey.So(err, convey.ShouldResemble, errFetching)
+		waitFetched(store, testArtifactId)
+	})
+}
+
+func TestEvict(t *testing.T) {
+	convey.Convey("least recently used artifact is evicted when capacity is exceeded", t, func() {
+		store := newTestStore(int64(len(testContent)))
+		for _, id := range []string{testArtifactId, testArtifactId2} {
+			convey.So(fileutils.WriteData(store.getPath(id), testContent), convey.ShouldBeNil)
+			convey.So(fileutils.WriteData(store.getPath(id)+checksumSuffix, []byte(getChecksum(testContent))),
+				convey.ShouldBeNil)
+		}
+		older := time.Now().Add(-time.Hour)
+		convey.So(os.Chtimes(store.getPath(testArtifactId), older, older), convey.ShouldBeNil)
+		store.evict(testArtifactId2)
+		convey.So(fileutils.IsExist(store.getPath(testArtifactId)), convey.ShouldBeFalse)
+		convey.So(fileutils.IsExist(store.getPath(testArtifactId)+checksumSuffix), convey.ShouldBeFalse)
+		convey.So(fileutils.IsExist(store.getPath(testArtifactId2)), convey.ShouldBeTrue)
+	})
+}
+
+func TestHandleDownload(t *testing.T) {
+	store := newTestStore(int64(len(testContent)))
+	cs := &cacheServer{store: store, serving: make(chan struct{}, 1)}
+
+	convey.Convey("peer is asked to retry later while the artifact is fetched, then it is served with range", t,
+		func() {
+			patches := patchFetch(store, testArtifactId, testContent, getChecksum(testContent))
+			defer patches.Reset()
+			req := httptest.NewRequest(http.MethodGet, GetPeerArtifactUrl("127.0.0.1", 1025, testArtifactId), nil)
+			recorder := httptest.NewRecorder()
+			cs.handleDownload(recorder, req)
+			convey.So(recorder.Code, convey.ShouldEqual, http.StatusServiceUnavailable)
+			convey.So(recorder.Header().Get("Retry-After"), convey.ShouldEqual, retryAfterSeconds)
+
+			waitFetched(store, testArtifactId)
+			req = httptest.NewRequest(http.MethodGet, GetPeerArtifactUrl("127.0.0.1", 1025, testArtifactId), nil)
+			req.Header.Set("Range", "bytes=9-")
+			recorder = httptest.NewRecorder()
+			cs.handleDownload(recorder, req)
+			convey.So(recorder.Code, convey.ShouldEqual, http.StatusPartialContent)
+			convey.So(recorder.Body.String(), convey.ShouldEqual, string(testContent[9:]))
+		})
+
+	convey.Convey("invalid request", t, func() {
+		req := httptest.NewRequest(http.MethodGet, GetPeerArtifactUrl("127.0.0.1", 1025, "../id"), nil)
+		recorder := httptest.NewRecorder()
+		cs.handleDownload(recorder, req)
+		convey.So(recorder.Code, convey.ShouldEqual, http.StatusBadRequest)
+
+		req = httptest.NewRequest(http.MethodPost, GetPeerArtifactUrl("127.0.0.1", 1025, testArtifactId), nil)
+		recorder = httptest.NewRecorder()
+		cs.handleDownload(recorder, req)
+		convey.So(recorder.Code, convey.ShouldEqual, http.StatusMethodNotAllowed)
+	})
+
+	convey.Convey("too many peers are being served", t, func() {
+		cs.serving <- struct{}{}
+		defer func() { <-cs.serving }()
+		req := httptest.NewRequest(http.MethodGet, GetPeerArtifactUrl("127.0.0.1", 1025, testArtifactId), nil)
+		recorder := httptest.NewRecorder()
+		cs.handleDownload(recorder, req)
+		convey.So(recorder.Code, convey.ShouldEqual, http.StatusServiceUnavailable)
+		convey.So(recorder.Header().Get("Retry-After"), convey.ShouldEqual, retryAfterSeconds)
+	})
+}
+
+func TestReload(t *testing.T) {
+	convey.Convey("site cache server is started and stopped by config", t, func() {
+		cfg := config.SiteCacheConfig{Enable: true, Port: config.MinSiteCachePort, Capacity: 1}
+		server := &cacheServer{server: &http.Server{}}
+		patches := gomonkey.ApplyFuncSeq(loadSiteCacheConfig, []gomonkey.OutputCell{
+			{Values: gomonkey.Params{cfg}},
+			{Values: gomonkey.Params{config.SiteCacheConfig{}}},
+		}).
+			ApplyFuncReturn(fileutils.IsExist, true).
+			ApplyFuncReturn(startCacheServer, server, nil)
+		defer patches.Reset()
+		mgr := &siteCacheMgr{ctx: context.Background()}
+		mgr.reload()
+		convey.So(mgr.server, convey.ShouldEqual, server)
+
+		mgr.reload()
+		convey.So(mgr.server, convey.ShouldBeNil)
+	})
+}
@@ -11,14 +11,40 @@
 // Package cloudconnect cache connection status for edge-om
 package cloudconnect
 
+import (
+	"strconv"
+	"time"
+
+	"huawei.com/mindx/common/fileutils"
+	"huawei.com/mindx/common/hwlog"
+
+	"edge-installer/pkg/common/path"
+)
+
 var isConnectCloud = false
 
-// SetCloudConnectStatus [method] set cloud connect result
+// SetCloudConnectStatus [method] set cloud connect result, the result is also recorded in the flag file, so that
+// the upgrade process knows whether the node has connected to center before the new version is effected
 func SetCloudConnectStatus(connectStatus bool) {
 	isConnectCloud = connectStatus
+	if err := recordCloudConnectStatus(connectStatus); err != nil {
+		hwlog.RunLog.Warnf("record cloud connect status failed, error: %v", err)
+	}
 }
 
 // GetCloudConnectStatus [method] get cloud connect result
 func GetCloudConnectStatus() bool {
 	return isConnectCloud
 }
+
+func recordCloudConnectStatus(connectStatus bool) error {
+	configPathMgr, err := path.GetConfigPathMgr()
+	if err != nil {
+		return err
+	}
+	flagPath := configPathMgr.GetCenterConnectedFlagPath()
+	if !connectStatus {
+		return fileutils.DeleteFile(flagPath)
+	}
+	return fileutils.WriteData(flagPath, []byte(strconv.FormatInt(time.Now().Unix(), 10)))
+}
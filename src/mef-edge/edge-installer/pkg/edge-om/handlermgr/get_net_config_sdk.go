@@ -38,6 +38,7 @@ func getNetConfig(dbMgr *config.DbMgr) string {
 		return constants.Failed
 	}
 	defer utils.ClearSliceByteMemory(netConfig.Token)
+	defer utils.ClearSliceByteMemory(netConfig.ProxyPwd)
 
 	bytes, err := json.Marshal(netConfig)
 	if err != nil {
@@ -68,5 +69,14 @@ func decryptToken(netConfig *config.NetManager) error {
 		return err
 	}
 	netConfig.Token = token
+	if len(netConfig.ProxyPwd) == 0 {
+		return nil
+	}
+	proxyPwd, err := kmc.DecryptContent(netConfig.ProxyPwd, kmcCfg)
+	if err != nil {
+		hwlog.RunLog.Error("decrypt proxy password failed")
+		return err
+	}
+	netConfig.ProxyPwd = proxyPwd
 	return nil
 }
@@ -66,6 +66,7 @@ func testDecryptTokenForSdk() {
 	testErrGetInstallRootDir()
 	testErrGetKmcCfg()
 	testErrDecryptContent()
+	testDecryptProxyPwd()
 }
 
 func testErrNetType() {
@@ -112,3 +113,27 @@ func testErrDecryptContent() {
 	err := decryptToken(netConfig)
 	convey.So(err, convey.ShouldResemble, testErr)
 }
+
+func testDecryptProxyPwd() {
+	const testProxyPwd = "proxy password"
+	var p1 = gomonkey.ApplyFuncReturn(util.GetKmcConfig, nil, nil).
+		ApplyFuncSeq(kmc.DecryptContent, []gomonkey.OutputCell{
+			{Values: gomonkey.Params{[]byte(testToken), nil}},
+			{Values: gomonkey.Params{[]byte(testProxyPwd), nil}},
+			{Values: gomonkey.Params{[]byte(testToken), nil}},
+			{Values: gomonkey.Params{nil, testErr}},
+		})
+	defer p1.Reset()
+
+	netConfig := &config.NetManager{
+		NetType:  constants.MEF,
+		ProxyPwd: []byte("encrypted proxy password"),
+	}
+	err := decryptToken(netConfig)
+	convey.So(err, convey.ShouldBeNil)
+	convey.So(string(netConfig.ProxyPwd), convey.ShouldEqual, testProxyPwd)
+
+	netConfig.ProxyPwd = []byte("encrypted proxy password")
+	err = decryptToken(netConfig)
+	convey.So(err, convey.ShouldResemble, testErr)
+}
@@ -13,11 +13,16 @@
 package handlermgr
 
 import (
+	"regexp"
+
 	"huawei.com/mindx/common/modulemgr/handler"
 
 	"edge-installer/pkg/common/constants"
 )
 
+// hexIdReg the ids of the sessions, streams and queries started by center, which are 16 random bytes in hex
+var hexIdReg = regexp.MustCompile(`^[a-f0-9]{32}$`)
+
 func init() {
 	registerInfoList = append(registerInfoList, []handler.RegisterInfo{
 		{MsgOpt: constants.OptPost, MsgRes: constants.ResDownloadCert, Handler: new(saveCertHandlerSdk)},
@@ -25,5 +30,8 @@ func init() {
 		{MsgOpt: constants.OptUpdate, MsgRes: constants.InnerPrepareDir, Handler: new(prepareDirHandler)},
 		{MsgOpt: constants.OptPost, MsgRes: constants.ResPackLogRequest, Handler: new(packLogHandler)},
 		{MsgOpt: constants.OptReport, MsgRes: constants.ResEdgeCloudConnection, Handler: new(cloudConnectHandler)},
+		{MsgOpt: constants.OptPost, MsgRes: constants.ResRemoteShell, Handler: new(remoteShellHandler)},
+		{MsgOpt: constants.OptPost, MsgRes: constants.ResPortForward, Handler: new(portForwardHandler)},
+		{MsgOpt: constants.OptPost, MsgRes: constants.ResTraceLog, Handler: new(traceLogHandler)},
 	}...)
 }
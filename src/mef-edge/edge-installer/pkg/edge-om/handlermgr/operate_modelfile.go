@@ -216,6 +216,10 @@ func (o *OperateModelFile) queryModelFile(dir string) (*utils.Set, error) {
 		return fileSet, err
 	}
 	for _, path := range pathList {
+		// the partial files are kept for resuming the download, they are not model files of any task
+		if path == constants.ModeFilePartialDir {
+			continue
+		}
 		if fileutils.IsFile(path) {
 			fileSet.Add(path)
 			continue
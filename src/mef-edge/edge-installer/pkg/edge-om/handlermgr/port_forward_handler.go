@@ -0,0 +1,502 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+//go:build MEFEdge_SDK
+
+// Package handlermgr
+package handlermgr
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"huawei.com/mindx/common/envutils"
+	"huawei.com/mindx/common/hwlog"
+	"huawei.com/mindx/common/limiter"
+	"huawei.com/mindx/common/modulemgr"
+	"huawei.com/mindx/common/modulemgr/model"
+
+	"edge-installer/pkg/common/config"
+	"edge-installer/pkg/common/constants"
+)
+
+const (
+	forwardActionConnect = "connect"
+	forwardActionData    = "data"
+	forwardActionAck     = "ack"
+	forwardActionClose   = "close"
+
+	maxForwardStreams       = 16
+	forwardIdleTimeout      = 10 * time.Minute
+	forwardCheckInterval    = 30 * time.Second
+	forwardDialTimeout      = 5 * time.Second
+	forwardWindowSize       = 256 * 1024
+	maxForwardPendingFrames = 64
+	maxForwardFrameLen      = 16 * 1024
+	forwardLimitPeriod      = time.Second
+	forwardReserveRate      = 0.5
+	forwardThrottleInterval = 20 * time.Millisecond
+
+	localHostIp        = "127.0.0.1"
+	hostNetworkMode    = "host"
+	containerNetPrefix = "container:"
+)
+
+// forwardRequest request from center to operate a port forward stream. The data frames and the close frame are
+// numbered from 1 in order, a close frame without seq aborts the stream immediately
+type forwardRequest struct {
+	StreamId  string `json:"streamId"`
+	Action    string `json:"action"`
+	Target    string `json:"target,omitempty"`
+	Container string `json:"container,omitempty"`
+	Seq       uint64 `json:"seq,omitempty"`
+	Data      []byte `json:"data,omitempty"`
+	Ack       uint64 `json:"ack,omitempty"`
+}
+
+// forwardData data of a port forward stream reported to center, closed is set on the last report.
+// Ack is the count of bytes received from center which have been written to target
+type forwardData struct {
+	StreamId string `json:"streamId"`
+	Seq      uint64 `json:"seq,omitempty"`
+	Data     []byte `json:"data,omitempty"`
+	Ack      uint64 `json:"ack,omitempty"`
+	Closed   bool   `json:"closed,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+type forwardStream struct {
+	id         string
+	conn       net.Conn
+	limiter    *limiter.ServerBandwidthLimiter
+	lastActive int64
+	done       chan struct{}
+	closeOnce  sync.Once
+	reason     string
+
+	// receiving side, frames from center may arrive out of order
+	recvLock sync.Mutex
+	nextSeq  uint64
+	pending  map[uint64]*forwardRequest
+	written  uint64
+	ackSent  uint64
+
+	// sending side, no more than forwardWindowSize bytes are in flight
+	sentLen   uint64
+	peerAcked uint64
+	ackCh     chan struct{}
+}
+
+type forwardStreamMgr struct {
+	lock      sync.Mutex
+	streams   map[string]*forwardStream
+	limiter   *limiter.ServerBandwidthLimiter
+	bandwidth int
+}
+
+var forwardMgr = &forwardStreamMgr{streams: make(map[string]*forwardStream)}
+
+type portForwardHandler struct{}
+
+// Handle portForwardHandler connect, write and close the tcp streams forwarded from center to the targets allowed
+// by pod config
+func (h *portForwardHandler) Handle(msg *model.Message) error {
+	var req forwardRequest
+	if err := msg.ParseContent(&req); err != nil {
+		hwlog.RunLog.Errorf("parse port forward request failed: %v", err)
+		return errors.New("parse port forward request failed")
+	}
+	if !hexIdReg.MatchString(req.StreamId) {
+		hwlog.RunLog.Error("port forward stream id is invalid")
+		return errors.New("port forward stream id is invalid")
+	}
+
+	var err error
+	switch req.Action {
+	case forwardActionConnect:
+		err = forwardMgr.connect(&req)
+	case forwardActionData, forwardActionClose:
+		err = forwardMgr.receive(&req)
+	case forwardActionAck:
+		err = forwardMgr.ack(req.StreamId, req.Ack)
+	default:
+		err = fmt.Errorf("action [%s] is not supported", req.Action)
+	}
+	if err != nil {
+		hwlog.RunLog.Errorf("port forward stream [%s] %s failed: %v", req.StreamId, req.Action, err)
+		if req.Action == forwardActionConnect || !forwardMgr.exist(req.StreamId) {
+			reportForwardData(forwardData{StreamId: req.StreamId, Closed: true, Reason: err.Error()})
+		}
+		return err
+	}
+	return nil
+}
+
+func (m *forwardStreamMgr) connect(req *forwardRequest) error {
+	podConfig, err := config.LoadPodConfig()
+	if err != nil {
+		hwlog.RunLog.Errorf("load pod config failed: %v", err)
+		return errors.New("load pod config failed")
+	}
+	addr, err := getForwardAddr(req, podConfig.PortForwardAllowList)
+	if err != nil {
+		return err
+	}
+
+	m.lock.Lock()
+	err = m.checkCapacity(req.StreamId)
+	m.lock.Unlock()
+	if err != nil {
+		return err
+	}
+	conn, err := net.DialTimeout("tcp", addr, forwardDialTimeout)
+	if err != nil {
+		return fmt.Errorf("connect to target [%s] failed: %v", req.Target, err)
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if err = m.checkCapacity(req.StreamId); err != nil {
+		closeForwardConn(req.StreamId, conn)
+		return err
+	}
+	m.updateLimiter(podConfig.PortForwardBandwidth)
+	stream := &forwardStream{
+		id:      req.StreamId,
+		conn:    conn,
+		limiter: m.limiter,
+		done:    make(chan struct{}),
+		nextSeq: 1,
+		pending: make(map[uint64]*forwardRequest),
+		ackCh:   make(chan struct{}, 1),
+	}
+	stream.touch()
+	stream.limiter.RegisterConn(stream.id)
+	m.streams[req.StreamId] = stream
+	go m.serve(stream)
+	go stream.watchIdle()
+	hwlog.RunLog.Infof("port forward stream [%s] to %s started", req.StreamId, req.Target)
+	return nil
+}
+
+func (m *forwardStreamMgr) checkCapacity(id string) error {
+	if _, ok := m.streams[id]; ok {
+		return errors.New("stream already exists")
+	}
+	if len(m.streams) >= maxForwardStreams {
+		return fmt.Errorf("the count of port forward streams reaches the limit %d", maxForwardStreams)
+	}
+	return nil
+}
+
+// updateLimiter the bandwidth is shared by all streams, the change of it takes effect when no stream is running
+func (m *forwardStreamMgr) updateLimiter(bandwidth int) {
+	if m.limiter != nil {
+		if len(m.streams) > 0 || m.bandwidth == bandwidth {
+			return
+		}
+		m.limiter.Stop()
+	}
+	m.limiter = limiter.NewServerBandwidthLimiter(&limiter.BandwidthLimiterConfig{
+		MaxThroughput: bandwidth * constants.KB,
+		Period:        forwardLimitPeriod,
+		ReserveRate:   forwardReserveRate,
+	})
+	m.bandwidth = bandwidth
+}
+
+func (m *forwardStreamMgr) get(id string) (*forwardStream, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	stream, ok := m.streams[id]
+	if !ok {
+		return nil, errors.New("stream does not exist")
+	}
+	return stream, nil
+}
+
+func (m *forwardStreamMgr) exist(id string) bool {
+	_, err := m.get(id)
+	return err == nil
+}
+
+func (m *forwardStreamMgr) receive(req *forwardRequest) error {
+	stream, err := m.get(req.StreamId)
+	if err != nil {
+		return err
+	}
+	if req.Action == forwardActionClose && req.Seq == 0 {
+		stream.stop("closed by center")
+		return nil
+	}
+	if err = stream.receive(req); err != nil {
+		stream.stop(err.Error())
+		return err
+	}
+	return nil
+}
+
+func (m *forwardStreamMgr) ack(id string, acked uint64) error {
+	stream, err := m.get(id)
+	if err != nil {
+		return err
+	}
+	stream.updatePeerAcked(acked)
+	return nil
+}
+
+// serve forwards the data read from target to center until the target closes the connection, the stream is
+// closed by center, or no data is transferred for a long time
+func (m *forwardStreamMgr) serve(stream *forwardStream) {
+	seq := uint64(1)
+	buf := make([]byte, maxForwardFrameLen)
+	for stream.waitWindow() {
+		n, err := stream.conn.Read(buf)
+		if n > 0 {
+			stream.touch()
+			if !stream.waitBandwidth(n) {
+				break
+			}
+			data := make([]byte, n)
+			copy(data, buf[:n])
+			reportForwardData(forwardData{StreamId: stream.id, Seq: seq, Data: data})
+			seq++
+			atomic.AddUint64(&stream.sentLen, uint64(n))
+		}
+		if err != nil {
+			break
+		}
+	}
+	stream.stop("")
+
+	m.lock.Lock()
+	delete(m.streams, stream.id)
+	m.lock.Unlock()
+	stream.limiter.UnregisterConn(stream.id)
+	reason := stream.reason
+	if reason == "" {
+		reason = "connection closed by target"
+	}
+	reportForwardData(forwardData{StreamId: stream.id, Seq: seq, Closed: true, Reason: reason})
+	hwlog.RunLog.Infof("port forward stream [%s] finished: %s", stream.id, reason)
+}
+
+// receive buffers the frame until all the frames before it are received, then writes them to target in order
+func (s *forwardStream) receive(req *forwardRequest) error {
+	s.recvLock.Lock()
+	defer s.recvLock.Unlock()
+	if req.Seq < s.nextSeq {
+		return nil
+	}
+	if len(req.Data) > maxForwardFrameLen {
+		return fmt.Errorf("frame should not be longer than %d", maxForwardFrameLen)
+	}
+	if len(s.pending) >= maxForwardPendingFrames {
+		return errors.New("too many frames are out of order")
+	}
+	s.pending[req.Seq] = req
+	for {
+		frame, ok := s.pending[s.nextSeq]
+		if !ok {
+			return nil
+		}
+		delete(s.pending, s.nextSeq)
+		s.nextSeq++
+		if frame.Action == forwardActionClose {
+			return s.closeWrite()
+		}
+		if err := s.write(frame.Data); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *forwardStream) write(data []byte) error {
+	s.touch()
+	if !s.waitBandwidth(len(data)) {
+		return errors.New("stream is closed")
+	}
+	if _, err := s.conn.Write(data); err != nil {
+		return fmt.Errorf("write to target failed: %v", err)
+	}
+	s.written += uint64(len(data))
+	if s.written-s.ackSent >= forwardWindowSize/4 {
+		s.ackSent = s.written
+		reportForwardData(forwardData{StreamId: s.id, Ack: s.written})
+	}
+	return nil
+}
+
+// closeWrite the client of center has finished sending, the response of target is still forwarded
+func (s *forwardStream) closeWrite() error {
+	tcpConn, ok := s.conn.(*net.TCPConn)
+	if !ok {
+		return errors.New("connection does not support half close")
+	}
+	if err := tcpConn.CloseWrite(); err != nil {
+		return fmt.Errorf("close write of target connection failed: %v", err)
+	}
+	return nil
+}
+
+func (s *forwardStream) updatePeerAcked(acked uint64) {
+	for {
+		old := atomic.LoadUint64(&s.peerAcked)
+		if acked <= old || atomic.CompareAndSwapUint64(&s.peerAcked, old, acked) {
+			break
+		}
+	}
+	select {
+	case s.ackCh <- struct{}{}:
+	default:
+	}
+}
+
+// waitWindow waits until the data in flight is less than the window, returns false if stream is closed
+func (s *forwardStream) waitWindow() bool {
+	for atomic.LoadUint64(&s.sentLen)-atomic.LoadUint64(&s.peerAcked) >= forwardWindowSize {
+		select {
+		case <-s.ackCh:
+		case <-s.done:
+			return false
+		}
+	}
+	return true
+}
+
+// waitBandwidth waits until the limiter allows the data to be transferred, returns false if stream is closed
+func (s *forwardStream) waitBandwidth(size int) bool {
+	for !s.limiter.Allow(s.id, size) {
+		select {
+		case <-time.After(forwardThrottleInterval):
+		case <-s.done:
+			return false
+		}
+	}
+	return true
+}
+
+func (s *forwardStream) watchIdle() {
+	ticker := time.NewTicker(forwardCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+			if time.Since(time.Unix(0, atomic.LoadInt64(&s.lastActive))) > forwardIdleTimeout {
+				s.stop("idle timeout")
+				return
+			}
+		}
+	}
+}
+
+// stop closing connection interrupts the pending read and write
+func (s *forwardStream) stop(reason string) {
+	s.closeOnce.Do(func() {
+		s.reason = reason
+		close(s.done)
+		closeForwardConn(s.id, s.conn)
+	})
+}
+
+func closeForwardConn(id string, conn net.Conn) {
+	if err := conn.Close(); err != nil {
+		hwlog.RunLog.Warnf("close connection of port forward stream [%s] failed: %v", id, err)
+	}
+}
+
+func (s *forwardStream) touch() {
+	atomic.StoreInt64(&s.lastActive, time.Now().UnixNano())
+}
+
+// getForwardAddr the target should be in the allow list of pod config, container target is the port in the
+// network namespace of the application container
+func getForwardAddr(req *forwardRequest, allowList []string) (string, error) {
+	port, err := config.ParsePortForwardTarget(req.Target)
+	if err != nil {
+		return "", fmt.Errorf("target is invalid: %v", err)
+	}
+	allowed := false
+	for _, target := range allowList {
+		if target == req.Target {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return "", fmt.Errorf("target [%s] is not allowed by pod config", req.Target)
+	}
+
+	ip := localHostIp
+	if strings.HasPrefix(req.Target, config.PortForwardContainer+":") {
+		if ip, err = getContainerIp(req.Container); err != nil {
+			return "", err
+		}
+	}
+	return net.JoinHostPort(ip, strconv.Itoa(port)), nil
+}
+
+func getContainerIp(container string) (string, error) {
+	if err := checkAppContainer(container); err != nil {
+		return "", err
+	}
+	// the application containers share the network of pause container unless host network is used
+	mode, err := envutils.RunCommand(constants.DockerCmd, envutils.DefCmdTimeoutSec, "inspect", "--format",
+		"{{.HostConfig.NetworkMode}}", container)
+	if err != nil {
+		hwlog.RunLog.Errorf("inspect network mode of container [%s] failed: %v", container, err)
+		return "", errors.New("inspect network mode of container failed")
+	}
+	mode = strings.TrimSpace(mode)
+	if mode == hostNetworkMode {
+		return localHostIp, nil
+	}
+	netContainer := container
+	if strings.HasPrefix(mode, containerNetPrefix) {
+		netContainer = strings.TrimPrefix(mode, containerNetPrefix)
+		if !containerNameReg.MatchString(netContainer) {
+			return "", errors.New("network container name is invalid")
+		}
+	}
+	ip, err := envutils.RunCommand(constants.DockerCmd, envutils.DefCmdTimeoutSec, "inspect", "--format",
+		"{{.NetworkSettings.IPAddress}}", netContainer)
+	if err != nil {
+		hwlog.RunLog.Errorf("inspect ip of container [%s] failed: %v", netContainer, err)
+		return "", errors.New("inspect ip of container failed")
+	}
+	ip = strings.TrimSpace(ip)
+	if net.ParseIP(ip) == nil {
+		return "", errors.New("container has no valid ip")
+	}
+	return ip, nil
+}
+
+func reportForwardData(data forwardData) {
+	msg, err := model.NewMessage()
+	if err != nil {
+		hwlog.RunLog.Errorf("create port forward data message failed: %v", err)
+		return
+	}
+	msg.SetRouter(constants.ModEdgeOm, constants.InnerClient, constants.OptReport, constants.ResPortForwardData)
+	if err = msg.FillContent(data, true); err != nil {
+		hwlog.RunLog.Errorf("fill port forward data into content failed: %v", err)
+		return
+	}
+	if err = modulemgr.SendMessage(msg); err != nil {
+		hwlog.RunLog.Errorf("send port forward data of stream [%s] failed: %v", data.StreamId, err)
+	}
+}
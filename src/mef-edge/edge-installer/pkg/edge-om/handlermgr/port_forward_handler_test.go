@@ -0,0 +1,169 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+//go:build MEFEdge_SDK
+
+// Package handlermgr for port forward handler test
+package handlermgr
+
+import (
+	"io"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/smartystreets/goconvey/convey"
+
+	"huawei.com/mindx/common/envutils"
+	"huawei.com/mindx/common/modulemgr/model"
+
+	"edge-installer/pkg/common/config"
+)
+
+func newForwardRequestMsg(req forwardRequest) *model.Message {
+	msg, err := model.NewMessage()
+	if err != nil {
+		panic(err)
+	}
+	if err = msg.FillContent(req); err != nil {
+		panic(err)
+	}
+	return msg
+}
+
+func startEchoServer() (net.Listener, int) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		panic(err)
+	}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				if _, err := io.Copy(conn, conn); err != nil {
+					return
+				}
+			}()
+		}
+	}()
+	return listener, listener.Addr().(*net.TCPAddr).Port
+}
+
+func waitForwardData(dataCh <-chan forwardData, match func(forwardData) bool) bool {
+	timer := time.NewTimer(testWaitTimeout)
+	defer timer.Stop()
+	for {
+		select {
+		case data := <-dataCh:
+			if match(data) {
+				return true
+			}
+		case <-timer.C:
+			return false
+		}
+	}
+}
+
+func TestGetForwardAddr(t *testing.T) {
+	allowList := []string{"host:8080", "container:8888"}
+	convey.Convey("target should be in allow list", t, func() {
+		_, err := getForwardAddr(&forwardRequest{Target: "host:22"}, allowList)
+		convey.So(err, convey.ShouldNotBeNil)
+		_, err = getForwardAddr(&forwardRequest{Target: "vm:8080"}, allowList)
+		convey.So(err, convey.ShouldNotBeNil)
+		_, err = getForwardAddr(&forwardRequest{Target: "host:8080"}, nil)
+		convey.So(err, convey.ShouldNotBeNil)
+		addr, err := getForwardAddr(&forwardRequest{Target: "host:8080"}, allowList)
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(addr, convey.ShouldEqual, "127.0.0.1:8080")
+	})
+
+	convey.Convey("container target is forwarded to the ip of pause container", t, func() {
+		outputs := []gomonkey.OutputCell{
+			{Values: gomonkey.Params{"/k8s_app_pod-1_default_0\n", nil}},
+			{Values: gomonkey.Params{"container:9c8d7e\n", nil}},
+			{Values: gomonkey.Params{"172.17.0.3\n", nil}},
+		}
+		patches := gomonkey.ApplyFuncSeq(envutils.RunCommand, outputs)
+		defer patches.Reset()
+		addr, err := getForwardAddr(&forwardRequest{Target: "container:8888", Container: "3f2a9c"}, allowList)
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(addr, convey.ShouldEqual, "172.17.0.3:8888")
+	})
+
+	convey.Convey("container target with host network is forwarded to local host", t, func() {
+		outputs := []gomonkey.OutputCell{
+			{Values: gomonkey.Params{"/k8s_app_pod-1_default_0\n", nil}},
+			{Values: gomonkey.Params{"host\n", nil}},
+		}
+		patches := gomonkey.ApplyFuncSeq(envutils.RunCommand, outputs)
+		defer patches.Reset()
+		addr, err := getForwardAddr(&forwardRequest{Target: "container:8888", Container: "3f2a9c"}, allowList)
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(addr, convey.ShouldEqual, "127.0.0.1:8888")
+	})
+}
+
+func TestPortForwardHandler(t *testing.T) {
+	listener, port := startEchoServer()
+	defer listener.Close()
+	target := "host:" + strconv.Itoa(port)
+	dataCh := make(chan forwardData, maxForwardPendingFrames)
+	patches := gomonkey.ApplyFunc(reportForwardData, func(data forwardData) {
+		dataCh <- data
+	}).ApplyFuncReturn(config.LoadPodConfig, &config.PodConfig{ContainerConfig: config.ContainerConfig{
+		PortForwardAllowList: []string{target}, PortForwardBandwidth: 1024}}, nil)
+	defer patches.Reset()
+	handler := &portForwardHandler{}
+
+	convey.Convey("target not allowed can not be connected", t, func() {
+		err := handler.Handle(newForwardRequestMsg(forwardRequest{StreamId: testSessionId,
+			Action: forwardActionConnect, Target: "host:1"}))
+		convey.So(err, convey.ShouldNotBeNil)
+		convey.So(waitForwardData(dataCh, func(data forwardData) bool { return data.Closed }), convey.ShouldBeTrue)
+	})
+
+	convey.Convey("frames out of order are written in order and stream is closed after target finishes", t, func() {
+		convey.So(handler.Handle(newForwardRequestMsg(forwardRequest{StreamId: testSessionId,
+			Action: forwardActionConnect, Target: target})), convey.ShouldBeNil)
+		convey.So(handler.Handle(newForwardRequestMsg(forwardRequest{StreamId: testSessionId,
+			Action: forwardActionData, Seq: 2, Data: []byte("world")})), convey.ShouldBeNil)
+		convey.So(handler.Handle(newForwardRequestMsg(forwardRequest{StreamId: testSessionId,
+			Action: forwardActionData, Seq: 1, Data: []byte("hello ")})), convey.ShouldBeNil)
+		convey.So(handler.Handle(newForwardRequestMsg(forwardRequest{StreamId: testSessionId,
+			Action: forwardActionClose, Seq: 3})), convey.ShouldBeNil)
+
+		var received []byte
+		convey.So(waitForwardData(dataCh, func(data forwardData) bool {
+			received = append(received, data.Data...)
+			return data.Closed
+		}), convey.ShouldBeTrue)
+		convey.So(string(received), convey.ShouldEqual, "hello world")
+		convey.So(forwardMgr.exist(testSessionId), convey.ShouldBeFalse)
+	})
+
+	convey.Convey("stream is aborted by center", t, func() {
+		convey.So(handler.Handle(newForwardRequestMsg(forwardRequest{StreamId: testSessionId,
+			Action: forwardActionConnect, Target: target})), convey.ShouldBeNil)
+		convey.So(handler.Handle(newForwardRequestMsg(forwardRequest{StreamId: testSessionId,
+			Action: forwardActionClose})), convey.ShouldBeNil)
+		convey.So(waitForwardData(dataCh, func(data forwardData) bool {
+			return data.Closed && data.Reason == "closed by center"
+		}), convey.ShouldBeTrue)
+		err := handler.Handle(newForwardRequestMsg(forwardRequest{StreamId: testSessionId,
+			Action: forwardActionData, Seq: 1, Data: []byte("x")}))
+		convey.So(err, convey.ShouldNotBeNil)
+	})
+}
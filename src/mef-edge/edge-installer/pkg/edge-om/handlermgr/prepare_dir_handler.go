@@ -15,6 +15,7 @@ package handlermgr
 import (
 	"errors"
 	"fmt"
+	"os"
 	"path/filepath"
 
 	"huawei.com/mindx/common/fileutils"
@@ -73,8 +74,8 @@ func (p *prepareDirHandler) sendResponse(msg *model.Message, respMsg string) {
 func processCreateDirRequest(req config.DirReq) error {
 	hwlog.RunLog.Info("start to create directory for software download")
 	if fileutils.IsExist(req.Path) {
-		if err := fileutils.DeleteAllFileWithConfusion(req.Path); err != nil {
-			hwlog.RunLog.Errorf("delete path %s failed: %v", req.Path, err)
+		if err := cleanDirExcept(req.Path, req.KeepFiles); err != nil {
+			hwlog.RunLog.Errorf("clean path %s failed: %v", req.Path, err)
 			return err
 		}
 	}
@@ -104,6 +105,34 @@ func processCreateDirRequest(req config.DirReq) error {
 	return nil
 }
 
+// cleanDirExcept delete all files in dir except the regular files to keep, the dir is deleted if nothing is kept
+func cleanDirExcept(dir string, keepFiles []string) error {
+	if len(keepFiles) == 0 {
+		return fileutils.DeleteAllFileWithConfusion(dir)
+	}
+	if _, err := fileutils.CheckOriginPath(dir); err != nil {
+		return err
+	}
+	toKeep := make(map[string]bool, len(keepFiles))
+	for _, name := range keepFiles {
+		toKeep[name] = true
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("read dir failed: %v", err)
+	}
+	for _, entry := range entries {
+		if toKeep[entry.Name()] && entry.Type().IsRegular() {
+			hwlog.RunLog.Infof("keep file [%s] for resuming download", entry.Name())
+			continue
+		}
+		if err = fileutils.DeleteAllFileWithConfusion(filepath.Join(dir, entry.Name())); err != nil {
+			return fmt.Errorf("delete %s failed: %v", entry.Name(), err)
+		}
+	}
+	return nil
+}
+
 func processDeleteDirRequest(req config.DirReq) error {
 	hwlog.RunLog.Info("start to clean directory of software download")
 	if err := fileutils.DeleteAllFileWithConfusion(req.Path); err != nil {
@@ -15,6 +15,7 @@ package handlermgr
 import (
 	"errors"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/agiledragon/gomonkey/v2"
@@ -72,6 +73,7 @@ func TestPrepareDirHandler(t *testing.T) {
 	defer p.Reset()
 	convey.Convey("test prepare dir handler, test process create dir request", t, testProcessCreateDirRequest)
 	convey.Convey("test prepare dir handler, test process delete dir request", t, testProcessDeleteDirRequest)
+	convey.Convey("test prepare dir handler, keep partial files for resuming", t, testCleanDirExcept)
 	convey.Convey("test prepare dir handler failed, parse dir request failed", t, parseReqFailed)
 }
 
@@ -144,6 +146,21 @@ func processDeleteDirRequestFailed() {
 	convey.So(err, convey.ShouldResemble, expectErr)
 }
 
+func testCleanDirExcept() {
+	partialFile := filepath.Join(testDir, "MEFEdge.tar.gz")
+	staleFile := filepath.Join(testDir, "stale.txt")
+	convey.So(fileutils.CreateDir(testDir, constants.Mode700), convey.ShouldBeNil)
+	convey.So(fileutils.WriteData(partialFile, []byte("partial")), convey.ShouldBeNil)
+	convey.So(fileutils.WriteData(staleFile, []byte("stale")), convey.ShouldBeNil)
+
+	convey.So(cleanDirExcept(testDir, []string{"MEFEdge.tar.gz", "MEFEdge.tar.gz.ckpt"}), convey.ShouldBeNil)
+	convey.So(fileutils.IsExist(partialFile), convey.ShouldBeTrue)
+	convey.So(fileutils.IsExist(staleFile), convey.ShouldBeFalse)
+
+	convey.So(cleanDirExcept(testDir, nil), convey.ShouldBeNil)
+	convey.So(fileutils.IsExist(testDir), convey.ShouldBeFalse)
+}
+
 func parseReqFailed() {
 	testInvalidMsg, err := model.NewMessage()
 	if err != nil {
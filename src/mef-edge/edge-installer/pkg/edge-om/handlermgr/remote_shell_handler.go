@@ -0,0 +1,488 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+//go:build MEFEdge_SDK
+
+// Package handlermgr
+package handlermgr
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"huawei.com/mindx/common/envutils"
+	"huawei.com/mindx/common/hwlog"
+	"huawei.com/mindx/common/modulemgr"
+	"huawei.com/mindx/common/modulemgr/model"
+	"huawei.com/mindx/common/terminal"
+
+	"edge-installer/pkg/common/config"
+	"edge-installer/pkg/common/constants"
+)
+
+const (
+	shellTypeHost      = "host"
+	shellTypeContainer = "container"
+
+	shellActionOpen   = "open"
+	shellActionInput  = "input"
+	shellActionResize = "resize"
+	shellActionClose  = "close"
+
+	maxShellSessions    = 4
+	shellIdleTimeout    = 10 * time.Minute
+	shellFlushInterval  = 100 * time.Millisecond
+	shellReadBufSize    = 4 * 1024
+	maxShellOutputChunk = 16 * 1024
+	maxShellInputLen    = 4 * 1024
+	maxShellArgs        = 16
+	maxShellArgLen      = 256
+	maxShellTermSize    = 1000
+
+	defaultContainerShell = "/bin/sh"
+	appContainerPrefix    = "/k8s_"
+	shellTermEnv          = "TERM=xterm"
+)
+
+var containerNameReg = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]{0,254}$`)
+
+// hostCommandRule the args of a host command are checked one by one, an arg should be one of the sub commands at
+// first, or match the option pattern, or be a value when values are allowed. The fixed args are always prepended to
+// disable the pagers, which could start an interactive shell
+type hostCommandRule struct {
+	subCommands map[string]struct{}
+	optionReg   *regexp.Regexp
+	allowValues bool
+	fixedArgs   []string
+}
+
+// hostShellAllowList commands that can be run on host, they are only used for checking the status of the device
+var hostShellAllowList = map[string]hostCommandRule{
+	"npu-smi": {subCommands: map[string]struct{}{"info": {}}, optionReg: regexp.MustCompile(`^-[tim]$`),
+		allowValues: true},
+	"df":     {optionReg: regexp.MustCompile(`^-[hTi]+$`)},
+	"free":   {optionReg: regexp.MustCompile(`^-[hmgkw]+$`)},
+	"ps":     {optionReg: regexp.MustCompile(`^-?[aefuxwlHj]+$`)},
+	"uptime": {optionReg: regexp.MustCompile(`^-[ps]$`)},
+	"ping":   {optionReg: regexp.MustCompile(`^-[46nc]$`), allowValues: true},
+	"ss":     {optionReg: regexp.MustCompile(`^-[tulnpaxswie46]+$`)},
+	"dmesg":  {optionReg: regexp.MustCompile(`^-[Tkux]+$`), fixedArgs: []string{"--nopager"}},
+	"journalctl": {optionReg: regexp.MustCompile(`^(-[bkrx]+|-[unp]|--since|--until)$`), allowValues: true,
+		fixedArgs: []string{"--no-pager"}},
+}
+
+// shellValueReg values of the host command options, such as unit names, counts, times and host names
+var shellValueReg = regexp.MustCompile(`^[a-zA-Z0-9@_.:/+][a-zA-Z0-9@_.:/+ -]{0,127}$`)
+
+// shellEnv the minimal environment of the session, the pagers are disabled
+var shellEnv = []string{"PATH=/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin", shellTermEnv,
+	"PAGER=cat", "SYSTEMD_PAGER=", "LANG=C"}
+
+// shellRequest request from center to operate a remote shell session
+type shellRequest struct {
+	SessionId string   `json:"sessionId"`
+	Action    string   `json:"action"`
+	Type      string   `json:"type,omitempty"`
+	Container string   `json:"container,omitempty"`
+	Command   []string `json:"command,omitempty"`
+	Rows      uint16   `json:"rows,omitempty"`
+	Cols      uint16   `json:"cols,omitempty"`
+	Data      []byte   `json:"data,omitempty"`
+}
+
+// shellOutput output of a remote shell session reported to center, closed is set on the last report
+type shellOutput struct {
+	SessionId string `json:"sessionId"`
+	Data      []byte `json:"data,omitempty"`
+	Closed    bool   `json:"closed,omitempty"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+type shellSession struct {
+	id         string
+	cmd        *exec.Cmd
+	pty        *os.File
+	lastActive int64
+	closeOnce  sync.Once
+	reason     string
+}
+
+type shellSessionMgr struct {
+	lock     sync.Mutex
+	sessions map[string]*shellSession
+}
+
+var shellMgr = &shellSessionMgr{sessions: make(map[string]*shellSession)}
+
+type remoteShellHandler struct{}
+
+// Handle remoteShellHandler open, write, resize and close the pty-backed shell sessions requested by center
+func (h *remoteShellHandler) Handle(msg *model.Message) error {
+	var req shellRequest
+	if err := msg.ParseContent(&req); err != nil {
+		hwlog.RunLog.Errorf("parse remote shell request failed: %v", err)
+		return errors.New("parse remote shell request failed")
+	}
+	if !hexIdReg.MatchString(req.SessionId) {
+		hwlog.RunLog.Error("remote shell session id is invalid")
+		return errors.New("remote shell session id is invalid")
+	}
+
+	var err error
+	switch req.Action {
+	case shellActionOpen:
+		err = shellMgr.open(&req)
+	case shellActionInput:
+		err = shellMgr.write(req.SessionId, req.Data)
+	case shellActionResize:
+		err = shellMgr.resize(req.SessionId, req.Rows, req.Cols)
+	case shellActionClose:
+		err = shellMgr.close(req.SessionId, "closed by center")
+	default:
+		err = fmt.Errorf("action [%s] is not supported", req.Action)
+	}
+	if err != nil {
+		hwlog.RunLog.Errorf("remote shell session [%s] %s failed: %v", req.SessionId, req.Action, err)
+		if req.Action == shellActionOpen || !shellMgr.exist(req.SessionId) {
+			reportShellOutput(shellOutput{SessionId: req.SessionId, Closed: true, Reason: err.Error()})
+		}
+		return err
+	}
+	return nil
+}
+
+func (m *shellSessionMgr) open(req *shellRequest) error {
+	if err := checkRemoteShellEnabled(); err != nil {
+		return err
+	}
+	if req.Rows > maxShellTermSize || req.Cols > maxShellTermSize {
+		return fmt.Errorf("terminal size should not be more than %d", maxShellTermSize)
+	}
+	name, args, err := getShellCommand(req)
+	if err != nil {
+		return err
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if _, ok := m.sessions[req.SessionId]; ok {
+		return errors.New("session already exists")
+	}
+	if len(m.sessions) >= maxShellSessions {
+		return fmt.Errorf("the count of remote shell sessions reaches the limit %d", maxShellSessions)
+	}
+	session, err := startShellSession(req, name, args)
+	if err != nil {
+		return err
+	}
+	m.sessions[req.SessionId] = session
+	go m.serve(session)
+	hwlog.RunLog.Infof("remote shell session [%s] of %s [%s] started", req.SessionId, req.Type, name)
+	return nil
+}
+
+func (m *shellSessionMgr) get(id string) (*shellSession, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	session, ok := m.sessions[id]
+	if !ok {
+		return nil, errors.New("session does not exist")
+	}
+	return session, nil
+}
+
+func (m *shellSessionMgr) exist(id string) bool {
+	_, err := m.get(id)
+	return err == nil
+}
+
+func (m *shellSessionMgr) write(id string, data []byte) error {
+	if len(data) > maxShellInputLen {
+		return fmt.Errorf("input should not be longer than %d", maxShellInputLen)
+	}
+	session, err := m.get(id)
+	if err != nil {
+		return err
+	}
+	session.touch()
+	if _, err = session.pty.Write(data); err != nil {
+		return fmt.Errorf("write to pty failed: %v", err)
+	}
+	return nil
+}
+
+func (m *shellSessionMgr) resize(id string, rows, cols uint16) error {
+	if rows == 0 || cols == 0 || rows > maxShellTermSize || cols > maxShellTermSize {
+		return fmt.Errorf("terminal size should be in range [1, %d]", maxShellTermSize)
+	}
+	session, err := m.get(id)
+	if err != nil {
+		return err
+	}
+	return terminal.SetPtySize(session.pty, rows, cols)
+}
+
+func (m *shellSessionMgr) close(id, reason string) error {
+	session, err := m.get(id)
+	if err != nil {
+		return err
+	}
+	session.stop(reason)
+	return nil
+}
+
+// serve forwards the output of session to center in batches until the command exits, the session is closed
+// by center, or no input and output for a long time
+func (m *shellSessionMgr) serve(session *shellSession) {
+	outputCh := make(chan []byte)
+	go session.readOutput(outputCh)
+
+	ticker := time.NewTicker(shellFlushInterval)
+	defer ticker.Stop()
+	var pending []byte
+	for {
+		select {
+		case data, ok := <-outputCh:
+			if !ok {
+				if len(pending) > 0 {
+					reportShellOutput(shellOutput{SessionId: session.id, Data: pending})
+				}
+				m.finish(session)
+				return
+			}
+			session.touch()
+			pending = append(pending, data...)
+			if len(pending) >= maxShellOutputChunk {
+				reportShellOutput(shellOutput{SessionId: session.id, Data: pending})
+				pending = nil
+			}
+		case <-ticker.C:
+			if len(pending) > 0 {
+				reportShellOutput(shellOutput{SessionId: session.id, Data: pending})
+				pending = nil
+			}
+			if session.idleTime() > shellIdleTimeout {
+				session.stop("idle timeout")
+			}
+		}
+	}
+}
+
+func (m *shellSessionMgr) finish(session *shellSession) {
+	session.stop("")
+	waitErr := session.cmd.Wait()
+	reason := session.reason
+	if reason == "" {
+		reason = "command exited"
+		if waitErr != nil {
+			reason = waitErr.Error()
+		}
+	}
+
+	m.lock.Lock()
+	delete(m.sessions, session.id)
+	m.lock.Unlock()
+	reportShellOutput(shellOutput{SessionId: session.id, Closed: true, Reason: reason})
+	hwlog.RunLog.Infof("remote shell session [%s] finished: %s", session.id, reason)
+}
+
+func startShellSession(req *shellRequest, name string, args []string) (*shellSession, error) {
+	master, slave, err := terminal.OpenPty()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := slave.Close(); err != nil {
+			hwlog.RunLog.Warnf("close pty slave failed: %v", err)
+		}
+	}()
+	if req.Rows != 0 && req.Cols != 0 {
+		if err = terminal.SetPtySize(master, req.Rows, req.Cols); err != nil {
+			hwlog.RunLog.Warnf("set pty size failed: %v", err)
+		}
+	}
+
+	cmd := exec.Command(name, args...)
+	cmd.Env = shellEnv
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = slave, slave, slave
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true, Setctty: true}
+	if err = cmd.Start(); err != nil {
+		if closeErr := master.Close(); closeErr != nil {
+			hwlog.RunLog.Warnf("close pty master failed: %v", closeErr)
+		}
+		return nil, fmt.Errorf("start command failed: %v", err)
+	}
+	session := &shellSession{id: req.SessionId, cmd: cmd, pty: master}
+	session.touch()
+	return session, nil
+}
+
+func (s *shellSession) readOutput(outputCh chan<- []byte) {
+	defer close(outputCh)
+	buf := make([]byte, shellReadBufSize)
+	for {
+		n, err := s.pty.Read(buf)
+		if n > 0 {
+			data := make([]byte, n)
+			copy(data, buf[:n])
+			outputCh <- data
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// stop kills the whole process group of the session, closing pty interrupts the pending read
+func (s *shellSession) stop(reason string) {
+	s.closeOnce.Do(func() {
+		s.reason = reason
+		if s.cmd.Process != nil {
+			if err := syscall.Kill(-s.cmd.Process.Pid, syscall.SIGKILL); err != nil && err != syscall.ESRCH {
+				hwlog.RunLog.Warnf("kill remote shell session [%s] failed: %v", s.id, err)
+			}
+		}
+		if err := s.pty.Close(); err != nil {
+			hwlog.RunLog.Warnf("close pty of remote shell session [%s] failed: %v", s.id, err)
+		}
+	})
+}
+
+func (s *shellSession) touch() {
+	atomic.StoreInt64(&s.lastActive, time.Now().UnixNano())
+}
+
+func (s *shellSession) idleTime() time.Duration {
+	return time.Since(time.Unix(0, atomic.LoadInt64(&s.lastActive)))
+}
+
+func getShellCommand(req *shellRequest) (string, []string, error) {
+	if len(req.Command) > maxShellArgs {
+		return "", nil, fmt.Errorf("the count of command args should not be more than %d", maxShellArgs)
+	}
+	for _, arg := range req.Command {
+		if arg == "" || len(arg) > maxShellArgLen || strings.ContainsAny(arg, "\x00\n") {
+			return "", nil, errors.New("command arg is invalid")
+		}
+	}
+
+	switch req.Type {
+	case shellTypeHost:
+		if len(req.Command) == 0 {
+			return "", nil, errors.New("command is necessary for host shell")
+		}
+		rule, ok := hostShellAllowList[req.Command[0]]
+		if !ok {
+			return "", nil, fmt.Errorf("command [%s] is not allowed on host", req.Command[0])
+		}
+		if err := rule.check(req.Command[1:]); err != nil {
+			return "", nil, fmt.Errorf("args of command [%s] are not allowed: %v", req.Command[0], err)
+		}
+		if err := envutils.CheckCommandAllowedSugid(req.Command[0]); err != nil {
+			return "", nil, fmt.Errorf("check command failed: %v", err)
+		}
+		cmdPath, err := exec.LookPath(req.Command[0])
+		if err != nil {
+			return "", nil, fmt.Errorf("look up command failed: %v", err)
+		}
+		return cmdPath, append(append([]string{}, rule.fixedArgs...), req.Command[1:]...), nil
+	case shellTypeContainer:
+		if err := checkAppContainer(req.Container); err != nil {
+			return "", nil, err
+		}
+		command := req.Command
+		if len(command) == 0 {
+			command = []string{defaultContainerShell}
+		}
+		cmdPath, err := exec.LookPath(constants.DockerCmd)
+		if err != nil {
+			return "", nil, fmt.Errorf("look up docker failed: %v", err)
+		}
+		return cmdPath, append([]string{"exec", "-it", req.Container}, command...), nil
+	default:
+		return "", nil, fmt.Errorf("shell type [%s] is not supported", req.Type)
+	}
+}
+
+func (r hostCommandRule) check(args []string) error {
+	for i, arg := range args {
+		if _, ok := r.subCommands[arg]; ok && i == 0 {
+			continue
+		}
+		if i == 0 && len(r.subCommands) > 0 {
+			return fmt.Errorf("sub command [%s] is not allowed", arg)
+		}
+		if r.optionReg.MatchString(arg) {
+			continue
+		}
+		if r.allowValues && shellValueReg.MatchString(arg) {
+			continue
+		}
+		return fmt.Errorf("arg [%s] is not allowed", arg)
+	}
+	if len(args) == 0 && len(r.subCommands) > 0 {
+		return errors.New("sub command is necessary")
+	}
+	return nil
+}
+
+// checkRemoteShellEnabled remote shell is disabled by default, it should be enabled on edge explicitly
+func checkRemoteShellEnabled() error {
+	podConfig, err := config.LoadPodConfig()
+	if err != nil {
+		hwlog.RunLog.Errorf("load pod config failed: %v", err)
+		return errors.New("load pod config failed")
+	}
+	if !podConfig.RemoteShellEnabled {
+		return errors.New("remote shell is disabled on this node")
+	}
+	return nil
+}
+
+// checkAppContainer only the containers of applications deployed by kubelet can be entered, pause containers are not
+func checkAppContainer(container string) error {
+	if !containerNameReg.MatchString(container) {
+		return errors.New("container name is invalid")
+	}
+	name, err := envutils.RunCommand(constants.DockerCmd, envutils.DefCmdTimeoutSec, "inspect", "--format",
+		"{{.Name}}", container)
+	if err != nil {
+		hwlog.RunLog.Errorf("inspect container [%s] failed: %v", container, err)
+		return errors.New("container does not exist")
+	}
+	name = strings.TrimSpace(name)
+	if !strings.HasPrefix(name, appContainerPrefix) || strings.HasPrefix(name, "/"+podNamePrefix) {
+		return errors.New("container is not an application container")
+	}
+	return nil
+}
+
+func reportShellOutput(output shellOutput) {
+	msg, err := model.NewMessage()
+	if err != nil {
+		hwlog.RunLog.Errorf("create remote shell output message failed: %v", err)
+		return
+	}
+	msg.SetRouter(constants.ModEdgeOm, constants.InnerClient, constants.OptReport, constants.ResRemoteShellOutput)
+	if err = msg.FillContent(output, true); err != nil {
+		hwlog.RunLog.Errorf("fill remote shell output into content failed: %v", err)
+		return
+	}
+	if err = modulemgr.SendMessage(msg); err != nil {
+		hwlog.RunLog.Errorf("send remote shell output of session [%s] failed: %v", output.SessionId, err)
+	}
+}
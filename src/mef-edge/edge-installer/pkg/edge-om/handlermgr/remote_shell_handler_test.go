@@ -0,0 +1,197 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+//go:build MEFEdge_SDK
+
+// Package handlermgr for remote shell handler test
+package handlermgr
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/smartystreets/goconvey/convey"
+
+	"huawei.com/mindx/common/envutils"
+	"huawei.com/mindx/common/modulemgr/model"
+
+	"edge-installer/pkg/common/config"
+)
+
+const (
+	testSessionId   = "0123456789abcdef0123456789abcdef"
+	testWaitTimeout = 5 * time.Second
+)
+
+func newShellRequestMsg(req shellRequest) *model.Message {
+	msg, err := model.NewMessage()
+	if err != nil {
+		panic(err)
+	}
+	if err = msg.FillContent(req); err != nil {
+		panic(err)
+	}
+	return msg
+}
+
+func waitShellOutput(outputs <-chan shellOutput, match func(shellOutput) bool) bool {
+	timer := time.NewTimer(testWaitTimeout)
+	defer timer.Stop()
+	for {
+		select {
+		case output := <-outputs:
+			if match(output) {
+				return true
+			}
+		case <-timer.C:
+			return false
+		}
+	}
+}
+
+func TestGetShellCommand(t *testing.T) {
+	convey.Convey("host command should be in allow list", t, func() {
+		_, _, err := getShellCommand(&shellRequest{Type: shellTypeHost, Command: []string{"rm", "-rf", "/"}})
+		convey.So(err, convey.ShouldNotBeNil)
+		_, _, err = getShellCommand(&shellRequest{Type: shellTypeHost})
+		convey.So(err, convey.ShouldNotBeNil)
+		_, _, err = getShellCommand(&shellRequest{Type: "unknown", Command: []string{"df"}})
+		convey.So(err, convey.ShouldNotBeNil)
+		_, _, err = getShellCommand(&shellRequest{Type: shellTypeHost, Command: make([]string, maxShellArgs+1)})
+		convey.So(err, convey.ShouldNotBeNil)
+		_, _, err = getShellCommand(&shellRequest{Type: shellTypeHost, Command: []string{"df", "-h\n"}})
+		convey.So(err, convey.ShouldNotBeNil)
+	})
+
+	convey.Convey("allowed host command is run directly", t, func() {
+		patches := gomonkey.ApplyFuncReturn(envutils.CheckCommandAllowedSugid, nil).
+			ApplyFuncReturn(exec.LookPath, "/usr/bin/df", nil)
+		defer patches.Reset()
+		name, args, err := getShellCommand(&shellRequest{Type: shellTypeHost, Command: []string{"df", "-h"}})
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(name, convey.ShouldEqual, "/usr/bin/df")
+		convey.So(args, convey.ShouldResemble, []string{"-h"})
+	})
+
+	convey.Convey("all args of host command are checked and pager is disabled", t, func() {
+		patches := gomonkey.ApplyFuncReturn(envutils.CheckCommandAllowedSugid, nil).
+			ApplyFuncReturn(exec.LookPath, "/usr/bin/journalctl", nil)
+		defer patches.Reset()
+		_, args, err := getShellCommand(&shellRequest{Type: shellTypeHost,
+			Command: []string{"journalctl", "-u", "edgecore", "-n", "100"}})
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(args, convey.ShouldResemble, []string{"--no-pager", "-u", "edgecore", "-n", "100"})
+		for _, command := range [][]string{{"journalctl", "--vacuum-size=1"}, {"dmesg", "-C"}, {"dmesg", "-H"},
+			{"ss", "-K"}, {"npu-smi", "set", "-t", "reset"}, {"ping", "-f", "127.0.0.1"}, {"top"}} {
+			_, _, err = getShellCommand(&shellRequest{Type: shellTypeHost, Command: command})
+			convey.So(err, convey.ShouldNotBeNil)
+		}
+	})
+
+	convey.Convey("only application container can be entered", t, func() {
+		patches := gomonkey.ApplyFuncReturn(exec.LookPath, "/usr/bin/docker", nil).
+			ApplyFuncReturn(envutils.RunCommand, "/k8s_app_pod-1_default_0\n", nil)
+		defer patches.Reset()
+		_, _, err := getShellCommand(&shellRequest{Type: shellTypeContainer, Container: "-it"})
+		convey.So(err, convey.ShouldNotBeNil)
+		name, args, err := getShellCommand(&shellRequest{Type: shellTypeContainer, Container: "3f2a9c"})
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(name, convey.ShouldEqual, "/usr/bin/docker")
+		convey.So(args, convey.ShouldResemble, []string{"exec", "-it", "3f2a9c", defaultContainerShell})
+	})
+
+	convey.Convey("pause container can not be entered", t, func() {
+		patches := gomonkey.ApplyFuncReturn(envutils.RunCommand, "/k8s_POD_pod-1_default_0\n", nil)
+		defer patches.Reset()
+		_, _, err := getShellCommand(&shellRequest{Type: shellTypeContainer, Container: "3f2a9c"})
+		convey.So(err, convey.ShouldNotBeNil)
+	})
+}
+
+func TestRemoteShellHandler(t *testing.T) {
+	if _, err := os.Stat("/dev/ptmx"); err != nil {
+		t.Skip("pty is not supported")
+	}
+	outputs := make(chan shellOutput, maxShellSessions*maxShellSessions)
+	patches := gomonkey.ApplyFunc(reportShellOutput, func(output shellOutput) {
+		outputs <- output
+	})
+	defer patches.Reset()
+	handler := &remoteShellHandler{}
+
+	convey.Convey("session can not be opened when remote shell is disabled", t, func() {
+		cfgPatch := gomonkey.ApplyFuncReturn(config.LoadPodConfig, &config.PodConfig{}, nil)
+		defer cfgPatch.Reset()
+		err := handler.Handle(newShellRequestMsg(shellRequest{SessionId: testSessionId, Action: shellActionOpen,
+			Type: shellTypeHost, Command: []string{"df"}}))
+		convey.So(err, convey.ShouldNotBeNil)
+		convey.So(err.Error(), convey.ShouldContainSubstring, "disabled")
+	})
+
+	enabledCfg := &config.PodConfig{ContainerConfig: config.ContainerConfig{RemoteShellEnabled: true}}
+	cfgPatch := gomonkey.ApplyFuncReturn(config.LoadPodConfig, enabledCfg, nil)
+	defer cfgPatch.Reset()
+
+	convey.Convey("session id should be valid", t, func() {
+		err := handler.Handle(newShellRequestMsg(shellRequest{SessionId: "../id", Action: shellActionClose}))
+		convey.So(err, convey.ShouldNotBeNil)
+	})
+
+	convey.Convey("input is echoed by the session and session is closed by center", t, func() {
+		getCmdPatch := gomonkey.ApplyFuncReturn(getShellCommand, "/bin/cat", []string{}, nil)
+		defer getCmdPatch.Reset()
+		convey.So(handler.Handle(newShellRequestMsg(shellRequest{SessionId: testSessionId, Action: shellActionOpen,
+			Type: shellTypeHost, Rows: 24, Cols: 80})), convey.ShouldBeNil)
+		convey.So(handler.Handle(newShellRequestMsg(shellRequest{SessionId: testSessionId,
+			Action: shellActionResize, Rows: 40, Cols: 120})), convey.ShouldBeNil)
+		convey.So(handler.Handle(newShellRequestMsg(shellRequest{SessionId: testSessionId,
+			Action: shellActionInput, Data: []byte("hello\n")})), convey.ShouldBeNil)
+		convey.So(waitShellOutput(outputs, func(output shellOutput) bool {
+			return strings.Contains(string(output.Data), "hello")
+		}), convey.ShouldBeTrue)
+
+		convey.So(handler.Handle(newShellRequestMsg(shellRequest{SessionId: testSessionId,
+			Action: shellActionClose})), convey.ShouldBeNil)
+		convey.So(waitShellOutput(outputs, func(output shellOutput) bool {
+			return output.Closed && output.Reason == "closed by center"
+		}), convey.ShouldBeTrue)
+		convey.So(shellMgr.exist(testSessionId), convey.ShouldBeFalse)
+	})
+
+	convey.Convey("input to a closed session is rejected and reported", t, func() {
+		err := handler.Handle(newShellRequestMsg(shellRequest{SessionId: testSessionId,
+			Action: shellActionInput, Data: []byte("ls\n")}))
+		convey.So(err, convey.ShouldNotBeNil)
+		convey.So(waitShellOutput(outputs, func(output shellOutput) bool { return output.Closed }),
+			convey.ShouldBeTrue)
+	})
+
+	convey.Convey("session count is limited", t, func() {
+		getCmdPatch := gomonkey.ApplyFuncReturn(getShellCommand, "/bin/cat", []string{}, nil)
+		defer getCmdPatch.Reset()
+		shellMgr.lock.Lock()
+		for i := 0; i < maxShellSessions; i++ {
+			shellMgr.sessions[strings.Repeat(string(rune('a'+i)), len(testSessionId))] = &shellSession{}
+		}
+		shellMgr.lock.Unlock()
+		defer func() {
+			shellMgr.lock.Lock()
+			shellMgr.sessions = make(map[string]*shellSession)
+			shellMgr.lock.Unlock()
+		}()
+		err := handler.Handle(newShellRequestMsg(shellRequest{SessionId: testSessionId, Action: shellActionOpen,
+			Type: shellTypeHost}))
+		convey.So(err, convey.ShouldNotBeNil)
+		convey.So(err.Error(), convey.ShouldContainSubstring, "limit")
+	})
+}
@@ -91,6 +91,10 @@ func (ch *saveCertHandlerSdk) processCert() error {
 
 func updateCert(res util.ClientCertResp) error {
 	var err error
+	if err = checkCertPolicy(res); err != nil {
+		hwlog.RunLog.Errorf("check %s cert with cert policy failed, error: %v", res.CertName, err)
+		return errors.New("check cert with cert policy failed")
+	}
 	if err = saveCaContent(res); err != nil {
 		hwlog.RunLog.Errorf("save cert content failed, err:%v", err)
 		return err
@@ -116,6 +120,23 @@ func updateCert(res util.ClientCertResp) error {
 	return fmt.Errorf("image mapping config %s failed, error: %v", res.ImageAddress, err)
 }
 
+// checkCertPolicy evaluates the image or software root ca received from center with the cert policy of edge
+func checkCertPolicy(res util.ClientCertResp) error {
+	configPathMgr, err := path.GetConfigPathMgr()
+	if err != nil {
+		return fmt.Errorf("get config path manager failed: %v", err)
+	}
+	policy, err := x509.LoadCertPolicy(configPathMgr.GetCertPolicyPath())
+	if err != nil {
+		return err
+	}
+	caMgr, err := x509.NewCaChainMgr([]byte(res.CertContent))
+	if err != nil {
+		return fmt.Errorf("parse cert content failed: %v", err)
+	}
+	return policy.CheckCerts(caMgr.GetCerts()).Err()
+}
+
 func deleteCert(res util.ClientCertResp) error {
 	// delete root ca
 	certDir, err := path.GetCompSpecificDir(constants.ConfigCertPathName)
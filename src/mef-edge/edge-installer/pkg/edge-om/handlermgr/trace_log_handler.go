@@ -0,0 +1,272 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+//go:build MEFEdge_SDK
+
+// Package handlermgr
+package handlermgr
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	"huawei.com/mindx/common/fileutils"
+	"huawei.com/mindx/common/hwlog"
+	"huawei.com/mindx/common/modulemgr"
+	"huawei.com/mindx/common/modulemgr/model"
+
+	"edge-installer/pkg/common/constants"
+	"edge-installer/pkg/common/path"
+)
+
+const (
+	maxTraceLogLines    = 200
+	maxTraceLogLineLen  = 1024
+	maxTraceLogFileSize = 64 * constants.MB
+	maxTraceLogBackups  = 32
+	traceLogChunkSize   = 64 * constants.KB
+	gzipExt             = ".gz"
+)
+
+var (
+	traceIdReg = regexp.MustCompile("^" + constants.UUIDRegex + "$")
+	// the components whose run logs are printed with trace id
+	traceLogComponents = []string{constants.EdgeMain, constants.EdgeOm}
+)
+
+// traceLogRequest request from center to collect the log lines of a trace
+type traceLogRequest struct {
+	QueryId string `json:"queryId"`
+	TraceId string `json:"traceId"`
+}
+
+// traceLogData log lines of a trace reported to center, each line is prefixed with its component
+type traceLogData struct {
+	QueryId   string   `json:"queryId"`
+	Lines     []string `json:"lines"`
+	Truncated bool     `json:"truncated,omitempty"`
+	Error     string   `json:"error,omitempty"`
+}
+
+type traceLogHandler struct {
+	running int32
+}
+
+// Handle traceLogHandler searches the run logs of edge components for the trace, the result is reported
+// asynchronously since reading logs may take a while
+func (h *traceLogHandler) Handle(msg *model.Message) error {
+	var req traceLogRequest
+	if err := msg.ParseContent(&req); err != nil {
+		hwlog.RunLog.Errorf("parse trace log request failed: %v", err)
+		return errors.New("parse trace log request failed")
+	}
+	if !hexIdReg.MatchString(req.QueryId) || !traceIdReg.MatchString(req.TraceId) {
+		hwlog.RunLog.Error("trace log request is invalid")
+		return errors.New("trace log request is invalid")
+	}
+	if !atomic.CompareAndSwapInt32(&h.running, 0, 1) {
+		reportTraceLog(traceLogData{QueryId: req.QueryId, Error: "trace log handler is busy"})
+		return errors.New("trace log handler is busy")
+	}
+
+	go func() {
+		defer atomic.StoreInt32(&h.running, 0)
+		hwlog.RunLog.Infof("start collect log lines of trace [%s]", req.TraceId)
+		reportTraceLog(collectTraceLog(req))
+	}()
+	return nil
+}
+
+func collectTraceLog(req traceLogRequest) traceLogData {
+	data := traceLogData{QueryId: req.QueryId, Lines: []string{}}
+	var errs []string
+	for _, component := range traceLogComponents {
+		if len(data.Lines) >= maxTraceLogLines {
+			data.Truncated = true
+			break
+		}
+		if err := searchTraceLog(component, req.TraceId, &data); err != nil {
+			hwlog.RunLog.Errorf("search run log of [%s] failed: %v", component, err)
+			errs = append(errs, fmt.Sprintf("search run log of [%s] failed", component))
+		}
+	}
+	data.Error = strings.Join(errs, "; ")
+	return data
+}
+
+// searchTraceLog scans the run log of the component and its rotated backups from the newest line backward, so
+// that the latest lines of the trace are kept when there are more than the limit. The lines found are appended
+// to data in chronological order.
+func searchTraceLog(component, traceId string, data *traceLogData) error {
+	logDir, backupDir, err := path.GetCompLogDirs(component)
+	if err != nil {
+		return err
+	}
+	limit := maxTraceLogLines - len(data.Lines)
+	var lines []string
+	match := func(line []byte) bool {
+		if !bytes.Contains(line, []byte(traceId)) {
+			return true
+		}
+		if len(lines) >= limit {
+			data.Truncated = true
+			return false
+		}
+		if len(line) > maxTraceLogLineLen {
+			line = line[:maxTraceLogLineLen]
+		}
+		lines = append(lines, fmt.Sprintf("[%s] %s", component, line))
+		return true
+	}
+	for _, logFile := range listTraceLogFiles(logDir, backupDir) {
+		if err = scanLogFileBackward(logFile, match); err != nil {
+			return fmt.Errorf("scan log file [%s] failed: %v", filepath.Base(logFile), err)
+		}
+		if data.Truncated {
+			break
+		}
+	}
+	for i := len(lines) - 1; i >= 0; i-- {
+		data.Lines = append(data.Lines, lines[i])
+	}
+	return nil
+}
+
+// listTraceLogFiles returns the run log and its backups rotated by hwlog, from the newest to the oldest
+func listTraceLogFiles(logDir, backupDir string) []string {
+	logName := fmt.Sprintf("%s_%s", filepath.Base(logDir), constants.RunLogFile)
+	files := []string{filepath.Join(logDir, logName)}
+	if backupDir == "" {
+		backupDir = logDir
+	}
+	reader, dirEntries, err := fileutils.ReadDir(backupDir)
+	if err != nil {
+		hwlog.RunLog.Warnf("read log backup dir failed: %v", err)
+		return files
+	}
+	defer fileutils.CloseFile(reader)
+	// backup is named as <name>-<timestamp><ext>[.gz], the timestamp sorts in the order of time
+	prefix := strings.TrimSuffix(logName, filepath.Ext(logName)) + "-"
+	var backups []string
+	for _, dirEntry := range dirEntries {
+		name := dirEntry.Name()
+		if dirEntry.Type().IsRegular() && strings.HasPrefix(name, prefix) &&
+			(strings.HasSuffix(name, filepath.Ext(logName)) || strings.HasSuffix(name, filepath.Ext(logName)+gzipExt)) {
+			backups = append(backups, name)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(backups)))
+	if len(backups) > maxTraceLogBackups {
+		backups = backups[:maxTraceLogBackups]
+	}
+	for _, name := range backups {
+		files = append(files, filepath.Join(backupDir, name))
+	}
+	return files
+}
+
+// scanLogFileBackward calls fn with the lines of the last maxTraceLogFileSize bytes of the log file from the last
+// line to the first one until fn returns false, a compressed backup is decompressed into memory first
+func scanLogFileBackward(logFile string, fn func(line []byte) bool) error {
+	file, err := fileutils.OpenFile(logFile, os.O_RDONLY, constants.Mode400)
+	if err != nil {
+		return err
+	}
+	defer fileutils.CloseFile(file)
+	if strings.HasSuffix(logFile, gzipExt) {
+		reader, err := gzip.NewReader(file)
+		if err != nil {
+			return err
+		}
+		defer reader.Close()
+		content, err := io.ReadAll(io.LimitReader(reader, maxTraceLogFileSize+1))
+		if err != nil {
+			return err
+		}
+		if len(content) > maxTraceLogFileSize {
+			return errors.New("decompressed log file is too large")
+		}
+		return scanLinesBackward(bytes.NewReader(content), int64(len(content)), fn)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	offset := info.Size() - maxTraceLogFileSize
+	if offset < 0 {
+		offset = 0
+	}
+	return scanLinesBackward(io.NewSectionReader(file, offset, info.Size()-offset), info.Size()-offset, fn)
+}
+
+// scanLinesBackward reads r from the end in chunks and calls fn with each line from the last to the first until fn
+// returns false, lines longer than a chunk are skipped so that the memory used is bounded
+func scanLinesBackward(r io.ReaderAt, size int64, fn func(line []byte) bool) error {
+	buf := make([]byte, traceLogChunkSize)
+	// rest is the head of the chunk read previously, which is the tail of a line not finished yet
+	var rest []byte
+	overlong := false
+	for end := size; end > 0; {
+		start := end - traceLogChunkSize
+		if start < 0 {
+			start = 0
+		}
+		n, err := r.ReadAt(buf[:end-start], start)
+		if err != nil && err != io.EOF {
+			return err
+		}
+		end = start
+		chunk := append(buf[:n:n], rest...)
+		for idx := bytes.LastIndexByte(chunk, '\n'); idx >= 0; idx = bytes.LastIndexByte(chunk, '\n') {
+			line := chunk[idx+1:]
+			chunk = chunk[:idx]
+			if overlong {
+				overlong = false
+				continue
+			}
+			if len(line) > 0 && !fn(line) {
+				return nil
+			}
+		}
+		// copy the head out since buf is read into again
+		rest = append([]byte{}, chunk...)
+		if len(rest) > traceLogChunkSize {
+			rest, overlong = nil, true
+		}
+	}
+	if len(rest) > 0 && !overlong {
+		fn(rest)
+	}
+	return nil
+}
+
+func reportTraceLog(data traceLogData) {
+	msg, err := model.NewMessage()
+	if err != nil {
+		hwlog.RunLog.Errorf("create trace log message failed: %v", err)
+		return
+	}
+	msg.SetRouter(constants.ModEdgeOm, constants.InnerClient, constants.OptReport, constants.ResTraceLogData)
+	if err = msg.FillContent(data, true); err != nil {
+		hwlog.RunLog.Errorf("fill trace log into content failed: %v", err)
+		return
+	}
+	if err = modulemgr.SendMessage(msg); err != nil {
+		hwlog.RunLog.Errorf("send log lines of trace query [%s] failed: %v", data.QueryId, err)
+	}
+}
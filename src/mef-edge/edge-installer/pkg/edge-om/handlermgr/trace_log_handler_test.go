@@ -0,0 +1,208 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+//go:build MEFEdge_SDK
+
+// Package handlermgr for trace log handler test
+package handlermgr
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/smartystreets/goconvey/convey"
+
+	"huawei.com/mindx/common/modulemgr/model"
+
+	"edge-installer/pkg/common/constants"
+	"edge-installer/pkg/common/path"
+)
+
+const testTraceId = "5f0c2a1e-7b3d-4c8e-9a6f-1d2e3f4a5b6c"
+
+func newTraceLogRequestMsg(req traceLogRequest) *model.Message {
+	msg, err := model.NewMessage()
+	if err != nil {
+		panic(err)
+	}
+	if err = msg.FillContent(req); err != nil {
+		panic(err)
+	}
+	return msg
+}
+
+func prepareTraceLogFile(component string, lines []string) string {
+	logDir := filepath.Join(os.TempDir(), "trace_log_test", component)
+	if err := os.MkdirAll(logDir, constants.Mode700); err != nil {
+		panic(err)
+	}
+	logFile := filepath.Join(logDir, fmt.Sprintf("%s_%s", component, constants.RunLogFile))
+	if err := os.WriteFile(logFile, []byte(strings.Join(lines, "\n")), constants.Mode600); err != nil {
+		panic(err)
+	}
+	return logDir
+}
+
+func prepareTraceLogBackup(logDir, timestamp string, lines []string, compress bool) {
+	name := fmt.Sprintf("%s_run-%s.log", filepath.Base(logDir), timestamp)
+	content := []byte(strings.Join(lines, "\n") + "\n")
+	if compress {
+		var buf bytes.Buffer
+		writer := gzip.NewWriter(&buf)
+		if _, err := writer.Write(content); err != nil {
+			panic(err)
+		}
+		if err := writer.Close(); err != nil {
+			panic(err)
+		}
+		name, content = name+gzipExt, buf.Bytes()
+	}
+	if err := os.WriteFile(filepath.Join(logDir, name), content, constants.Mode600); err != nil {
+		panic(err)
+	}
+}
+
+func traceLine(index int) string {
+	return fmt.Sprintf(`[INFO] 12    msgconv/proxy.go:160    {<nil>}-{"%s"} line %d`, testTraceId, index)
+}
+
+func TestCollectTraceLog(t *testing.T) {
+	mainLogDir := prepareTraceLogFile(constants.EdgeMain, []string{
+		fmt.Sprintf(`[INFO] 12    msgconv/proxy.go:160    {<nil>}-{"%s"} forward message`, testTraceId),
+		`[INFO] 12    edgehub/init.go:106    [routeToCenter]`,
+	})
+	omLogDir := prepareTraceLogFile(constants.EdgeOm, []string{
+		fmt.Sprintf(`[INFO] 15    modulemgr/msg_handler.go:117    {<nil>}-{"%s"} receive msg`, testTraceId),
+	})
+	defer os.RemoveAll(filepath.Dir(mainLogDir))
+
+	convey.Convey("log lines of trace are collected from components", t, func() {
+		patches := gomonkey.ApplyFuncSeq(path.GetCompLogDirs, []gomonkey.OutputCell{
+			{Values: gomonkey.Params{mainLogDir, "", nil}},
+			{Values: gomonkey.Params{omLogDir, "", nil}},
+		})
+		defer patches.Reset()
+		data := collectTraceLog(traceLogRequest{QueryId: testSessionId, TraceId: testTraceId})
+		convey.So(data.QueryId, convey.ShouldEqual, testSessionId)
+		convey.So(data.Error, convey.ShouldBeEmpty)
+		convey.So(len(data.Lines), convey.ShouldEqual, 2)
+		convey.So(data.Lines[0], convey.ShouldStartWith, "[edge_main] ")
+		convey.So(data.Lines[1], convey.ShouldStartWith, "[edge_om] ")
+	})
+
+	convey.Convey("the failure of one component is reported", t, func() {
+		patches := gomonkey.ApplyFuncSeq(path.GetCompLogDirs, []gomonkey.OutputCell{
+			{Values: gomonkey.Params{"", "", errors.New("read link failed")}},
+			{Values: gomonkey.Params{omLogDir, "", nil}},
+		})
+		defer patches.Reset()
+		data := collectTraceLog(traceLogRequest{QueryId: testSessionId, TraceId: testTraceId})
+		convey.So(data.Error, convey.ShouldContainSubstring, constants.EdgeMain)
+		convey.So(len(data.Lines), convey.ShouldEqual, 1)
+	})
+}
+
+func TestSearchTraceLogBackward(t *testing.T) {
+	const currentLines = maxTraceLogLines - 10
+	var current []string
+	for i := 0; i < currentLines; i++ {
+		current = append(current, traceLine(i+2))
+	}
+	logDir := prepareTraceLogFile(constants.EdgeMain, current)
+	prepareTraceLogBackup(logDir, "2025-01-02T00-00-00.000", []string{traceLine(1)}, false)
+	prepareTraceLogBackup(logDir, "2025-01-01T00-00-00.000", []string{traceLine(0)}, true)
+	defer os.RemoveAll(filepath.Dir(logDir))
+
+	convey.Convey("rotated logs are listed from the newest to the oldest", t, func() {
+		files := listTraceLogFiles(logDir, logDir)
+		convey.So(len(files), convey.ShouldEqual, 3)
+		convey.So(files[1], convey.ShouldEndWith, "2025-01-02T00-00-00.000.log")
+		convey.So(files[2], convey.ShouldEndWith, "2025-01-01T00-00-00.000.log.gz")
+	})
+
+	convey.Convey("the latest lines are kept in chronological order when exceeding the limit", t, func() {
+		patches := gomonkey.ApplyFuncReturn(path.GetCompLogDirs, logDir, logDir, nil)
+		defer patches.Reset()
+		data := traceLogData{Lines: make([]string, maxTraceLogLines-currentLines+1)}
+		convey.So(searchTraceLog(constants.EdgeMain, testTraceId, &data), convey.ShouldBeNil)
+		convey.So(data.Truncated, convey.ShouldBeTrue)
+		convey.So(len(data.Lines), convey.ShouldEqual, maxTraceLogLines)
+		convey.So(data.Lines[maxTraceLogLines-currentLines+1], convey.ShouldEndWith, "line 3")
+		convey.So(data.Lines[maxTraceLogLines-1], convey.ShouldEndWith, fmt.Sprintf("line %d", currentLines+1))
+	})
+
+	convey.Convey("lines in rotated logs are found", t, func() {
+		patches := gomonkey.ApplyFuncReturn(path.GetCompLogDirs, logDir, logDir, nil)
+		defer patches.Reset()
+		data := traceLogData{}
+		convey.So(searchTraceLog(constants.EdgeMain, testTraceId, &data), convey.ShouldBeNil)
+		convey.So(data.Truncated, convey.ShouldBeFalse)
+		convey.So(len(data.Lines), convey.ShouldEqual, currentLines+2)
+		convey.So(data.Lines[0], convey.ShouldEndWith, "line 0")
+		convey.So(data.Lines[1], convey.ShouldEndWith, "line 1")
+	})
+}
+
+func TestScanLinesBackward(t *testing.T) {
+	collect := func(content string) []string {
+		var lines []string
+		err := scanLinesBackward(strings.NewReader(content), int64(len(content)), func(line []byte) bool {
+			lines = append(lines, string(line))
+			return true
+		})
+		convey.So(err, convey.ShouldBeNil)
+		return lines
+	}
+
+	convey.Convey("lines across chunks are read from the last to the first", t, func() {
+		long := strings.Repeat("a", traceLogChunkSize-1)
+		convey.So(collect("first\n"+long+"\nlast\n"), convey.ShouldResemble, []string{"last", long, "first"})
+	})
+
+	convey.Convey("lines longer than a chunk are skipped", t, func() {
+		overlong := strings.Repeat("a", 3*traceLogChunkSize)
+		convey.So(collect("first\n"+overlong+"\nlast"), convey.ShouldResemble, []string{"last", "first"})
+		convey.So(collect(overlong+"\nlast"), convey.ShouldResemble, []string{"last"})
+	})
+}
+
+func TestTraceLogHandler(t *testing.T) {
+	reported := make(chan traceLogData, 1)
+	patches := gomonkey.ApplyFunc(reportTraceLog, func(data traceLogData) {
+		reported <- data
+	}).ApplyFuncReturn(collectTraceLog, traceLogData{QueryId: testSessionId})
+	defer patches.Reset()
+	handler := &traceLogHandler{}
+
+	convey.Convey("invalid trace log request is rejected", t, func() {
+		err := handler.Handle(newTraceLogRequestMsg(traceLogRequest{QueryId: testSessionId, TraceId: "x\ny"}))
+		convey.So(err, convey.ShouldNotBeNil)
+		err = handler.Handle(newTraceLogRequestMsg(traceLogRequest{QueryId: "../id", TraceId: testTraceId}))
+		convey.So(err, convey.ShouldNotBeNil)
+	})
+
+	convey.Convey("result of trace log request is reported", t, func() {
+		err := handler.Handle(newTraceLogRequestMsg(traceLogRequest{QueryId: testSessionId, TraceId: testTraceId}))
+		convey.So(err, convey.ShouldBeNil)
+		select {
+		case data := <-reported:
+			convey.So(data.QueryId, convey.ShouldEqual, testSessionId)
+		case <-time.After(testWaitTimeout):
+			convey.So("timeout", convey.ShouldBeEmpty)
+		}
+	})
+}
@@ -22,8 +22,13 @@ var registerInfoListSdk = []*modulemgr.RegisterModuleInfo{
 	{MsgOpt: constants.OptReport, MsgRes: constants.InnerSoftwareVersion, ModuleName: constants.UpgradeManagerName},
 	{MsgOpt: constants.OptPost, MsgRes: constants.InnerSoftwareVerification, ModuleName: constants.UpgradeManagerName},
 	{MsgOpt: constants.OptPost, MsgRes: constants.ResUpgradeInfo, ModuleName: constants.UpgradeManagerName},
+	{MsgOpt: constants.OptPost, MsgRes: constants.ResRollbackInfo, ModuleName: constants.UpgradeManagerName},
+	{MsgOpt: constants.OptPost, MsgRes: constants.ResPreflight, ModuleName: constants.UpgradeManagerName},
 	{MsgOpt: constants.OptPost, MsgRes: constants.ResPackLogRequest, ModuleName: constants.ModEdgeOm},
 	{MsgOpt: constants.OptPost, MsgRes: constants.ResDownloadCert, ModuleName: constants.ModEdgeOm},
+	{MsgOpt: constants.OptPost, MsgRes: constants.ResRemoteShell, ModuleName: constants.ModEdgeOm},
+	{MsgOpt: constants.OptPost, MsgRes: constants.ResPortForward, ModuleName: constants.ModEdgeOm},
+	{MsgOpt: constants.OptPost, MsgRes: constants.ResTraceLog, ModuleName: constants.ModEdgeOm},
 	{MsgOpt: constants.OptUpdate, MsgRes: constants.InnerPrepareDir, ModuleName: constants.ModEdgeOm},
 	{MsgOpt: constants.OptGet, MsgRes: constants.InnerCert, ModuleName: constants.ModEdgeOm},
 }
@@ -18,6 +18,8 @@ import (
 	"huawei.com/mindx/common/modulemgr/handler"
 
 	"edge-installer/pkg/common/constants"
+	"edge-installer/pkg/edge-om/upgrade/handlers/preflight"
+	"edge-installer/pkg/edge-om/upgrade/handlers/rollback"
 	"edge-installer/pkg/edge-om/upgrade/handlers/upgrade"
 	"edge-installer/pkg/edge-om/upgrade/handlers/verification"
 	"edge-installer/pkg/edge-om/upgrade/reporter"
@@ -28,6 +30,8 @@ var regOnce sync.Once
 var registerInfoList = []handler.RegisterInfo{
 	{MsgOpt: constants.OptPost, MsgRes: constants.InnerSoftwareVerification, Handler: new(verification.Handler)},
 	{MsgOpt: constants.OptPost, MsgRes: constants.ResUpgradeInfo, Handler: new(upgrade.Handler)},
+	{MsgOpt: constants.OptPost, MsgRes: constants.ResRollbackInfo, Handler: new(rollback.Handler)},
+	{MsgOpt: constants.OptPost, MsgRes: constants.ResPreflight, Handler: new(preflight.Handler)},
 	{MsgOpt: constants.OptReport, MsgRes: constants.InnerSoftwareVersion, Handler: new(reporter.Handler)},
 }
 
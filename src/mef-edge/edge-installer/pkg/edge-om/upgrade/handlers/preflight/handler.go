@@ -0,0 +1,88 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+//go:build MEFEdge_SDK
+
+// Package preflight this file for preflight check handler, the environment is checked before upgrading
+package preflight
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	"huawei.com/mindx/common/hwlog"
+	"huawei.com/mindx/common/modulemgr"
+	"huawei.com/mindx/common/modulemgr/model"
+
+	"edge-installer/pkg/common/constants"
+	"edge-installer/pkg/common/path"
+	"edge-installer/pkg/common/util"
+	"edge-installer/pkg/installer/preupgrade/tasks"
+)
+
+var (
+	checkIdReg      = regexp.MustCompile("^[a-f0-9]{32}$")
+	versionReg      = regexp.MustCompile(`^[a-zA-Z0-9]([-_.a-zA-Z0-9]{0,62}[a-zA-Z0-9])?$`)
+	innerVersionReg = regexp.MustCompile("^([0-9.]{1,16})?$")
+)
+
+// Handler preflight check handler
+type Handler struct{}
+
+// Handle preflight handler entry, the checks of preupgrade are run and the results are reported to center
+func (h *Handler) Handle(msg *model.Message) error {
+	hwlog.RunLog.Info("start to handle preflight check")
+	var req util.PreflightRequest
+	if err := msg.ParseContent(&req); err != nil {
+		hwlog.RunLog.Error("convert request param failed")
+		return err
+	}
+	if err := checkRequest(req); err != nil {
+		hwlog.RunLog.Errorf("preflight request is invalid: %v", err)
+		return err
+	}
+	installDir, err := path.GetInstallDir()
+	if err != nil {
+		return fmt.Errorf("get install dir failed, %v", err)
+	}
+	report := util.PreflightReport{CheckId: req.CheckId}
+	report.CurrentVersion, report.Items = tasks.NewPreflightCheck(installDir, req).Run()
+	if err = reportResult(report); err != nil {
+		hwlog.RunLog.Errorf("report preflight result failed: %v", err)
+		return err
+	}
+	hwlog.RunLog.Info("handle preflight check success")
+	return nil
+}
+
+func checkRequest(req util.PreflightRequest) error {
+	if !checkIdReg.MatchString(req.CheckId) {
+		return errors.New("check id is invalid")
+	}
+	if req.SoftwareName != constants.MEFEdgeName {
+		return fmt.Errorf("unknown software %s", req.SoftwareName)
+	}
+	if !versionReg.MatchString(req.Version) || !innerVersionReg.MatchString(req.InnerVersion) {
+		return errors.New("version is invalid")
+	}
+	return nil
+}
+
+func reportResult(report util.PreflightReport) error {
+	msg, err := model.NewMessage()
+	if err != nil {
+		return fmt.Errorf("create message failed: %v", err)
+	}
+	msg.SetRouter(constants.ModEdgeOm, constants.InnerClient, constants.OptReport, constants.ResPreflightResult)
+	if err = msg.FillContent(report, true); err != nil {
+		return fmt.Errorf("fill content failed: %v", err)
+	}
+	return modulemgr.SendMessage(msg)
+}
@@ -0,0 +1,73 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+//go:build MEFEdge_SDK
+
+// Package rollback this file for rolling back automatically when the effected version can not connect to center
+package rollback
+
+import (
+	"context"
+	"time"
+
+	"huawei.com/mindx/common/fileutils"
+	"huawei.com/mindx/common/hwlog"
+
+	"edge-installer/pkg/common/config"
+	"edge-installer/pkg/common/path"
+	"edge-installer/pkg/edge-om/common/cloudconnect"
+)
+
+const guardCheckInterval = 5 * time.Second
+
+// WatchRollbackGuard waits the effected version to connect to center, the software is rolled back to the inactive
+// version if the connection is not ready before the deadline of the rollback guard
+func WatchRollbackGuard(ctx context.Context) {
+	configPathMgr, err := path.GetConfigPathMgr()
+	if err != nil {
+		hwlog.RunLog.Errorf("get config path manager failed, error: %v", err)
+		return
+	}
+	guardPath := configPathMgr.GetRollbackGuardPath()
+	if !fileutils.IsExist(guardPath) {
+		return
+	}
+	guard, err := config.LoadRollbackGuard(guardPath)
+	if err != nil {
+		hwlog.RunLog.Errorf("load rollback guard failed, error: %v", err)
+		return
+	}
+	hwlog.RunLog.Infof("version [%s] is effected, waiting for connecting to center", guard.Version)
+
+	ticker := time.NewTicker(guardCheckInterval)
+	defer ticker.Stop()
+	for {
+		if cloudconnect.GetCloudConnectStatus() {
+			if err = fileutils.DeleteFile(guardPath); err != nil {
+				hwlog.RunLog.Errorf("remove rollback guard failed, error: %v", err)
+				return
+			}
+			hwlog.RunLog.Infof("version [%s] connects to center, rollback guard is cleared", guard.Version)
+			return
+		}
+		if guard.IsExpired() {
+			hwlog.RunLog.Errorf("version [%s] does not connect to center in time, roll back to version [%s]",
+				guard.Version, guard.PrevVersion)
+			if err = Rollback(); err != nil {
+				hwlog.RunLog.Errorf("auto rollback failed, error: %v", err)
+			}
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
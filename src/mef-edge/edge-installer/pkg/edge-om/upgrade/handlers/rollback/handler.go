@@ -0,0 +1,78 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+//go:build MEFEdge_SDK
+
+// Package rollback this file for rollback software handler
+package rollback
+
+import (
+	"errors"
+	"fmt"
+
+	"huawei.com/mindx/common/hwlog"
+	"huawei.com/mindx/common/modulemgr/model"
+
+	"edge-installer/pkg/common/constants"
+	"edge-installer/pkg/common/path"
+	"edge-installer/pkg/common/path/pathmgr"
+	"edge-installer/pkg/common/util"
+	"edge-installer/pkg/installer/upgrade/flows"
+)
+
+// Handler rollback software handler
+type Handler struct{}
+
+// Handle rollback handler entry, the software is rolled back to the inactive version as center requests
+func (h *Handler) Handle(msg *model.Message) error {
+	hwlog.RunLog.Info("start to handle rollback software")
+	var info util.SoftwareUpdateInfo
+	if err := msg.ParseContent(&info); err != nil {
+		hwlog.RunLog.Error("convert request param failed")
+		return err
+	}
+	if info.SoftwareName != constants.MEFEdgeName {
+		hwlog.RunLog.Errorf("unknown software %s", info.SoftwareName)
+		return fmt.Errorf("unknown software %s", info.SoftwareName)
+	}
+	if err := Rollback(); err != nil {
+		hwlog.RunLog.Errorf("rollback software[%s] failed, error: %v", info.SoftwareName, err)
+		return err
+	}
+	hwlog.RunLog.Info("handle rollback software success")
+	return nil
+}
+
+// Rollback roll back the software to the inactive version by the rollback flow
+func Rollback() error {
+	processFlag := util.FlagLockInstance(constants.FlagPath, constants.ProcessFlag, constants.RollbackMode)
+	if err := processFlag.Lock(); err != nil {
+		return errors.New("lock rollback failed, there may be another process processing the upgrade")
+	}
+	defer func() {
+		if err := processFlag.Unlock(); err != nil {
+			hwlog.RunLog.Warnf("unlock rollback failed, %v", err)
+		}
+	}()
+
+	installRootDir, err := path.GetInstallRootDir()
+	if err != nil {
+		return fmt.Errorf("get install root dir failed, error: %v", err)
+	}
+	logRootDir, err := path.GetLogRootDir(installRootDir)
+	if err != nil {
+		return fmt.Errorf("get log root dir failed, error: %v", err)
+	}
+	logBackupRootDir, err := path.GetLogBackupRootDir(installRootDir)
+	if err != nil {
+		return fmt.Errorf("get log backup root dir failed, error: %v", err)
+	}
+	pathMgr := pathmgr.NewPathMgr(installRootDir, "", logRootDir, logBackupRootDir)
+	return flows.NewRollbackFlow(pathMgr).RunTasks()
+}
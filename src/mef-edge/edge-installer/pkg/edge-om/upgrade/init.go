@@ -25,6 +25,7 @@ import (
 	"edge-installer/pkg/common/constants"
 	"edge-installer/pkg/common/path"
 	"edge-installer/pkg/edge-om/upgrade/handlers"
+	"edge-installer/pkg/edge-om/upgrade/handlers/rollback"
 	"edge-installer/pkg/edge-om/upgrade/reporter"
 )
 
@@ -81,6 +82,7 @@ func (u *upgradeMgr) Start() {
 
 	time.Sleep(startWaitTime)
 	go reporter.ReportSoftwareVersion(softwareVersionReportCount)
+	go rollback.WatchRollbackGuard(u.ctx)
 	for {
 		select {
 		case <-u.ctx.Done():
@@ -17,7 +17,7 @@ import (
 	"fmt"
 	"net"
 	"strconv"
-	"strings"
+	"time"
 
 	"huawei.com/mindx/common/hwlog"
 	"huawei.com/mindx/common/utils"
@@ -27,6 +27,8 @@ import (
 	"edge-installer/pkg/common/path"
 )
 
+const testConnTimeout = 5 * time.Second
+
 // SetNodeIPToEdgeCore set nodeIP to edge core configuration file
 func SetNodeIPToEdgeCore() error {
 	configPathMgr, err := path.GetConfigPathMgr()
@@ -64,10 +66,23 @@ func getNodeIP(edgeOmConfigDir string) (string, error) {
 		return "", nil
 	}
 
-	address := net.JoinHostPort(netConfig.IP, strconv.Itoa(netConfig.Port))
-	conn, err := net.Dial("tcp", address)
+	var lastErr error
+	// the node ip is the local address routed to MEF Center, the first reachable center address is used
+	for _, addr := range netConfig.GetCenterAddrs() {
+		ip, err := getLocalIPTo(net.JoinHostPort(addr, strconv.Itoa(netConfig.Port)))
+		if err == nil {
+			return ip, nil
+		}
+		hwlog.RunLog.Warnf("test connect to center [%s] failed: %v", addr, err)
+		lastErr = err
+	}
+	return "", fmt.Errorf("test connect to server failed: %v", lastErr)
+}
+
+func getLocalIPTo(address string) (string, error) {
+	conn, err := net.DialTimeout("tcp", address, testConnTimeout)
 	if err != nil {
-		return "", errors.New("test connect to server failed")
+		return "", err
 	}
 	defer func() {
 		if err = conn.Close(); err != nil {
@@ -79,7 +94,5 @@ func getNodeIP(edgeOmConfigDir string) (string, error) {
 	if !ok {
 		return "", errors.New("get local address failed")
 	}
-
-	ip := strings.Split(localAddr.String(), ":")[0]
-	return ip, nil
+	return localAddr.IP.String(), nil
 }
@@ -42,8 +42,7 @@ func getNetMgrTemplate() *config.NetManager {
 func TestSetNodeIPToEdgeCore(t *testing.T) {
 	netManager := getNetMgrTemplate()
 	var p = gomonkey.ApplyFuncReturn(config.GetNetManager, netManager, nil).
-		ApplyFuncReturn(net.Dial, fakeNetConn{}, nil).
-		ApplyMethodReturn(&net.TCPAddr{}, "String", "127.0.0.1").
+		ApplyFuncReturn(net.DialTimeout, fakeNetConn{}, nil).
 		ApplyFuncReturn(config.SetNodeIP, nil)
 	defer p.Reset()
 
@@ -61,6 +60,8 @@ func TestSetNodeIPToEdgeCore(t *testing.T) {
 		convey.So(err, convey.ShouldBeNil)
 	})
 
+	convey.Convey("get node ip should be success, the next center address is reachable", t, testGetNodeIPFailover)
+
 	convey.Convey("get node ip should be failed, get install root dir error", t, func() {
 		var p1 = gomonkey.ApplyFuncReturn(path.GetConfigPathMgr, nil, test.ErrTest)
 		defer p1.Reset()
@@ -89,12 +90,37 @@ func TestSetNodeIPToEdgeCore(t *testing.T) {
 	})
 }
 
+func testGetNodeIPFailover() {
+	const unreachableAddr = "center1.example.com"
+	netManager := getNetMgrTemplate()
+	netManager.CenterAddrs = []string{unreachableAddr, "127.0.0.1"}
+	var dialed []string
+	var p1 = gomonkey.ApplyFuncReturn(config.GetNetManager, netManager, nil).
+		ApplyFunc(net.DialTimeout, func(_, address string, _ time.Duration) (net.Conn, error) {
+			dialed = append(dialed, address)
+			if host, _, err := net.SplitHostPort(address); err != nil || host == unreachableAddr {
+				return nil, test.ErrTest
+			}
+			return fakeNetConn{}, nil
+		})
+	defer p1.Reset()
+
+	ip, err := getNodeIP("")
+	convey.So(err, convey.ShouldBeNil)
+	convey.So(ip, convey.ShouldEqual, "127.0.0.1")
+	convey.So(dialed, convey.ShouldResemble, []string{"center1.example.com:0", "127.0.0.1:0"})
+
+	netManager.CenterAddrs = []string{unreachableAddr}
+	_, err = getNodeIP("")
+	convey.So(err, convey.ShouldResemble, fmt.Errorf("test connect to server failed: %v", test.ErrTest))
+}
+
 type fakeNetConn struct{}
 
 func (c fakeNetConn) Read([]byte) (int, error)         { return 0, nil }
 func (c fakeNetConn) Write([]byte) (int, error)        { return 0, nil }
 func (c fakeNetConn) Close() error                     { return nil }
-func (c fakeNetConn) LocalAddr() net.Addr              { return &net.TCPAddr{} }
+func (c fakeNetConn) LocalAddr() net.Addr              { return &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1)} }
 func (c fakeNetConn) RemoteAddr() net.Addr             { return &net.TCPAddr{} }
 func (c fakeNetConn) SetDeadline(time.Time) error      { return nil }
 func (c fakeNetConn) SetReadDeadline(time.Time) error  { return nil }
@@ -0,0 +1,123 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+//go:build MEFEdge_SDK
+
+// Package commands this file for edge control command set download policy
+package commands
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+
+	"huawei.com/mindx/common/fileutils"
+	"huawei.com/mindx/common/hwlog"
+
+	"edge-installer/pkg/common/config"
+	"edge-installer/pkg/common/util"
+	"edge-installer/pkg/installer/edgectl/common"
+)
+
+type downloadPolicyCmd struct {
+	bandwidthLimit int64
+	windowStart    string
+	windowEnd      string
+}
+
+// DownloadPolicyCmd edge control command set bandwidth cap and time window of downloads
+func DownloadPolicyCmd() common.Command {
+	return &downloadPolicyCmd{}
+}
+
+// Name command name
+func (cmd *downloadPolicyCmd) Name() string {
+	return common.DownloadPolicy
+}
+
+// Description command description
+func (cmd *downloadPolicyCmd) Description() string {
+	return common.DownloadPolicyDesc
+}
+
+// BindFlag command flag binding
+func (cmd *downloadPolicyCmd) BindFlag() bool {
+	flag.Int64Var(&cmd.bandwidthLimit, common.BandwidthLimitCmd, 0,
+		fmt.Sprintf("The bandwidth cap of downloads in KB/s, the range is from 0 to %d, 0 means no limit",
+			config.MaxBandwidthLimit))
+	flag.StringVar(&cmd.windowStart, common.WindowStartCmd, "",
+		"The start of the download time window in HH:MM, empty means downloading at any time")
+	flag.StringVar(&cmd.windowEnd, common.WindowEndCmd, "",
+		"The end of the download time window in HH:MM, the window crosses midnight if it is earlier than the start")
+	return true
+}
+
+// LockFlag command lock flag
+func (cmd *downloadPolicyCmd) LockFlag() bool {
+	return true
+}
+
+// Execute execute command
+func (cmd *downloadPolicyCmd) Execute(ctx *common.Context) error {
+	hwlog.RunLog.Info("start to set download policy")
+	fmt.Println("start to set download policy...")
+
+	if ctx == nil {
+		hwlog.RunLog.Error("ctx is nil")
+		return errors.New("ctx is nil")
+	}
+
+	if !util.IsFlagSet(common.BandwidthLimitCmd) && !util.IsFlagSet(common.WindowStartCmd) &&
+		!util.IsFlagSet(common.WindowEndCmd) {
+		hwlog.RunLog.Info("does not modify any configuration")
+		fmt.Println("does not modify any configuration.")
+		return nil
+	}
+
+	policyPath := ctx.ConfigPathMgr.GetDownloadPolicyPath()
+	policy := config.DownloadPolicy{}
+	if fileutils.IsExist(policyPath) {
+		oldPolicy, err := config.LoadDownloadPolicy(policyPath)
+		if err != nil {
+			hwlog.RunLog.Warnf("load current download policy failed, it is overwritten: %v", err)
+		} else {
+			policy = *oldPolicy
+		}
+	}
+	if util.IsFlagSet(common.BandwidthLimitCmd) {
+		policy.BandwidthLimit = cmd.bandwidthLimit
+	}
+	if util.IsFlagSet(common.WindowStartCmd) || util.IsFlagSet(common.WindowEndCmd) {
+		policy.WindowStart, policy.WindowEnd = cmd.windowStart, cmd.windowEnd
+	}
+	if err := policy.Check(); err != nil {
+		hwlog.RunLog.Errorf("check download policy failed, error: %v", err)
+		return fmt.Errorf("check download policy failed, error: %v", err)
+	}
+
+	if err := config.SaveDownloadPolicy(policyPath, policy); err != nil {
+		return err
+	}
+	if err := util.SetPathOwnerGroupToMEFEdge(policyPath, false, false); err != nil {
+		return fmt.Errorf("set download policy owner failed, error: %v", err)
+	}
+	hwlog.RunLog.Infof("set download policy success, bandwidth limit: %d KB/s, window: [%s-%s]",
+		policy.BandwidthLimit, policy.WindowStart, policy.WindowEnd)
+	return nil
+}
+
+// PrintOpLogOk print operation success log
+func (cmd *downloadPolicyCmd) PrintOpLogOk(user, ip string) {
+	common.DefaultPrintOpLogOk(cmd, user, ip)
+}
+
+// PrintOpLogFail print operation fail log
+func (cmd *downloadPolicyCmd) PrintOpLogFail(user, ip string) {
+	common.DefaultPrintOpLogFail(cmd, user, ip)
+}
@@ -0,0 +1,78 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+//go:build MEFEdge_SDK
+
+// Package commands test for download policy
+package commands
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/smartystreets/goconvey/convey"
+
+	"huawei.com/mindx/common/fileutils"
+
+	"edge-installer/pkg/common/config"
+	"edge-installer/pkg/common/path/pathmgr"
+	"edge-installer/pkg/common/util"
+	"edge-installer/pkg/installer/edgectl/common"
+)
+
+func TestDownloadPolicyCmd(t *testing.T) {
+	policyCtx := &common.Context{ConfigPathMgr: pathmgr.NewConfigPathMgr("/tmp/test_download_policy_cmd")}
+	policyPath := policyCtx.ConfigPathMgr.GetDownloadPolicyPath()
+	defer func() {
+		if err := fileutils.DeleteAllFileWithConfusion(policyCtx.ConfigPathMgr.GetInstallRootDir()); err != nil {
+			t.Logf("clear test dir failed: %v", err)
+		}
+	}()
+	p := gomonkey.ApplyFuncReturn(util.SetPathOwnerGroupToMEFEdge, nil)
+	defer p.Reset()
+
+	convey.Convey("test download policy cmd methods", t, func() {
+		convey.So(DownloadPolicyCmd().Name(), convey.ShouldEqual, common.DownloadPolicy)
+		convey.So(DownloadPolicyCmd().Description(), convey.ShouldEqual, common.DownloadPolicyDesc)
+		convey.So(DownloadPolicyCmd().LockFlag(), convey.ShouldBeTrue)
+		DownloadPolicyCmd().PrintOpLogOk(userRoot, ipLocalhost)
+		DownloadPolicyCmd().PrintOpLogFail(userRoot, ipLocalhost)
+		convey.So(DownloadPolicyCmd().Execute(nil), convey.ShouldResemble, errors.New("ctx is nil"))
+	})
+
+	convey.Convey("set download policy success, unset fields keep the current value", t, func() {
+		p1 := gomonkey.ApplyFuncReturn(util.IsFlagSet, true)
+		policyCmd := &downloadPolicyCmd{bandwidthLimit: 1024, windowStart: "22:00", windowEnd: "06:00"}
+		convey.So(policyCmd.Execute(policyCtx), convey.ShouldBeNil)
+		p1.Reset()
+
+		p2 := gomonkey.ApplyFunc(util.IsFlagSet, func(name string) bool {
+			return name == common.BandwidthLimitCmd
+		})
+		defer p2.Reset()
+		convey.So((&downloadPolicyCmd{bandwidthLimit: 0}).Execute(policyCtx), convey.ShouldBeNil)
+		policy, err := config.LoadDownloadPolicy(policyPath)
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(*policy, convey.ShouldResemble, config.DownloadPolicy{WindowStart: "22:00", WindowEnd: "06:00"})
+	})
+
+	convey.Convey("set download policy failed, invalid window", t, func() {
+		p1 := gomonkey.ApplyFuncReturn(util.IsFlagSet, true)
+		defer p1.Reset()
+		policyCmd := &downloadPolicyCmd{windowStart: "22:00"}
+		convey.So(policyCmd.Execute(policyCtx), convey.ShouldNotBeNil)
+	})
+
+	convey.Convey("does not modify any configuration", t, func() {
+		p1 := gomonkey.ApplyFuncReturn(util.IsFlagSet, false)
+		defer p1.Reset()
+		convey.So(DownloadPolicyCmd().Execute(policyCtx), convey.ShouldBeNil)
+	})
+}
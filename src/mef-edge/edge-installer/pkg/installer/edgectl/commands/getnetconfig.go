@@ -68,7 +68,7 @@ func (cmd *getNetCfgCmd) ExecuteWithCode(ctx *common.Context) (int, error) {
 		hwlog.RunLog.Error("ctx is nil")
 		return defaultErrorCode, errors.New("ctx is nil")
 	}
-	return cmd.getNetConfig()
+	return cmd.getNetConfig(ctx)
 }
 
 // PrintOpLogOk print operation success log
@@ -81,7 +81,7 @@ func (cmd *getNetCfgCmd) PrintOpLogFail(user, ip string) {
 	common.DefaultPrintOpLogFail(cmd, user, ip)
 }
 
-func (cmd *getNetCfgCmd) getNetConfig() (int, error) {
+func (cmd *getNetCfgCmd) getNetConfig(ctx *common.Context) (int, error) {
 	dbMgr, err := config.GetComponentDbMgr(constants.EdgeOm)
 	if err != nil {
 		hwlog.RunLog.Errorf("get component db manager failed, error: %v", err)
@@ -104,5 +104,8 @@ func (cmd *getNetCfgCmd) getNetConfig() (int, error) {
 	}
 	hwlog.RunLog.Infof("current net type is [%s]", netManager.NetType)
 	fmt.Printf("current net type is [%s]\n", netManager.NetType)
+	if netManager.NetType == constants.MEF {
+		printMefNetConfig(ctx, netManager)
+	}
 	return otherNetCode, nil
 }
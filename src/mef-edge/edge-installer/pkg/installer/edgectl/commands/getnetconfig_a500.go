@@ -0,0 +1,22 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+//go:build !MEFEdge_SDK || MEFEdge_A500
+
+// Package commands for
+package commands
+
+import (
+	"edge-installer/pkg/common/config"
+	"edge-installer/pkg/installer/edgectl/common"
+)
+
+// printMefNetConfig MEF net config is not configured by edgectl on this platform
+func printMefNetConfig(_ *common.Context, _ *config.NetManager) {}
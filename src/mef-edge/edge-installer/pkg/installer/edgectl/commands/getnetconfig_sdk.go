@@ -0,0 +1,66 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+//go:build MEFEdge_SDK
+
+// Package commands for
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"huawei.com/mindx/common/fileutils"
+	"huawei.com/mindx/common/hwlog"
+
+	"edge-installer/pkg/common/config"
+	"edge-installer/pkg/installer/edgectl/common"
+)
+
+// printMefNetConfig print the configured MEF Center addresses, the one edge-main is connected to and the http proxy
+func printMefNetConfig(ctx *common.Context, netManager *config.NetManager) {
+	addrs := strings.Join(netManager.GetCenterAddrs(), ",")
+	hwlog.RunLog.Infof("MEF Center addresses are [%s]", addrs)
+	fmt.Printf("MEF Center addresses are [%s]\n", addrs)
+
+	activeAddr := "none"
+	if ctx.ConfigPathMgr != nil {
+		activeAddr = getActiveCenterAddr(ctx.ConfigPathMgr.GetActiveCenterPath())
+	}
+	hwlog.RunLog.Infof("active MEF Center address is [%s]", activeAddr)
+	fmt.Printf("active MEF Center address is [%s]\n", activeAddr)
+
+	if netManager.ProxyUrl == "" {
+		fmt.Println("http proxy is [none]")
+		return
+	}
+	proxyInfo := fmt.Sprintf("http proxy is [%s], user is [%s], no proxy hosts are [%s]", netManager.ProxyUrl,
+		netManager.ProxyUser, strings.Join(netManager.NoProxy, ","))
+	hwlog.RunLog.Info(proxyInfo)
+	fmt.Println(proxyInfo)
+}
+
+func getActiveCenterAddr(activeCenterPath string) string {
+	if !fileutils.IsExist(activeCenterPath) {
+		return "none"
+	}
+	content, err := fileutils.LoadFile(activeCenterPath)
+	if err != nil {
+		hwlog.RunLog.Warnf("load active MEF Center file failed, error: %v", err)
+		return "unknown"
+	}
+	var activeCenter config.ActiveCenter
+	if err = json.Unmarshal(content, &activeCenter); err != nil || activeCenter.Addr == "" {
+		hwlog.RunLog.Warnf("parse active MEF Center file failed, error: %v", err)
+		return "unknown"
+	}
+	return fmt.Sprintf("%s, connected at %s", activeCenter.Addr, activeCenter.ConnectedAt)
+}
@@ -0,0 +1,56 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+//go:build MEFEdge_SDK
+
+// Package commands for
+package commands
+
+import (
+	"testing"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/smartystreets/goconvey/convey"
+
+	"huawei.com/mindx/common/fileutils"
+	"huawei.com/mindx/common/test"
+)
+
+func TestGetActiveCenterAddr(t *testing.T) {
+	const activeCenterPath = "/tmp/active-center.json"
+	convey.Convey("active center file does not exist", t, func() {
+		p := gomonkey.ApplyFuncReturn(fileutils.IsExist, false)
+		defer p.Reset()
+		convey.So(getActiveCenterAddr(activeCenterPath), convey.ShouldEqual, "none")
+	})
+
+	convey.Convey("active center is read from file", t, func() {
+		p := gomonkey.ApplyFuncReturn(fileutils.IsExist, true).
+			ApplyFuncReturn(fileutils.LoadFile,
+				[]byte(`{"addr":"10.0.0.2","connectedAt":"2025-01-01 00:00:00"}`), nil)
+		defer p.Reset()
+		convey.So(getActiveCenterAddr(activeCenterPath), convey.ShouldEqual,
+			"10.0.0.2, connected at 2025-01-01 00:00:00")
+	})
+
+	convey.Convey("load active center file failed", t, func() {
+		p := gomonkey.ApplyFuncReturn(fileutils.IsExist, true).
+			ApplyFuncReturn(fileutils.LoadFile, nil, test.ErrTest)
+		defer p.Reset()
+		convey.So(getActiveCenterAddr(activeCenterPath), convey.ShouldEqual, "unknown")
+	})
+
+	convey.Convey("active center file has no address", t, func() {
+		p := gomonkey.ApplyFuncReturn(fileutils.IsExist, true).
+			ApplyFuncReturn(fileutils.LoadFile, []byte("{}"), nil)
+		defer p.Reset()
+		convey.So(getActiveCenterAddr(activeCenterPath), convey.ShouldEqual, "unknown")
+	})
+}
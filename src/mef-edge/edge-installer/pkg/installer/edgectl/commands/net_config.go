@@ -33,6 +33,9 @@ type netConfigCmd struct {
 	rootCa      string
 	token       string
 	testConnect bool
+	proxyUrl    string
+	proxyUser   string
+	noProxy     string
 }
 
 // NetConfigCmd edge control command net config
@@ -53,11 +56,18 @@ func (cmd *netConfigCmd) Description() string {
 // BindFlag command flag binding
 func (cmd *netConfigCmd) BindFlag() bool {
 	flag.StringVar(&(cmd.netType), "net_type", constants.MEF, "the type of net manager")
-	flag.StringVar(&(cmd.ip), "ip", "", "the ip of MEF Center")
+	flag.StringVar(&(cmd.ip), "ip", "",
+		"the ip or domain name of MEF Center, multiple addresses separated by comma are tried in order")
 	flag.IntVar(&(cmd.port), "port", constants.DefaultWsPort, "the port of MEF Center")
 	flag.IntVar(&(cmd.authPort), "auth_port", constants.DefaultWsTestPort, "the auth port of MEF Center")
 	flag.StringVar(&(cmd.rootCa), "root_ca", "", "the root ca of MEF Center")
 	flag.BoolVar(&(cmd.testConnect), "test_connect", true, "whether to test the connection between MEF Edge and Center")
+	flag.StringVar(&(cmd.proxyUrl), "proxy_url", "",
+		"the http proxy for connections to MEF Center and file servers, such as http://ip:port, empty means no proxy")
+	flag.StringVar(&(cmd.proxyUser), "proxy_user", "",
+		"the user name for http proxy basic auth, the password is entered interactively")
+	flag.StringVar(&(cmd.noProxy), "no_proxy", "",
+		"the hosts connected without http proxy, multiple ip, cidr or domain name separated by comma")
 	return true
 }
 
@@ -76,6 +86,9 @@ func (cmd *netConfigCmd) getConfigFlow(ctx *common.Context) FlowCommon.Flow {
 			AuthPort:      cmd.authPort,
 			RootCa:        cmd.rootCa,
 			TestConnect:   cmd.testConnect,
+			ProxyUrl:      cmd.proxyUrl,
+			ProxyUser:     cmd.proxyUser,
+			NoProxy:       cmd.noProxy,
 			ConfigPathMgr: ctx.ConfigPathMgr,
 		}
 		return netconfig.NewMefConfigFlow(param)
@@ -0,0 +1,81 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package commands
+
+import (
+	"errors"
+	"fmt"
+
+	"huawei.com/mindx/common/hwlog"
+
+	"edge-installer/pkg/common/path"
+	"edge-installer/pkg/common/path/pathmgr"
+	"edge-installer/pkg/installer/edgectl/common"
+	"edge-installer/pkg/installer/upgrade/flows"
+)
+
+type rollbackCmd struct {
+}
+
+// RollbackCmd edge control command rollback mef-edge to the inactive version
+func RollbackCmd() common.Command {
+	return &rollbackCmd{}
+}
+
+// Name command name
+func (cmd *rollbackCmd) Name() string {
+	return common.Rollback
+}
+
+// Description command description
+func (cmd *rollbackCmd) Description() string {
+	return common.RollbackDesc
+}
+
+// BindFlag command flag binding
+func (cmd *rollbackCmd) BindFlag() bool {
+	return false
+}
+
+// LockFlag command lock flag
+func (cmd *rollbackCmd) LockFlag() bool {
+	return true
+}
+
+// Execute execute command
+func (cmd *rollbackCmd) Execute(ctx *common.Context) error {
+	if ctx == nil {
+		hwlog.RunLog.Error("ctx is nil")
+		return errors.New("ctx is nil")
+	}
+	installRootDir := ctx.WorkPathMgr.GetInstallRootDir()
+	logRootDir, err := path.GetLogRootDir(installRootDir)
+	if err != nil {
+		return fmt.Errorf("get log root dir failed, error: %v", err)
+	}
+	logBackupRootDir, err := path.GetLogBackupRootDir(installRootDir)
+	if err != nil {
+		return fmt.Errorf("get log backup root dir failed, error: %v", err)
+	}
+
+	pathMgr := pathmgr.NewPathMgr(installRootDir, "", logRootDir, logBackupRootDir)
+	return flows.NewRollbackFlow(pathMgr).RunTasks()
+}
+
+// PrintOpLogOk print operation success log
+func (cmd *rollbackCmd) PrintOpLogOk(user, ip string) {
+	hwlog.OpLog.Infof("[%s@%s] rollback software success", user, ip)
+}
+
+// PrintOpLogFail print operation fail log
+func (cmd *rollbackCmd) PrintOpLogFail(user, ip string) {
+	hwlog.OpLog.Errorf("[%s@%s] rollback software failed", user, ip)
+}
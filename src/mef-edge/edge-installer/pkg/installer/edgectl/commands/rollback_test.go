@@ -0,0 +1,62 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package commands
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/smartystreets/goconvey/convey"
+
+	"huawei.com/mindx/common/test"
+
+	"edge-installer/pkg/common/path"
+	"edge-installer/pkg/installer/edgectl/common"
+	"edge-installer/pkg/installer/upgrade/tasks"
+)
+
+func TestRollbackCmd(t *testing.T) {
+	p := gomonkey.ApplyFuncReturn(path.GetLogRootDir, "/var/alog", nil).
+		ApplyFuncReturn(path.GetLogBackupRootDir, "/home/log", nil).
+		ApplyMethodReturn(&tasks.RollbackProcessTask{}, "Run", nil)
+	defer p.Reset()
+
+	convey.Convey("test rollback cmd methods", t, func() {
+		convey.So(RollbackCmd().Name(), convey.ShouldEqual, common.Rollback)
+		convey.So(RollbackCmd().Description(), convey.ShouldEqual, common.RollbackDesc)
+		convey.So(RollbackCmd().BindFlag(), convey.ShouldBeFalse)
+		convey.So(RollbackCmd().LockFlag(), convey.ShouldBeTrue)
+	})
+
+	convey.Convey("test rollback cmd successful", t, func() {
+		convey.So(RollbackCmd().Execute(ctx), convey.ShouldBeNil)
+		RollbackCmd().PrintOpLogOk(userRoot, ipLocalhost)
+	})
+
+	convey.Convey("test rollback cmd failed", t, func() {
+		convey.So(RollbackCmd().Execute(nil), convey.ShouldResemble, errors.New("ctx is nil"))
+		RollbackCmd().PrintOpLogFail(userRoot, ipLocalhost)
+
+		p1 := gomonkey.ApplyFuncReturn(path.GetLogRootDir, "", test.ErrTest)
+		defer p1.Reset()
+		convey.So(RollbackCmd().Execute(ctx), convey.ShouldResemble,
+			fmt.Errorf("get log root dir failed, error: %v", test.ErrTest))
+	})
+
+	convey.Convey("test rollback cmd failed, rollback process failed", t, func() {
+		p1 := gomonkey.ApplyMethodReturn(&tasks.RollbackProcessTask{}, "Run", test.ErrTest)
+		defer p1.Reset()
+		convey.So(RollbackCmd().Execute(ctx), convey.ShouldResemble, errors.New("rollback process task failed"))
+	})
+}
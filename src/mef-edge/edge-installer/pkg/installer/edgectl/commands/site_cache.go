@@ -0,0 +1,151 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+//go:build MEFEdge_SDK
+
+// Package commands this file for edge control command set site cache role
+package commands
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+
+	"huawei.com/mindx/common/fileutils"
+	"huawei.com/mindx/common/hwlog"
+
+	"edge-installer/pkg/common/config"
+	"edge-installer/pkg/common/constants"
+	"edge-installer/pkg/common/util"
+	"edge-installer/pkg/installer/edgectl/common"
+)
+
+// siteCacheDir the dir of cached artifacts, it is a variable for test
+var siteCacheDir = constants.EdgeSiteCachePath
+
+type siteCacheCmd struct {
+	enable   bool
+	port     int
+	capacity int64
+}
+
+// SiteCacheCmd edge control command set the site cache role of the node
+func SiteCacheCmd() common.Command {
+	return &siteCacheCmd{}
+}
+
+// Name command name
+func (cmd *siteCacheCmd) Name() string {
+	return common.SiteCache
+}
+
+// Description command description
+func (cmd *siteCacheCmd) Description() string {
+	return common.SiteCacheDesc
+}
+
+// BindFlag command flag binding
+func (cmd *siteCacheCmd) BindFlag() bool {
+	flag.BoolVar(&cmd.enable, common.SiteCacheEnableCmd, false,
+		"Whether the node serves the artifacts hosted by MEF Center to the peers in the same site")
+	flag.IntVar(&cmd.port, common.SiteCachePortCmd, 0,
+		fmt.Sprintf("The port listened for the peers, the range is from %d to %d",
+			config.MinSiteCachePort, config.MaxSiteCachePort))
+	flag.Int64Var(&cmd.capacity, common.SiteCacheCapacityCmd, config.DefaultSiteCacheCapacity,
+		fmt.Sprintf("The capacity of cached artifacts in GB, the range is from 1 to %d", config.MaxSiteCacheCapacity))
+	return true
+}
+
+// LockFlag command lock flag
+func (cmd *siteCacheCmd) LockFlag() bool {
+	return true
+}
+
+// Execute execute command
+func (cmd *siteCacheCmd) Execute(ctx *common.Context) error {
+	hwlog.RunLog.Info("start to set site cache")
+	fmt.Println("start to set site cache...")
+
+	if ctx == nil {
+		hwlog.RunLog.Error("ctx is nil")
+		return errors.New("ctx is nil")
+	}
+
+	if !util.IsFlagSet(common.SiteCacheEnableCmd) && !util.IsFlagSet(common.SiteCachePortCmd) &&
+		!util.IsFlagSet(common.SiteCacheCapacityCmd) {
+		hwlog.RunLog.Info("does not modify any configuration")
+		fmt.Println("does not modify any configuration.")
+		return nil
+	}
+
+	cfgPath := ctx.ConfigPathMgr.GetSiteCachePath()
+	cfg := config.SiteCacheConfig{Capacity: config.DefaultSiteCacheCapacity}
+	if fileutils.IsExist(cfgPath) {
+		oldCfg, err := config.LoadSiteCacheConfig(cfgPath)
+		if err != nil {
+			hwlog.RunLog.Warnf("load current site cache config failed, it is overwritten: %v", err)
+		} else {
+			cfg = *oldCfg
+		}
+	}
+	if util.IsFlagSet(common.SiteCacheEnableCmd) {
+		cfg.Enable = cmd.enable
+	}
+	if util.IsFlagSet(common.SiteCachePortCmd) {
+		cfg.Port = cmd.port
+	}
+	if util.IsFlagSet(common.SiteCacheCapacityCmd) {
+		cfg.Capacity = cmd.capacity
+	}
+	if err := cfg.Check(); err != nil {
+		hwlog.RunLog.Errorf("check site cache config failed, error: %v", err)
+		return fmt.Errorf("check site cache config failed, error: %v", err)
+	}
+
+	if err := prepareSiteCacheDir(cfg.Enable); err != nil {
+		return err
+	}
+	if err := config.SaveSiteCacheConfig(cfgPath, cfg); err != nil {
+		return err
+	}
+	if err := util.SetPathOwnerGroupToMEFEdge(cfgPath, false, false); err != nil {
+		return fmt.Errorf("set site cache config owner failed, error: %v", err)
+	}
+	hwlog.RunLog.Infof("set site cache success, enable: %v, port: %d, capacity: %d GB",
+		cfg.Enable, cfg.Port, cfg.Capacity)
+	return nil
+}
+
+// prepareSiteCacheDir the dir of cached artifacts is created for MEFEdge when the site cache is enabled,
+// and deleted with the cached artifacts when it is disabled
+func prepareSiteCacheDir(enable bool) error {
+	if !enable {
+		if err := fileutils.DeleteAllFileWithConfusion(siteCacheDir); err != nil {
+			return fmt.Errorf("delete site cache dir failed, error: %v", err)
+		}
+		return nil
+	}
+	if err := fileutils.CreateDir(siteCacheDir, constants.Mode700); err != nil {
+		return fmt.Errorf("create site cache dir failed, error: %v", err)
+	}
+	if err := util.SetPathOwnerGroupToMEFEdge(siteCacheDir, true, false); err != nil {
+		return fmt.Errorf("set site cache dir owner failed, error: %v", err)
+	}
+	return nil
+}
+
+// PrintOpLogOk print operation success log
+func (cmd *siteCacheCmd) PrintOpLogOk(user, ip string) {
+	common.DefaultPrintOpLogOk(cmd, user, ip)
+}
+
+// PrintOpLogFail print operation fail log
+func (cmd *siteCacheCmd) PrintOpLogFail(user, ip string) {
+	common.DefaultPrintOpLogFail(cmd, user, ip)
+}
@@ -0,0 +1,83 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+//go:build MEFEdge_SDK
+
+// Package commands test for site cache
+package commands
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/smartystreets/goconvey/convey"
+
+	"huawei.com/mindx/common/fileutils"
+
+	"edge-installer/pkg/common/config"
+	"edge-installer/pkg/common/path/pathmgr"
+	"edge-installer/pkg/common/util"
+	"edge-installer/pkg/installer/edgectl/common"
+)
+
+func TestSiteCacheCmd(t *testing.T) {
+	siteCacheCtx := &common.Context{ConfigPathMgr: pathmgr.NewConfigPathMgr("/tmp/test_site_cache_cmd")}
+	cfgPath := siteCacheCtx.ConfigPathMgr.GetSiteCachePath()
+	siteCacheDir = "/tmp/test_site_cache_cmd/cache"
+	defer func() {
+		if err := fileutils.DeleteAllFileWithConfusion(siteCacheCtx.ConfigPathMgr.GetInstallRootDir()); err != nil {
+			t.Logf("clear test dir failed: %v", err)
+		}
+	}()
+	p := gomonkey.ApplyFuncReturn(util.SetPathOwnerGroupToMEFEdge, nil)
+	defer p.Reset()
+
+	convey.Convey("test site cache cmd methods", t, func() {
+		convey.So(SiteCacheCmd().Name(), convey.ShouldEqual, common.SiteCache)
+		convey.So(SiteCacheCmd().Description(), convey.ShouldEqual, common.SiteCacheDesc)
+		convey.So(SiteCacheCmd().LockFlag(), convey.ShouldBeTrue)
+		SiteCacheCmd().PrintOpLogOk(userRoot, ipLocalhost)
+		SiteCacheCmd().PrintOpLogFail(userRoot, ipLocalhost)
+		convey.So(SiteCacheCmd().Execute(nil), convey.ShouldResemble, errors.New("ctx is nil"))
+	})
+
+	convey.Convey("enable site cache success, the cache dir is created", t, func() {
+		p1 := gomonkey.ApplyFunc(util.IsFlagSet, func(name string) bool {
+			return name != common.SiteCacheCapacityCmd
+		})
+		defer p1.Reset()
+		convey.So((&siteCacheCmd{enable: true, port: 8443}).Execute(siteCacheCtx), convey.ShouldBeNil)
+		cfg, err := config.LoadSiteCacheConfig(cfgPath)
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(*cfg, convey.ShouldResemble,
+			config.SiteCacheConfig{Enable: true, Port: 8443, Capacity: config.DefaultSiteCacheCapacity})
+		convey.So(fileutils.IsExist(siteCacheDir), convey.ShouldBeTrue)
+	})
+
+	convey.Convey("enable site cache failed, invalid port", t, func() {
+		p1 := gomonkey.ApplyFuncReturn(util.IsFlagSet, true)
+		defer p1.Reset()
+		siteCmd := &siteCacheCmd{enable: true, port: 80, capacity: config.DefaultSiteCacheCapacity}
+		convey.So(siteCmd.Execute(siteCacheCtx), convey.ShouldNotBeNil)
+	})
+
+	convey.Convey("disable site cache success, the cache dir is deleted", t, func() {
+		p1 := gomonkey.ApplyFunc(util.IsFlagSet, func(name string) bool {
+			return name == common.SiteCacheEnableCmd
+		})
+		defer p1.Reset()
+		convey.So((&siteCacheCmd{enable: false}).Execute(siteCacheCtx), convey.ShouldBeNil)
+		cfg, err := config.LoadSiteCacheConfig(cfgPath)
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(cfg.Enable, convey.ShouldBeFalse)
+		convey.So(cfg.Port, convey.ShouldEqual, 8443)
+		convey.So(fileutils.IsExist(siteCacheDir), convey.ShouldBeFalse)
+	})
+}
@@ -22,6 +22,7 @@ const (
 	CollectLog        = "collectlog"
 	GetCertInfo       = "getcertinfo"
 	Effect            = "effect"
+	Rollback          = "rollback"
 	UpdateKmc         = "updatekmc"
 	ImportCrl         = "importcrl"
 	UpdateCrl         = "updatecrl"
@@ -52,6 +53,7 @@ const (
 	CollectLogDesc        = "to collect the log"
 	GetCertInfoDesc       = "to print certificate information"
 	EffectDesc            = "to effect the software"
+	RollbackDesc          = "to roll back the software to the inactive version"
 	UpdateKmcDesc         = "to update kmc key"
 	ImportCrlDesc         = "to import crl of the certificate used for cloud edge interconnection"
 	UpdateCrlDesc         = "to update crl file for package signature verification"
@@ -26,6 +26,12 @@ const (
 
 	GetAlarmCfg     = "getalarmconfig"
 	GetAlarmCfgDesc = "to get alarm used configuration"
+
+	DownloadPolicy     = "downloadpolicy"
+	DownloadPolicyDesc = "to set bandwidth cap and time window of software and model file downloads"
+
+	SiteCache     = "sitecache"
+	SiteCacheDesc = "to set the site cache role which serves the artifacts hosted by MEF Center to peers"
 )
 
 // netconfig commands config
@@ -34,6 +40,7 @@ const (
 	MinTokenLen        = 16
 	MaxTokenLen        = 64
 	EnterTokenWaitTime = 1 * time.Minute
+	MaxProxyPwdLen     = 128
 )
 
 // update and get alarm config commands config
@@ -43,3 +50,17 @@ const (
 	UnitDay                 = "day"
 	DiffTime                = 3
 )
+
+// download policy commands config
+const (
+	BandwidthLimitCmd = "bandwidth_limit"
+	WindowStartCmd    = "window_start"
+	WindowEndCmd      = "window_end"
+)
+
+// site cache commands config
+const (
+	SiteCacheEnableCmd   = "enable"
+	SiteCachePortCmd     = "port"
+	SiteCacheCapacityCmd = "capacity"
+)
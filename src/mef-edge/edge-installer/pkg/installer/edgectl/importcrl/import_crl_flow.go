@@ -40,6 +40,7 @@ type CrlImportFlow struct {
 	crlPath       string
 	peer          string
 	certPair      *CertPair
+	crlContent    []byte
 }
 
 // NewCrlImportFlow is the func to create a CrlImportFlow instance
@@ -72,6 +73,7 @@ func (cif *CrlImportFlow) RunFlow() error {
 	var tasks = []func() error{
 		cif.setCertPair,
 		cif.checkCrl,
+		cif.checkPolicy,
 		cif.crlImport,
 	}
 
@@ -106,14 +108,36 @@ func (cif *CrlImportFlow) checkCrl() error {
 		return errors.New("peer's cert has not yet imported")
 	}
 
-	_, err := x509.CheckCrlsChainReturnContent(cif.crlPath, cif.certPair.certPath)
+	crlContent, err := x509.CheckCrlsChainReturnContent(cif.crlPath, cif.certPair.certPath)
 	if err != nil {
 		return fmt.Errorf("check crl chain failed: %s", err.Error())
 	}
 
+	cif.crlContent = crlContent
 	return nil
 }
 
+func (cif *CrlImportFlow) checkPolicy() error {
+	if err := checkCrlPolicy(cif.crlContent, cif.configPathMgr.GetCertPolicyPath()); err != nil {
+		fmt.Printf("check crl with cert policy failed: %s\n", err.Error())
+		return fmt.Errorf("check crl with cert policy failed: %s", err.Error())
+	}
+	return nil
+}
+
+// checkCrlPolicy evaluates the signature algorithm and issuer of the crl with the cert policy
+func checkCrlPolicy(crlContent []byte, policyPath string) error {
+	policy, err := x509.LoadCertPolicy(policyPath)
+	if err != nil {
+		return err
+	}
+	crls, err := x509.ParseCrls(&x509.CrlData{CrlContent: crlContent})
+	if err != nil {
+		return err
+	}
+	return policy.CheckCrls(crls).Err()
+}
+
 func (cif *CrlImportFlow) crlImport() error {
 	if cif.certPair == nil {
 		return errors.New("cert pair does not initialized")
@@ -93,6 +93,7 @@ func crlImportSuccess() {
 		ApplyFuncReturn(envutils.GetUid, uint32(1225), nil).
 		ApplyFuncReturn(envutils.GetGid, uint32(1225), nil).
 		ApplyFuncReturn(util.CreateBackupWithMefOwner, nil).
+		ApplyFuncReturn(checkCrlPolicy, nil).
 		ApplyFuncSeq(envutils.RunCommandWithUser, []gomonkey.OutputCell{
 			{Values: gomonkey.Params{"", nil}},
 			{Values: gomonkey.Params{"", nil}},
@@ -128,10 +129,21 @@ func checkCrlFailed() {
 		expectErr := fmt.Errorf("check crl chain failed: %s", test.ErrTest.Error())
 		convey.So(err, convey.ShouldResemble, expectErr)
 	})
+
+	convey.Convey("check crl with cert policy failed", func() {
+		p := gomonkey.ApplyFuncReturn(x509.CheckCrlsChainReturnContent, []byte{}, nil).
+			ApplyFuncReturn(checkCrlPolicy, test.ErrTest)
+		defer p.Reset()
+		crlImportFlow := NewCrlImportFlow(configPathMgr, testImportCrlPath, constants.MefCenterPeer)
+		err := crlImportFlow.RunFlow()
+		expectErr := fmt.Errorf("check crl with cert policy failed: %s", test.ErrTest.Error())
+		convey.So(err, convey.ShouldResemble, expectErr)
+	})
 }
 
 func copyCrlToTmpFailed() {
-	p := gomonkey.ApplyFuncReturn(x509.CheckCrlsChainReturnContent, []byte{}, nil)
+	p := gomonkey.ApplyFuncReturn(x509.CheckCrlsChainReturnContent, []byte{}, nil).
+		ApplyFuncReturn(checkCrlPolicy, nil)
 	defer p.Reset()
 
 	convey.Convey("init tmp dir failed", func() {
@@ -163,7 +175,8 @@ func copyCrlToTmpFailed() {
 }
 
 func copyCrlToEdgeMainFailed() {
-	p := gomonkey.ApplyFuncReturn(x509.CheckCrlsChainReturnContent, []byte{}, nil)
+	p := gomonkey.ApplyFuncReturn(x509.CheckCrlsChainReturnContent, []byte{}, nil).
+		ApplyFuncReturn(checkCrlPolicy, nil)
 	defer p.Reset()
 
 	convey.Convey("copy temp crl to dst failed", func() {
@@ -42,6 +42,11 @@ import (
 	"edge-installer/pkg/installer/edgectl/common"
 )
 
+const (
+	addrSeparator  = ","
+	maxCenterAddrs = 8
+)
+
 // MefConfigFlow mef config flow
 type MefConfigFlow struct {
 	param    Param
@@ -56,6 +61,9 @@ type Param struct {
 	AuthPort      int
 	RootCa        string
 	TestConnect   bool
+	ProxyUrl      string
+	ProxyUser     string
+	NoProxy       string
 	ConfigPathMgr *pathmgr.ConfigPathMgr
 }
 
@@ -65,6 +73,7 @@ type checkParamTask struct {
 	authPort      int
 	token         string
 	rootCa        string
+	proxyCfg      httpsmgr.ProxyCfg
 	configPathMgr *pathmgr.ConfigPathMgr
 }
 
@@ -76,11 +85,12 @@ type importRootCaTask struct {
 type setConfigToDbTask struct {
 	token         []byte
 	netType       string
-	ip            string
+	addrs         []string
 	port          int
 	authPort      int
 	rootCa        string
 	testConnect   bool
+	proxyCfg      httpsmgr.ProxyCfg // password of proxy user is encrypted
 	configPathMgr *pathmgr.ConfigPathMgr
 }
 
@@ -125,11 +135,17 @@ func (cmf *MefConfigFlow) doPostProcess() {
 func (cmf *MefConfigFlow) RunTasks() error {
 	defer cmf.doPostProcess()
 
+	proxyCfg := httpsmgr.ProxyCfg{
+		Url:      cmf.param.ProxyUrl,
+		UserName: cmf.param.ProxyUser,
+		NoProxy:  splitAddrs(cmf.param.NoProxy),
+	}
 	checkParam := checkParamTask{
 		ip:            cmf.param.Ip,
 		port:          cmf.param.Port,
 		authPort:      cmf.param.AuthPort,
 		rootCa:        cmf.param.RootCa,
+		proxyCfg:      proxyCfg,
 		configPathMgr: cmf.param.ConfigPathMgr,
 	}
 	if err := checkParam.runTask(); err != nil {
@@ -139,11 +155,12 @@ func (cmf *MefConfigFlow) RunTasks() error {
 
 	setConfig := setConfigToDbTask{
 		netType:       cmf.param.NetType,
-		ip:            cmf.param.Ip,
+		addrs:         splitAddrs(cmf.param.Ip),
 		port:          cmf.param.Port,
 		authPort:      cmf.param.AuthPort,
 		rootCa:        cmf.param.RootCa,
 		testConnect:   cmf.param.TestConnect,
+		proxyCfg:      proxyCfg,
 		configPathMgr: cmf.param.ConfigPathMgr,
 	}
 	if err := setConfig.runTask(); err != nil {
@@ -166,6 +183,7 @@ func (cpt *checkParamTask) runTask() error {
 	var checkFunc = []func() error{
 		cpt.checkParamIp,
 		cpt.checkParamPort,
+		cpt.checkParamProxy,
 		cpt.checkParamRootCa,
 	}
 	for _, function := range checkFunc {
@@ -176,23 +194,61 @@ func (cpt *checkParamTask) runTask() error {
 	return nil
 }
 
+// splitAddrs multiple addresses are separated by comma, addresses of MEF Center are tried in order for failover
+func splitAddrs(param string) []string {
+	var addrs []string
+	for _, addr := range strings.Split(param, addrSeparator) {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			addrs = append(addrs, addr)
+		}
+	}
+	return addrs
+}
+
 func (cpt *checkParamTask) checkParamIp() error {
-	ip := net.ParseIP(cpt.ip)
-	if cpt.ip == constants.IpZero || cpt.ip == constants.IpBroadcast || ip.To4() == nil {
+	addrs := splitAddrs(cpt.ip)
+	if len(addrs) == 0 || len(addrs) > maxCenterAddrs {
+		hwlog.RunLog.Errorf("check param ip failed, the count of addresses should be in [1, %d]", maxCenterAddrs)
+		return errors.New("param ip is invalid")
+	}
+	addrSet := make(map[string]struct{}, len(addrs))
+	for _, addr := range addrs {
+		if _, ok := addrSet[addr]; ok {
+			hwlog.RunLog.Error("check param ip failed, address is duplicated")
+			return errors.New("param ip has duplicated address")
+		}
+		addrSet[addr] = struct{}{}
+		if err := cpt.checkCenterAddr(addr); err != nil {
+			return err
+		}
+	}
+
+	hwlog.RunLog.Info("check param ip success")
+	return nil
+}
+
+func (cpt *checkParamTask) checkCenterAddr(addr string) error {
+	if net.ParseIP(addr) == nil && !utils.IsDigitString(strings.ReplaceAll(addr, ".", "")) {
+		if err := utils.CheckDomain(addr, true, true); err != nil {
+			hwlog.RunLog.Errorf("check param ip failed, domain name is invalid: %v", err)
+			return errors.New("param ip is invalid")
+		}
+		return nil
+	}
+	ip := net.ParseIP(addr)
+	if addr == constants.IpZero || addr == constants.IpBroadcast || ip.To4() == nil {
 		hwlog.RunLog.Error("check param ip failed, ip is invalid")
 		return errors.New("param ip is invalid")
 	}
 
-	if err := cpt.checkLocalIp(); err != nil {
+	if err := checkLocalIp(addr); err != nil {
 		hwlog.RunLog.Errorf("check param ip failed, error: %v", err)
 		return err
 	}
-
-	hwlog.RunLog.Info("check param ip success")
 	return nil
 }
 
-func (cpt *checkParamTask) checkLocalIp() error {
+func checkLocalIp(addr string) error {
 	addresses, err := net.InterfaceAddrs()
 	if err != nil {
 		hwlog.RunLog.Errorf("get local ip failed, error: %v", err)
@@ -204,7 +260,7 @@ func (cpt *checkParamTask) checkLocalIp() error {
 		if !ok {
 			continue
 		}
-		if cpt.ip == ipNet.IP.String() {
+		if addr == ipNet.IP.String() {
 			return errors.New("param ip is the same as local ip")
 		}
 	}
@@ -231,6 +287,19 @@ func (cpt *checkParamTask) checkParamPort() error {
 	return nil
 }
 
+func (cpt *checkParamTask) checkParamProxy() error {
+	if cpt.proxyCfg.Url == "" && (cpt.proxyCfg.UserName != "" || len(cpt.proxyCfg.NoProxy) > 0) {
+		hwlog.RunLog.Error("check param proxy failed, proxy_user or no_proxy is set without proxy_url")
+		return errors.New("param proxy_url is necessary when proxy_user or no_proxy is set")
+	}
+	if err := cpt.proxyCfg.Check(); err != nil {
+		hwlog.RunLog.Errorf("check param proxy failed, error: %v", err)
+		return fmt.Errorf("param proxy is invalid: %v", err)
+	}
+	hwlog.RunLog.Info("check param proxy success")
+	return nil
+}
+
 func (cpt *checkParamTask) checkParamRootCa() error {
 	var checkFunc = []func() error{
 		cpt.checkRootCaFile,
@@ -306,6 +375,7 @@ func (cpt *checkParamTask) showRootCaFingerprint() error {
 func (sct *setConfigToDbTask) runTask() error {
 	var setFunc = []func() error{
 		sct.getToken,
+		sct.getProxyPassword,
 		sct.testConnection,
 		sct.setNetManagerToDb,
 	}
@@ -353,6 +423,54 @@ func (sct *setConfigToDbTask) getToken() error {
 	return nil
 }
 
+func (sct *setConfigToDbTask) getProxyPassword() error {
+	if sct.proxyCfg.UserName == "" {
+		return nil
+	}
+	fmt.Println("Please enter proxy password: ")
+	password, err := terminal.ReadPasswordWithTimeout(common.StandardInput, common.MaxProxyPwdLen,
+		common.EnterTokenWaitTime)
+	if err != nil {
+		hwlog.RunLog.Error("get proxy password failed")
+		return errors.New("get proxy password failed")
+	}
+	defer utils.ClearSliceByteMemory(password)
+	if !checker.IntChecker(len(password), 1, common.MaxProxyPwdLen) {
+		return errors.New("input proxy password length invalid")
+	}
+
+	kmcDir := sct.configPathMgr.GetCompKmcDir(constants.EdgeOm)
+	kmcCfg, err := util.GetKmcConfig(kmcDir)
+	if err != nil {
+		hwlog.RunLog.Errorf("get kmc config failed when encrypt proxy password, error: %v", err)
+		return err
+	}
+	encryptPwd, err := kmc.EncryptContent(password, kmcCfg)
+	if err != nil {
+		hwlog.RunLog.Errorf("encrypt proxy password failed, error: %v", err)
+		return err
+	}
+	sct.proxyCfg.Password = encryptPwd
+	hwlog.RunLog.Info("get proxy password success")
+	return nil
+}
+
+// getProxyFunc get the proxy for connection test with the decrypted password
+func (sct *setConfigToDbTask) getProxyFunc(kmcCfg *kmc.SubConfig) (httpsmgr.ProxyFunc, error) {
+	proxyCfg := sct.proxyCfg
+	if len(sct.proxyCfg.Password) > 0 {
+		password, err := kmc.DecryptContent(sct.proxyCfg.Password, kmcCfg)
+		if err != nil {
+			hwlog.RunLog.Errorf("decrypt proxy password failed, error: %v", err)
+			return nil, err
+		}
+		defer utils.ClearSliceByteMemory(password)
+		proxyCfg.Password = password
+	}
+	return proxyCfg.GetProxyFunc()
+}
+
+// testConnection the connection test is passed if any address of MEF Center is connected
 func (sct *setConfigToDbTask) testConnection() error {
 	if !sct.testConnect {
 		hwlog.RunLog.Info("skip MEF Center connection test")
@@ -360,7 +478,6 @@ func (sct *setConfigToDbTask) testConnection() error {
 	}
 
 	hwlog.RunLog.Info("start test connection to MEF center")
-	url := fmt.Sprintf("https://%s:%d%s", sct.ip, sct.authPort, constants.MefCenterConnTestUrl)
 	tlsCfg := certutils.TlsCertInfo{
 		RootCaPath: sct.rootCa,
 		RootCaOnly: true,
@@ -379,6 +496,10 @@ func (sct *setConfigToDbTask) testConnection() error {
 		return err
 	}
 	defer utils.ClearSliceByteMemory(decryptToken)
+	proxy, err := sct.getProxyFunc(kmcCfg)
+	if err != nil {
+		return err
+	}
 
 	reqHeaders := map[string]interface{}{
 		constants.Token: string(decryptToken),
@@ -388,30 +509,45 @@ func (sct *setConfigToDbTask) testConnection() error {
 			utils.ClearStringMemory(str)
 		}
 	}()
-	if _, err = httpsmgr.GetHttpsReq(url, tlsCfg, reqHeaders).Get(nil); err == nil {
-		hwlog.RunLog.Info("test connection between MEF Edge and Center success")
-		return nil
-	}
-	if strings.Contains(err.Error(), strconv.Itoa(http.StatusUnauthorized)) {
-		hwlog.RunLog.Error("auth failed by center: token is incorrect")
-	}
-	if strings.Contains(err.Error(), strconv.Itoa(http.StatusLocked)) {
-		hwlog.RunLog.Error("auth failed by center: ip is lock")
+	for _, addr := range sct.addrs {
+		url := fmt.Sprintf("https://%s%s", net.JoinHostPort(addr, strconv.Itoa(sct.authPort)),
+			constants.MefCenterConnTestUrl)
+		if _, err = httpsmgr.GetHttpsReq(url, tlsCfg, reqHeaders).SetProxy(proxy).Get(nil); err == nil {
+			hwlog.RunLog.Infof("test connection between MEF Edge and Center [%s] success", addr)
+			return nil
+		}
+		if strings.Contains(err.Error(), strconv.Itoa(http.StatusUnauthorized)) {
+			hwlog.RunLog.Errorf("auth failed by center [%s]: token is incorrect", addr)
+			continue
+		}
+		if strings.Contains(err.Error(), strconv.Itoa(http.StatusLocked)) {
+			hwlog.RunLog.Errorf("auth failed by center [%s]: ip is lock", addr)
+			continue
+		}
+		hwlog.RunLog.Errorf("auth failed by center [%s]: %v", addr, err)
 	}
-	hwlog.RunLog.Errorf("auth failed by center: %v", err)
 	return errors.New("test connection between MEF Edge and Center failed")
 }
 
 func (sct *setConfigToDbTask) setNetManagerToDb() error {
+	if len(sct.addrs) == 0 {
+		return errors.New("address of MEF Center is empty")
+	}
 	netConfig := config.NetManager{
-		NetType:  sct.netType,
-		IP:       sct.ip,
-		Port:     sct.port,
-		AuthPort: sct.authPort,
-		Token:    sct.token,
+		NetType:     sct.netType,
+		IP:          sct.addrs[0],
+		Port:        sct.port,
+		AuthPort:    sct.authPort,
+		Token:       sct.token,
+		CenterAddrs: sct.addrs,
+		ProxyUrl:    sct.proxyCfg.Url,
+		ProxyUser:   sct.proxyCfg.UserName,
+		ProxyPwd:    sct.proxyCfg.Password,
+		NoProxy:     sct.proxyCfg.NoProxy,
 	}
 	defer utils.ClearSliceByteMemory(netConfig.Token)
 	defer utils.ClearSliceByteMemory(sct.token)
+	defer utils.ClearSliceByteMemory(sct.proxyCfg.Password)
 
 	configPathMgr, err := path.GetConfigPathMgr()
 	if err != nil {
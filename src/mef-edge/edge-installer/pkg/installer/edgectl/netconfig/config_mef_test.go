@@ -18,6 +18,7 @@ import (
 	"net"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/agiledragon/gomonkey/v2"
@@ -130,6 +131,7 @@ func TestMefConfigFlow(t *testing.T) {
 		convey.Convey("check parameters task failed", func() {
 			convey.Convey("check param ip failed", checkParamIpFailed)
 			convey.Convey("check param port failed", checkParamPortFailed)
+			convey.Convey("check param proxy failed", checkParamProxyFailed)
 			convey.Convey("check param root ca failed", checkParamRootCaFailed)
 		})
 
@@ -148,6 +150,7 @@ func TestMefConfigFlow(t *testing.T) {
 	})
 
 	convey.Convey("test mef net config flow successful", t, MefConfigSuccess)
+	convey.Convey("test mef net config flow with proxy successful", t, mefConfigWithProxySuccess)
 }
 
 func MefConfigSuccess() {
@@ -165,6 +168,57 @@ func MefConfigSuccess() {
 	convey.So(err, convey.ShouldBeNil)
 }
 
+func mefConfigWithProxySuccess() {
+	var savedNetMgr *config.NetManager
+	p := gomonkey.ApplyFuncReturn(terminal.ReadPasswordWithTimeout, []byte(testToken), nil).
+		ApplyFuncReturn(kmc.EncryptContent, []byte(testToken), nil).
+		ApplyFuncReturn(kmc.DecryptContent, []byte(testToken), nil).
+		ApplyMethodReturn(&httpsmgr.HttpsRequest{}, "Get", []byte{}, nil).
+		ApplyFunc(config.SetNetManager, func(_ *config.DbMgr, netMgr *config.NetManager) error {
+			savedNetMgr = &config.NetManager{ProxyUrl: netMgr.ProxyUrl, ProxyUser: netMgr.ProxyUser,
+				NoProxy: netMgr.NoProxy}
+			return nil
+		}).
+		ApplyFuncReturn(envutils.GetUid, uint32(os.Geteuid()), nil).
+		ApplyFuncReturn(envutils.GetGid, uint32(os.Getegid()), nil).
+		ApplyFuncReturn(removeFilesByMEFEdgeUser, nil)
+	defer p.Reset()
+
+	testProxyParam := testParam
+	testProxyParam.ProxyUrl = "http://192.168.1.10:3128"
+	testProxyParam.ProxyUser = "proxyuser"
+	testProxyParam.NoProxy = "10.0.0.0/8, .example.com"
+	err := NewMefConfigFlow(testProxyParam).RunTasks()
+	convey.So(err, convey.ShouldBeNil)
+	convey.So(savedNetMgr, convey.ShouldResemble, &config.NetManager{ProxyUrl: "http://192.168.1.10:3128",
+		ProxyUser: "proxyuser", NoProxy: []string{"10.0.0.0/8", ".example.com"}})
+}
+
+func checkParamProxyFailed() {
+	testInvalidParam := testParam
+	convey.Convey("proxy_user is set without proxy_url", func() {
+		testInvalidParam.ProxyUser = "proxyuser"
+		err := NewMefConfigFlow(testInvalidParam).RunTasks()
+		expectErr := errors.New("param proxy_url is necessary when proxy_user or no_proxy is set")
+		convey.So(err, convey.ShouldResemble, expectErr)
+	})
+
+	convey.Convey("proxy_url is not http", func() {
+		testInvalidParam.ProxyUrl = "https://192.168.1.10:3128"
+		err := NewMefConfigFlow(testInvalidParam).RunTasks()
+		convey.So(err, convey.ShouldNotBeNil)
+		convey.So(err.Error(), convey.ShouldStartWith, "param proxy is invalid")
+	})
+
+	convey.Convey("no_proxy item is invalid", func() {
+		testInvalidParam.ProxyUrl = "http://192.168.1.10:3128"
+		testInvalidParam.NoProxy = "10.0.0.0/33"
+		err := NewMefConfigFlow(testInvalidParam).RunTasks()
+		convey.So(err, convey.ShouldNotBeNil)
+		convey.So(err.Error(), convey.ShouldStartWith, "param proxy is invalid")
+	})
+}
+
 func checkParamIpFailed() {
 	testInvalidParam := testParam
 	convey.Convey("param ip is invalid", func() {
@@ -189,6 +243,35 @@ func checkParamIpFailed() {
 		expectErr := errors.New("param ip is the same as local ip")
 		convey.So(err, convey.ShouldResemble, expectErr)
 	})
+
+	convey.Convey("param ip has duplicated address", func() {
+		testInvalidParam.Ip = "192.168.0.1, 192.168.0.2,192.168.0.1"
+		err := NewMefConfigFlow(testInvalidParam).RunTasks()
+		expectErr := errors.New("param ip has duplicated address")
+		convey.So(err, convey.ShouldResemble, expectErr)
+	})
+
+	convey.Convey("param ip has too many addresses", func() {
+		testInvalidParam.Ip = strings.TrimSuffix(strings.Repeat("192.168.0.1,", maxCenterAddrs+1), ",")
+		err := NewMefConfigFlow(testInvalidParam).RunTasks()
+		expectErr := errors.New("param ip is invalid")
+		convey.So(err, convey.ShouldResemble, expectErr)
+	})
+
+	convey.Convey("one of the addresses is invalid", func() {
+		testInvalidParam.Ip = "192.168.0.1,255.255.255.255"
+		err := NewMefConfigFlow(testInvalidParam).RunTasks()
+		expectErr := errors.New("param ip is invalid")
+		convey.So(err, convey.ShouldResemble, expectErr)
+	})
+}
+
+func TestSplitCenterAddrs(t *testing.T) {
+	convey.Convey("center addresses are split in order", t, func() {
+		convey.So(splitAddrs("192.168.0.2, center.example.com,,192.168.0.1 "), convey.ShouldResemble,
+			[]string{"192.168.0.2", "center.example.com", "192.168.0.1"})
+		convey.So(splitAddrs(""), convey.ShouldBeNil)
+	})
 }
 
 func checkParamPortFailed() {
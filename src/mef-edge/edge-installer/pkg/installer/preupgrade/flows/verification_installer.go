@@ -33,6 +33,7 @@ func NewVerificationInstaller(edgeDir string) common.Flow {
 	const (
 		progressReceived   = 25
 		progressPrepareDir = 40
+		progressCheckEnv   = 80
 	)
 	f := &verificationInstaller{}
 	f.edgeDir = edgeDir
@@ -41,7 +42,8 @@ func NewVerificationInstaller(edgeDir string) common.Flow {
 	f.AddTask(tasks.LockUpgrade(), "lock upgrade", progressReceived)
 	f.AddTask(tasks.PrepareDir(constants.EdgeInstaller), "prepare package dir", progressPrepareDir)
 	f.AddTask(tasks.NewPrepareOnlineInstallEnv(f.downloadPath, f.extractPath, f.edgeDir),
-		"check package and environment", common.ProgressSuccess)
+		"check package and environment", progressCheckEnv)
+	f.AddTask(tasks.NewApplyDeltaPackage(f.extractPath, f.edgeDir), "apply delta package", common.ProgressSuccess)
 	f.AddException(f.clearUnpackPath)
 	f.AddFinal(f.clearDownloadPath, progressReceived)
 	f.AddFinal(f.unlockUpgradeFlag, progressReceived)
@@ -0,0 +1,72 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+//go:build MEFEdge_SDK
+
+package tasks
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"huawei.com/mindx/common/envutils"
+	"huawei.com/mindx/common/fileutils"
+	"huawei.com/mindx/common/hwlog"
+
+	"edge-installer/pkg/common/constants"
+	"edge-installer/pkg/common/deltapkg"
+	"edge-installer/pkg/installer/common"
+)
+
+// deltaDirSuffix the suffix of the dir where the unpacked delta package is moved to while reconstructing
+const deltaDirSuffix = "_delta"
+
+type applyDeltaPackage struct {
+	extractPath string
+	workDir     string
+}
+
+// NewApplyDeltaPackage reconstructs the full package in extract path when the unpacked package is a delta package,
+// the full package is reconstructed from the delta package and the installed software
+func NewApplyDeltaPackage(extractPath, installPath string) common.Task {
+	return &applyDeltaPackage{
+		extractPath: extractPath,
+		workDir:     filepath.Join(installPath, constants.SoftwareDir),
+	}
+}
+
+// Run task
+func (ad *applyDeltaPackage) Run() error {
+	if !deltapkg.IsDeltaPackage(ad.extractPath) {
+		return nil
+	}
+	hwlog.RunLog.Info("the package is a delta package, start to reconstruct the full package")
+	if err := envutils.CheckDiskSpace(filepath.Dir(ad.extractPath), constants.InstallerExtractOnlineMin); err != nil {
+		hwlog.RunLog.Errorf("check disk space for reconstructing full package failed: %v", err)
+		return err
+	}
+	deltaDir := ad.extractPath + deltaDirSuffix
+	if err := fileutils.DeleteAllFileWithConfusion(deltaDir); err != nil {
+		return fmt.Errorf("clean delta dir failed: %v", err)
+	}
+	if err := fileutils.RenameFile(ad.extractPath, deltaDir); err != nil {
+		return fmt.Errorf("move delta package failed: %v", err)
+	}
+	defer func() {
+		if err := fileutils.DeleteAllFileWithConfusion(deltaDir); err != nil {
+			hwlog.RunLog.Warnf("clean delta dir failed: %v", err)
+		}
+	}()
+	if err := deltapkg.Apply(deltaDir, ad.workDir, ad.extractPath); err != nil {
+		hwlog.RunLog.Errorf("apply delta package failed: %v", err)
+		return err
+	}
+	hwlog.RunLog.Info("reconstruct the full package from delta package success")
+	return nil
+}
@@ -0,0 +1,59 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+//go:build MEFEdge_SDK
+
+// Package tasks for testing apply delta package
+package tasks
+
+import (
+	"testing"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/smartystreets/goconvey/convey"
+
+	"huawei.com/mindx/common/envutils"
+	"huawei.com/mindx/common/fileutils"
+	"huawei.com/mindx/common/test"
+
+	"edge-installer/pkg/common/deltapkg"
+)
+
+func TestApplyDeltaPackage(t *testing.T) {
+	task := NewApplyDeltaPackage("/tmp/unpack/edge_installer", "/usr/local/mindx/MEFEdge")
+	convey.Convey("full package should be skipped", t, func() {
+		p := gomonkey.ApplyFuncReturn(deltapkg.IsDeltaPackage, false).
+			ApplyFuncReturn(deltapkg.Apply, test.ErrTest)
+		defer p.Reset()
+		convey.So(task.Run(), convey.ShouldBeNil)
+	})
+
+	convey.Convey("delta package should be applied", t, func() {
+		p := gomonkey.ApplyFuncReturn(deltapkg.IsDeltaPackage, true).
+			ApplyFuncReturn(envutils.CheckDiskSpace, nil).
+			ApplyFuncReturn(fileutils.DeleteAllFileWithConfusion, nil).
+			ApplyFuncReturn(fileutils.RenameFile, nil)
+		defer p.Reset()
+		convey.Convey("apply success", func() {
+			p1 := gomonkey.ApplyFuncReturn(deltapkg.Apply, nil)
+			defer p1.Reset()
+			convey.So(task.Run(), convey.ShouldBeNil)
+		})
+		convey.Convey("apply failed", func() {
+			p1 := gomonkey.ApplyFuncReturn(deltapkg.Apply, deltapkg.ErrBaseMismatch)
+			defer p1.Reset()
+			convey.So(task.Run(), convey.ShouldResemble, deltapkg.ErrBaseMismatch)
+		})
+		convey.Convey("disk space is not enough", func() {
+			p1 := gomonkey.ApplyFuncReturn(envutils.CheckDiskSpace, test.ErrTest)
+			defer p1.Reset()
+			convey.So(task.Run(), convey.ShouldResemble, test.ErrTest)
+		})
+	})
+}
@@ -0,0 +1,137 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+//go:build MEFEdge_SDK
+
+package tasks
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"huawei.com/mindx/common/envutils"
+	"huawei.com/mindx/common/fileutils"
+	"huawei.com/mindx/common/hwlog"
+
+	"edge-installer/pkg/common/config"
+	"edge-installer/pkg/common/constants"
+	"edge-installer/pkg/common/util"
+)
+
+// check items of preflight
+const (
+	PreflightVersion   = "version"
+	PreflightLock      = "lock"
+	PreflightDiskSpace = "diskSpace"
+	PreflightCommands  = "necessaryCommands"
+)
+
+// PreflightCheck runs the checks of preupgrade tasks before the package is downloaded, nothing is modified
+type PreflightCheck struct {
+	CheckEnvironmentBase
+	downloadPath string
+	target       util.PreflightRequest
+}
+
+// NewPreflightCheck create the preflight check for upgrading the software installed in install path to the target
+func NewPreflightCheck(installPath string, target util.PreflightRequest) *PreflightCheck {
+	return &PreflightCheck{
+		CheckEnvironmentBase: CheckEnvironmentBase{
+			extractPath:    filepath.Join(constants.UnpackPath, constants.EdgeInstaller),
+			installPath:    installPath,
+			extractMinDisk: constants.InstallerExtractOnlineMin,
+			installMinDisk: constants.InstallerUpgradeSdkMin,
+		},
+		downloadPath: constants.EdgeDownloadPath,
+		target:       target,
+	}
+}
+
+// Run runs all the check items, the failure of an item does not stop the others. The current version is returned
+// together with the results
+func (pc *PreflightCheck) Run() (string, []util.PreflightItem) {
+	currentVersion, versionErr := pc.checkVersion()
+	checks := []struct {
+		name string
+		err  error
+	}{
+		{name: PreflightVersion, err: versionErr},
+		{name: PreflightLock, err: pc.checkLock()},
+		{name: PreflightDiskSpace, err: pc.checkDiskSpaceOnly()},
+		{name: PreflightCommands, err: util.CheckNecessaryCommands()},
+	}
+	items := make([]util.PreflightItem, 0, len(checks))
+	for _, check := range checks {
+		item := util.PreflightItem{Name: check.name, Passed: check.err == nil}
+		if check.err != nil {
+			hwlog.RunLog.Warnf("preflight check [%s] failed: %v", check.name, check.err)
+			item.Message = check.err.Error()
+		}
+		items = append(items, item)
+	}
+	return currentVersion, items
+}
+
+// checkVersion checks the target version is not the current version, and the inner version is compatible as the
+// upgrade task checks
+func (pc *PreflightCheck) checkVersion() (string, error) {
+	versionPath := filepath.Join(pc.installPath, constants.SoftwareDir, constants.VersionXml)
+	versionMgr := config.NewVersionXmlMgr(versionPath)
+	currentVersion, err := versionMgr.GetVersion()
+	if err != nil {
+		return "", errors.New("get current version failed")
+	}
+	if pc.target.Version == currentVersion {
+		return currentVersion, fmt.Errorf("the software is already of version [%s]", currentVersion)
+	}
+	if pc.target.InnerVersion == "" {
+		return currentVersion, nil
+	}
+	currentInnerVersion, err := versionMgr.GetInnerVersion()
+	if err != nil {
+		return currentVersion, errors.New("get current inner version failed")
+	}
+	isValidVersion, err := util.IsValidVersion(currentInnerVersion, pc.target.InnerVersion)
+	if err != nil {
+		return currentVersion, fmt.Errorf("compare version failed: %v", err)
+	}
+	if !isValidVersion {
+		return currentVersion, fmt.Errorf("upgrade version [%s] is not the previous version or the next version of "+
+			"current version [%s]", pc.target.InnerVersion, currentInnerVersion)
+	}
+	return currentVersion, nil
+}
+
+func (pc *PreflightCheck) checkLock() error {
+	if err := util.FlagLockInstance(constants.FlagPath, constants.ProcessFlag, constants.Upgrade).Check(); err != nil {
+		return fmt.Errorf("the upgrade cannot be locked: %v", err)
+	}
+	return nil
+}
+
+// checkDiskSpaceOnly checks the free space for downloading, extracting and installing the package, the dirs which
+// do not exist are checked by their existing parents
+func (pc *PreflightCheck) checkDiskSpaceOnly() error {
+	downloadSize := uint64(constants.InstallerTarGzSizeMaxInMB * constants.MB)
+	if err := envutils.CheckDiskSpace(existingParent(pc.downloadPath), downloadSize); err != nil {
+		return fmt.Errorf("check disk space for downloading failed: %v", err)
+	}
+	if err := pc.checkFreeSpace(existingParent(pc.extractPath)); err != nil {
+		return fmt.Errorf("check disk space for extracting and installing failed: %v", err)
+	}
+	return nil
+}
+
+func existingParent(path string) string {
+	for !fileutils.IsExist(path) && path != filepath.Dir(path) {
+		path = filepath.Dir(path)
+	}
+	return path
+}
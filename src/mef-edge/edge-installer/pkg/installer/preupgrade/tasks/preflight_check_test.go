@@ -0,0 +1,73 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+//go:build MEFEdge_SDK
+
+// Package tasks for testing preflight check
+package tasks
+
+import (
+	"testing"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/smartystreets/goconvey/convey"
+
+	"huawei.com/mindx/common/envutils"
+	"huawei.com/mindx/common/fileutils"
+	"huawei.com/mindx/common/test"
+
+	"edge-installer/pkg/common/config"
+	"edge-installer/pkg/common/util"
+)
+
+func itemsPassed(items []util.PreflightItem) map[string]bool {
+	passed := make(map[string]bool, len(items))
+	for _, item := range items {
+		passed[item.Name] = item.Passed
+	}
+	return passed
+}
+
+func TestPreflightCheck(t *testing.T) {
+	target := util.PreflightRequest{Version: "5.0.RC2", InnerVersion: "2.0"}
+	versionMgr := config.VersionXmlMgr{}
+	p := gomonkey.ApplyMethodReturn(versionMgr, "GetVersion", "5.0.RC1", nil).
+		ApplyMethodReturn(versionMgr, "GetInnerVersion", "1.0", nil).
+		ApplyFuncReturn(envutils.CheckDiskSpace, nil).
+		ApplyFuncReturn(util.InSamePartition, true, nil).
+		ApplyFuncReturn(util.CheckNecessaryCommands, nil).
+		ApplyFuncReturn(fileutils.CreateDir, test.ErrTest)
+	defer p.Reset()
+
+	convey.Convey("all check items should be passed", t, func() {
+		p1 := gomonkey.ApplyMethodReturn(util.FlagLockInstance("", "", ""), "Check", nil)
+		defer p1.Reset()
+		version, items := NewPreflightCheck("/usr/local/mindx/MEFEdge", target).Run()
+		convey.So(version, convey.ShouldEqual, "5.0.RC1")
+		convey.So(itemsPassed(items), convey.ShouldResemble, map[string]bool{PreflightVersion: true,
+			PreflightLock: true, PreflightDiskSpace: true, PreflightCommands: true})
+	})
+
+	convey.Convey("failed check items should not stop the others", t, func() {
+		p1 := gomonkey.ApplyMethodReturn(util.FlagLockInstance("", "", ""), "Check", test.ErrTest).
+			ApplyFuncReturn(envutils.CheckDiskSpace, test.ErrTest)
+		defer p1.Reset()
+		incompatible := util.PreflightRequest{Version: "5.0.RC3", InnerVersion: "3.0"}
+		_, items := NewPreflightCheck("/usr/local/mindx/MEFEdge", incompatible).Run()
+		convey.So(itemsPassed(items), convey.ShouldResemble, map[string]bool{PreflightVersion: false,
+			PreflightLock: false, PreflightDiskSpace: false, PreflightCommands: true})
+	})
+
+	convey.Convey("upgrading to the current version should be failed", t, func() {
+		p1 := gomonkey.ApplyMethodReturn(util.FlagLockInstance("", "", ""), "Check", nil)
+		defer p1.Reset()
+		_, items := NewPreflightCheck("/usr/local/mindx/MEFEdge", util.PreflightRequest{Version: "5.0.RC1"}).Run()
+		convey.So(itemsPassed(items)[PreflightVersion], convey.ShouldBeFalse)
+	})
+}
@@ -71,19 +71,25 @@ func (ceb CheckEnvironmentBase) checkDiskSpace() error {
 		hwlog.RunLog.Errorf("make sure [%s] exist failed,error:%v", ceb.extractPath, err)
 		return err
 	}
-	isSamePart, err := util.InSamePartition(ceb.extractPath, ceb.installPath)
+	return ceb.checkFreeSpace(ceb.extractPath)
+}
+
+// checkFreeSpace checks the free space of the partitions where the package is extracted and installed, extractDir
+// is the extract path or its existing parent
+func (ceb CheckEnvironmentBase) checkFreeSpace(extractDir string) error {
+	isSamePart, err := util.InSamePartition(extractDir, ceb.installPath)
 	if err != nil {
 		hwlog.RunLog.Errorf("check is same partition failed,error:%v", err)
 		return err
 	}
 	if isSamePart {
-		if err = envutils.CheckDiskSpace(ceb.extractPath, ceb.extractMinDisk+ceb.installMinDisk); err != nil {
+		if err = envutils.CheckDiskSpace(extractDir, ceb.extractMinDisk+ceb.installMinDisk); err != nil {
 			fmt.Println("disk space is not enough")
 			hwlog.RunLog.Error(err)
 			return err
 		}
 	}
-	if err = envutils.CheckDiskSpace(ceb.extractPath, ceb.extractMinDisk); err != nil {
+	if err = envutils.CheckDiskSpace(extractDir, ceb.extractMinDisk); err != nil {
 		fmt.Println("disk space is not enough")
 		hwlog.RunLog.Error(err)
 		return err
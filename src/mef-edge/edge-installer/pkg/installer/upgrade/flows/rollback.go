@@ -0,0 +1,51 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package flows this file for rollback flow
+package flows
+
+import (
+	"errors"
+
+	"huawei.com/mindx/common/hwlog"
+
+	"edge-installer/pkg/common/path/pathmgr"
+	"edge-installer/pkg/installer/common"
+	commonTasks "edge-installer/pkg/installer/common/tasks"
+	"edge-installer/pkg/installer/upgrade/tasks"
+)
+
+type rollbackFlow struct {
+	pathMgr *pathmgr.PathManager
+}
+
+// NewRollbackFlow create rollback flow instance
+func NewRollbackFlow(pathMgr *pathmgr.PathManager) common.Flow {
+	return &rollbackFlow{
+		pathMgr: pathMgr,
+	}
+}
+
+// RunTasks run rollback tasks
+func (rf *rollbackFlow) RunTasks() error {
+	hwlog.RunLog.Info("------------------start rollback task------------------")
+	rollbackProcess := tasks.RollbackProcessTask{
+		PostProcessBaseTask: commonTasks.PostProcessBaseTask{
+			WorkPathMgr: rf.pathMgr.WorkPathMgr,
+			LogPathMgr:  rf.pathMgr.LogPathMgr,
+		},
+		ConfigPathMgr: rf.pathMgr.ConfigPathMgr,
+	}
+	if err := rollbackProcess.Run(); err != nil {
+		return errors.New("rollback process task failed")
+	}
+	hwlog.RunLog.Info("------------------rollback task success------------------")
+	return nil
+}
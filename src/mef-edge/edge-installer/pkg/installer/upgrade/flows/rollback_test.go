@@ -0,0 +1,45 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package flows for testing rollback flow
+package flows
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/smartystreets/goconvey/convey"
+
+	"huawei.com/mindx/common/test"
+
+	"edge-installer/pkg/common/path/pathmgr"
+	"edge-installer/pkg/installer/upgrade/tasks"
+)
+
+var (
+	testRollbackDir = "/tmp/test_rollback_flow_dir"
+	flowRollback    = NewRollbackFlow(pathmgr.NewPathMgr(testRollbackDir, testRollbackDir, testRollbackDir,
+		testRollbackDir))
+)
+
+func TestRollbackFlow(t *testing.T) {
+	convey.Convey("rollback flow should be success", t, func() {
+		p := gomonkey.ApplyMethodReturn(&tasks.RollbackProcessTask{}, "Run", nil)
+		defer p.Reset()
+		convey.So(flowRollback.RunTasks(), convey.ShouldBeNil)
+	})
+
+	convey.Convey("rollback flow should be failed", t, func() {
+		p := gomonkey.ApplyMethodReturn(&tasks.RollbackProcessTask{}, "Run", test.ErrTest)
+		defer p.Reset()
+		convey.So(flowRollback.RunTasks(), convey.ShouldResemble, errors.New("rollback process task failed"))
+	})
+}
@@ -14,11 +14,14 @@ package tasks
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"gorm.io/gorm"
 
 	"huawei.com/mindx/common/backuputils"
 	"huawei.com/mindx/common/database"
+	"huawei.com/mindx/common/envutils"
+	"huawei.com/mindx/common/fileutils"
 	"huawei.com/mindx/common/hwlog"
 
 	"edge-installer/pkg/common/config"
@@ -38,6 +41,7 @@ type PostEffectProcessTask struct {
 // Run post process task
 func (p *PostEffectProcessTask) Run() error {
 	var postFunc = []func() error{
+		p.snapshotConfig,
 		p.clearAlarmInDB,
 		p.removeUpgradeBin,
 		p.CreateSoftwareSymlink,
@@ -74,6 +78,76 @@ func (p *PostEffectProcessTask) clearAlarmInDB() error {
 	return deleteMetaByType(dbPath, constants.DbEdgeMainPath, constants.MetaAlarmKey)
 }
 
+// snapshotConfig [method] keep a copy of the config of current version and write the rollback guard if needed,
+// so that the software can be rolled back to current version if the effected one does not work
+func (p *PostEffectProcessTask) snapshotConfig() error {
+	versionPath, err := fileutils.EvalSymlinks(p.WorkPathMgr.GetVersionXmlPath())
+	if err != nil {
+		return fmt.Errorf("get real version file path failed, error: %v", err)
+	}
+	prevVersion, err := config.NewVersionXmlMgr(versionPath).GetVersion()
+	if err != nil {
+		return fmt.Errorf("get current version failed, error: %v", err)
+	}
+	version, err := config.NewVersionXmlMgr(p.WorkPathMgr.GetUpgradeTempVersionXmlPath()).GetVersion()
+	if err != nil {
+		return fmt.Errorf("get effect version failed, error: %v", err)
+	}
+
+	rollbackDir := p.ConfigPathMgr.GetConfigRollbackDir()
+	if err = fileutils.DeleteAllFileWithConfusion(rollbackDir); err != nil {
+		return fmt.Errorf("remove old config snapshot failed, error: %v", err)
+	}
+	if _, err = envutils.RunCommand(constants.CpCmd, envutils.DefCmdTimeoutSec, "-a",
+		p.ConfigPathMgr.GetConfigDir(), rollbackDir); err != nil {
+		return fmt.Errorf("snapshot config for rollback failed, error: %v", err)
+	}
+
+	if !p.needRollbackGuard() {
+		if err = fileutils.DeleteFile(p.ConfigPathMgr.GetRollbackGuardPath()); err != nil {
+			return fmt.Errorf("remove old rollback guard failed, error: %v", err)
+		}
+		hwlog.RunLog.Infof("snapshot config of version [%s] for rollback success, rollback guard is not armed",
+			prevVersion)
+		return nil
+	}
+	guard := config.RollbackGuard{
+		PrevVersion: prevVersion,
+		Version:     version,
+		Deadline:    time.Now().Add(constants.RollbackGuardTimeout).Unix(),
+	}
+	if err = config.SaveRollbackGuard(p.ConfigPathMgr.GetRollbackGuardPath(), guard); err != nil {
+		return err
+	}
+	hwlog.RunLog.Infof("snapshot config of version [%s] for rollback success", prevVersion)
+	return nil
+}
+
+// needRollbackGuard [method] the rollback guard is armed only when the node is managed by MEF center and has
+// connected to center before the upgrade, otherwise the effected version would be rolled back since it can never
+// connect to center, such as the offline upgrade of a node which is not managed or not connected yet
+func (p *PostEffectProcessTask) needRollbackGuard() bool {
+	dbMgr := config.NewDbMgr(p.ConfigPathMgr.GetCompConfigDir(constants.EdgeOm), constants.DbEdgeOmPath)
+	if err := dbMgr.InitDB(); err != nil {
+		hwlog.RunLog.Warnf("init database of %s failed, rollback guard is not armed", constants.EdgeOm)
+		return false
+	}
+	netManager, err := config.GetNetManager(dbMgr)
+	if err != nil {
+		hwlog.RunLog.Warnf("get net config failed, rollback guard is not armed, error: %v", err)
+		return false
+	}
+	if netManager.NetType != constants.MEF {
+		hwlog.RunLog.Infof("net type is [%s], rollback guard is not armed", netManager.NetType)
+		return false
+	}
+	if !fileutils.IsExist(p.ConfigPathMgr.GetCenterConnectedFlagPath()) {
+		hwlog.RunLog.Info("node has not connected to center before upgrade, rollback guard is not armed")
+		return false
+	}
+	return true
+}
+
 func (p *PostEffectProcessTask) removeUpgradeBin() error {
 	return p.RemoveUpgradeBinByPath(p.WorkPathMgr.GetUpgradeTempBinaryPath())
 }
@@ -22,6 +22,7 @@ import (
 
 	"huawei.com/mindx/common/backuputils"
 	"huawei.com/mindx/common/database"
+	"huawei.com/mindx/common/envutils"
 	"huawei.com/mindx/common/fileutils"
 	"huawei.com/mindx/common/test"
 
@@ -33,7 +34,13 @@ import (
 )
 
 func TestPostEffectProcessTask(t *testing.T) {
-	p := gomonkey.ApplyMethodReturn(&util.EdgeGUidMgr{}, "SetEUGidToEdge", nil).
+	p := gomonkey.ApplyFuncReturn(fileutils.EvalSymlinks, "", nil).
+		ApplyMethodReturn(config.VersionXmlMgr{}, "GetVersion", "5.0.0", nil).
+		ApplyFuncReturn(envutils.RunCommand, "", nil).
+		ApplyFuncReturn(config.SaveRollbackGuard, nil).
+		ApplyPrivateMethod(&PostEffectProcessTask{}, "needRollbackGuard",
+			func(*PostEffectProcessTask) bool { return true }).
+		ApplyMethodReturn(&util.EdgeGUidMgr{}, "SetEUGidToEdge", nil).
 		ApplyFuncReturn(deleteMetaByType, nil).
 		ApplyMethodReturn(&tasks.PostProcessBaseTask{}, "CreateSoftwareSymlink", nil).
 		ApplyMethodReturn(&tasks.PostProcessBaseTask{}, "UpdateMefServiceInfo", nil).
@@ -52,6 +59,7 @@ func TestPostEffectProcessTask(t *testing.T) {
 	defer p.Reset()
 
 	convey.Convey("post effect process should be success", t, postEffectProcessTaskSuccess)
+	convey.Convey("post effect process should be failed, snapshot config failed", t, snapshotConfigFailed)
 	convey.Convey("post effect process should be failed, clear alarm in db failed", t, clearAlarmInDBFailed)
 	convey.Convey("post effect process should be failed, copy reset script failed", t, copyResetScriptFailed)
 	convey.Convey("post effect process should be failed, smooth config failed", t, smoothConfigFailed)
@@ -73,6 +81,30 @@ func postEffectProcessTaskSuccess() {
 	})
 }
 
+func snapshotConfigFailed() {
+	convey.Convey("get current version failed", func() {
+		p1 := gomonkey.ApplyMethodReturn(config.VersionXmlMgr{}, "GetVersion", "", test.ErrTest)
+		defer p1.Reset()
+		err := postEffectProcess.Run()
+		convey.So(err, convey.ShouldResemble, fmt.Errorf("get current version failed, error: %v", test.ErrTest))
+	})
+
+	convey.Convey("copy config dir failed", func() {
+		p1 := gomonkey.ApplyFuncReturn(envutils.RunCommand, "", test.ErrTest)
+		defer p1.Reset()
+		err := postEffectProcess.Run()
+		convey.So(err, convey.ShouldResemble, fmt.Errorf("snapshot config for rollback failed, error: %v",
+			test.ErrTest))
+	})
+
+	convey.Convey("save rollback guard failed", func() {
+		p1 := gomonkey.ApplyFuncReturn(config.SaveRollbackGuard, test.ErrTest)
+		defer p1.Reset()
+		err := postEffectProcess.Run()
+		convey.So(err, convey.ShouldResemble, test.ErrTest)
+	})
+}
+
 func clearAlarmInDBFailed() {
 	convey.Convey("set euid and egid failed", func() {
 		p1 := gomonkey.ApplyMethodReturn(&util.EdgeGUidMgr{}, "SetEUGidToEdge", test.ErrTest)
@@ -144,6 +176,57 @@ func restartFailed() {
 	convey.So(err, convey.ShouldResemble, fmt.Errorf("restart target [%s] failed", constants.MefEdgeTargetFile))
 }
 
+func TestNeedRollbackGuard(t *testing.T) {
+	p := gomonkey.ApplyMethodReturn(&config.DbMgr{}, "InitDB", nil).
+		ApplyFuncReturn(config.GetNetManager, &config.NetManager{NetType: constants.MEF}, nil)
+	defer p.Reset()
+	flagPath := postEffectProcess.ConfigPathMgr.GetCenterConnectedFlagPath()
+	defer clearEnv(flagPath)
+
+	convey.Convey("rollback guard is armed when node has connected to center", t, func() {
+		convey.So(fileutils.WriteData(flagPath, []byte("1")), convey.ShouldBeNil)
+		convey.So(postEffectProcess.needRollbackGuard(), convey.ShouldBeTrue)
+	})
+
+	convey.Convey("rollback guard is not armed when net type is not MEF", t, func() {
+		p1 := gomonkey.ApplyFuncReturn(config.GetNetManager, &config.NetManager{NetType: constants.FD}, nil)
+		defer p1.Reset()
+		convey.So(postEffectProcess.needRollbackGuard(), convey.ShouldBeFalse)
+	})
+
+	convey.Convey("rollback guard is not armed when get net config failed", t, func() {
+		p1 := gomonkey.ApplyFuncReturn(config.GetNetManager, nil, test.ErrTest)
+		defer p1.Reset()
+		convey.So(postEffectProcess.needRollbackGuard(), convey.ShouldBeFalse)
+	})
+
+	convey.Convey("rollback guard is not armed when node has not connected to center", t, func() {
+		clearEnv(flagPath)
+		convey.So(postEffectProcess.needRollbackGuard(), convey.ShouldBeFalse)
+	})
+}
+
+func TestSnapshotConfigOffline(t *testing.T) {
+	p := gomonkey.ApplyMethodReturn(config.VersionXmlMgr{}, "GetVersion", "5.0.0", nil).
+		ApplyFuncReturn(envutils.RunCommand, "", nil).
+		ApplyMethodReturn(&config.DbMgr{}, "InitDB", nil).
+		ApplyFuncReturn(config.GetNetManager, &config.NetManager{NetType: constants.MEF}, nil).
+		ApplyFuncReturn(config.SaveRollbackGuard, test.ErrTest)
+	defer p.Reset()
+	guardPath := postEffectProcess.ConfigPathMgr.GetRollbackGuardPath()
+	versionPath := postEffectProcess.WorkPathMgr.GetVersionXmlPath()
+	// the version xml is written into the software dir of the test dir, clear the whole dir for other tests
+	defer clearEnv(testDir)
+
+	convey.Convey("offline upgrade of a node not connected to center does not arm rollback guard", t, func() {
+		clearEnv(postEffectProcess.ConfigPathMgr.GetCenterConnectedFlagPath())
+		convey.So(fileutils.WriteData(versionPath, []byte("")), convey.ShouldBeNil)
+		convey.So(fileutils.WriteData(guardPath, []byte("{}")), convey.ShouldBeNil)
+		convey.So(postEffectProcess.snapshotConfig(), convey.ShouldBeNil)
+		convey.So(fileutils.IsExist(guardPath), convey.ShouldBeFalse)
+	})
+}
+
 func TestDeleteMetaByType(t *testing.T) {
 	p := gomonkey.ApplyMethodReturn(&config.DbMgr{}, "InitDB", nil)
 	defer p.Reset()
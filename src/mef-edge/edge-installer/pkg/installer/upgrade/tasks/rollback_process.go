@@ -0,0 +1,160 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package tasks for rolling back the software to the inactive version
+package tasks
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"huawei.com/mindx/common/fileutils"
+	"huawei.com/mindx/common/hwlog"
+
+	"edge-installer/pkg/common/config"
+	"edge-installer/pkg/common/constants"
+	"edge-installer/pkg/common/path/pathmgr"
+	"edge-installer/pkg/common/util"
+	"edge-installer/pkg/installer/common"
+	"edge-installer/pkg/installer/common/tasks"
+)
+
+// RollbackProcessTask the task for rolling back the software to the inactive version, the inactive software dir
+// becomes the working one and the config snapshot taken when the active version was effected is restored
+type RollbackProcessTask struct {
+	tasks.PostProcessBaseTask
+	ConfigPathMgr *pathmgr.ConfigPathMgr
+}
+
+// Run rollback process task
+func (r *RollbackProcessTask) Run() error {
+	var rollbackFunc = []func() error{
+		r.checkRollbackEnv,
+		r.clearUpgradeTempDir,
+		r.restoreConfig,
+		r.CreateSoftwareSymlink,
+		r.UpdateMefServiceInfo,
+		r.SetSoftwareDirImmutable,
+		r.clearRollbackGuard,
+		r.restart,
+	}
+	for _, function := range rollbackFunc {
+		if err := function(); err != nil {
+			hwlog.RunLog.Error(err)
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *RollbackProcessTask) checkRollbackEnv() error {
+	inactiveDir, err := pathmgr.GetTargetInstallDir(r.WorkPathMgr.GetInstallRootDir())
+	if err != nil {
+		return fmt.Errorf("get inactive software dir failed, error: %v", err)
+	}
+	inactiveVersionPath := filepath.Join(inactiveDir, constants.VersionXml)
+	if !fileutils.IsExist(inactiveVersionPath) {
+		return errors.New("no inactive version to roll back to")
+	}
+	inactiveVersion, err := config.NewVersionXmlMgr(inactiveVersionPath).GetVersion()
+	if err != nil {
+		return fmt.Errorf("get inactive version failed, error: %v", err)
+	}
+	if !fileutils.IsExist(r.ConfigPathMgr.GetConfigRollbackDir()) {
+		return errors.New("no config snapshot of the inactive version, rollback is not supported")
+	}
+
+	var activeVersion string
+	activeVersionPath, err := fileutils.EvalSymlinks(r.WorkPathMgr.GetVersionXmlPath())
+	if err == nil {
+		activeVersion, err = config.NewVersionXmlMgr(activeVersionPath).GetVersion()
+	}
+	if err != nil {
+		hwlog.RunLog.Warnf("get active version failed, error: %v", err)
+	}
+	hwlog.RunLog.Infof("start to roll back from version [%s] to [%s]", activeVersion, inactiveVersion)
+	fmt.Printf("start to roll back from version [%s] to [%s]\n", activeVersion, inactiveVersion)
+	return nil
+}
+
+// clearUpgradeTempDir the upgraded software which is not effected is discarded, otherwise it would replace
+// the inactive software dir when switching the software symlink
+func (r *RollbackProcessTask) clearUpgradeTempDir() error {
+	upgradeTempDir := r.WorkPathMgr.GetUpgradeTempDir()
+	if !fileutils.IsExist(upgradeTempDir) {
+		return nil
+	}
+	if err := util.UnSetImmutable(upgradeTempDir); err != nil {
+		hwlog.RunLog.Warn("unset temp software immutable failed, maybe include link file")
+	}
+	if err := fileutils.DeleteAllFileWithConfusion(upgradeTempDir); err != nil {
+		return fmt.Errorf("remove software to effect failed, error: %v", err)
+	}
+	hwlog.RunLog.Warn("the software to effect is discarded by rollback")
+	return nil
+}
+
+func (r *RollbackProcessTask) restoreConfig() error {
+	configDir := r.ConfigPathMgr.GetConfigDir()
+	rollbackDir := r.ConfigPathMgr.GetConfigRollbackDir()
+	rollbackTempDir := r.ConfigPathMgr.GetConfigRollbackTempDir()
+	if err := fileutils.DeleteAllFileWithConfusion(rollbackTempDir); err != nil {
+		return fmt.Errorf("clean config rollback temp dir failed, error: %v", err)
+	}
+
+	if err := fileutils.RenameFile(configDir, rollbackTempDir); err != nil {
+		return fmt.Errorf("backup cur config dir failed, error: %v", err)
+	}
+	if err := fileutils.RenameFile(rollbackDir, configDir); err != nil {
+		if recoverErr := fileutils.RenameFile(rollbackTempDir, configDir); recoverErr != nil {
+			hwlog.RunLog.Errorf("recover current config failed, please manually recover it by renaming [%s] to [%s]",
+				rollbackTempDir, configDir)
+		}
+		return fmt.Errorf("restore config snapshot failed, error: %v", err)
+	}
+
+	if err := fileutils.DeleteAllFileWithConfusion(rollbackTempDir); err != nil {
+		hwlog.RunLog.Warnf("remove config rollback temp dir [%s] failed: %v, please manually remove it",
+			rollbackTempDir, err)
+	}
+	hwlog.RunLog.Info("restore config snapshot success")
+	return nil
+}
+
+func (r *RollbackProcessTask) clearRollbackGuard() error {
+	guardPath := r.ConfigPathMgr.GetRollbackGuardPath()
+	if !fileutils.IsExist(guardPath) {
+		return nil
+	}
+	if err := fileutils.DeleteFile(guardPath); err != nil {
+		return fmt.Errorf("remove rollback guard failed, error: %v", err)
+	}
+	return nil
+}
+
+func (r *RollbackProcessTask) restart() error {
+	if active := util.IsServiceActive(constants.EdgeOmServiceFile); !active {
+		hwlog.RunLog.Info("service is not running, no need to restart")
+		return nil
+	}
+
+	hwlog.RunLog.Info("rollback completed, restarting...")
+	fmt.Println("rollback completed, restarting...")
+	if err := util.RestartService(constants.MefEdgeTargetFile); err != nil {
+		hwlog.RunLog.Errorf("restart target [%s] failed, error: %v", constants.MefEdgeTargetFile, err)
+		return fmt.Errorf("restart target [%s] failed", constants.MefEdgeTargetFile)
+	}
+
+	componentMgr := common.NewComponentMgr(r.WorkPathMgr.GetInstallRootDir())
+	componentMgr.CheckAllServiceActive()
+	hwlog.RunLog.Info("restart all services success")
+	return nil
+}
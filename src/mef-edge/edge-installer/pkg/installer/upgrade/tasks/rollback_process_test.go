@@ -0,0 +1,105 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+// Package tasks for testing rollback process
+package tasks
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/smartystreets/goconvey/convey"
+
+	"huawei.com/mindx/common/fileutils"
+
+	"edge-installer/pkg/common/config"
+	"edge-installer/pkg/common/constants"
+	"edge-installer/pkg/common/util"
+	"edge-installer/pkg/installer/common/tasks"
+)
+
+const testConfigFile = "test.json"
+
+var rollbackProcess = RollbackProcessTask{
+	PostProcessBaseTask: tasks.PostProcessBaseTask{
+		WorkPathMgr: pathMgr.SoftwarePathMgr.WorkPathMgr,
+		LogPathMgr:  pathMgr.LogPathMgr,
+	},
+	ConfigPathMgr: pathMgr.ConfigPathMgr,
+}
+
+// prepareRollbackEnv software_B is active, software_A is the inactive version to roll back to
+func prepareRollbackEnv() error {
+	clearEnv(testDir)
+	workPathMgr := pathMgr.SoftwarePathMgr.WorkPathMgr
+	configPathMgr := pathMgr.ConfigPathMgr
+	versions := map[string]string{workPathMgr.GetWorkADir(): "5.0.0", workPathMgr.GetWorkBDir(): "6.0.0"}
+	for dir, version := range versions {
+		versionXml := []byte("<Version>" + version + "</Version>")
+		if err := fileutils.WriteData(filepath.Join(dir, constants.VersionXml), versionXml); err != nil {
+			return err
+		}
+	}
+	if err := os.Symlink(workPathMgr.GetWorkBDir(), workPathMgr.GetWorkDir()); err != nil {
+		return err
+	}
+	if err := fileutils.WriteData(filepath.Join(configPathMgr.GetConfigDir(), testConfigFile),
+		[]byte("6.0.0")); err != nil {
+		return err
+	}
+	if err := fileutils.WriteData(filepath.Join(configPathMgr.GetConfigRollbackDir(), testConfigFile),
+		[]byte("5.0.0")); err != nil {
+		return err
+	}
+	return config.SaveRollbackGuard(configPathMgr.GetRollbackGuardPath(), config.RollbackGuard{})
+}
+
+func TestRollbackProcessTask(t *testing.T) {
+	p := gomonkey.ApplyMethodReturn(&tasks.PostProcessBaseTask{}, "UpdateMefServiceInfo", nil).
+		ApplyMethodReturn(&tasks.PostProcessBaseTask{}, "SetSoftwareDirImmutable", nil).
+		ApplyFuncReturn(util.IsServiceActive, false)
+	defer p.Reset()
+
+	convey.Convey("rollback should be success", t, rollbackProcessSuccess)
+	convey.Convey("rollback should be failed, no config snapshot", t, rollbackWithoutSnapshot)
+}
+
+func rollbackProcessSuccess() {
+	defer clearEnv(testDir)
+	convey.So(prepareRollbackEnv(), convey.ShouldBeNil)
+	configPathMgr := pathMgr.ConfigPathMgr
+
+	convey.So(rollbackProcess.Run(), convey.ShouldBeNil)
+	workDir, err := filepath.EvalSymlinks(pathMgr.SoftwarePathMgr.WorkPathMgr.GetWorkDir())
+	convey.So(err, convey.ShouldBeNil)
+	convey.So(workDir, convey.ShouldEqual, pathMgr.SoftwarePathMgr.WorkPathMgr.GetWorkADir())
+	data, err := fileutils.LoadFile(filepath.Join(configPathMgr.GetConfigDir(), testConfigFile))
+	convey.So(err, convey.ShouldBeNil)
+	convey.So(string(data), convey.ShouldEqual, "5.0.0")
+	convey.So(fileutils.IsExist(configPathMgr.GetConfigRollbackDir()), convey.ShouldBeFalse)
+	convey.So(fileutils.IsExist(configPathMgr.GetConfigRollbackTempDir()), convey.ShouldBeFalse)
+	convey.So(fileutils.IsExist(configPathMgr.GetRollbackGuardPath()), convey.ShouldBeFalse)
+}
+
+func rollbackWithoutSnapshot() {
+	defer clearEnv(testDir)
+	convey.So(prepareRollbackEnv(), convey.ShouldBeNil)
+	convey.So(fileutils.DeleteAllFileWithConfusion(pathMgr.ConfigPathMgr.GetConfigRollbackDir()), convey.ShouldBeNil)
+
+	err := rollbackProcess.Run()
+	convey.So(err, convey.ShouldResemble, errors.New("no config snapshot of the inactive version, "+
+		"rollback is not supported"))
+	workDir, err := filepath.EvalSymlinks(pathMgr.SoftwarePathMgr.WorkPathMgr.GetWorkDir())
+	convey.So(err, convey.ShouldBeNil)
+	convey.So(workDir, convey.ShouldEqual, pathMgr.SoftwarePathMgr.WorkPathMgr.GetWorkBDir())
+}
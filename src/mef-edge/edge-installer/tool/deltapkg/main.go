@@ -0,0 +1,102 @@
+// Copyright (c) Huawei Technologies Co., Ltd. 2025-2025. All rights reserved.
+// MEF is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+//go:build MEFEdge_SDK
+
+// Package main this file for the release tool of delta package
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"huawei.com/mindx/common/hwlog"
+
+	"edge-installer/pkg/common/deltapkg"
+)
+
+const (
+	createCmd = "create"
+	packCmd   = "pack"
+
+	cmdIndex    = 1
+	cmdArgIndex = 2
+	minArgsLen  = 2
+
+	errorExitCode = 1
+	wrongArgsCode = 2
+)
+
+func main() {
+	if len(os.Args) < minArgsLen {
+		printUsage()
+		os.Exit(wrongArgsCode)
+	}
+	if err := hwlog.InitRunLogger(&hwlog.LogConfig{OnlyToStdout: true}, context.Background()); err != nil {
+		fmt.Printf("initialize log failed, error: %v\n", err)
+		os.Exit(errorExitCode)
+	}
+	var err error
+	switch os.Args[cmdIndex] {
+	case createCmd:
+		err = create()
+	case packCmd:
+		err = pack()
+	default:
+		printUsage()
+		os.Exit(wrongArgsCode)
+	}
+	if err != nil {
+		fmt.Printf("Execute [%s] command failed, error: %v\n", os.Args[cmdIndex], err)
+		os.Exit(errorExitCode)
+	}
+	fmt.Printf("Execute [%s] command success!\n", os.Args[cmdIndex])
+}
+
+// create the delta package from two signed full packages, then the manifest in output dir is signed by the release
+// signing process before packing
+func create() error {
+	flagSet := flag.NewFlagSet(createCmd, flag.ExitOnError)
+	basePkg := flagSet.String("base", "", "the signed full package of base version")
+	targetPkg := flagSet.String("target", "", "the signed full package of target version")
+	outDir := flagSet.String("out", "", "the dir to save the unpacked delta package, it should not exist")
+	if err := flagSet.Parse(os.Args[cmdArgIndex:]); err != nil {
+		return err
+	}
+	if *basePkg == "" || *targetPkg == "" || *outDir == "" {
+		flagSet.PrintDefaults()
+		return errors.New("the parameter is invalid")
+	}
+	if _, err := os.Stat(*outDir); !os.IsNotExist(err) {
+		return errors.New("the output dir already exists")
+	}
+	return deltapkg.Create(*basePkg, *targetPkg, *outDir)
+}
+
+func pack() error {
+	flagSet := flag.NewFlagSet(packCmd, flag.ExitOnError)
+	dir := flagSet.String("dir", "", "the unpacked delta package whose manifest has been signed")
+	out := flagSet.String("out", "", "the delta package file to create")
+	if err := flagSet.Parse(os.Args[cmdArgIndex:]); err != nil {
+		return err
+	}
+	if *dir == "" || *out == "" {
+		flagSet.PrintDefaults()
+		return errors.New("the parameter is invalid")
+	}
+	return deltapkg.Pack(*dir, *out)
+}
+
+func printUsage() {
+	fmt.Printf("usage: deltapkg %s -base <pkg> -target <pkg> -out <dir> | %s -dir <dir> -out <file>\n",
+		createCmd, packCmd)
+}
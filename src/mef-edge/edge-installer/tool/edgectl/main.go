@@ -145,6 +145,7 @@ func initCmd() {
 	registerCmd(commands.UpgradeCmd())
 	registerCmd(commands.GetNetCfgCmd())
 	registerCmd(commands.EffectCmd())
+	registerCmd(commands.RollbackCmd())
 	registerCmd(commands.LogCollectCmd())
 	registerCmd(commands.UpdateKmcCmd())
 	initCmdExt()
@@ -24,4 +24,6 @@ func initCmdExt() {
 	registerCmd(commands.NewGetUnusedCertInfoCmd())
 	registerCmd(commands.NewDeleteCertInfoCmd())
 	registerCmd(commands.NewRestoreCertInfoCmd())
+	registerCmd(commands.DownloadPolicyCmd())
+	registerCmd(commands.SiteCacheCmd())
 }